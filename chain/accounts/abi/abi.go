@@ -120,6 +120,32 @@ func (abi ABI) Unpack(v interface{}, name string, output []byte) (err error) {
 	return unpack.singleUnpack(v, output)
 }
 
+//按方法的返回值类型逐个解码，返回值的顺序与Abi中的定义一致
+func (abi ABI) UnpackValues(name string, output []byte) ([]interface{}, error) {
+
+	if err := bytesAreProper(output); err != nil {
+		return nil, err
+	}
+	method, ok := abi.Methods[name]
+	if !ok {
+		return nil, errors.New("abi: could not locate named method")
+	}
+
+	values := make([]interface{}, len(method.Outputs))
+	j := 0
+	for i, argument := range method.Outputs {
+		if argument.Type.T == ArrayTy {
+			j += argument.Type.Size
+		}
+		value, err := toGoType((i+j)*32, argument.Type, output)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
 func (abi ABI) InputUnpack(v []interface{}, name string, input []byte) (err error) {
 
 	//判断输入数据是否正确
@@ -272,8 +272,9 @@ func (b *SimulatedBackend) callContract(ctx context.Context, call ethereum.CallM
 	gaspool := new(core.GasPool).AddGas(math.MaxBig256)
 	sizepool := new(big.Int).SetInt64(protocol.MaxBlockSize)
 
-	//创建这个交易的状态对象
-	ret, gasUsed, _, failed, err := core.NewStateTransition(vmenv, msg, gaspool, sizepool).NormalTransitionDb(b.blockchain.CurrentBlock().DposCtx(), b.blockchain.CurrentBlock().BokerCtx(), b.blockchain.Boker())
+	//创建这个交易的状态对象，使用上下文副本避免修改当前区块
+	dposContext, bokerContext := core.CopyContexts(b.blockchain.CurrentBlock())
+	ret, gasUsed, _, failed, err := core.NewStateTransition(vmenv, msg, gaspool, sizepool).NormalTransitionDb(dposContext, bokerContext, b.blockchain.Boker())
 	log.Info("callContract", "ret", ret, "gasUsed", gasUsed)
 
 	return ret, gasUsed, failed, err
@@ -0,0 +1,109 @@
+package accounts
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/Tinachain/Tina/chain/common"
+)
+
+//单个充值地址集合最多派生的地址数量
+const MaxDepositAddresses = 10000
+
+var (
+	ErrUnknownDepositSet  = errors.New("unknown deposit address set")
+	ErrInvalidDepositSet  = errors.New("invalid deposit address set")
+	ErrDepositSetTooLarge = errors.New("too many deposit addresses in set")
+)
+
+//由扩展公钥派生的一组只读充值地址(子公钥序号为[Start, Start+Count))
+type DepositSet struct {
+	Name  string //集合名称
+	Xpub  string //扩展公钥
+	Start uint32 //第一个地址的子公钥序号
+	Count uint32 //地址数量
+}
+
+//充值地址
+type DepositAddress struct {
+	Index   uint32 //子公钥序号
+	Address common.Address
+}
+
+//已派生的充值地址集合
+type depositSet struct {
+	set       DepositSet
+	addresses []DepositAddress
+}
+
+//派生并跟踪一组充值地址，同名的集合会被替换
+func (am *Manager) TrackDeposits(set DepositSet) ([]DepositAddress, error) {
+
+	if set.Name == "" || set.Count == 0 || set.Start+set.Count < set.Start || set.Start+set.Count > hardenedOffset {
+		return nil, ErrInvalidDepositSet
+	}
+	if set.Count > MaxDepositAddresses {
+		return nil, ErrDepositSetTooLarge
+	}
+	key, err := ParseExtendedPublicKey(set.Xpub)
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]DepositAddress, 0, set.Count)
+	for index := set.Start; index < set.Start+set.Count; index++ {
+		child, err := key.Child(index)
+		if err == ErrInvalidChild {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, DepositAddress{Index: index, Address: child.Address()})
+	}
+
+	am.lock.Lock()
+	defer am.lock.Unlock()
+
+	am.deposits[set.Name] = &depositSet{set: set, addresses: addresses}
+	return addresses, nil
+}
+
+//停止跟踪充值地址集合
+func (am *Manager) UntrackDeposits(name string) bool {
+
+	am.lock.Lock()
+	defer am.lock.Unlock()
+
+	if _, ok := am.deposits[name]; !ok {
+		return false
+	}
+	delete(am.deposits, name)
+	return true
+}
+
+//得到所有跟踪的充值地址集合，按名称排序
+func (am *Manager) DepositSets() []DepositSet {
+
+	am.lock.RLock()
+	defer am.lock.RUnlock()
+
+	sets := make([]DepositSet, 0, len(am.deposits))
+	for _, deposits := range am.deposits {
+		sets = append(sets, deposits.set)
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i].Name < sets[j].Name })
+	return sets
+}
+
+//得到充值地址集合中的地址
+func (am *Manager) DepositAddresses(name string) ([]DepositAddress, error) {
+
+	am.lock.RLock()
+	defer am.lock.RUnlock()
+
+	deposits, ok := am.deposits[name]
+	if !ok {
+		return nil, ErrUnknownDepositSet
+	}
+	return append([]DepositAddress(nil), deposits.addresses...), nil
+}
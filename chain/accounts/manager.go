@@ -32,6 +32,7 @@ type Manager struct {
 	updates  chan WalletEvent           // Subscription sink for backend wallet changes
 	wallets  []Wallet                   // Cache of all wallets from all registered backends
 	feed     event.Feed                 // Wallet feed notifying of arrivals/departures
+	deposits map[string]*depositSet     // Watch-only deposit address sets derived from extended public keys
 	quit     chan chan error
 	lock     sync.RWMutex
 }
@@ -57,6 +58,7 @@ func NewManager(backends ...Backend) *Manager {
 		updaters: subs,
 		updates:  updates,
 		wallets:  wallets,
+		deposits: make(map[string]*depositSet),
 		quit:     make(chan chan error),
 	}
 	for _, backend := range backends {
@@ -0,0 +1,187 @@
+//secretstore包用于保存挖矿账号的密码等敏感信息，避免以明文形式出现在内存、配置文件以及日志中。
+//
+//Secret在内存中使用进程启动后随机生成的密钥加密保存，只在使用时解密；
+//密码文件使用scrypt从口令派生的密钥通过AES-GCM加密，节点启动时输入口令解锁。
+package secretstore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	fileVersion = 1
+	scryptN     = 1 << 18
+	scryptR     = 8
+	scryptP     = 1
+	scryptDKLen = 32
+)
+
+var (
+	ErrDecrypt        = errors.New("could not decrypt sealed secret with given passphrase")
+	ErrUnknownVersion = errors.New("unknown sealed secret file version")
+)
+
+//进程内用于加密Secret的密钥
+var (
+	memoryKey     []byte
+	memoryKeyOnce sync.Once
+)
+
+func memoryCipher() cipher.AEAD {
+	memoryKeyOnce.Do(func() {
+		memoryKey = make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, memoryKey); err != nil {
+			panic("failed to generate secret store key: " + err.Error())
+		}
+	})
+	return newGCM(memoryKey)
+}
+
+func newGCM(key []byte) cipher.AEAD {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		panic(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(err)
+	}
+	return gcm
+}
+
+//在内存中加密保存的密码，打印或者写入日志时只输出占位符
+type Secret struct {
+	nonce  []byte
+	sealed []byte
+}
+
+//加密保存密码
+func Seal(plain string) *Secret {
+
+	gcm := memoryCipher()
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		panic("failed to generate secret nonce: " + err.Error())
+	}
+	return &Secret{nonce: nonce, sealed: gcm.Seal(nil, nonce, []byte(plain), nil)}
+}
+
+//解密得到密码，空的Secret返回空字符串
+func (s *Secret) Open() string {
+
+	if s == nil || s.sealed == nil {
+		return ""
+	}
+	plain, err := memoryCipher().Open(nil, s.nonce, s.sealed, nil)
+	if err != nil {
+		return ""
+	}
+	return string(plain)
+}
+
+//是否没有保存密码
+func (s *Secret) Empty() bool {
+	return s == nil || len(s.sealed) == 0
+}
+
+func (s *Secret) String() string {
+	if s.Empty() {
+		return ""
+	}
+	return "<sealed>"
+}
+
+//日志输出时使用占位符
+func (s *Secret) TerminalString() string {
+	return s.String()
+}
+
+//密码文件的格式
+type sealedFile struct {
+	Version    int           `json:"version"`
+	N          int           `json:"n"`
+	R          int           `json:"r"`
+	P          int           `json:"p"`
+	Salt       hexutil.Bytes `json:"salt"`
+	Nonce      hexutil.Bytes `json:"nonce"`
+	Ciphertext hexutil.Bytes `json:"ciphertext"`
+}
+
+//使用口令加密密码并写入文件（只有所有者可以读写）
+func WriteFile(path string, secret string, passphrase string) error {
+	return writeFile(path, secret, passphrase, scryptN, scryptP)
+}
+
+func writeFile(path string, secret string, passphrase string, n, p int) error {
+
+	salt := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return err
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, n, scryptR, p, scryptDKLen)
+	if err != nil {
+		return err
+	}
+	gcm := newGCM(key)
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	content, err := json.Marshal(&sealedFile{
+		Version:    fileVersion,
+		N:          n,
+		R:          scryptR,
+		P:          p,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, []byte(secret), nil),
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0600)
+}
+
+//使用口令解密密码文件
+func ReadFile(path string, passphrase string) (*Secret, error) {
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file sealedFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+	if file.Version != fileVersion {
+		return nil, ErrUnknownVersion
+	}
+	key, err := scrypt.Key([]byte(passphrase), file.Salt, file.N, file.R, file.P, scryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+	gcm := newGCM(key)
+	if len(file.Nonce) != gcm.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	plain, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return Seal(string(plain)), nil
+}
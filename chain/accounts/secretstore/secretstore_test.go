@@ -0,0 +1,53 @@
+package secretstore
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//测试内存中的密码可以解密，并且打印时不会输出明文
+func TestSealOpen(t *testing.T) {
+	secret := Seal("coinbase password")
+	if have := secret.Open(); have != "coinbase password" {
+		t.Fatalf("secret mismatch: have %q", have)
+	}
+	if out := fmt.Sprint(secret); strings.Contains(out, "password") {
+		t.Errorf("secret leaked in output: %s", out)
+	}
+	var empty *Secret
+	if !empty.Empty() || empty.Open() != "" {
+		t.Errorf("nil secret not empty")
+	}
+}
+
+//测试密码文件使用正确的口令才能解密
+func TestFileRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secretstore-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "miner.sealed")
+
+	if err := writeFile(path, "coinbase password", "unlock", 1<<4, 1); err != nil {
+		t.Fatalf("failed to write sealed file: %v", err)
+	}
+	content, _ := ioutil.ReadFile(path)
+	if strings.Contains(string(content), "coinbase password") {
+		t.Fatalf("sealed file contains plaintext")
+	}
+	secret, err := ReadFile(path, "unlock")
+	if err != nil {
+		t.Fatalf("failed to read sealed file: %v", err)
+	}
+	if have := secret.Open(); have != "coinbase password" {
+		t.Errorf("secret mismatch: have %q", have)
+	}
+	if _, err := ReadFile(path, "wrong"); err != ErrDecrypt {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrDecrypt)
+	}
+}
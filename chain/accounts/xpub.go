@@ -0,0 +1,207 @@
+package accounts
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/btcsuite/btcd/btcec"
+	"golang.org/x/crypto/ripemd160"
+)
+
+//BIP-32扩展公钥的版本号
+var (
+	xpubVersion = []byte{0x04, 0x88, 0xb2, 0x1e} //主网(xpub)
+	tpubVersion = []byte{0x04, 0x35, 0x87, 0xcf} //测试网(tpub)
+	xprvVersion = []byte{0x04, 0x88, 0xad, 0xe4} //主网私钥(xprv)
+	tprvVersion = []byte{0x04, 0x35, 0x83, 0x94} //测试网私钥(tprv)
+)
+
+const (
+	extendedKeyLength = 78 //扩展密钥序列化后的长度(不包括校验码)
+	hardenedOffset    = 0x80000000
+)
+
+var (
+	ErrInvalidExtendedKey = errors.New("invalid extended public key")
+	ErrPrivateExtendedKey = errors.New("extended private keys are not accepted, use the extended public key")
+	ErrHardenedDerivation = errors.New("cannot derive hardened child from extended public key")
+	ErrInvalidChild       = errors.New("invalid child key, use the next index")
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+//BIP-32扩展公钥，只能派生非强化的子公钥，用于只读(watch-only)的地址
+type ExtendedPublicKey struct {
+	version     []byte
+	depth       uint8
+	parentFP    []byte
+	childNumber uint32
+	chainCode   []byte
+	key         *btcec.PublicKey
+}
+
+//解析Base58Check编码的扩展公钥(xpub或tpub)
+func ParseExtendedPublicKey(encoded string) (*ExtendedPublicKey, error) {
+
+	data, err := base58CheckDecode(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != extendedKeyLength {
+		return nil, ErrInvalidExtendedKey
+	}
+	version := data[:4]
+	if bytes.Equal(version, xprvVersion) || bytes.Equal(version, tprvVersion) {
+		return nil, ErrPrivateExtendedKey
+	}
+	if !bytes.Equal(version, xpubVersion) && !bytes.Equal(version, tpubVersion) {
+		return nil, ErrInvalidExtendedKey
+	}
+	key, err := btcec.ParsePubKey(data[45:78], btcec.S256())
+	if err != nil {
+		return nil, ErrInvalidExtendedKey
+	}
+	return &ExtendedPublicKey{
+		version:     common.CopyBytes(version),
+		depth:       data[4],
+		parentFP:    common.CopyBytes(data[5:9]),
+		childNumber: binary.BigEndian.Uint32(data[9:13]),
+		chainCode:   common.CopyBytes(data[13:45]),
+		key:         key,
+	}, nil
+}
+
+//派生第index个子公钥(CKDpub)
+func (k *ExtendedPublicKey) Child(index uint32) (*ExtendedPublicKey, error) {
+
+	if index >= hardenedOffset {
+		return nil, ErrHardenedDerivation
+	}
+	parent := k.key.SerializeCompressed()
+	data := make([]byte, len(parent)+4)
+	copy(data, parent)
+	binary.BigEndian.PutUint32(data[len(parent):], index)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	curve := btcec.S256()
+	il := new(big.Int).SetBytes(sum[:32])
+	if il.Cmp(curve.N) >= 0 {
+		return nil, ErrInvalidChild
+	}
+	x, y := curve.ScalarBaseMult(sum[:32])
+	x, y = curve.Add(x, y, k.key.X, k.key.Y)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, ErrInvalidChild
+	}
+	return &ExtendedPublicKey{
+		version:     k.version,
+		depth:       k.depth + 1,
+		parentFP:    hash160(parent)[:4],
+		childNumber: index,
+		chainCode:   sum[32:],
+		key:         &btcec.PublicKey{Curve: curve, X: x, Y: y},
+	}, nil
+}
+
+//按相对路径依次派生子公钥
+func (k *ExtendedPublicKey) Derive(path DerivationPath) (*ExtendedPublicKey, error) {
+
+	key := k
+	for _, index := range path {
+		child, err := key.Child(index)
+		if err != nil {
+			return nil, err
+		}
+		key = child
+	}
+	return key, nil
+}
+
+//公钥对应的账号地址
+func (k *ExtendedPublicKey) Address() common.Address {
+	return crypto.PubkeyToAddress(ecdsa.PublicKey(*k.key))
+}
+
+//Base58Check编码的扩展公钥
+func (k *ExtendedPublicKey) String() string {
+
+	data := make([]byte, 0, extendedKeyLength)
+	data = append(data, k.version...)
+	data = append(data, k.depth)
+	data = append(data, k.parentFP...)
+	data = append(data, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[9:13], k.childNumber)
+	data = append(data, k.chainCode...)
+	data = append(data, k.key.SerializeCompressed()...)
+	return base58CheckEncode(data)
+}
+
+func hash160(data []byte) []byte {
+	sha := sha256.Sum256(data)
+	hasher := ripemd160.New()
+	hasher.Write(sha[:])
+	return hasher.Sum(nil)
+}
+
+func checksum(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:4]
+}
+
+func base58CheckDecode(encoded string) ([]byte, error) {
+
+	value := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range []byte(encoded) {
+		digit := bytes.IndexByte([]byte(base58Alphabet), c)
+		if digit < 0 {
+			return nil, ErrInvalidExtendedKey
+		}
+		value.Mul(value, radix)
+		value.Add(value, big.NewInt(int64(digit)))
+	}
+	decoded := value.Bytes()
+	for i := 0; i < len(encoded) && encoded[i] == base58Alphabet[0]; i++ {
+		decoded = append([]byte{0}, decoded...)
+	}
+	if len(decoded) < 4 {
+		return nil, ErrInvalidExtendedKey
+	}
+	data, sum := decoded[:len(decoded)-4], decoded[len(decoded)-4:]
+	if !bytes.Equal(checksum(data), sum) {
+		return nil, ErrInvalidExtendedKey
+	}
+	return data, nil
+}
+
+func base58CheckEncode(data []byte) string {
+
+	data = append(common.CopyBytes(data), checksum(data)...)
+	value := new(big.Int).SetBytes(data)
+	radix, mod := big.NewInt(58), new(big.Int)
+
+	var encoded []byte
+	for value.Sign() > 0 {
+		value.DivMod(value, radix, mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+	for i := 0; i < len(data) && data[i] == 0; i++ {
+		encoded = append(encoded, base58Alphabet[0])
+	}
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
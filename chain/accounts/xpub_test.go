@@ -0,0 +1,77 @@
+package accounts
+
+import (
+	"testing"
+)
+
+//BIP-32测试向量2
+const (
+	testMasterXpub = "xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB"
+	testChildXpub  = "xpub69H7F5d8KSRgmmdJg2KhpAK8SR3DjMwAdkxj3ZuxV27CprR9LgpeyGmXUbC6wb7ERfvrnKZjXoUmmDznezpbZb7ap6r1D3tgFxHmwMkQTPH"
+)
+
+//测试扩展公钥的解析、派生以及编码与BIP-32测试向量一致
+func TestExtendedPublicKeyDerivation(t *testing.T) {
+	master, err := ParseExtendedPublicKey(testMasterXpub)
+	if err != nil {
+		t.Fatalf("failed to parse xpub: %v", err)
+	}
+	if master.String() != testMasterXpub {
+		t.Errorf("master encoding mismatch: have %s", master.String())
+	}
+	child, err := master.Derive(DerivationPath{0})
+	if err != nil {
+		t.Fatalf("failed to derive child: %v", err)
+	}
+	if child.String() != testChildXpub {
+		t.Errorf("child encoding mismatch: have %s, want %s", child.String(), testChildXpub)
+	}
+	if _, err := master.Child(hardenedOffset); err != ErrHardenedDerivation {
+		t.Errorf("hardened derivation error mismatch: have %v, want %v", err, ErrHardenedDerivation)
+	}
+}
+
+//测试拒绝私钥以及校验码错误的扩展密钥
+func TestExtendedPublicKeyInvalid(t *testing.T) {
+	xprv := "xprv9s21ZrQH143K31xYSDQpPDxsXRTUcvj2iNHm5NUtrGiGG5e2DtALGdso3pGz6ssrdK4PFmM8NSpSBHNqPqm55Qn3LqFtT2emdEXVYsCzC2U"
+	if _, err := ParseExtendedPublicKey(xprv); err != ErrPrivateExtendedKey {
+		t.Errorf("private key error mismatch: have %v, want %v", err, ErrPrivateExtendedKey)
+	}
+	corrupt := testMasterXpub[:len(testMasterXpub)-1] + "C"
+	if _, err := ParseExtendedPublicKey(corrupt); err != ErrInvalidExtendedKey {
+		t.Errorf("checksum error mismatch: have %v, want %v", err, ErrInvalidExtendedKey)
+	}
+}
+
+//测试充值地址集合的派生、替换以及删除
+func TestTrackDeposits(t *testing.T) {
+	am := NewManager()
+	defer am.Close()
+
+	addresses, err := am.TrackDeposits(DepositSet{Name: "hot", Xpub: testMasterXpub, Start: 0, Count: 3})
+	if err != nil {
+		t.Fatalf("failed to track deposits: %v", err)
+	}
+	if len(addresses) != 3 || addresses[2].Index != 2 {
+		t.Fatalf("derived addresses mismatch: %v", addresses)
+	}
+	child, _ := ParseExtendedPublicKey(testChildXpub)
+	if addresses[0].Address != child.Address() {
+		t.Errorf("first address mismatch: have %x, want %x", addresses[0].Address, child.Address())
+	}
+	if _, err := am.TrackDeposits(DepositSet{Name: "hot", Xpub: testMasterXpub, Start: 5, Count: 1}); err != nil {
+		t.Fatalf("failed to replace deposits: %v", err)
+	}
+	if addresses, _ := am.DepositAddresses("hot"); len(addresses) != 1 || addresses[0].Index != 5 {
+		t.Errorf("replaced addresses mismatch: %v", addresses)
+	}
+	if _, err := am.TrackDeposits(DepositSet{Name: "cold", Xpub: testMasterXpub, Count: MaxDepositAddresses + 1}); err != ErrDepositSetTooLarge {
+		t.Errorf("size error mismatch: have %v, want %v", err, ErrDepositSetTooLarge)
+	}
+	if !am.UntrackDeposits("hot") || len(am.DepositSets()) != 0 {
+		t.Errorf("deposit set not removed: %v", am.DepositSets())
+	}
+	if _, err := am.DepositAddresses("hot"); err != ErrUnknownDepositSet {
+		t.Errorf("unknown set error mismatch: have %v, want %v", err, ErrUnknownDepositSet)
+	}
+}
@@ -145,9 +145,12 @@ func (boker *BokerBackend) loadConfig() error {
 		}
 		log.Info("(boker *BokerBackend) Blacks", "Size", len(boker.config.BlackList.Blacks))
 
+		//密码不再以明文保存在配置中, 挖矿账号的密码通过--minerpassword指定的加密文件加载
 		boker.config.Producer.Coinbase = config.Producer.Coinbase
-		boker.config.Producer.Password = config.Producer.Password
-		log.Info("Load Bokerchain Producer", "Coinbase", boker.config.Producer.Coinbase, "Password", boker.config.Producer.Password)
+		if config.Producer.Password != "" {
+			log.Warn("Ignoring plaintext producer password in boker.json, use a sealed password file (--minerpassword)")
+		}
+		log.Info("Load Bokerchain Producer", "Coinbase", boker.config.Producer.Coinbase)
 
 		return nil
 	}
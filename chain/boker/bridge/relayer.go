@@ -0,0 +1,330 @@
+//bridge包实现跨链桥的中继服务: 轮询外部以太坊链上跨链桥合约的BridgeBurn事件,
+//事件达到确认区块数后使用本节点的挖矿账号(验证者)提交BridgeRelease交易,
+//超过三分之二的验证者确认后释放本链上锁定的资产。
+//
+//本链锁定资产(BridgeLock)后在外部链上的铸造由外部链合约根据锁定记录的存储证明(eth_getProof)完成, 不经过中继服务。
+package bridge
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/boker/api"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+const (
+	pollInterval   = 15 * time.Second //轮询外部链的间隔
+	requestTimeout = 30 * time.Second //单次轮询的超时时间
+	maxBlockRange  = 1000             //单次轮询最多扫描的外部链区块数
+)
+
+var (
+	errMissingURL      = errors.New("bridge remote url missing")
+	errMissingContract = errors.New("bridge remote contract missing")
+	errInvalidBurn     = errors.New("invalid bridge burn event")
+	errBokerNotReady   = errors.New("boker service not ready")
+)
+
+//已经扫描的外部链区块在数据库中的前缀(后接合约地址)
+var progressPrefix = []byte("bridge-relayed-")
+
+//中继服务的配置
+type Config struct {
+	URL           string         `toml:",omitempty"` //外部链的RPC地址
+	Contract      common.Address `toml:",omitempty"` //外部链上的跨链桥合约地址
+	Confirmations uint64         `toml:",omitempty"` //外部链上的事件需要的确认区块数
+	FromBlock     uint64         `toml:",omitempty"` //首次启动时开始扫描的外部链区块
+}
+
+//中继服务使用的节点接口
+type Backend interface {
+	BlockChain() *core.BlockChain
+	ChainDb() ethdb.Database
+	Coinbase() (common.Address, error)
+	Boker() bokerapi.Api
+	TxPool() *core.TxPool
+}
+
+//已经提交但还没有打包的释放交易
+type pendingRelease struct {
+	tx    common.Hash //释放交易的哈希
+	block uint64      //销毁事件所在的外部链区块
+}
+
+//跨链桥中继服务
+type Relayer struct {
+	config  *Config
+	backend Backend
+	client  *rpc.Client
+	relay   func(ctx context.Context, args *protocol.BridgeReleaseArgs) (common.Hash, error) //提交释放交易，不需要提交时返回空哈希
+	status  func(hash common.Hash) core.TxStatus                                             //查询释放交易的状态
+
+	next    uint64                          //下一个需要扫描的外部链区块
+	pending map[common.Hash]*pendingRelease //已经提交但还没有打包的释放交易(键为释放编号)
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+//根据配置连接外部链
+func New(config *Config, backend Backend) (*Relayer, error) {
+
+	if config == nil || config.URL == "" {
+		return nil, errMissingURL
+	}
+	if config.Contract == (common.Address{}) {
+		return nil, errMissingContract
+	}
+	client, err := rpc.Dial(config.URL)
+	if err != nil {
+		return nil, err
+	}
+	return newRelayer(config, backend, client), nil
+}
+
+func newRelayer(config *Config, backend Backend, client *rpc.Client) *Relayer {
+
+	r := &Relayer{
+		config:  config,
+		backend: backend,
+		client:  client,
+		next:    config.FromBlock,
+		pending: make(map[common.Hash]*pendingRelease),
+		quit:    make(chan struct{}),
+	}
+	r.relay = r.submitRelease
+	r.status = r.txStatus
+	if data, _ := backend.ChainDb().Get(r.progressKey()); len(data) == 8 {
+		r.next = binary.BigEndian.Uint64(data)
+	}
+	return r
+}
+
+//启动中继服务
+func (r *Relayer) Start() {
+
+	log.Info("Bridge relayer started", "url", r.config.URL, "contract", r.config.Contract, "from", r.next)
+	r.wg.Add(1)
+	go r.loop()
+}
+
+//停止中继服务并关闭与外部链的连接
+func (r *Relayer) Stop() {
+
+	close(r.quit)
+	r.wg.Wait()
+	r.client.Close()
+	log.Info("Bridge relayer stopped")
+}
+
+func (r *Relayer) loop() {
+
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		if err := r.poll(ctx); err != nil {
+			log.Warn("Bridge relayer poll failed", "url", r.config.URL, "err", err)
+		}
+		cancel()
+
+		select {
+		case <-ticker.C:
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+func (r *Relayer) progressKey() []byte {
+	return append(append([]byte{}, progressPrefix...), r.config.Contract.Bytes()...)
+}
+
+//扫描外部链上达到确认区块数的销毁事件并提交释放交易，
+//扫描进度不会超过还没有打包的释放交易对应的区块
+func (r *Relayer) poll(ctx context.Context) error {
+
+	r.checkPending()
+
+	var head hexutil.Uint64
+	if err := r.client.CallContext(ctx, &head, "eth_blockNumber"); err != nil {
+		return err
+	}
+	if uint64(head) < r.config.Confirmations {
+		return nil
+	}
+	to := uint64(head) - r.config.Confirmations
+	if r.next > to {
+		return nil
+	}
+	if to-r.next >= maxBlockRange {
+		to = r.next + maxBlockRange - 1
+	}
+
+	var logs []types.Log
+	filter := map[string]interface{}{
+		"address":   r.config.Contract,
+		"fromBlock": hexutil.EncodeUint64(r.next),
+		"toBlock":   hexutil.EncodeUint64(to),
+		"topics":    []interface{}{core.BridgeBurnTopic},
+	}
+	if err := r.client.CallContext(ctx, &logs, "eth_getLogs", filter); err != nil {
+		return err
+	}
+	for i := range logs {
+		if logs[i].Removed {
+			continue
+		}
+		args, err := parseBurn(&logs[i])
+		if err != nil {
+			log.Warn("Skipping bridge burn event", "tx", logs[i].TxHash, "index", logs[i].Index, "err", err)
+			continue
+		}
+		id := core.BridgeReleaseId(args)
+		if _, ok := r.pending[id]; ok {
+			continue
+		}
+		//提交失败时下次轮询从该事件所在的区块重新扫描
+		hash, err := r.relay(ctx, args)
+		if err != nil {
+			r.setNext(r.pendingLimit(logs[i].BlockNumber))
+			return err
+		}
+		if hash != (common.Hash{}) {
+			r.pending[id] = &pendingRelease{tx: hash, block: logs[i].BlockNumber}
+		}
+	}
+	r.setNext(r.pendingLimit(to + 1))
+	return nil
+}
+
+//清理已经打包或者被交易池丢弃的释放交易，丢弃的交易在重新扫描时再次提交
+func (r *Relayer) checkPending() {
+
+	for id, release := range r.pending {
+		switch r.status(release.tx) {
+		case core.TxStatusIncluded:
+			log.Info("Bridge release included", "tx", release.tx, "block", release.block)
+			delete(r.pending, id)
+		case core.TxStatusUnknown:
+			log.Warn("Bridge release dropped, resubmitting", "tx", release.tx, "block", release.block)
+			delete(r.pending, id)
+		}
+	}
+}
+
+//返回扫描进度可以到达的区块，不超过最早一个还没有打包的释放交易对应的区块
+func (r *Relayer) pendingLimit(next uint64) uint64 {
+
+	for _, release := range r.pending {
+		if release.block < next {
+			next = release.block
+		}
+	}
+	return next
+}
+
+//先查询交易池，交易不在交易池中时再查询本链上是否已经打包
+func (r *Relayer) txStatus(hash common.Hash) core.TxStatus {
+
+	if status := r.backend.TxPool().Status([]common.Hash{hash})[0]; status != core.TxStatusUnknown {
+		return status
+	}
+	if blockHash, _, _ := core.GetTxLookupEntry(r.backend.ChainDb(), hash); blockHash != (common.Hash{}) {
+		return core.TxStatusIncluded
+	}
+	return core.TxStatusUnknown
+}
+
+func (r *Relayer) setNext(next uint64) {
+
+	r.next = next
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, next)
+	if err := r.backend.ChainDb().Put(r.progressKey(), data); err != nil {
+		log.Error("Failed to store bridge relayer progress", "err", err)
+	}
+}
+
+//解析外部链合约的BridgeBurn(address indexed sender, address indexed recipient, uint256 amount)事件
+func parseBurn(l *types.Log) (*protocol.BridgeReleaseArgs, error) {
+
+	if len(l.Topics) != 3 || l.Topics[0] != core.BridgeBurnTopic || len(l.Data) != common.HashLength {
+		return nil, errInvalidBurn
+	}
+	amount := new(big.Int).SetBytes(l.Data)
+	if amount.Sign() <= 0 {
+		return nil, errInvalidBurn
+	}
+	return &protocol.BridgeReleaseArgs{
+		RemoteTx:  l.TxHash,
+		LogIndex:  uint64(l.Index),
+		Recipient: common.BytesToAddress(l.Topics[2].Bytes()),
+		Amount:    amount,
+	}, nil
+}
+
+//使用挖矿账号提交释放交易，挖矿账号不是验证者、已经确认过或者已经释放时跳过
+func (r *Relayer) submitRelease(ctx context.Context, args *protocol.BridgeReleaseArgs) (common.Hash, error) {
+
+	if r.backend.Boker() == nil {
+		return common.Hash{}, errBokerNotReady
+	}
+	coinbase, err := r.backend.Coinbase()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	chain := r.backend.BlockChain()
+	header := chain.CurrentHeader()
+	statedb, err := chain.StateAt(header.Root)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	dposContext, err := types.NewDposContextFromProto(r.backend.ChainDb(), header.DposProto)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if !dposContext.IsValidator(coinbase) {
+		log.Debug("Bridge relayer coinbase is not a validator", "coinbase", coinbase)
+		return common.Hash{}, nil
+	}
+
+	id := core.BridgeReleaseId(args)
+	if core.IsBridgeReleased(statedb, id) || core.HasBridgeApproval(statedb, id, coinbase) {
+		return common.Hash{}, nil
+	}
+
+	data, err := rlp.EncodeToBytes(args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tx, err := r.backend.Boker().SubmitBokerTransaction(ctx,
+		protocol.SystemBase,
+		protocol.BridgeRelease,
+		coinbase,
+		protocol.BridgeAddress,
+		[]byte(""),
+		data,
+		new(big.Int).SetUint64(0),
+		0)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Submitted bridge release", "remoteTx", args.RemoteTx, "index", args.LogIndex, "recipient", args.Recipient, "amount", args.Amount, "tx", tx.Hash())
+	return tx.Hash(), nil
+}
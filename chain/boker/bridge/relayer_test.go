@@ -0,0 +1,170 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/api"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//模拟外部链，返回固定的区块高度和日志
+type RemoteService struct {
+	head    uint64
+	logs    []*types.Log
+	filters []map[string]interface{}
+}
+
+func (s *RemoteService) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(s.head)
+}
+
+func (s *RemoteService) GetLogs(filter map[string]interface{}) []*types.Log {
+	s.filters = append(s.filters, filter)
+	return s.logs
+}
+
+//模拟节点，只提供数据库
+type testBackend struct {
+	db ethdb.Database
+}
+
+func (b *testBackend) BlockChain() *core.BlockChain      { return nil }
+func (b *testBackend) ChainDb() ethdb.Database           { return b.db }
+func (b *testBackend) Coinbase() (common.Address, error) { return common.Address{}, nil }
+func (b *testBackend) Boker() bokerapi.Api               { return nil }
+func (b *testBackend) TxPool() *core.TxPool              { return nil }
+
+func newTestRelayer(t *testing.T, remote *RemoteService, db ethdb.Database) *Relayer {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", remote); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	config := &Config{Contract: common.HexToAddress("0xb1"), Confirmations: 5}
+	return newRelayer(config, &testBackend{db}, rpc.DialInProc(server))
+}
+
+func burnLog(block uint64, index uint, recipient common.Address, amount int64) *types.Log {
+	return &types.Log{
+		Address:     common.HexToAddress("0xb1"),
+		Topics:      []common.Hash{core.BridgeBurnTopic, common.HexToAddress("0x01").Hash(), recipient.Hash()},
+		Data:        common.BigToHash(big.NewInt(amount)).Bytes(),
+		BlockNumber: block,
+		TxHash:      common.BytesToHash([]byte{byte(block)}),
+		Index:       index,
+	}
+}
+
+//测试只中继达到确认区块数的有效销毁事件，并保存扫描进度
+func TestRelayBurns(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	recipient := common.HexToAddress("0x02")
+	invalid := burnLog(4, 1, recipient, 7)
+	invalid.Topics = invalid.Topics[:2]
+	remote := &RemoteService{head: 20, logs: []*types.Log{burnLog(3, 0, recipient, 7), invalid}}
+
+	relayer := newTestRelayer(t, remote, db)
+	var relayed []*protocol.BridgeReleaseArgs
+	relayer.relay = func(ctx context.Context, args *protocol.BridgeReleaseArgs) (common.Hash, error) {
+		relayed = append(relayed, args)
+		return common.Hash{}, nil
+	}
+	if err := relayer.poll(context.Background()); err != nil {
+		t.Fatalf("failed to poll: %v", err)
+	}
+	if len(relayed) != 1 {
+		t.Fatalf("relayed count mismatch: have %d, want 1", len(relayed))
+	}
+	if args := relayed[0]; args.Recipient != recipient || args.Amount.Int64() != 7 || args.LogIndex != 0 || args.RemoteTx != remote.logs[0].TxHash {
+		t.Errorf("relayed args mismatch: %+v", args)
+	}
+	if filter := remote.filters[0]; filter["fromBlock"] != "0x0" || filter["toBlock"] != "0xf" {
+		t.Errorf("filter range mismatch: %v", filter)
+	}
+	if restarted := newTestRelayer(t, remote, db); restarted.next != 16 {
+		t.Errorf("stored progress mismatch: have %d, want 16", restarted.next)
+	}
+}
+
+//测试提交失败时从失败事件所在的区块重新扫描
+func TestRelayRetry(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	remote := &RemoteService{head: 20, logs: []*types.Log{burnLog(3, 0, common.HexToAddress("0x02"), 7), burnLog(9, 0, common.HexToAddress("0x03"), 8)}}
+
+	relayer := newTestRelayer(t, remote, db)
+	failure := errors.New("account locked")
+	relayer.relay = func(ctx context.Context, args *protocol.BridgeReleaseArgs) (common.Hash, error) {
+		if args.Amount.Int64() == 8 {
+			return common.Hash{}, failure
+		}
+		return common.Hash{}, nil
+	}
+	if err := relayer.poll(context.Background()); err != failure {
+		t.Fatalf("poll error mismatch: have %v, want %v", err, failure)
+	}
+	if relayer.next != 9 {
+		t.Errorf("retry block mismatch: have %d, want 9", relayer.next)
+	}
+}
+
+//测试已提交的释放交易打包前不推进扫描进度也不重复提交，被丢弃时重新提交
+func TestRelayPending(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	remote := &RemoteService{head: 20, logs: []*types.Log{burnLog(3, 0, common.HexToAddress("0x02"), 7), burnLog(9, 0, common.HexToAddress("0x03"), 8)}}
+
+	relayer := newTestRelayer(t, remote, db)
+	submits := make(map[int64]int)
+	statuses := make(map[common.Hash]core.TxStatus)
+	//已经打包的释放交易表示验证者已经确认，不需要再次提交
+	relayer.relay = func(ctx context.Context, args *protocol.BridgeReleaseArgs) (common.Hash, error) {
+		hash := common.BigToHash(args.Amount)
+		if statuses[hash] == core.TxStatusIncluded {
+			return common.Hash{}, nil
+		}
+		submits[args.Amount.Int64()]++
+		return hash, nil
+	}
+	relayer.status = func(hash common.Hash) core.TxStatus {
+		return statuses[hash]
+	}
+	poll := func() {
+		if err := relayer.poll(context.Background()); err != nil {
+			t.Fatalf("failed to poll: %v", err)
+		}
+	}
+	statuses[common.BigToHash(big.NewInt(7))] = core.TxStatusPending
+	statuses[common.BigToHash(big.NewInt(8))] = core.TxStatusPending
+	poll()
+	poll()
+	if relayer.next != 3 {
+		t.Errorf("progress with pending releases: have %d, want 3", relayer.next)
+	}
+	if submits[7] != 1 || submits[8] != 1 {
+		t.Errorf("pending releases resubmitted: %v", submits)
+	}
+
+	//第一个释放交易打包，第二个被交易池丢弃
+	statuses[common.BigToHash(big.NewInt(7))] = core.TxStatusIncluded
+	statuses[common.BigToHash(big.NewInt(8))] = core.TxStatusUnknown
+	poll()
+	if relayer.next != 9 {
+		t.Errorf("progress with dropped release: have %d, want 9", relayer.next)
+	}
+	if submits[8] != 2 {
+		t.Errorf("dropped release submissions: have %d, want 2", submits[8])
+	}
+
+	statuses[common.BigToHash(big.NewInt(8))] = core.TxStatusIncluded
+	poll()
+	if relayer.next != 16 {
+		t.Errorf("progress after inclusion: have %d, want 16", relayer.next)
+	}
+}
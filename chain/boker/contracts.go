@@ -11,6 +11,7 @@ import (
 	"github.com/Tinachain/Tina/chain/core/types"
 	"github.com/Tinachain/Tina/chain/eth"
 	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/internal/ethapi"
 	"github.com/Tinachain/Tina/chain/log"
 )
 
@@ -63,6 +64,7 @@ func NewContract(db ethdb.Database, ethereum *eth.Ethereum, bokerProto *types.Bo
 	}
 	log.Info("contracts.go NewContract Start System Contract Service")
 	bokerContracts.services.contract.Start()
+	ethapi.RegisterContractABI(address, boker_contract.BokerInterfaceBaseABI)
 
 	return bokerContracts, nil
 }
@@ -86,6 +88,7 @@ func (c *BokerContracts) SetSystemContract(address common.Address, from common.A
 
 	log.Info("SetContract Start Contract")
 	c.services.contract.Start()
+	ethapi.RegisterContractABI(address, boker_contract.BokerInterfaceBaseABI)
 
 	return nil
 }
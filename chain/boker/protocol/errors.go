@@ -0,0 +1,64 @@
+package protocol
+
+import "fmt"
+
+//错误码的分类，每类占用100个错误码
+const (
+	ErrCategoryConsensus   = 1000 //出块与共识
+	ErrCategoryTransaction = 1100 //交易格式
+	ErrCategoryContract    = 1200 //基础合约
+	ErrCategoryStock       = 1300 //账号与股权
+	ErrCategoryValidator   = 1400 //验证者
+	ErrCategoryGovernance  = 1500 //治理与奖励
+	ErrCategoryBridge      = 1600 //跨链转账
+	ErrCategoryMultiSend   = 1700 //批量转账
+	ErrCategorySponsor     = 1800 //代付Gas
+	ErrCategorySystem      = 1900 //系统内部错误
+)
+
+var errCategoryNames = map[int]string{
+	ErrCategoryConsensus:   "consensus",
+	ErrCategoryTransaction: "transaction",
+	ErrCategoryContract:    "contract",
+	ErrCategoryStock:       "stock",
+	ErrCategoryValidator:   "validator",
+	ErrCategoryGovernance:  "governance",
+	ErrCategoryBridge:      "bridge",
+	ErrCategoryMultiSend:   "multisend",
+	ErrCategorySponsor:     "sponsor",
+	ErrCategorySystem:      "system",
+}
+
+//Tina链的错误，通过RPC返回时带有数字错误码以及分类信息，客户端可以根据错误码区分失败类型
+type Error struct {
+	code    int
+	message string
+}
+
+//通过RPC返回的错误数据
+type ErrorData struct {
+	Code     int    `json:"code"`
+	Category string `json:"category"`
+}
+
+//已经使用的错误码
+var errCodes = make(map[int]string)
+
+func newError(code int, message string) *Error {
+
+	if prev, ok := errCodes[code]; ok {
+		panic(fmt.Sprintf("duplicate boker error code %d: %q and %q", code, prev, message))
+	}
+	errCodes[code] = message
+	return &Error{code: code, message: message}
+}
+
+func (e *Error) Error() string  { return e.message }
+func (e *Error) ErrorCode() int { return e.code }
+
+//错误码所属的分类
+func (e *Error) Category() int { return e.code / 100 * 100 }
+
+func (e *Error) ErrorData() interface{} {
+	return &ErrorData{Code: e.code, Category: errCategoryNames[e.Category()]}
+}
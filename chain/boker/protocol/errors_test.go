@@ -0,0 +1,41 @@
+package protocol
+
+import "testing"
+
+// Tests that every boker error carries a code within a named category.
+func TestErrorCategories(t *testing.T) {
+	for code, message := range errCodes {
+		if _, ok := errCategoryNames[code/100*100]; !ok {
+			t.Errorf("error %d (%q) has no category", code, message)
+		}
+	}
+
+	tests := []struct {
+		err      *Error
+		code     int
+		category string
+	}{
+		{ErrInvalidSponsor, 1800, "sponsor"},
+		{ErrInvalidMultiSend, 1700, "multisend"},
+		{ErrBridgeReleased, 1602, "bridge"},
+		{ErrInvalidContractMeta, 1212, "contract"},
+	}
+	for i, tt := range tests {
+		if code := tt.err.ErrorCode(); code != tt.code {
+			t.Errorf("test %d: code mismatch: have %d, want %d", i, code, tt.code)
+		}
+		data := tt.err.ErrorData().(*ErrorData)
+		if data.Code != tt.code || data.Category != tt.category {
+			t.Errorf("test %d: data mismatch: have %+v, want %d/%s", i, data, tt.code, tt.category)
+		}
+	}
+}
+
+func TestDuplicateErrorCode(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("duplicate error code accepted")
+		}
+	}()
+	newError(ErrInvalidSponsor.ErrorCode(), "duplicate")
+}
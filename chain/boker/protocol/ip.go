@@ -0,0 +1,97 @@
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sync"
+)
+
+//交易IP字段的处理方式
+const (
+	IpModeFull   = "full"   //完整IP(默认，与依赖IP字段的链保持兼容)
+	IpModeSubnet = "subnet" //只保留网段(IPv4保留前24位，IPv6保留前48位)
+	IpModeHash   = "hash"   //加盐哈希，可以区分来源节点但不能还原IP
+	IpModeOff    = "off"    //不记录或不输出
+)
+
+//节点对交易IP字段的隐私策略
+type IpPolicy struct {
+	Embed  string //节点提交交易时写入IP字段的方式
+	Redact string //RPC输出交易IP字段的方式
+	Salt   string //hash方式使用的盐
+}
+
+var (
+	ipPolicy   = IpPolicy{Embed: IpModeFull, Redact: IpModeFull}
+	ipPolicyMu sync.RWMutex
+)
+
+//检查IP字段的处理方式是否合法(为空时按full处理)
+func CheckIpMode(mode string) error {
+
+	switch mode {
+	case "", IpModeFull, IpModeSubnet, IpModeHash, IpModeOff:
+		return nil
+	}
+	return fmt.Errorf("invalid ip mode %q (full, subnet, hash or off)", mode)
+}
+
+//设置节点的IP隐私策略
+func SetIpPolicy(policy IpPolicy) error {
+
+	if err := CheckIpMode(policy.Embed); err != nil {
+		return err
+	}
+	if err := CheckIpMode(policy.Redact); err != nil {
+		return err
+	}
+	ipPolicyMu.Lock()
+	defer ipPolicyMu.Unlock()
+	ipPolicy = policy
+	return nil
+}
+
+func currentIpPolicy() IpPolicy {
+	ipPolicyMu.RLock()
+	defer ipPolicyMu.RUnlock()
+	return ipPolicy
+}
+
+//按照处理方式转换IP，无法解析的IP在subnet方式下返回空
+func AnonymizeIp(ip string, mode string, salt string) string {
+
+	if ip == "" {
+		return ""
+	}
+	switch mode {
+	case IpModeSubnet:
+		parsed := net.ParseIP(ip)
+		if parsed == nil {
+			return ""
+		}
+		if v4 := parsed.To4(); v4 != nil {
+			return (&net.IPNet{IP: v4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+		}
+		return (&net.IPNet{IP: parsed.Mask(net.CIDRMask(48, 128)), Mask: net.CIDRMask(48, 128)}).String()
+	case IpModeHash:
+		hash := sha256.Sum256(append([]byte(salt), ip...))
+		return hex.EncodeToString(hash[:8])
+	case IpModeOff:
+		return ""
+	}
+	return ip
+}
+
+//得到节点写入交易IP字段的值
+func TxIp() string {
+	policy := currentIpPolicy()
+	return AnonymizeIp(GetExternalIp(), policy.Embed, policy.Salt)
+}
+
+//得到RPC输出的交易IP字段
+func RedactIp(ip []byte) string {
+	policy := currentIpPolicy()
+	return AnonymizeIp(string(ip), policy.Redact, policy.Salt)
+}
@@ -0,0 +1,51 @@
+package protocol
+
+import "testing"
+
+func TestAnonymizeIp(t *testing.T) {
+	tests := []struct {
+		ip, mode, want string
+	}{
+		{"203.0.113.57", IpModeFull, "203.0.113.57"},
+		{"203.0.113.57", "", "203.0.113.57"},
+		{"203.0.113.57", IpModeSubnet, "203.0.113.0/24"},
+		{"2001:db8:85a3::8a2e:370:7334", IpModeSubnet, "2001:db8:85a3::/48"},
+		{"not-an-ip", IpModeSubnet, ""},
+		{"203.0.113.57", IpModeOff, ""},
+		{"", IpModeHash, ""},
+	}
+	for i, tt := range tests {
+		if have := AnonymizeIp(tt.ip, tt.mode, "salt"); have != tt.want {
+			t.Errorf("test %d: have %q, want %q", i, have, tt.want)
+		}
+	}
+
+	//同一个盐得到相同的哈希，不同的盐得到不同的哈希
+	hash := AnonymizeIp("203.0.113.57", IpModeHash, "salt")
+	if len(hash) != 16 || hash == "203.0.113.57" {
+		t.Fatalf("unexpected hash %q", hash)
+	}
+	if again := AnonymizeIp("203.0.113.57", IpModeHash, "salt"); again != hash {
+		t.Errorf("hash not stable: have %q, want %q", again, hash)
+	}
+	if other := AnonymizeIp("203.0.113.57", IpModeHash, "pepper"); other == hash {
+		t.Errorf("hash independent of salt: %q", other)
+	}
+}
+
+func TestSetIpPolicy(t *testing.T) {
+	defer SetIpPolicy(IpPolicy{Embed: IpModeFull, Redact: IpModeFull})
+
+	if err := SetIpPolicy(IpPolicy{Embed: "mask"}); err == nil {
+		t.Fatal("invalid mode accepted")
+	}
+	if err := SetIpPolicy(IpPolicy{Embed: IpModeOff, Redact: IpModeSubnet}); err != nil {
+		t.Fatalf("failed to set policy: %v", err)
+	}
+	if ip := RedactIp([]byte("198.51.100.7")); ip != "198.51.100.0/24" {
+		t.Errorf("redacted ip mismatch: have %q, want %q", ip, "198.51.100.0/24")
+	}
+	if ip := TxIp(); ip != "" {
+		t.Errorf("disabled ip written: %q", ip)
+	}
+}
@@ -52,6 +52,12 @@ const (
 //次要交易类型
 type TxMinor uint8
 
+//普通交易的次要类型
+const (
+	NormalCall TxMinor = iota //普通交易
+	Sponsored                 //由代付账号支付Gas的交易（代付账号的签名放在交易的扩展数据中）
+)
+
 //基础交易的次要类型
 const (
 	MinMinor          TxMinor = iota
@@ -62,6 +68,20 @@ const (
 	VoteCancel                //用户取消投票
 	VoteEpoch                 //产生当前的出块节点(在每次周期产生的时候触发)
 	Timeout                   //超时处理
+	SetRewardSchedule         //调整出块奖励计划（在下一个周期开始时生效）
+	GovPropose                //发起治理提案
+	GovVote                   //对治理提案投票
+	ConfirmContract           //确认待生效的系统基础合约变更
+	ApproveDeployer           //允许账号部署合约（开启部署白名单时有效）
+	RevokeDeployer            //取消账号部署合约的权限
+	SetSigningKey             //设置验证者的出块签名账号（to为验证者自身时恢复使用验证者账号签名）
+	BridgeLock                //锁定资产以跨链转出（to为外部链上的接收账号）
+	BridgeRelease             //验证者确认外部链上的销毁事件并释放锁定的资产
+	MultiSend                 //批量转账（接收账号和数量列表放在交易负载中，to为批量转账系统账号）
+	SetCommission             //出块节点登记佣金比例（百分比放在交易负载中，to为委托奖励系统账号）
+	ClaimRewards              //投票者领取委托奖励（to为委托奖励系统账号）
+	SetValidatorInfo          //登记或更新验证者的运营信息（信息放在交易负载中，to为验证者自身，信息为空时删除）
+	FundGasPool               //向股权Gas池注入资金（数量为交易的value，to为股权Gas池系统账号）
 	MaxMinor                  //最大值
 )
 
@@ -75,6 +95,7 @@ const (
 const (
 	Word TxMinor = iota
 	Data
+	ContractMeta //登记合约元数据（名称、Abi哈希、源码链接）
 )
 
 //股权类型的次要类型
@@ -104,6 +125,14 @@ const (
 	User                           //个人基础合约
 )
 
+//基础合约的变更操作
+type BaseContractAction uint8
+
+const (
+	ContractSet    BaseContractAction = iota //设置基础合约
+	ContractCancel                           //取消基础合约
+)
+
 type StockState uint8
 
 const (
@@ -120,8 +149,6 @@ var (
 	MaxGasLimit        *big.Int = new(big.Int).SetUint64(0)                  //最大的GasLimit
 	TimeOfFirstBlock            = int64(0)                                   //创世区块的时间偏移量
 	ConfirmedBlockHead          = []byte("confirmed-block-head")
-	MaxWordSize                 = int64(1 * 1024 * 1024)
-	MaxDataSize                 = int(1 * 1024 * 1024)
 	MaxExtraSize                = int64(5 * 1024 * 1024)
 	MaxNormalSize               = common.StorageSize(32 * 1024)
 	MaxBlockSize                = int64(5 * 1024 * 1024)
@@ -147,13 +174,19 @@ var (
 	ValidatorPrefix  = []byte("validator")  //存放验证者投票信息
 	VotePrefix       = []byte("vote")       //存放投票数量
 	ValidatorsPrefix = []byte("validators") //存放所有的验证者列表
+	VoterPrefix      = []byte("voter")      //存放账号的投票记录(投票权重分叉之后)
 
 )
 
-//合约相关
+//合约相关(存放在Boker的合约树中)
 var (
-	SingleContractPrefix = []byte("single")    //存放单个合约信息
-	ContractsPrefix      = []byte("contracts") //存放所有合约信息
+	SingleContractPrefix = []byte("single")        //存放单个合约信息
+	ContractsPrefix      = []byte("contracts")     //存放所有合约信息
+	PendingContractKey   = []byte("pending")       //存放待生效的系统基础合约变更
+	SystemContractDelay  = uint64(720)             //系统基础合约变更在提出后需要等待的区块数
+	DeployerPrefix       = []byte("deployer")      //存放允许部署合约的账号
+	SigningKeyPrefix     = []byte("signingKey")    //存放验证者的出块签名账号
+	ValidatorInfoPrefix  = []byte("validatorInfo") //存放验证者的运营信息
 )
 
 //股权相关
@@ -162,50 +195,269 @@ var (
 	StocksPrefix      = []byte("stocks")
 	OwnerPrefix       = []byte("owner")
 	GasPoolPrefix     = []byte("gasPool")
+
+	GasPoolFundPrefix = []byte("gasPoolFund") //存放每个账号累计注入股权Gas池的数量
 )
 
+//治理相关
 var (
-	ErrNilBlockHeader             = errors.New("nil block header returned")                       //区块头为空
-	ErrUnknownBlock               = errors.New("unknown block")                                   //未知区块
-	ErrInvalidProducer            = errors.New("invalid current producer")                        //出块节点出错
-	ErrInvalidProducerTime        = errors.New("invalid time to mint the block")                  //不正确的出块时间
-	ErrInvalidCoinbase            = errors.New("invalid current mining coinbase")                 //当前挖矿账号错误
-	ErrInvalidSystem              = errors.New("invalid current system")                          //当前系统的投票合约出错
-	ErrMismatchSignerAndValidator = errors.New("mismatch block signer and validator")             //签名者和区块头中的验证者不是同一个
-	ErrNoSigner                   = errors.New("missing signing methods")                         //缺少签名方法
-	ErrInvalidType                = errors.New("invalid transaction type")                        //无效的交易类型
-	ErrInvalidAddress             = errors.New("invalid transaction payload address")             //无效的交易有效负载地
-	ErrInvalidAction              = errors.New("invalid transaction payload action")              //无效的事务有效负载操
-	ErrLoadConfig                 = errors.New("load bokerchain config error")                    //加载配置信息出错
-	ErrNotFoundAddress            = errors.New("not found bokerchain contract address")           //没有找到合约地址
-	ErrNotFoundType               = errors.New("not found bokerchain contract type")              //没有找到合约类型
-	ErrWriteJson                  = errors.New("write bokerchain json file error")                //写保存基础合约的Json格式出错
-	ErrOpenFile                   = errors.New("open bokerchain json file error")                 //打开基础合约保存文件出错
-	ErrWriteFile                  = errors.New("bokerchain write file error")                     //写基础合约保存文件出错
-	ErrContractExist              = errors.New("bokerchain contract aleady exist")                //写基础合约保存文件出错
-	ErrSystem                     = errors.New("system error")                                    //系统错误
-	ErrNotFoundContract           = errors.New("not found bokerchain contract")                   //没有找到合约
-	ErrNotFoundAccount            = errors.New("not found bokerchain account")                    //没有找到合约
-	ErrNewContractService         = errors.New("create bokerchain base contract err")             //没有找到合约
-	ErrSaveContractTrie           = errors.New("save contract trie err")                          //没有找到合约
-	ErrLevel                      = errors.New("account level error")                             //没有找到合约
-	ErrPointerIsNil               = errors.New("Trie Pointer is Nil")                             //Hash树指针是nil
-	ErrTransactionType            = errors.New("Error Transaction Type")                          //交易类型错误
-	ErrSpecialAccount             = errors.New("Current Account is`t BokerChain Special Account") //当前账号不是指定的特殊账号
-	ErrValidatorsIsFull           = errors.New("Current Validators is Full")                      //当前验证者数量已满
-	ErrExistsValidators           = errors.New("Current Validators Exists")                       //当前存在验证者
-	ErrGenesisBlock               = errors.New("not genesis block")                               //区块需要不为0，即最近区块不是创世区块，证明已经工作
-	ErrDecodeValidators           = errors.New("failed to decode validators")                     //解码验证者失败
-	ErrEncodeValidators           = errors.New("failed to encode validators")                     //编码验证者失败
-	ErrSetEpochTrieFail           = errors.New("failed set epoch trie")                           //设置周期树失败
-	ErrEpochTrieNil               = errors.New("failed to producers length is zero")              //出块节点长度为0
-	ErrToIsNil                    = errors.New("setValidator block header to is nil")             //设置验证者区块头为nil
-	ErrTxType                     = errors.New("failed to tx type")                               //交易类型失败
-	ErrIsnStock                   = errors.New("not is stock account")                            //不是股权账号
-	ErrIsnOwner                   = errors.New("coinbase not is owner of chain")
-	ErrStockLow                   = errors.New("account stock too low")
+	GovernancePrefix   = []byte("governance") //存放治理提案以及投票信息
+	ProposalCountKey   = []byte("count")      //已产生的提案数量
+	ProposalKeyPrefix  = []byte("proposal")   //单个提案
+	ProposalVotePrefix = []byte("vote")       //单个账号对提案的投票
+	GovVotingEpochs    = uint64(1)            //提案投票持续的周期数
+
+	ProposalWeightPrefix = []byte("weight") //提案产生时快照的单个账号投票权重
+)
+
+var (
+	ErrNilBlockHeader             = newError(1000, "nil block header returned")                       //区块头为空
+	ErrUnknownBlock               = newError(1001, "unknown block")                                   //未知区块
+	ErrInvalidProducer            = newError(1002, "invalid current producer")                        //出块节点出错
+	ErrInvalidProducerTime        = newError(1003, "invalid time to mint the block")                  //不正确的出块时间
+	ErrInvalidCoinbase            = newError(1004, "invalid current mining coinbase")                 //当前挖矿账号错误
+	ErrInvalidSystem              = newError(1200, "invalid current system")                          //当前系统的投票合约出错
+	ErrMismatchSignerAndValidator = newError(1005, "mismatch block signer and validator")             //签名者和区块头中的验证者不是同一个
+	ErrNoSigner                   = newError(1006, "missing signing methods")                         //缺少签名方法
+	ErrInvalidType                = newError(1100, "invalid transaction type")                        //无效的交易类型
+	ErrInvalidAddress             = newError(1101, "invalid transaction payload address")             //无效的交易有效负载地
+	ErrInvalidAction              = newError(1102, "invalid transaction payload action")              //无效的事务有效负载操
+	ErrLoadConfig                 = newError(1900, "load bokerchain config error")                    //加载配置信息出错
+	ErrNotFoundAddress            = newError(1201, "not found bokerchain contract address")           //没有找到合约地址
+	ErrNotFoundType               = newError(1202, "not found bokerchain contract type")              //没有找到合约类型
+	ErrWriteJson                  = newError(1901, "write bokerchain json file error")                //写保存基础合约的Json格式出错
+	ErrOpenFile                   = newError(1902, "open bokerchain json file error")                 //打开基础合约保存文件出错
+	ErrWriteFile                  = newError(1903, "bokerchain write file error")                     //写基础合约保存文件出错
+	ErrContractExist              = newError(1203, "bokerchain contract aleady exist")                //写基础合约保存文件出错
+	ErrSystem                     = newError(1904, "system error")                                    //系统错误
+	ErrNotFoundContract           = newError(1204, "not found bokerchain contract")                   //没有找到合约
+	ErrNotFoundAccount            = newError(1300, "not found bokerchain account")                    //没有找到合约
+	ErrNewContractService         = newError(1205, "create bokerchain base contract err")             //没有找到合约
+	ErrSaveContractTrie           = newError(1206, "save contract trie err")                          //没有找到合约
+	ErrLevel                      = newError(1301, "account level error")                             //没有找到合约
+	ErrPointerIsNil               = newError(1905, "Trie Pointer is Nil")                             //Hash树指针是nil
+	ErrTransactionType            = newError(1103, "Error Transaction Type")                          //交易类型错误
+	ErrSpecialAccount             = newError(1302, "Current Account is`t BokerChain Special Account") //当前账号不是指定的特殊账号
+	ErrValidatorsIsFull           = newError(1400, "Current Validators is Full")                      //当前验证者数量已满
+	ErrExistsValidators           = newError(1401, "Current Validators Exists")                       //当前存在验证者
+	ErrGenesisBlock               = newError(1007, "not genesis block")                               //区块需要不为0，即最近区块不是创世区块，证明已经工作
+	ErrDecodeValidators           = newError(1402, "failed to decode validators")                     //解码验证者失败
+	ErrEncodeValidators           = newError(1403, "failed to encode validators")                     //编码验证者失败
+	ErrNotValidator               = newError(1404, "account is not a validator")                      //账号不是验证者
+	ErrInvalidSigningKey          = newError(1405, "signer is not the validator signing key")         //本节点的签名账号不是验证者当前的出块签名账号
+	ErrSetEpochTrieFail           = newError(1008, "failed set epoch trie")                           //设置周期树失败
+	ErrEpochTrieNil               = newError(1009, "failed to producers length is zero")              //出块节点长度为0
+	ErrToIsNil                    = newError(1104, "setValidator block header to is nil")             //设置验证者区块头为nil
+	ErrTxType                     = newError(1105, "failed to tx type")                               //交易类型失败
+	ErrIsnStock                   = newError(1303, "not is stock account")                            //不是股权账号
+	ErrIsnOwner                   = newError(1304, "coinbase not is owner of chain")
+	ErrStockLow                   = newError(1305, "account stock too low")
+	ErrNotSupportedInLightMode    = newError(1106, "not supported in light mode") //轻节点不支持该操作
+	ErrInvalidRewardSchedule      = newError(1500, "invalid reward schedule")     //奖励计划参数错误
+	ErrInvalidProposal            = newError(1501, "invalid governance proposal") //提案参数错误
+	ErrUnknownProposal            = newError(1502, "unknown governance proposal") //提案不存在
+	ErrProposalClosed             = newError(1503, "governance proposal closed")  //提案投票已结束
+	ErrAlreadyVoted               = newError(1504, "already voted on proposal")   //已经对提案投过票
+	ErrNoVotingWeight             = newError(1505, "account has no voting weight")
+	ErrInvalidGasPoolFunding      = newError(1506, "invalid gas pool contribution")                  //注入股权Gas池的数量必须大于0且不能使Gas池溢出
+	ErrGasPoolInsufficient        = newError(1507, "insufficient funds for gas pool contribution")   //余额不足
+	ErrNotContractAuthority       = newError(1207, "account is neither stock manager nor validator") //无权变更系统基础合约
+	ErrNoPendingContract          = newError(1208, "no pending system contract change")              //没有待生效的系统基础合约变更
+	ErrPendingContractExist       = newError(1209, "system contract change already pending")         //已存在待生效的系统基础合约变更
+	ErrPendingContractLocked      = newError(1210, "system contract change still locked")            //系统基础合约变更尚未到达生效区块
+	ErrPendingContractMismatch    = newError(1211, "mismatch pending system contract")               //确认的合约与待生效的合约不一致
+	ErrInvalidContractMeta        = newError(1212, "invalid contract metadata")                      //合约元数据错误
+	ErrDeployNotApproved          = newError(1213, "account not approved to deploy contracts")       //账号不在部署白名单中
+	ErrContractMetaDisabled       = newError(1214, "contract metadata not enabled at this block")    //当前区块尚未到达合约元数据分叉
+	ErrUnknownMajor               = newError(1107, "unknown major transaction type")                 //未知的主要交易类型
+	ErrUnknownMinor               = newError(1108, "unknown minor transaction type")                 //主要交易类型下未知的次要交易类型
+	ErrMissingRecipient           = newError(1109, "transaction type requires a recipient address")  //该类型的交易必须指定接收地址
+	ErrMissingPayload             = newError(1110, "contract creation without code")                 //创建合约时没有合约代码
+	ErrPayloadTooLarge            = newError(1111, "transaction payload too large")                  //交易数据超过该类型允许的大小
+	ErrMissingTxField             = newError(1112, "missing required transaction field")             //缺少交易必需的字段
+	ErrInvalidChainId             = newError(1113, "transaction signed for a different chain id")    //交易签名的链ID与本链不一致
+	ErrReceiptMissing             = newError(1114, "transaction receipt data unavailable")           //交易已在区块中但收据数据缺失
+	ErrNonCanonicalBlock          = newError(1115, "block hash is not currently canonical")          //要求主链区块时哈希对应的区块不在主链上
+	ErrRPCTimeout                 = newError(1116, "rpc request deadline exceeded")                  //RPC请求超过了该方法的执行期限
+	ErrRewindFinalized            = newError(1117, "cannot rewind below the confirmed block")        //不允许将区块链回滚到已确认(不可逆)的区块以下
+	ErrDuplicateSystemTx          = newError(1118, "duplicate system transaction already pending")   //交易池中已有相同的系统交易
+	ErrUnknownFinalized           = newError(1119, "confirmed block unknown, cannot rewind")         //无法得到已确认(不可逆)的区块时不允许回滚
+	ErrUnprotectedTx              = newError(1120, "unprotected transaction without chain id")       //EIP155生效后拒绝未包含链ID的交易
+	ErrInvalidBridgeAmount        = newError(1600, "invalid bridge amount")                          //跨链数量必须大于0
+	ErrInvalidBridgeRelease       = newError(1601, "invalid bridge release")                         //释放交易负载错误
+	ErrBridgeReleased             = newError(1602, "bridge transfer already released")               //外部链上的销毁事件已经释放
+	ErrBridgeApproved             = newError(1603, "bridge transfer already approved by validator")  //验证者已经确认过该销毁事件
+	ErrBridgeInsufficient         = newError(1604, "insufficient funds for bridge transfer")         //余额不足
+	ErrUnknownBridgeLock          = newError(1605, "unknown bridge lock")                            //锁定记录不存在
+	ErrBridgeDisabled             = newError(1606, "bridge not enabled at this block")               //当前区块尚未到达跨链桥分叉
+	ErrInvalidMultiSend           = newError(1700, "invalid multi-send payload")                     //批量转账负载错误
+	ErrMultiSendTooLarge          = newError(1701, "too many multi-send recipients")                 //批量转账的接收账号过多
+	ErrMultiSendInsufficient      = newError(1702, "insufficient funds for multi-send")              //余额不足
+	ErrMultiSendDisabled          = newError(1703, "multi-send not enabled at this block")           //当前区块尚未到达批量转账分叉
+	ErrInvalidSponsor             = newError(1800, "invalid gas sponsor signature")                  //代付账号的签名错误
+	ErrSponsorInsufficient        = newError(1801, "insufficient sponsor funds for gas")             //代付账号的余额不足以支付Gas
+	ErrInvalidVote                = newError(1406, "invalid validator vote")                         //投票交易负载错误
+	ErrInvalidCommission          = newError(1407, "invalid producer commission")                    //佣金比例必须在0到100之间
+	ErrNoDelegationRewards        = newError(1408, "no delegation rewards to claim")                 //没有可以领取的委托奖励
+	ErrInvalidValidatorInfo       = newError(1409, "invalid validator info")                         //验证者运营信息负载错误或字段过长
+	ErrGovernanceDisabled         = newError(1508, "governance not enabled at this block")           //当前区块尚未到达治理分叉
+	ErrRewardScheduleDisabled     = newError(1509, "reward schedule not enabled at this block")      //当前区块尚未到达奖励计划调整的分叉
+	ErrGasPoolFundingDisabled     = newError(1510, "gas pool funding not enabled at this block")     //当前区块尚未到达股权Gas池注资分叉
+	ErrDelegationDisabled         = newError(1410, "delegation not enabled at this block")           //委托奖励依赖投票记录，当前区块尚未到达投票权重分叉
+	ErrSponsorDisabled            = newError(1802, "gas sponsoring not enabled at this block")       //当前区块尚未到达代付分叉
+	ErrSigningKeyDisabled         = newError(1411, "signing key rotation not enabled at this block") //当前区块尚未到达出块签名账号分叉
+	ErrValidatorInfoDisabled      = newError(1412, "validator info not enabled at this block")       //当前区块尚未到达验证者运营信息分叉
+)
+
+//奖励计划覆盖值在状态中存放的系统账号
+var RewardScheduleAddress = common.BytesToAddress([]byte("tina-reward-schedule"))
+
+//跨链桥锁定的资产以及锁定、释放记录在状态中存放的系统账号
+var BridgeAddress = common.BytesToAddress([]byte("tina-bridge"))
+
+//批量转账交易的to以及每笔转账事件的日志地址
+var MultiSendAddress = common.BytesToAddress([]byte("tina-multisend"))
+
+//注入股权Gas池交易的to(注入的资金从发送账号中扣除，股权分币时重新发放给股权持有者)
+var GasPoolAddress = common.BytesToAddress([]byte("tina-gaspool"))
+
+//出块节点的佣金比例、留给投票者的奖励以及投票者的委托奖励在状态中存放的系统账号
+var DelegationAddress = common.BytesToAddress([]byte("tina-delegation"))
+
+//单笔批量转账交易最多的接收账号数量
+const MaxMultiSendRecipients = 256
+
+//验证者运营信息中每个字段的最大长度（字节）
+const MaxValidatorInfoLength = 256
+
+//验证者的运营信息，供区块浏览器以及投票者查看出块节点的运营者
+type ValidatorInfo struct {
+	Name    string //名称
+	Website string //网站
+	Contact string //联系方式
+}
+
+//治理提案类型
+type ProposalKind uint8
+
+const (
+	ParamChange     ProposalKind = iota //调整出块奖励计划
+	ContractUpgrade                     //升级系统基础合约
 )
 
+//投票权重的计算方式
+type VoteWeight uint8
+
+const (
+	WeightStock   VoteWeight = iota //按股权数量
+	WeightBalance                   //按账号余额（无法在提案产生时快照，不再接受）
+)
+
+//验证者选举中一个账号的投票记录
+type VoteRecord struct {
+	Candidate common.Address //投票的候选者
+	Amount    *big.Int       //投票时转入投票合约的数量
+	Epoch     uint64         //最后一次投票的周期
+}
+
+//提案状态
+type ProposalState uint8
+
+const (
+	ProposalVoting   ProposalState = iota //投票中
+	ProposalEnacted                       //已通过并生效
+	ProposalRejected                      //未通过
+)
+
+//治理提案
+type Proposal struct {
+	Id             uint64
+	Kind           ProposalKind
+	Weight         VoteWeight
+	Proposer       common.Address
+	Description    string
+	Target         common.Address //升级后的系统基础合约地址
+	Reward         *big.Int       //新的区块总奖励（单位为TinaUnit）
+	GasPoolPercent uint64         //新的股权Gas池比例
+	CreatedEpoch   uint64         //提案产生的周期
+	EndEpoch       uint64         //投票结束（并进行计票）的周期
+	Yes            *big.Int       //赞成票权重
+	No             *big.Int       //反对票权重
+	Total          *big.Int       //提案产生时快照的全部投票权重（计算法定人数）
+	State          ProposalState
+}
+
+//发起提案的交易负载
+type ProposalArgs struct {
+	Kind           ProposalKind
+	Weight         VoteWeight
+	Description    string
+	Target         common.Address
+	Reward         *big.Int
+	GasPoolPercent uint64
+}
+
+//投票的交易负载
+type VoteArgs struct {
+	Id      uint64
+	Approve bool
+}
+
+//基础合约的一次变更记录
+type BaseContractRecord struct {
+	Address     common.Address     //合约地址
+	Type        BaseContractType   //合约类型
+	Action      BaseContractAction //变更操作
+	From        common.Address     //发起变更的账号
+	TxHash      common.Hash        //引起变更的交易（治理提案升级时为空）
+	BlockNumber uint64             //变更所在的区块高度
+}
+
+//待生效的系统基础合约变更
+type PendingContract struct {
+	Address        common.Address   //新的系统基础合约地址
+	Proposer       common.Address   //提出变更的账号
+	ProposedBlock  uint64           //提出变更的区块高度
+	EffectiveBlock uint64           //最早可以确认生效的区块高度
+	Approvals      []common.Address //已确认变更的账号
+}
+
+//合约元数据（存放在扩展交易的Extra字段中）
+type ContractMetadata struct {
+	Name    string      //合约名称
+	AbiHash common.Hash //Abi的Keccak256哈希
+	Source  string      //源码链接
+	Abi     string      //Abi（可以为空，只登记哈希）
+}
+
+//跨链锁定记录（外部链上的合约通过eth_getProof得到的存储证明验证后铸造对应的资产）
+type BridgeLockRecord struct {
+	Nonce     uint64         //锁定序号
+	Sender    common.Address //锁定资产的账号
+	Recipient common.Address //外部链上的接收账号
+	Amount    *big.Int       //锁定数量
+}
+
+//释放锁定资产的交易负载（对应外部链上的一次销毁事件）
+type BridgeReleaseArgs struct {
+	RemoteTx  common.Hash    //外部链上销毁事件所在的交易
+	LogIndex  uint64         //销毁事件在区块中的日志序号
+	Recipient common.Address //本链上的接收账号
+	Amount    *big.Int       //释放数量
+}
+
+//批量转账交易负载中的一笔转账
+type MultiSendItem struct {
+	Recipient common.Address //接收账号
+	Amount    *big.Int       //转账数量
+}
+
+//调整出块奖励计划的交易负载
+type RewardSchedule struct {
+	Reward         *big.Int //区块总奖励（单位为TinaUnit）
+	GasPoolPercent uint64   //放入股权Gas池的比例（百分比）
+}
+
 type StockRewards struct {
 	Timer  int64
 	Number uint64
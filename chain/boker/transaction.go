@@ -7,8 +7,8 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"encoding/base64"
+	"sync"
 
-	"errors"
 	"math/big"
 
 	"github.com/Tinachain/Tina/chain/accounts"
@@ -19,17 +19,12 @@ import (
 	"github.com/Tinachain/Tina/chain/eth"
 	"github.com/Tinachain/Tina/chain/internal/ethapi"
 	"github.com/Tinachain/Tina/chain/log"
-	"github.com/Tinachain/Tina/chain/params"
-)
-
-const (
-	defaultGas      = 90000
-	defaultGasPrice = 50 * params.Shannon
 )
 
 //Tina链的基础合约管理
 type BokerTransaction struct {
 	ethereum *eth.Ethereum
+	lock     sync.Mutex //保证检查交易池中的重复交易与提交交易之间不会插入其它系统交易
 }
 
 func NewTransaction(ethereum *eth.Ethereum) *BokerTransaction {
@@ -118,6 +113,9 @@ func (t *BokerTransaction) SubmitBokerTransaction(ctx context.Context,
 			Extra:      hexutil.Bytes(extra),
 		}
 
+		t.lock.Lock()
+		defer t.lock.Unlock()
+
 		//查找包含所请求签名者的钱包
 		account := accounts.Account{Address: args.From}
 
@@ -140,62 +138,36 @@ func (t *BokerTransaction) SubmitBokerTransaction(ctx context.Context,
 			"Name", args.Name,
 			"Extra", args.Extra)
 
-		var chainID *big.Int
-		var tx *types.Transaction
-
-		//根据交易类型进行区分
-		if protocol.SystemBase == txMajor || protocol.UserBase == txMajor {
+		//按统一的编码构造交易(与boker_buildSystemTx返回的未签名交易一致)
+		tx, err := ethapi.NewBokerTransaction(args.Major,
+			args.Minor,
+			(uint64)(*args.Nonce),
+			(common.Address)(*args.To),
+			(*big.Int)(args.Value),
+			args.Name,
+			args.Extra,
+			args.Encryption)
+		if err != nil {
 
-			tx = types.NewBaseTransaction(args.Major,
-				args.Minor,
-				(uint64)(*args.Nonce),
-				(common.Address)(*args.To),
-				(*big.Int)(args.Value),
-				[]byte(""))
-			if config := t.ethereum.ApiBackend.ChainConfig(); config.IsEIP155(t.ethereum.ApiBackend.CurrentBlock().Number()) {
+			log.Error("SubmitBokerTransaction txType Not Found", "major", txMajor)
+			return nil, err
+		}
 
-				chainID = config.ChainId
-			}
+		//交易池中已有相同的交易(RPC被快速重复调用)时不再以新的Nonce重复提交
+		if mode := t.ethereum.SystemTxDedup(); mode != eth.SystemTxDedupOff {
+			if pooled := t.ethereum.TxPool().FindDuplicate(from, tx); pooled != nil {
 
-		} else if protocol.Extra == txMajor {
-
-			tx = types.NewExtraTransaction(args.Major,
-				args.Minor,
-				(uint64)(*args.Nonce),
-				(common.Address)(*args.To),
-				(*big.Int)(args.Value),
-				new(big.Int).SetUint64(defaultGas),
-				new(big.Int).SetUint64(defaultGasPrice),
-				args.Name,
-				args.Extra,
-				args.Encryption)
-			if config := t.ethereum.ApiBackend.ChainConfig(); config.IsEIP155(t.ethereum.ApiBackend.CurrentBlock().Number()) {
-
-				chainID = config.ChainId
+				log.Warn("SubmitBokerTransaction duplicate pending", "mode", mode, "major", txMajor, "minor", txMinor, "from", from, "to", to, "pooled", pooled.Hash())
+				if mode == eth.SystemTxDedupReuse {
+					return pooled, nil
+				}
+				return nil, protocol.ErrDuplicateSystemTx
 			}
-			log.Info("SubmitBokerTransaction tx", "Major", tx.Major(), "Miner", tx.Minor(), "Extra", tx.Extra())
-
-		} else if protocol.Stock == txMajor {
-
-			tx = types.NewStockTransaction(args.Major,
-				args.Minor,
-				(uint64)(*args.Nonce),
-				(common.Address)(*args.To),
-				(*big.Int)(args.Value),
-				new(big.Int).SetUint64(defaultGas),
-				new(big.Int).SetUint64(defaultGasPrice),
-				args.Name,
-				args.Extra,
-				args.Encryption)
-			if config := t.ethereum.ApiBackend.ChainConfig(); config.IsEIP155(t.ethereum.ApiBackend.CurrentBlock().Number()) {
-
-				chainID = config.ChainId
-			}
-			log.Info("SubmitBokerTransaction tx", "Major", tx.Major(), "Miner", tx.Minor(), "Extra", tx.Extra())
-		} else {
+		}
+		var chainID *big.Int
+		if config := t.ethereum.ApiBackend.ChainConfig(); config.IsEIP155(t.ethereum.ApiBackend.CurrentBlock().Number()) {
 
-			log.Error("SubmitBokerTransaction txType Not Found")
-			return nil, errors.New("SubmitBokerTransaction txType Not Found")
+			chainID = config.ChainId
 		}
 
 		log.Info("SubmitBokerTransaction NewTransaction", "Value", tx.Value, "tx.Hash", tx.Hash().String())
@@ -22,6 +22,7 @@ import (
 
 	"github.com/Tinachain/Tina/chain/accounts"
 	"github.com/Tinachain/Tina/chain/accounts/keystore"
+	"github.com/Tinachain/Tina/chain/accounts/secretstore"
 	"github.com/Tinachain/Tina/chain/cmd/utils"
 	"github.com/Tinachain/Tina/chain/console"
 	"github.com/Tinachain/Tina/chain/crypto"
@@ -153,6 +154,19 @@ For non-interactive use the passphrase can be specified with the --password flag
 
 Since only one password can be given, only format update can be performed,
 changing your password is only possible interactively.
+`,
+			},
+			{
+				Name:      "sealpassword",
+				Usage:     "Store the coinbase password in a sealed file",
+				Action:    utils.MigrateFlags(accountSealPassword),
+				ArgsUsage: "<file>",
+				Description: `
+    geth account sealpassword <file>
+
+Encrypts the coinbase password with a separate passphrase and writes it to <file>.
+Start the node with --minerpassword <file> to load it; the passphrase is prompted
+at startup and the password is never written to the configuration or the logs.
 `,
 			},
 			{
@@ -205,6 +219,22 @@ func accountList(ctx *cli.Context) error {
 	return nil
 }
 
+//将挖矿账号的密码加密保存到文件中
+func accountSealPassword(ctx *cli.Context) error {
+
+	path := ctx.Args().First()
+	if len(path) == 0 {
+		utils.Fatalf("file must be given as argument")
+	}
+	password := getPassPhrase("Please enter the coinbase password to seal.", true, 0, nil)
+	passphrase := getPassPhrase("Please give a passphrase to unlock the sealed file at startup.", true, 0, nil)
+	if err := secretstore.WriteFile(path, password, passphrase); err != nil {
+		utils.Fatalf("Failed to write sealed password: %v", err)
+	}
+	fmt.Printf("Sealed password written to %s\n", path)
+	return nil
+}
+
 //尝试短时间内解锁指定帐户
 func unlockAccount(ctx *cli.Context, ks *keystore.KeyStore, address string, i int, passwords []string) (accounts.Account, string) {
 
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -9,6 +10,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/cmd/utils"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/console"
@@ -77,6 +79,24 @@ Requires a first argument of the file to write to.
 Optional second and third arguments control the first and
 last block to write. In this mode, the file will be appended
 if already existing.`,
+	}
+	checkpointCommand = cli.Command{
+		Action:    utils.MigrateFlags(importCheckpoint),
+		Name:      "checkpoint",
+		Usage:     "Bootstrap a node from an epoch state checkpoint",
+		ArgsUsage: "<filename>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.CacheFlag,
+			utils.CheckpointHashFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The checkpoint command initializes a database containing only the genesis block
+(run init first) from a state checkpoint exported at an epoch boundary. The
+checkpoint must be signed by the validators of its epoch, and match the block
+hash given by --checkpoint.hash if set. The node then syncs from the checkpoint
+block onwards.`,
 	}
 	copydbCommand = cli.Command{
 		Action:    utils.MigrateFlags(copyDb),
@@ -202,6 +222,8 @@ func importChain(ctx *cli.Context) error {
 		}
 	}
 
+	//停止链管理器，把内存中的状态写入磁盘
+	chain.Stop()
 	fmt.Printf("Import done in %v.\n\n", time.Since(start))
 
 	// Output pre-compaction stats mostly to see the import trashing
@@ -278,6 +300,36 @@ func exportChain(ctx *cli.Context) error {
 	return nil
 }
 
+//从周期检查点初始化只包含创世区块的数据库
+func importCheckpoint(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	var trusted common.Hash
+	if hash := ctx.GlobalString(utils.CheckpointHashFlag.Name); hash != "" {
+		if err := trusted.UnmarshalText([]byte(hash)); err != nil {
+			utils.Fatalf("Invalid trusted checkpoint hash: %v", err)
+		}
+	}
+	stack, _ := makeConfigNode(ctx)
+	chainDb := utils.MakeChainDatabase(ctx, stack)
+	defer chainDb.Close()
+
+	file, err := os.Open(ctx.Args().First())
+	if err != nil {
+		utils.Fatalf("Failed to open checkpoint: %v", err)
+	}
+	defer file.Close()
+
+	start := time.Now()
+	checkpoint, err := core.ImportCheckpoint(chainDb, bufio.NewReader(file), trusted, protocol.ConsensusSize)
+	if err != nil {
+		utils.Fatalf("Checkpoint import error: %v", err)
+	}
+	fmt.Printf("Imported checkpoint #%d [%x] in %v\n", checkpoint.Block.NumberU64(), checkpoint.Block.Hash(), time.Since(start))
+	return nil
+}
+
 func copyDb(ctx *cli.Context) error {
 	// Ensure we have a source chain directory to copy
 	if len(ctx.Args()) != 1 {
@@ -11,6 +11,7 @@ import (
 
 	"github.com/Tinachain/Tina/chain/accounts"
 	"github.com/Tinachain/Tina/chain/accounts/keystore"
+	"github.com/Tinachain/Tina/chain/accounts/secretstore"
 	"github.com/Tinachain/Tina/chain/cmd/utils"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/console"
@@ -60,6 +61,8 @@ var (
 		utils.TxPoolNoLocalsFlag,
 		utils.TxPoolJournalFlag,
 		utils.TxPoolRejournalFlag,
+		utils.TxPoolScheduledFlag,
+		utils.TxPoolRebroadcastFlag,
 		utils.TxPoolPriceLimitFlag,
 		utils.TxPoolPriceBumpFlag,
 		utils.TxPoolAccountSlotsFlag,
@@ -67,19 +70,39 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
+		utils.SystemTxDedupFlag,
+		utils.TxIpModeFlag,
+		utils.TxIpSaltFlag,
+		utils.RPCIpRedactionFlag,
 		utils.FastSyncFlag,
 		utils.LightModeFlag,
 		utils.SyncModeFlag,
+		utils.ArchiveFlag,
+		utils.ReadOnlyFlag,
 		utils.LightServFlag,
 		utils.LightPeersFlag,
+		utils.LightCheckpointFlag,
 		utils.LightKDFFlag,
 		utils.CacheFlag,
 		utils.TrieCacheGenFlag,
+		utils.TrieDirtyCacheFlag,
+		utils.DatabaseRepairFlag,
 		utils.ListenPortFlag,
 		utils.MaxPeersFlag,
 		utils.MaxPendingPeersFlag,
 		utils.ValidatorFlag,
 		utils.CoinbaseFlag,
+		utils.SigningKeyFlag,
+		utils.MinerPasswordFileFlag,
+		utils.MaxClockDriftFlag,
+		utils.RemoteSignerURLFlag,
+		utils.RemoteSignerCertFlag,
+		utils.RemoteSignerKeyFlag,
+		utils.RemoteSignerCAFlag,
+		utils.BridgeURLFlag,
+		utils.BridgeContractFlag,
+		utils.BridgeConfirmationsFlag,
+		utils.BridgeFromBlockFlag,
 		utils.GasPriceFlag,
 		utils.MiningEnabledFlag,
 		utils.TargetGasLimitFlag,
@@ -90,10 +113,18 @@ var (
 		utils.NodeKeyFileFlag,
 		utils.NodeKeyHexFlag,
 		utils.VMEnableDebugFlag,
+		utils.InternalTxIndexFlag,
+		utils.TokenIndexFlag,
+		utils.NameIndexFlag,
+		utils.RichListFlag,
+		utils.CheckpointDirFlag,
 		utils.NetworkIdFlag,
 		utils.RPCCORSDomainFlag,
+		utils.RPCVirtualHostsFlag,
 		utils.EthStatsURLFlag,
 		utils.MetricsEnabledFlag,
+		utils.MetricsListenAddrFlag,
+		utils.MetricsPortFlag,
 		utils.NoCompactionFlag,
 		utils.GpoBlocksFlag,
 		utils.GpoPercentileFlag,
@@ -106,13 +137,29 @@ var (
 		utils.RPCListenAddrFlag,
 		utils.RPCPortFlag,
 		utils.RPCApiFlag,
+		utils.RPCReadTimeoutFlag,
+		utils.RPCWriteTimeoutFlag,
+		utils.RPCIdleTimeoutFlag,
 		utils.WSEnabledFlag,
 		utils.WSListenAddrFlag,
 		utils.WSPortFlag,
 		utils.WSApiFlag,
 		utils.WSAllowedOriginsFlag,
+		utils.WSMaxSubscriptionsFlag,
+		utils.WSMaxBufferedFlag,
+		utils.WSHandshakeTimeoutFlag,
 		utils.IPCDisabledFlag,
 		utils.IPCPathFlag,
+		utils.IPCOnlyPrivilegedFlag,
+		utils.AuthSecretFlag,
+		utils.AuthModulesFlag,
+		utils.AuditLogFlag,
+		utils.EthCompatFlag,
+		utils.AllowUnprotectedTxsFlag,
+		utils.RPCTimeoutFlag,
+		utils.RPCMethodTimeoutsFlag,
+		utils.RPCCacheFlag,
+		utils.VirtualPendingFlag,
 	}
 
 	whisperFlags = []cli.Flag{
@@ -141,6 +188,7 @@ func init() {
 		initCommand,   //初始化指令
 		importCommand, //从一个文件导入链
 		exportCommand, //导出链到指定文件
+		checkpointCommand,
 		copydbCommand,
 		removedbCommand,
 		dumpCommand,
@@ -302,6 +350,17 @@ func startNode(ctx *cli.Context, stack *node.Node) {
 		utils.Fatalf("ethereum service is nil")
 	}
 
+	//从加密的密码文件中加载挖矿账号的密码
+	if path := ctx.GlobalString(utils.MinerPasswordFileFlag.Name); path != "" {
+		passphrase := getPassPhrase("Unlocking sealed miner password "+path, false, 0, nil)
+		secret, err := secretstore.ReadFile(path, passphrase)
+		if err != nil {
+			utils.Fatalf("Failed to unlock sealed miner password: %v", err)
+		}
+		ethereum.SetPassword(secret.Open())
+		log.Info("Loaded sealed miner password", "file", path)
+	}
+
 	//由于设置的默克尔树会发生变化，因此这里不能使用第一个区块的信息 fxh7622
 	block := ethereum.BlockChain().CurrentBlock()
 	bokerChain := boker.New(ethereum.ChainDb())
@@ -54,10 +54,13 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.NoUSBFlag,
 			utils.NetworkIdFlag,
 			utils.SyncModeFlag,
+			utils.ArchiveFlag,
+			utils.ReadOnlyFlag,
 			utils.EthStatsURLFlag,
 			utils.IdentityFlag,
 			utils.LightServFlag,
 			utils.LightPeersFlag,
+			utils.LightCheckpointFlag,
 			utils.LightKDFFlag,
 		},
 	},
@@ -77,6 +80,8 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TxPoolNoLocalsFlag,
 			utils.TxPoolJournalFlag,
 			utils.TxPoolRejournalFlag,
+			utils.TxPoolScheduledFlag,
+			utils.TxPoolRebroadcastFlag,
 			utils.TxPoolPriceLimitFlag,
 			utils.TxPoolPriceBumpFlag,
 			utils.TxPoolAccountSlotsFlag,
@@ -84,6 +89,10 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TxPoolAccountQueueFlag,
 			utils.TxPoolGlobalQueueFlag,
 			utils.TxPoolLifetimeFlag,
+			utils.SystemTxDedupFlag,
+			utils.TxIpModeFlag,
+			utils.TxIpSaltFlag,
+			utils.RPCIpRedactionFlag,
 		},
 	},
 	{
@@ -91,6 +100,8 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.CacheFlag,
 			utils.TrieCacheGenFlag,
+			utils.TrieDirtyCacheFlag,
+			utils.DatabaseRepairFlag,
 		},
 	},
 	{
@@ -107,14 +118,31 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.RPCListenAddrFlag,
 			utils.RPCPortFlag,
 			utils.RPCApiFlag,
+			utils.RPCReadTimeoutFlag,
+			utils.RPCWriteTimeoutFlag,
+			utils.RPCIdleTimeoutFlag,
 			utils.WSEnabledFlag,
 			utils.WSListenAddrFlag,
 			utils.WSPortFlag,
 			utils.WSApiFlag,
 			utils.WSAllowedOriginsFlag,
+			utils.WSMaxSubscriptionsFlag,
+			utils.WSMaxBufferedFlag,
+			utils.WSHandshakeTimeoutFlag,
 			utils.IPCDisabledFlag,
 			utils.IPCPathFlag,
+			utils.IPCOnlyPrivilegedFlag,
+			utils.AuthSecretFlag,
+			utils.AuthModulesFlag,
+			utils.AuditLogFlag,
+			utils.EthCompatFlag,
+			utils.AllowUnprotectedTxsFlag,
+			utils.RPCTimeoutFlag,
+			utils.RPCMethodTimeoutsFlag,
+			utils.RPCCacheFlag,
+			utils.VirtualPendingFlag,
 			utils.RPCCORSDomainFlag,
+			utils.RPCVirtualHostsFlag,
 			utils.JSpathFlag,
 			utils.ExecFlag,
 			utils.PreloadJSFlag,
@@ -143,11 +171,27 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.MiningEnabledFlag,
 			utils.ValidatorFlag,
 			utils.CoinbaseFlag,
+			utils.SigningKeyFlag,
+			utils.MinerPasswordFileFlag,
+			utils.MaxClockDriftFlag,
+			utils.RemoteSignerURLFlag,
+			utils.RemoteSignerCertFlag,
+			utils.RemoteSignerKeyFlag,
+			utils.RemoteSignerCAFlag,
 			utils.TargetGasLimitFlag,
 			utils.GasPriceFlag,
 			utils.ExtraDataFlag,
 		},
 	},
+	{
+		Name: "BRIDGE RELAYER",
+		Flags: []cli.Flag{
+			utils.BridgeURLFlag,
+			utils.BridgeContractFlag,
+			utils.BridgeConfirmationsFlag,
+			utils.BridgeFromBlockFlag,
+		},
+	},
 	{
 		Name: "GAS PRICE ORACLE",
 		Flags: []cli.Flag{
@@ -159,6 +203,11 @@ var AppHelpFlagGroups = []flagGroup{
 		Name: "VIRTUAL MACHINE",
 		Flags: []cli.Flag{
 			utils.VMEnableDebugFlag,
+			utils.InternalTxIndexFlag,
+			utils.TokenIndexFlag,
+			utils.NameIndexFlag,
+			utils.RichListFlag,
+			utils.CheckpointDirFlag,
 		},
 	},
 	{
@@ -26,11 +26,15 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Tinachain/Tina/chain/accounts"
 	"github.com/Tinachain/Tina/chain/accounts/keystore"
+	"github.com/Tinachain/Tina/chain/boker/bridge"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/consensus/dpos"
+	"github.com/Tinachain/Tina/chain/consensus/dpos/remotesigner"
 	"github.com/Tinachain/Tina/chain/core"
 	"github.com/Tinachain/Tina/chain/core/state"
 	"github.com/Tinachain/Tina/chain/core/vm"
@@ -150,6 +154,14 @@ var (
 		Usage: `Blockchain sync mode ("fast", "full", or "light")`,
 		Value: &defaultSyncMode,
 	}
+	ArchiveFlag = cli.BoolFlag{
+		Name:  "archive",
+		Usage: "Retain the state of every historical block (requires full sync from genesis)",
+	}
+	ReadOnlyFlag = cli.BoolFlag{
+		Name:  "readonly",
+		Usage: "Run as a read-only replica: follow the chain without mining or accepting transactions",
+	}
 
 	LightServFlag = cli.IntFlag{
 		Name:  "lightserv",
@@ -161,6 +173,10 @@ var (
 		Usage: "Maximum number of LES client peers",
 		Value: 20,
 	}
+	LightCheckpointFlag = cli.StringFlag{
+		Name:  "lightcheckpoint",
+		Usage: "Trusted light client checkpoint to start syncing from (<index>:<sectionHead>:<chtRoot>:<bloomTrieRoot>)",
+	}
 	LightKDFFlag = cli.BoolFlag{
 		Name:  "lightkdf",
 		Usage: "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
@@ -200,6 +216,16 @@ var (
 		Usage: "Time interval to regenerate the local transaction journal",
 		Value: core.DefaultTxPoolConfig.Rejournal,
 	}
+	TxPoolScheduledFlag = cli.StringFlag{
+		Name:  "txpool.scheduled",
+		Usage: "Disk file for scheduled transactions to survive node restarts",
+		Value: core.DefaultTxPoolConfig.Scheduled,
+	}
+	TxPoolRebroadcastFlag = cli.DurationFlag{
+		Name:  "txpool.rebroadcast",
+		Usage: "Time interval to re-announce unmined local transactions to all peers (0 = disabled)",
+		Value: core.DefaultTxPoolConfig.Rebroadcast,
+	}
 	TxPoolPriceLimitFlag = cli.Uint64Flag{
 		Name:  "txpool.pricelimit",
 		Usage: "Minimum gas price limit to enforce for acceptance into the pool",
@@ -235,6 +261,25 @@ var (
 		Usage: "Maximum amount of time non-executable transaction are queued",
 		Value: eth.DefaultConfig.TxPool.Lifetime,
 	}
+	SystemTxDedupFlag = cli.StringFlag{
+		Name:  "systemtx.dedup",
+		Usage: "Handling of node-submitted system transactions already pending with the same type, sender, recipient and payload (off, reject, reuse)",
+		Value: eth.DefaultConfig.SystemTxDedup,
+	}
+	TxIpModeFlag = cli.StringFlag{
+		Name:  "txip.mode",
+		Usage: "How the node's IP is written into the ip field of submitted transactions (full, subnet, hash, off)",
+		Value: eth.DefaultConfig.TxIpMode,
+	}
+	TxIpSaltFlag = cli.StringFlag{
+		Name:  "txip.salt",
+		Usage: "Salt used when ip fields are hashed",
+	}
+	RPCIpRedactionFlag = cli.StringFlag{
+		Name:  "txip.rpcredaction",
+		Usage: "How the ip field of transactions is shown in RPC output (full, subnet, hash, off)",
+		Value: eth.DefaultConfig.RPCIpRedaction,
+	}
 	// Performance tuning settings
 	CacheFlag = cli.IntFlag{
 		Name:  "cache",
@@ -246,6 +291,15 @@ var (
 		Usage: "Number of trie node generations to keep in memory",
 		Value: int(state.MaxTrieCacheGen),
 	}
+	TrieDirtyCacheFlag = cli.IntFlag{
+		Name:  "trie.dirtycache",
+		Usage: "Megabytes of memory for committed state trie nodes before they are flushed to disk in the background",
+		Value: eth.DefaultConfig.TrieDirtyCache,
+	}
+	DatabaseRepairFlag = cli.BoolFlag{
+		Name:  "db.repair",
+		Usage: "Truncate a damaged chain database to the last verifiable block at startup and resync from there",
+	}
 	// Miner settings
 	MiningEnabledFlag = cli.BoolFlag{
 		Name:  "mine",
@@ -266,6 +320,47 @@ var (
 		Usage: "Public address for block mining rewards (default = first account created)",
 		Value: "0",
 	}
+	SigningKeyFlag = cli.StringFlag{
+		Name:  "signingkey",
+		Usage: "Account used to sign produced blocks when the validator has registered a separate signing key (default = coinbase)",
+	}
+	MinerPasswordFileFlag = cli.StringFlag{
+		Name:  "minerpassword",
+		Usage: "Sealed file holding the coinbase password used for system transactions (created with 'geth account sealpassword')",
+	}
+	RemoteSignerURLFlag = cli.StringFlag{
+		Name:  "signer.url",
+		Usage: "URL of a remote signing service used to seal blocks instead of a local unlocked account",
+	}
+	RemoteSignerCertFlag = cli.StringFlag{
+		Name:  "signer.cert",
+		Usage: "Client certificate presented to the remote signer (mutual TLS)",
+	}
+	RemoteSignerKeyFlag = cli.StringFlag{
+		Name:  "signer.key",
+		Usage: "Private key of the remote signer client certificate",
+	}
+	RemoteSignerCAFlag = cli.StringFlag{
+		Name:  "signer.ca",
+		Usage: "CA certificate used to verify the remote signer (default = system roots)",
+	}
+	BridgeURLFlag = cli.StringFlag{
+		Name:  "bridge.url",
+		Usage: "RPC endpoint of the remote Ethereum chain watched by the bridge relayer",
+	}
+	BridgeContractFlag = cli.StringFlag{
+		Name:  "bridge.contract",
+		Usage: "Address of the bridge contract on the remote chain emitting BridgeBurn events",
+	}
+	BridgeConfirmationsFlag = cli.Uint64Flag{
+		Name:  "bridge.confirmations",
+		Usage: "Number of remote blocks a BridgeBurn event must be buried under before it is relayed",
+		Value: 12,
+	}
+	BridgeFromBlockFlag = cli.Uint64Flag{
+		Name:  "bridge.from",
+		Usage: "Remote block to start scanning from when the relayer runs for the first time",
+	}
 	GasPriceFlag = BigFlag{
 		Name:  "gasprice",
 		Usage: "Minimal gas price to accept for mining a transactions",
@@ -291,6 +386,35 @@ var (
 		Name:  "vmdebug",
 		Usage: "Record information useful for VM and contract debugging",
 	}
+	InternalTxIndexFlag = cli.BoolFlag{
+		Name:  "internaltxindex",
+		Usage: "Index internal transactions produced by contract execution of imported blocks",
+	}
+	TokenIndexFlag = cli.BoolFlag{
+		Name:  "tokenindex",
+		Usage: "Index ERC-20/ERC-721 Transfer events of imported blocks",
+	}
+	NameIndexFlag = cli.BoolFlag{
+		Name:  "nameindex",
+		Usage: "Index transactions of imported blocks by their name field",
+	}
+	RichListFlag = cli.IntFlag{
+		Name:  "richlist",
+		Usage: "Number of top balances ranked at each imported block (0 = disabled)",
+	}
+	CheckpointDirFlag = cli.StringFlag{
+		Name:  "checkpoint.dir",
+		Usage: "Directory within the datadir to export a signed state checkpoint to at each epoch boundary (empty = disabled)",
+	}
+	MaxClockDriftFlag = cli.DurationFlag{
+		Name:  "maxclockdrift",
+		Usage: "Maximum estimated system clock drift (against NTP and validator heartbeats) to still seal blocks at (0 = unlimited)",
+		Value: eth.DefaultConfig.MaxClockDrift,
+	}
+	CheckpointHashFlag = cli.StringFlag{
+		Name:  "checkpoint.hash",
+		Usage: "Trusted block hash the imported state checkpoint must match",
+	}
 	// Logging and debug settings
 	EthStatsURLFlag = cli.StringFlag{
 		Name:  "ethstats",
@@ -300,6 +424,15 @@ var (
 		Name:  metrics.MetricsEnabledFlag,
 		Usage: "Enable metrics collection and reporting",
 	}
+	MetricsListenAddrFlag = cli.StringFlag{
+		Name:  "metricsaddr",
+		Usage: "Metrics HTTP server listening interface (Prometheus format under /metrics)",
+	}
+	MetricsPortFlag = cli.IntFlag{
+		Name:  "metricsport",
+		Usage: "Metrics HTTP server listening port",
+		Value: node.DefaultMetricsPort,
+	}
 	NoCompactionFlag = cli.BoolFlag{
 		Name:  "nocompaction",
 		Usage: "Disables db compaction after import",
@@ -324,11 +457,31 @@ var (
 		Usage: "Comma separated list of domains from which to accept cross origin requests (browser enforced)",
 		Value: "",
 	}
+	RPCVirtualHostsFlag = cli.StringFlag{
+		Name:  "rpcvhosts",
+		Usage: "Comma separated list of virtual hostnames from which to accept requests (server enforced). Accepts '*' wildcard.",
+		Value: strings.Join(node.DefaultConfig.HTTPVirtualHosts, ","),
+	}
 	RPCApiFlag = cli.StringFlag{
 		Name:  "rpcapi",
 		Usage: "API's offered over the HTTP-RPC interface",
 		Value: "",
 	}
+	RPCReadTimeoutFlag = cli.DurationFlag{
+		Name:  "rpcreadtimeout",
+		Usage: "Maximum duration for reading an HTTP-RPC request (0 = unlimited)",
+		Value: node.DefaultConfig.HTTPTimeouts.ReadTimeout,
+	}
+	RPCWriteTimeoutFlag = cli.DurationFlag{
+		Name:  "rpcwritetimeout",
+		Usage: "Maximum duration for writing an HTTP-RPC response (0 = unlimited)",
+		Value: node.DefaultConfig.HTTPTimeouts.WriteTimeout,
+	}
+	RPCIdleTimeoutFlag = cli.DurationFlag{
+		Name:  "rpcidletimeout",
+		Usage: "Maximum duration an idle HTTP-RPC keep-alive connection is kept open (0 = read timeout)",
+		Value: node.DefaultConfig.HTTPTimeouts.IdleTimeout,
+	}
 	IPCDisabledFlag = cli.BoolFlag{
 		Name:  "ipcdisable",
 		Usage: "Disable the IPC-RPC server",
@@ -337,6 +490,51 @@ var (
 		Name:  "ipcpath",
 		Usage: "Filename for IPC socket/pipe within the datadir (explicit paths escape it)",
 	}
+	IPCOnlyPrivilegedFlag = cli.BoolFlag{
+		Name:  "ipconlyadmin",
+		Usage: "Restrict the admin, debug and personal API's to the IPC-RPC interface",
+	}
+	AuthSecretFlag = cli.StringFlag{
+		Name:  "authsecret",
+		Usage: "File within the datadir holding the hex secret verifying HTTP/WS RPC tokens (generated if missing, enables authentication, tokens must be issued within 60s)",
+	}
+	AuthModulesFlag = cli.StringFlag{
+		Name:  "authapi",
+		Usage: "API's requiring a valid token over the HTTP-RPC and WS-RPC interfaces",
+		Value: strings.Join(node.DefaultConfig.AuthModules, ","),
+	}
+	AuditLogFlag = cli.StringFlag{
+		Name:  "auditlog",
+		Usage: "Filename of the privileged RPC call audit log within the datadir (explicit paths escape it, empty disables)",
+		Value: node.DefaultConfig.AuditLog,
+	}
+	EthCompatFlag = cli.BoolFlag{
+		Name:  "ethcompat",
+		Usage: "Emit strictly standard eth_* schemas including the uncle fields (Tina extensions stay available in the boker namespace)",
+	}
+	AllowUnprotectedTxsFlag = cli.BoolFlag{
+		Name:  "allowunprotectedtxs",
+		Usage: "Accept transactions without a chain id over RPC after EIP155 (they can be replayed on other chains)",
+	}
+	RPCTimeoutFlag = cli.DurationFlag{
+		Name:  "rpctimeout",
+		Usage: "Default deadline of block, call, log and trace RPC requests (0 = no deadline)",
+		Value: eth.DefaultConfig.RPCTimeout,
+	}
+	RPCMethodTimeoutsFlag = cli.StringFlag{
+		Name:  "rpcmethodtimeouts",
+		Usage: "Per-method RPC deadlines overriding the default (e.g. eth_call=5s,eth_getLogs=2m)",
+		Value: "",
+	}
+	RPCCacheFlag = cli.IntFlag{
+		Name:  "rpccache",
+		Usage: "Number of immutable RPC responses (finalized blocks, receipts and code) to cache (0 = disabled)",
+		Value: eth.DefaultConfig.RPCCacheSize,
+	}
+	VirtualPendingFlag = cli.BoolFlag{
+		Name:  "virtualpending",
+		Usage: "Answer pending-tagged RPC queries of a non-mining node from a speculative block assembled from the transaction pool",
+	}
 	WSEnabledFlag = cli.BoolFlag{
 		Name:  "ws",
 		Usage: "Enable the WS-RPC server",
@@ -361,6 +559,21 @@ var (
 		Usage: "Origins from which to accept websockets requests",
 		Value: "",
 	}
+	WSMaxSubscriptionsFlag = cli.IntFlag{
+		Name:  "wsmaxsubs",
+		Usage: "Maximum number of subscriptions per WS-RPC connection (0 = unlimited)",
+		Value: node.DefaultWSMaxSubscriptions,
+	}
+	WSMaxBufferedFlag = cli.IntFlag{
+		Name:  "wsmaxbuffered",
+		Usage: "Maximum number of queued notifications per WS-RPC connection before disconnecting it",
+		Value: node.DefaultWSMaxBufferedNotifications,
+	}
+	WSHandshakeTimeoutFlag = cli.DurationFlag{
+		Name:  "wshandshaketimeout",
+		Usage: "Maximum duration for completing a WS-RPC handshake (0 = unlimited)",
+		Value: node.DefaultWSHandshakeTimeout,
+	}
 	ExecFlag = cli.StringFlag{
 		Name:  "exec",
 		Usage: "Execute JavaScript statement",
@@ -607,9 +820,21 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(RPCCORSDomainFlag.Name) {
 		cfg.HTTPCors = splitAndTrim(ctx.GlobalString(RPCCORSDomainFlag.Name))
 	}
+	if ctx.GlobalIsSet(RPCVirtualHostsFlag.Name) {
+		cfg.HTTPVirtualHosts = splitAndTrim(ctx.GlobalString(RPCVirtualHostsFlag.Name))
+	}
 	if ctx.GlobalIsSet(RPCApiFlag.Name) {
 		cfg.HTTPModules = splitAndTrim(ctx.GlobalString(RPCApiFlag.Name))
 	}
+	if ctx.GlobalIsSet(RPCReadTimeoutFlag.Name) {
+		cfg.HTTPTimeouts.ReadTimeout = ctx.GlobalDuration(RPCReadTimeoutFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCWriteTimeoutFlag.Name) {
+		cfg.HTTPTimeouts.WriteTimeout = ctx.GlobalDuration(RPCWriteTimeoutFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCIdleTimeoutFlag.Name) {
+		cfg.HTTPTimeouts.IdleTimeout = ctx.GlobalDuration(RPCIdleTimeoutFlag.Name)
+	}
 }
 
 // setWS creates the WebSocket RPC listener interface string from the set
@@ -631,6 +856,26 @@ func setWS(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(WSApiFlag.Name) {
 		cfg.WSModules = splitAndTrim(ctx.GlobalString(WSApiFlag.Name))
 	}
+	if ctx.GlobalIsSet(WSMaxSubscriptionsFlag.Name) {
+		cfg.WSMaxSubscriptions = ctx.GlobalInt(WSMaxSubscriptionsFlag.Name)
+	}
+	if ctx.GlobalIsSet(WSMaxBufferedFlag.Name) {
+		cfg.WSMaxBufferedNotifications = ctx.GlobalInt(WSMaxBufferedFlag.Name)
+	}
+	if ctx.GlobalIsSet(WSHandshakeTimeoutFlag.Name) {
+		cfg.WSHandshakeTimeout = ctx.GlobalDuration(WSHandshakeTimeoutFlag.Name)
+	}
+}
+
+// setMetrics creates the metrics HTTP endpoint configuration from the set command
+// line flags. The endpoint is only exposed if a listening interface is given.
+func setMetrics(ctx *cli.Context, cfg *node.Config) {
+	if ctx.GlobalIsSet(MetricsListenAddrFlag.Name) {
+		cfg.MetricsHost = ctx.GlobalString(MetricsListenAddrFlag.Name)
+	}
+	if ctx.GlobalIsSet(MetricsPortFlag.Name) {
+		cfg.MetricsPort = ctx.GlobalInt(MetricsPortFlag.Name)
+	}
 }
 
 // setIPC creates an IPC path configuration from the set command line flags,
@@ -712,6 +957,15 @@ func setCoinbase(ctx *cli.Context, ks *keystore.KeyStore, cfg *eth.Config) {
 			log.Error("Option %q: %v", CoinbaseFlag.Name, err)
 		}
 		cfg.Coinbase = account.Address
+	}
+	if ctx.GlobalIsSet(SigningKeyFlag.Name) {
+		account, err := MakeAddress(ks, ctx.GlobalString(SigningKeyFlag.Name))
+		if err != nil {
+			Fatalf("Option %q: %v", SigningKeyFlag.Name, err)
+		}
+		cfg.SigningKey = account.Address
+	}
+	if ctx.GlobalIsSet(CoinbaseFlag.Name) {
 		return
 	}
 	accounts := ks.Accounts()
@@ -724,6 +978,34 @@ func setCoinbase(ctx *cli.Context, ks *keystore.KeyStore, cfg *eth.Config) {
 	}
 }
 
+//根据--signer.*标志设置远程签名服务
+func setRemoteSigner(ctx *cli.Context, cfg *eth.Config) {
+	if ctx.GlobalIsSet(RemoteSignerURLFlag.Name) {
+		cfg.RemoteSigner = &remotesigner.Config{
+			URL:      ctx.GlobalString(RemoteSignerURLFlag.Name),
+			CertFile: ctx.GlobalString(RemoteSignerCertFlag.Name),
+			KeyFile:  ctx.GlobalString(RemoteSignerKeyFlag.Name),
+			CAFile:   ctx.GlobalString(RemoteSignerCAFlag.Name),
+		}
+	}
+}
+
+//根据--bridge.*标志设置跨链桥中继服务
+func setBridge(ctx *cli.Context, cfg *eth.Config) {
+	if ctx.GlobalIsSet(BridgeURLFlag.Name) {
+		contract := ctx.GlobalString(BridgeContractFlag.Name)
+		if !common.IsHexAddress(contract) {
+			Fatalf("Invalid bridge contract address %q", contract)
+		}
+		cfg.Bridge = &bridge.Config{
+			URL:           ctx.GlobalString(BridgeURLFlag.Name),
+			Contract:      common.HexToAddress(contract),
+			Confirmations: ctx.GlobalUint64(BridgeConfirmationsFlag.Name),
+			FromBlock:     ctx.GlobalUint64(BridgeFromBlockFlag.Name),
+		}
+	}
+}
+
 //从全局--password标志指定的文件中读取密码行
 func MakePasswordList(ctx *cli.Context) []string {
 
@@ -787,6 +1069,19 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	setIPC(ctx, cfg)
 	setHTTP(ctx, cfg)
 	setWS(ctx, cfg)
+	setMetrics(ctx, cfg)
+	if ctx.GlobalBool(IPCOnlyPrivilegedFlag.Name) {
+		cfg.IPCOnlyPrivileged = true
+	}
+	if ctx.GlobalIsSet(AuthSecretFlag.Name) {
+		cfg.AuthSecret = ctx.GlobalString(AuthSecretFlag.Name)
+	}
+	if ctx.GlobalIsSet(AuthModulesFlag.Name) {
+		cfg.AuthModules = splitAndTrim(ctx.GlobalString(AuthModulesFlag.Name))
+	}
+	if ctx.GlobalIsSet(AuditLogFlag.Name) {
+		cfg.AuditLog = ctx.GlobalString(AuditLogFlag.Name)
+	}
 	setNodeUserIdent(ctx, cfg)
 
 	switch {
@@ -824,6 +1119,12 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolRejournalFlag.Name) {
 		cfg.Rejournal = ctx.GlobalDuration(TxPoolRejournalFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolScheduledFlag.Name) {
+		cfg.Scheduled = ctx.GlobalString(TxPoolScheduledFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolRebroadcastFlag.Name) {
+		cfg.Rebroadcast = ctx.GlobalDuration(TxPoolRebroadcastFlag.Name)
+	}
 	if ctx.GlobalIsSet(TxPoolPriceLimitFlag.Name) {
 		cfg.PriceLimit = ctx.GlobalUint64(TxPoolPriceLimitFlag.Name)
 	}
@@ -878,6 +1179,8 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
 	//setValidator(ctx, ks, cfg)
 	setCoinbase(ctx, ks, cfg)
+	setRemoteSigner(ctx, cfg)
+	setBridge(ctx, cfg)
 	setGPO(ctx, &cfg.GPO)
 	setTxPool(ctx, &cfg.TxPool)
 
@@ -895,6 +1198,13 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(LightPeersFlag.Name) {
 		cfg.LightPeers = ctx.GlobalInt(LightPeersFlag.Name)
 	}
+	if ctx.GlobalIsSet(LightCheckpointFlag.Name) {
+		checkpoint, err := params.ParseTrustedCheckpoint(ctx.GlobalString(LightCheckpointFlag.Name))
+		if err != nil {
+			Fatalf("Option %q: %v", LightCheckpointFlag.Name, err)
+		}
+		cfg.Checkpoint = checkpoint
+	}
 	if ctx.GlobalIsSet(NetworkIdFlag.Name) {
 		cfg.NetworkId = ctx.GlobalUint64(NetworkIdFlag.Name)
 	}
@@ -903,6 +1213,24 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 		cfg.DatabaseCache = ctx.GlobalInt(CacheFlag.Name)
 	}
 	cfg.DatabaseHandles = makeDatabaseHandles()
+	if ctx.GlobalIsSet(DatabaseRepairFlag.Name) {
+		cfg.DatabaseRepair = ctx.GlobalBool(DatabaseRepairFlag.Name)
+	}
+	if ctx.GlobalIsSet(ReadOnlyFlag.Name) {
+		cfg.ReadOnly = ctx.GlobalBool(ReadOnlyFlag.Name)
+	}
+	if ctx.GlobalIsSet(ArchiveFlag.Name) {
+		cfg.Archive = ctx.GlobalBool(ArchiveFlag.Name)
+	}
+	if cfg.Archive && cfg.SyncMode != downloader.FullSync {
+		Fatalf("--%s requires full sync, have %v", ArchiveFlag.Name, cfg.SyncMode)
+	}
+	if ctx.GlobalIsSet(TrieDirtyCacheFlag.Name) {
+		cfg.TrieDirtyCache = ctx.GlobalInt(TrieDirtyCacheFlag.Name)
+	}
+	if cfg.TrieDirtyCache <= 0 {
+		Fatalf("--%s must be positive, have %d", TrieDirtyCacheFlag.Name, cfg.TrieDirtyCache)
+	}
 
 	if ctx.GlobalIsSet(DocRootFlag.Name) {
 		cfg.DocRoot = ctx.GlobalString(DocRootFlag.Name)
@@ -917,6 +1245,87 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 		// TODO(fjl): force-enable this in --dev mode
 		cfg.EnablePreimageRecording = ctx.GlobalBool(VMEnableDebugFlag.Name)
 	}
+	if ctx.GlobalIsSet(InternalTxIndexFlag.Name) {
+		cfg.InternalTxIndex = ctx.GlobalBool(InternalTxIndexFlag.Name)
+	}
+	if ctx.GlobalIsSet(TokenIndexFlag.Name) {
+		cfg.TokenIndex = ctx.GlobalBool(TokenIndexFlag.Name)
+	}
+	if ctx.GlobalIsSet(NameIndexFlag.Name) {
+		cfg.NameIndex = ctx.GlobalBool(NameIndexFlag.Name)
+	}
+	if ctx.GlobalIsSet(RichListFlag.Name) {
+		cfg.RichListSize = ctx.GlobalInt(RichListFlag.Name)
+	}
+	if ctx.GlobalIsSet(CheckpointDirFlag.Name) {
+		cfg.CheckpointDir = ctx.GlobalString(CheckpointDirFlag.Name)
+	}
+	if ctx.GlobalIsSet(MaxClockDriftFlag.Name) {
+		cfg.MaxClockDrift = ctx.GlobalDuration(MaxClockDriftFlag.Name)
+	}
+	if ctx.GlobalIsSet(SystemTxDedupFlag.Name) {
+		switch mode := ctx.GlobalString(SystemTxDedupFlag.Name); mode {
+		case eth.SystemTxDedupOff, eth.SystemTxDedupReject, eth.SystemTxDedupReuse:
+			cfg.SystemTxDedup = mode
+		default:
+			Fatalf("Invalid system transaction dedup mode %q (off, reject or reuse)", mode)
+		}
+	}
+	if ctx.GlobalIsSet(TxIpModeFlag.Name) {
+		cfg.TxIpMode = ctx.GlobalString(TxIpModeFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxIpSaltFlag.Name) {
+		cfg.TxIpSalt = ctx.GlobalString(TxIpSaltFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCIpRedactionFlag.Name) {
+		cfg.RPCIpRedaction = ctx.GlobalString(RPCIpRedactionFlag.Name)
+	}
+	for _, mode := range []string{cfg.TxIpMode, cfg.RPCIpRedaction} {
+		if err := protocol.CheckIpMode(mode); err != nil {
+			Fatalf("%v", err)
+		}
+	}
+	if ctx.GlobalIsSet(EthCompatFlag.Name) {
+		cfg.EthCompatible = ctx.GlobalBool(EthCompatFlag.Name)
+	}
+	if ctx.GlobalIsSet(AllowUnprotectedTxsFlag.Name) {
+		cfg.AllowUnprotectedTxs = ctx.GlobalBool(AllowUnprotectedTxsFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCTimeoutFlag.Name) {
+		cfg.RPCTimeout = ctx.GlobalDuration(RPCTimeoutFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCMethodTimeoutsFlag.Name) {
+		cfg.RPCTimeouts = parseMethodTimeouts(ctx.GlobalString(RPCMethodTimeoutsFlag.Name), cfg.RPCTimeouts)
+	}
+	if ctx.GlobalIsSet(RPCCacheFlag.Name) {
+		cfg.RPCCacheSize = ctx.GlobalInt(RPCCacheFlag.Name)
+	}
+	if ctx.GlobalIsSet(VirtualPendingFlag.Name) {
+		cfg.VirtualPending = ctx.GlobalBool(VirtualPendingFlag.Name)
+	}
+}
+
+//解析method=duration列表，覆盖已有的按方法设置的执行期限
+func parseMethodTimeouts(input string, base map[string]time.Duration) map[string]time.Duration {
+	timeouts := make(map[string]time.Duration)
+	for method, timeout := range base {
+		timeouts[method] = timeout
+	}
+	for _, entry := range strings.Split(input, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			Fatalf("Option %q: invalid entry %q, want method=duration", RPCMethodTimeoutsFlag.Name, entry)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil {
+			Fatalf("Option %q: %v", RPCMethodTimeoutsFlag.Name, err)
+		}
+		timeouts[strings.TrimSpace(parts[0])] = timeout
+	}
+	return timeouts
 }
 
 // SetDashboardConfig applies dashboard related command line flags to the config.
@@ -1031,6 +1440,7 @@ func MakeChain(ctx *cli.Context, stack *node.Node) (chain *core.BlockChain, chai
 	if err != nil {
 		Fatalf("Can't create BlockChain: %v", err)
 	}
+	chain.SetTrieCache(ctx.GlobalInt(TrieDirtyCacheFlag.Name), ctx.GlobalBool(ArchiveFlag.Name))
 	chain.SetBoker(ethereum.Boker())
 	return chain, chainDb
 }
@@ -3,6 +3,7 @@ package dpos
 import (
 	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
 	"github.com/Tinachain/Tina/chain/consensus"
 	"github.com/Tinachain/Tina/chain/core/types"
 	"github.com/Tinachain/Tina/chain/rpc"
@@ -17,8 +18,24 @@ type API struct {
 	dpos  *Dpos
 }
 
-// GetValidators retrieves the list of the validators at specified block
-func (api *API) GetValidators(number *rpc.BlockNumber) ([]common.Address, error) {
+// ValidatorInfo is the operator metadata a validator registered on chain
+type ValidatorInfo struct {
+	Validator common.Address `json:"validator"`
+	Name      string         `json:"name"`
+	Website   string         `json:"website"`
+	Contact   string         `json:"contact"`
+}
+
+// ValidatorDetail is a validator of an epoch together with its election votes
+// and the number of blocks it produced so far in the epoch
+type ValidatorDetail struct {
+	Address         common.Address `json:"address"`
+	Votes           *hexutil.Big   `json:"votes"`
+	ProducedInEpoch hexutil.Uint64 `json:"producedInEpoch"`
+}
+
+// header retrieves the header at specified block, defaulting to the latest one
+func (api *API) header(number *rpc.BlockNumber) (*types.Header, error) {
 	var header *types.Header
 	if number == nil || *number == rpc.LatestBlockNumber {
 		header = api.chain.CurrentHeader()
@@ -28,6 +45,15 @@ func (api *API) GetValidators(number *rpc.BlockNumber) ([]common.Address, error)
 	if header == nil {
 		return nil, protocol.ErrUnknownBlock
 	}
+	return header, nil
+}
+
+// GetValidators retrieves the list of the validators at specified block
+func (api *API) GetValidators(number *rpc.BlockNumber) ([]common.Address, error) {
+	header, err := api.header(number)
+	if err != nil {
+		return nil, err
+	}
 
 	epochTrie, err := types.NewEpochTrie(header.DposProto.EpochHash, api.dpos.db)
 	if err != nil {
@@ -42,15 +68,61 @@ func (api *API) GetValidators(number *rpc.BlockNumber) ([]common.Address, error)
 	return validators, nil
 }
 
+// GetValidatorsDetailed retrieves the validators at specified block together
+// with their vote counts and the blocks they produced in the block's epoch
+func (api *API) GetValidatorsDetailed(number *rpc.BlockNumber) ([]*ValidatorDetail, error) {
+	header, err := api.header(number)
+	if err != nil {
+		return nil, err
+	}
+	dposContext, err := types.NewDposContextFromProto(api.dpos.db, header.DposProto)
+	if err != nil {
+		return nil, err
+	}
+	validators, err := dposContext.GetEpochTrie()
+	if err != nil {
+		return nil, err
+	}
+	epoch := header.Time.Int64() / protocol.EpochInterval
+	details := make([]*ValidatorDetail, 0, len(validators))
+	for _, validator := range validators {
+		details = append(details, &ValidatorDetail{
+			Address:         validator,
+			Votes:           (*hexutil.Big)(dposContext.GetValidatorVotes(validator)),
+			ProducedInEpoch: hexutil.Uint64(dposContext.GetMintCnt(epoch, validator)),
+		})
+	}
+	return details, nil
+}
+
 // GetConfirmedBlockNumber retrieves the latest irreversible block
 func (api *API) GetConfirmedBlockNumber() (*big.Int, error) {
-	var err error
-	header := api.dpos.confirmedBlockHeader
-	if header == nil {
-		header, err = api.dpos.loadConfirmedBlockHeader(api.chain)
-		if err != nil {
-			return nil, err
-		}
+	header, err := api.dpos.ConfirmedBlockHeader(api.chain)
+	if err != nil {
+		return nil, err
 	}
 	return header.Number, nil
 }
+
+// GetValidatorInfo retrieves the operator metadata registered by a validator at
+// specified block, or nil if the validator never registered any
+func (api *API) GetValidatorInfo(validator common.Address, number *rpc.BlockNumber) (*ValidatorInfo, error) {
+	header, err := api.header(number)
+	if err != nil {
+		return nil, err
+	}
+	bokerContext, err := types.NewBokerContextFromProto(api.dpos.db, header.BokerProto)
+	if err != nil {
+		return nil, err
+	}
+	info, err := bokerContext.ValidatorInfo(validator)
+	if info == nil || err != nil {
+		return nil, err
+	}
+	return &ValidatorInfo{
+		Validator: validator,
+		Name:      info.Name,
+		Website:   info.Website,
+		Contact:   info.Contact,
+	}, nil
+}
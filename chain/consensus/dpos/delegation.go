@@ -0,0 +1,148 @@
+package dpos
+
+import (
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+//委托奖励系统账号中的存储位置(kind与账号拼接后的哈希)
+func delegationSlot(kind string, account common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte(kind), account.Bytes())
+}
+
+func getDelegation(statedb *state.StateDB, kind string, account common.Address) *big.Int {
+	return statedb.GetState(protocol.DelegationAddress, delegationSlot(kind, account)).Big()
+}
+
+func setDelegation(statedb *state.StateDB, kind string, account common.Address, value *big.Int) {
+
+	//设置Nonce，防止系统账号作为空账号被清理
+	if statedb.GetNonce(protocol.DelegationAddress) == 0 {
+		statedb.SetNonce(protocol.DelegationAddress, 1)
+	}
+	statedb.SetState(protocol.DelegationAddress, delegationSlot(kind, account), common.BigToHash(value))
+}
+
+//得到出块节点登记的佣金比例(百分比)，没有登记时出块奖励全部归出块节点
+func GetCommission(statedb *state.StateDB, producer common.Address) (uint64, bool) {
+
+	value := getDelegation(statedb, "commission", producer)
+	if value.Sign() == 0 {
+		return 0, false
+	}
+	return value.Uint64() - 1, true
+}
+
+//登记出块节点的佣金比例(百分比)，从下一个区块开始生效
+func SetCommission(statedb *state.StateDB, producer common.Address, percent uint64) error {
+
+	if percent > 100 {
+		return protocol.ErrInvalidCommission
+	}
+	setDelegation(statedb, "commission", producer, new(big.Int).SetUint64(percent+1))
+	return nil
+}
+
+//出块节点在本周期内留给投票者、尚未分配的奖励
+func GetPendingDelegation(statedb *state.StateDB, producer common.Address) *big.Int {
+	return getDelegation(statedb, "pending", producer)
+}
+
+//得到账号可以领取的以及已经领取的委托奖励
+func GetDelegationRewards(statedb *state.StateDB, account common.Address) (accrued, claimed *big.Int) {
+	return getDelegation(statedb, "accrued", account), getDelegation(statedb, "claimed", account)
+}
+
+//按出块节点的佣金比例拆分出块奖励，投票者的部分转入委托奖励系统账号等待周期结束时分配，返回出块节点的部分
+func splitDelegation(statedb *state.StateDB, producer common.Address, reward *big.Int) *big.Int {
+
+	commission, ok := GetCommission(statedb, producer)
+	if !ok || commission >= 100 {
+		return reward
+	}
+	voters := new(big.Int).Mul(reward, new(big.Int).SetUint64(100-commission))
+	voters.Div(voters, big.NewInt(100))
+
+	statedb.AddBalance(protocol.DelegationAddress, voters)
+	setDelegation(statedb, "pending", producer, new(big.Int).Add(GetPendingDelegation(statedb, producer), voters))
+	return new(big.Int).Sub(reward, voters)
+}
+
+//将当前周期的验证者留给投票者的奖励按投票记录的权重分配给投票者(投票权重分叉之后在VoteEpoch交易中执行)，
+//不能整除的部分以及没有有效投票时的奖励归出块节点，同样需要领取
+func DistributeDelegation(config *params.ChainConfig, statedb *state.StateDB, dposContext *types.DposContext, epoch uint64) error {
+
+	producers, err := dposContext.GetEpochTrie()
+	if err != nil {
+		return err
+	}
+	voters, records, err := dposContext.VoteRecords()
+	if err != nil {
+		return err
+	}
+	for _, producer := range producers {
+		pending := GetPendingDelegation(statedb, producer)
+		if pending.Sign() == 0 {
+			continue
+		}
+
+		//投票给该出块节点的账号的权重
+		total := new(big.Int)
+		weights := make([]*big.Int, len(records))
+		for i, record := range records {
+			if record.Candidate != producer {
+				continue
+			}
+			weights[i] = record.Amount
+			if config.VoteWeight != nil {
+				age := uint64(0)
+				if epoch > record.Epoch {
+					age = epoch - record.Epoch
+				}
+				weights[i] = config.VoteWeight.Weight(record.Amount, statedb.GetBalance(voters[i]), age)
+			}
+			total.Add(total, weights[i])
+		}
+
+		remain := new(big.Int).Set(pending)
+		if total.Sign() > 0 {
+			for i, weight := range weights {
+				if weight == nil || weight.Sign() <= 0 {
+					continue
+				}
+				share := new(big.Int).Mul(pending, weight)
+				share.Div(share, total)
+				remain.Sub(remain, share)
+				accrued, _ := GetDelegationRewards(statedb, voters[i])
+				setDelegation(statedb, "accrued", voters[i], accrued.Add(accrued, share))
+			}
+		}
+		accrued, _ := GetDelegationRewards(statedb, producer)
+		setDelegation(statedb, "accrued", producer, accrued.Add(accrued, remain))
+		setDelegation(statedb, "pending", producer, new(big.Int))
+
+		log.Info("Distributed delegation rewards", "producer", producer, "epoch", epoch, "amount", pending, "producerShare", remain)
+	}
+	return nil
+}
+
+//将账号可以领取的委托奖励从委托奖励系统账号转入账号，返回领取的数量
+func ClaimDelegation(statedb *state.StateDB, account common.Address) (*big.Int, error) {
+
+	accrued, claimed := GetDelegationRewards(statedb, account)
+	if accrued.Sign() == 0 {
+		return nil, protocol.ErrNoDelegationRewards
+	}
+	statedb.SubBalance(protocol.DelegationAddress, accrued)
+	statedb.AddBalance(account, accrued)
+	setDelegation(statedb, "accrued", account, new(big.Int))
+	setDelegation(statedb, "claimed", account, new(big.Int).Add(claimed, accrued))
+	return accrued, nil
+}
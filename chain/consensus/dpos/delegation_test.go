@@ -0,0 +1,81 @@
+package dpos
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+// Tests that block rewards of a producer with a registered commission are split
+// with its voters pro-rata at the epoch transaction, and that voters can claim
+// the distributed rewards exactly once.
+func TestDelegationRewards(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	dposContext, _ := types.NewDposContext(db)
+
+	var (
+		producer, solo   = common.HexToAddress("0x01"), common.HexToAddress("0x02")
+		alice, bob, carl = common.HexToAddress("0xa1"), common.HexToAddress("0xb0"), common.HexToAddress("0xc0")
+	)
+	dposContext.SetEpochTrie([]common.Address{producer, solo})
+	dposContext.AddVoteRecord(alice, producer, big.NewInt(300), 0)
+	dposContext.AddVoteRecord(bob, producer, big.NewInt(100), 0)
+	dposContext.AddVoteRecord(carl, common.HexToAddress("0x03"), big.NewInt(100), 0)
+
+	// Producers without a commission keep the whole reward
+	if reward := splitDelegation(statedb, producer, big.NewInt(1000)); reward.Int64() != 1000 {
+		t.Fatalf("unregistered producer reward mismatch: have %v, want 1000", reward)
+	}
+	if err := SetCommission(statedb, producer, 101); err != protocol.ErrInvalidCommission {
+		t.Fatalf("commission above 100%%: have %v, want %v", err, protocol.ErrInvalidCommission)
+	}
+	SetCommission(statedb, producer, 20)
+	SetCommission(statedb, solo, 50)
+
+	for i := 0; i < 2; i++ {
+		if reward := splitDelegation(statedb, producer, big.NewInt(1001)); reward.Int64() != 201 {
+			t.Fatalf("producer share mismatch: have %v, want 201", reward)
+		}
+	}
+	splitDelegation(statedb, solo, big.NewInt(100))
+	if pending := GetPendingDelegation(statedb, producer); pending.Int64() != 1600 {
+		t.Fatalf("pending mismatch: have %v, want 1600", pending)
+	}
+	if balance := statedb.GetBalance(protocol.DelegationAddress); balance.Int64() != 1650 {
+		t.Fatalf("delegation balance mismatch: have %v, want 1650", balance)
+	}
+
+	if err := DistributeDelegation(params.TestChainConfig, statedb, dposContext, 1); err != nil {
+		t.Fatalf("failed to distribute: %v", err)
+	}
+	// Voters share 3:1, a producer without voters gets its own pending rewards
+	for account, want := range map[common.Address]int64{alice: 1200, bob: 400, carl: 0, producer: 0, solo: 50} {
+		if accrued, _ := GetDelegationRewards(statedb, account); accrued.Int64() != want {
+			t.Errorf("account %x: accrued mismatch: have %v, want %d", account, accrued, want)
+		}
+	}
+	if pending := GetPendingDelegation(statedb, producer); pending.Sign() != 0 {
+		t.Errorf("pending not reset: %v", pending)
+	}
+
+	if amount, err := ClaimDelegation(statedb, alice); err != nil || amount.Int64() != 1200 {
+		t.Fatalf("claim mismatch: have %v (%v), want 1200", amount, err)
+	}
+	if _, err := ClaimDelegation(statedb, alice); err != protocol.ErrNoDelegationRewards {
+		t.Fatalf("second claim: have %v, want %v", err, protocol.ErrNoDelegationRewards)
+	}
+	accrued, claimed := GetDelegationRewards(statedb, alice)
+	if accrued.Sign() != 0 || claimed.Int64() != 1200 || statedb.GetBalance(alice).Int64() != 1200 {
+		t.Errorf("alice after claim: accrued %v, claimed %v, balance %v", accrued, claimed, statedb.GetBalance(alice))
+	}
+	if balance := statedb.GetBalance(protocol.DelegationAddress); balance.Int64() != 450 {
+		t.Errorf("delegation balance mismatch: have %v, want 450", balance)
+	}
+}
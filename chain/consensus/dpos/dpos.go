@@ -38,6 +38,7 @@ var (
 	errInvalidMixDigest  = errors.New("non-zero mix digest")                         // 如果块的混合摘要不为零，则返回errInvalidMixDigest。
 	errInvalidUncleHash  = errors.New("non empty uncle hash")                        //叔块Hash未定义（Dpos下不存在叔块）
 	errInvalidDifficulty = errors.New("invalid difficulty")                          //难度未定义
+	errUnauthorized      = errors.New("validator not authorized")                    //没有设置出块的验证者以及签名函数
 	ErrInvalidTimestamp  = errors.New("invalid timestamp")                           //出块时间不正确
 	ErrWaitForPrevBlock  = errors.New("wait for last block arrived")                 //等待最后一个区块到达
 	ErrMintFutureBlock   = errors.New("mint the future block")                       //根据时间计算是一个未来的区块
@@ -46,30 +47,10 @@ var (
 	uncleHash = types.CalcUncleHash(nil) // Always Keccak256(RLP([])) as uncles are meaningless outside of PoW.
 )
 
-//矿工奖励和股权奖励
-var (
-	MinerRewards [6]*big.Int = [6]*big.Int{new(big.Int).SetUint64(22549179),
-		new(big.Int).SetUint64(11274590),
-		new(big.Int).SetUint64(5637295),
-		new(big.Int).SetUint64(2818647),
-		new(big.Int).SetUint64(1409324),
-		new(big.Int).SetUint64(704662)}
-
-	StockRewards [6]*big.Int = [6]*big.Int{new(big.Int).SetUint64(5637295),
-		new(big.Int).SetUint64(2818647),
-		new(big.Int).SetUint64(1409324),
-		new(big.Int).SetUint64(704662),
-		new(big.Int).SetUint64(352331),
-		new(big.Int).SetUint64(176165)}
-)
-
-var (
-	genesisYear = int(2019)
-)
-
 type Dpos struct {
 	db                   ethdb.Database //数据库对象
-	signer               common.Address //签名者地址
+	validator            common.Address //验证者地址（区块头中的验证者）
+	signer               common.Address //签名者地址（验证者设置的出块签名账号）
 	signFn               SignerFn       //签名处理函数
 	signatures           *lru.ARCCache  //最近的块签名加快采矿
 	confirmedBlockHeader *types.Header
@@ -257,16 +238,22 @@ func (d *Dpos) verifySeal(chain consensus.ChainReader, header *types.Header, par
 			return err
 		}
 
+		//根据父区块得到出块节点当前的签名账号
+		bokerContext, err := types.NewBokerContextFromProto(d.db, parent.BokerProto)
+		if err != nil {
+			return err
+		}
+
 		//验证区块签名者
-		if err := d.verifyBlockSigner(producer, header); err != nil {
+		if err := d.verifyBlockSigner(producer, bokerContext.SigningKey(producer), header); err != nil {
 			return err
 		}
 	}
 	return d.updateConfirmedBlockHeader(chain)
 }
 
-//验证区块签名
-func (d *Dpos) verifyBlockSigner(producer common.Address, header *types.Header) error {
+//验证区块签名（signingKey为出块节点当前的签名账号，没有设置时即为出块节点自身）
+func (d *Dpos) verifyBlockSigner(producer common.Address, signingKey common.Address, header *types.Header) error {
 
 	//根据包头得到签名者
 	signer, err := ecrecover(header, d.signatures)
@@ -274,13 +261,13 @@ func (d *Dpos) verifyBlockSigner(producer common.Address, header *types.Header)
 		return err
 	}
 
-	//判断签名者和验证者是否是一个人
-	if bytes.Compare(signer.Bytes(), producer.Bytes()) != 0 {
+	//判断签名者是否是出块节点的签名账号
+	if bytes.Compare(signer.Bytes(), signingKey.Bytes()) != 0 {
 		return protocol.ErrInvalidProducer
 	}
 
-	//判断签名者和区块头中的验证者是否是同一个人
-	if bytes.Compare(signer.Bytes(), header.Validator.Bytes()) != 0 {
+	//判断出块节点和区块头中的验证者是否是同一个人
+	if bytes.Compare(producer.Bytes(), header.Validator.Bytes()) != 0 {
 		return protocol.ErrMismatchSignerAndValidator
 	}
 	return nil
@@ -349,6 +336,15 @@ func (d *Dpos) updateConfirmedBlockHeader(chain consensus.ChainReader) error {
 	return nil
 }
 
+//已经确认(不可逆)的区块头, 区块链不允许回滚到该区块以下
+func (d *Dpos) ConfirmedBlockHeader(chain consensus.ChainReader) (*types.Header, error) {
+
+	if header := d.confirmedBlockHeader; header != nil {
+		return header, nil
+	}
+	return d.loadConfirmedBlockHeader(chain)
+}
+
 //加载确认区块头
 func (s *Dpos) loadConfirmedBlockHeader(chain consensus.ChainReader) (*types.Header, error) {
 
@@ -391,46 +387,36 @@ func (d *Dpos) Prepare(chain consensus.ChainReader, header *types.Header) error
 	//设置区块难度(此处恒定为1)
 	header.Difficulty = d.CalcDifficulty(chain, header.Time.Uint64(), parent)
 
-	//设置区块头的验证者的签名
-	header.Validator = d.signer
+	//设置区块头的验证者
+	header.Validator = d.validator
 	return nil
 }
 
-func getRewards(header *types.Header) (minerParam, stockParam *big.Int) {
-
-	if 0 == header.Time.Int64() {
-		return new(big.Int).SetUint64(0), new(big.Int).SetUint64(0)
-	} else {
-
-		year := time.Unix(header.Time.Int64(), 0).Year()
-		postion := (year - genesisYear) / 4
-		minerParam = MinerRewards[postion]
-		stockParam = StockRewards[postion]
-		return minerParam, stockParam
-	}
-}
-
 func AccumulateRewards(config *params.ChainConfig,
 	state *state.StateDB,
 	header *types.Header,
 	uncles []*types.Header,
 	txs []*types.Transaction,
 	bokerContext *types.BokerContext,
-	boker bokerapi.Api) {
+	boker bokerapi.Api,
+	genesisTime int64) {
 
 	//log.Info("dpos.go AccumulateRewards", "Number", header.Number.String(), "Time", header.Time)
-	minerParam, stockParam := getRewards(header)
+	minerParam, stockParam := getRewards(config, state, header, genesisTime)
 
-	//给出块节点的报酬
+	//给出块节点的报酬(登记了佣金比例时投票者的部分留到周期结束时分配)
 	minerReward := big.NewInt(1)
 	minerReward.Mul(protocol.TinaUnit, minerParam)
+	minerReward = splitDelegation(state, header.Coinbase, minerReward)
 	state.AddBalance(header.Coinbase, new(big.Int).Set(minerReward))
+	bokerContext.Rewards().ProducerReward.Add(bokerContext.Rewards().ProducerReward, minerReward)
 	//log.Info("dpos.go AccumulateRewards Miner Award", "Coinbase", header.Coinbase, "reward", new(big.Int).Set(minerReward))
 
 	//将其它部分放入到股权gas池中,等待分配
 	stockReward := big.NewInt(1)
 	stockReward.Mul(protocol.TinaUnit, stockParam)
 	bokerContext.AddGasPool(new(big.Int).Set(stockReward).Uint64())
+	bokerContext.Rewards().GasPoolContribution.Add(bokerContext.Rewards().GasPoolContribution, stockReward)
 	//log.Info("dpos.go AccumulateRewards Stock Award", "reward", new(big.Int).Set(stockReward))
 }
 
@@ -445,13 +431,32 @@ func (d *Dpos) Finalize(chain consensus.ChainReader,
 	bokerContext *types.BokerContext,
 	boker bokerapi.Api) (*types.Block, error) {
 
+	//记录本区块的出块节点以及交易的Gas费用
+	rewards := bokerContext.Rewards()
+	rewards.Producer = header.Coinbase
+	for i, receipt := range receipts {
+		if i < len(txs) && receipt.GasUsed != nil {
+			rewards.GasFees.Add(rewards.GasFees, new(big.Int).Mul(receipt.GasUsed, txs[i].GasPrice()))
+		}
+	}
+
+	var genesisTime int64
+	if genesis := chain.GetHeaderByNumber(genesisNumber); genesis != nil {
+		genesisTime = genesis.Time.Int64()
+	}
+
+	//跨越周期边界时对治理提案进行计票并执行
+	parent := chain.GetHeaderByHash(header.ParentHash)
+	if epoch := EpochOf(header.Time.Int64(), genesisTime); parent != nil && epoch > EpochOf(parent.Time.Int64(), genesisTime) && chain.Config().IsGovernance(header.Number) {
+		enactProposals(chain.Config().Governance, state, bokerContext, epoch, header.Number.Uint64())
+	}
+
 	if header.Number.Uint64() != firstNumber {
-		AccumulateRewards(chain.Config(), state, header, uncles, txs, bokerContext, boker)
+		AccumulateRewards(chain.Config(), state, header, uncles, txs, bokerContext, boker, genesisTime)
 	}
 
 	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
 
-	parent := chain.GetHeaderByHash(header.ParentHash)
 	if protocol.TimeOfFirstBlock == 0 {
 		if firstBlockHeader := chain.GetHeaderByNumber(1); firstBlockHeader != nil {
 			protocol.TimeOfFirstBlock = firstBlockHeader.Time.Int64()
@@ -460,6 +465,9 @@ func (d *Dpos) Finalize(chain consensus.ChainReader,
 
 	//更新MintCnt的默克尔树，并返回一个新区块
 	updateMintCnt(parent.Time.Int64(), header.Time.Int64(), header.Validator, dposContext)
+	if header.Number.Uint64() != firstNumber {
+		markSlots(parent.Time.Int64(), header.Time.Int64())
+	}
 	header.DposProto = dposContext.ToProto()
 	header.BokerProto = bokerContext.ToProto()
 
@@ -503,9 +511,18 @@ func (d *Dpos) CheckProducer(lastBlock *types.Block, now int64, firstTimer int64
 	if err != nil {
 		return err
 	}
-	if (producer == common.Address{}) || bytes.Compare(producer.Bytes(), d.signer.Bytes()) != 0 {
+	if (producer == common.Address{}) || bytes.Compare(producer.Bytes(), d.validator.Bytes()) != 0 {
 		return protocol.ErrInvalidProducer
 	}
+
+	//本节点的签名账号必须是验证者当前设置的签名账号，否则产生的区块无法通过验证
+	bokerContext, err := types.NewBokerContextFromProto(d.db, lastBlock.Header().BokerProto)
+	if err != nil {
+		return err
+	}
+	if bokerContext.SigningKey(producer) != d.signer {
+		return protocol.ErrInvalidSigningKey
+	}
 	return nil
 }
 
@@ -605,14 +622,29 @@ func (d *Dpos) APIs(chain consensus.ChainReader) []rpc.API {
 	}}
 }
 
-func (d *Dpos) Authorize(signer common.Address, signFn SignerFn) {
+//设置出块的验证者以及签名账号，验证者没有设置单独的签名账号时两者相同
+func (d *Dpos) Authorize(validator common.Address, signer common.Address, signFn SignerFn) {
 
 	d.mu.Lock()
+	d.validator = validator
 	d.signer = signer
 	d.signFn = signFn
 	d.mu.Unlock()
 }
 
+//使用出块签名账号签名区块以外的哈希(如验证者心跳), 返回签名[R || S || V]
+func (d *Dpos) SignHash(hash common.Hash) ([]byte, error) {
+
+	d.mu.RLock()
+	signer, signFn := d.signer, d.signFn
+	d.mu.RUnlock()
+
+	if signFn == nil {
+		return nil, errUnauthorized
+	}
+	return signFn(accounts.Account{Address: signer}, hash[:])
+}
+
 //根据签名头获取到用户账号
 func ecrecover(header *types.Header, sigcache *lru.ARCCache) (common.Address, error) {
 
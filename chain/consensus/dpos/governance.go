@@ -0,0 +1,55 @@
+package dpos
+
+import (
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+//在周期边界对投票已结束的提案进行计票，参与投票的权重达到法定人数并且赞成票权重大于反对票权重的提案生效
+func enactProposals(config *params.GovernanceConfig, statedb *state.StateDB, bokerContext *types.BokerContext, epoch, number uint64) {
+
+	for _, proposal := range bokerContext.GetProposals() {
+
+		if proposal.State != protocol.ProposalVoting || epoch < proposal.EndEpoch {
+			continue
+		}
+
+		result := protocol.ProposalRejected
+		cast := new(big.Int).Add(proposal.Yes, proposal.No)
+		if config.Quorum(cast, proposal.Total) && proposal.Yes.Cmp(proposal.No) > 0 {
+			if err := enactProposal(statedb, bokerContext, proposal, epoch, number); err != nil {
+				log.Warn("Failed to enact governance proposal", "id", proposal.Id, "err", err)
+			} else {
+				result = protocol.ProposalEnacted
+			}
+		}
+		log.Info("Governance proposal closed", "id", proposal.Id, "yes", proposal.Yes, "no", proposal.No, "state", result)
+
+		if err := bokerContext.SetProposalState(proposal.Id, result); err != nil {
+			log.Error("Failed to update governance proposal", "id", proposal.Id, "err", err)
+		}
+	}
+}
+
+//执行通过的提案
+func enactProposal(statedb *state.StateDB, bokerContext *types.BokerContext, proposal *protocol.Proposal, epoch, number uint64) error {
+
+	switch proposal.Kind {
+	case protocol.ParamChange:
+		return SetRewardOverride(statedb, &RewardOverride{
+			Reward:         proposal.Reward,
+			GasPoolPercent: proposal.GasPoolPercent,
+			Epoch:          epoch,
+		})
+	case protocol.ContractUpgrade:
+		//与直接变更一样进入待生效状态，等待延迟区块后由股权管理者或者超过2/3的验证者确认
+		return bokerContext.ProposeSystemContract(proposal.Target, proposal.Proposer, number)
+	default:
+		return protocol.ErrInvalidProposal
+	}
+}
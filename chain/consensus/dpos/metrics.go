@@ -0,0 +1,20 @@
+package dpos
+
+import (
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/metrics"
+)
+
+var (
+	slotMintedMeter = metrics.NewMeter("dpos/slots/minted")
+	slotMissedMeter = metrics.NewMeter("dpos/slots/missed")
+)
+
+//统计出块的时间片以及父区块与当前区块之间没有出块的时间片
+func markSlots(parentBlockTime, currentBlockTime int64) {
+
+	slotMintedMeter.Mark(1)
+	if missed := (currentBlockTime-parentBlockTime)/protocol.BlockInterval - 1; missed > 0 {
+		slotMissedMeter.Mark(missed)
+	}
+}
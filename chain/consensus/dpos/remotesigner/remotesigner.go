@@ -0,0 +1,131 @@
+//remotesigner包通过远程签名服务(HTTP JSON-RPC, 支持双向TLS认证)对区块进行签名,
+//出块节点不需要在本地解锁验证者或签名账号。
+//
+//签名服务需要提供signer_signHash(address, hash)方法, 返回65字节的secp256k1签名[R || S || V](V为0或1)。
+package remotesigner
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/accounts"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//单次签名请求的超时时间，需要小于出块间隔
+const signTimeout = 2 * time.Second
+
+var (
+	errMissingURL        = errors.New("remote signer url missing")
+	errIncompleteCert    = errors.New("remote signer client certificate and key must be set together")
+	errInvalidCA         = errors.New("no certificates found in remote signer ca file")
+	errInvalidSignature  = errors.New("invalid signature length from remote signer")
+	errMismatchSignature = errors.New("remote signature does not match signing account")
+)
+
+//远程签名服务的配置
+type Config struct {
+	URL      string `toml:",omitempty"` //签名服务地址
+	CertFile string `toml:",omitempty"` //客户端证书(双向TLS认证)
+	KeyFile  string `toml:",omitempty"` //客户端证书私钥
+	CAFile   string `toml:",omitempty"` //验证服务端证书的CA(为空时使用系统CA)
+}
+
+//远程签名者
+type Signer struct {
+	url    string
+	client *rpc.Client
+}
+
+//根据配置连接远程签名服务
+func New(config *Config) (*Signer, error) {
+
+	if config == nil || config.URL == "" {
+		return nil, errMissingURL
+	}
+	tlsConfig, err := newTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := &http.Client{
+		Timeout:   signTimeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}
+	client, err := rpc.DialHTTPWithClient(config.URL, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{url: config.URL, client: client}, nil
+}
+
+//加载客户端证书以及CA
+func newTLSConfig(config *Config) (*tls.Config, error) {
+
+	tlsConfig := new(tls.Config)
+	if (config.CertFile == "") != (config.KeyFile == "") {
+		return nil, errIncompleteCert
+	}
+	if config.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load remote signer certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if config.CAFile != "" {
+		pem, err := ioutil.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read remote signer ca: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errInvalidCA
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+//请求远程签名服务对哈希签名, 函数签名与dpos.SignerFn一致
+//返回的签名会在本地恢复公钥并与签名账号比较, 防止签名服务使用了错误的账号
+func (s *Signer) SignHash(account accounts.Account, hash []byte) ([]byte, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), signTimeout)
+	defer cancel()
+
+	var signature hexutil.Bytes
+	if err := s.client.CallContext(ctx, &signature, "signer_signHash", account.Address, hexutil.Bytes(hash)); err != nil {
+		log.Error("Remote signer request failed", "url", s.url, "account", account.Address, "err", err)
+		return nil, err
+	}
+	if len(signature) != 65 {
+		return nil, errInvalidSignature
+	}
+	pubkey, err := crypto.SigToPub(hash, signature)
+	if err != nil {
+		return nil, err
+	}
+	if crypto.PubkeyToAddress(*pubkey) != account.Address {
+		return nil, errMismatchSignature
+	}
+	return signature, nil
+}
+
+//关闭与签名服务的连接
+func (s *Signer) Close() {
+	s.client.Close()
+}
+
+//签名服务的地址
+func (s *Signer) URL() string {
+	return s.url
+}
@@ -0,0 +1,79 @@
+package remotesigner
+
+import (
+	"crypto/ecdsa"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/accounts"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//模拟签名服务，使用固定的私钥签名
+type SignerService struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s *SignerService) SignHash(address common.Address, hash hexutil.Bytes) (hexutil.Bytes, error) {
+	return crypto.Sign(hash, s.key)
+}
+
+func newTestSigner(t *testing.T, key *ecdsa.PrivateKey) (*Signer, func()) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("signer", &SignerService{key}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	signer, err := New(&Config{URL: httpServer.URL})
+	if err != nil {
+		t.Fatalf("failed to create remote signer: %v", err)
+	}
+	return signer, func() {
+		signer.Close()
+		httpServer.Close()
+	}
+}
+
+//测试远程签名能够恢复出签名账号
+func TestSignHash(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer, closeFn := newTestSigner(t, key)
+	defer closeFn()
+
+	account := accounts.Account{Address: crypto.PubkeyToAddress(key.PublicKey)}
+	hash := crypto.Keccak256([]byte("header"))
+	sig, err := signer.SignHash(account, hash)
+	if err != nil {
+		t.Fatalf("failed to sign hash: %v", err)
+	}
+	pubkey, err := crypto.SigToPub(hash, sig)
+	if err != nil || crypto.PubkeyToAddress(*pubkey) != account.Address {
+		t.Errorf("signer mismatch: have %v, want %x", err, account.Address)
+	}
+}
+
+//测试签名服务使用了其他账号签名时返回错误
+func TestSignHashMismatch(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer, closeFn := newTestSigner(t, key)
+	defer closeFn()
+
+	other, _ := crypto.GenerateKey()
+	account := accounts.Account{Address: crypto.PubkeyToAddress(other.PublicKey)}
+	if _, err := signer.SignHash(account, crypto.Keccak256([]byte("header"))); err != errMismatchSignature {
+		t.Errorf("error mismatch: have %v, want %v", err, errMismatchSignature)
+	}
+}
+
+//测试客户端证书和私钥必须同时设置
+func TestIncompleteCertificate(t *testing.T) {
+	if _, err := New(&Config{URL: "https://localhost:8550", CertFile: "client.crt"}); err != errIncompleteCert {
+		t.Errorf("error mismatch: have %v, want %v", err, errIncompleteCert)
+	}
+	if _, err := New(&Config{}); err != errMissingURL {
+		t.Errorf("error mismatch: have %v, want %v", err, errMissingURL)
+	}
+}
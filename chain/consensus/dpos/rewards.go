@@ -0,0 +1,116 @@
+package dpos
+
+import (
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+//奖励计划覆盖值在系统账号中的存储位置
+var (
+	rewardSlot         = common.BigToHash(big.NewInt(0)) //区块总奖励
+	gasPoolPercentSlot = common.BigToHash(big.NewInt(1)) //放入股权Gas池的比例
+	activeEpochSlot    = common.BigToHash(big.NewInt(2)) //生效周期+1（0表示没有覆盖值）
+)
+
+//通过治理交易设置的奖励计划
+type RewardOverride struct {
+	Reward         *big.Int //区块总奖励（单位为TinaUnit）
+	GasPoolPercent uint64   //放入股权Gas池的比例
+	Epoch          uint64   //开始生效的周期
+}
+
+//读取状态中的奖励计划覆盖值，没有则返回nil
+func GetRewardOverride(statedb *state.StateDB) *RewardOverride {
+
+	active := statedb.GetState(protocol.RewardScheduleAddress, activeEpochSlot).Big()
+	if active.Sign() == 0 {
+		return nil
+	}
+	return &RewardOverride{
+		Reward:         statedb.GetState(protocol.RewardScheduleAddress, rewardSlot).Big(),
+		GasPoolPercent: statedb.GetState(protocol.RewardScheduleAddress, gasPoolPercentSlot).Big().Uint64(),
+		Epoch:          active.Uint64() - 1,
+	}
+}
+
+//将奖励计划覆盖值写入状态，在指定周期开始时生效
+func SetRewardOverride(statedb *state.StateDB, override *RewardOverride) error {
+
+	if override.Reward == nil || override.Reward.Sign() < 0 || override.GasPoolPercent > 100 {
+		return protocol.ErrInvalidRewardSchedule
+	}
+	touchRewardAccount(statedb)
+	statedb.SetState(protocol.RewardScheduleAddress, rewardSlot, common.BigToHash(override.Reward))
+	statedb.SetState(protocol.RewardScheduleAddress, gasPoolPercentSlot, common.BigToHash(new(big.Int).SetUint64(override.GasPoolPercent)))
+	statedb.SetState(protocol.RewardScheduleAddress, activeEpochSlot, common.BigToHash(new(big.Int).SetUint64(override.Epoch+1)))
+	return nil
+}
+
+//设置Nonce，防止系统账号作为空账号被清理
+func touchRewardAccount(statedb *state.StateDB) {
+	if statedb.GetNonce(protocol.RewardScheduleAddress) == 0 {
+		statedb.SetNonce(protocol.RewardScheduleAddress, 1)
+	}
+}
+
+//检查奖励计划调整的取值：区块总奖励不能超过链配置奖励计划中的最高奖励，Gas池比例不能超过100
+func CheckRewardSchedule(config *params.ChainConfig, reward *big.Int, gasPoolPercent uint64) error {
+
+	if reward == nil || reward.Sign() < 0 || reward.Cmp(config.RewardSchedule().MaxReward()) > 0 || gasPoolPercent > 100 {
+		return protocol.ErrInvalidRewardSchedule
+	}
+	return nil
+}
+
+//奖励计划调整的确认数量在系统账号中的存储位置，验证者需要在同一个周期内确认相同的奖励计划
+func rewardApprovalKey(schedule *protocol.RewardSchedule, epoch uint64) common.Hash {
+	return crypto.Keccak256Hash(
+		common.BigToHash(schedule.Reward).Bytes(),
+		common.BigToHash(new(big.Int).SetUint64(schedule.GasPoolPercent)).Bytes(),
+		common.BigToHash(new(big.Int).SetUint64(epoch)).Bytes())
+}
+
+//记录验证者对奖励计划调整的确认，返回该周期内确认的验证者数量（重复确认不计数）
+func ApproveRewardSchedule(statedb *state.StateDB, schedule *protocol.RewardSchedule, epoch uint64, validator common.Address) uint64 {
+
+	key := rewardApprovalKey(schedule, epoch)
+	approval := crypto.Keccak256Hash(key.Bytes(), validator.Bytes())
+	count := statedb.GetState(protocol.RewardScheduleAddress, key).Big().Uint64()
+	if statedb.GetState(protocol.RewardScheduleAddress, approval) != (common.Hash{}) {
+		return count
+	}
+	touchRewardAccount(statedb)
+	count++
+	statedb.SetState(protocol.RewardScheduleAddress, approval, common.BigToHash(big.NewInt(1)))
+	statedb.SetState(protocol.RewardScheduleAddress, key, common.BigToHash(new(big.Int).SetUint64(count)))
+	return count
+}
+
+//根据区块时间计算所在的周期
+func EpochOf(blockTime, genesisTime int64) uint64 {
+	if blockTime <= genesisTime {
+		return 0
+	}
+	return uint64((blockTime - genesisTime) / protocol.EpochInterval)
+}
+
+//计算区块的出块奖励以及放入股权Gas池的数量（单位为TinaUnit），治理交易设置的覆盖值在生效周期后优先于链配置
+func getRewards(config *params.ChainConfig, statedb *state.StateDB, header *types.Header, genesisTime int64) (minerParam, stockParam *big.Int) {
+
+	if 0 == header.Time.Int64() {
+		return new(big.Int), new(big.Int)
+	}
+
+	schedule := config.RewardSchedule()
+	total, percent := schedule.RewardAt(header.Time.Int64()), schedule.GasPoolPercent
+	if override := GetRewardOverride(statedb); override != nil && EpochOf(header.Time.Int64(), genesisTime) >= override.Epoch {
+		total, percent = override.Reward, override.GasPoolPercent
+	}
+	return params.SplitReward(total, percent)
+}
@@ -0,0 +1,113 @@
+package dpos
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+func TestCheckRewardSchedule(t *testing.T) {
+	config := params.TestChainConfig
+	max := params.DefaultRewardConfig.Rewards[0]
+
+	tests := []struct {
+		reward  *big.Int
+		percent uint64
+		valid   bool
+	}{
+		{max, 100, true},
+		{new(big.Int), 0, true},
+		{nil, 20, false},
+		{big.NewInt(-1), 20, false},
+		{new(big.Int).Add(max, big.NewInt(1)), 20, false},
+		{max, 101, false},
+	}
+	for i, tt := range tests {
+		if err := CheckRewardSchedule(config, tt.reward, tt.percent); (err == nil) != tt.valid {
+			t.Errorf("test %d: have %v, want valid %v", i, err, tt.valid)
+		}
+	}
+}
+
+// Tests that validator approvals of a reward schedule are counted once per
+// validator and separately for every schedule and epoch.
+func TestApproveRewardSchedule(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+
+	var (
+		schedule = &protocol.RewardSchedule{Reward: big.NewInt(1000), GasPoolPercent: 20}
+		other    = &protocol.RewardSchedule{Reward: big.NewInt(1000), GasPoolPercent: 30}
+		v1, v2   = common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	)
+	if count := ApproveRewardSchedule(statedb, schedule, 5, v1); count != 1 {
+		t.Fatalf("first approval: have %d, want 1", count)
+	}
+	if count := ApproveRewardSchedule(statedb, schedule, 5, v1); count != 1 {
+		t.Fatalf("repeated approval: have %d, want 1", count)
+	}
+	if count := ApproveRewardSchedule(statedb, other, 5, v2); count != 1 {
+		t.Fatalf("other schedule approval: have %d, want 1", count)
+	}
+	if count := ApproveRewardSchedule(statedb, schedule, 6, v2); count != 1 {
+		t.Fatalf("next epoch approval: have %d, want 1", count)
+	}
+	if count := ApproveRewardSchedule(statedb, schedule, 5, v2); count != 2 {
+		t.Fatalf("second approval: have %d, want 2", count)
+	}
+	//确认不会改变当前的奖励计划
+	if override := GetRewardOverride(statedb); override != nil {
+		t.Fatalf("approvals set an override: %+v", override)
+	}
+}
+
+// Tests that the block reward split between the producer and the stock gas pool
+// is paid out and recorded in the reward report of the block.
+func TestAccumulateRewards(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	bokerContext, _ := types.NewBokerContext(db)
+
+	producer := common.HexToAddress("0x01")
+	SetRewardOverride(statedb, &RewardOverride{Reward: big.NewInt(10), GasPoolPercent: 20})
+	header := &types.Header{Number: big.NewInt(2), Time: big.NewInt(1), Coinbase: producer}
+
+	minerReward := new(big.Int).Mul(protocol.TinaUnit, big.NewInt(8))
+	stockReward := new(big.Int).Mul(protocol.TinaUnit, big.NewInt(2))
+	AccumulateRewards(params.TestChainConfig, statedb, header, nil, nil, bokerContext, nil, 0)
+
+	rewards := bokerContext.Rewards()
+	if balance := statedb.GetBalance(producer); balance.Cmp(minerReward) != 0 {
+		t.Errorf("producer balance mismatch: have %v, want %v", balance, minerReward)
+	}
+	if rewards.ProducerReward.Cmp(minerReward) != 0 {
+		t.Errorf("recorded producer reward mismatch: have %v, want %v", rewards.ProducerReward, minerReward)
+	}
+	if pool := bokerContext.GetGasPool(); pool != stockReward.Uint64() {
+		t.Errorf("gas pool mismatch: have %d, want %v", pool, stockReward)
+	}
+	if rewards.GasPoolContribution.Cmp(stockReward) != 0 {
+		t.Errorf("recorded gas pool contribution mismatch: have %v, want %v", rewards.GasPoolContribution, stockReward)
+	}
+
+	//登记了佣金比例时只记录出块节点自己的部分
+	SetCommission(statedb, producer, 50)
+	AccumulateRewards(params.TestChainConfig, statedb, header, nil, nil, bokerContext, nil, 0)
+
+	share := new(big.Int).Div(minerReward, big.NewInt(2))
+	if want := new(big.Int).Add(minerReward, share); rewards.ProducerReward.Cmp(want) != 0 {
+		t.Errorf("recorded producer reward with commission mismatch: have %v, want %v", rewards.ProducerReward, want)
+	}
+	if pending := GetPendingDelegation(statedb, producer); pending.Cmp(share) != 0 {
+		t.Errorf("pending delegation mismatch: have %v, want %v", pending, share)
+	}
+	if want := new(big.Int).Mul(stockReward, big.NewInt(2)); rewards.GasPoolContribution.Cmp(want) != 0 {
+		t.Errorf("recorded gas pool contribution mismatch: have %v, want %v", rewards.GasPoolContribution, want)
+	}
+}
@@ -0,0 +1,62 @@
+package simulator
+
+import (
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+//模拟网络中只包含区块的链，实现consensus.ChainReader
+type chain struct {
+	genesis *types.Block
+	head    *types.Block
+	blocks  map[common.Hash]*types.Block
+	numbers []*types.Block
+}
+
+func newChain(genesis *types.Block) *chain {
+	return &chain{
+		genesis: genesis,
+		head:    genesis,
+		blocks:  map[common.Hash]*types.Block{genesis.Hash(): genesis},
+		numbers: []*types.Block{genesis},
+	}
+}
+
+//将区块设置为链头
+func (c *chain) insert(block *types.Block) {
+	c.blocks[block.Hash()] = block
+	c.numbers = append(c.numbers, block)
+	c.head = block
+}
+
+func (c *chain) Config() *params.ChainConfig  { return params.TestChainConfig }
+func (c *chain) CurrentHeader() *types.Header { return c.head.Header() }
+
+func (c *chain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if block := c.GetBlock(hash, number); block != nil {
+		return block.Header()
+	}
+	return nil
+}
+
+func (c *chain) GetHeaderByNumber(number uint64) *types.Header {
+	if number >= uint64(len(c.numbers)) {
+		return nil
+	}
+	return c.numbers[number].Header()
+}
+
+func (c *chain) GetHeaderByHash(hash common.Hash) *types.Header {
+	if block, ok := c.blocks[hash]; ok {
+		return block.Header()
+	}
+	return nil
+}
+
+func (c *chain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	if block, ok := c.blocks[hash]; ok && block.NumberU64() == number {
+		return block
+	}
+	return nil
+}
@@ -0,0 +1,354 @@
+// Package simulator runs an in-memory multi-validator Dpos network on virtual
+// time, without p2p networking or transaction processing.
+//
+// Every simulated validator runs its own Dpos engine over a shared database and
+// decides whether a slot is its own through the same checks the miner uses
+// (CheckDeadline and CheckProducer), then seals the block with its key. At each
+// epoch boundary the candidates are elected into the validator set of the next
+// block, the same way the VoteEpoch system transaction does on chain. This lets
+// integrators assert the producer schedule, round-robin order and the effect of
+// votes of their validator configurations before changing them on mainnet.
+package simulator
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/Tinachain/Tina/chain/accounts"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/consensus/dpos"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+//默认的创世区块时间(与周期边界对齐)
+const DefaultGenesisTime = int64(1500000000) / protocol.EpochInterval * protocol.EpochInterval
+
+var (
+	errNoCandidates   = errors.New("no candidates to elect")
+	errUnknownNode    = errors.New("unknown validator")
+	errDoubleProducer = errors.New("several validators claim the same slot")
+)
+
+//候选验证者以及其得票数
+type Candidate struct {
+	Address common.Address
+	Votes   *big.Int
+}
+
+//由候选者选出周期的验证者(顺序即出块顺序)
+type ElectFn func(candidates []Candidate) []Candidate
+
+//按注册顺序选出全部候选者，与部署的投票合约(getCandidates)相同
+func RegistrationOrder(candidates []Candidate) []Candidate {
+	return candidates
+}
+
+//按得票数从高到低选出最多n个候选者，得票相同时按注册顺序
+func TopVotes(n int) ElectFn {
+	return func(candidates []Candidate) []Candidate {
+		elected := make([]Candidate, len(candidates))
+		copy(elected, candidates)
+		sort.SliceStable(elected, func(i, j int) bool {
+			return elected[i].Votes.Cmp(elected[j].Votes) > 0
+		})
+		if len(elected) > n {
+			elected = elected[:n]
+		}
+		return elected
+	}
+}
+
+//模拟网络的配置
+type Config struct {
+	GenesisTime int64       //创世区块时间(为0时使用DefaultGenesisTime)
+	Candidates  []Candidate //初始候选者(创世周期的验证者由其选出)
+	Elect       ElectFn     //选举函数(为空时使用RegistrationOrder)
+}
+
+//一个时间片的模拟结果
+type Slot struct {
+	Time     int64          //时间片的开始时间
+	Epoch    uint64         //时间片所在的周期
+	Producer common.Address //应该出块的验证者
+	Number   uint64         //产生的区块高度(错过时间片时为0)
+	Missed   bool           //验证者离线而错过了时间片
+}
+
+//模拟的验证者节点
+type node struct {
+	key    *ecdsa.PrivateKey
+	engine *dpos.Dpos
+	online bool
+}
+
+//内存中的多验证者Dpos网络
+type Network struct {
+	db         ethdb.Database
+	chain      *chain
+	elect      ElectFn
+	candidates []Candidate
+	nodes      map[common.Address]*node
+	now        int64
+	produced   map[common.Address]int
+}
+
+//创建模拟网络, 为每个候选者生成密钥并在创世区块中选出第一个周期的验证者
+func New(config Config) (*Network, error) {
+
+	if len(config.Candidates) == 0 {
+		return nil, errNoCandidates
+	}
+	genesisTime := config.GenesisTime
+	if genesisTime == 0 {
+		genesisTime = DefaultGenesisTime
+	}
+	db, _ := ethdb.NewMemDatabase()
+	n := &Network{
+		db:       db,
+		elect:    config.Elect,
+		nodes:    make(map[common.Address]*node),
+		now:      genesisTime,
+		produced: make(map[common.Address]int),
+	}
+	if n.elect == nil {
+		n.elect = RegistrationOrder
+	}
+	for _, candidate := range config.Candidates {
+		if _, err := n.Register(candidate.Votes); err != nil {
+			return nil, err
+		}
+	}
+
+	//由初始候选者选出创世周期的验证者
+	dposContext, err := types.NewDposContextFromProto(db, &types.DposContextProto{})
+	if err != nil {
+		return nil, err
+	}
+	dposContext.SetEpochTrie([]common.Address{})
+	if err := n.electInto(dposContext); err != nil {
+		return nil, err
+	}
+	dposProto, err := dposContext.CommitTo(db)
+	if err != nil {
+		return nil, err
+	}
+	bokerContext, err := types.NewBokerContext(db)
+	if err != nil {
+		return nil, err
+	}
+	bokerProto, err := bokerContext.CommitTo(db)
+	if err != nil {
+		return nil, err
+	}
+	genesis := types.NewBlockWithHeader(&types.Header{
+		Number:     new(big.Int),
+		Time:       big.NewInt(genesisTime),
+		Difficulty: big.NewInt(1),
+		GasLimit:   new(big.Int),
+		GasUsed:    new(big.Int),
+		DposProto:  dposProto,
+		BokerProto: bokerProto,
+	})
+	n.chain = newChain(genesis)
+	return n, nil
+}
+
+//注册新的候选者(生成其密钥)，在下一个周期的选举中生效
+func (n *Network) Register(votes *big.Int) (common.Address, error) {
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return common.Address{}, err
+	}
+	validator := crypto.PubkeyToAddress(key.PublicKey)
+
+	engine := dpos.New(&params.DposConfig{}, n.db)
+	engine.Authorize(validator, validator, func(account accounts.Account, hash []byte) ([]byte, error) {
+		return crypto.Sign(hash, key)
+	})
+	n.nodes[validator] = &node{key: key, engine: engine, online: true}
+	n.candidates = append(n.candidates, Candidate{Address: validator, Votes: new(big.Int).Set(votes)})
+	return validator, nil
+}
+
+//得到全部候选者(按注册顺序)
+func (n *Network) Candidates() []Candidate {
+
+	candidates := make([]Candidate, len(n.candidates))
+	copy(candidates, n.candidates)
+	return candidates
+}
+
+//设置候选者的得票数，在下一个周期的选举中生效
+func (n *Network) Vote(validator common.Address, votes *big.Int) error {
+
+	for i := range n.candidates {
+		if n.candidates[i].Address == validator {
+			n.candidates[i].Votes = new(big.Int).Set(votes)
+			return nil
+		}
+	}
+	return errUnknownNode
+}
+
+//设置验证者节点是否在线(离线的验证者不出块)
+func (n *Network) SetOnline(validator common.Address, online bool) error {
+
+	node, ok := n.nodes[validator]
+	if !ok {
+		return errUnknownNode
+	}
+	node.online = online
+	return nil
+}
+
+//当前的虚拟时间
+func (n *Network) Now() int64 {
+	return n.now
+}
+
+//当前的链头区块
+func (n *Network) Head() *types.Block {
+	return n.chain.head
+}
+
+//区块所在的周期
+func (n *Network) EpochOf(time int64) uint64 {
+	return dpos.EpochOf(time, n.chain.genesis.Time().Int64())
+}
+
+//当前链头区块的验证者(按出块顺序)
+func (n *Network) Validators() ([]common.Address, error) {
+
+	dposContext, err := types.NewDposContextFromProto(n.db, n.chain.head.Header().DposProto)
+	if err != nil {
+		return nil, err
+	}
+	return dposContext.GetEpochTrie()
+}
+
+//每个验证者产生的区块数量
+func (n *Network) Produced() map[common.Address]int {
+
+	produced := make(map[common.Address]int, len(n.produced))
+	for validator, count := range n.produced {
+		produced[validator] = count
+	}
+	return produced
+}
+
+//推进虚拟时间并运行指定数量的时间片
+func (n *Network) Run(slots int) ([]Slot, error) {
+
+	results := make([]Slot, 0, slots)
+	for i := 0; i < slots; i++ {
+		slot, err := n.step()
+		if err != nil {
+			return results, err
+		}
+		results = append(results, slot)
+	}
+	return results, nil
+}
+
+//运行下一个时间片：找出认为该时间片属于自己的验证者，由其产生并签名区块
+func (n *Network) step() (Slot, error) {
+
+	n.now += protocol.BlockInterval
+	genesisTime := n.chain.genesis.Time().Int64()
+	head := n.chain.head
+
+	slot := Slot{Time: n.now, Epoch: n.EpochOf(n.now)}
+	dposContext, err := types.NewDposContextFromProto(n.db, head.Header().DposProto)
+	if err != nil {
+		return slot, err
+	}
+	if slot.Producer, err = dposContext.GetProducer(n.now, genesisTime); err != nil {
+		return slot, fmt.Errorf("slot %d: %v", n.now, err)
+	}
+
+	//与矿工相同的检查，同一时间片只能有一个验证者出块
+	var producer *node
+	for validator, node := range n.nodes {
+		if !node.online {
+			continue
+		}
+		if node.engine.CheckDeadline(head, n.now, genesisTime) != nil || node.engine.CheckProducer(head, n.now, genesisTime) != nil {
+			continue
+		}
+		if producer != nil || validator != slot.Producer {
+			return slot, fmt.Errorf("slot %d: %v", n.now, errDoubleProducer)
+		}
+		producer = node
+	}
+	if producer == nil {
+		slot.Missed = true
+		return slot, nil
+	}
+
+	block, err := n.seal(producer, slot)
+	if err != nil {
+		return slot, fmt.Errorf("slot %d: %v", n.now, err)
+	}
+	n.chain.insert(block)
+	n.produced[slot.Producer]++
+	slot.Number = block.NumberU64()
+	return slot, nil
+}
+
+//产生并签名区块，周期的第一个区块中选出新的验证者
+func (n *Network) seal(producer *node, slot Slot) (*types.Block, error) {
+
+	parent := n.chain.head.Header()
+	dposContext, err := types.NewDposContextFromProto(n.db, parent.DposProto)
+	if err != nil {
+		return nil, err
+	}
+	if slot.Epoch > n.EpochOf(parent.Time.Int64()) {
+		if err := n.electInto(dposContext); err != nil {
+			return nil, err
+		}
+	}
+	dposProto, err := dposContext.CommitTo(n.db)
+	if err != nil {
+		return nil, err
+	}
+
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		UncleHash:  types.CalcUncleHash(nil),
+		Validator:  slot.Producer,
+		Coinbase:   slot.Producer,
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		Time:       big.NewInt(slot.Time),
+		Difficulty: big.NewInt(1),
+		GasLimit:   new(big.Int),
+		GasUsed:    new(big.Int),
+		Extra:      make([]byte, protocol.ExtraVanity+protocol.ExtraSeal),
+		DposProto:  dposProto,
+		BokerProto: parent.BokerProto,
+	}
+	return producer.engine.Seal(n.chain, types.NewBlockWithHeader(header), slot.Time, nil)
+}
+
+//按选举函数选出验证者并写入Dpos对象
+func (n *Network) electInto(dposContext *types.DposContext) error {
+
+	elected := n.elect(n.Candidates())
+	if len(elected) == 0 {
+		return errNoCandidates
+	}
+	validators := make([]common.Address, len(elected))
+	votes := make([]*big.Int, len(elected))
+	for i, candidate := range elected {
+		validators[i], votes[i] = candidate.Address, candidate.Votes
+	}
+	return dposContext.SetValidatorVotes(validators, votes)
+}
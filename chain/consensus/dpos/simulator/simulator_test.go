@@ -0,0 +1,136 @@
+package simulator
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+)
+
+// newNetwork creates a simulated network of candidates with the given votes.
+func newNetwork(t *testing.T, elect ElectFn, votes ...int64) *Network {
+	candidates := make([]Candidate, len(votes))
+	for i, v := range votes {
+		candidates[i] = Candidate{Votes: big.NewInt(v)}
+	}
+	network, err := New(Config{Candidates: candidates, Elect: elect})
+	if err != nil {
+		t.Fatalf("failed to create network: %v", err)
+	}
+	return network
+}
+
+// addresses returns the addresses of the candidates at the given indexes.
+func addresses(network *Network, indexes ...int) []common.Address {
+	candidates := network.Candidates()
+	result := make([]common.Address, len(indexes))
+	for i, index := range indexes {
+		result[i] = candidates[index].Address
+	}
+	return result
+}
+
+// Tests that validators produce in round-robin order of the epoch set, one block
+// per slot, and that the deployed registration order ignores the votes.
+func TestRoundRobin(t *testing.T) {
+	network := newNetwork(t, nil, 1, 3, 2)
+
+	validators, err := network.Validators()
+	if err != nil {
+		t.Fatalf("failed to get validators: %v", err)
+	}
+	if want := addresses(network, 0, 1, 2); !reflect.DeepEqual(validators, want) {
+		t.Fatalf("validators mismatch: have %x, want %x", validators, want)
+	}
+	slots, err := network.Run(30)
+	if err != nil {
+		t.Fatalf("failed to run network: %v", err)
+	}
+	for i, slot := range slots {
+		if want := validators[(i+1)%len(validators)]; slot.Producer != want {
+			t.Errorf("slot %d: producer mismatch: have %x, want %x", i, slot.Producer, want)
+		}
+		if slot.Missed || slot.Number != uint64(i+1) {
+			t.Errorf("slot %d: block mismatch: have #%d (missed %v), want #%d", i, slot.Number, slot.Missed, i+1)
+		}
+	}
+	for _, validator := range validators {
+		if produced := network.Produced()[validator]; produced != 10 {
+			t.Errorf("validator %x produced %d blocks, want 10", validator, produced)
+		}
+	}
+	if head := network.Head(); head.NumberU64() != 30 || head.Time().Int64() != network.Now() {
+		t.Errorf("head mismatch: have #%d at %d, want #30 at %d", head.NumberU64(), head.Time(), network.Now())
+	}
+}
+
+// Tests that the slots of an offline validator are missed without shifting the
+// schedule of the others.
+func TestOfflineValidator(t *testing.T) {
+	network := newNetwork(t, nil, 1, 1, 1)
+	offline := network.Candidates()[1].Address
+	network.SetOnline(offline, false)
+
+	slots, err := network.Run(12)
+	if err != nil {
+		t.Fatalf("failed to run network: %v", err)
+	}
+	number := uint64(0)
+	for i, slot := range slots {
+		if slot.Missed != (slot.Producer == offline) {
+			t.Errorf("slot %d: producer %x missed %v", i, slot.Producer, slot.Missed)
+		}
+		if !slot.Missed {
+			if number++; slot.Number != number {
+				t.Errorf("slot %d: number mismatch: have %d, want %d", i, slot.Number, number)
+			}
+		}
+	}
+	if produced := network.Produced(); produced[offline] != 0 || len(produced) != 2 {
+		t.Errorf("production mismatch: %v", produced)
+	}
+}
+
+// Tests that a vote-weighted election picks the top candidates for the genesis
+// epoch and that vote changes only take effect at the next epoch boundary.
+func TestVoteWeightedEpochs(t *testing.T) {
+	network := newNetwork(t, TopVotes(2), 1, 3, 2)
+
+	validators, _ := network.Validators()
+	if want := addresses(network, 1, 2); !reflect.DeepEqual(validators, want) {
+		t.Fatalf("genesis validators mismatch: have %x, want %x", validators, want)
+	}
+	if err := network.Vote(network.Candidates()[0].Address, big.NewInt(10)); err != nil {
+		t.Fatalf("failed to vote: %v", err)
+	}
+
+	epochSlots := int(protocol.EpochInterval / protocol.BlockInterval)
+	slots, err := network.Run(epochSlots + 4)
+	if err != nil {
+		t.Fatalf("failed to run network: %v", err)
+	}
+	for i, slot := range slots {
+		if slot.Epoch == 0 {
+			if slot.Producer == network.Candidates()[0].Address {
+				t.Fatalf("slot %d: vote applied before the epoch boundary", i)
+			}
+			continue
+		}
+		// The first block of the epoch is still scheduled by the previous set
+		// and elects the new one for the following slots.
+		if slot.Epoch != 1 || i+1 < epochSlots {
+			t.Fatalf("slot %d: epoch mismatch: have %d", i, slot.Epoch)
+		}
+	}
+	validators, _ = network.Validators()
+	if want := addresses(network, 0, 1); !reflect.DeepEqual(validators, want) {
+		t.Fatalf("epoch validators mismatch: have %x, want %x", validators, want)
+	}
+	for i, slot := range slots[epochSlots-1:] {
+		if want := validators[(epochSlots+i)%2]; i > 0 && slot.Producer != want {
+			t.Errorf("epoch slot %d: producer mismatch: have %x, want %x", i, slot.Producer, want)
+		}
+	}
+}
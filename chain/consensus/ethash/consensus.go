@@ -568,7 +568,9 @@ func AccumulateRewards(config *params.ChainConfig,
 
 	//计算给每个交易产生用户应该分配币的数量
 	var singleReward *big.Int = big.NewInt(0)
-	singleReward.Div(userMultiple, big.NewInt(int64(len(txs))))
+	if len(txs) > 0 {
+		singleReward.Div(userMultiple, big.NewInt(int64(len(txs))))
+	}
 	//singleReward = math.Min()
 
 	//对于交易发起用户进行分币
@@ -79,6 +79,11 @@ func (s *BokerInterfaceService) createTransactOpts() *bind.TransactOpts {
 
 func (s *BokerInterfaceService) Start() {
 
+	//只读副本不自动提交投票转换、黑名单以及Gas分配交易
+	if s.ethereum.IsReadOnly() {
+		log.Info("Read-only node, system contract transactions are not submitted")
+		return
+	}
 	s.start = true
 
 	go s.pollVote()
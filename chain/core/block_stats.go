@@ -0,0 +1,222 @@
+package core
+
+import (
+	"encoding/binary"
+	"sort"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+//一天的秒数
+const secondsPerDay = 24 * 60 * 60
+
+var (
+	blockStatsPrefix  = []byte("S")  // blockStatsPrefix + num (uint64 big endian) + hash -> cumulative block statistics
+	dailyStatsPrefix  = []byte("sD") // dailyStatsPrefix + day (uint64 big endian) -> statistics of the canonical blocks of the day
+	activeAddrPrefix  = []byte("sA") // activeAddrPrefix + day (uint64 big endian) + address -> address active during the day
+	activeAddrPresent = []byte{1}
+
+	txTypeStatsPrefix = []byte("sT") // txTypeStatsPrefix + num (uint64 big endian) + hash -> cumulative statistics per transaction type
+)
+
+//得到时间戳所在的UTC日期(从1970-01-01开始的天数)
+func StatsDay(time uint64) uint64 {
+	return time / secondsPerDay
+}
+
+func encodeStatsDay(day uint64) []byte {
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, day)
+	return enc
+}
+
+//区块中扩展交易的数据字节数
+func extraBytes(block *types.Block) uint64 {
+	var size uint64
+	for _, tx := range block.Transactions() {
+		if tx.Major() == protocol.Extra {
+			size += uint64(len(tx.Extra()))
+		}
+	}
+	return size
+}
+
+// GetBlockStats retrieves the cumulative statistics recorded for a block.
+func GetBlockStats(db DatabaseReader, hash common.Hash, number uint64) *types.BlockStats {
+	data, _ := db.Get(append(append(blockStatsPrefix, encodeBlockNumber(number)...), hash[:]...))
+	if len(data) == 0 {
+		return nil
+	}
+	stats := new(types.BlockStats)
+	if err := rlp.DecodeBytes(data, stats); err != nil {
+		log.Error("Invalid block stats RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return stats
+}
+
+// WriteBlockStats adds the block to the cumulative statistics of its parent
+// and stores the result. Blocks whose parent has no statistics (e.g. the chain
+// was imported before the statistics existed) start a new accumulation.
+func WriteBlockStats(db ethdb.Putter, parent *types.BlockStats, block *types.Block) error {
+	stats := &types.BlockStats{First: block.NumberU64()}
+	if parent != nil {
+		*stats = *parent
+	}
+	stats.Txs += uint64(len(block.Transactions()))
+	stats.GasUsed += block.GasUsed().Uint64()
+	stats.GasLimit += block.GasLimit().Uint64()
+	stats.ExtraBytes += extraBytes(block)
+
+	data, err := rlp.EncodeToBytes(stats)
+	if err != nil {
+		return err
+	}
+	key := append(append(blockStatsPrefix, encodeBlockNumber(block.NumberU64())...), block.Hash().Bytes()...)
+	if err := db.Put(key, data); err != nil {
+		log.Crit("Failed to store block stats", "err", err)
+	}
+	return nil
+}
+
+// DeleteBlockStats removes the statistics associated with a block hash.
+func DeleteBlockStats(db DatabaseDeleter, hash common.Hash, number uint64) {
+	db.Delete(append(append(blockStatsPrefix, encodeBlockNumber(number)...), hash.Bytes()...))
+}
+
+// GetTxTypeStats retrieves the cumulative statistics per transaction type
+// recorded for a block.
+func GetTxTypeStats(db DatabaseReader, hash common.Hash, number uint64) *types.TxTypeStats {
+	data, _ := db.Get(append(append(txTypeStatsPrefix, encodeBlockNumber(number)...), hash[:]...))
+	if len(data) == 0 {
+		return nil
+	}
+	stats := new(types.TxTypeStats)
+	if err := rlp.DecodeBytes(data, stats); err != nil {
+		log.Error("Invalid tx type stats RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return stats
+}
+
+// WriteTxTypeStats adds the gas and bytes used by the transactions of the
+// block, grouped by transaction type, to the cumulative statistics of its
+// parent and stores the result. Like the block statistics, blocks whose parent
+// has no statistics start a new accumulation.
+func WriteTxTypeStats(db ethdb.Putter, parent *types.TxTypeStats, block *types.Block, receipts types.Receipts) error {
+	stats := &types.TxTypeStats{First: block.NumberU64()}
+	if parent != nil {
+		stats.First = parent.First
+	}
+	usages := make(map[[2]uint8]*types.TxTypeUsage)
+	if parent != nil {
+		for _, usage := range parent.Types {
+			copied := *usage
+			usages[[2]uint8{uint8(usage.Major), uint8(usage.Minor)}] = &copied
+		}
+	}
+	for i, tx := range block.Transactions() {
+		key := [2]uint8{uint8(tx.Major()), uint8(tx.Minor())}
+		usage, ok := usages[key]
+		if !ok {
+			usage = &types.TxTypeUsage{Major: tx.Major(), Minor: tx.Minor()}
+			usages[key] = usage
+		}
+		usage.Txs++
+		usage.Bytes += uint64(tx.Size())
+		if i < len(receipts) && receipts[i].GasUsed != nil {
+			usage.GasUsed += receipts[i].GasUsed.Uint64()
+		}
+	}
+	for _, usage := range usages {
+		stats.Types = append(stats.Types, usage)
+	}
+	sort.Slice(stats.Types, func(i, j int) bool {
+		if stats.Types[i].Major != stats.Types[j].Major {
+			return stats.Types[i].Major < stats.Types[j].Major
+		}
+		return stats.Types[i].Minor < stats.Types[j].Minor
+	})
+
+	data, err := rlp.EncodeToBytes(stats)
+	if err != nil {
+		return err
+	}
+	key := append(append(txTypeStatsPrefix, encodeBlockNumber(block.NumberU64())...), block.Hash().Bytes()...)
+	if err := db.Put(key, data); err != nil {
+		log.Crit("Failed to store tx type stats", "err", err)
+	}
+	return nil
+}
+
+// DeleteTxTypeStats removes the statistics per transaction type associated
+// with a block hash.
+func DeleteTxTypeStats(db DatabaseDeleter, hash common.Hash, number uint64) {
+	db.Delete(append(append(txTypeStatsPrefix, encodeBlockNumber(number)...), hash.Bytes()...))
+}
+
+// GetDailyStats retrieves the statistics of the canonical blocks of a day.
+func GetDailyStats(db DatabaseReader, day uint64) *types.DailyStats {
+	data, _ := db.Get(append(dailyStatsPrefix, encodeStatsDay(day)...))
+	if len(data) == 0 {
+		return nil
+	}
+	stats := new(types.DailyStats)
+	if err := rlp.DecodeBytes(data, stats); err != nil {
+		log.Error("Invalid daily stats RLP", "day", day, "err", err)
+		return nil
+	}
+	return stats
+}
+
+// WriteDailyStats adds a block that became canonical to the statistics of its
+// day. Blocks later dropped by a reorg are not subtracted.
+func WriteDailyStats(db ethdb.Database, batch ethdb.Putter, signer types.Signer, block *types.Block) error {
+	day := StatsDay(block.Time().Uint64())
+	stats := GetDailyStats(db, day)
+	if stats == nil {
+		stats = new(types.DailyStats)
+	}
+	stats.Blocks++
+	stats.Txs += uint64(len(block.Transactions()))
+	stats.ExtraBytes += extraBytes(block)
+
+	seen := make(map[common.Address]bool)
+	for _, tx := range block.Transactions() {
+		addresses := make([]common.Address, 0, 2)
+		if from, err := types.Sender(signer, tx); err == nil {
+			addresses = append(addresses, from)
+		}
+		if tx.To() != nil {
+			addresses = append(addresses, *tx.To())
+		}
+		for _, address := range addresses {
+			if seen[address] {
+				continue
+			}
+			seen[address] = true
+			key := append(append(activeAddrPrefix, encodeStatsDay(day)...), address.Bytes()...)
+			if ok, _ := db.Has(key); ok {
+				continue
+			}
+			if err := batch.Put(key, activeAddrPresent); err != nil {
+				log.Crit("Failed to store active address", "err", err)
+			}
+			stats.ActiveAddresses++
+		}
+	}
+
+	data, err := rlp.EncodeToBytes(stats)
+	if err != nil {
+		return err
+	}
+	if err := batch.Put(append(dailyStatsPrefix, encodeStatsDay(day)...), data); err != nil {
+		log.Crit("Failed to store daily stats", "err", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/core/vm"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+// Tests that importing blocks accumulates the block statistics on top of the
+// parent and adds the canonical blocks to the statistics of their day.
+func TestBlockStatsImport(t *testing.T) {
+	var (
+		db, _   = ethdb.NewMemDatabase()
+		key, _  = crypto.GenerateKey()
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &Genesis{Config: params.TestChainConfig, Alloc: GenesisAlloc{addr: {Balance: big.NewInt(1000000000)}}}
+		genesis = gspec.MustCommit(db)
+	)
+	chain, err := NewBlockChain(db, gspec.Config, newChainMakerFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	//区块i包含i笔转账
+	blocks, _ := GenerateChain(gspec.Config, genesis, db, 3, nil, func(i int, gen *BlockGen) {
+		gen.OffsetTime(0)
+		for j := 0; j < i; j++ {
+			tx := types.NewTransaction(protocol.Normal, protocol.NormalCall, gen.TxNonce(addr), common.Address{0x01}, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil)
+			tx, _ = types.SignTx(tx, types.HomesteadSigner{}, key)
+			gen.AddTx(tx, nil)
+		}
+	})
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+
+	//创世区块没有统计，因此从区块1开始累计
+	head := blocks[len(blocks)-1]
+	stats := GetBlockStats(db, head.Hash(), head.NumberU64())
+	if stats == nil {
+		t.Fatalf("head statistics missing")
+	}
+	if stats.First != 1 || stats.Txs != 3 || stats.GasUsed != 3*21000 {
+		t.Errorf("head statistics mismatch: %+v", stats)
+	}
+	if stats := GetBlockStats(db, blocks[1].Hash(), 2); stats == nil || stats.Txs != 1 {
+		t.Errorf("block 2 statistics mismatch: %+v", stats)
+	}
+
+	daily := GetDailyStats(db, StatsDay(head.Time().Uint64()))
+	if daily == nil || daily.Blocks != 3 || daily.Txs != 3 || daily.ActiveAddresses != 2 {
+		t.Errorf("daily statistics mismatch: %+v", daily)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common/math"
 	"github.com/Tinachain/Tina/chain/consensus"
 	"github.com/Tinachain/Tina/chain/core/state"
@@ -58,6 +59,44 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 
 		return fmt.Errorf("transaction root hash mismatch: have %x, want %x", hash, header.TxHash)
 	}
+
+	return checkBlockExtra(v.config, block)
+}
+
+//扩展交易携带的数据累计不能超过每个区块的上限（分叉之前的区块不检查）
+func checkBlockExtra(config *params.ChainConfig, block *types.Block) error {
+
+	if !config.IsExtraLimit(block.Number()) {
+		return nil
+	}
+	if size, limit := BlockExtraSize(block.Transactions()), config.BlockExtraLimit(); size > limit {
+		return fmt.Errorf("%v: have %d, limit %d", ErrBlockExtraLimit, size, limit)
+	}
+	return nil
+}
+
+//得到区块中扩展交易携带数据累计的字节数
+func BlockExtraSize(txs types.Transactions) uint64 {
+
+	var size uint64
+	for _, tx := range txs {
+		size += tx.ExtraPayloadSize()
+	}
+	return size
+}
+
+//检查扩展交易携带的数据是否超过链配置中对应类型以及每个区块的上限
+func CheckExtraPayload(config *params.ChainConfig, tx *types.Transaction) error {
+
+	size := tx.ExtraPayloadSize()
+	switch {
+	case tx.Minor() == protocol.Word && size > config.WordSizeLimit():
+		return ErrOverExtraData
+	case tx.Minor() == protocol.Data && size > config.DataSizeLimit():
+		return ErrOverExtraData
+	case size > config.BlockExtraLimit():
+		return ErrOverExtraData
+	}
 	return nil
 }
 
@@ -27,6 +27,7 @@ import (
 	"github.com/Tinachain/Tina/chain/rlp"
 	"github.com/Tinachain/Tina/chain/trie"
 	"github.com/hashicorp/golang-lru"
+	"gopkg.in/karalabe/cookiejar.v2/collections/prque"
 )
 
 var (
@@ -48,6 +49,7 @@ type BlockChain struct {
 	hc               *HeaderChain
 	chainDb          ethdb.Database
 	rmLogsFeed       event.Feed
+	rmTxFeed         event.Feed
 	chainFeed        event.Feed
 	chainSideFeed    event.Feed
 	chainHeadFeed    event.Feed
@@ -73,8 +75,19 @@ type BlockChain struct {
 	processor        Processor        //区块处理器接口
 	validator        Validator        //区块验证接口
 	vmConfig         vm.Config        //虚拟机配置
+	internalTxIndex  bool             //是否在导入区块时记录内部交易
+	tokenIndex       bool             //是否在导入区块时记录代币转账
+	nameIndex        bool             //是否在导入区块时按名称记录交易
+	richListSize     int              //余额排行榜的账号数量(0为不维护排行榜)
 	badBlocks        *lru.Cache       // Bad block cache
+	importStats      *lru.Cache       //最近导入区块的各阶段耗时
 	boker            bokerapi.Api     //Tina链的接口类
+
+	triedb         *trie.NodeDatabase //状态树节点的内存写入层，提交的状态先保存在内存中再批量写入磁盘
+	triegc         *prque.Prque       //按区块高度排列的待回收状态根
+	trieDirtyLimit common.StorageSize //脏节点缓存上限，超过后在后台写入磁盘
+	trieCommitted  time.Time          //最近一次把完整状态写入磁盘的时间
+	archive        bool               //归档节点保留全部历史状态，不回收任何状态
 }
 
 //返回初始化后的块链， 它初始化默认的以太坊验证器和处理器
@@ -90,19 +103,26 @@ func NewBlockChain(chainDb ethdb.Database,
 	blockCache, _ := lru.New(blockCacheLimit)
 	futureBlocks, _ := lru.New(maxFutureBlocks)
 	badBlocks, _ := lru.New(badBlockLimit)
+	importStats, _ := lru.New(importStatsLimit)
+	triedb := state.NewNodeDatabase(chainDb)
 
 	bc := &BlockChain{
-		config:       config,
-		chainDb:      chainDb,
-		stateCache:   state.NewDatabase(chainDb),
-		quit:         make(chan struct{}),
-		bodyCache:    bodyCache,
-		bodyRLPCache: bodyRLPCache,
-		blockCache:   blockCache,
-		futureBlocks: futureBlocks,
-		engine:       engine,
-		vmConfig:     vmConfig,
-		badBlocks:    badBlocks,
+		config:         config,
+		chainDb:        chainDb,
+		stateCache:     state.NewDatabaseWithNodes(triedb),
+		quit:           make(chan struct{}),
+		bodyCache:      bodyCache,
+		bodyRLPCache:   bodyRLPCache,
+		blockCache:     blockCache,
+		futureBlocks:   futureBlocks,
+		engine:         engine,
+		vmConfig:       vmConfig,
+		badBlocks:      badBlocks,
+		importStats:    importStats,
+		triedb:         triedb,
+		triegc:         prque.New(),
+		trieDirtyLimit: DefaultTrieDirtyCache * 1024 * 1024,
+		trieCommitted:  time.Now(),
 	}
 	log.Info("New Block Chain")
 
@@ -176,9 +196,12 @@ func (bc *BlockChain) loadLastState() error {
 
 	// Make sure the state associated with the block is available
 	if _, err := state.New(currentBlock.Root(), bc.stateCache); err != nil {
-		// Dangling block without a state associated, init from scratch
-		log.Warn("Head state missing, resetting chain", "number", currentBlock.Number(), "hash", currentBlock.Hash())
-		return bc.Reset()
+		//最近区块的状态可能在节点退出时还没有写入磁盘，回退到最近一个状态完整的区块
+		log.Warn("Head state missing, repairing chain", "number", currentBlock.Number(), "hash", currentBlock.Hash())
+		if currentBlock = bc.repairHeadState(currentBlock); currentBlock == nil {
+			// Dangling chain without any state associated, init from scratch
+			return bc.Reset()
+		}
 	}
 
 	// Everything seems to be fine, set as the head block
@@ -237,10 +260,8 @@ func (bc *BlockChain) SetHead(head uint64) error {
 		bc.currentBlock = bc.GetBlock(currentHeader.Hash(), currentHeader.Number.Uint64())
 	}
 	if bc.currentBlock != nil {
-		if _, err := state.New(bc.currentBlock.Root(), bc.stateCache); err != nil {
-			// Rewound state missing, rolled back to before pivot, reset to genesis
-			bc.currentBlock = nil
-		}
+		//回退目标的状态可能已经被回收，继续回退到最近一个状态完整的区块(快速同步的起点之前则回到创世区块)
+		bc.currentBlock = bc.repairHeadState(bc.currentBlock)
 	}
 
 	// Rewind the fast block in a simpleton way to the target head
@@ -372,6 +393,13 @@ func (bc *BlockChain) SetBoker(boker bokerapi.Api) {
 	bc.processor.SetBoker(boker)
 }
 
+//开启内部交易索引，之后导入的区块会记录合约执行过程中产生的内部交易
+func (bc *BlockChain) EnableInternalTxIndex() {
+	bc.procmu.Lock()
+	defer bc.procmu.Unlock()
+	bc.internalTxIndex = true
+}
+
 //得到Tina链接口
 func (bc *BlockChain) Boker() bokerapi.Api {
 	bc.procmu.RLock()
@@ -610,6 +638,7 @@ func (bc *BlockChain) Stop() {
 	atomic.StoreInt32(&bc.procInterrupt, 1)
 
 	bc.wg.Wait()
+	bc.flushTrieCache()
 	log.Info("Blockchain manager stopped")
 }
 
@@ -746,6 +775,9 @@ func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain [
 		if err := WriteTxLookupEntries(batch, block); err != nil {
 			return i, fmt.Errorf("failed to write lookup metadata: %v", err)
 		}
+		if err := bc.writeContractMetaLookups(batch, block); err != nil {
+			return i, fmt.Errorf("failed to write contract metadata lookups: %v", err)
+		}
 		stats.processed++
 
 		if batch.ValueSize() >= ethdb.IdealBatchSize {
@@ -788,6 +820,11 @@ func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain [
 
 //将区块和状态信息写入数据库
 func (bc *BlockChain) WriteBlockAndState(block *types.Block, receipts []*types.Receipt, state *state.StateDB) (status WriteStatus, err error) {
+	return bc.writeBlockAndState(block, receipts, state, nil)
+}
+
+//写入区块以及状态，timings不为空时记录上下文提交、状态树提交以及数据库写入的耗时
+func (bc *BlockChain) writeBlockAndState(block *types.Block, receipts []*types.Receipt, state *state.StateDB, timings *importTimings) (status WriteStatus, err error) {
 	bc.wg.Add(1)
 	defer bc.wg.Done()
 
@@ -800,6 +837,12 @@ func (bc *BlockChain) WriteBlockAndState(block *types.Block, receipts []*types.R
 	bc.mu.Lock()
 	defer bc.mu.Unlock()
 
+	//提交状态之前记录区块中修改过的账号
+	var dirty []common.Address
+	if bc.richListSize > 0 {
+		dirty = state.DirtyAccounts()
+	}
+
 	//链上当前的总难度
 	localTd := bc.GetTd(bc.currentBlock.Hash(), bc.currentBlock.NumberU64())
 	//新挖出来的区块所对应的总难度
@@ -816,20 +859,39 @@ func (bc *BlockChain) WriteBlockAndState(block *types.Block, receipts []*types.R
 		return NonStatTy, err
 	}
 
+	cstart := time.Now()
 	if _, err := block.DposContext.CommitTo(batch); err != nil {
 		return NonStatTy, err
 	}
 	if _, err := block.BokerContext.CommitTo(batch); err != nil {
 		return NonStatTy, err
 	}
-	if _, err := state.CommitTo(batch, bc.config.IsEIP158(block.Number())); err != nil {
+	tstart := time.Now()
+	//状态只提交到内存中的节点缓存，由retainState决定何时写入磁盘
+	root, err := state.CommitTo(bc.triedb, bc.config.IsEIP158(block.Number()))
+	if err != nil {
 		return NonStatTy, err
 	}
+	if timings != nil {
+		timings.context, timings.trie = tstart.Sub(cstart), time.Since(tstart)
+	}
 
 	//将所有交易执行的回执写入数据库
 	if err := WriteBlockReceipts(batch, block.Hash(), block.NumberU64(), receipts); err != nil {
 		return NonStatTy, err
 	}
+	if err := WriteBlockRewards(batch, block.Hash(), block.NumberU64(), block.BokerContext.Rewards()); err != nil {
+		return NonStatTy, err
+	}
+	if err := WriteContractHistory(bc.chainDb, batch, block, block.BokerContext.ContractRecords()); err != nil {
+		return NonStatTy, err
+	}
+	if err := WriteBlockStats(batch, GetBlockStats(bc.chainDb, block.ParentHash(), block.NumberU64()-1), block); err != nil {
+		return NonStatTy, err
+	}
+	if err := WriteTxTypeStats(batch, GetTxTypeStats(bc.chainDb, block.ParentHash(), block.NumberU64()-1), block, receipts); err != nil {
+		return NonStatTy, err
+	}
 
 	// If the total difficulty is higher than our known, add it to the canonical chain
 	// Second clause in the if statement reduces the vulnerability to selfish mining.
@@ -855,6 +917,12 @@ func (bc *BlockChain) WriteBlockAndState(block *types.Block, receipts []*types.R
 		if err := WriteTxLookupEntries(batch, block); err != nil {
 			return NonStatTy, err
 		}
+		if err := bc.writeContractMetaLookups(batch, block); err != nil {
+			return NonStatTy, err
+		}
+		if err := WriteDailyStats(bc.chainDb, batch, types.MakeSigner(bc.config, block.Number()), block); err != nil {
+			return NonStatTy, err
+		}
 		// Write hash preimages
 		if err := WritePreimages(bc.chainDb, block.NumberU64(), state.Preimages()); err != nil {
 			return NonStatTy, err
@@ -863,13 +931,23 @@ func (bc *BlockChain) WriteBlockAndState(block *types.Block, receipts []*types.R
 	} else {
 		status = SideStatTy
 	}
+	wstart := time.Now()
 	if err := batch.Write(); err != nil {
 		return NonStatTy, err
 	}
+	if timings != nil {
+		timings.write = time.Since(wstart)
+	}
+	bc.retainState(root, block.NumberU64())
 
 	// Set new head.
 	if status == CanonStatTy {
 		bc.insert(block)
+		if bc.richListSize > 0 {
+			if err := bc.updateRichList(block, dirty, state); err != nil {
+				log.Warn("Failed to update rich list", "number", block.NumberU64(), "err", err)
+			}
+		}
 	}
 	bc.futureBlocks.Remove(block.Hash())
 	return status, nil
@@ -1007,16 +1085,38 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 		if err != nil {
 			return i, events, coalescedLogs, err
 		}
+		block.BokerContext, err = types.NewBokerContextFromProto(bc.chainDb, parent.Header().BokerProto)
+		if err != nil {
+			return i, events, coalescedLogs, err
+		}
 		state, err := state.New(parent.Root(), bc.stateCache)
 		if err != nil {
 			return i, events, coalescedLogs, err
 		}
 		// Process block using the parent state as reference point.
-		receipts, logs, usedGas, err := bc.processor.Process(block, state, bc.vmConfig)
+		vmConfig := bc.vmConfig
+		var tracer *InternalTxTracer
+		if bc.internalTxIndex {
+			tracer = NewInternalTxTracer()
+			vmConfig.Debug, vmConfig.Tracer = true, tracer
+		}
+		//预先恢复交易的签名者(结果缓存在交易中)，单独统计签名恢复的耗时
+		timings := new(importTimings)
+		sstart := time.Now()
+		signer := types.MakeSigner(bc.config, block.Number())
+		for _, tx := range block.Transactions() {
+			types.Sender(signer, tx)
+		}
+		pstart := time.Now()
+		timings.senders = pstart.Sub(sstart)
+
+		receipts, logs, usedGas, err := bc.processor.Process(block, state, vmConfig)
 		if err != nil {
 			bc.reportBlock(block, receipts, err)
 			return i, events, coalescedLogs, err
 		}
+		vstart := time.Now()
+		timings.execution = vstart.Sub(pstart)
 
 		// Validate the state using the default validator
 		err = bc.Validator().ValidateState(block, parent, state, receipts, usedGas)
@@ -1039,13 +1139,31 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 				return i, events, coalescedLogs, err
 			}
 		}
+		timings.validation = time.Since(vstart)
 
 		// Validate the dpos state using the default validator
 		// Write the block to the chain and get the status.
-		status, err := bc.WriteBlockAndState(block, receipts, state)
+		status, err := bc.writeBlockAndState(block, receipts, state, timings)
 		if err != nil {
 			return i, events, coalescedLogs, err
 		}
+		if tracer != nil {
+			if err := WriteInternalTxs(bc.chainDb, block, tracer.Results()); err != nil {
+				return i, events, coalescedLogs, err
+			}
+		}
+		if bc.tokenIndex {
+			if err := WriteTokenTransfers(bc.chainDb, block, receipts); err != nil {
+				return i, events, coalescedLogs, err
+			}
+		}
+		if bc.nameIndex {
+			if err := WriteTxNames(bc.chainDb, block); err != nil {
+				return i, events, coalescedLogs, err
+			}
+		}
+		bc.recordImport(block, timings, time.Since(bstart))
+
 		switch status {
 		case CanonStatTy:
 			log.Debug("Inserted new block", "number", block.Number(), "hash", block.Hash(), "uncles", len(block.Uncles()),
@@ -1210,6 +1328,9 @@ func (bc *BlockChain) reorg(oldBlock, newBlock *types.Block) error {
 		if err := WriteTxLookupEntries(bc.chainDb, block); err != nil {
 			return err
 		}
+		if err := bc.writeContractMetaLookups(bc.chainDb, block); err != nil {
+			return err
+		}
 		addedTxs = append(addedTxs, block.Transactions()...)
 	}
 
@@ -1223,6 +1344,15 @@ func (bc *BlockChain) reorg(oldBlock, newBlock *types.Block) error {
 	if len(deletedLogs) > 0 {
 		go bc.rmLogsFeed.Send(RemovedLogsEvent{deletedLogs})
 	}
+	// Return the dropped transactions to the pool for re-mining, except the system
+	// transactions bound to the slot of the producer of the abandoned fork
+	reinject, skipped := reorgTxs(diff)
+	if len(skipped) > 0 {
+		log.Debug("Skipping producer transactions of abandoned fork", "count", len(skipped))
+	}
+	if len(reinject) > 0 {
+		go bc.rmTxFeed.Send(RemovedTransactionEvent{reinject})
+	}
 
 	return nil
 }
@@ -1416,6 +1546,11 @@ func (bc *BlockChain) SubscribeRemovedLogsEvent(ch chan<- RemovedLogsEvent) even
 	return bc.scope.Track(bc.rmLogsFeed.Subscribe(ch))
 }
 
+// SubscribeRemovedTransactionEvent registers a subscription of RemovedTransactionEvent.
+func (bc *BlockChain) SubscribeRemovedTransactionEvent(ch chan<- RemovedTransactionEvent) event.Subscription {
+	return bc.scope.Track(bc.rmTxFeed.Subscribe(ch))
+}
+
 // SubscribeChainEvent registers a subscription of ChainEvent.
 func (bc *BlockChain) SubscribeChainEvent(ch chan<- ChainEvent) event.Subscription {
 	return bc.scope.Track(bc.chainFeed.Subscribe(ch))
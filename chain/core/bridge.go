@@ -0,0 +1,152 @@
+package core
+
+import (
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+//跨链桥事件的日志主题(与外部链上合约的事件签名一致)
+var (
+	BridgeLockTopic    = crypto.Keccak256Hash([]byte("BridgeLock(uint64,address,address,uint256)")) //本链锁定
+	BridgeReleaseTopic = crypto.Keccak256Hash([]byte("BridgeRelease(bytes32,address,uint256)"))     //本链释放
+	BridgeBurnTopic    = crypto.Keccak256Hash([]byte("BridgeBurn(address,address,uint256)"))        //外部链销毁
+)
+
+//锁定次数在跨链桥系统账号中的存储位置
+var bridgeLockCountSlot = common.BigToHash(big.NewInt(0))
+
+//跨链桥系统账号中的存储位置(kind与各字段拼接后的哈希)
+func bridgeSlot(kind string, fields ...[]byte) common.Hash {
+	return crypto.Keccak256Hash(append([][]byte{[]byte(kind)}, fields...)...)
+}
+
+//存储位置偏移offset后的位置
+func bridgeSlotAt(slot common.Hash, offset int64) common.Hash {
+	return common.BigToHash(new(big.Int).Add(slot.Big(), big.NewInt(offset)))
+}
+
+//锁定记录的承诺哈希在跨链桥系统账号中的存储位置，外部链通过该位置的存储证明验证锁定记录，
+//之后的三个位置依次存放锁定账号、接收账号以及锁定数量
+func BridgeLockSlot(nonce uint64) common.Hash {
+	return bridgeSlot("lock", common.BigToHash(new(big.Int).SetUint64(nonce)).Bytes())
+}
+
+//锁定记录的承诺哈希
+func BridgeLockHash(record *protocol.BridgeLockRecord) common.Hash {
+	data, _ := rlp.EncodeToBytes(record)
+	return crypto.Keccak256Hash(data)
+}
+
+//释放交易对应的外部链销毁事件的标识
+func BridgeReleaseId(args *protocol.BridgeReleaseArgs) common.Hash {
+	data, _ := rlp.EncodeToBytes(args)
+	return crypto.Keccak256Hash(data)
+}
+
+//得到锁定的次数(即下一次锁定的序号)
+func GetBridgeLockCount(statedb *state.StateDB) uint64 {
+	return statedb.GetState(protocol.BridgeAddress, bridgeLockCountSlot).Big().Uint64()
+}
+
+//得到指定序号的锁定记录
+func GetBridgeLock(statedb *state.StateDB, nonce uint64) (*protocol.BridgeLockRecord, error) {
+
+	slot := BridgeLockSlot(nonce)
+	if (statedb.GetState(protocol.BridgeAddress, slot) == common.Hash{}) {
+		return nil, protocol.ErrUnknownBridgeLock
+	}
+	return &protocol.BridgeLockRecord{
+		Nonce:     nonce,
+		Sender:    common.BytesToAddress(statedb.GetState(protocol.BridgeAddress, bridgeSlotAt(slot, 1)).Bytes()),
+		Recipient: common.BytesToAddress(statedb.GetState(protocol.BridgeAddress, bridgeSlotAt(slot, 2)).Bytes()),
+		Amount:    statedb.GetState(protocol.BridgeAddress, bridgeSlotAt(slot, 3)).Big(),
+	}, nil
+}
+
+//外部链销毁事件是否已经释放
+func IsBridgeReleased(statedb *state.StateDB, id common.Hash) bool {
+	return statedb.GetState(protocol.BridgeAddress, bridgeSlot("released", id.Bytes())) != common.Hash{}
+}
+
+//验证者是否已经确认过外部链销毁事件
+func HasBridgeApproval(statedb *state.StateDB, id common.Hash, validator common.Address) bool {
+	return statedb.GetState(protocol.BridgeAddress, bridgeSlot("approval", id.Bytes(), validator.Bytes())) != common.Hash{}
+}
+
+//得到外部链销毁事件的确认数
+func GetBridgeApprovals(statedb *state.StateDB, id common.Hash) uint64 {
+	return statedb.GetState(protocol.BridgeAddress, bridgeSlot("approvals", id.Bytes())).Big().Uint64()
+}
+
+//释放需要的确认数(超过验证者数量的三分之二)
+func BridgeRequiredApprovals(validators int) uint64 {
+	return uint64(validators*2/3 + 1)
+}
+
+//设置Nonce，防止系统账号作为空账号被清理
+func touchBridge(statedb *state.StateDB) {
+	if statedb.GetNonce(protocol.BridgeAddress) == 0 {
+		statedb.SetNonce(protocol.BridgeAddress, 1)
+	}
+}
+
+//将资产转入跨链桥系统账号并写入锁定记录
+func applyBridgeLock(statedb *state.StateDB, header *types.Header, sender, recipient common.Address, amount *big.Int) *protocol.BridgeLockRecord {
+
+	touchBridge(statedb)
+	statedb.SubBalance(sender, amount)
+	statedb.AddBalance(protocol.BridgeAddress, amount)
+
+	record := &protocol.BridgeLockRecord{
+		Nonce:     GetBridgeLockCount(statedb),
+		Sender:    sender,
+		Recipient: recipient,
+		Amount:    new(big.Int).Set(amount),
+	}
+	slot := BridgeLockSlot(record.Nonce)
+	statedb.SetState(protocol.BridgeAddress, slot, BridgeLockHash(record))
+	statedb.SetState(protocol.BridgeAddress, bridgeSlotAt(slot, 1), record.Sender.Hash())
+	statedb.SetState(protocol.BridgeAddress, bridgeSlotAt(slot, 2), record.Recipient.Hash())
+	statedb.SetState(protocol.BridgeAddress, bridgeSlotAt(slot, 3), common.BigToHash(record.Amount))
+	statedb.SetState(protocol.BridgeAddress, bridgeLockCountSlot, common.BigToHash(new(big.Int).SetUint64(record.Nonce+1)))
+
+	statedb.AddLog(&types.Log{
+		Address:     protocol.BridgeAddress,
+		Topics:      []common.Hash{BridgeLockTopic, common.BigToHash(new(big.Int).SetUint64(record.Nonce)), sender.Hash(), recipient.Hash()},
+		Data:        common.BigToHash(amount).Bytes(),
+		BlockNumber: header.Number.Uint64(),
+	})
+	return record
+}
+
+//记录验证者的确认，确认数达到要求时从跨链桥系统账号释放资产，返回是否已经释放
+func applyBridgeApproval(statedb *state.StateDB, header *types.Header, validator common.Address, args *protocol.BridgeReleaseArgs, required uint64) bool {
+
+	id := BridgeReleaseId(args)
+	approvals := GetBridgeApprovals(statedb, id) + 1
+
+	touchBridge(statedb)
+	statedb.SetState(protocol.BridgeAddress, bridgeSlot("approval", id.Bytes(), validator.Bytes()), common.BigToHash(common.Big1))
+	statedb.SetState(protocol.BridgeAddress, bridgeSlot("approvals", id.Bytes()), common.BigToHash(new(big.Int).SetUint64(approvals)))
+	if approvals < required {
+		return false
+	}
+
+	statedb.SubBalance(protocol.BridgeAddress, args.Amount)
+	statedb.AddBalance(args.Recipient, args.Amount)
+	statedb.SetState(protocol.BridgeAddress, bridgeSlot("released", id.Bytes()), common.BigToHash(common.Big1))
+
+	statedb.AddLog(&types.Log{
+		Address:     protocol.BridgeAddress,
+		Topics:      []common.Hash{BridgeReleaseTopic, id, args.Recipient.Hash()},
+		Data:        common.BigToHash(args.Amount).Bytes(),
+		BlockNumber: header.Number.Uint64(),
+	})
+	return true
+}
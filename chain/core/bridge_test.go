@@ -0,0 +1,70 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+// Tests that bridge transactions are only accepted from the bridge fork block
+// on, and that a lock is released again once the validators approve the burn.
+func TestBridgeTransaction(t *testing.T) {
+	var (
+		validator = common.HexToAddress("0x01")
+		sender    = common.HexToAddress("0x5e")
+		recipient = common.HexToAddress("0x0e")
+	)
+	env := newBokerTestEnv(t, validator)
+	env.statedb.AddBalance(sender, big.NewInt(1000))
+
+	config := *env.config
+	config.BridgeBlock = big.NewInt(2)
+	env.config = &config
+
+	lock := types.NewBaseTransaction(protocol.SystemBase, protocol.BridgeLock, 0, recipient, big.NewInt(300), nil)
+	if _, err := env.apply(sender, lock); err != protocol.ErrBridgeDisabled {
+		t.Errorf("before fork: have %v, want %v", err, protocol.ErrBridgeDisabled)
+	}
+	if count := GetBridgeLockCount(env.statedb); count != 0 {
+		t.Errorf("lock recorded before fork: count %d", count)
+	}
+
+	env.header.Number = big.NewInt(2)
+	if _, err := env.apply(sender, lock); err != nil {
+		t.Fatalf("failed to lock: %v", err)
+	}
+	record, err := GetBridgeLock(env.statedb, 0)
+	if err != nil {
+		t.Fatalf("failed to read lock: %v", err)
+	}
+	if record.Sender != sender || record.Recipient != recipient || record.Amount.Int64() != 300 {
+		t.Errorf("lock record mismatch: %+v", record)
+	}
+	if balance := env.statedb.GetBalance(protocol.BridgeAddress); balance.Int64() != 300 {
+		t.Errorf("bridge balance mismatch: have %v, want 300", balance)
+	}
+
+	args := &protocol.BridgeReleaseArgs{RemoteTx: common.Hash{0x01}, Recipient: sender, Amount: big.NewInt(200)}
+	data, _ := rlp.EncodeToBytes(args)
+	release := types.NewBaseTransaction(protocol.SystemBase, protocol.BridgeRelease, 0, protocol.BridgeAddress, new(big.Int), data)
+	if _, err := env.apply(sender, release); err != protocol.ErrNotValidator {
+		t.Errorf("non-validator release: have %v, want %v", err, protocol.ErrNotValidator)
+	}
+	if _, err := env.apply(validator, release); err != nil {
+		t.Fatalf("failed to release: %v", err)
+	}
+	if !IsBridgeReleased(env.statedb, BridgeReleaseId(args)) {
+		t.Errorf("burn not released")
+	}
+	if balance := env.statedb.GetBalance(sender); balance.Int64() != 900 {
+		t.Errorf("sender balance mismatch: have %v, want 900", balance)
+	}
+	release = types.NewBaseTransaction(protocol.SystemBase, protocol.BridgeRelease, 1, protocol.BridgeAddress, new(big.Int), data)
+	if _, err := env.apply(validator, release); err != protocol.ErrBridgeReleased {
+		t.Errorf("second release: have %v, want %v", err, protocol.ErrBridgeReleased)
+	}
+}
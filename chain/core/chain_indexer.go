@@ -360,6 +360,34 @@ func (c *ChainIndexer) Sections() (uint64, uint64, common.Hash) {
 	return c.storedSections, c.storedSections*c.sectionSize - 1, c.SectionHead(c.storedSections - 1)
 }
 
+// Rewind drops all processed sections starting with the given one and schedules
+// them for reprocessing, cascading the rollback to the child indexers. It returns
+// the number of sections dropped.
+func (c *ChainIndexer) Rewind(section uint64) uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if section >= c.storedSections {
+		return 0
+	}
+	dropped := c.storedSections - section
+	c.setValidSections(section)
+
+	// Roll back the children the same way a reorg would
+	head := section * c.sectionSize
+	if head < c.cascadedHead {
+		c.cascadedHead = head
+		for _, child := range c.children {
+			child.newHead(c.cascadedHead, true)
+		}
+	}
+	select {
+	case c.update <- struct{}{}:
+	default:
+	}
+	return dropped
+}
+
 // AddChildIndexer adds a child ChainIndexer that can use the output of this one
 func (c *ChainIndexer) AddChildIndexer(indexer *ChainIndexer) {
 	c.lock.Lock()
@@ -234,3 +234,54 @@ func (b *testChainIndexBackend) Commit() error {
 	}
 	return nil
 }
+
+// Tests that rewinding an indexer drops the sections from the given one on,
+// rolls back its children and reprocesses the dropped sections in the background.
+func TestChainIndexerRewind(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	defer db.Close()
+
+	backends := make([]*testChainIndexBackend, 2)
+	for i := range backends {
+		backends[i] = &testChainIndexBackend{t: t, processCh: make(chan uint64, 100)}
+		backends[i].indexer = NewChainIndexer(db, ethdb.NewTable(db, string([]byte{byte(i)})), backends[i], 10, 0, 0, fmt.Sprintf("indexer-%d", i))
+	}
+	backends[0].indexer.AddChildIndexer(backends[1].indexer)
+	defer backends[0].indexer.Close()
+
+	for i := uint64(0); i < 50; i++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(i), DposProto: &types.DposContextProto{}, BokerProto: &types.BokerBackendProto{}}
+		if i > 0 {
+			header.ParentHash = GetCanonicalHash(db, i-1)
+		}
+		WriteHeader(db, header)
+		WriteCanonicalHash(db, header.Hash(), i)
+	}
+	// expect waits until every backend processed the given blocks in order
+	expect := func(from, to uint64) {
+		for _, backend := range backends {
+			for number := from; number < to; number++ {
+				select {
+				case <-time.After(10 * time.Second):
+					t.Fatalf("Expected processed block #%d, got nothing", number)
+				case processed := <-backend.processCh:
+					if processed != number {
+						t.Fatalf("Expected processed block #%d, got #%d", number, processed)
+					}
+				}
+			}
+			backend.stored = 5
+			backend.assertSections()
+		}
+	}
+	backends[0].indexer.newHead(49, false)
+	expect(0, 50)
+
+	if dropped := backends[0].indexer.Rewind(5); dropped != 0 {
+		t.Errorf("rewind past the stored sections: dropped %d, want 0", dropped)
+	}
+	if dropped := backends[0].indexer.Rewind(2); dropped != 3 {
+		t.Errorf("dropped sections mismatch: have %d, want 3", dropped)
+	}
+	expect(20, 50)
+}
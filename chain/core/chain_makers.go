@@ -197,7 +197,7 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, db ethdb.Dat
 		}
 
 		//累计奖励
-		dpos.AccumulateRewards(config, statedb, h, b.uncles, b.txs, bokerContext, boker)
+		dpos.AccumulateRewards(config, statedb, h, b.uncles, b.txs, bokerContext, boker, 0)
 
 		//提交数据
 		root, err := statedb.CommitTo(db, config.IsEIP158(h.Number))
@@ -0,0 +1,283 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+	"github.com/Tinachain/Tina/chain/trie"
+)
+
+//检查点签名哈希的前缀，防止与交易以及区块签名哈希混淆
+var checkpointPrefix = []byte("tina-checkpoint")
+
+var (
+	errCheckpointNotEmpty   = errors.New("database already contains blocks beyond genesis")
+	errCheckpointGenesis    = errors.New("checkpoint belongs to another genesis")
+	errCheckpointUntrusted  = errors.New("checkpoint block does not match the trusted hash")
+	errCheckpointBody       = errors.New("checkpoint block body does not match its header")
+	errCheckpointSignatures = errors.New("checkpoint lacks enough validator signatures")
+)
+
+//周期检查点文件的头部，之后依次是区块状态树(包括合约存储树和合约代码)、Dpos树以及Boker树的全部节点
+type Checkpoint struct {
+	Genesis    common.Hash   //创世区块哈希
+	First      *types.Header //首区块的区块头(Dpos按首区块时间计算确认周期)
+	Block      *types.Block  //周期内的第一个区块
+	Td         *big.Int      //该区块的总难度
+	Signatures [][]byte      //验证者(或其签名账号)对检查点签名哈希的签名[R || S || V]
+}
+
+//验证者需要签名的检查点哈希，包括创世区块、首区块、检查点区块以及总难度，区块哈希中包含了全部树的根哈希
+func (c *Checkpoint) SigHash() common.Hash {
+
+	data, _ := rlp.EncodeToBytes([]interface{}{
+		checkpointPrefix,
+		c.Genesis,
+		c.First.Hash(),
+		c.Block.Hash(),
+		c.Td,
+	})
+	return crypto.Keccak256Hash(data)
+}
+
+//签名了检查点的验证者, 签名者可以是验证者自身或者验证者当前的出块签名账号
+func (c *Checkpoint) Signers(db ethdb.Database) ([]common.Address, error) {
+
+	header := c.Block.Header()
+	dposContext, err := types.NewDposContextFromProto(db, header.DposProto)
+	if err != nil {
+		return nil, err
+	}
+	validators, err := dposContext.GetEpochTrie()
+	if err != nil {
+		return nil, err
+	}
+	bokerContext, err := types.NewBokerContextFromProto(db, header.BokerProto)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[common.Address]common.Address)
+	for _, validator := range validators {
+		keys[validator] = validator
+		keys[bokerContext.SigningKey(validator)] = validator
+	}
+
+	hash := c.SigHash()
+	seen := make(map[common.Address]bool)
+	var signers []common.Address
+	for _, sig := range c.Signatures {
+		if len(sig) != 65 || sig[64] > 1 {
+			continue
+		}
+		pub, err := crypto.Ecrecover(hash[:], sig)
+		if err != nil || len(pub) == 0 || pub[0] != 4 {
+			continue
+		}
+		var signer common.Address
+		copy(signer[:], crypto.Keccak256(pub[1:])[12:])
+		if validator, ok := keys[signer]; ok && !seen[validator] {
+			seen[validator] = true
+			signers = append(signers, validator)
+		}
+	}
+	return signers, nil
+}
+
+//检查点中需要导出的树(Dpos树以及Boker树)的根哈希
+func checkpointRoots(header *types.Header) []common.Hash {
+
+	dpos, boker := header.DposProto, header.BokerProto
+	return []common.Hash{
+		dpos.EpochHash, dpos.ValidatorHash, dpos.VoteHash,
+		boker.SingleHash, boker.ContractsHash, boker.SingleStockHash, boker.StocksHash,
+		boker.OwnerHash, boker.GasPoolHash, boker.GovernanceHash,
+	}
+}
+
+//遍历区块的状态树以及Dpos和Boker树的全部节点，节点缺失时返回错误
+func iterateCheckpoint(db ethdb.Database, header *types.Header, fn func(hash common.Hash) error) error {
+
+	statedb, err := state.New(header.Root, state.NewDatabase(db))
+	if err != nil {
+		return err
+	}
+	it := state.NewNodeIterator(statedb)
+	for it.Next() {
+		if it.Hash != (common.Hash{}) {
+			if err := fn(it.Hash); err != nil {
+				return err
+			}
+		}
+	}
+	if it.Error != nil {
+		return it.Error
+	}
+	for _, root := range checkpointRoots(header) {
+		if root == (common.Hash{}) {
+			continue
+		}
+		t, err := trie.New(root, db)
+		if err != nil {
+			return err
+		}
+		nodes := t.NodeIterator(nil)
+		for nodes.Next(true) {
+			if hash := nodes.Hash(); hash != (common.Hash{}) {
+				if err := fn(hash); err != nil {
+					return err
+				}
+			}
+		}
+		if err := nodes.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//将区块的检查点写入w, 返回写入的节点数量
+func (bc *BlockChain) ExportCheckpoint(w io.Writer, checkpoint *Checkpoint) (int, error) {
+
+	//检查点的状态可能还在内存中，先写入磁盘
+	if err := bc.triedb.Commit(checkpoint.Block.Root()); err != nil {
+		return 0, err
+	}
+	if err := rlp.Encode(w, checkpoint); err != nil {
+		return 0, err
+	}
+	seen := make(map[common.Hash]struct{})
+	err := iterateCheckpoint(bc.chainDb, checkpoint.Block.Header(), func(hash common.Hash) error {
+		if _, ok := seen[hash]; ok {
+			return nil
+		}
+		seen[hash] = struct{}{}
+
+		blob, err := bc.chainDb.Get(hash[:])
+		if err != nil {
+			return fmt.Errorf("missing node %x: %v", hash, err)
+		}
+		return rlp.Encode(w, blob)
+	})
+	return len(seen), err
+}
+
+//生成区块的检查点(不包括签名)
+func (bc *BlockChain) NewCheckpoint(block *types.Block) (*Checkpoint, error) {
+
+	first := bc.GetHeaderByNumber(1)
+	td := bc.GetTd(block.Hash(), block.NumberU64())
+	if first == nil || td == nil || block.NumberU64() <= 1 {
+		return nil, protocol.ErrUnknownBlock
+	}
+	return &Checkpoint{
+		Genesis: bc.genesisBlock.Hash(),
+		First:   first,
+		Block:   block,
+		Td:      td,
+	}, nil
+}
+
+//从检查点初始化只包含创世区块的数据库: 校验全部节点以及验证者签名以后将检查点区块设置为当前区块。
+//trusted不为空时检查点区块必须与之相同，required为至少需要的验证者签名数量
+func ImportCheckpoint(db ethdb.Database, r io.Reader, trusted common.Hash, required int) (*Checkpoint, error) {
+
+	genesis := GetCanonicalHash(db, 0)
+	if head := GetHeadBlockHash(db); head != genesis {
+		return nil, errCheckpointNotEmpty
+	}
+
+	stream := rlp.NewStream(r, 0)
+	checkpoint := new(Checkpoint)
+	if err := stream.Decode(checkpoint); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint header: %v", err)
+	}
+	block := checkpoint.Block
+	switch {
+	case checkpoint.Genesis != genesis:
+		return nil, errCheckpointGenesis
+	case trusted != (common.Hash{}) && block.Hash() != trusted:
+		return nil, errCheckpointUntrusted
+	case types.DeriveSha(block.Transactions()) != block.TxHash():
+		return nil, errCheckpointBody
+	case checkpoint.First.Number.Uint64() != 1 || block.NumberU64() <= 1:
+		return nil, protocol.ErrUnknownBlock
+	}
+
+	//节点按哈希保存，写入前不需要信任检查点
+	nodes := 0
+	batch := db.NewBatch()
+	for {
+		var blob []byte
+		if err := stream.Decode(&blob); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("node %d: %v", nodes, err)
+		}
+		if err := batch.Put(crypto.Keccak256(blob), blob); err != nil {
+			return nil, err
+		}
+		if nodes++; batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return nil, err
+			}
+			batch = db.NewBatch()
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+
+	//校验检查点区块的全部树都已经完整，以及验证者签名
+	if err := iterateCheckpoint(db, block.Header(), func(common.Hash) error { return nil }); err != nil {
+		return nil, fmt.Errorf("incomplete checkpoint state: %v", err)
+	}
+	signers, err := checkpoint.Signers(db)
+	if err != nil {
+		return nil, err
+	}
+	if len(signers) < required {
+		return nil, errCheckpointSignatures
+	}
+
+	//写入首区块头以及检查点区块，并将检查点区块设置为当前区块和已确认区块
+	hash, number := block.Hash(), block.NumberU64()
+	if err := WriteHeader(db, checkpoint.First); err != nil {
+		return nil, err
+	}
+	if err := WriteCanonicalHash(db, checkpoint.First.Hash(), 1); err != nil {
+		return nil, err
+	}
+	if err := WriteTd(db, hash, number, checkpoint.Td); err != nil {
+		return nil, err
+	}
+	if err := WriteBlock(db, block); err != nil {
+		return nil, err
+	}
+	if err := WriteCanonicalHash(db, hash, number); err != nil {
+		return nil, err
+	}
+	if err := WriteHeadHeaderHash(db, hash); err != nil {
+		return nil, err
+	}
+	if err := WriteHeadFastBlockHash(db, hash); err != nil {
+		return nil, err
+	}
+	if err := WriteHeadBlockHash(db, hash); err != nil {
+		return nil, err
+	}
+	if err := db.Put(protocol.ConfirmedBlockHead, hash.Bytes()); err != nil {
+		return nil, err
+	}
+	log.Info("Imported state checkpoint", "number", number, "hash", hash, "nodes", nodes, "signers", len(signers))
+	return checkpoint, nil
+}
@@ -0,0 +1,184 @@
+package core
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/consensus/ethash"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/core/vm"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+var (
+	checkpointValidatorKey, _ = crypto.GenerateKey()
+	checkpointSigningKey, _   = crypto.GenerateKey()
+	checkpointValidator       = crypto.PubkeyToAddress(checkpointValidatorKey.PublicKey)
+
+	checkpointGenesis = &Genesis{
+		Config: params.TestChainConfig,
+		Alloc: GenesisAlloc{
+			common.Address{0x01}: {Balance: big.NewInt(1000000)},
+			common.Address{0x02}: {Balance: big.NewInt(1), Code: []byte{0x60, 0x00}, Storage: map[common.Hash]common.Hash{{0x01}: {0x02}}},
+		},
+	}
+)
+
+// newCheckpointChain creates a chain of two blocks, the second one starting an epoch
+// of a single validator signing through a separate signing key.
+func newCheckpointChain(t *testing.T) (*BlockChain, *types.Block) {
+	db, _ := ethdb.NewMemDatabase()
+	genesis := checkpointGenesis.MustCommit(db)
+
+	dposContext, _ := types.NewDposContextFromProto(db, genesis.Header().DposProto)
+	if err := dposContext.SetEpochTrie([]common.Address{checkpointValidator}); err != nil {
+		t.Fatalf("failed to set validators: %v", err)
+	}
+	dposProto, err := dposContext.CommitTo(db)
+	if err != nil {
+		t.Fatalf("failed to commit dpos context: %v", err)
+	}
+	bokerContext, _ := types.NewBokerContextFromProto(db, genesis.Header().BokerProto)
+	if err := bokerContext.SetSigningKey(checkpointValidator, crypto.PubkeyToAddress(checkpointSigningKey.PublicKey)); err != nil {
+		t.Fatalf("failed to set signing key: %v", err)
+	}
+	bokerProto, err := bokerContext.CommitTo(db)
+	if err != nil {
+		t.Fatalf("failed to commit boker context: %v", err)
+	}
+
+	parent, td := genesis, new(big.Int).Set(genesis.Difficulty())
+	for i := 0; i < 2; i++ {
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Root:       parent.Root(),
+			Difficulty: parent.Difficulty(),
+			Number:     new(big.Int).Add(parent.Number(), common.Big1),
+			Time:       new(big.Int).Add(parent.Time(), big.NewInt(5)),
+			GasLimit:   parent.GasLimit(),
+			GasUsed:    new(big.Int),
+			DposProto:  parent.Header().DposProto,
+			BokerProto: parent.Header().BokerProto,
+		}
+		if i == 1 {
+			header.DposProto, header.BokerProto = dposProto, bokerProto
+		}
+		block := types.NewBlock(header, nil, nil, nil)
+		td.Add(td, block.Difficulty())
+
+		WriteTd(db, block.Hash(), block.NumberU64(), td)
+		WriteBlock(db, block)
+		WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		parent = block
+	}
+	WriteHeadBlockHash(db, parent.Hash())
+	WriteHeadHeaderHash(db, parent.Hash())
+
+	chain, err := NewBlockChain(db, checkpointGenesis.Config, ethash.NewFullFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	return chain, parent
+}
+
+// exportCheckpoint exports the checkpoint of the block signed by the given keys.
+func exportCheckpoint(t *testing.T, chain *BlockChain, block *types.Block, keys ...*ecdsa.PrivateKey) []byte {
+	checkpoint, err := chain.NewCheckpoint(block)
+	if err != nil {
+		t.Fatalf("failed to create checkpoint: %v", err)
+	}
+	hash := checkpoint.SigHash()
+	for _, key := range keys {
+		sig, err := crypto.Sign(hash[:], key)
+		if err != nil {
+			t.Fatalf("failed to sign checkpoint: %v", err)
+		}
+		checkpoint.Signatures = append(checkpoint.Signatures, sig)
+	}
+	buf := new(bytes.Buffer)
+	if _, err := chain.ExportCheckpoint(buf, checkpoint); err != nil {
+		t.Fatalf("failed to export checkpoint: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// Tests that a node initialized from a signed epoch checkpoint resumes at the
+// checkpoint block with its full state and consensus contexts.
+func TestCheckpointRoundtrip(t *testing.T) {
+	chain, block := newCheckpointChain(t)
+	defer chain.Stop()
+
+	blob := exportCheckpoint(t, chain, block, checkpointSigningKey)
+
+	db, _ := ethdb.NewMemDatabase()
+	checkpointGenesis.MustCommit(db)
+	if _, err := ImportCheckpoint(db, bytes.NewReader(blob), block.Hash(), 1); err != nil {
+		t.Fatalf("failed to import checkpoint: %v", err)
+	}
+	imported, err := NewBlockChain(db, checkpointGenesis.Config, ethash.NewFullFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create blockchain from checkpoint: %v", err)
+	}
+	defer imported.Stop()
+
+	if head := imported.CurrentBlock(); head.Hash() != block.Hash() {
+		t.Fatalf("head mismatch: have #%d [%x], want #%d [%x]", head.NumberU64(), head.Hash(), block.NumberU64(), block.Hash())
+	}
+	statedb, err := imported.State()
+	if err != nil {
+		t.Fatalf("failed to open checkpoint state: %v", err)
+	}
+	if value := statedb.GetState(common.Address{0x02}, common.Hash{0x01}); value != (common.Hash{0x02}) {
+		t.Errorf("storage mismatch: have %x, want %x", value, common.Hash{0x02})
+	}
+	validators, err := imported.CurrentBlock().DposCtx().GetEpochTrie()
+	if err != nil || len(validators) != 1 || validators[0] != checkpointValidator {
+		t.Errorf("validators mismatch: have %v (%v), want [%x]", validators, err, checkpointValidator)
+	}
+	if first := imported.GetHeaderByNumber(1); first == nil {
+		t.Errorf("first header missing")
+	}
+}
+
+// Tests that checkpoints lacking validator signatures, not matching the trusted
+// hash or missing state are rejected.
+func TestCheckpointRejection(t *testing.T) {
+	chain, block := newCheckpointChain(t)
+	defer chain.Stop()
+
+	stranger, _ := crypto.GenerateKey()
+	signed := exportCheckpoint(t, chain, block, checkpointSigningKey)
+
+	tests := []struct {
+		blob    []byte
+		trusted common.Hash
+		fail    bool
+	}{
+		{blob: exportCheckpoint(t, chain, block), fail: true},
+		{blob: exportCheckpoint(t, chain, block, stranger), fail: true},
+		{blob: exportCheckpoint(t, chain, block, checkpointValidatorKey), fail: false},
+		{blob: signed, trusted: common.Hash{0xff}, fail: true},
+		{blob: signed[:len(signed)-64], fail: true},
+		{blob: signed, fail: false},
+	}
+	for i, tt := range tests {
+		db, _ := ethdb.NewMemDatabase()
+		checkpointGenesis.MustCommit(db)
+
+		_, err := ImportCheckpoint(db, bytes.NewReader(tt.blob), tt.trusted, 1)
+		if tt.fail && err == nil {
+			t.Errorf("test %d: invalid checkpoint imported", i)
+		}
+		if !tt.fail && err != nil {
+			t.Errorf("test %d: valid checkpoint rejected: %v", i, err)
+		}
+		if err != nil && GetHeadBlockHash(db) != GetCanonicalHash(db, 0) {
+			t.Errorf("test %d: head moved by rejected checkpoint", i)
+		}
+	}
+}
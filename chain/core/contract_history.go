@@ -0,0 +1,82 @@
+package core
+
+import (
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+var contractHistoryPrefix = []byte("cH") // contractHistoryPrefix + num (uint64 big endian) + hash -> base contract changes of the block
+
+//区块中基础合约的变更记录，以及同一条链上之前最近一个存在变更的区块
+type contractHistoryEntry struct {
+	Records    []*protocol.BaseContractRecord
+	PrevNumber uint64
+	PrevHash   common.Hash
+}
+
+func getContractHistoryEntry(db DatabaseReader, hash common.Hash, number uint64) *contractHistoryEntry {
+	data, _ := db.Get(append(append(contractHistoryPrefix, encodeBlockNumber(number)...), hash[:]...))
+	if len(data) == 0 {
+		return nil
+	}
+	entry := new(contractHistoryEntry)
+	if err := rlp.DecodeBytes(data, entry); err != nil {
+		log.Error("Invalid contract history RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return entry
+}
+
+// GetContractHistory retrieves the base contract changes of the chain ending
+// at the given block, oldest first. Changes made before the node recorded the
+// history (e.g. the chain was imported by an older version) are not included.
+func GetContractHistory(db DatabaseReader, hash common.Hash, number uint64) []*protocol.BaseContractRecord {
+	var blocks [][]*protocol.BaseContractRecord
+	for entry := getContractHistoryEntry(db, hash, number); entry != nil; {
+		if len(entry.Records) > 0 {
+			blocks = append(blocks, entry.Records)
+		}
+		if entry.PrevHash == (common.Hash{}) {
+			break
+		}
+		entry = getContractHistoryEntry(db, entry.PrevHash, entry.PrevNumber)
+	}
+	records := make([]*protocol.BaseContractRecord, 0)
+	for i := len(blocks) - 1; i >= 0; i-- {
+		records = append(records, blocks[i]...)
+	}
+	return records
+}
+
+// WriteContractHistory stores the base contract changes of a block together
+// with a link to the latest earlier block of the same chain that changed the
+// base contracts, so the history can be walked without visiting every block.
+func WriteContractHistory(db ethdb.Database, batch ethdb.Putter, block *types.Block, records []*protocol.BaseContractRecord) error {
+	entry := &contractHistoryEntry{Records: records}
+	if parent := getContractHistoryEntry(db, block.ParentHash(), block.NumberU64()-1); parent != nil {
+		if len(parent.Records) > 0 {
+			entry.PrevNumber, entry.PrevHash = block.NumberU64()-1, block.ParentHash()
+		} else {
+			entry.PrevNumber, entry.PrevHash = parent.PrevNumber, parent.PrevHash
+		}
+	}
+	data, err := rlp.EncodeToBytes(entry)
+	if err != nil {
+		return err
+	}
+	key := append(append(contractHistoryPrefix, encodeBlockNumber(block.NumberU64())...), block.Hash().Bytes()...)
+	if err := batch.Put(key, data); err != nil {
+		log.Crit("Failed to store contract history", "err", err)
+	}
+	return nil
+}
+
+// DeleteContractHistory removes the base contract changes associated with a
+// block hash.
+func DeleteContractHistory(db DatabaseDeleter, hash common.Hash, number uint64) {
+	db.Delete(append(append(contractHistoryPrefix, encodeBlockNumber(number)...), hash.Bytes()...))
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+)
+
+// Tests that the base contract history is collected along the chain of the
+// requested block only, skipping blocks without changes.
+func TestContractHistory(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+
+	newBlock := func(parent *types.Block, number int64, extra byte) *types.Block {
+		header := &types.Header{Number: big.NewInt(number), Extra: []byte{extra}}
+		if parent != nil {
+			header.ParentHash = parent.Hash()
+		}
+		return types.NewBlock(header, nil, nil, nil)
+	}
+	record := func(number uint64, action protocol.BaseContractAction) *protocol.BaseContractRecord {
+		return &protocol.BaseContractRecord{Address: common.Address{0x01}, Type: protocol.System, Action: action, BlockNumber: number}
+	}
+	write := func(block *types.Block, records ...*protocol.BaseContractRecord) {
+		if err := WriteContractHistory(db, db, block, records); err != nil {
+			t.Fatalf("failed to write history of block %d: %v", block.NumberU64(), err)
+		}
+	}
+
+	//1(设置) <- 2 <- 3(取消) <- 4，分叉 2 <- 3'(设置)
+	b1 := newBlock(nil, 1, 0)
+	b2 := newBlock(b1, 2, 0)
+	b3 := newBlock(b2, 3, 0)
+	b4 := newBlock(b3, 4, 0)
+	f3 := newBlock(b2, 3, 1)
+	write(b1, record(1, protocol.ContractSet))
+	write(b2)
+	write(b3, record(3, protocol.ContractCancel))
+	write(b4)
+	write(f3, record(3, protocol.ContractSet))
+
+	tests := []struct {
+		block   *types.Block
+		actions []protocol.BaseContractAction
+	}{
+		{b1, []protocol.BaseContractAction{protocol.ContractSet}},
+		{b2, []protocol.BaseContractAction{protocol.ContractSet}},
+		{b4, []protocol.BaseContractAction{protocol.ContractSet, protocol.ContractCancel}},
+		{f3, []protocol.BaseContractAction{protocol.ContractSet, protocol.ContractSet}},
+	}
+	for i, tt := range tests {
+		history := GetContractHistory(db, tt.block.Hash(), tt.block.NumberU64())
+		if len(history) != len(tt.actions) {
+			t.Errorf("test %d: history length mismatch: have %d, want %d", i, len(history), len(tt.actions))
+			continue
+		}
+		for j, action := range tt.actions {
+			if history[j].Action != action {
+				t.Errorf("test %d: record %d action mismatch: have %v, want %v", i, j, history[j].Action, action)
+			}
+		}
+	}
+
+	DeleteContractHistory(db, b4.Hash(), b4.NumberU64())
+	if history := GetContractHistory(db, b4.Hash(), b4.NumberU64()); len(history) != 0 {
+		t.Errorf("history of deleted block: have %d records, want none", len(history))
+	}
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+var contractMetaPrefix = []byte("M") // contractMetaPrefix + address -> hash of the transaction registering the contract metadata
+
+//解析扩展交易中的合约元数据，Abi不为空时必须与Abi哈希一致
+func DecodeContractMeta(extra []byte) (*protocol.ContractMetadata, error) {
+
+	meta := new(protocol.ContractMetadata)
+	if err := rlp.DecodeBytes(extra, meta); err != nil {
+		return nil, protocol.ErrInvalidContractMeta
+	}
+	if meta.Name == "" || meta.AbiHash == (common.Hash{}) {
+		return nil, protocol.ErrInvalidContractMeta
+	}
+	if meta.Abi != "" && crypto.Keccak256Hash([]byte(meta.Abi)) != meta.AbiHash {
+		return nil, protocol.ErrInvalidContractMeta
+	}
+	return meta, nil
+}
+
+// GetContractMetaLookup retrieves the hash of the transaction which registered
+// the metadata of a contract, or an empty hash if none was registered.
+func GetContractMetaLookup(db DatabaseReader, address common.Address) common.Hash {
+	data, _ := db.Get(append(contractMetaPrefix, address.Bytes()...))
+	if len(data) == 0 {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteContractMetaLookup stores the hash of the transaction registering the
+// metadata of a contract.
+func WriteContractMetaLookup(db ethdb.Putter, address common.Address, hash common.Hash) error {
+	if err := db.Put(append(contractMetaPrefix, address.Bytes()...), hash.Bytes()); err != nil {
+		log.Crit("Failed to store contract metadata lookup", "err", err)
+	}
+	return nil
+}
+
+//为区块中登记合约元数据的交易写入索引，已登记的合约只能由原登记账号更新
+func (bc *BlockChain) writeContractMetaLookups(db ethdb.Putter, block *types.Block) error {
+
+	if !bc.config.IsContractMeta(block.Number()) {
+		return nil
+	}
+
+	signer := types.MakeSigner(bc.config, block.Number())
+	registrants := make(map[common.Address]common.Address)
+	for _, tx := range block.Transactions() {
+
+		if tx.Major() != protocol.Extra || tx.Minor() != protocol.ContractMeta || tx.To() == nil {
+			continue
+		}
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return err
+		}
+		address := *tx.To()
+
+		registrant, ok := registrants[address]
+		if !ok {
+			if hash := GetContractMetaLookup(bc.chainDb, address); hash != (common.Hash{}) {
+				if previous, _, number, _ := GetTransaction(bc.chainDb, hash); previous != nil {
+					if registrant, err = types.Sender(types.MakeSigner(bc.config, new(big.Int).SetUint64(number)), previous); err == nil {
+						ok = true
+					}
+				}
+			}
+		}
+		if ok && registrant != from {
+			log.Warn("Ignoring contract metadata from foreign account", "contract", address, "from", from, "registrant", registrant)
+			continue
+		}
+		registrants[address] = from
+		if err := WriteContractMetaLookup(db, address, tx.Hash()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+func TestDecodeContractMeta(t *testing.T) {
+	abi := `[{"type":"function","name":"get","inputs":[],"outputs":[]}]`
+
+	tests := []struct {
+		meta  interface{}
+		valid bool
+	}{
+		{protocol.ContractMetadata{Name: "Token", AbiHash: crypto.Keccak256Hash([]byte(abi))}, true},
+		{protocol.ContractMetadata{Name: "Token", AbiHash: crypto.Keccak256Hash([]byte(abi)), Source: "https://example.com", Abi: abi}, true},
+		{protocol.ContractMetadata{AbiHash: crypto.Keccak256Hash([]byte(abi))}, false},
+		{protocol.ContractMetadata{Name: "Token"}, false},
+		{protocol.ContractMetadata{Name: "Token", AbiHash: common.HexToHash("0x01"), Abi: abi}, false},
+		{[]string{"Token"}, false},
+	}
+	for i, tt := range tests {
+		extra, _ := rlp.EncodeToBytes(tt.meta)
+		if _, err := DecodeContractMeta(extra); (err == nil) != tt.valid {
+			t.Errorf("test %d: have %v, want valid %v", i, err, tt.valid)
+		}
+	}
+}
+
+// Tests that contract metadata can only be registered for deployed contracts,
+// and that registering it does not call the contract.
+func TestContractMetaTransaction(t *testing.T) {
+	var (
+		key, _   = crypto.GenerateKey()
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.HexToAddress("0x0c")
+		account  = common.HexToAddress("0x0a")
+	)
+	env := newBokerTestEnv(t)
+	config := *env.config
+	config.ContractMetaBlock = big.NewInt(2)
+	env.config = &config
+	env.statedb.AddBalance(sender, new(big.Int).Mul(big.NewInt(1e9), big.NewInt(1e9)))
+	//调用该合约时总是失败(INVALID指令)
+	env.statedb.SetCode(contract, []byte{0xfe})
+
+	metaTx := func(nonce uint64, to common.Address, meta protocol.ContractMetadata) *types.Transaction {
+		extra, _ := rlp.EncodeToBytes(meta)
+		tx := types.NewExtraTransaction(protocol.Extra, protocol.ContractMeta, nonce, to, new(big.Int), big.NewInt(100000), big.NewInt(1), nil, extra, 0)
+		tx, _ = types.SignTx(tx, types.HomesteadSigner{}, key)
+		return tx
+	}
+	meta := protocol.ContractMetadata{Name: "Token", AbiHash: common.HexToHash("0xab")}
+
+	//分叉之前与其它扩展交易一样调用合约，不校验元数据
+	receipt, err := env.apply(sender, metaTx(0, contract, protocol.ContractMetadata{Name: "Token"}))
+	if err != nil {
+		t.Fatalf("before fork: failed to apply transaction: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusFailed {
+		t.Errorf("before fork: contract not called: status %d", receipt.Status)
+	}
+	env.header.Number = big.NewInt(2)
+
+	if _, err := env.apply(sender, metaTx(1, account, meta)); err != protocol.ErrInvalidContractMeta {
+		t.Errorf("plain account: have %v, want %v", err, protocol.ErrInvalidContractMeta)
+	}
+	if _, err := env.apply(sender, metaTx(1, contract, protocol.ContractMetadata{Name: "Token"})); err != protocol.ErrInvalidContractMeta {
+		t.Errorf("invalid metadata: have %v, want %v", err, protocol.ErrInvalidContractMeta)
+	}
+	if nonce := env.statedb.GetNonce(sender); nonce != 1 {
+		t.Fatalf("rejected registrations changed the nonce to %d", nonce)
+	}
+
+	receipt, err = env.apply(sender, metaTx(1, contract, meta))
+	if err != nil {
+		t.Fatalf("failed to register metadata: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Errorf("registration called the contract: status %d", receipt.Status)
+	}
+	if nonce := env.statedb.GetNonce(sender); nonce != 2 {
+		t.Errorf("nonce mismatch: have %d, want 2", nonce)
+	}
+}
@@ -55,6 +55,7 @@ var (
 	blockHashPrefix     = []byte("H") // blockHashPrefix + hash -> num (uint64 big endian)
 	bodyPrefix          = []byte("b") // bodyPrefix + num (uint64 big endian) + hash -> block body
 	blockReceiptsPrefix = []byte("r") // blockReceiptsPrefix + num (uint64 big endian) + hash -> block receipts
+	blockRewardsPrefix  = []byte("w") // blockRewardsPrefix + num (uint64 big endian) + hash -> block rewards
 	lookupPrefix        = []byte("l") // lookupPrefix + hash -> transaction/receipt lookup metadata
 	bloomBitsPrefix     = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
 
@@ -98,16 +99,16 @@ func GetCanonicalHash(db DatabaseReader, number uint64) common.Hash {
 	return common.BytesToHash(data)
 }
 
-// missingNumber is returned by GetBlockNumber if no header with the
+// MissingNumber is returned by GetBlockNumber if no header with the
 // given block hash has been stored in the database
-const missingNumber = uint64(0xffffffffffffffff)
+const MissingNumber = uint64(0xffffffffffffffff)
 
 // GetBlockNumber returns the block number assigned to a block hash
 // if the corresponding header is present in the database
 func GetBlockNumber(db DatabaseReader, hash common.Hash) uint64 {
 	data, _ := db.Get(append(blockHashPrefix, hash.Bytes()...))
 	if len(data) != 8 {
-		return missingNumber
+		return MissingNumber
 	}
 	return binary.BigEndian.Uint64(data)
 }
@@ -260,6 +261,15 @@ func GetBlockReceipts(db DatabaseReader, hash common.Hash, number uint64) types.
 	if len(data) == 0 {
 		return nil
 	}
+	//新写入的收据使用压缩格式，之前写入的收据仍然按RLP读取
+	if types.IsCompactReceipts(data) {
+		receipts, err := types.DecodeCompactReceipts(data, hash, number)
+		if err != nil {
+			log.Error("Invalid compact receipts", "hash", hash, "err", err)
+			return nil
+		}
+		return receipts
+	}
 	storageReceipts := []*types.ReceiptForStorage{}
 	if err := rlp.DecodeBytes(data, &storageReceipts); err != nil {
 		log.Error("Invalid receipt array RLP", "hash", hash, "err", err)
@@ -272,6 +282,21 @@ func GetBlockReceipts(db DatabaseReader, hash common.Hash, number uint64) types.
 	return receipts
 }
 
+// GetBlockRewards retrieves the reward and gas distribution report recorded
+// when the block given by its hash was finalized.
+func GetBlockRewards(db DatabaseReader, hash common.Hash, number uint64) *types.BlockRewards {
+	data, _ := db.Get(append(append(blockRewardsPrefix, encodeBlockNumber(number)...), hash[:]...))
+	if len(data) == 0 {
+		return nil
+	}
+	rewards := new(types.BlockRewards)
+	if err := rlp.DecodeBytes(data, rewards); err != nil {
+		log.Error("Invalid block rewards RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return rewards
+}
+
 // GetTxLookupEntry retrieves the positional metadata associated with a transaction
 // hash to allow retrieving the transaction or receipt by hash.
 func GetTxLookupEntry(db DatabaseReader, hash common.Hash) (common.Hash, uint64, uint64) {
@@ -351,6 +376,26 @@ func GetReceipt(db DatabaseReader, hash common.Hash) (*types.Receipt, common.Has
 	return (*types.Receipt)(&receipt), common.Hash{}, 0, 0
 }
 
+// GetTransactionReceipt retrieves a transaction together with its receipt and
+// positional metadata. The transaction is located through its lookup entry or
+// the old separate metadata, and the receipt is then taken from the receipts of
+// the containing block, falling back to the old per-transaction receipt. The
+// returned receipt is nil if the transaction is known but its receipt data is
+// missing from the database.
+func GetTransactionReceipt(db DatabaseReader, hash common.Hash) (*types.Transaction, *types.Receipt, common.Hash, uint64, uint64) {
+	tx, blockHash, blockNumber, index := GetTransaction(db, hash)
+	if tx == nil {
+		return nil, nil, common.Hash{}, 0, 0
+	}
+	if blockHash != (common.Hash{}) {
+		if receipts := GetBlockReceipts(db, blockHash, blockNumber); int(index) < len(receipts) {
+			return tx, receipts[index], blockHash, blockNumber, index
+		}
+	}
+	receipt, _, _, _ := GetReceipt(db, hash)
+	return tx, receipt, blockHash, blockNumber, index
+}
+
 // GetBloomBits retrieves the compressed bloom bit vector belonging to the given
 // section and bit index from the.
 func GetBloomBits(db DatabaseReader, bit uint, section uint64, head common.Hash) ([]byte, error) {
@@ -463,14 +508,16 @@ func WriteBlock(db ethdb.Putter, block *types.Block) error {
 // as a single receipt slice. This is used during chain reorganisations for
 // rescheduling dropped transactions.
 func WriteBlockReceipts(db ethdb.Putter, hash common.Hash, number uint64, receipts types.Receipts) error {
-	// Convert the receipts into their storage form and serialize them
-	storageReceipts := make([]*types.ReceiptForStorage, len(receipts))
-	for i, receipt := range receipts {
-		storageReceipts[i] = (*types.ReceiptForStorage)(receipt)
-	}
-	bytes, err := rlp.EncodeToBytes(storageReceipts)
+	//优先使用压缩格式，无法压缩时(累计Gas不是单调递增)按完整的存储格式保存
+	bytes, err := types.EncodeCompactReceipts(receipts)
 	if err != nil {
-		return err
+		storageReceipts := make([]*types.ReceiptForStorage, len(receipts))
+		for i, receipt := range receipts {
+			storageReceipts[i] = (*types.ReceiptForStorage)(receipt)
+		}
+		if bytes, err = rlp.EncodeToBytes(storageReceipts); err != nil {
+			return err
+		}
 	}
 	// Store the flattened receipt slice
 	key := append(append(blockReceiptsPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
@@ -480,6 +527,19 @@ func WriteBlockReceipts(db ethdb.Putter, hash common.Hash, number uint64, receip
 	return nil
 }
 
+// WriteBlockRewards stores the reward and gas distribution report of a block.
+func WriteBlockRewards(db ethdb.Putter, hash common.Hash, number uint64, rewards *types.BlockRewards) error {
+	bytes, err := rlp.EncodeToBytes(rewards)
+	if err != nil {
+		return err
+	}
+	key := append(append(blockRewardsPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+	if err := db.Put(key, bytes); err != nil {
+		log.Crit("Failed to store block rewards", "err", err)
+	}
+	return nil
+}
+
 // WriteTxLookupEntries stores a positional metadata for every transaction from
 // a block, enabling hash based transaction and receipt lookups.
 func WriteTxLookupEntries(db ethdb.Putter, block *types.Block) error {
@@ -538,6 +598,10 @@ func DeleteTd(db DatabaseDeleter, hash common.Hash, number uint64) {
 // DeleteBlock removes all block data associated with a hash.
 func DeleteBlock(db DatabaseDeleter, hash common.Hash, number uint64) {
 	DeleteBlockReceipts(db, hash, number)
+	DeleteBlockRewards(db, hash, number)
+	DeleteBlockStats(db, hash, number)
+	DeleteTxTypeStats(db, hash, number)
+	DeleteContractHistory(db, hash, number)
 	DeleteHeader(db, hash, number)
 	DeleteBody(db, hash, number)
 	DeleteTd(db, hash, number)
@@ -548,6 +612,11 @@ func DeleteBlockReceipts(db DatabaseDeleter, hash common.Hash, number uint64) {
 	db.Delete(append(append(blockReceiptsPrefix, encodeBlockNumber(number)...), hash.Bytes()...))
 }
 
+// DeleteBlockRewards removes the reward report associated with a block hash.
+func DeleteBlockRewards(db DatabaseDeleter, hash common.Hash, number uint64) {
+	db.Delete(append(append(blockRewardsPrefix, encodeBlockNumber(number)...), hash.Bytes()...))
+}
+
 // DeleteTxLookupEntry removes all transaction data associated with a hash.
 func DeleteTxLookupEntry(db DatabaseDeleter, hash common.Hash) {
 	db.Delete(append(lookupPrefix, hash.Bytes()...))
@@ -21,6 +21,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/core/types"
 	"github.com/Tinachain/Tina/chain/crypto/sha3"
@@ -34,7 +35,7 @@ func TestHeaderStorage(t *testing.T) {
 
 	// Create a test header to move around the database and make sure it's really new
 	dposCtx, _ := types.NewDposContext(db)
-	header := &types.Header{Number: big.NewInt(42), Extra: []byte("test header"), DposContext: dposCtx.ToProto()}
+	header := &types.Header{Number: big.NewInt(42), Extra: []byte("test header"), DposProto: dposCtx.ToProto(), BokerProto: &types.BokerBackendProto{}}
 	if entry := GetHeader(db, header.Hash(), header.Number.Uint64()); entry != nil {
 		t.Fatalf("Non existent header returned: %v", entry)
 	}
@@ -70,7 +71,7 @@ func TestBodyStorage(t *testing.T) {
 
 	// Create a test body to move around the database and make sure it's really new
 	dposCtx, _ := types.NewDposContext(db)
-	body := &types.Body{Uncles: []*types.Header{{Extra: []byte("test header"), DposContext: dposCtx.ToProto()}}}
+	body := &types.Body{Uncles: []*types.Header{{Extra: []byte("test header"), DposProto: dposCtx.ToProto(), BokerProto: &types.BokerBackendProto{}}}}
 
 	hasher := sha3.NewKeccak256()
 	rlp.Encode(hasher, body)
@@ -292,9 +293,9 @@ func TestHeadStorage(t *testing.T) {
 func TestLookupStorage(t *testing.T) {
 	db, _ := ethdb.NewMemDatabase()
 
-	tx1 := types.NewTransaction(types.Binary, 1, common.BytesToAddress([]byte{0x11}), big.NewInt(111), big.NewInt(1111), big.NewInt(11111), []byte{0x11, 0x11, 0x11})
-	tx2 := types.NewTransaction(types.Binary, 2, common.BytesToAddress([]byte{0x22}), big.NewInt(222), big.NewInt(2222), big.NewInt(22222), []byte{0x22, 0x22, 0x22})
-	tx3 := types.NewTransaction(types.Binary, 3, common.BytesToAddress([]byte{0x33}), big.NewInt(333), big.NewInt(3333), big.NewInt(33333), []byte{0x33, 0x33, 0x33})
+	tx1 := types.NewTransaction(protocol.Normal, protocol.NormalCall, 1, common.BytesToAddress([]byte{0x11}), big.NewInt(111), big.NewInt(1111), big.NewInt(11111), []byte{0x11, 0x11, 0x11})
+	tx2 := types.NewTransaction(protocol.Normal, protocol.NormalCall, 2, common.BytesToAddress([]byte{0x22}), big.NewInt(222), big.NewInt(2222), big.NewInt(22222), []byte{0x22, 0x22, 0x22})
+	tx3 := types.NewTransaction(protocol.Normal, protocol.NormalCall, 3, common.BytesToAddress([]byte{0x33}), big.NewInt(333), big.NewInt(3333), big.NewInt(33333), []byte{0x33, 0x33, 0x33})
 	txs := []*types.Transaction{tx1, tx2, tx3}
 
 	block := types.NewBlock(&types.Header{Number: big.NewInt(314)}, txs, nil, nil)
@@ -388,3 +389,80 @@ func TestBlockReceiptStorage(t *testing.T) {
 		t.Fatalf("deleted receipts returned: %v", rs)
 	}
 }
+
+// Tests that block reward reports can be stored, retrieved and deleted along
+// with their block.
+func TestBlockRewardsStorage(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+
+	rewards := &types.BlockRewards{
+		Producer:            common.BytesToAddress([]byte{0x01}),
+		ProducerReward:      big.NewInt(800),
+		GasFees:             big.NewInt(42000),
+		GasPoolContribution: big.NewInt(200),
+		StockPayouts: []types.StockPayout{
+			{Account: common.BytesToAddress([]byte{0x22}), Amount: big.NewInt(7)},
+		},
+	}
+	hash := common.BytesToHash([]byte{0x03, 0x14})
+	if r := GetBlockRewards(db, hash, 1); r != nil {
+		t.Fatalf("non existent rewards returned: %v", r)
+	}
+	if err := WriteBlockRewards(db, hash, 1, rewards); err != nil {
+		t.Fatalf("failed to write block rewards: %v", err)
+	}
+	if r := GetBlockRewards(db, hash, 1); r == nil {
+		t.Fatalf("no rewards returned")
+	} else {
+		rlpHave, _ := rlp.EncodeToBytes(r)
+		rlpWant, _ := rlp.EncodeToBytes(rewards)
+		if !bytes.Equal(rlpHave, rlpWant) {
+			t.Fatalf("rewards mismatch: have %v, want %v", r, rewards)
+		}
+	}
+	DeleteBlock(db, hash, 1)
+	if r := GetBlockRewards(db, hash, 1); r != nil {
+		t.Fatalf("deleted rewards returned: %v", r)
+	}
+}
+
+// Tests that transactions are retrieved together with their receipt from the
+// receipts of the containing block, and that missing receipt data is reported.
+func TestTransactionReceiptStorage(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+
+	tx1 := types.NewTransaction(protocol.Normal, protocol.NormalCall, 1, common.BytesToAddress([]byte{0x11}), big.NewInt(111), big.NewInt(1111), big.NewInt(11111), []byte{0x11, 0x11, 0x11})
+	tx2 := types.NewTransaction(protocol.Normal, protocol.NormalCall, 2, common.BytesToAddress([]byte{0x22}), big.NewInt(222), big.NewInt(2222), big.NewInt(22222), []byte{0x22, 0x22, 0x22})
+	block := types.NewBlock(&types.Header{Number: big.NewInt(314), DposProto: &types.DposContextProto{}, BokerProto: &types.BokerBackendProto{}}, []*types.Transaction{tx1, tx2}, nil, nil)
+
+	receipts := []*types.Receipt{
+		{CumulativeGasUsed: big.NewInt(1), TxHash: tx1.Hash(), GasUsed: big.NewInt(1)},
+		{CumulativeGasUsed: big.NewInt(3), TxHash: tx2.Hash(), GasUsed: big.NewInt(2)},
+	}
+	if err := WriteBlock(db, block); err != nil {
+		t.Fatalf("failed to write block: %v", err)
+	}
+	if err := WriteTxLookupEntries(db, block); err != nil {
+		t.Fatalf("failed to write lookup entries: %v", err)
+	}
+	if err := WriteBlockReceipts(db, block.Hash(), block.NumberU64(), receipts); err != nil {
+		t.Fatalf("failed to write block receipts: %v", err)
+	}
+
+	tx, receipt, hash, number, index := GetTransactionReceipt(db, tx2.Hash())
+	if tx == nil || tx.Hash() != tx2.Hash() || hash != block.Hash() || number != 314 || index != 1 {
+		t.Fatalf("transaction mismatch: have %v at %x/%d/%d", tx, hash, number, index)
+	}
+	if receipt == nil || receipt.TxHash != tx2.Hash() || receipt.GasUsed.Int64() != 2 {
+		t.Fatalf("receipt mismatch: have %v", receipt)
+	}
+
+	//交易已知但收据数据缺失
+	DeleteBlockReceipts(db, block.Hash(), block.NumberU64())
+	if tx, receipt, _, _, _ := GetTransactionReceipt(db, tx1.Hash()); tx == nil || receipt != nil {
+		t.Errorf("missing receipt: have transaction %v, receipt %v", tx, receipt)
+	}
+	if tx, receipt, _, _, _ := GetTransactionReceipt(db, common.Hash{0x01}); tx != nil || receipt != nil {
+		t.Errorf("unknown transaction: have transaction %v, receipt %v", tx, receipt)
+	}
+}
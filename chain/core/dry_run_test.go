@@ -0,0 +1,48 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+// Tests that dry-running an unsigned system transaction reports the error the
+// block producer would hit, without touching the chain state.
+func TestDryRunTransaction(t *testing.T) {
+	chain, block := newCheckpointChain(t)
+	defer chain.Stop()
+
+	config := *chain.config
+	config.ValidatorInfoBlock = new(big.Int).Add(block.Number(), common.Big2)
+	chain.config = &config
+
+	data, _ := rlp.EncodeToBytes(&protocol.ValidatorInfo{Name: "tina"})
+	outsider := common.Address{0x01}
+
+	tx := types.NewBaseTransaction(protocol.SystemBase, protocol.SetValidatorInfo, 0, checkpointValidator, new(big.Int), data)
+	if _, err := DryRunTransaction(chain, checkpointValidator, tx); err != protocol.ErrValidatorInfoDisabled {
+		t.Fatalf("before fork: have %v, want %v", err, protocol.ErrValidatorInfoDisabled)
+	}
+	config.ValidatorInfoBlock = new(big.Int).Add(block.Number(), common.Big1)
+
+	tx = types.NewBaseTransaction(protocol.SystemBase, protocol.SetValidatorInfo, 0, outsider, new(big.Int), data)
+	if _, err := DryRunTransaction(chain, outsider, tx); err != protocol.ErrNotValidator {
+		t.Fatalf("non-validator: have %v, want %v", err, protocol.ErrNotValidator)
+	}
+	tx = types.NewBaseTransaction(protocol.SystemBase, protocol.SetValidatorInfo, 0, checkpointValidator, new(big.Int), data)
+	receipt, err := DryRunTransaction(chain, checkpointValidator, tx)
+	if err != nil || receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("validator: have %v (%v), want success", receipt, err)
+	}
+	bokerContext, _ := types.NewBokerContextFromProto(chain.chainDb, chain.CurrentBlock().Header().BokerProto)
+	if info, _ := bokerContext.ValidatorInfo(checkpointValidator); info != nil {
+		t.Errorf("dry run modified the chain: %v", info)
+	}
+	if head := chain.CurrentBlock(); head.Hash() != block.Hash() {
+		t.Errorf("head changed: have %x, want %x", head.Hash(), block.Hash())
+	}
+}
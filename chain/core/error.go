@@ -32,4 +32,8 @@ var (
 	// ErrNonceTooHigh is returned if the nonce of a transaction is higher than the
 	// next one expected based on the local chain.
 	ErrNonceTooHigh = errors.New("nonce too high")
+
+	// ErrBlockExtraLimit is returned if the data carried by the extra transactions
+	// of a block exceeds the per-block budget of the chain config.
+	ErrBlockExtraLimit = errors.New("block extra size limit reached")
 )
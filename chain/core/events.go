@@ -24,6 +24,10 @@ import (
 // TxPreEvent is posted when a transaction enters the transaction pool.
 type TxPreEvent struct{ Tx *types.Transaction }
 
+// TxRebroadcastEvent is posted when pending transactions should be announced
+// to all peers again, even those that already received them.
+type TxRebroadcastEvent struct{ Txs types.Transactions }
+
 // PendingLogsEvent is posted pre mining and notifies of pending logs.
 type PendingLogsEvent struct {
 	Logs []*types.Log
@@ -35,7 +39,8 @@ type PendingStateEvent struct{}
 // NewMinedBlockEvent is posted when a block has been imported.
 type NewMinedBlockEvent struct{ Block *types.Block }
 
-// RemovedTransactionEvent is posted when a reorg happens
+// RemovedTransactionEvent is posted when a reorg happens, carrying the transactions
+// of the abandoned fork that should be returned to the transaction pool.
 type RemovedTransactionEvent struct{ Txs types.Transactions }
 
 // RemovedLogsEvent is posted when a reorg happens
@@ -65,6 +65,35 @@ func NewEVMContext(msg Message,
 	}
 }
 
+//创建一个新的EVM使用的上下文，预编译合约可以通过上下文读取验证者以及股权信息
+func NewBokerEVMContext(msg Message,
+	header *types.Header,
+	chain ChainContext,
+	author *common.Address,
+	dposContext *types.DposContext,
+	bokerContext *types.BokerContext) vm.Context {
+
+	context := NewEVMContext(msg, header, chain, author)
+	context.DposContext, context.BokerContext = dposContext, bokerContext
+	return context
+}
+
+//得到区块共识上下文的副本，供eth_call、交易跟踪等只读执行使用，执行过程中的修改不会影响区块持有的上下文
+func CopyContexts(block *types.Block) (*types.DposContext, *types.BokerContext) {
+
+	var (
+		dposContext  *types.DposContext
+		bokerContext *types.BokerContext
+	)
+	if block.DposCtx() != nil {
+		dposContext = block.DposCtx().Copy()
+	}
+	if block.BokerCtx() != nil {
+		bokerContext = block.BokerCtx().Copy()
+	}
+	return dposContext, bokerContext
+}
+
 // GetHashFn returns a GetHashFunc which retrieves header hashes by number
 func GetHashFn(ref *types.Header, chain ChainContext) func(n uint64) common.Hash {
 	return func(n uint64) common.Hash {
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+func TestExtraPayloadLimits(t *testing.T) {
+	config := &params.ChainConfig{MaxWordSize: 100, MaxDataSize: 200, MaxBlockExtraSize: 300}
+
+	newTx := func(minor protocol.TxMinor, size int) *types.Transaction {
+		return types.NewExtraTransaction(protocol.Extra, minor, 0, common.Address{0x01}, big.NewInt(0), big.NewInt(21000), big.NewInt(1), nil, bytes.Repeat([]byte{0x01}, size), 0)
+	}
+	tests := []struct {
+		tx   *types.Transaction
+		want error
+	}{
+		{newTx(protocol.Word, 100), nil},
+		{newTx(protocol.Word, 101), ErrOverExtraData},
+		{newTx(protocol.Data, 200), nil},
+		{newTx(protocol.Data, 201), ErrOverExtraData},
+		{newTx(protocol.ContractMeta, 300), nil},
+		{newTx(protocol.ContractMeta, 301), ErrOverExtraData},
+	}
+	for i, tt := range tests {
+		if err := CheckExtraPayload(config, tt.tx); err != tt.want {
+			t.Errorf("test %d: have %v, want %v", i, err, tt.want)
+		}
+	}
+
+	//普通交易不计入扩展数据
+	normal := types.NewTransaction(protocol.Normal, protocol.NormalCall, 0, common.Address{0x01}, big.NewInt(0), big.NewInt(21000), big.NewInt(1), make([]byte, 500))
+	txs := types.Transactions{newTx(protocol.Word, 100), normal, newTx(protocol.Data, 150)}
+	if size := BlockExtraSize(txs); size != 250 {
+		t.Errorf("block extra size mismatch: have %d, want 250", size)
+	}
+
+	//未设置时使用默认上限
+	defaults := new(params.ChainConfig)
+	if defaults.WordSizeLimit() != params.MaxWordSize || defaults.DataSizeLimit() != params.MaxDataSize || defaults.BlockExtraLimit() != params.MaxBlockExtraSize {
+		t.Errorf("default limits mismatch: have %d/%d/%d", defaults.WordSizeLimit(), defaults.DataSizeLimit(), defaults.BlockExtraLimit())
+	}
+}
+
+// Tests that the per-block extra budget is only enforced on blocks past the
+// extra limit fork.
+func TestBlockExtraFork(t *testing.T) {
+	config := &params.ChainConfig{MaxBlockExtraSize: 100, ExtraLimitBlock: big.NewInt(10)}
+	txs := []*types.Transaction{
+		types.NewExtraTransaction(protocol.Extra, protocol.Data, 0, common.Address{0x01}, big.NewInt(0), big.NewInt(21000), big.NewInt(1), nil, make([]byte, 101), 0),
+	}
+	if err := checkBlockExtra(config, types.NewBlock(&types.Header{Number: big.NewInt(9)}, txs, nil, nil)); err != nil {
+		t.Errorf("block before the fork rejected: %v", err)
+	}
+	if err := checkBlockExtra(config, types.NewBlock(&types.Header{Number: big.NewInt(10)}, txs, nil, nil)); err == nil {
+		t.Errorf("oversized block after the fork accepted")
+	}
+}
@@ -137,6 +137,17 @@ func SetupGenesisBlock(db ethdb.Database, genesis *Genesis) (*params.ChainConfig
 	if genesis != nil && genesis.Config == nil {
 		return params.DposChainConfig, common.Hash{}, errGenesisNoConfig
 	}
+	if genesis != nil {
+		if err := genesis.Config.CheckGasSchedules(); err != nil {
+			return genesis.Config, common.Hash{}, err
+		}
+		if err := genesis.Config.CheckVoteWeight(); err != nil {
+			return genesis.Config, common.Hash{}, err
+		}
+		if err := genesis.Config.CheckGovernance(); err != nil {
+			return genesis.Config, common.Hash{}, err
+		}
+	}
 
 	//如果没有存储的genesis块，只需提交新块
 	stored := GetCanonicalHash(db, 0)
@@ -192,7 +203,7 @@ func SetupGenesisBlock(db ethdb.Database, genesis *Genesis) (*params.ChainConfig
 	// Check config compatibility and write the config. Compatibility errors
 	// are returned to the caller unless we're already at block zero.
 	height := GetBlockNumber(db, GetHeadHeaderHash(db))
-	if height == missingNumber {
+	if height == MissingNumber {
 		return newcfg, stored, fmt.Errorf("missing block number for head header hash")
 	}
 	log.Info("GetBlockNumber")
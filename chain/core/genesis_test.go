@@ -23,15 +23,14 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/Tinachain/Tina/chain/common"
-	"github.com/Tinachain/Tina/chain/consensus/ethash"
-	"github.com/Tinachain/Tina/chain/core/vm"
+	"github.com/Tinachain/Tina/chain/core/types"
 	"github.com/Tinachain/Tina/chain/ethdb"
 	"github.com/Tinachain/Tina/chain/params"
 )
 
 func TestSetupGenesis(t *testing.T) {
 	var (
-		customghash = common.HexToHash("0xa4813c30e66d854572733941743e6736f20c0487d32c6b2fe1233946b036f6f5")
+		customghash = common.HexToHash("0xaa39c251c3dbd22734069c40197b01d52a455891c3decd36012f365e2b1bb39c")
 		customg     = Genesis{
 			Config: &params.ChainConfig{HomesteadBlock: big.NewInt(3)},
 			Alloc: GenesisAlloc{
@@ -71,12 +70,14 @@ func TestSetupGenesis(t *testing.T) {
 			fn: func(db ethdb.Database) (*params.ChainConfig, common.Hash, error) {
 				// Commit the 'old' genesis block with Homestead transition at #2.
 				// Advance to block #4, past the homestead transition block of customg.
-				genesis := oldcustomg.MustCommit(db)
-				bc, _ := NewBlockChain(db, oldcustomg.Config, ethash.NewFullFaker(), vm.Config{})
-				defer bc.Stop()
-				bc.SetValidator(bproc{})
-				bc.InsertChain(makeBlockChainWithDiff(genesis, []int{2, 3, 4, 5}, 0))
-				bc.CurrentBlock()
+				parent := oldcustomg.MustCommit(db).Header()
+				for _, difficulty := range []int64{2, 3, 4, 5} {
+					header := &types.Header{ParentHash: parent.Hash(), Number: new(big.Int).Add(parent.Number, common.Big1), Difficulty: big.NewInt(difficulty)}
+					WriteHeader(db, header)
+					WriteCanonicalHash(db, header.Hash(), header.Number.Uint64())
+					parent = header
+				}
+				WriteHeadHeaderHash(db, parent.Hash())
 				// This should return a compatibility error.
 				return SetupGenesisBlock(db, &customg)
 			},
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"time"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/metrics"
+)
+
+var (
+	importSendersTimer    = metrics.NewTimer("chain/import/senders")
+	importExecutionTimer  = metrics.NewTimer("chain/import/execution")
+	importValidationTimer = metrics.NewTimer("chain/import/validation")
+	importContextTimer    = metrics.NewTimer("chain/import/context")
+	importTrieTimer       = metrics.NewTimer("chain/import/trie")
+	importWriteTimer      = metrics.NewTimer("chain/import/write")
+	importSlowMeter       = metrics.NewMeter("chain/import/slow")
+)
+
+const (
+	importStatsLimit = 128 //保留最近导入区块的耗时统计数量
+
+	//导入区块的耗时超过半个出块周期时告警，超过之后节点在连续出块时会逐渐落后
+	slowImportThreshold = time.Duration(protocol.BlockInterval) * time.Second / 2
+)
+
+//导入一个区块时各个阶段的耗时
+type importTimings struct {
+	senders    time.Duration //交易签名者恢复
+	execution  time.Duration //EVM执行交易以及Finalize
+	validation time.Duration //状态、Dpos状态以及签名者校验
+	context    time.Duration //Dpos以及Boker上下文提交
+	trie       time.Duration //状态树提交
+	write      time.Duration //leveldb批量写入
+}
+
+//导入区块的各阶段耗时统计(毫秒)
+type BlockImportStats struct {
+	Number     uint64      `json:"number"`
+	Hash       common.Hash `json:"hash"`
+	Txs        int         `json:"txs"`
+	Senders    float64     `json:"senders"`
+	Execution  float64     `json:"execution"`
+	Validation float64     `json:"validation"`
+	Context    float64     `json:"context"`
+	TrieCommit float64     `json:"trieCommit"`
+	DbWrite    float64     `json:"dbWrite"`
+	Total      float64     `json:"total"`
+	Slowest    string      `json:"slowest"` //耗时最长的阶段
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+//记录导入区块的各阶段耗时，耗时超过告警阈值时输出最慢的阶段
+func (bc *BlockChain) recordImport(block *types.Block, timings *importTimings, total time.Duration) {
+
+	importSendersTimer.Update(timings.senders)
+	importExecutionTimer.Update(timings.execution)
+	importValidationTimer.Update(timings.validation)
+	importContextTimer.Update(timings.context)
+	importTrieTimer.Update(timings.trie)
+	importWriteTimer.Update(timings.write)
+
+	stages := []struct {
+		name    string
+		elapsed time.Duration
+	}{
+		{"senders", timings.senders},
+		{"execution", timings.execution},
+		{"validation", timings.validation},
+		{"context", timings.context},
+		{"trie", timings.trie},
+		{"write", timings.write},
+	}
+	slowest := stages[0]
+	for _, stage := range stages[1:] {
+		if stage.elapsed > slowest.elapsed {
+			slowest = stage
+		}
+	}
+	stats := &BlockImportStats{
+		Number:     block.NumberU64(),
+		Hash:       block.Hash(),
+		Txs:        len(block.Transactions()),
+		Senders:    milliseconds(timings.senders),
+		Execution:  milliseconds(timings.execution),
+		Validation: milliseconds(timings.validation),
+		Context:    milliseconds(timings.context),
+		TrieCommit: milliseconds(timings.trie),
+		DbWrite:    milliseconds(timings.write),
+		Total:      milliseconds(total),
+		Slowest:    slowest.name,
+	}
+	bc.importStats.Add(stats.Hash, stats)
+
+	if total > slowImportThreshold {
+		importSlowMeter.Mark(1)
+		log.Warn("Slow block import", "number", stats.Number, "hash", stats.Hash, "txs", stats.Txs,
+			"elapsed", common.PrettyDuration(total), "slowest", slowest.name, "stage", common.PrettyDuration(slowest.elapsed))
+	}
+}
+
+//得到最近导入区块的各阶段耗时，按导入顺序排列
+func (bc *BlockChain) ImportStats() []*BlockImportStats {
+
+	stats := make([]*BlockImportStats, 0, bc.importStats.Len())
+	for _, hash := range bc.importStats.Keys() {
+		if entry, exist := bc.importStats.Peek(hash); exist {
+			stats = append(stats, entry.(*BlockImportStats))
+		}
+	}
+	return stats
+}
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/hashicorp/golang-lru"
+)
+
+func TestImportStats(t *testing.T) {
+	cache, _ := lru.New(importStatsLimit)
+	bc := &BlockChain{importStats: cache}
+
+	for i := 1; i <= importStatsLimit+2; i++ {
+		block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(int64(i))})
+		timings := &importTimings{senders: time.Millisecond, execution: 3 * time.Millisecond, write: 2 * time.Millisecond}
+		bc.recordImport(block, timings, 6*time.Millisecond)
+	}
+	stats := bc.ImportStats()
+	if len(stats) != importStatsLimit {
+		t.Fatalf("retained stats mismatch: have %d, want %d", len(stats), importStatsLimit)
+	}
+	if stats[0].Number != 3 || stats[len(stats)-1].Number != importStatsLimit+2 {
+		t.Fatalf("stats order mismatch: first #%d, last #%d", stats[0].Number, stats[len(stats)-1].Number)
+	}
+	last := stats[len(stats)-1]
+	if last.Slowest != "execution" || last.Execution != 3 || last.Total != 6 {
+		t.Fatalf("timings mismatch: have %+v", last)
+	}
+}
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/core/vm"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+var (
+	internalTxPrefix     = []byte("I") // internalTxPrefix + tx hash -> internal transactions of the transaction
+	internalTxAddrPrefix = []byte("A") // internalTxAddrPrefix + address -> transactions with internal transactions touching the address
+)
+
+//在区块导入时记录内部交易的轻量级Tracer
+type InternalTxTracer struct {
+	txHash  common.Hash
+	current []*types.InternalTx
+	pending map[int]int //调用深度 -> 尚未返回的调用在current中的位置
+	results map[common.Hash][]*types.InternalTx
+}
+
+func NewInternalTxTracer() *InternalTxTracer {
+	return &InternalTxTracer{results: make(map[common.Hash][]*types.InternalTx)}
+}
+
+//开始记录一笔交易
+func (t *InternalTxTracer) Start(hash common.Hash) {
+	t.txHash = hash
+	t.current = nil
+	t.pending = make(map[int]int)
+}
+
+//结束记录一笔交易，交易失败时所有内部交易均标记为失败
+func (t *InternalTxTracer) Finish(failed bool) {
+	if failed {
+		for _, itx := range t.current {
+			itx.Failed = true
+		}
+	}
+	if len(t.current) > 0 {
+		t.results[t.txHash] = t.current
+	}
+	t.current = nil
+}
+
+//得到所有已记录的内部交易
+func (t *InternalTxTracer) Results() map[common.Hash][]*types.InternalTx {
+	return t.results
+}
+
+func (t *InternalTxTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+
+	//回到调用者时栈顶为调用结果（失败为0）
+	if index, ok := t.pending[depth]; ok {
+		for d := range t.pending {
+			if d >= depth {
+				delete(t.pending, d)
+			}
+		}
+		if stack.Back(0).Sign() == 0 {
+			for _, itx := range t.current[index:] {
+				itx.Failed = true
+			}
+		} else if t.current[index].Type == types.InternalCreate {
+			t.current[index].To = common.BigToAddress(stack.Back(0))
+		}
+	}
+	if err != nil {
+		return nil
+	}
+
+	itx := &types.InternalTx{From: contract.Address(), Value: new(big.Int), Depth: uint64(depth)}
+	switch op {
+	case vm.CALL, vm.CALLCODE:
+		itx.To, itx.Value = common.BigToAddress(stack.Back(1)), new(big.Int).Set(stack.Back(2))
+		itx.Type = types.InternalCall
+		if op == vm.CALLCODE {
+			itx.Type = types.InternalCallCode
+		}
+	case vm.DELEGATECALL, vm.STATICCALL:
+		itx.To = common.BigToAddress(stack.Back(1))
+		itx.Type = types.InternalDelegateCall
+		if op == vm.STATICCALL {
+			itx.Type = types.InternalStaticCall
+		}
+	case vm.CREATE:
+		itx.Value, itx.Type = new(big.Int).Set(stack.Back(0)), types.InternalCreate
+	case vm.SELFDESTRUCT:
+		itx.To, itx.Type = common.BigToAddress(stack.Back(0)), types.InternalSelfDestruct
+		itx.Value.Set(env.StateDB.GetBalance(contract.Address()))
+		t.current = append(t.current, itx)
+		return nil
+	default:
+		return nil
+	}
+	t.pending[depth] = len(t.current)
+	t.current = append(t.current, itx)
+	return nil
+}
+
+func (t *InternalTxTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	return nil
+}
+
+// GetInternalTxs retrieves the internal transactions recorded for a transaction.
+func GetInternalTxs(db DatabaseReader, hash common.Hash) []*types.InternalTx {
+	data, _ := db.Get(append(internalTxPrefix, hash.Bytes()...))
+	if len(data) == 0 {
+		return nil
+	}
+	var itxs []*types.InternalTx
+	if err := rlp.DecodeBytes(data, &itxs); err != nil {
+		log.Error("Invalid internal transactions RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return itxs
+}
+
+// GetInternalTxRefs retrieves the transactions whose internal transactions
+// touched the given address, oldest first.
+func GetInternalTxRefs(db DatabaseReader, address common.Address) []types.InternalTxRef {
+	data, _ := db.Get(append(internalTxAddrPrefix, address.Bytes()...))
+	if len(data) == 0 {
+		return nil
+	}
+	var refs []types.InternalTxRef
+	if err := rlp.DecodeBytes(data, &refs); err != nil {
+		log.Error("Invalid internal transaction refs RLP", "address", address, "err", err)
+		return nil
+	}
+	return refs
+}
+
+// WriteInternalTxs stores the internal transactions of a block and appends the
+// transactions to the index of every address they touched.
+func WriteInternalTxs(db ethdb.Database, block *types.Block, results map[common.Hash][]*types.InternalTx) error {
+	var (
+		touched   = make(map[common.Address][]types.InternalTxRef)
+		addresses []common.Address
+	)
+	for _, tx := range block.Transactions() {
+		hash := tx.Hash()
+		itxs, ok := results[hash]
+		if !ok {
+			continue
+		}
+		data, err := rlp.EncodeToBytes(itxs)
+		if err != nil {
+			return err
+		}
+		if err := db.Put(append(internalTxPrefix, hash.Bytes()...), data); err != nil {
+			log.Crit("Failed to store internal transactions", "err", err)
+		}
+		seen := make(map[common.Address]bool)
+		for _, itx := range itxs {
+			for _, address := range []common.Address{itx.From, itx.To} {
+				if address != (common.Address{}) && !seen[address] {
+					seen[address] = true
+					if _, ok := touched[address]; !ok {
+						addresses = append(addresses, address)
+					}
+					touched[address] = append(touched[address], types.InternalTxRef{TxHash: hash, BlockNumber: block.NumberU64()})
+				}
+			}
+		}
+	}
+	for _, address := range addresses {
+		data, err := rlp.EncodeToBytes(append(GetInternalTxRefs(db, address), touched[address]...))
+		if err != nil {
+			return err
+		}
+		if err := db.Put(append(internalTxAddrPrefix, address.Bytes()...), data); err != nil {
+			log.Crit("Failed to store internal transaction refs", "err", err)
+		}
+	}
+	return nil
+}
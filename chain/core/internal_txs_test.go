@@ -0,0 +1,123 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/core/vm"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+// callCode returns contract code calling the given address with the given value.
+func callCode(to common.Address, value byte) []byte {
+	code := []byte{0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, value, 0x73}
+	code = append(code, to.Bytes()...)
+	return append(code, 0x61, 0xff, 0xff, byte(vm.CALL))
+}
+
+// Tests that the internal transaction tracer records the calls made by a
+// contract, and marks the calls which failed.
+func TestInternalTxTracer(t *testing.T) {
+	var (
+		sender   = common.HexToAddress("0x01")
+		contract = common.HexToAddress("0x0a")
+		payee    = common.HexToAddress("0x0b")
+		broken   = common.HexToAddress("0x0c")
+	)
+	db, _ := ethdb.NewMemDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	statedb.AddBalance(contract, big.NewInt(10))
+	statedb.SetCode(contract, append(append(callCode(payee, 3), callCode(broken, 0)...), byte(vm.STOP)))
+	statedb.SetCode(broken, []byte{0xfe})
+
+	tracer := NewInternalTxTracer()
+	context := vm.Context{
+		CanTransfer: CanTransfer,
+		Transfer:    Transfer,
+		Origin:      sender,
+		GasPrice:    big.NewInt(1),
+		GasLimit:    big.NewInt(10000000),
+		BlockNumber: big.NewInt(1),
+		Time:        big.NewInt(1),
+		Difficulty:  big.NewInt(1),
+	}
+	evm := vm.NewEVM(context, statedb, params.TestChainConfig, vm.Config{Debug: true, Tracer: tracer})
+
+	hash := common.HexToHash("0x1234")
+	tracer.Start(hash)
+	if _, _, err := evm.Call(vm.AccountRef(sender), contract, nil, 1000000, new(big.Int)); err != nil {
+		t.Fatalf("failed to call contract: %v", err)
+	}
+	tracer.Finish(false)
+
+	itxs := tracer.Results()[hash]
+	if len(itxs) != 2 {
+		t.Fatalf("internal transaction count mismatch: have %d, want 2", len(itxs))
+	}
+	if itx := itxs[0]; itx.Type != types.InternalCall || itx.From != contract || itx.To != payee || itx.Value.Int64() != 3 || itx.Depth != 1 || itx.Failed {
+		t.Errorf("transfer mismatch: have %+v", itx)
+	}
+	if itx := itxs[1]; itx.To != broken || !itx.Failed {
+		t.Errorf("failed call mismatch: have %+v", itx)
+	}
+	if balance := statedb.GetBalance(payee); balance.Int64() != 3 {
+		t.Errorf("payee balance mismatch: have %v, want 3", balance)
+	}
+
+	//交易失败时所有内部交易均标记为失败，没有内部交易的交易不记录
+	tracer.Start(common.HexToHash("0x5678"))
+	evm.Call(vm.AccountRef(sender), contract, nil, 1000000, new(big.Int))
+	tracer.Finish(true)
+	for i, itx := range tracer.Results()[common.HexToHash("0x5678")] {
+		if !itx.Failed {
+			t.Errorf("internal transaction %d of a failed transaction not failed", i)
+		}
+	}
+	tracer.Start(common.HexToHash("0x9abc"))
+	tracer.Finish(false)
+	if _, ok := tracer.Results()[common.HexToHash("0x9abc")]; ok {
+		t.Errorf("transaction without internal transactions recorded")
+	}
+}
+
+// Tests that internal transactions are stored per transaction and indexed by
+// every address they touched across blocks.
+func TestInternalTxStorage(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+
+	var (
+		contract, payee = common.HexToAddress("0x0a"), common.HexToAddress("0x0b")
+		tx1             = types.NewTransaction(protocol.Normal, protocol.NormalCall, 0, contract, new(big.Int), big.NewInt(100000), big.NewInt(1), nil)
+		tx2             = types.NewTransaction(protocol.Normal, protocol.NormalCall, 1, contract, new(big.Int), big.NewInt(100000), big.NewInt(1), nil)
+		itx             = &types.InternalTx{Type: types.InternalCall, From: contract, To: payee, Value: big.NewInt(3), Depth: 1}
+	)
+	block1 := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{tx1}, nil, nil)
+	block2 := types.NewBlock(&types.Header{Number: big.NewInt(2)}, []*types.Transaction{tx2}, nil, nil)
+
+	if err := WriteInternalTxs(db, block1, map[common.Hash][]*types.InternalTx{tx1.Hash(): {itx}}); err != nil {
+		t.Fatalf("failed to write internal transactions: %v", err)
+	}
+	if err := WriteInternalTxs(db, block2, map[common.Hash][]*types.InternalTx{tx2.Hash(): {itx, itx}}); err != nil {
+		t.Fatalf("failed to write internal transactions: %v", err)
+	}
+	if itxs := GetInternalTxs(db, tx1.Hash()); len(itxs) != 1 || itxs[0].To != payee || itxs[0].Value.Int64() != 3 {
+		t.Errorf("internal transactions mismatch: have %v", itxs)
+	}
+	if itxs := GetInternalTxs(db, tx2.Hash()); len(itxs) != 2 {
+		t.Errorf("internal transaction count mismatch: have %d, want 2", len(itxs))
+	}
+	for _, address := range []common.Address{contract, payee} {
+		refs := GetInternalTxRefs(db, address)
+		if len(refs) != 2 || refs[0].TxHash != tx1.Hash() || refs[0].BlockNumber != 1 || refs[1].TxHash != tx2.Hash() || refs[1].BlockNumber != 2 {
+			t.Errorf("address %x: refs mismatch: have %v", address, refs)
+		}
+	}
+	if refs := GetInternalTxRefs(db, common.HexToAddress("0x0c")); refs != nil {
+		t.Errorf("untouched address refs: have %v", refs)
+	}
+}
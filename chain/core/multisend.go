@@ -0,0 +1,60 @@
+package core
+
+import (
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/params"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+//批量转账中每笔转账的日志主题
+var MultiSendTopic = crypto.Keccak256Hash([]byte("MultiSend(address,address,uint256)"))
+
+//解码并校验批量转账交易负载，返回转账列表以及转账总额
+func DecodeMultiSend(data []byte) ([]protocol.MultiSendItem, *big.Int, error) {
+
+	var items []protocol.MultiSendItem
+	if err := rlp.DecodeBytes(data, &items); err != nil || len(items) == 0 {
+		return nil, nil, protocol.ErrInvalidMultiSend
+	}
+	if len(items) > protocol.MaxMultiSendRecipients {
+		return nil, nil, protocol.ErrMultiSendTooLarge
+	}
+	total := new(big.Int)
+	for _, item := range items {
+		if item.Amount == nil || item.Amount.Sign() <= 0 {
+			return nil, nil, protocol.ErrInvalidMultiSend
+		}
+		total.Add(total, item.Amount)
+	}
+	return items, total, nil
+}
+
+//批量转账交易需要的Gas：交易数据的固有Gas加上每个接收账号的转账Gas
+func MultiSendGas(data []byte, recipients int) *big.Int {
+
+	gas := new(big.Int).SetUint64(params.MultiSendRecipientGas)
+	gas.Mul(gas, big.NewInt(int64(recipients)))
+	return gas.Add(gas, IntrinsicGas(data, false, true))
+}
+
+//从sender依次转账到每个接收账号，每笔转账产生一条日志
+func applyMultiSend(statedb *state.StateDB, header *types.Header, sender common.Address, items []protocol.MultiSendItem) {
+
+	for _, item := range items {
+		statedb.SubBalance(sender, item.Amount)
+		statedb.AddBalance(item.Recipient, item.Amount)
+
+		statedb.AddLog(&types.Log{
+			Address:     protocol.MultiSendAddress,
+			Topics:      []common.Hash{MultiSendTopic, sender.Hash(), item.Recipient.Hash()},
+			Data:        common.BigToHash(item.Amount).Bytes(),
+			BlockNumber: header.Number.Uint64(),
+		})
+	}
+}
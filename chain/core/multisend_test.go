@@ -0,0 +1,90 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/core/vm"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+// Tests that multi-send transactions are only accepted from the multi-send fork
+// block on and towards the multi-send system account, charge gas per recipient
+// and pay every recipient at once.
+func TestMultiSendTransaction(t *testing.T) {
+	var (
+		sender     = common.HexToAddress("0x5e")
+		recipients = []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")}
+		author     = common.HexToAddress("0xa0")
+		price      = big.NewInt(2)
+		funds      = big.NewInt(1000000000)
+	)
+	data, _ := rlp.EncodeToBytes([]protocol.MultiSendItem{{Recipient: recipients[0], Amount: big.NewInt(100)}, {Recipient: recipients[1], Amount: big.NewInt(200)}})
+	required := MultiSendGas(data, len(recipients))
+
+	config := *params.TestChainConfig
+	config.MultiSendBlock = big.NewInt(1)
+
+	apply := func(to common.Address, gas *big.Int, balance *big.Int) (*types.Receipt, *state.StateDB, error) {
+		db, _ := ethdb.NewMemDatabase()
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+		statedb.AddBalance(sender, balance)
+		dposContext, _ := types.NewDposContext(db)
+		bokerContext, _ := types.NewBokerContext(db)
+
+		header := &types.Header{Number: big.NewInt(1), Time: big.NewInt(1), Difficulty: big.NewInt(1), GasLimit: big.NewInt(10000000)}
+		tx := types.NewTransaction(protocol.SystemBase, protocol.MultiSend, 0, to, new(big.Int), gas, price, data)
+		msg := types.NewMessage(sender, &to, 0, new(big.Int), gas, price, nil, data, nil, nil, true, protocol.SystemBase, protocol.MultiSend)
+
+		statedb.Prepare(tx.Hash(), common.Hash{}, 0)
+		gp := new(GasPool).AddGas(header.GasLimit)
+		sp := new(big.Int).SetInt64(protocol.MaxBlockSize)
+		receipt, _, err := applyMessage(&config, dposContext, bokerContext, nil, &author, gp, sp, statedb, header, tx, new(big.Int), vm.Config{}, msg, nil)
+		return receipt, statedb, err
+	}
+
+	config.MultiSendBlock = big.NewInt(2)
+	if _, statedb, err := apply(protocol.MultiSendAddress, required, funds); err != protocol.ErrMultiSendDisabled || statedb.GetBalance(sender).Cmp(funds) != 0 {
+		t.Errorf("before fork: have %v, want %v", err, protocol.ErrMultiSendDisabled)
+	}
+	config.MultiSendBlock = big.NewInt(1)
+
+	if _, _, err := apply(common.HexToAddress("0xbad"), required, funds); err != protocol.ErrInvalidMultiSend {
+		t.Errorf("other recipient account: have %v, want %v", err, protocol.ErrInvalidMultiSend)
+	}
+	if _, _, err := apply(protocol.MultiSendAddress, new(big.Int).Sub(required, common.Big1), funds); err != vm.ErrOutOfGas {
+		t.Errorf("gas below recipient cost: have %v, want %v", err, vm.ErrOutOfGas)
+	}
+	cost := new(big.Int).Mul(required, price)
+	if _, _, err := apply(protocol.MultiSendAddress, required, new(big.Int).Add(cost, big.NewInt(299))); err != protocol.ErrMultiSendInsufficient {
+		t.Errorf("insufficient funds: have %v, want %v", err, protocol.ErrMultiSendInsufficient)
+	}
+
+	receipt, statedb, err := apply(protocol.MultiSendAddress, new(big.Int).Mul(required, common.Big2), funds)
+	if err != nil {
+		t.Fatalf("failed to apply multi-send: %v", err)
+	}
+	if receipt.GasUsed.Cmp(required) != 0 {
+		t.Errorf("gas used mismatch: have %v, want %v", receipt.GasUsed, required)
+	}
+	if want := new(big.Int).Sub(funds, new(big.Int).Add(cost, big.NewInt(300))); statedb.GetBalance(sender).Cmp(want) != 0 {
+		t.Errorf("sender balance mismatch: have %v, want %v", statedb.GetBalance(sender), want)
+	}
+	for i, amount := range []int64{100, 200} {
+		if balance := statedb.GetBalance(recipients[i]); balance.Int64() != amount {
+			t.Errorf("recipient %d balance mismatch: have %v, want %d", i, balance, amount)
+		}
+	}
+	if len(receipt.Logs) != len(recipients) {
+		t.Errorf("log count mismatch: have %d, want %d", len(receipt.Logs), len(recipients))
+	}
+	if statedb.GetNonce(sender) != 1 {
+		t.Errorf("sender nonce mismatch: have %d, want 1", statedb.GetNonce(sender))
+	}
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+var txNamePrefix = []byte("tN") // txNamePrefix + keccak(name) -> transactions carrying the name
+
+//参与索引的交易名称最大长度，超过该长度或为空的名称不索引
+const MaxIndexedNameLength = 64
+
+//按名称索引的交易位置
+type TxNameEntry struct {
+	TxHash      common.Hash
+	BlockHash   common.Hash
+	BlockNumber uint64
+	Index       uint64 //交易在区块中的序号
+}
+
+//开启交易名称索引，之后导入的区块会按Name字段记录交易
+func (bc *BlockChain) EnableNameIndex() {
+	bc.procmu.Lock()
+	defer bc.procmu.Unlock()
+	bc.nameIndex = true
+}
+
+//判断交易名称是否可以被索引
+func IndexableName(name []byte) bool {
+	return len(name) > 0 && len(name) <= MaxIndexedNameLength
+}
+
+func txNameKey(name []byte) []byte {
+	return append(common.CopyBytes(txNamePrefix), crypto.Keccak256(name)...)
+}
+
+// GetTxNames retrieves the transactions recorded under a name, oldest first.
+// Entries of blocks that later left the canonical chain are not removed.
+func GetTxNames(db DatabaseReader, name []byte) []*TxNameEntry {
+	if !IndexableName(name) {
+		return nil
+	}
+	data, _ := db.Get(txNameKey(name))
+	if len(data) == 0 {
+		return nil
+	}
+	var entries []*TxNameEntry
+	if err := rlp.DecodeBytes(data, &entries); err != nil {
+		log.Error("Invalid transaction name RLP", "name", string(name), "err", err)
+		return nil
+	}
+	return entries
+}
+
+// WriteTxNames appends the transactions of a block carrying an indexable name
+// to the index of that name.
+func WriteTxNames(db ethdb.Database, block *types.Block) error {
+	var (
+		named = make(map[string][]*TxNameEntry)
+		names []string
+	)
+	for i, tx := range block.Transactions() {
+		name := tx.Name()
+		if !IndexableName(name) {
+			continue
+		}
+		if _, ok := named[string(name)]; !ok {
+			names = append(names, string(name))
+		}
+		named[string(name)] = append(named[string(name)], &TxNameEntry{
+			TxHash:      tx.Hash(),
+			BlockHash:   block.Hash(),
+			BlockNumber: block.NumberU64(),
+			Index:       uint64(i),
+		})
+	}
+	for _, name := range names {
+		data, err := rlp.EncodeToBytes(append(GetTxNames(db, []byte(name)), named[name]...))
+		if err != nil {
+			return err
+		}
+		if err := db.Put(txNameKey([]byte(name)), data); err != nil {
+			log.Crit("Failed to store transaction names", "err", err)
+		}
+	}
+	return nil
+}
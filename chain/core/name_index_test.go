@@ -0,0 +1,46 @@
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+)
+
+func TestTxNameIndex(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+
+	newTx := func(nonce uint64, name []byte) *types.Transaction {
+		return types.NewExtraTransaction(protocol.Extra, protocol.Word, nonce, common.Address{0x01}, big.NewInt(1), big.NewInt(21000), big.NewInt(1), name, []byte("memo"), 0)
+	}
+	invoice := []byte("invoice-42")
+	blocks := []*types.Block{
+		types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{newTx(0, invoice), newTx(1, nil)}, nil, nil),
+		types.NewBlock(&types.Header{Number: big.NewInt(2)}, []*types.Transaction{newTx(2, bytes.Repeat([]byte("x"), MaxIndexedNameLength+1)), newTx(3, invoice)}, nil, nil),
+	}
+	for _, block := range blocks {
+		if err := WriteTxNames(db, block); err != nil {
+			t.Fatalf("failed to write names of block %d: %v", block.NumberU64(), err)
+		}
+	}
+	entries := GetTxNames(db, invoice)
+	if len(entries) != 2 {
+		t.Fatalf("entry count mismatch: have %d, want 2", len(entries))
+	}
+	for i, want := range []struct {
+		block *types.Block
+		index uint64
+	}{{blocks[0], 0}, {blocks[1], 1}} {
+		tx := want.block.Transactions()[want.index]
+		if entries[i].TxHash != tx.Hash() || entries[i].BlockHash != want.block.Hash() || entries[i].BlockNumber != want.block.NumberU64() || entries[i].Index != want.index {
+			t.Errorf("entry %d mismatch: have %+v", i, entries[i])
+		}
+	}
+	if entries := GetTxNames(db, bytes.Repeat([]byte("x"), MaxIndexedNameLength+1)); entries != nil {
+		t.Errorf("oversized name indexed: %v", entries)
+	}
+}
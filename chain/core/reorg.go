@@ -0,0 +1,47 @@
+package core
+
+import (
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/log"
+)
+
+//出块节点在自己的出块时间段内产生的系统交易(轮换出块节点、股权分币)只在原分叉上有效,
+//新分叉的出块节点会产生自己的这类交易，链重组时不重新放入交易池
+func isProducerTx(tx *types.Transaction) bool {
+
+	switch tx.Major() {
+	case protocol.SystemBase:
+		return tx.Minor() == protocol.VoteEpoch
+	case protocol.Stock:
+		return tx.Minor() == protocol.StockAssignGas
+	}
+	return false
+}
+
+//将链重组时被丢弃(没有被新分叉打包)的交易分为需要重新放入交易池的交易,
+//以及由出块节点产生、不再重新放入交易池的系统交易
+func reorgTxs(dropped types.Transactions) (reinject, skipped types.Transactions) {
+
+	for _, tx := range dropped {
+		if isProducerTx(tx) {
+			skipped = append(skipped, tx)
+		} else {
+			reinject = append(reinject, tx)
+		}
+	}
+	return reinject, skipped
+}
+
+//链重组以后按区块链的当前区块重置交易池并放入被丢弃的交易。重组事件与新区块头事件的到达顺序不确定,
+//区块链写入新的区块以后才会释放锁，所以这里得到的当前区块一定是新分叉上的区块
+func (pool *TxPool) reinjectRemoved(txs types.Transactions) {
+
+	var accepted int
+	for _, err := range pool.Reinject(pool.chain.CurrentBlock().Header(), txs) {
+		if err == nil {
+			accepted++
+		}
+	}
+	log.Debug("Reinjected reorged transactions", "count", len(txs), "accepted", accepted)
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/consensus/ethash"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/core/vm"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+// makeProducerFork assembles a fork on top of parent sealed by the given producer,
+// containing the given transactions per block, and writes it into the database.
+func makeProducerFork(db ethdb.Database, parent *types.Block, producer common.Address, txs [][]*types.Transaction) []*types.Block {
+	blocks := make([]*types.Block, len(txs))
+	for i, list := range txs {
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Coinbase:   producer,
+			Root:       parent.Root(),
+			Difficulty: parent.Difficulty(),
+			Number:     new(big.Int).Add(parent.Number(), common.Big1),
+			Time:       new(big.Int).Add(parent.Time(), big.NewInt(protocol.BlockInterval)),
+			GasLimit:   parent.GasLimit(),
+			GasUsed:    new(big.Int),
+			DposProto:  parent.Header().DposProto,
+			BokerProto: parent.Header().BokerProto,
+		}
+		blocks[i] = types.NewBlock(header, list, nil, nil)
+		if err := WriteBlock(db, blocks[i]); err != nil {
+			panic(err)
+		}
+		parent = blocks[i]
+	}
+	return blocks
+}
+
+// Tests that when the fork of one producer is replaced by the longer fork of a
+// competing producer, the transactions of the abandoned blocks which were not
+// included by the new fork are returned to the transaction pool, while the system
+// transactions bound to the slot of the abandoned producer are not.
+func TestReorgReinjectsTransactions(t *testing.T) {
+	var (
+		db, _   = ethdb.NewMemDatabase()
+		key, _  = crypto.GenerateKey()
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &Genesis{Config: params.TestChainConfig, Alloc: GenesisAlloc{addr: {Balance: big.NewInt(1000000000)}}}
+		genesis = gspec.MustCommit(db)
+	)
+	chain, err := NewBlockChain(db, gspec.Config, ethash.NewFullFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	sign := func(tx *types.Transaction) *types.Transaction {
+		signed, err := types.SignTx(tx, types.HomesteadSigner{}, key)
+		if err != nil {
+			t.Fatalf("failed to sign transaction: %v", err)
+		}
+		return signed
+	}
+	var (
+		shared  = sign(types.NewTransaction(protocol.Normal, protocol.NormalCall, 0, common.Address{0x01}, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil))
+		dropped = sign(types.NewTransaction(protocol.Normal, protocol.NormalCall, 1, common.Address{0x01}, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil))
+		rotate  = sign(types.NewBaseTransaction(protocol.SystemBase, protocol.VoteEpoch, 2, common.Address{0x02}, new(big.Int), nil))
+	)
+	// Producer A seals two blocks, the competing producer B three, only re-including
+	// the first transaction of A
+	forkA := makeProducerFork(db, genesis, common.Address{0xa}, [][]*types.Transaction{{shared, rotate}, {dropped}})
+	forkB := makeProducerFork(db, genesis, common.Address{0xb}, [][]*types.Transaction{{shared}, nil, nil})
+
+	for _, block := range forkA {
+		chain.insert(block)
+	}
+	pool := NewTxPool(testTxPoolConfig, gspec.Config, chain)
+	defer pool.Stop()
+
+	removed := make(chan RemovedTransactionEvent, 1)
+	sub := chain.SubscribeRemovedTransactionEvent(removed)
+	defer sub.Unsubscribe()
+
+	if err := chain.reorg(forkA[len(forkA)-1], forkB[len(forkB)-1]); err != nil {
+		t.Fatalf("failed to reorg: %v", err)
+	}
+	select {
+	case ev := <-removed:
+		if len(ev.Txs) != 1 || ev.Txs[0].Hash() != dropped.Hash() {
+			t.Fatalf("removed transactions mismatch: have %v, want [%x]", ev.Txs, dropped.Hash())
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("removed transaction event timeout")
+	}
+	for deadline := time.Now().Add(time.Second); pool.Get(dropped.Hash()) == nil; {
+		if time.Now().After(deadline) {
+			t.Fatalf("dropped transaction not reinjected into the pool")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if pool.Get(shared.Hash()) != nil {
+		t.Errorf("re-included transaction reinjected into the pool")
+	}
+	if pool.Get(rotate.Hash()) != nil {
+		t.Errorf("producer transaction of abandoned fork reinjected into the pool")
+	}
+	if hash, _, _ := GetTxLookupEntry(db, dropped.Hash()); hash != (common.Hash{}) {
+		t.Errorf("lookup entry of dropped transaction not deleted")
+	}
+}
+
+// Tests that the system transactions sealed by a producer in its own slot are told
+// apart from the ones submitted by users.
+func TestReorgTxsSkipsProducerTransactions(t *testing.T) {
+	var (
+		normal = types.NewTransaction(protocol.Normal, protocol.NormalCall, 0, common.Address{}, new(big.Int), big.NewInt(21000), big.NewInt(1), nil)
+		vote   = types.NewBaseTransaction(protocol.SystemBase, protocol.VoteUser, 1, common.Address{}, new(big.Int), nil)
+		rotate = types.NewBaseTransaction(protocol.SystemBase, protocol.VoteEpoch, 2, common.Address{}, new(big.Int), nil)
+		assign = types.NewStockTransaction(protocol.Stock, protocol.StockAssignGas, 3, common.Address{}, new(big.Int), new(big.Int), new(big.Int), nil, nil, 0)
+	)
+	reinject, skipped := reorgTxs(types.Transactions{normal, vote, rotate, assign})
+	if len(reinject) != 2 || reinject[0] != normal || reinject[1] != vote {
+		t.Errorf("reinjected transactions mismatch: have %v", reinject)
+	}
+	if len(skipped) != 2 || skipped[0] != rotate || skipped[1] != assign {
+		t.Errorf("skipped transactions mismatch: have %v", skipped)
+	}
+}
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/log"
+)
+
+var (
+	errMissingCanonicalHash = errors.New("missing canonical hash")
+	errMissingBlock         = errors.New("missing block body or header")
+	errMissingReceipts      = errors.New("missing receipts")
+	errNoRepairPoint        = errors.New("no verifiable block found, the genesis block is damaged")
+)
+
+//读取主链上指定高度的区块，并检查区块头哈希、交易根、叔块根以及已存储收据的根
+func readCanonicalBlock(db DatabaseReader, number uint64) (*types.Block, error) {
+
+	hash := GetCanonicalHash(db, number)
+	if hash == (common.Hash{}) {
+		return nil, errMissingCanonicalHash
+	}
+	//直接从数据库读取，避免之后修改缓存中的区块
+	block := GetBlock(db, hash, number)
+	if block == nil {
+		return nil, errMissingBlock
+	}
+	header := block.Header()
+	if computed := header.Hash(); computed != hash {
+		return nil, fmt.Errorf("header hash mismatch (canonical %x, computed %x)", hash, computed)
+	}
+	if root := types.DeriveSha(block.Transactions()); root != header.TxHash {
+		return nil, fmt.Errorf("transaction root mismatch (header %x, computed %x)", header.TxHash, root)
+	}
+	if hash := types.CalcUncleHash(block.Uncles()); hash != header.UncleHash {
+		return nil, fmt.Errorf("uncle root mismatch (header %x, computed %x)", header.UncleHash, hash)
+	}
+	receipts := GetBlockReceipts(db, hash, number)
+	if receipts == nil && len(block.Transactions()) > 0 {
+		return nil, errMissingReceipts
+	}
+	if root := types.DeriveSha(receipts); root != header.ReceiptHash {
+		return nil, fmt.Errorf("stored receipt root mismatch (header %x, computed %x)", header.ReceiptHash, root)
+	}
+	return block, nil
+}
+
+//检查主链上指定高度的区块是否完整可用：区块数据与区块头中的根一致，总难度、状态(从statedb读取)以及Dpos/Boker上下文可以读取
+func CheckStoredBlock(db ethdb.Database, statedb state.Database, number uint64) (*types.Block, error) {
+
+	block, err := readCanonicalBlock(db, number)
+	if err != nil {
+		return nil, err
+	}
+	header := block.Header()
+	hash := block.Hash()
+	if GetTd(db, hash, number) == nil {
+		return nil, errors.New("missing total difficulty")
+	}
+	if _, err := state.New(header.Root, statedb); err != nil {
+		return nil, fmt.Errorf("missing state: %v", err)
+	}
+	if _, err := types.NewDposContextFromProto(db, header.DposProto); err != nil {
+		return nil, fmt.Errorf("missing dpos context: %v", err)
+	}
+	if _, err := types.NewBokerContextFromProto(db, header.BokerProto); err != nil {
+		return nil, fmt.Errorf("missing boker context: %v", err)
+	}
+	return block, nil
+}
+
+//从from开始向前查找最后一个完整可用的主链区块
+func FindRepairPoint(db ethdb.Database, statedb state.Database, from uint64) (*types.Block, error) {
+
+	for number := from; ; number-- {
+		block, err := CheckStoredBlock(db, statedb, number)
+		if err == nil {
+			return block, nil
+		}
+		log.Warn("Damaged block found", "number", number, "err", err)
+		if number == 0 {
+			return nil, errNoRepairPoint
+		}
+	}
+}
+
+//数据库损坏时将链头截断到最后一个完整可用的主链区块，并删除其后的主链索引，
+//返回截断前后的区块高度(链头完整时两者相同)
+func RepairHead(db ethdb.Database) (uint64, uint64, error) {
+
+	//链头区块丢失时从链头区块头(或者其所在的高度)开始查找
+	head := GetHeadBlockHash(db)
+	if head == (common.Hash{}) {
+		head = GetHeadHeaderHash(db)
+	}
+	if head == (common.Hash{}) {
+		return 0, 0, nil
+	}
+	from := GetBlockNumber(db, head)
+	if from == MissingNumber {
+		return 0, 0, fmt.Errorf("head block number missing [%x…]", head[:4])
+	}
+	block, err := FindRepairPoint(db, state.NewDatabase(db), from)
+	if err != nil {
+		return from, 0, err
+	}
+	to := block.NumberU64()
+	if to == from && block.Hash() == GetHeadBlockHash(db) {
+		return from, to, nil
+	}
+	for number := to + 1; number <= from; number++ {
+		DeleteCanonicalHash(db, number)
+	}
+	if err := WriteHeadBlockHash(db, block.Hash()); err != nil {
+		return from, to, err
+	}
+	if err := WriteHeadHeaderHash(db, block.Hash()); err != nil {
+		return from, to, err
+	}
+	if err := WriteHeadFastBlockHash(db, block.Hash()); err != nil {
+		return from, to, err
+	}
+	log.Warn("Repaired damaged chain database", "from", from, "to", to, "hash", block.Hash())
+	return from, to, nil
+}
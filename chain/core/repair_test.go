@@ -0,0 +1,54 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+// Tests that a damaged head block is truncated to the last verifiable block,
+// and that an intact head is left untouched.
+func TestRepairHead(t *testing.T) {
+	var (
+		db, _   = ethdb.NewMemDatabase()
+		gspec   = &Genesis{Config: params.TestChainConfig}
+		genesis = gspec.MustCommit(db)
+	)
+	blocks := makeProducerFork(db, genesis, common.Address{0xa}, [][]*types.Transaction{nil, nil, nil})
+	for _, block := range blocks {
+		WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		WriteTd(db, block.Hash(), block.NumberU64(), new(big.Int).Add(block.Number(), common.Big1))
+	}
+	head := blocks[len(blocks)-1]
+	WriteHeadBlockHash(db, head.Hash())
+	WriteHeadHeaderHash(db, head.Hash())
+
+	if from, to, err := RepairHead(db); err != nil || from != 3 || to != 3 || GetHeadBlockHash(db) != head.Hash() {
+		t.Fatalf("intact chain: have %d -> %d (%v), want untouched", from, to, err)
+	}
+	// Damage the bodies of the two topmost blocks
+	tx := types.NewTransaction(protocol.Normal, protocol.NormalCall, 0, common.Address{0x01}, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil)
+	for _, block := range blocks[1:] {
+		WriteBody(db, block.Hash(), block.NumberU64(), &types.Body{Transactions: []*types.Transaction{tx}})
+	}
+	from, to, err := RepairHead(db)
+	if err != nil || from != 3 || to != 1 {
+		t.Fatalf("damaged chain: have %d -> %d (%v), want 3 -> 1", from, to, err)
+	}
+	if hash := GetHeadBlockHash(db); hash != blocks[0].Hash() {
+		t.Fatalf("head block mismatch: have %x, want %x", hash, blocks[0].Hash())
+	}
+	if hash := GetHeadHeaderHash(db); hash != blocks[0].Hash() {
+		t.Fatalf("head header mismatch: have %x, want %x", hash, blocks[0].Hash())
+	}
+	for number := uint64(2); number <= 3; number++ {
+		if hash := GetCanonicalHash(db, number); hash != (common.Hash{}) {
+			t.Fatalf("canonical hash #%d not removed: %x", number, hash)
+		}
+	}
+}
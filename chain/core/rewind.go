@@ -0,0 +1,30 @@
+package core
+
+import (
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/log"
+)
+
+//回滚区块链(debug_setHead)的结果
+type RewindReport struct {
+	From         uint64                 `json:"from"`                   //回滚前的区块高度
+	To           uint64                 `json:"to"`                     //回滚后的区块高度
+	Finalized    uint64                 `json:"finalized"`              //已确认(不可逆)的区块高度
+	Abandoned    int                    `json:"abandoned"`              //被丢弃的区块数量
+	RecoveryFile string                 `json:"recoveryFile,omitempty"` //被丢弃区块的备份文件(可以使用admin_importChain重新导入)
+	Reinjected   []common.Hash          `json:"reinjected"`             //重新放入交易池的交易
+	Dropped      map[common.Hash]string `json:"dropped"`                //无法重新放入交易池的交易以及原因
+}
+
+//区块链回滚到newHead以后按新的状态重置交易池，并将被丢弃区块中的交易重新放入交易池，返回每笔交易的结果
+func (pool *TxPool) Reinject(newHead *types.Header, txs types.Transactions) []error {
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.reset(nil, newHead)
+
+	log.Debug("Reinjecting rewound transactions", "count", len(txs))
+	return pool.addTxsLocked(txs, false)
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+var richListKey = []byte("richList") // richListKey -> balance ranking and total supply of the canonical head
+
+// GetRichList retrieves the balance ranking maintained at block import.
+func GetRichList(db DatabaseReader) *types.RichList {
+	data, _ := db.Get(richListKey)
+	if len(data) == 0 {
+		return nil
+	}
+	list := new(types.RichList)
+	if err := rlp.DecodeBytes(data, list); err != nil {
+		log.Error("Invalid rich list RLP", "err", err)
+		return nil
+	}
+	return list
+}
+
+// WriteRichList stores the balance ranking of the canonical head.
+func WriteRichList(db ethdb.Putter, list *types.RichList) error {
+	data, err := rlp.EncodeToBytes(list)
+	if err != nil {
+		return err
+	}
+	if err := db.Put(richListKey, data); err != nil {
+		log.Crit("Failed to store rich list", "err", err)
+	}
+	return nil
+}
+
+//开启余额排行榜，之后成为规范链头的区块会更新排行榜前size个账号以及总发行量
+func (bc *BlockChain) EnableRichList(size int) {
+	bc.procmu.Lock()
+	defer bc.procmu.Unlock()
+	bc.richListSize = size
+}
+
+//根据区块中修改过的账号更新排行榜，排行榜不是基于父区块时(首次开启或者链重组)遍历整个状态重建
+func (bc *BlockChain) updateRichList(block *types.Block, dirty []common.Address, statedb *state.StateDB) error {
+
+	size := uint64(bc.richListSize)
+	list := GetRichList(bc.chainDb)
+	if list == nil || list.Size != size || list.Head != block.ParentHash() {
+		return bc.rebuildRichList(block, statedb, size)
+	}
+	parent := bc.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return bc.rebuildRichList(block, statedb, size)
+	}
+	parentState, err := state.New(parent.Root(), bc.stateCache)
+	if err != nil {
+		return bc.rebuildRichList(block, statedb, size)
+	}
+
+	for _, addr := range dirty {
+		balance := statedb.GetBalance(addr)
+		list.TotalSupply.Add(list.TotalSupply, balance)
+		list.TotalSupply.Sub(list.TotalSupply, parentState.GetBalance(addr))
+		updateRichAccount(list, addr, balance, 2*size)
+	}
+	//排行榜中的账号余额减少后无法确定补位的账号
+	if !list.Complete && uint64(len(list.Accounts)) < size {
+		return bc.rebuildRichList(block, statedb, size)
+	}
+	list.Head, list.Number = block.Hash(), block.NumberU64()
+	return WriteRichList(bc.chainDb, list)
+}
+
+//遍历区块的状态重建排行榜
+func (bc *BlockChain) rebuildRichList(block *types.Block, statedb *state.StateDB, size uint64) error {
+
+	start := time.Now()
+	list := &types.RichList{
+		Head:        block.Hash(),
+		Number:      block.NumberU64(),
+		Size:        size,
+		TotalSupply: new(big.Int),
+		Complete:    true,
+	}
+	err := statedb.ForEachBalance(func(addr common.Address, balance *big.Int) bool {
+		list.TotalSupply.Add(list.TotalSupply, balance)
+		updateRichAccount(list, addr, balance, 2*size)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("Rebuilt rich list", "number", block.NumberU64(), "accounts", len(list.Accounts), "elapsed", common.PrettyDuration(time.Since(start)))
+	return WriteRichList(bc.chainDb, list)
+}
+
+//更新账号在排行榜中的位置，排行榜始终是余额最大的前len(Accounts)个账号
+func updateRichAccount(list *types.RichList, addr common.Address, balance *big.Int, capacity uint64) {
+
+	var last *big.Int
+	if n := len(list.Accounts); n > 0 {
+		last = list.Accounts[n-1].Balance
+	}
+	ranked := false
+	for i, account := range list.Accounts {
+		if account.Address == addr {
+			list.Accounts = append(list.Accounts[:i], list.Accounts[i+1:]...)
+			ranked = true
+			break
+		}
+	}
+	if balance.Sign() == 0 {
+		return
+	}
+	//排行榜不完整时，只有不小于排行榜最后一名的账号能确定名次
+	if !list.Complete {
+		if last == nil || balance.Cmp(last) < 0 || (!ranked && balance.Cmp(last) == 0) {
+			return
+		}
+	}
+	index := sort.Search(len(list.Accounts), func(i int) bool {
+		return list.Accounts[i].Balance.Cmp(balance) < 0
+	})
+	list.Accounts = append(list.Accounts, types.RichAccount{})
+	copy(list.Accounts[index+1:], list.Accounts[index:])
+	list.Accounts[index] = types.RichAccount{Address: addr, Balance: new(big.Int).Set(balance)}
+
+	if uint64(len(list.Accounts)) > capacity {
+		list.Accounts = list.Accounts[:capacity]
+		list.Complete = false
+	}
+}
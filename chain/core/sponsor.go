@@ -0,0 +1,82 @@
+package core
+
+import (
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+//代付签名哈希的前缀，防止与交易签名哈希混淆
+var sponsorPrefix = []byte("tina-sponsor")
+
+//是否是由代付账号支付Gas的交易
+func IsSponsored(tx *types.Transaction) bool {
+	return tx.Major() == protocol.Normal && tx.Minor() == protocol.Sponsored
+}
+
+//代付账号需要签名的哈希，包括链ID、发送账号以及交易中除扩展数据以外的字段(含交易类型)，
+//签名只对该发送账号的该Nonce有效
+func SponsorHash(chainId *big.Int, sender common.Address, tx *types.Transaction) common.Hash {
+
+	if chainId == nil {
+		chainId = new(big.Int)
+	}
+	data, _ := rlp.EncodeToBytes([]interface{}{
+		sponsorPrefix,
+		chainId,
+		sender,
+		tx.Major(),
+		tx.Minor(),
+		tx.Nonce(),
+		tx.GasPrice(),
+		tx.Gas(),
+		tx.To(),
+		tx.Value(),
+		tx.Data(),
+		tx.Name(),
+		tx.Encryption(),
+	})
+	return crypto.Keccak256Hash(data)
+}
+
+//由交易扩展数据中的签名[R || S || V](V为0/1)得到代付账号
+func TxSponsor(chainId *big.Int, sender common.Address, tx *types.Transaction) (common.Address, error) {
+
+	sig := tx.Extra()
+	if !IsSponsored(tx) || len(sig) != 65 || sig[64] > 1 {
+		return common.Address{}, protocol.ErrInvalidSponsor
+	}
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])
+	if !crypto.ValidateSignatureValues(sig[64], r, s, true) {
+		return common.Address{}, protocol.ErrInvalidSponsor
+	}
+	hash := SponsorHash(chainId, sender, tx)
+	pub, err := crypto.Ecrecover(hash[:], sig)
+	if err != nil || len(pub) == 0 || pub[0] != 4 {
+		return common.Address{}, protocol.ErrInvalidSponsor
+	}
+	var payer common.Address
+	copy(payer[:], crypto.Keccak256(pub[1:])[12:])
+	if payer == sender {
+		return common.Address{}, protocol.ErrInvalidSponsor
+	}
+	return payer, nil
+}
+
+//发送账号需要支付的最大费用，代付分叉之后代付交易的Gas由代付账号支付
+func senderCost(tx *types.Transaction, sponsor bool) *big.Int {
+
+	if sponsor && IsSponsored(tx) {
+		return tx.Value()
+	}
+	return tx.Cost()
+}
+
+//代付账号需要支付的最大费用
+func sponsorCost(tx *types.Transaction) *big.Int {
+	return new(big.Int).Mul(tx.Gas(), tx.GasPrice())
+}
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+)
+
+// sponsoredTransaction creates a value transfer of sender whose gas is paid by
+// the account of payer.
+func sponsoredTransaction(t *testing.T, chainId *big.Int, nonce uint64, to common.Address, sender, payer *ecdsa.PrivateKey) *types.Transaction {
+	tx := types.NewExtraTransaction(protocol.Normal, protocol.Sponsored, nonce, to, big.NewInt(100), big.NewInt(21000), big.NewInt(2), nil, make([]byte, 65), 0)
+
+	hash := SponsorHash(chainId, crypto.PubkeyToAddress(sender.PublicKey), tx)
+	sig, err := crypto.Sign(hash[:], payer)
+	if err != nil {
+		t.Fatalf("failed to sign sponsorship: %v", err)
+	}
+	tx.SetExtra(sig)
+
+	signed, err := types.SignTx(tx, types.HomesteadSigner{}, sender)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	return signed
+}
+
+// Tests that the gas of sponsored transactions is charged to and refunded to
+// the sponsor, and that invalid or underfunded sponsorships are rejected.
+func TestSponsoredTransaction(t *testing.T) {
+	var (
+		senderKey, _ = crypto.GenerateKey()
+		payerKey, _  = crypto.GenerateKey()
+		sender       = crypto.PubkeyToAddress(senderKey.PublicKey)
+		payer        = crypto.PubkeyToAddress(payerKey.PublicKey)
+		to           = common.HexToAddress("0x0b")
+	)
+	env := newBokerTestEnv(t)
+	config := *env.config
+	config.SponsorBlock = big.NewInt(1)
+	env.config = &config
+	chainId := env.config.ChainId
+	env.statedb.AddBalance(sender, big.NewInt(100))
+	env.statedb.AddBalance(payer, big.NewInt(50000))
+
+	receipt, err := env.apply(sender, sponsoredTransaction(t, chainId, 0, to, senderKey, payerKey))
+	if err != nil {
+		t.Fatalf("failed to apply sponsored transaction: %v", err)
+	}
+	if receipt.GasUsed.Uint64() != 21000 {
+		t.Errorf("gas used mismatch: have %v, want %v", receipt.GasUsed, 21000)
+	}
+	if balance := env.statedb.GetBalance(sender); balance.Sign() != 0 {
+		t.Errorf("sender balance mismatch: have %v, want 0", balance)
+	}
+	if balance := env.statedb.GetBalance(payer); balance.Int64() != 50000-2*21000 {
+		t.Errorf("sponsor balance mismatch: have %v, want %v", balance, 50000-2*21000)
+	}
+	if balance := env.statedb.GetBalance(to); balance.Int64() != 100 {
+		t.Errorf("recipient balance mismatch: have %v, want 100", balance)
+	}
+
+	//代付签名只对签名时的发送账号和Nonce有效
+	env.statedb.AddBalance(sender, big.NewInt(100))
+	tx := sponsoredTransaction(t, chainId, 0, to, senderKey, payerKey)
+	tx = types.NewExtraTransaction(protocol.Normal, protocol.Sponsored, 1, to, tx.Value(), tx.Gas(), tx.GasPrice(), nil, tx.Extra(), 0)
+	tx, _ = types.SignTx(tx, types.HomesteadSigner{}, senderKey)
+	if _, err := env.apply(sender, tx); err != protocol.ErrInvalidSponsor {
+		t.Errorf("stale sponsorship: have %v, want %v", err, protocol.ErrInvalidSponsor)
+	}
+	//代付签名包括交易的文件名称等字段
+	tx = sponsoredTransaction(t, chainId, 1, to, senderKey, payerKey)
+	tx = types.NewExtraTransaction(protocol.Normal, protocol.Sponsored, 1, to, tx.Value(), tx.Gas(), tx.GasPrice(), []byte("name"), tx.Extra(), 0)
+	tx, _ = types.SignTx(tx, types.HomesteadSigner{}, senderKey)
+	if _, err := env.apply(sender, tx); err != protocol.ErrInvalidSponsor {
+		t.Errorf("altered name: have %v, want %v", err, protocol.ErrInvalidSponsor)
+	}
+	if _, err := env.apply(sender, sponsoredTransaction(t, chainId, 1, to, senderKey, senderKey)); err != protocol.ErrInvalidSponsor {
+		t.Errorf("self sponsorship: have %v, want %v", err, protocol.ErrInvalidSponsor)
+	}
+	if _, err := env.apply(sender, sponsoredTransaction(t, chainId, 1, to, senderKey, payerKey)); err != protocol.ErrSponsorInsufficient {
+		t.Errorf("underfunded sponsor: have %v, want %v", err, protocol.ErrSponsorInsufficient)
+	}
+	if balance := env.statedb.GetBalance(sender); balance.Int64() != 100 {
+		t.Errorf("rejected transactions charged the sender: balance %v", balance)
+	}
+}
+
+// Tests that before the sponsor fork sponsored transactions are processed as
+// normal transactions, with the gas charged to the sender.
+func TestSponsoredTransactionBeforeFork(t *testing.T) {
+	var (
+		senderKey, _ = crypto.GenerateKey()
+		payerKey, _  = crypto.GenerateKey()
+		sender       = crypto.PubkeyToAddress(senderKey.PublicKey)
+		payer        = crypto.PubkeyToAddress(payerKey.PublicKey)
+		to           = common.HexToAddress("0x0b")
+	)
+	env := newBokerTestEnv(t)
+	config := *env.config
+	config.SponsorBlock = big.NewInt(2)
+	env.config = &config
+	env.statedb.AddBalance(sender, big.NewInt(100+2*21000))
+	env.statedb.AddBalance(payer, big.NewInt(50000))
+
+	if _, err := env.apply(sender, sponsoredTransaction(t, config.ChainId, 0, to, senderKey, payerKey)); err != nil {
+		t.Fatalf("failed to apply transaction: %v", err)
+	}
+	if balance := env.statedb.GetBalance(sender); balance.Sign() != 0 {
+		t.Errorf("sender balance mismatch: have %v, want 0", balance)
+	}
+	if balance := env.statedb.GetBalance(payer); balance.Int64() != 50000 {
+		t.Errorf("sponsor charged before the fork: balance %v", balance)
+	}
+	//分叉之前不校验代付签名
+	if _, err := env.apply(sender, sponsoredTransaction(t, config.ChainId, 1, to, senderKey, senderKey)); err != errInsufficientBalanceForGas {
+		t.Errorf("self sponsorship: have %v, want %v", err, errInsufficientBalanceForGas)
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/rlp"
 	"github.com/Tinachain/Tina/chain/trie"
 	lru "github.com/hashicorp/golang-lru"
 )
@@ -70,8 +71,28 @@ func NewDatabase(db ethdb.Database) Database {
 	return &cachingDB{db: db, codeSizeCache: csc}
 }
 
+// NewNodeDatabase creates an in-memory write layer for state tries, in which
+// every account leaf references the storage trie of the account.
+func NewNodeDatabase(db ethdb.Database) *trie.NodeDatabase {
+	return trie.NewNodeDatabase(db, func(leaf []byte) []common.Hash {
+		var account Account
+		if err := rlp.DecodeBytes(leaf, &account); err != nil {
+			return nil
+		}
+		return []common.Hash{account.Root}
+	})
+}
+
+// NewDatabaseWithNodes creates a backing store for state that reads through
+// the given node database. State committed into triedb is visible right away,
+// before it is flushed to disk.
+func NewDatabaseWithNodes(triedb *trie.NodeDatabase) Database {
+	csc, _ := lru.New(codeSizeCacheSize)
+	return &cachingDB{db: triedb, codeSizeCache: csc}
+}
+
 type cachingDB struct {
-	db            ethdb.Database
+	db            trie.Database
 	mu            sync.Mutex
 	pastTries     []*trie.SecureTrie
 	codeSizeCache *lru.Cache
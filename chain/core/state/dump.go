@@ -19,6 +19,7 @@ package state
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/rlp"
@@ -71,6 +72,21 @@ func (self *StateDB) RawDump() Dump {
 	return dump
 }
 
+//遍历已提交状态中的所有账号余额, 回调返回false时停止遍历
+func (self *StateDB) ForEachBalance(cb func(addr common.Address, balance *big.Int) bool) error {
+	it := trie.NewIterator(self.trie.NodeIterator(nil))
+	for it.Next() {
+		var data Account
+		if err := rlp.DecodeBytes(it.Value, &data); err != nil {
+			return err
+		}
+		if !cb(common.BytesToAddress(self.trie.GetKey(it.Key)), data.Balance) {
+			return nil
+		}
+	}
+	return it.Err
+}
+
 func (self *StateDB) Dump() []byte {
 	json, err := json.MarshalIndent(self.RawDump(), "", "    ")
 	if err != nil {
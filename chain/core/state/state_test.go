@@ -24,6 +24,8 @@ import (
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/crypto"
 	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/rlp"
+	"github.com/Tinachain/Tina/chain/trie"
 	checker "gopkg.in/check.v1"
 )
 
@@ -230,3 +232,79 @@ func compareStateObjects(so0, so1 *stateObject, t *testing.T) {
 		t.Fatalf("Deleted mismatch: have %v, want %v", so0.deleted, so1.deleted)
 	}
 }
+
+//测试提交前能得到修改过的账号，提交后能遍历所有账号的余额
+func TestDirtyAccountsAndBalances(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	state, _ := New(common.Hash{}, NewDatabase(db))
+
+	state.AddBalance(toAddr([]byte{0x01}), big.NewInt(22))
+	state.AddBalance(toAddr([]byte{0x02}), big.NewInt(44))
+	state.IntermediateRoot(false)
+	if dirty := state.DirtyAccounts(); len(dirty) != 2 {
+		t.Fatalf("dirty account count mismatch: have %d, want 2", len(dirty))
+	}
+	root, _ := state.CommitTo(db, false)
+	if dirty := state.DirtyAccounts(); len(dirty) != 0 {
+		t.Fatalf("dirty accounts left after commit: %v", dirty)
+	}
+
+	state, _ = New(root, NewDatabase(db))
+	balances := make(map[common.Address]int64)
+	if err := state.ForEachBalance(func(addr common.Address, balance *big.Int) bool {
+		balances[addr] = balance.Int64()
+		return true
+	}); err != nil {
+		t.Fatalf("failed to iterate balances: %v", err)
+	}
+	if len(balances) != 2 || balances[toAddr([]byte{0x01})] != 22 || balances[toAddr([]byte{0x02})] != 44 {
+		t.Errorf("balances mismatch: have %v", balances)
+	}
+}
+
+func TestGetProof(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	state, _ := New(common.Hash{}, NewDatabase(db))
+
+	addr, slot := toAddr([]byte{0x01}), common.BytesToHash([]byte{0x02})
+	state.AddBalance(addr, big.NewInt(42))
+	state.SetState(addr, slot, common.BytesToHash([]byte{0x03}))
+	state.AddBalance(toAddr([]byte{0x04}), big.NewInt(1))
+	root, _ := state.CommitTo(db, false)
+	state, _ = New(root, NewDatabase(db))
+
+	verify := func(root common.Hash, key []byte, proof [][]byte) []byte {
+		proofDb, _ := ethdb.NewMemDatabase()
+		for _, node := range proof {
+			proofDb.Put(crypto.Keccak256(node), node)
+		}
+		value, err, _ := trie.VerifyProof(root, crypto.Keccak256(key), proofDb)
+		if err != nil {
+			t.Fatalf("failed to verify proof: %v", err)
+		}
+		return value
+	}
+	proof, err := state.GetProof(addr)
+	if err != nil {
+		t.Fatalf("failed to prove account: %v", err)
+	}
+	var account Account
+	if err := rlp.DecodeBytes(verify(root, addr.Bytes(), proof), &account); err != nil {
+		t.Fatalf("failed to decode proven account: %v", err)
+	}
+	if account.Balance.Int64() != 42 {
+		t.Errorf("proven balance mismatch: have %v, want 42", account.Balance)
+	}
+
+	proof, err = state.GetStorageProof(addr, slot)
+	if err != nil {
+		t.Fatalf("failed to prove storage: %v", err)
+	}
+	var value []byte
+	if err := rlp.DecodeBytes(verify(account.Root, slot.Bytes(), proof), &value); err != nil {
+		t.Fatalf("failed to decode proven storage: %v", err)
+	}
+	if !bytes.Equal(value, []byte{0x03}) {
+		t.Errorf("proven storage mismatch: have %x, want 03", value)
+	}
+}
@@ -236,6 +236,47 @@ func (self *StateDB) GetState(a common.Address, b common.Hash) common.Hash {
 	return common.Hash{}
 }
 
+// trieProver is implemented by tries able to produce Merkle proofs.
+type trieProver interface {
+	Prove(hashedKey []byte, fromLevel uint, proofDb trie.DatabaseWriter) error
+}
+
+// proofList collects the nodes of a Merkle proof in path order.
+type proofList [][]byte
+
+func (n *proofList) Put(key []byte, value []byte) error {
+	*n = append(*n, common.CopyBytes(value))
+	return nil
+}
+
+// prove collects the Merkle proof of a key in a trie opened by the state database.
+func prove(tr Trie, key []byte) ([][]byte, error) {
+	prover, ok := tr.(trieProver)
+	if !ok {
+		return nil, fmt.Errorf("trie %T cannot produce proofs", tr)
+	}
+	var proof proofList
+	if err := prover.Prove(crypto.Keccak256(key), 0, &proof); err != nil {
+		return nil, err
+	}
+	return [][]byte(proof), nil
+}
+
+// GetProof returns the Merkle proof of an account in the state trie.
+func (self *StateDB) GetProof(a common.Address) ([][]byte, error) {
+	return prove(self.trie, a.Bytes())
+}
+
+// GetStorageProof returns the Merkle proof of a storage slot in the storage
+// trie of an account.
+func (self *StateDB) GetStorageProof(a common.Address, key common.Hash) ([][]byte, error) {
+	tr := self.StorageTrie(a)
+	if tr == nil {
+		return nil, fmt.Errorf("account %x doesn't exist", a)
+	}
+	return prove(tr, key.Bytes())
+}
+
 // StorageTrie returns the storage trie of an account.
 // The return value is a copy and is nil for non-existent accounts.
 func (self *StateDB) StorageTrie(a common.Address) Trie {
@@ -391,6 +432,22 @@ func (self *StateDB) MarkStateObjectDirty(addr common.Address) {
 	self.stateObjectsDirty[addr] = struct{}{}
 }
 
+// DirtyStorage returns the accounts modified since the last commit, along with
+// the storage slots written to each of them since the state was last finalised.
+func (self *StateDB) DirtyStorage() map[common.Address][]common.Hash {
+	dirties := make(map[common.Address][]common.Hash, len(self.stateObjectsDirty))
+	for addr := range self.stateObjectsDirty {
+		var keys []common.Hash
+		if stateObject, ok := self.stateObjects[addr]; ok {
+			for key := range stateObject.dirtyStorage {
+				keys = append(keys, key)
+			}
+		}
+		dirties[addr] = keys
+	}
+	return dirties
+}
+
 // createObject creates a new state object. If there is an existing account with
 // the given address, it is overwritten and returned as the second return value.
 func (self *StateDB) createObject(addr common.Address) (newobj, prev *stateObject) {
@@ -512,6 +569,15 @@ func (self *StateDB) GetRefund() *big.Int {
 	return self.refund
 }
 
+//得到已修改但尚未提交的账号
+func (s *StateDB) DirtyAccounts() []common.Address {
+	addresses := make([]common.Address, 0, len(s.stateObjectsDirty))
+	for addr := range s.stateObjectsDirty {
+		addresses = append(addresses, addr)
+	}
+	return addresses
+}
+
 // Finalise finalises the state by removing the self destructed objects
 // and clears the journal as well as the refunds.
 func (s *StateDB) Finalise(deleteEmptyObjects bool) {
@@ -62,6 +62,34 @@ func TestUpdateLeaks(t *testing.T) {
 	}
 }
 
+// Tests that the dirty accounts are reported along with the storage slots written
+// since the state was last finalised.
+func TestDirtyStorage(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	state, _ := New(common.Hash{}, NewDatabase(db))
+
+	addr1, addr2 := common.BytesToAddress([]byte{1}), common.BytesToAddress([]byte{2})
+	key := common.BytesToHash([]byte{3})
+	state.AddBalance(addr1, big.NewInt(1))
+	state.SetState(addr2, key, common.BytesToHash([]byte{4}))
+
+	dirties := state.DirtyStorage()
+	if len(dirties) != 2 {
+		t.Fatalf("dirty account count mismatch: have %d, want 2", len(dirties))
+	}
+	if keys := dirties[addr1]; len(keys) != 0 {
+		t.Errorf("dirty storage mismatch for %x: have %x, want none", addr1, keys)
+	}
+	if keys := dirties[addr2]; len(keys) != 1 || keys[0] != key {
+		t.Errorf("dirty storage mismatch for %x: have %x, want [%x]", addr2, keys, key)
+	}
+
+	state.Finalise(false)
+	if keys := state.DirtyStorage()[addr2]; len(keys) != 0 {
+		t.Errorf("dirty storage after finalise: have %x, want none", keys)
+	}
+}
+
 // Tests that no intermediate state of an object is stored into the database,
 // only the one right before the commit.
 func TestIntermediateLeaks(t *testing.T) {
@@ -20,11 +20,13 @@ import (
 	_ "bytes"
 	"errors"
 	"math/big"
+	"time"
 
 	"github.com/Tinachain/Tina/chain/boker/api"
 	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/consensus"
+	"github.com/Tinachain/Tina/chain/consensus/dpos"
 	"github.com/Tinachain/Tina/chain/consensus/misc"
 	"github.com/Tinachain/Tina/chain/core/state"
 	"github.com/Tinachain/Tina/chain/core/types"
@@ -32,6 +34,7 @@ import (
 	"github.com/Tinachain/Tina/chain/crypto"
 	"github.com/Tinachain/Tina/chain/log"
 	"github.com/Tinachain/Tina/chain/params"
+	"github.com/Tinachain/Tina/chain/rlp"
 )
 
 //状态处理器，负责一个从一个节点到另一个节点
@@ -68,11 +71,17 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		misc.ApplyDAOHardFork(statedb)
 	}
 
+	//启用内部交易索引时记录每笔交易产生的内部交易
+	tracer, _ := cfg.Tracer.(*InternalTxTracer)
+
 	//得到区块中所有的交易，并将这些交易使用Dpos引擎进行执行。
 	for i, tx := range block.Transactions() {
 
 		//设置当前statedb状态,以便后面evm创建交易日志
 		statedb.Prepare(tx.Hash(), block.Hash(), i)
+		if tracer != nil {
+			tracer.Start(tx.Hash())
+		}
 
 		if p.bc.Boker() != nil {
 			log.Info("(p *StateProcessor) Process boker notis nil")
@@ -82,6 +91,9 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		if err != nil {
 			return nil, nil, nil, err
 		}
+		if tracer != nil {
+			tracer.Finish(len(receipt.PostState) == 0 && receipt.Status == types.ReceiptStatusFailed)
+		}
 
 		//执行完毕的交易回执放入到回执数组中
 		receipts = append(receipts, receipt)
@@ -115,9 +127,25 @@ func normalTransaction(config *params.ChainConfig,
 		return nil, nil, err
 	}
 
-	context := NewEVMContext(msg, header, bc, author)
+	context := NewBokerEVMContext(msg, header, bc, author, dposContext, bokerContext)
 	vmenv := vm.NewEVM(context, statedb, config, cfg)
-	_, gas, failed, err := NormalMessage(vmenv, msg, gp, sp, dposContext, bokerContext, boker)
+
+	var (
+		ret   []byte
+		gas   *big.Int
+		vmerr error
+	)
+	if config.IsSponsor(header.Number) && IsSponsored(tx) {
+
+		//代付交易的Gas由扩展数据中签名的代付账号支付(分叉之前按普通交易处理)
+		var payer common.Address
+		if payer, err = TxSponsor(config.ChainId, msg.From(), tx); err != nil {
+			return nil, nil, err
+		}
+		ret, gas, vmerr, err = SponsoredCallMessage(vmenv, msg, payer, gp, sp, dposContext, bokerContext, boker)
+	} else {
+		ret, gas, vmerr, err = NormalCallMessage(vmenv, msg, gp, sp, dposContext, bokerContext, boker)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
@@ -132,10 +160,16 @@ func normalTransaction(config *params.ChainConfig,
 	usedGas.Add(usedGas, gas)
 
 	//为交易创建一个新收据，存储tx使用的中间根和gas基于eip阶段，我们传递了根触发删除帐户。
-	receipt := types.NewReceipt(root, failed, usedGas)
+	receipt := types.NewReceipt(root, vmerr != nil, usedGas)
 	receipt.TxHash = tx.Hash()
 	receipt.GasUsed = new(big.Int).Set(gas)
 
+	//记录执行失败的原因
+	receipt.ErrorCode = vm.ErrorCode(vmerr)
+	if vmerr == vm.ErrExecutionReverted {
+		receipt.RevertReason, _ = vm.UnpackRevert(ret)
+	}
+
 	//如果交易创建了合同，则将创建地址存储在收据中
 	if msg.To() == nil {
 		receipt.ContractAddress = crypto.CreateAddress(vmenv.Context.Origin, tx.Nonce())
@@ -166,19 +200,32 @@ func setSystemContractTransaction(config *params.ChainConfig,
 
 	log.Info("state_processor.go setSystemContractTransaction")
 
+	//分叉之前没有确认系统基础合约变更的交易
+	if msg.Minor() == protocol.ConfirmContract && !config.IsSystemContract(header.Number) {
+		return nil, nil, protocol.ErrInvalidType
+	}
+
 	msg, err := tx.AsMessage(types.MakeSigner(config, header.Number))
 	if err != nil {
 		log.Error("contractSetTransaction tx.AsMessage", "msg", msg, "err", err)
 		return nil, nil, err
 	}
 
-	context := NewEVMContext(msg, header, bc, author)
+	contractType, registered := bokerContext.GetBaseContract(*msg.To())
+	previous, previousErr := bokerContext.GetSystemContractAddress()
+
+	context := NewBokerEVMContext(msg, header, bc, author, dposContext, bokerContext)
 	vmenv := vm.NewEVM(context, statedb, config, cfg)
 	_, gas, failed, err := systemContractMessage(vmenv, msg, gp, sp, msg.Major(), msg.Minor(), dposContext, bokerContext, boker)
 	if err != nil {
 		log.Error("contractSetTransaction contractMessage", "gas", gas, "failed", failed, "err", err)
 		return nil, nil, err
 	}
+	recordBaseContract(bokerContext, *msg.To(), msg, tx, header, contractType, registered)
+	//确认变更时原有的系统基础合约被替换
+	if previousErr == nil && previous != *msg.To() {
+		recordBaseContract(bokerContext, previous, msg, tx, header, protocol.System, true)
+	}
 
 	var root []byte
 	if config.IsByzantium(header.Number) {
@@ -196,6 +243,34 @@ func setSystemContractTransaction(config *params.ChainConfig,
 	return receipt, gas, err
 }
 
+//比较交易执行前后基础合约的注册状态，记录合约的设置或取消
+func recordBaseContract(bokerContext *types.BokerContext,
+	address common.Address,
+	msg types.Message,
+	tx *types.Transaction,
+	header *types.Header,
+	contractType protocol.BaseContractType,
+	registered bool) {
+
+	currentType, current := bokerContext.GetBaseContract(address)
+	if current == registered {
+		return
+	}
+
+	record := &protocol.BaseContractRecord{
+		Address:     address,
+		Type:        currentType,
+		Action:      protocol.ContractSet,
+		From:        msg.From(),
+		TxHash:      tx.Hash(),
+		BlockNumber: header.Number.Uint64(),
+	}
+	if !current {
+		record.Type, record.Action = contractType, protocol.ContractCancel
+	}
+	bokerContext.AddContractRecord(record)
+}
+
 func systemBaseTransaction(config *params.ChainConfig,
 	dposContext *types.DposContext,
 	bokerContext *types.BokerContext,
@@ -221,7 +296,7 @@ func systemBaseTransaction(config *params.ChainConfig,
 	}
 	log.Info("systemBaseTransaction", "Major", tx.Major(), "Minor", tx.Minor(), "Time", header.Time.Int64())
 
-	context := NewEVMContext(msg, header, bc, author)
+	context := NewBokerEVMContext(msg, header, bc, author, dposContext, bokerContext)
 	vmenv := vm.NewEVM(context, statedb, config, cfg)
 	_, gas, failed, err := SystemBaseMessage(vmenv, msg, gp, sp, dposContext, bokerContext, boker)
 	if err != nil {
@@ -229,6 +304,29 @@ func systemBaseTransaction(config *params.ChainConfig,
 		return nil, nil, err
 	}
 
+	//投票权重分叉之后由链上的投票记录计票(投票合约执行失败的投票不记录)，周期交易先按投票记录分配委托奖励
+	if config.IsVoteWeight(header.Number) && !failed {
+
+		firstBlock := bc.GetBlockByNumber(0)
+		if firstBlock == nil {
+			return nil, nil, errors.New("not found first block")
+		}
+		epoch := dpos.EpochOf(header.Time.Int64(), firstBlock.Time().Int64())
+
+		switch msg.Minor() {
+		case protocol.VoteUser:
+			err = recordVote(dposContext, msg, epoch)
+		case protocol.VoteEpoch:
+			if err = dpos.DistributeDelegation(config, statedb, dposContext, epoch); err == nil {
+				err = electByVoteWeight(config.VoteWeight, dposContext, statedb, epoch)
+			}
+		}
+		if err != nil {
+			log.Error("systemBaseTransaction vote failed", "err", err)
+			return nil, nil, err
+		}
+	}
+
 	var root []byte
 	if config.IsByzantium(header.Number) {
 		statedb.Finalise(true)
@@ -272,13 +370,19 @@ func userBaseTransaction(config *params.ChainConfig,
 	}
 	log.Info("userBaseTransaction", "Major", tx.Major(), "Minor", tx.Minor(), "Time", header.Time.Int64())
 
-	context := NewEVMContext(msg, header, bc, author)
+	if msg.To() == nil {
+		return nil, nil, protocol.ErrToIsNil
+	}
+	contractType, registered := bokerContext.GetBaseContract(*msg.To())
+
+	context := NewBokerEVMContext(msg, header, bc, author, dposContext, bokerContext)
 	vmenv := vm.NewEVM(context, statedb, config, cfg)
 	_, gas, failed, err := UserBaseMessage(vmenv, msg, gp, sp, dposContext, bokerContext, boker)
 	if err != nil {
 		log.Error("userBaseTransaction failed", "err", err)
 		return nil, nil, err
 	}
+	recordBaseContract(bokerContext, *msg.To(), msg, tx, header, contractType, registered)
 
 	var root []byte
 	if config.IsByzantium(header.Number) {
@@ -323,7 +427,7 @@ func extraTransaction(config *params.ChainConfig,
 	}
 	log.Info("extraTransaction", "Major", tx.Major(), "Minor", tx.Minor(), "Time", header.Time.Int64(), "Extra", tx.Extra())
 
-	context := NewEVMContext(msg, header, bc, author)
+	context := NewBokerEVMContext(msg, header, bc, author, dposContext, bokerContext)
 	vmenv := vm.NewEVM(context, statedb, config, cfg)
 	_, gas, failed, err := ExtraMessage(vmenv, msg, gp, sp, dposContext, bokerContext, boker)
 	if err != nil {
@@ -371,7 +475,7 @@ func validatorTransaction(config *params.ChainConfig,
 		return nil, nil, err
 	}
 
-	context := NewEVMContext(msg, header, bc, author)
+	context := NewBokerEVMContext(msg, header, bc, author, dposContext, bokerContext)
 	vmenv := vm.NewEVM(context, statedb, config, cfg)
 
 	firstBlock := bc.GetBlockByNumber(0)
@@ -463,11 +567,11 @@ func stockTransaction(config *params.ChainConfig,
 	}
 	log.Info("state_processor.go stockTransaction", "Major", tx.Major(), "Minor", tx.Minor(), "Time", header.Time.Int64(), "Extra", tx.Extra())
 
-	if nil == msg.To {
+	if msg.To() == nil {
 		return nil, nil, errors.New("Stock Transaction To is nil")
 	}
 
-	context := NewEVMContext(msg, header, bc, author)
+	context := NewBokerEVMContext(msg, header, bc, author, dposContext, bokerContext)
 	vmenv := vm.NewEVM(context, statedb, config, cfg)
 	_, gas, failed, err := StockMessage(vmenv, msg, gp, sp, tx.Time(), dposContext, bokerContext, boker)
 	if err != nil {
@@ -493,6 +597,679 @@ func stockTransaction(config *params.ChainConfig,
 	return receipt, gas, err
 }
 
+//调整出块奖励计划（只有当前出块节点可以发起，在下一个周期开始时生效）
+func rewardScheduleTransaction(config *params.ChainConfig,
+	dposContext *types.DposContext,
+	bokerContext *types.BokerContext,
+	bc *BlockChain,
+	author *common.Address,
+	gp *GasPool,
+	sp *big.Int,
+	statedb *state.StateDB,
+	header *types.Header,
+	tx *types.Transaction,
+	usedGas *big.Int,
+	cfg vm.Config,
+	msg types.Message,
+	boker bokerapi.Api) (*types.Receipt, *big.Int, error) {
+
+	log.Info("state_processor.go rewardScheduleTransaction")
+
+	if !config.IsRewardSchedule(header.Number) {
+		return nil, nil, protocol.ErrRewardScheduleDisabled
+	}
+	var schedule protocol.RewardSchedule
+	if err := rlp.DecodeBytes(tx.Data(), &schedule); err != nil {
+		return nil, nil, protocol.ErrInvalidRewardSchedule
+	}
+	if err := dpos.CheckRewardSchedule(config, schedule.Reward, schedule.GasPoolPercent); err != nil {
+		return nil, nil, err
+	}
+
+	//只有股权管理者或者当前周期的验证者才能调整奖励计划
+	validators, _ := dposContext.GetEpochTrie()
+	if !bokerContext.IsContractAuthority(msg.From(), validators) {
+		return nil, nil, protocol.ErrNotContractAuthority
+	}
+	firstBlock := bc.GetBlockByNumber(0)
+	if firstBlock == nil {
+		return nil, nil, errors.New("not found first block")
+	}
+	epoch := dpos.EpochOf(header.Time.Int64(), firstBlock.Time().Int64())
+
+	context := NewBokerEVMContext(msg, header, bc, author, dposContext, bokerContext)
+	vmenv := vm.NewEVM(context, statedb, config, cfg)
+	_, gas, failed, err := validatorMessage(vmenv, msg, gp, sp, msg.Major(), msg.Minor(), dposContext, bokerContext, boker)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	//股权管理者直接调整，验证者需要在同一周期内超过2/3确认相同的奖励计划；在下一个周期开始时生效
+	enact := msg.From() == bokerContext.GetStockManager()
+	if !enact {
+		approvals := dpos.ApproveRewardSchedule(statedb, &schedule, epoch, msg.From())
+		enact = approvals*3 > uint64(len(validators))*2
+	}
+	if enact {
+		override := &dpos.RewardOverride{
+			Reward:         schedule.Reward,
+			GasPoolPercent: schedule.GasPoolPercent,
+			Epoch:          epoch + 1,
+		}
+		if err := dpos.SetRewardOverride(statedb, override); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var root []byte
+	if config.IsByzantium(header.Number) {
+		statedb.Finalise(true)
+	} else {
+		root = statedb.IntermediateRoot(config.IsEIP158(header.Number)).Bytes()
+	}
+	usedGas.Add(usedGas, gas)
+	receipt := types.NewReceipt(root, failed, usedGas)
+	receipt.TxHash = tx.Hash()
+	receipt.GasUsed = new(big.Int).Set(gas)
+	receipt.Logs = statedb.GetLogs(tx.Hash())
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	return receipt, gas, nil
+}
+
+//部署白名单交易（批准或者取消账号部署合约的权限）
+func deployerTransaction(config *params.ChainConfig,
+	dposContext *types.DposContext,
+	bokerContext *types.BokerContext,
+	bc *BlockChain,
+	author *common.Address,
+	gp *GasPool,
+	sp *big.Int,
+	statedb *state.StateDB,
+	header *types.Header,
+	tx *types.Transaction,
+	usedGas *big.Int,
+	cfg vm.Config,
+	msg types.Message,
+	boker bokerapi.Api) (*types.Receipt, *big.Int, error) {
+
+	log.Info("state_processor.go deployerTransaction", "minor", msg.Minor(), "deployer", msg.To())
+
+	//分叉之前没有部署白名单交易
+	if !config.IsDeployControl(header.Number) {
+		return nil, nil, protocol.ErrInvalidType
+	}
+
+	//与系统基础合约变更相同，只有股权管理者或者当前周期的验证者才能修改白名单
+	validators, _ := dposContext.GetEpochTrie()
+	if !bokerContext.IsContractAuthority(msg.From(), validators) {
+		return nil, nil, protocol.ErrNotContractAuthority
+	}
+
+	context := NewBokerEVMContext(msg, header, bc, author, dposContext, bokerContext)
+	vmenv := vm.NewEVM(context, statedb, config, cfg)
+	_, gas, failed, err := validatorMessage(vmenv, msg, gp, sp, msg.Major(), msg.Minor(), dposContext, bokerContext, boker)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := bokerContext.SetDeployer(*msg.To(), msg.Minor() == protocol.ApproveDeployer); err != nil {
+		return nil, nil, err
+	}
+
+	var root []byte
+	if config.IsByzantium(header.Number) {
+		statedb.Finalise(true)
+	} else {
+		root = statedb.IntermediateRoot(config.IsEIP158(header.Number)).Bytes()
+	}
+	usedGas.Add(usedGas, gas)
+	receipt := types.NewReceipt(root, failed, usedGas)
+	receipt.TxHash = tx.Hash()
+	receipt.GasUsed = new(big.Int).Set(gas)
+	receipt.Logs = statedb.GetLogs(tx.Hash())
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	return receipt, gas, nil
+}
+
+//出块签名账号交易（验证者设置或轮换用于区块签名的账号，与接收奖励的账号分离）
+func signingKeyTransaction(config *params.ChainConfig,
+	dposContext *types.DposContext,
+	bokerContext *types.BokerContext,
+	bc *BlockChain,
+	author *common.Address,
+	gp *GasPool,
+	sp *big.Int,
+	statedb *state.StateDB,
+	header *types.Header,
+	tx *types.Transaction,
+	usedGas *big.Int,
+	cfg vm.Config,
+	msg types.Message,
+	boker bokerapi.Api) (*types.Receipt, *big.Int, error) {
+
+	log.Info("state_processor.go signingKeyTransaction", "validator", msg.From(), "key", msg.To())
+
+	if !config.IsSigningKey(header.Number) {
+		return nil, nil, protocol.ErrSigningKeyDisabled
+	}
+	//只有当前周期的验证者才能设置自己的签名账号
+	if !dposContext.IsValidator(msg.From()) {
+		return nil, nil, protocol.ErrNotValidator
+	}
+
+	context := NewBokerEVMContext(msg, header, bc, author, dposContext, bokerContext)
+	vmenv := vm.NewEVM(context, statedb, config, cfg)
+	_, gas, failed, err := validatorMessage(vmenv, msg, gp, sp, msg.Major(), msg.Minor(), dposContext, bokerContext, boker)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := bokerContext.SetSigningKey(msg.From(), *msg.To()); err != nil {
+		return nil, nil, err
+	}
+
+	var root []byte
+	if config.IsByzantium(header.Number) {
+		statedb.Finalise(true)
+	} else {
+		root = statedb.IntermediateRoot(config.IsEIP158(header.Number)).Bytes()
+	}
+	usedGas.Add(usedGas, gas)
+	receipt := types.NewReceipt(root, failed, usedGas)
+	receipt.TxHash = tx.Hash()
+	receipt.GasUsed = new(big.Int).Set(gas)
+	receipt.Logs = statedb.GetLogs(tx.Hash())
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	return receipt, gas, nil
+}
+
+//验证者运营信息交易（登记或更新验证者的名称、网站以及联系方式）
+func validatorInfoTransaction(config *params.ChainConfig,
+	dposContext *types.DposContext,
+	bokerContext *types.BokerContext,
+	bc *BlockChain,
+	author *common.Address,
+	gp *GasPool,
+	sp *big.Int,
+	statedb *state.StateDB,
+	header *types.Header,
+	tx *types.Transaction,
+	usedGas *big.Int,
+	cfg vm.Config,
+	msg types.Message,
+	boker bokerapi.Api) (*types.Receipt, *big.Int, error) {
+
+	log.Info("state_processor.go validatorInfoTransaction", "validator", msg.From())
+
+	if !config.IsValidatorInfo(header.Number) {
+		return nil, nil, protocol.ErrValidatorInfoDisabled
+	}
+	if *msg.To() != msg.From() || (msg.Value() != nil && msg.Value().Sign() != 0) {
+		return nil, nil, protocol.ErrInvalidType
+	}
+
+	//只有当前周期的验证者以及得到投票的候选者才能登记运营信息
+	if !dposContext.IsValidator(msg.From()) && dposContext.GetValidatorVotes(msg.From()).Sign() <= 0 {
+		return nil, nil, protocol.ErrNotValidator
+	}
+	info := new(protocol.ValidatorInfo)
+	if err := rlp.DecodeBytes(tx.Data(), info); err != nil {
+		return nil, nil, protocol.ErrInvalidValidatorInfo
+	}
+	if err := types.CheckValidatorInfo(info); err != nil {
+		return nil, nil, err
+	}
+
+	context := NewBokerEVMContext(msg, header, bc, author, dposContext, bokerContext)
+	vmenv := vm.NewEVM(context, statedb, config, cfg)
+	_, gas, failed, err := validatorMessage(vmenv, msg, gp, sp, msg.Major(), msg.Minor(), dposContext, bokerContext, boker)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := bokerContext.SetValidatorInfo(msg.From(), info); err != nil {
+		return nil, nil, err
+	}
+
+	var root []byte
+	if config.IsByzantium(header.Number) {
+		statedb.Finalise(true)
+	} else {
+		root = statedb.IntermediateRoot(config.IsEIP158(header.Number)).Bytes()
+	}
+	usedGas.Add(usedGas, gas)
+	receipt := types.NewReceipt(root, failed, usedGas)
+	receipt.TxHash = tx.Hash()
+	receipt.GasUsed = new(big.Int).Set(gas)
+	receipt.Logs = statedb.GetLogs(tx.Hash())
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	return receipt, gas, nil
+}
+
+//跨链桥交易（锁定资产跨链转出，以及验证者确认外部链上的销毁事件后释放锁定的资产）
+func bridgeTransaction(config *params.ChainConfig,
+	dposContext *types.DposContext,
+	bokerContext *types.BokerContext,
+	bc *BlockChain,
+	author *common.Address,
+	gp *GasPool,
+	sp *big.Int,
+	statedb *state.StateDB,
+	header *types.Header,
+	tx *types.Transaction,
+	usedGas *big.Int,
+	cfg vm.Config,
+	msg types.Message,
+	boker bokerapi.Api) (*types.Receipt, *big.Int, error) {
+
+	log.Info("state_processor.go bridgeTransaction", "minor", msg.Minor(), "from", msg.From())
+
+	if !config.IsBridge(header.Number) {
+		return nil, nil, protocol.ErrBridgeDisabled
+	}
+	//先校验交易负载，无效的跨链交易不会被打包
+	var apply func() error
+	switch msg.Minor() {
+	case protocol.BridgeLock:
+
+		amount := msg.Value()
+		if amount == nil || amount.Sign() <= 0 {
+			return nil, nil, protocol.ErrInvalidBridgeAmount
+		}
+		apply = func() error {
+			//扣除Gas之后再检查余额
+			if statedb.GetBalance(msg.From()).Cmp(amount) < 0 {
+				return protocol.ErrBridgeInsufficient
+			}
+			record := applyBridgeLock(statedb, header, msg.From(), *msg.To(), amount)
+			log.Info("Bridge lock", "nonce", record.Nonce, "sender", record.Sender, "recipient", record.Recipient, "amount", record.Amount)
+			return nil
+		}
+	case protocol.BridgeRelease:
+
+		var args protocol.BridgeReleaseArgs
+		if err := rlp.DecodeBytes(tx.Data(), &args); err != nil || args.Amount == nil || args.Amount.Sign() <= 0 {
+			return nil, nil, protocol.ErrInvalidBridgeRelease
+		}
+		validators, err := dposContext.GetEpochTrie()
+		if err != nil {
+			return nil, nil, err
+		}
+		if !dposContext.IsValidator(msg.From()) {
+			return nil, nil, protocol.ErrNotValidator
+		}
+		id := BridgeReleaseId(&args)
+		if IsBridgeReleased(statedb, id) {
+			return nil, nil, protocol.ErrBridgeReleased
+		}
+		if HasBridgeApproval(statedb, id, msg.From()) {
+			return nil, nil, protocol.ErrBridgeApproved
+		}
+		required := BridgeRequiredApprovals(len(validators))
+		if GetBridgeApprovals(statedb, id)+1 >= required && statedb.GetBalance(protocol.BridgeAddress).Cmp(args.Amount) < 0 {
+			return nil, nil, protocol.ErrBridgeInsufficient
+		}
+		apply = func() error {
+			released := applyBridgeApproval(statedb, header, msg.From(), &args, required)
+			log.Info("Bridge release approval", "id", id, "validator", msg.From(), "released", released)
+			return nil
+		}
+	default:
+		return nil, nil, protocol.ErrInvalidType
+	}
+
+	context := NewBokerEVMContext(msg, header, bc, author, dposContext, bokerContext)
+	vmenv := vm.NewEVM(context, statedb, config, cfg)
+	_, gas, failed, err := validatorMessage(vmenv, msg, gp, sp, msg.Major(), msg.Minor(), dposContext, bokerContext, boker)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := apply(); err != nil {
+		return nil, nil, err
+	}
+
+	var root []byte
+	if config.IsByzantium(header.Number) {
+		statedb.Finalise(true)
+	} else {
+		root = statedb.IntermediateRoot(config.IsEIP158(header.Number)).Bytes()
+	}
+	usedGas.Add(usedGas, gas)
+	receipt := types.NewReceipt(root, failed, usedGas)
+	receipt.TxHash = tx.Hash()
+	receipt.GasUsed = new(big.Int).Set(gas)
+	receipt.Logs = statedb.GetLogs(tx.Hash())
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	return receipt, gas, nil
+}
+
+//批量转账交易（一笔交易向多个账号转账，所有转账一起生效）
+func multiSendTransaction(config *params.ChainConfig,
+	dposContext *types.DposContext,
+	bokerContext *types.BokerContext,
+	bc *BlockChain,
+	author *common.Address,
+	gp *GasPool,
+	sp *big.Int,
+	statedb *state.StateDB,
+	header *types.Header,
+	tx *types.Transaction,
+	usedGas *big.Int,
+	cfg vm.Config,
+	msg types.Message,
+	boker bokerapi.Api) (*types.Receipt, *big.Int, error) {
+
+	log.Info("state_processor.go multiSendTransaction", "from", msg.From())
+
+	if !config.IsMultiSend(header.Number) {
+		return nil, nil, protocol.ErrMultiSendDisabled
+	}
+	//转账数量全部放在负载中，交易本身不能携带数量，并且只能发送到批量转账系统账号
+	if msg.Value() != nil && msg.Value().Sign() != 0 {
+		return nil, nil, protocol.ErrInvalidMultiSend
+	}
+	if msg.To() == nil || *msg.To() != protocol.MultiSendAddress {
+		return nil, nil, protocol.ErrInvalidMultiSend
+	}
+	items, total, err := DecodeMultiSend(tx.Data())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	context := NewBokerEVMContext(msg, header, bc, author, dposContext, bokerContext)
+	vmenv := vm.NewEVM(context, statedb, config, cfg)
+	_, gas, failed, err := multiSendMessage(vmenv, msg, gp, sp, len(items))
+	if err != nil {
+		return nil, nil, err
+	}
+	//余额不足时整笔交易无效，不会部分转账
+	if statedb.GetBalance(msg.From()).Cmp(total) < 0 {
+		return nil, nil, protocol.ErrMultiSendInsufficient
+	}
+	applyMultiSend(statedb, header, msg.From(), items)
+
+	var root []byte
+	if config.IsByzantium(header.Number) {
+		statedb.Finalise(true)
+	} else {
+		root = statedb.IntermediateRoot(config.IsEIP158(header.Number)).Bytes()
+	}
+	usedGas.Add(usedGas, gas)
+	receipt := types.NewReceipt(root, failed, usedGas)
+	receipt.TxHash = tx.Hash()
+	receipt.GasUsed = new(big.Int).Set(gas)
+	receipt.Logs = statedb.GetLogs(tx.Hash())
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	return receipt, gas, nil
+}
+
+//委托奖励交易（出块节点登记佣金比例，投票者领取委托奖励）
+func delegationTransaction(config *params.ChainConfig,
+	dposContext *types.DposContext,
+	bokerContext *types.BokerContext,
+	bc *BlockChain,
+	author *common.Address,
+	gp *GasPool,
+	sp *big.Int,
+	statedb *state.StateDB,
+	header *types.Header,
+	tx *types.Transaction,
+	usedGas *big.Int,
+	cfg vm.Config,
+	msg types.Message,
+	boker bokerapi.Api) (*types.Receipt, *big.Int, error) {
+
+	log.Info("state_processor.go delegationTransaction", "minor", msg.Minor(), "from", msg.From())
+
+	//委托奖励按投票记录分配，投票记录在投票权重分叉之后才开始写入
+	if !config.IsVoteWeight(header.Number) {
+		return nil, nil, protocol.ErrDelegationDisabled
+	}
+
+	if *msg.To() != protocol.DelegationAddress || (msg.Value() != nil && msg.Value().Sign() != 0) {
+		return nil, nil, protocol.ErrInvalidType
+	}
+
+	//先校验交易负载，无效的委托奖励交易不会被打包
+	var apply func() error
+	switch msg.Minor() {
+	case protocol.SetCommission:
+
+		var percent uint64
+		if err := rlp.DecodeBytes(tx.Data(), &percent); err != nil || percent > 100 {
+			return nil, nil, protocol.ErrInvalidCommission
+		}
+		apply = func() error {
+			log.Info("Set producer commission", "producer", msg.From(), "percent", percent)
+			return dpos.SetCommission(statedb, msg.From(), percent)
+		}
+	case protocol.ClaimRewards:
+
+		if accrued, _ := dpos.GetDelegationRewards(statedb, msg.From()); accrued.Sign() == 0 {
+			return nil, nil, protocol.ErrNoDelegationRewards
+		}
+		apply = func() error {
+			amount, err := dpos.ClaimDelegation(statedb, msg.From())
+			if err == nil {
+				log.Info("Claimed delegation rewards", "account", msg.From(), "amount", amount)
+			}
+			return err
+		}
+	default:
+		return nil, nil, protocol.ErrInvalidType
+	}
+
+	context := NewBokerEVMContext(msg, header, bc, author, dposContext, bokerContext)
+	vmenv := vm.NewEVM(context, statedb, config, cfg)
+	_, gas, failed, err := validatorMessage(vmenv, msg, gp, sp, msg.Major(), msg.Minor(), dposContext, bokerContext, boker)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := apply(); err != nil {
+		return nil, nil, err
+	}
+
+	var root []byte
+	if config.IsByzantium(header.Number) {
+		statedb.Finalise(true)
+	} else {
+		root = statedb.IntermediateRoot(config.IsEIP158(header.Number)).Bytes()
+	}
+	usedGas.Add(usedGas, gas)
+	receipt := types.NewReceipt(root, failed, usedGas)
+	receipt.TxHash = tx.Hash()
+	receipt.GasUsed = new(big.Int).Set(gas)
+	receipt.Logs = statedb.GetLogs(tx.Hash())
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	return receipt, gas, nil
+}
+
+//股权Gas池注资交易（任何账号都可以向股权Gas池注入资金，注入的资金在股权分币时发放给股权持有者）
+func gasPoolFundTransaction(config *params.ChainConfig,
+	dposContext *types.DposContext,
+	bokerContext *types.BokerContext,
+	bc *BlockChain,
+	author *common.Address,
+	gp *GasPool,
+	sp *big.Int,
+	statedb *state.StateDB,
+	header *types.Header,
+	tx *types.Transaction,
+	usedGas *big.Int,
+	cfg vm.Config,
+	msg types.Message,
+	boker bokerapi.Api) (*types.Receipt, *big.Int, error) {
+
+	log.Info("state_processor.go gasPoolFundTransaction", "from", msg.From(), "amount", msg.Value())
+
+	if !config.IsGasPoolFund(header.Number) {
+		return nil, nil, protocol.ErrGasPoolFundingDisabled
+	}
+	if *msg.To() != protocol.GasPoolAddress {
+		return nil, nil, protocol.ErrInvalidType
+	}
+	amount := msg.Value()
+	if amount == nil || amount.Sign() <= 0 || !amount.IsUint64() || bokerContext.GetGasPool()+amount.Uint64() < bokerContext.GetGasPool() {
+		return nil, nil, protocol.ErrInvalidGasPoolFunding
+	}
+
+	context := NewBokerEVMContext(msg, header, bc, author, dposContext, bokerContext)
+	vmenv := vm.NewEVM(context, statedb, config, cfg)
+	_, gas, failed, err := validatorMessage(vmenv, msg, gp, sp, msg.Major(), msg.Minor(), dposContext, bokerContext, boker)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	//扣除Gas之后再检查余额
+	if statedb.GetBalance(msg.From()).Cmp(amount) < 0 {
+		return nil, nil, protocol.ErrGasPoolInsufficient
+	}
+	statedb.SubBalance(msg.From(), amount)
+	pool, err := bokerContext.AddGasPoolContribution(msg.From(), amount)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Info("Funded stock gas pool", "from", msg.From(), "amount", amount, "gasPool", pool)
+
+	var root []byte
+	if config.IsByzantium(header.Number) {
+		statedb.Finalise(true)
+	} else {
+		root = statedb.IntermediateRoot(config.IsEIP158(header.Number)).Bytes()
+	}
+	usedGas.Add(usedGas, gas)
+	receipt := types.NewReceipt(root, failed, usedGas)
+	receipt.TxHash = tx.Hash()
+	receipt.GasUsed = new(big.Int).Set(gas)
+	receipt.Logs = statedb.GetLogs(tx.Hash())
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	return receipt, gas, nil
+}
+
+//治理交易（发起提案以及对提案投票）
+func governanceTransaction(config *params.ChainConfig,
+	dposContext *types.DposContext,
+	bokerContext *types.BokerContext,
+	bc *BlockChain,
+	author *common.Address,
+	gp *GasPool,
+	sp *big.Int,
+	statedb *state.StateDB,
+	header *types.Header,
+	tx *types.Transaction,
+	usedGas *big.Int,
+	cfg vm.Config,
+	msg types.Message,
+	boker bokerapi.Api) (*types.Receipt, *big.Int, error) {
+
+	log.Info("state_processor.go governanceTransaction", "minor", msg.Minor())
+
+	if !config.IsGovernance(header.Number) {
+		return nil, nil, protocol.ErrGovernanceDisabled
+	}
+	firstBlock := bc.GetBlockByNumber(0)
+	if firstBlock == nil {
+		return nil, nil, errors.New("not found first block")
+	}
+	epoch := dpos.EpochOf(header.Time.Int64(), firstBlock.Time().Int64())
+
+	//先校验交易负载，无效的治理交易不会被打包
+	var apply func() error
+	switch msg.Minor() {
+	case protocol.GovPropose:
+
+		var args protocol.ProposalArgs
+		if err := rlp.DecodeBytes(tx.Data(), &args); err != nil {
+			return nil, nil, protocol.ErrInvalidProposal
+		}
+		switch args.Kind {
+		case protocol.ParamChange:
+			if dpos.CheckRewardSchedule(config, args.Reward, args.GasPoolPercent) != nil {
+				return nil, nil, protocol.ErrInvalidProposal
+			}
+		case protocol.ContractUpgrade:
+			if args.Target == (common.Address{}) {
+				return nil, nil, protocol.ErrInvalidProposal
+			}
+			//升级系统基础合约的提案只能由有权变更系统基础合约的账号发起
+			validators, _ := dposContext.GetEpochTrie()
+			if !bokerContext.IsContractAuthority(msg.From(), validators) {
+				return nil, nil, protocol.ErrNotContractAuthority
+			}
+		default:
+			return nil, nil, protocol.ErrInvalidProposal
+		}
+		//投票权重在提案产生时快照，余额无法快照，只接受按股权投票
+		if args.Weight != protocol.WeightStock {
+			return nil, nil, protocol.ErrInvalidProposal
+		}
+		if bokerContext.StockWeight(msg.From()).Sign() <= 0 {
+			return nil, nil, protocol.ErrNoVotingWeight
+		}
+		apply = func() error {
+			_, err := bokerContext.AddProposal(&protocol.Proposal{
+				Kind:           args.Kind,
+				Weight:         args.Weight,
+				Proposer:       msg.From(),
+				Description:    args.Description,
+				Target:         args.Target,
+				Reward:         args.Reward,
+				GasPoolPercent: args.GasPoolPercent,
+				CreatedEpoch:   epoch,
+				EndEpoch:       epoch + protocol.GovVotingEpochs,
+			})
+			return err
+		}
+
+	case protocol.GovVote:
+
+		var args protocol.VoteArgs
+		if err := rlp.DecodeBytes(tx.Data(), &args); err != nil {
+			return nil, nil, protocol.ErrInvalidProposal
+		}
+		proposal, err := bokerContext.GetProposal(args.Id)
+		if err != nil {
+			return nil, nil, protocol.ErrUnknownProposal
+		}
+		if proposal.State != protocol.ProposalVoting || epoch >= proposal.EndEpoch {
+			return nil, nil, protocol.ErrProposalClosed
+		}
+		if bokerContext.HasVoted(args.Id, msg.From()) {
+			return nil, nil, protocol.ErrAlreadyVoted
+		}
+		if bokerContext.ProposalWeight(args.Id, msg.From()).Sign() <= 0 {
+			return nil, nil, protocol.ErrNoVotingWeight
+		}
+		apply = func() error {
+			return bokerContext.VoteProposal(args.Id, msg.From(), args.Approve, epoch)
+		}
+
+	default:
+		return nil, nil, protocol.ErrInvalidType
+	}
+
+	context := NewBokerEVMContext(msg, header, bc, author, dposContext, bokerContext)
+	vmenv := vm.NewEVM(context, statedb, config, cfg)
+	_, gas, failed, err := validatorMessage(vmenv, msg, gp, sp, msg.Major(), msg.Minor(), dposContext, bokerContext, boker)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := apply(); err != nil {
+		return nil, nil, err
+	}
+
+	var root []byte
+	if config.IsByzantium(header.Number) {
+		statedb.Finalise(true)
+	} else {
+		root = statedb.IntermediateRoot(config.IsEIP158(header.Number)).Bytes()
+	}
+	usedGas.Add(usedGas, gas)
+	receipt := types.NewReceipt(root, failed, usedGas)
+	receipt.TxHash = tx.Hash()
+	receipt.GasUsed = new(big.Int).Set(gas)
+	receipt.Logs = statedb.GetLogs(tx.Hash())
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	return receipt, gas, nil
+}
+
 //执行交易
 func ApplyTransaction(config *params.ChainConfig,
 	dposContext *types.DposContext,
@@ -514,6 +1291,65 @@ func ApplyTransaction(config *params.ChainConfig,
 	}
 	log.Info("state_processor.go ApplyTransaction", "Number", header.Number.String(), "Major", msg.Major(), "Minor", msg.Minor(), "from", msg.From(), "extra", tx.Extra())
 
+	return applyMessage(config, dposContext, bokerContext, bc, author, gp, sp, statedb, header, tx, usedGas, cfg, msg, boker)
+}
+
+//在当前区块之上试执行交易(不需要签名，不检查Nonce，也不修改链上的状态)，返回交易打包时会遇到的错误以及执行是否失败
+func DryRunTransaction(bc *BlockChain, from common.Address, tx *types.Transaction) (*types.Receipt, error) {
+
+	parent := bc.CurrentBlock()
+	statedb, err := bc.StateAt(parent.Root())
+	if err != nil {
+		return nil, err
+	}
+	dposContext, err := types.NewDposContextFromProto(bc.chainDb, parent.Header().DposProto)
+	if err != nil {
+		return nil, err
+	}
+	bokerContext, err := types.NewBokerContextFromProto(bc.chainDb, parent.Header().BokerProto)
+	if err != nil {
+		return nil, err
+	}
+
+	//按下一个区块的高度和时间执行
+	timestamp := time.Now().Unix()
+	if timestamp <= parent.Time().Int64() {
+		timestamp = parent.Time().Int64() + 1
+	}
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Validator:  parent.Header().Validator,
+		Coinbase:   parent.Coinbase(),
+		Difficulty: parent.Difficulty(),
+		Number:     new(big.Int).Add(parent.Number(), common.Big1),
+		GasLimit:   parent.GasLimit(),
+		GasUsed:    new(big.Int),
+		Time:       big.NewInt(timestamp),
+	}
+	msg := types.NewMessage(from, tx.To(), tx.Nonce(), tx.Value(), tx.Gas(), tx.GasPrice(), tx.Name(), tx.Data(), tx.Extra(), tx.Ip(), false, tx.Major(), tx.Minor())
+
+	gp := new(GasPool).AddGas(header.GasLimit)
+	sp := new(big.Int).SetInt64(protocol.MaxBlockSize)
+	receipt, _, err := applyMessage(bc.Config(), dposContext, bokerContext, bc, nil, gp, sp, statedb, header, tx, new(big.Int), vm.Config{}, msg, bc.Boker())
+	return receipt, err
+}
+
+//按交易的主要类型以及次要类型执行已经得到发送者的交易消息
+func applyMessage(config *params.ChainConfig,
+	dposContext *types.DposContext,
+	bokerContext *types.BokerContext,
+	bc *BlockChain,
+	author *common.Address,
+	gp *GasPool,
+	sp *big.Int,
+	statedb *state.StateDB,
+	header *types.Header,
+	tx *types.Transaction,
+	usedGas *big.Int,
+	cfg vm.Config,
+	msg types.Message,
+	boker bokerapi.Api) (*types.Receipt, *big.Int, error) {
+
 	if msg.Major() == protocol.Normal {
 
 		return normalTransaction(config, dposContext, bokerContext, bc, author, gp, sp, statedb, header, tx, usedGas, cfg, msg, boker)
@@ -525,7 +1361,7 @@ func ApplyTransaction(config *params.ChainConfig,
 
 		switch msg.Minor() {
 
-		case protocol.SetSystemContract:
+		case protocol.SetSystemContract, protocol.ConfirmContract:
 
 			return setSystemContractTransaction(config, dposContext, bokerContext, bc, author, gp, sp, statedb, header, tx, usedGas, cfg, msg, boker)
 		case protocol.VoteUser, protocol.VoteEpoch, protocol.RegisterCandidate:
@@ -534,6 +1370,33 @@ func ApplyTransaction(config *params.ChainConfig,
 		case protocol.SetValidator:
 
 			return validatorTransaction(config, dposContext, bokerContext, bc, author, gp, sp, statedb, header, tx, usedGas, cfg, msg, boker)
+		case protocol.SetRewardSchedule:
+
+			return rewardScheduleTransaction(config, dposContext, bokerContext, bc, author, gp, sp, statedb, header, tx, usedGas, cfg, msg, boker)
+		case protocol.GovPropose, protocol.GovVote:
+
+			return governanceTransaction(config, dposContext, bokerContext, bc, author, gp, sp, statedb, header, tx, usedGas, cfg, msg, boker)
+		case protocol.ApproveDeployer, protocol.RevokeDeployer:
+
+			return deployerTransaction(config, dposContext, bokerContext, bc, author, gp, sp, statedb, header, tx, usedGas, cfg, msg, boker)
+		case protocol.SetSigningKey:
+
+			return signingKeyTransaction(config, dposContext, bokerContext, bc, author, gp, sp, statedb, header, tx, usedGas, cfg, msg, boker)
+		case protocol.SetValidatorInfo:
+
+			return validatorInfoTransaction(config, dposContext, bokerContext, bc, author, gp, sp, statedb, header, tx, usedGas, cfg, msg, boker)
+		case protocol.FundGasPool:
+
+			return gasPoolFundTransaction(config, dposContext, bokerContext, bc, author, gp, sp, statedb, header, tx, usedGas, cfg, msg, boker)
+		case protocol.BridgeLock, protocol.BridgeRelease:
+
+			return bridgeTransaction(config, dposContext, bokerContext, bc, author, gp, sp, statedb, header, tx, usedGas, cfg, msg, boker)
+		case protocol.MultiSend:
+
+			return multiSendTransaction(config, dposContext, bokerContext, bc, author, gp, sp, statedb, header, tx, usedGas, cfg, msg, boker)
+		case protocol.SetCommission, protocol.ClaimRewards:
+
+			return delegationTransaction(config, dposContext, bokerContext, bc, author, gp, sp, statedb, header, tx, usedGas, cfg, msg, boker)
 		default:
 
 			return nil, nil, protocol.ErrInvalidType
@@ -0,0 +1,169 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/core/vm"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+// bokerTestEnv is the state and the consensus contexts of a block under
+// construction, on which single transactions are applied.
+type bokerTestEnv struct {
+	config       *params.ChainConfig
+	statedb      *state.StateDB
+	dposContext  *types.DposContext
+	bokerContext *types.BokerContext
+	header       *types.Header
+}
+
+func newBokerTestEnv(t *testing.T, validators ...common.Address) *bokerTestEnv {
+	db, _ := ethdb.NewMemDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	dposContext, err := types.NewDposContext(db)
+	if err != nil {
+		t.Fatalf("failed to create dpos context: %v", err)
+	}
+	if err := dposContext.SetEpochTrie(validators); err != nil {
+		t.Fatalf("failed to set validators: %v", err)
+	}
+	bokerContext, err := types.NewBokerContext(db)
+	if err != nil {
+		t.Fatalf("failed to create boker context: %v", err)
+	}
+	return &bokerTestEnv{
+		config:       params.TestChainConfig,
+		statedb:      statedb,
+		dposContext:  dposContext,
+		bokerContext: bokerContext,
+		header:       &types.Header{Number: big.NewInt(1), Time: big.NewInt(1), Difficulty: big.NewInt(1), GasLimit: big.NewInt(10000000)},
+	}
+}
+
+// apply runs a transaction sent by from through the state processor.
+func (env *bokerTestEnv) apply(from common.Address, tx *types.Transaction) (*types.Receipt, error) {
+	msg := types.NewMessage(from, tx.To(), tx.Nonce(), tx.Value(), tx.Gas(), tx.GasPrice(), tx.Name(), tx.Data(), tx.Extra(), tx.Ip(), true, tx.Major(), tx.Minor())
+	env.statedb.Prepare(tx.Hash(), common.Hash{}, 0)
+
+	author := common.Address{}
+	gp := new(GasPool).AddGas(env.header.GasLimit)
+	sp := new(big.Int).SetInt64(protocol.MaxBlockSize)
+	receipt, _, err := applyMessage(env.config, env.dposContext, env.bokerContext, nil, &author, gp, sp, env.statedb, env.header, tx, new(big.Int), vm.Config{}, msg, nil)
+	return receipt, err
+}
+
+// Tests that only validators can rotate their block signing key, free of gas,
+// and only from the signing key fork block on.
+func TestSigningKeyTransaction(t *testing.T) {
+	var (
+		validator = common.HexToAddress("0x01")
+		outsider  = common.HexToAddress("0x02")
+		key       = common.HexToAddress("0x0e")
+	)
+	newEnv := func() *bokerTestEnv {
+		env := newBokerTestEnv(t, validator)
+		env.statedb.AddBalance(validator, big.NewInt(1000))
+
+		config := *env.config
+		config.SigningKeyBlock = big.NewInt(1)
+		env.config = &config
+		return env
+	}
+	env := newEnv()
+	env.config.SigningKeyBlock = big.NewInt(2)
+
+	tx := types.NewBaseTransaction(protocol.SystemBase, protocol.SetSigningKey, 0, key, new(big.Int), nil)
+	if _, err := env.apply(validator, tx); err != protocol.ErrSigningKeyDisabled {
+		t.Errorf("before fork: have %v, want %v", err, protocol.ErrSigningKeyDisabled)
+	}
+	if signer := env.bokerContext.SigningKey(validator); signer != validator {
+		t.Errorf("signing key set before fork: have %x, want %x", signer, validator)
+	}
+
+	env = newEnv()
+	if _, err := env.apply(outsider, tx); err != protocol.ErrNotValidator {
+		t.Errorf("outsider: have %v, want %v", err, protocol.ErrNotValidator)
+	}
+	tx = types.NewBaseTransaction(protocol.SystemBase, protocol.SetSigningKey, 0, common.Address{}, new(big.Int), nil)
+	if _, err := env.apply(validator, tx); err != protocol.ErrToIsNil {
+		t.Errorf("empty key: have %v, want %v", err, protocol.ErrToIsNil)
+	}
+
+	env = newEnv()
+	tx = types.NewBaseTransaction(protocol.SystemBase, protocol.SetSigningKey, 0, key, new(big.Int), nil)
+	receipt, err := env.apply(validator, tx)
+	if err != nil {
+		t.Fatalf("validator: failed to set signing key: %v", err)
+	}
+	if receipt.GasUsed.Sign() != 0 || env.statedb.GetBalance(validator).Int64() != 1000 {
+		t.Errorf("gas charged: used %v, balance %v", receipt.GasUsed, env.statedb.GetBalance(validator))
+	}
+	if signer := env.bokerContext.SigningKey(validator); signer != key {
+		t.Errorf("signing key mismatch: have %x, want %x", signer, key)
+	}
+	if _, err := env.apply(validator, tx); err != ErrNonceTooLow {
+		t.Errorf("replay: have %v, want %v", err, ErrNonceTooLow)
+	}
+
+	//设置为验证者自身的账号时恢复使用验证者账号签名
+	tx = types.NewBaseTransaction(protocol.SystemBase, protocol.SetSigningKey, 1, validator, new(big.Int), nil)
+	if _, err := env.apply(validator, tx); err != nil {
+		t.Fatalf("validator: failed to reset signing key: %v", err)
+	}
+	if signer := env.bokerContext.SigningKey(validator); signer != validator {
+		t.Errorf("reset signing key mismatch: have %x, want %x", signer, validator)
+	}
+}
+
+// Tests that the stock gas pool can only be funded from the gas pool fund fork
+// block on, with the contribution moved from the sender into the pool.
+func TestGasPoolFundTransaction(t *testing.T) {
+	sender := common.HexToAddress("0x5e")
+
+	env := newBokerTestEnv(t)
+	env.statedb.AddBalance(sender, big.NewInt(1000))
+
+	config := *env.config
+	config.GasPoolFundBlock = big.NewInt(2)
+	env.config = &config
+
+	tx := types.NewBaseTransaction(protocol.SystemBase, protocol.FundGasPool, 0, protocol.GasPoolAddress, big.NewInt(300), nil)
+	if _, err := env.apply(sender, tx); err != protocol.ErrGasPoolFundingDisabled {
+		t.Errorf("before fork: have %v, want %v", err, protocol.ErrGasPoolFundingDisabled)
+	}
+	env.header.Number = big.NewInt(2)
+
+	other := types.NewBaseTransaction(protocol.SystemBase, protocol.FundGasPool, 0, common.HexToAddress("0xbad"), big.NewInt(300), nil)
+	if _, err := env.apply(sender, other); err != protocol.ErrInvalidType {
+		t.Errorf("other recipient account: have %v, want %v", err, protocol.ErrInvalidType)
+	}
+	empty := types.NewBaseTransaction(protocol.SystemBase, protocol.FundGasPool, 0, protocol.GasPoolAddress, new(big.Int), nil)
+	if _, err := env.apply(sender, empty); err != protocol.ErrInvalidGasPoolFunding {
+		t.Errorf("zero amount: have %v, want %v", err, protocol.ErrInvalidGasPoolFunding)
+	}
+	large := types.NewBaseTransaction(protocol.SystemBase, protocol.FundGasPool, 0, protocol.GasPoolAddress, big.NewInt(1001), nil)
+	if _, err := env.apply(sender, large); err != protocol.ErrGasPoolInsufficient {
+		t.Errorf("insufficient funds: have %v, want %v", err, protocol.ErrGasPoolInsufficient)
+	}
+
+	//余额检查在扣除Gas之后，测试中不回滚状态，失败的交易已经使用了Nonce
+	tx = types.NewBaseTransaction(protocol.SystemBase, protocol.FundGasPool, 1, protocol.GasPoolAddress, big.NewInt(300), nil)
+	if _, err := env.apply(sender, tx); err != nil {
+		t.Fatalf("failed to fund gas pool: %v", err)
+	}
+	if balance := env.statedb.GetBalance(sender); balance.Int64() != 700 {
+		t.Errorf("sender balance mismatch: have %v, want 700", balance)
+	}
+	if pool := env.bokerContext.GetGasPool(); pool != 300 {
+		t.Errorf("gas pool mismatch: have %d, want 300", pool)
+	}
+	if contribution := env.bokerContext.GasPoolContribution(sender); contribution.Int64() != 300 {
+		t.Errorf("contribution mismatch: have %v, want 300", contribution)
+	}
+}
@@ -45,14 +45,16 @@ type StateTransition struct {
 	sp         *big.Int //区块剩余的空间池
 	msg        Message
 	gas        uint64
-	gasPrice   *big.Int     // gas的价格
-	initialGas *big.Int     // 最开始的gas
-	value      *big.Int     // 转账的值
-	data       []byte       // 输入数据
-	extra      []byte       //扩展字段
-	state      vm.StateDB   //StateDB对象
-	evm        *vm.EVM      //虚拟机对象
-	boker      bokerapi.Api //Tina链的接口对象
+	gasPrice   *big.Int        // gas的价格
+	initialGas *big.Int        // 最开始的gas
+	value      *big.Int        // 转账的值
+	data       []byte          // 输入数据
+	extra      []byte          //扩展字段
+	state      vm.StateDB      //StateDB对象
+	evm        *vm.EVM         //虚拟机对象
+	boker      bokerapi.Api    //Tina链的接口对象
+	vmerr      error           //虚拟机执行错误
+	payer      *common.Address //代付Gas的账号（为空时由发送账号支付）
 }
 
 // Message represents a message sent to a contract.
@@ -149,6 +151,36 @@ func NormalMessage(evm *vm.EVM,
 	return ret, gasUsed, failed, err
 }
 
+//普通交易处理，返回虚拟机的执行错误用于区分回滚、Gas耗尽以及非法指令等失败原因
+func NormalCallMessage(evm *vm.EVM,
+	msg Message,
+	gp *GasPool,
+	sp *big.Int,
+	dposContext *types.DposContext,
+	bokerContext *types.BokerContext,
+	boker bokerapi.Api) (ret []byte, usedGas *big.Int, vmerr error, err error) {
+
+	st := NewStateTransition(evm, msg, gp, sp)
+	ret, _, usedGas, _, err = st.NormalTransitionDb(dposContext, bokerContext, boker)
+	return ret, usedGas, st.vmerr, err
+}
+
+//由代付账号支付Gas的普通交易处理
+func SponsoredCallMessage(evm *vm.EVM,
+	msg Message,
+	payer common.Address,
+	gp *GasPool,
+	sp *big.Int,
+	dposContext *types.DposContext,
+	bokerContext *types.BokerContext,
+	boker bokerapi.Api) (ret []byte, usedGas *big.Int, vmerr error, err error) {
+
+	st := NewStateTransition(evm, msg, gp, sp)
+	st.payer = &payer
+	ret, _, usedGas, _, err = st.NormalTransitionDb(dposContext, bokerContext, boker)
+	return ret, usedGas, st.vmerr, err
+}
+
 //系统基础交易
 func SystemBaseMessage(evm *vm.EVM,
 	msg Message,
@@ -243,6 +275,14 @@ func validatorMessage(evm *vm.EVM, msg Message, gp *GasPool, sp *big.Int, txMajo
 	return ret, new(big.Int).SetInt64(0), failed, err
 }
 
+//批量转账交易，按接收账号的数量收取Gas
+func multiSendMessage(evm *vm.EVM, msg Message, gp *GasPool, sp *big.Int, recipients int) ([]byte, *big.Int, bool, error) {
+
+	st := NewStateTransition(evm, msg, gp, sp)
+	ret, _, gasUsed, failed, err := st.MultiSendTransitionDb(recipients)
+	return ret, gasUsed, failed, err
+}
+
 //获取交易的from信息
 func (st *StateTransition) from() vm.AccountRef {
 
@@ -271,6 +311,15 @@ func (st *StateTransition) to() vm.AccountRef {
 	return reference
 }
 
+//获取支付Gas的账号
+func (st *StateTransition) gasPayer() common.Address {
+
+	if st.payer != nil {
+		return *st.payer
+	}
+	return st.from().Address()
+}
+
 //获取交易中实际使用的Gas信息，从Gas中减去实际费用，得到剩余费用
 func (st *StateTransition) useGas(amount uint64) error {
 	if st.gas < amount {
@@ -292,12 +341,15 @@ func (st *StateTransition) buyGas() error {
 	//计算Gas的价格合计 = Gas * GasPrice
 	mgval := new(big.Int).Mul(mgas, st.gasPrice)
 	var (
-		state  = st.state
-		sender = st.from()
+		state = st.state
+		payer = st.gasPayer()
 	)
 
-	//判断用户账户中有足够的费用
-	if state.GetBalance(sender.Address()).Cmp(mgval) < 0 {
+	//判断用户账户(或代付账号)中有足够的费用
+	if state.GetBalance(payer).Cmp(mgval) < 0 {
+		if st.payer != nil {
+			return protocol.ErrSponsorInsufficient
+		}
 		return errInsufficientBalanceForGas
 	}
 
@@ -307,7 +359,7 @@ func (st *StateTransition) buyGas() error {
 	}
 	st.gas += mgas.Uint64()
 	st.initialGas.Set(mgas)
-	state.SubBalance(sender.Address(), mgval)
+	state.SubBalance(payer, mgval)
 	return nil
 }
 
@@ -347,6 +399,13 @@ func (st *StateTransition) NormalTransitionDb(dposContext *types.DposContext,
 	homestead := true
 	contractCreation := msg.To() == nil
 
+	//开启部署白名单时只有被批准的账号才能部署合约
+	if contractCreation && st.evm.ChainConfig().IsDeployWhitelist(st.evm.BlockNumber) {
+		if bokerContext == nil || !bokerContext.IsDeployer(sender.Address()) {
+			return nil, nil, nil, false, protocol.ErrDeployNotApproved
+		}
+	}
+
 	intrinsicGas := IntrinsicGas(st.data, contractCreation, homestead)
 	if intrinsicGas.BitLen() > 64 {
 		return nil, nil, nil, false, vm.ErrOutOfGas
@@ -374,6 +433,7 @@ func (st *StateTransition) NormalTransitionDb(dposContext *types.DposContext,
 			return nil, nil, nil, false, vmerr
 		}
 	}
+	st.vmerr = vmerr
 	requiredGas = new(big.Int).Set(st.gasUsed())
 
 	//退还Gas
@@ -461,6 +521,17 @@ func (st *StateTransition) ExtraTransitionDb(dposContext *types.DposContext,
 	homestead := true
 	contractCreation := msg.To() == nil
 
+	//合约元数据分叉之后登记合约元数据只记录在交易中，不调用合约；分叉之前与其它扩展交易一样调用合约
+	contractMeta := msg.Minor() == protocol.ContractMeta && st.evm.ChainConfig().IsContractMeta(st.evm.BlockNumber)
+	if contractMeta {
+		if contractCreation || st.state.GetCodeSize(*msg.To()) == 0 {
+			return nil, nil, nil, false, protocol.ErrInvalidContractMeta
+		}
+		if _, err = DecodeContractMeta(msg.Extra()); err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+
 	intrinsicGas := IntrinsicGas(st.data, contractCreation, homestead)
 	if intrinsicGas.BitLen() > 64 {
 		return nil, nil, nil, false, vm.ErrOutOfGas
@@ -478,6 +549,9 @@ func (st *StateTransition) ExtraTransitionDb(dposContext *types.DposContext,
 	if contractCreation {
 
 		ret, _, st.gas, vmerr = evm.Create(sender, st.data, st.gas, st.value)
+	} else if contractMeta {
+
+		st.state.SetNonce(sender.Address(), st.state.GetNonce(sender.Address())+1)
 	} else {
 
 		st.state.SetNonce(sender.Address(), st.state.GetNonce(sender.Address())+1)
@@ -546,6 +620,7 @@ func (st *StateTransition) bokerAssignGas(operation common.Address, now *big.Int
 
 		//给用户加钱
 		st.state.AddBalance(v.Account, new(big.Int).SetUint64(userGas))
+		bokerContext.AddStockPayout(v.Account, new(big.Int).SetUint64(userGas))
 	}
 	return []byte(""), new(big.Int).SetInt64(0), new(big.Int).SetInt64(0), false, nil
 }
@@ -603,8 +678,47 @@ func (st *StateTransition) SystemContractTransitionDb(txMajor protocol.TxMajor,
 		return
 	}
 
-	if txMinor == protocol.SetSystemContract {
-		boker.SetSystemContract(*st.msg.To(), st.from().Address(), bokerContext)
+	//分叉之前保持原有的处理，交易直接设置系统基础合约
+	from := st.from().Address()
+	if !st.evm.ChainConfig().IsSystemContract(st.evm.BlockNumber) {
+		if txMinor == protocol.SetSystemContract {
+			boker.SetSystemContract(*st.msg.To(), from, bokerContext)
+		}
+		st.state.SetNonce(from, st.state.GetNonce(from)+1)
+		return []byte(""), new(big.Int).SetInt64(0), new(big.Int).SetInt64(0), false, nil
+	}
+
+	//只有股权管理者或者当前周期的验证者才能变更系统基础合约
+	validators, _ := dposContext.GetEpochTrie()
+	if !bokerContext.IsContractAuthority(from, validators) {
+		return []byte(""), new(big.Int).SetInt64(0), new(big.Int).SetInt64(0), false, protocol.ErrNotContractAuthority
+	}
+
+	number := st.evm.BlockNumber.Uint64()
+	confirm := false
+	switch txMinor {
+	case protocol.SetSystemContract:
+		//还没有系统基础合约时变更无需等待，提出的同时进行确认
+		if _, err := bokerContext.GetSystemContractAddress(); err != nil {
+			confirm = true
+		}
+		if err = bokerContext.ProposeSystemContract(*st.msg.To(), from, number); err != nil {
+			return []byte(""), new(big.Int).SetInt64(0), new(big.Int).SetInt64(0), false, err
+		}
+	case protocol.ConfirmContract:
+		confirm = true
+	}
+	if confirm {
+		ready, err := bokerContext.ConfirmSystemContract(*st.msg.To(), from, number, validators)
+		if err != nil {
+			return []byte(""), new(big.Int).SetInt64(0), new(big.Int).SetInt64(0), false, err
+		}
+		if ready {
+			if err := bokerContext.RemoveSystemContract(); err != nil {
+				return []byte(""), new(big.Int).SetInt64(0), new(big.Int).SetInt64(0), false, err
+			}
+			boker.SetSystemContract(*st.msg.To(), from, bokerContext)
+		}
 	}
 
 	st.state.SetNonce(st.from().Address(), st.state.GetNonce(st.from().Address())+1)
@@ -622,8 +736,18 @@ func (st *StateTransition) UserContractTransitionDb(txMajor protocol.TxMajor,
 		return
 	}
 
-	if txMinor == protocol.SetSystemContract {
-		bokerContext.SetUserBaseContract(*st.msg.To(), st.from().Address())
+	//分叉之前保持原有的处理，只有SetSystemContract类型的交易设置用户基础合约
+	if !st.evm.ChainConfig().IsUserContract(st.evm.BlockNumber) {
+		if txMinor == protocol.SetSystemContract {
+			bokerContext.SetUserBaseContract(*st.msg.To(), st.from().Address())
+		}
+	} else {
+		switch txMinor {
+		case protocol.SetUserContract:
+			bokerContext.SetUserBaseContract(*st.msg.To(), st.from().Address())
+		case protocol.CancelUserContract:
+			bokerContext.CancelUserBaseContract(*st.msg.To(), st.from().Address())
+		}
 	}
 
 	st.state.SetNonce(st.from().Address(), st.state.GetNonce(st.from().Address())+1)
@@ -650,18 +774,41 @@ func (st *StateTransition) ValidatorTransitionDb(txMajor protocol.TxMajor, txMin
 	return []byte(""), new(big.Int).SetInt64(0), new(big.Int).SetInt64(0), false, nil
 }
 
+//批量转账交易只收取Gas并增加Nonce，转账在校验余额后由调用者执行
+func (st *StateTransition) MultiSendTransitionDb(recipients int) (ret []byte, requiredGas, usedGas *big.Int, failed bool, err error) {
+
+	log.Info("(st *StateTransition) MultiSendTransitionDb", "recipients", recipients)
+	if err = st.preCheck(); err != nil {
+		return
+	}
+
+	gas := MultiSendGas(st.data, recipients)
+	if gas.BitLen() > 64 {
+		return nil, nil, nil, false, vm.ErrOutOfGas
+	}
+	if err = st.useGas(gas.Uint64()); err != nil {
+		return nil, nil, nil, false, err
+	}
+	st.state.SetNonce(st.from().Address(), st.state.GetNonce(st.from().Address())+1)
+
+	requiredGas = new(big.Int).Set(st.gasUsed())
+	st.refundGas()
+
+	return []byte(""), requiredGas, st.gasUsed(), false, nil
+}
+
 //退还Gas
 func (st *StateTransition) refundGas() {
 
-	sender := st.from()
+	payer := st.gasPayer()
 	remaining := new(big.Int).Mul(new(big.Int).SetUint64(st.gas), st.gasPrice)
-	st.state.AddBalance(sender.Address(), remaining)
+	st.state.AddBalance(payer, remaining)
 
 	uhalf := remaining.Div(st.gasUsed(), common.Big2)
 	refund := math.BigMin(uhalf, st.state.GetRefund())
 	st.gas += refund.Uint64()
 
-	st.state.AddBalance(sender.Address(), refund.Mul(refund, st.gasPrice))
+	st.state.AddBalance(payer, refund.Mul(refund, st.gasPrice))
 
 	//将剩余的Gas归还给发送方
 	st.gp.AddGas(new(big.Int).SetUint64(st.gas))
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+var tokenTransferPrefix = []byte("tT") // tokenTransferPrefix + address -> token transfers touching the address
+
+//ERC-20以及ERC-721的Transfer事件主题
+var TokenTransferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+//开启代币索引，之后导入的区块会记录交易日志中的代币转账
+func (bc *BlockChain) EnableTokenIndex() {
+	bc.procmu.Lock()
+	defer bc.procmu.Unlock()
+	bc.tokenIndex = true
+}
+
+//解码Transfer事件，ERC-20的数量放在日志数据中，ERC-721的tokenId作为第三个索引参数，其他格式的日志返回nil
+func DecodeTokenTransfer(l *types.Log) *types.TokenTransfer {
+
+	if len(l.Topics) < 3 || l.Topics[0] != TokenTransferTopic {
+		return nil
+	}
+	transfer := &types.TokenTransfer{
+		Token:       l.Address,
+		From:        common.BytesToAddress(l.Topics[1].Bytes()),
+		To:          common.BytesToAddress(l.Topics[2].Bytes()),
+		TxHash:      l.TxHash,
+		BlockHash:   l.BlockHash,
+		BlockNumber: l.BlockNumber,
+		LogIndex:    uint64(l.Index),
+	}
+	switch {
+	case len(l.Topics) == 3 && len(l.Data) == 32:
+		transfer.Standard, transfer.Value = types.TokenERC20, new(big.Int).SetBytes(l.Data)
+	case len(l.Topics) == 4 && len(l.Data) == 0:
+		transfer.Standard, transfer.Value = types.TokenERC721, l.Topics[3].Big()
+	default:
+		return nil
+	}
+	return transfer
+}
+
+// GetTokenTransfers retrieves the token transfers recorded for an address,
+// oldest first. Transfers of blocks that later left the canonical chain are
+// not removed.
+func GetTokenTransfers(db DatabaseReader, address common.Address) []*types.TokenTransfer {
+	data, _ := db.Get(append(tokenTransferPrefix, address.Bytes()...))
+	if len(data) == 0 {
+		return nil
+	}
+	var transfers []*types.TokenTransfer
+	if err := rlp.DecodeBytes(data, &transfers); err != nil {
+		log.Error("Invalid token transfers RLP", "address", address, "err", err)
+		return nil
+	}
+	return transfers
+}
+
+// WriteTokenTransfers decodes the Transfer events of a block's receipts and
+// appends them to the index of the sender and the recipient.
+func WriteTokenTransfers(db ethdb.Database, block *types.Block, receipts types.Receipts) error {
+	var (
+		touched   = make(map[common.Address][]*types.TokenTransfer)
+		addresses []common.Address
+	)
+	var index uint64
+	for _, receipt := range receipts {
+		for _, l := range receipt.Logs {
+			l := *l
+			l.BlockHash, l.BlockNumber, l.Index = block.Hash(), block.NumberU64(), uint(index)
+			index++
+
+			transfer := DecodeTokenTransfer(&l)
+			if transfer == nil {
+				continue
+			}
+			parties := []common.Address{transfer.From}
+			if transfer.To != transfer.From {
+				parties = append(parties, transfer.To)
+			}
+			for _, address := range parties {
+				if address == (common.Address{}) {
+					continue
+				}
+				if _, ok := touched[address]; !ok {
+					addresses = append(addresses, address)
+				}
+				touched[address] = append(touched[address], transfer)
+			}
+		}
+	}
+	for _, address := range addresses {
+		data, err := rlp.EncodeToBytes(append(GetTokenTransfers(db, address), touched[address]...))
+		if err != nil {
+			return err
+		}
+		if err := db.Put(append(tokenTransferPrefix, address.Bytes()...), data); err != nil {
+			log.Crit("Failed to store token transfers", "err", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+)
+
+// transferLog creates an ERC-20 Transfer event, or an ERC-721 one if id is set.
+func transferLog(token, from, to common.Address, value int64, id bool) *types.Log {
+	l := &types.Log{
+		Address: token,
+		Topics:  []common.Hash{TokenTransferTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+	}
+	if id {
+		l.Topics = append(l.Topics, common.BigToHash(big.NewInt(value)))
+	} else {
+		l.Data = common.BigToHash(big.NewInt(value)).Bytes()
+	}
+	return l
+}
+
+func TestDecodeTokenTransfer(t *testing.T) {
+	var (
+		token    = common.HexToAddress("0x70")
+		from, to = common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	)
+	if transfer := DecodeTokenTransfer(transferLog(token, from, to, 5, false)); transfer == nil || transfer.Standard != types.TokenERC20 || transfer.From != from || transfer.To != to || transfer.Value.Int64() != 5 {
+		t.Errorf("erc20 transfer mismatch: have %+v", transfer)
+	}
+	if transfer := DecodeTokenTransfer(transferLog(token, from, to, 7, true)); transfer == nil || transfer.Standard != types.TokenERC721 || transfer.Value.Int64() != 7 {
+		t.Errorf("erc721 transfer mismatch: have %+v", transfer)
+	}
+
+	approval := transferLog(token, from, to, 5, false)
+	approval.Topics[0] = common.HexToHash("0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925")
+	malformed := transferLog(token, from, to, 5, true)
+	malformed.Data = []byte{0x01}
+	short := transferLog(token, from, to, 5, false)
+	short.Topics = short.Topics[:2]
+
+	for i, l := range []*types.Log{approval, malformed, short} {
+		if transfer := DecodeTokenTransfer(l); transfer != nil {
+			t.Errorf("test %d: decoded non transfer log: %+v", i, transfer)
+		}
+	}
+}
+
+// Tests that token transfers are indexed for the sender and the recipient with
+// their position in the block, and appended across blocks.
+func TestTokenTransferStorage(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+
+	var (
+		token      = common.HexToAddress("0x70")
+		alice, bob = common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	)
+	newBlock := func(number int64) *types.Block {
+		return types.NewBlockWithHeader(&types.Header{Number: big.NewInt(number), DposProto: &types.DposContextProto{}, BokerProto: &types.BokerBackendProto{}})
+	}
+	block1, block2 := newBlock(1), newBlock(2)
+
+	//铸造只记录在接收账号，给自己转账只记录一次，其他日志也占用日志序号
+	receipts := types.Receipts{
+		&types.Receipt{Logs: []*types.Log{{Address: token}, transferLog(token, common.Address{}, alice, 10, false)}},
+		&types.Receipt{Logs: []*types.Log{transferLog(token, alice, alice, 1, false)}},
+	}
+	if err := WriteTokenTransfers(db, block1, receipts); err != nil {
+		t.Fatalf("failed to write token transfers: %v", err)
+	}
+	receipts = types.Receipts{&types.Receipt{Logs: []*types.Log{transferLog(token, alice, bob, 4, false)}}}
+	if err := WriteTokenTransfers(db, block2, receipts); err != nil {
+		t.Fatalf("failed to write token transfers: %v", err)
+	}
+
+	transfers := GetTokenTransfers(db, alice)
+	if len(transfers) != 3 {
+		t.Fatalf("alice transfer count mismatch: have %d, want 3", len(transfers))
+	}
+	for i, want := range []struct {
+		number, index uint64
+		value         int64
+	}{{1, 1, 10}, {1, 2, 1}, {2, 0, 4}} {
+		if transfer := transfers[i]; transfer.BlockNumber != want.number || transfer.LogIndex != want.index || transfer.Value.Int64() != want.value {
+			t.Errorf("alice transfer %d mismatch: have %+v", i, transfer)
+		}
+	}
+	if transfers[0].BlockHash != block1.Hash() {
+		t.Errorf("block hash mismatch: have %x, want %x", transfers[0].BlockHash, block1.Hash())
+	}
+	if transfers := GetTokenTransfers(db, bob); len(transfers) != 1 || transfers[0].From != alice {
+		t.Errorf("bob transfers mismatch: have %v", transfers)
+	}
+	if transfers := GetTokenTransfers(db, common.Address{}); transfers != nil {
+		t.Errorf("zero address indexed: have %v", transfers)
+	}
+}
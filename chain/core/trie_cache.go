@@ -0,0 +1,112 @@
+package core
+
+import (
+	"time"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/trie"
+)
+
+const (
+	//非归档节点在内存中保留最近区块状态的数量，更早且没有写入磁盘的状态会被回收
+	TriesInMemory = 128
+
+	//状态树脏节点缓存的默认上限(MB)
+	DefaultTrieDirtyCache = 256
+
+	//非归档节点定期把一个完整的状态写入磁盘，限制节点异常退出后需要重新执行的区块数量
+	trieCommitInterval = 5 * time.Minute
+)
+
+//设置状态树脏节点缓存的上限(MB)以及是否为归档节点，需要在导入区块之前调用
+func (bc *BlockChain) SetTrieCache(dirtyLimit int, archive bool) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	bc.trieDirtyLimit = common.StorageSize(dirtyLimit) * 1024 * 1024
+	bc.archive = archive
+}
+
+//返回状态树节点的内存写入层
+func (bc *BlockChain) TrieDB() *trie.NodeDatabase {
+	return bc.triedb
+}
+
+//得到状态树节点或者合约代码，包括还没有写入磁盘的节点
+func (bc *BlockChain) TrieNode(hash common.Hash) ([]byte, error) {
+	return bc.triedb.Node(hash)
+}
+
+//区块状态提交到内存之后调用：非归档节点引用新的状态根并回收过期的状态，缓存超过上限时在后台写入磁盘
+func (bc *BlockChain) retainState(root common.Hash, number uint64) {
+
+	if !bc.archive {
+		bc.triedb.Reference(root, common.Hash{})
+		bc.triegc.Push(root, -float32(number))
+
+		if number > TriesInMemory {
+			chosen := number - TriesInMemory
+
+			//在回收之前把一个完整的状态写入磁盘(待写入的节点在调用时已经确定)
+			if time.Since(bc.trieCommitted) > trieCommitInterval {
+				if header := bc.GetHeaderByNumber(chosen); header != nil && bc.triedb.CommitAsync(header.Root) {
+					bc.trieCommitted = time.Now()
+				}
+			}
+			for !bc.triegc.Empty() {
+				root, number := bc.triegc.Pop()
+				if uint64(-number) > chosen {
+					bc.triegc.Push(root, number)
+					break
+				}
+				bc.triedb.Dereference(root.(common.Hash))
+			}
+		}
+	}
+	//后台写入跟不上导入速度时同步写入，限制内存占用
+	switch size := bc.triedb.Size(); {
+	case size > 2*bc.trieDirtyLimit:
+		if err := bc.triedb.Cap(bc.trieDirtyLimit - ethdb.IdealBatchSize); err != nil {
+			log.Error("Failed to flush trie nodes", "err", err)
+		}
+	case size > bc.trieDirtyLimit:
+		bc.triedb.CapAsync(bc.trieDirtyLimit - ethdb.IdealBatchSize)
+	}
+}
+
+//节点关闭时把状态写入磁盘：归档节点写入全部缓存的节点，其他节点写入链头区块的状态
+func (bc *BlockChain) flushTrieCache() {
+
+	var err error
+	if bc.archive {
+		err = bc.triedb.Cap(0)
+	} else {
+		err = bc.triedb.Commit(bc.CurrentBlock().Root())
+	}
+	if err != nil {
+		log.Error("Failed to flush trie nodes", "err", err)
+		return
+	}
+	log.Info("Flushed state trie cache", "number", bc.CurrentBlock().Number(), "archive", bc.archive, "pending", bc.triedb.Size())
+}
+
+//链头区块的状态不可用时(例如节点异常退出时还没有写入磁盘)，沿父区块回退到最近一个状态完整的区块
+func (bc *BlockChain) repairHeadState(head *types.Block) *types.Block {
+
+	for block := head; block != nil; block = bc.GetBlock(block.ParentHash(), block.NumberU64()-1) {
+		if _, err := state.New(block.Root(), bc.stateCache); err == nil {
+			if block.Hash() != head.Hash() {
+				log.Warn("Rewound blockchain to past state", "number", block.Number(), "hash", block.Hash(), "from", head.Number())
+			}
+			return block
+		}
+		if block.NumberU64() == 0 {
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/consensus/ethash"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/vm"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+// retainTestStates commits a chain of states, each crediting a new account, into
+// the trie cache of the blockchain as block imports would.
+func retainTestStates(t *testing.T, chain *BlockChain, n int) []common.Hash {
+	var (
+		roots  []common.Hash
+		parent = chain.Genesis().Root()
+	)
+	for i := 1; i <= n; i++ {
+		statedb, err := state.New(parent, chain.stateCache)
+		if err != nil {
+			t.Fatalf("state #%d: failed to open parent: %v", i, err)
+		}
+		statedb.AddBalance(common.BigToAddress(big.NewInt(int64(i))), big.NewInt(int64(i)))
+		root, err := statedb.CommitTo(chain.triedb, false)
+		if err != nil {
+			t.Fatalf("state #%d: failed to commit: %v", i, err)
+		}
+		chain.retainState(root, uint64(i))
+		roots, parent = append(roots, root), root
+	}
+	return roots
+}
+
+// Tests that recent states stay in memory while older ones are collected on
+// regular nodes and retained on archive nodes.
+func TestTrieCacheRetention(t *testing.T) {
+	for _, archive := range []bool{false, true} {
+		var (
+			db, _ = ethdb.NewMemDatabase()
+			gspec = &Genesis{Config: params.TestChainConfig}
+		)
+		gspec.MustCommit(db)
+		chain, err := NewBlockChain(db, gspec.Config, ethash.NewFullFaker(), vm.Config{})
+		if err != nil {
+			t.Fatalf("failed to create blockchain: %v", err)
+		}
+		chain.SetTrieCache(DefaultTrieDirtyCache, archive)
+
+		roots := retainTestStates(t, chain, TriesInMemory+2)
+		head := roots[len(roots)-1]
+
+		if _, err := state.New(head, chain.stateCache); err != nil {
+			t.Fatalf("archive %v: head state unavailable: %v", archive, err)
+		}
+		if ok, _ := db.Has(head[:]); ok {
+			t.Fatalf("archive %v: head state written before flush", archive)
+		}
+		_, err = state.New(roots[0], chain.stateCache)
+		if archive && err != nil {
+			t.Fatalf("archive %v: old state collected: %v", archive, err)
+		}
+		if !archive && err == nil {
+			t.Fatalf("archive %v: old state retained", archive)
+		}
+		if _, err := state.New(roots[2], chain.stateCache); err != nil {
+			t.Fatalf("archive %v: recent state unavailable: %v", archive, err)
+		}
+		// Committing the head writes its complete state to disk
+		if err := chain.triedb.Commit(head); err != nil {
+			t.Fatalf("archive %v: failed to commit head: %v", archive, err)
+		}
+		statedb, err := state.New(head, state.NewDatabase(db))
+		if err != nil {
+			t.Fatalf("archive %v: committed state unavailable on disk: %v", archive, err)
+		}
+		for i := 1; i < len(roots); i++ {
+			if balance := statedb.GetBalance(common.BigToAddress(big.NewInt(int64(i)))); balance.Int64() != int64(i) {
+				t.Fatalf("archive %v: account %d balance mismatch: have %v, want %d", archive, i, balance, i)
+			}
+		}
+		chain.Stop()
+	}
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+)
+
+//交易池中的交易及其状态
+type PoolTx struct {
+	Tx     *types.Transaction
+	From   common.Address
+	Status TxStatus  //TxStatusPending或TxStatusQueued
+	Added  time.Time //加入交易池的时间(节点重启后从交易日志恢复的交易为恢复的时间)
+}
+
+//记录交易加入交易池的时间，替换同一Nonce的交易时重新计时
+func (pool *TxPool) markArrival(hash common.Hash) {
+	if _, ok := pool.arrivals[hash]; !ok {
+		pool.arrivals[hash] = time.Now()
+	}
+}
+
+//删除已经不在交易池中的交易的加入时间
+func (pool *TxPool) pruneArrivals() {
+	for hash := range pool.arrivals {
+		if pool.all[hash] == nil {
+			delete(pool.arrivals, hash)
+		}
+	}
+}
+
+//得到交易池中交易的状态，调用者需要持有锁
+func (pool *TxPool) poolTx(tx *types.Transaction) *PoolTx {
+
+	from, _ := types.Sender(types.HomesteadSigner{}, tx)
+	status := TxStatusQueued
+	if list := pool.pending[from]; list != nil && list.txs.Get(tx.Nonce()) == tx {
+		status = TxStatusPending
+	}
+	return &PoolTx{Tx: tx, From: from, Status: status, Added: pool.arrivals[tx.Hash()]}
+}
+
+//得到交易池中的交易及其状态，交易不在交易池中时返回nil
+func (pool *TxPool) Lookup(hash common.Hash) *PoolTx {
+
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	tx := pool.all[hash]
+	if tx == nil {
+		return nil
+	}
+	return pool.poolTx(tx)
+}
+
+//得到交易池中账号Nonce在[from, to]范围内的交易(包括可执行和排队的交易)，按Nonce排序
+func (pool *TxPool) NonceRange(addr common.Address, from, to uint64) []*PoolTx {
+
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	var txs []*PoolTx
+	for _, list := range []*txList{pool.pending[addr], pool.queue[addr]} {
+		if list == nil {
+			continue
+		}
+		for _, tx := range list.Flatten() {
+			if tx.Nonce() >= from && tx.Nonce() <= to {
+				txs = append(txs, pool.poolTx(tx))
+			}
+		}
+	}
+	sort.Slice(txs, func(i, j int) bool { return txs[i].Tx.Nonce() < txs[j].Tx.Nonce() })
+	return txs
+}
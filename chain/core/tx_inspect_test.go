@@ -0,0 +1,91 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+)
+
+// Tests that pooled transactions can be looked up by hash and by nonce range,
+// reporting whether they are executable or queued behind a nonce gap.
+func TestTransactionLookup(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	pool.currentState.AddBalance(from, big.NewInt(1000000))
+
+	//Nonce 0和1可执行，Nonce 3因为缺少Nonce 2而排队
+	txs := types.Transactions{
+		transaction(0, big.NewInt(100000), key),
+		transaction(1, big.NewInt(100000), key),
+		transaction(3, big.NewInt(100000), key),
+	}
+	start := time.Now()
+	for i, err := range pool.AddRemotes(txs) {
+		if err != nil {
+			t.Fatalf("tx %d: failed to add: %v", i, err)
+		}
+	}
+
+	for i, want := range []TxStatus{TxStatusPending, TxStatusPending, TxStatusQueued} {
+		ptx := pool.Lookup(txs[i].Hash())
+		if ptx == nil {
+			t.Fatalf("tx %d: not found", i)
+		}
+		if ptx.Tx != txs[i] || ptx.From != from || ptx.Status != want {
+			t.Errorf("tx %d: have %x from %x status %v, want %x from %x status %v", i, ptx.Tx.Hash(), ptx.From, ptx.Status, txs[i].Hash(), from, want)
+		}
+		if ptx.Added.Before(start.Add(-time.Second)) || ptx.Added.After(time.Now()) {
+			t.Errorf("tx %d: arrival time %v out of range", i, ptx.Added)
+		}
+	}
+	if ptx := pool.Lookup(common.Hash{0x01}); ptx != nil {
+		t.Errorf("unknown transaction found: %+v", ptx)
+	}
+
+	//范围包含两端，并且按Nonce排序
+	tests := []struct {
+		from, to uint64
+		nonces   []uint64
+	}{
+		{0, 10, []uint64{0, 1, 3}},
+		{1, 3, []uint64{1, 3}},
+		{2, 2, nil},
+		{4, 10, nil},
+	}
+	for _, tt := range tests {
+		ptxs := pool.NonceRange(from, tt.from, tt.to)
+		if len(ptxs) != len(tt.nonces) {
+			t.Errorf("range %d-%d: have %d transactions, want %d", tt.from, tt.to, len(ptxs), len(tt.nonces))
+			continue
+		}
+		for i, ptx := range ptxs {
+			if ptx.Tx.Nonce() != tt.nonces[i] {
+				t.Errorf("range %d-%d: tx %d nonce mismatch: have %d, want %d", tt.from, tt.to, i, ptx.Tx.Nonce(), tt.nonces[i])
+			}
+		}
+	}
+	if ptxs := pool.NonceRange(common.Address{0x01}, 0, 10); len(ptxs) != 0 {
+		t.Errorf("unknown account has %d transactions", len(ptxs))
+	}
+
+	//交易离开交易池后清除加入时间
+	pool.mu.Lock()
+	pool.removeTx(txs[2].Hash())
+	pool.pruneArrivals()
+	_, ok := pool.arrivals[txs[2].Hash()]
+	pool.mu.Unlock()
+	if ok {
+		t.Errorf("arrival time of removed transaction kept")
+	}
+	if ptx := pool.Lookup(txs[2].Hash()); ptx != nil {
+		t.Errorf("removed transaction found: %+v", ptx)
+	}
+}
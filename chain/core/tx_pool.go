@@ -3,7 +3,6 @@ package core
 import (
 	"errors"
 	"fmt"
-	"math"
 	"math/big"
 	"sort"
 	"sync"
@@ -14,6 +13,7 @@ import (
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/core/state"
 	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
 	"github.com/Tinachain/Tina/chain/event"
 	"github.com/Tinachain/Tina/chain/log"
 	"github.com/Tinachain/Tina/chain/metrics"
@@ -61,6 +61,10 @@ var (
 	queuedRateLimitCounter = metrics.NewCounter("txpool/queued/ratelimit") // Dropped due to rate limiting
 	queuedNofundsCounter   = metrics.NewCounter("txpool/queued/nofunds")   // Dropped due to out-of-funds
 
+	//交易池大小
+	pendingGauge = metrics.NewGauge("txpool/pending")
+	queuedGauge  = metrics.NewGauge("txpool/queued")
+
 	// General tx metrics
 	invalidTxCounter     = metrics.NewCounter("txpool/invalid")
 	underpricedTxCounter = metrics.NewCounter("txpool/underpriced")
@@ -83,6 +87,7 @@ type blockChain interface {
 	GetBlock(hash common.Hash, number uint64) *types.Block
 	StateAt(root common.Hash) (*state.StateDB, error)
 	SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Subscription
+	SubscribeRemovedTransactionEvent(ch chan<- RemovedTransactionEvent) event.Subscription
 	Boker() bokerapi.Api
 }
 
@@ -91,6 +96,8 @@ type TxPoolConfig struct {
 	NoLocals     bool          //Whether local transaction handling should be disabled
 	Journal      string        //Journal of local transactions to survive node restarts
 	Rejournal    time.Duration //重新生成本地交易日志的时间间隔
+	Scheduled    string        //定时交易的存储文件(为空时只保存在内存中)
+	Rebroadcast  time.Duration //重新广播本地未打包交易的时间间隔(0为不重新广播)
 	PriceLimit   uint64        //最小的GasPrice Minimum gas price to enforce for acceptance into the pool
 	PriceBump    uint64        //最小的Price Minimum price bump percentage to replace an already existing transaction (nonce)
 	AccountSlots uint64        //Minimum number of executable transaction slots guaranteed per account
@@ -104,6 +111,8 @@ type TxPoolConfig struct {
 var DefaultTxPoolConfig = TxPoolConfig{
 	Journal:      "transactions.rlp",
 	Rejournal:    time.Hour,
+	Scheduled:    "scheduled.rlp",
+	Rebroadcast:  10 * time.Minute,
 	PriceLimit:   1,
 	PriceBump:    10,
 	AccountSlots: 16,            //一个账户所能放的默认交易数量
@@ -141,28 +150,35 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 // TxPool包含所有当前已知的交易。交易从网络收到或提交时进入池本地 当它们被包含在区块链中时，它们会退出交易池。
 //交易池分隔可处理的交易（可以应用于当前状态）和未来的交易。 交易在这些之间移动随着时间的推移，它们会被接收和处理。
 type TxPool struct {
-	config        TxPoolConfig                       //交易池配置
-	chainconfig   *params.ChainConfig                //链配置
-	chain         blockChain                         //链
-	gasPrice      *big.Int                           //最低的GasPrice限制
-	txFeed        event.Feed                         //通过txFeed来订阅TxPool的消息
-	scope         event.SubscriptionScope            //
-	chainHeadCh   chan ChainHeadEvent                //订阅了区块头的消息，当有了新的区块头生成的时候会在这里收到通知
-	chainHeadSub  event.Subscription                 //区块头消息的订阅器
-	signer        types.Signer                       //封装了交易签名处理
-	mu            sync.RWMutex                       //
-	currentState  *state.StateDB                     //区块链头部当前状态
-	pendingState  *state.ManagedState                //Pending state tracking virtual nonces
-	currentMaxGas *big.Int                           //当前的交易Gas上限
-	locals        *accountSet                        //Set of local transaction to exepmt from evicion rules
-	journal       *txJournal                         //日志本地交易备份到磁盘
-	pending       map[common.Address]*txList         //所有当前可处理的交易
-	queue         map[common.Address]*txList         //不可处理的交易队列
-	beats         map[common.Address]time.Time       //每个已知帐户的最后心跳
-	all           map[common.Hash]*types.Transaction //允许查看的所有交易
-	priced        *txPricedList                      //按价格排序的所有交易
-	wg            sync.WaitGroup                     //for shutdown sync
-	homestead     bool
+	config          TxPoolConfig                       //交易池配置
+	chainconfig     *params.ChainConfig                //链配置
+	chain           blockChain                         //链
+	gasPrice        *big.Int                           //最低的GasPrice限制
+	txFeed          event.Feed                         //通过txFeed来订阅TxPool的消息
+	rebroadcastFeed event.Feed                         //需要重新广播的交易
+	scope           event.SubscriptionScope            //
+	chainHeadCh     chan ChainHeadEvent                //订阅了区块头的消息，当有了新的区块头生成的时候会在这里收到通知
+	chainHeadSub    event.Subscription                 //区块头消息的订阅器
+	rmTxCh          chan RemovedTransactionEvent       //链重组时被丢弃区块中需要重新放入交易池的交易
+	rmTxSub         event.Subscription                 //链重组交易消息的订阅器
+	signer          types.Signer                       //封装了交易签名处理
+	mu              sync.RWMutex                       //
+	currentState    *state.StateDB                     //区块链头部当前状态
+	pendingState    *state.ManagedState                //Pending state tracking virtual nonces
+	currentMaxGas   *big.Int                           //当前的交易Gas上限
+	locals          *accountSet                        //Set of local transaction to exepmt from evicion rules
+	journal         *txJournal                         //日志本地交易备份到磁盘
+	pending         map[common.Address]*txList         //所有当前可处理的交易
+	queue           map[common.Address]*txList         //不可处理的交易队列
+	beats           map[common.Address]time.Time       //每个已知帐户的最后心跳
+	all             map[common.Hash]*types.Transaction //允许查看的所有交易
+	arrivals        map[common.Hash]time.Time          //交易加入交易池的时间
+	scheduled       map[common.Hash]*ScheduledTx       //还没有到达生效区块的定时交易
+	priced          *txPricedList                      //按价格排序的所有交易
+	wg              sync.WaitGroup                     //for shutdown sync
+	homestead       bool
+	sponsor         bool //下一个区块是否已经过了代付分叉
+	contractMeta    bool //下一个区块是否已经过了合约元数据分叉
 }
 
 //创建一个新的交易池，排序和过滤入站来自网络的交易
@@ -182,12 +198,18 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		queue:       make(map[common.Address]*txList),
 		beats:       make(map[common.Address]time.Time),
 		all:         make(map[common.Hash]*types.Transaction),
+		arrivals:    make(map[common.Hash]time.Time),
+		scheduled:   make(map[common.Hash]*ScheduledTx),
 		chainHeadCh: make(chan ChainHeadEvent, chainHeadChanSize),
+		rmTxCh:      make(chan RemovedTransactionEvent, rmTxChanSize),
 		gasPrice:    new(big.Int).SetUint64(config.PriceLimit),
 	}
 	//pool.locals = newAccountSet(pool.signer)
 	pool.locals = newAccountSet(types.HomesteadSigner{})
 	pool.priced = newTxPricedList(&pool.all)
+	if config.Scheduled != "" {
+		pool.loadScheduled()
+	}
 	pool.reset(nil, chain.CurrentBlock().Header())
 
 	//如果本地交易被允许,而且配置的Journal目录不为空,那么从指定的目录加载日志.
@@ -205,6 +227,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 
 	//从区块链订阅事件
 	pool.chainHeadSub = pool.chain.SubscribeChainHeadEvent(pool.chainHeadCh)
+	pool.rmTxSub = pool.chain.SubscribeRemovedTransactionEvent(pool.rmTxCh)
 
 	//启动交易池检测循环
 	pool.wg.Add(1)
@@ -232,6 +255,14 @@ func (pool *TxPool) loop() {
 	journal := time.NewTicker(pool.config.Rejournal)
 	defer journal.Stop()
 
+	//启动重新广播本地交易的定时器
+	var rebroadcast <-chan time.Time
+	if pool.config.Rebroadcast > 0 {
+		ticker := time.NewTicker(pool.config.Rebroadcast)
+		defer ticker.Stop()
+		rebroadcast = ticker.C
+	}
+
 	// Track the previous head headers for transaction reorgs
 	head := pool.chain.CurrentBlock()
 
@@ -250,9 +281,15 @@ func (pool *TxPool) loop() {
 
 				pool.mu.Unlock()
 			}
+		//链重组后将被丢弃区块中的交易重新放入交易池
+		case ev := <-pool.rmTxCh:
+			pool.reinjectRemoved(ev.Txs)
+
 		//由于系统停止而取消订阅
 		case <-pool.chainHeadSub.Err():
 			return
+		case <-pool.rmTxSub.Err():
+			return
 
 		//报告就是打印了一些日志
 		case <-report.C:
@@ -260,6 +297,8 @@ func (pool *TxPool) loop() {
 			pending, queued := pool.stats()
 			stales := pool.priced.stales
 			pool.mu.RUnlock()
+			pendingGauge.Update(int64(pending))
+			queuedGauge.Update(int64(queued))
 
 			if pending != prevPending || queued != prevQueued || stales != prevStales {
 				log.Debug("Transaction pool status report", "executable", pending, "queued", queued, "stales", stales)
@@ -283,6 +322,7 @@ func (pool *TxPool) loop() {
 					}
 				}
 			}
+			pool.pruneArrivals()
 			pool.mu.Unlock()
 
 		//处理定时写交易日志的信息
@@ -294,6 +334,10 @@ func (pool *TxPool) loop() {
 				}
 				pool.mu.Unlock()
 			}
+
+		//重新广播本地账号还没有打包的交易
+		case <-rebroadcast:
+			pool.rebroadcastLocals()
 		}
 	}
 }
@@ -308,69 +352,12 @@ func (pool *TxPool) lockedReset(oldHead, newHead *types.Header) {
 }
 
 //reset方法检索区块链的当前状态并且确保交易池的内容关于当前的区块链状态是有效的。主要功能包括：
-//因为更换了区块头，所以原有的区块中有一些交易因为区块头的更换而作废，这部分交易需要重新加入到txPool里面等待插入新的区块
+//(链重组时被丢弃区块中的交易由区块链通过RemovedTransactionEvent通知，见reinjectRemoved)
 //生成新的currentState和pendingState
 //因为状态的改变。将pending中的部分交易移到queue里面
 //因为状态的改变，将queue里面的交易移入到pending里面。
 func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 
-	//如果我们要重新安排旧状态，请重新注入所有丢弃的交易
-	var reinject types.Transactions
-	if oldHead != nil && oldHead.Hash() != newHead.ParentHash {
-
-		// If the reorg is too deep, avoid doing it (will happen during fast sync)
-		oldNum := oldHead.Number.Uint64()
-		newNum := newHead.Number.Uint64()
-
-		if depth := uint64(math.Abs(float64(oldNum) - float64(newNum))); depth > 64 {
-			log.Warn("Skipping deep transaction reorg", "depth", depth)
-		} else {
-			// Reorg seems shallow enough to pull in all transactions into memory
-			var discarded, included types.Transactions
-
-			var (
-				rem = pool.chain.GetBlock(oldHead.Hash(), oldHead.Number.Uint64())
-				add = pool.chain.GetBlock(newHead.Hash(), newHead.Number.Uint64())
-			)
-
-			//如果老的高度大于新的.那么需要把多的全部删除.
-			for rem.NumberU64() > add.NumberU64() {
-				discarded = append(discarded, rem.Transactions()...)
-				if rem = pool.chain.GetBlock(rem.ParentHash(), rem.NumberU64()-1); rem == nil {
-					log.Error("Unrooted old chain seen by tx pool", "block", oldHead.Number, "hash", oldHead.Hash())
-					return
-				}
-			}
-
-			//如果新的高度大于老的, 那么需要增加.
-			for add.NumberU64() > rem.NumberU64() {
-				included = append(included, add.Transactions()...)
-				if add = pool.chain.GetBlock(add.ParentHash(), add.NumberU64()-1); add == nil {
-					log.Error("Unrooted new chain seen by tx pool", "block", newHead.Number, "hash", newHead.Hash())
-					return
-				}
-			}
-
-			//高度相同了.如果hash不同,那么需要往后找,一直找到他们相同hash根的节点.
-			for rem.Hash() != add.Hash() {
-				discarded = append(discarded, rem.Transactions()...)
-				if rem = pool.chain.GetBlock(rem.ParentHash(), rem.NumberU64()-1); rem == nil {
-					log.Error("Unrooted old chain seen by tx pool", "block", oldHead.Number, "hash", oldHead.Hash())
-					return
-				}
-				included = append(included, add.Transactions()...)
-				if add = pool.chain.GetBlock(add.ParentHash(), add.NumberU64()-1); add == nil {
-					log.Error("Unrooted new chain seen by tx pool", "block", newHead.Number, "hash", newHead.Hash())
-					return
-				}
-			}
-
-			//找出所有存在discard里面,但是不在included里面的值.
-			//需要等下把这些交易重新插入到pool里面。
-			reinject = types.TxDifference(discarded, included)
-		}
-	}
-
 	//将内部状态初始化为当前头部
 	if newHead == nil {
 		newHead = pool.chain.CurrentBlock().Header() // Special case during testing
@@ -386,12 +373,11 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 
 	//这里需要进行判断，如果是基础合约的话，GasLimit会很大，这里要看是否进行处理
 	pool.currentMaxGas = newHead.GasLimit
+	next := new(big.Int).Add(newHead.Number, big.NewInt(1))
+	pool.sponsor = pool.chainconfig.IsSponsor(next)
+	pool.contractMeta = pool.chainconfig.IsContractMeta(next)
 	//log.Info("Set newHead.GasLimit", "newHead.GasLimit", newHead.GasLimit, "Number", newHead.Number)
 
-	// Inject any transactions discarded due to reorgs
-	//log.Debug("Reinjecting stale transactions", "count", len(reinject))
-	pool.addTxsLocked(reinject, false)
-
 	//验证pending transaction池里面的交易， 会移除所有已经存在区块链里面的交易，或者是因为其他交易导致不可用的交易(比如有一个更高的gasPrice)
 	//demote 降级 将pending中的一些交易降级到queue里面。
 	pool.demoteUnexecutables()
@@ -405,6 +391,9 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	//检查队列并尽可能地将事务移到pending，或删除那些已经失效的事务
 	//promote 升级
 	pool.promoteExecutables(nil)
+
+	//将下一个区块可以打包的定时交易加入交易池
+	pool.promoteScheduled(newHead.Number.Uint64())
 }
 
 // Stop terminates the transaction pool.
@@ -414,6 +403,7 @@ func (pool *TxPool) Stop() {
 
 	// Unsubscribe subscriptions registered from blockchain
 	pool.chainHeadSub.Unsubscribe()
+	pool.rmTxSub.Unsubscribe()
 	pool.wg.Wait()
 
 	if pool.journal != nil {
@@ -551,10 +541,21 @@ func (pool *TxPool) normalValidateTx(tx *types.Transaction, local bool) error {
 	}
 	log.Info("normalValidateTx", "from current Nonce", pool.currentState.GetNonce(from), "tx Nonce", tx.Nonce())
 
-	//cost == Value + GasPrice * GasLimit
+	//代付分叉之后代付交易的Gas由代付账号支付，需要校验代付签名以及代付账号的余额
+	if pool.sponsor && IsSponsored(tx) {
+		payer, err := TxSponsor(pool.chainconfig.ChainId, from, tx)
+		if err != nil {
+			return err
+		}
+		if pool.currentState.GetBalance(payer).Cmp(sponsorCost(tx)) < 0 {
+			return protocol.ErrSponsorInsufficient
+		}
+	}
+
+	//cost == Value + GasPrice * GasLimit(代付交易只有Value)
 	//判断当前from用户的钱是否大于本次交易所花成本的最大值，如果小于则返回 ErrInsufficientFunds
-	if pool.currentState.GetBalance(from).Cmp(tx.Cost()) < 0 {
-		log.Error("normalValidateTx", "balance", pool.currentState.GetBalance(from), "cost", tx.Cost())
+	if pool.currentState.GetBalance(from).Cmp(senderCost(tx, pool.sponsor)) < 0 {
+		log.Error("normalValidateTx", "balance", pool.currentState.GetBalance(from), "cost", senderCost(tx, pool.sponsor))
 		return ErrInsufficientFunds
 	}
 
@@ -684,6 +685,9 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 		if len(tx.Extra()) > int(protocol.MaxExtraSize) {
 			return ErrOverExtraData
 		}
+		if err := CheckExtraPayload(pool.chainconfig, tx); err != nil {
+			return err
+		}
 
 	}
 
@@ -709,7 +713,7 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 		}
 	} else if protocol.Extra == tx.Major() {
 
-		if (tx.Minor() >= protocol.Word) && (tx.Minor() <= protocol.Data) {
+		if (tx.Minor() >= protocol.Word) && (tx.Minor() <= protocol.Data || (tx.Minor() == protocol.ContractMeta && pool.contractMeta)) {
 
 			return pool.extraValidateTx(tx, local)
 		}
@@ -766,7 +770,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 	if list := pool.pending[from]; list != nil && list.Overlaps(tx) {
 
 		//本次交易的Nonce已经存在，检查是否满足所需的价格冲击
-		inserted, old := list.Add(tx, pool.config.PriceBump)
+		inserted, old := list.Add(tx, pool.config.PriceBump, pool.sponsor)
 		if !inserted {
 			log.Error("TxPool add nonce Exists", "nonce", tx.Nonce())
 			pendingDiscardCounter.Inc(1)
@@ -784,6 +788,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 
 		//在交易池中添加本次交易
 		pool.all[tx.Hash()] = tx
+		pool.markArrival(tx.Hash())
 		pool.priced.Put(tx)
 		pool.journalTx(from, tx)
 
@@ -820,7 +825,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction) (bool, er
 	if pool.queue[from] == nil {
 		pool.queue[from] = newTxList(false)
 	}
-	inserted, old := pool.queue[from].Add(tx, pool.config.PriceBump)
+	inserted, old := pool.queue[from].Add(tx, pool.config.PriceBump, pool.sponsor)
 	if !inserted {
 		// An older transaction was better, discard this
 		queuedDiscardCounter.Inc(1)
@@ -833,6 +838,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction) (bool, er
 		queuedReplaceCounter.Inc(1)
 	}
 	pool.all[hash] = tx
+	pool.markArrival(hash)
 	pool.priced.Put(tx)
 	return old != nil, nil
 }
@@ -858,7 +864,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	}
 	list := pool.pending[addr]
 
-	inserted, old := list.Add(tx, pool.config.PriceBump)
+	inserted, old := list.Add(tx, pool.config.PriceBump, pool.sponsor)
 	if !inserted {
 
 		// 如果不能替换, 已经存在一个老的交易了. 删除.
@@ -1007,6 +1013,45 @@ func (pool *TxPool) Get(hash common.Hash) *types.Transaction {
 	return pool.all[hash]
 }
 
+//判断重复提交的交易使用的键(交易类型、接收地址以及负载哈希，发送账号由交易池按账号分组)
+type duplicateKey struct {
+	major   protocol.TxMajor
+	minor   protocol.TxMinor
+	to      common.Address
+	payload common.Hash
+}
+
+func txDuplicateKey(tx *types.Transaction) duplicateKey {
+
+	key := duplicateKey{major: tx.Major(), minor: tx.Minor()}
+	if to := tx.To(); to != nil {
+		key.to = *to
+	}
+	key.payload = crypto.Keccak256Hash(tx.Value().Bytes(), tx.Name(), tx.Data(), tx.Extra(), []byte{tx.Encryption()})
+	return key
+}
+
+//返回交易池(包括可处理以及排队的交易)中from账号与tx的交易类型、接收地址以及负载都相同的交易，没有则返回nil，
+//用于避免RPC被快速重复调用时以连续的Nonce提交重复的系统交易
+func (pool *TxPool) FindDuplicate(from common.Address, tx *types.Transaction) *types.Transaction {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	key := txDuplicateKey(tx)
+	for _, lists := range []map[common.Address]*txList{pool.pending, pool.queue} {
+		list := lists[from]
+		if list == nil {
+			continue
+		}
+		for _, pooled := range list.Flatten() {
+			if txDuplicateKey(pooled) == key {
+				return pooled
+			}
+		}
+	}
+	return nil
+}
+
 //删除某个交易， 并把所有后续的交易移动到future queue
 func (pool *TxPool) removeTx(hash common.Hash) {
 	// Fetch the transaction we wish to delete
@@ -1078,7 +1123,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 		}
 
 		//删除所有余额不足的交易。
-		drops, _ := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
+		drops, _ := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas, pool.sponsor)
 		for _, tx := range drops {
 			hash := tx.Hash()
 			log.Trace("Removed unpayable queued transaction", "hash", hash)
@@ -1267,7 +1312,7 @@ func (pool *TxPool) demoteUnexecutables() {
 		}
 
 		// 删除所有的太昂贵的交易。 用户的balance可能不够用。或者是out of gas
-		drops, invalids := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
+		drops, invalids := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas, pool.sponsor)
 		for _, tx := range drops {
 			hash := tx.Hash()
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+)
+
+// Tests that pending and queued transactions of the same account, type,
+// recipient and payload are reported as duplicates regardless of their nonce.
+func TestFindDuplicate(t *testing.T) {
+	var (
+		from, other = common.HexToAddress("0x01"), common.HexToAddress("0x02")
+		to          = common.HexToAddress("0x03")
+		pool        = &TxPool{pending: make(map[common.Address]*txList), queue: make(map[common.Address]*txList)}
+	)
+	pending := types.NewBaseTransaction(protocol.SystemBase, protocol.SetSigningKey, 0, to, new(big.Int), []byte("key"))
+	queued := types.NewBaseTransaction(protocol.SystemBase, protocol.SetValidatorInfo, 5, to, new(big.Int), []byte("info"))
+	pool.pending[from] = newTxList(true)
+	pool.pending[from].Add(pending, 10, false)
+	pool.queue[from] = newTxList(false)
+	pool.queue[from].Add(queued, 10, false)
+
+	tests := []struct {
+		from common.Address
+		tx   *types.Transaction
+		want *types.Transaction
+	}{
+		{from, types.NewBaseTransaction(protocol.SystemBase, protocol.SetSigningKey, 1, to, new(big.Int), []byte("key")), pending},
+		{from, types.NewBaseTransaction(protocol.SystemBase, protocol.SetValidatorInfo, 6, to, new(big.Int), []byte("info")), queued},
+		{from, types.NewBaseTransaction(protocol.SystemBase, protocol.SetSigningKey, 1, to, new(big.Int), []byte("other")), nil},
+		{from, types.NewBaseTransaction(protocol.SystemBase, protocol.SetSigningKey, 1, from, new(big.Int), []byte("key")), nil},
+		{from, types.NewBaseTransaction(protocol.SystemBase, protocol.SetSigningKey, 1, to, big.NewInt(1), []byte("key")), nil},
+		{from, types.NewBaseTransaction(protocol.SystemBase, protocol.SetCommission, 1, to, new(big.Int), []byte("key")), nil},
+		{other, types.NewBaseTransaction(protocol.SystemBase, protocol.SetSigningKey, 0, to, new(big.Int), []byte("key")), nil},
+	}
+	for i, tt := range tests {
+		if have := pool.FindDuplicate(tt.from, tt.tx); have != tt.want {
+			t.Errorf("test %d: duplicate mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
@@ -26,6 +26,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Tinachain/Tina/chain/boker/api"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/core/state"
 	"github.com/Tinachain/Tina/chain/core/types"
@@ -68,12 +70,20 @@ func (bc *testBlockChain) SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) even
 	return bc.chainHeadFeed.Subscribe(ch)
 }
 
+func (bc *testBlockChain) SubscribeRemovedTransactionEvent(ch chan<- RemovedTransactionEvent) event.Subscription {
+	return new(event.Feed).Subscribe(ch)
+}
+
+func (bc *testBlockChain) Boker() bokerapi.Api {
+	return nil
+}
+
 func transaction(nonce uint64, gaslimit *big.Int, key *ecdsa.PrivateKey) *types.Transaction {
 	return pricedTransaction(nonce, gaslimit, big.NewInt(1), key)
 }
 
 func pricedTransaction(nonce uint64, gaslimit, gasprice *big.Int, key *ecdsa.PrivateKey) *types.Transaction {
-	tx, _ := types.SignTx(types.NewTransaction(types.Binary, nonce, common.Address{}, big.NewInt(100), gaslimit, gasprice, nil), types.HomesteadSigner{}, key)
+	tx, _ := types.SignTx(types.NewTransaction(protocol.Normal, protocol.NormalCall, nonce, common.Address{}, big.NewInt(100), gaslimit, gasprice, nil), types.HomesteadSigner{}, key)
 	return tx
 }
 
@@ -321,7 +331,7 @@ func TestTransactionNegativeValue(t *testing.T) {
 	pool, key := setupTxPool()
 	defer pool.Stop()
 
-	tx, _ := types.SignTx(types.NewTransaction(types.Binary, 0, common.Address{}, big.NewInt(-1), big.NewInt(100), big.NewInt(1), nil), types.HomesteadSigner{}, key)
+	tx, _ := types.SignTx(types.NewTransaction(protocol.Normal, protocol.NormalCall, 0, common.Address{}, big.NewInt(-1), big.NewInt(100), big.NewInt(1), nil), types.HomesteadSigner{}, key)
 	from, _ := deriveSender(tx)
 	pool.currentState.AddBalance(from, big.NewInt(1))
 	if err := pool.AddRemote(tx); err != ErrNegativeValue {
@@ -377,9 +387,9 @@ func TestTransactionDoubleNonce(t *testing.T) {
 	resetState()
 
 	signer := types.HomesteadSigner{}
-	tx1, _ := types.SignTx(types.NewTransaction(types.Binary, 0, common.Address{}, big.NewInt(100), big.NewInt(100000), big.NewInt(1), nil), signer, key)
-	tx2, _ := types.SignTx(types.NewTransaction(types.Binary, 0, common.Address{}, big.NewInt(100), big.NewInt(1000000), big.NewInt(2), nil), signer, key)
-	tx3, _ := types.SignTx(types.NewTransaction(types.Binary, 0, common.Address{}, big.NewInt(100), big.NewInt(1000000), big.NewInt(1), nil), signer, key)
+	tx1, _ := types.SignTx(types.NewTransaction(protocol.Normal, protocol.NormalCall, 0, common.Address{}, big.NewInt(100), big.NewInt(100000), big.NewInt(1), nil), signer, key)
+	tx2, _ := types.SignTx(types.NewTransaction(protocol.Normal, protocol.NormalCall, 0, common.Address{}, big.NewInt(100), big.NewInt(1000000), big.NewInt(2), nil), signer, key)
+	tx3, _ := types.SignTx(types.NewTransaction(protocol.Normal, protocol.NormalCall, 0, common.Address{}, big.NewInt(100), big.NewInt(1000000), big.NewInt(1), nil), signer, key)
 
 	// Add the first two transaction, ensure higher priced stays only
 	if replace, err := pool.add(tx1, false); err != nil || replace {
@@ -1563,6 +1573,58 @@ func testTransactionJournaling(t *testing.T, nolocals bool) {
 	pool.Stop()
 }
 
+// Tests that only the executable local transactions are rebroadcast, and that
+// a single pooled transaction can be rebroadcast on demand.
+func TestTransactionRebroadcast(t *testing.T) {
+	t.Parallel()
+
+	pool, local := setupTxPool()
+	defer pool.Stop()
+
+	remote, _ := crypto.GenerateKey()
+	for _, key := range []*ecdsa.PrivateKey{local, remote} {
+		pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000))
+	}
+	events := make(chan TxRebroadcastEvent, 1)
+	sub := pool.SubscribeTxRebroadcastEvent(events)
+	defer sub.Unsubscribe()
+
+	pending, queued := transaction(0, big.NewInt(100000), local), transaction(2, big.NewInt(100000), local)
+	if err := pool.AddLocal(pending); err != nil {
+		t.Fatalf("failed to add pending local transaction: %v", err)
+	}
+	if err := pool.AddLocal(queued); err != nil {
+		t.Fatalf("failed to add queued local transaction: %v", err)
+	}
+	if err := pool.AddRemote(transaction(0, big.NewInt(100000), remote)); err != nil {
+		t.Fatalf("failed to add remote transaction: %v", err)
+	}
+	pool.rebroadcastLocals()
+	select {
+	case ev := <-events:
+		if len(ev.Txs) != 1 || ev.Txs[0].Hash() != pending.Hash() {
+			t.Errorf("rebroadcast locals mismatch: have %v, want %x", ev.Txs, pending.Hash())
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("local transactions not rebroadcast")
+	}
+
+	if pool.Rebroadcast(common.Hash{0x01}) {
+		t.Errorf("unknown transaction rebroadcast")
+	}
+	if !pool.Rebroadcast(queued.Hash()) {
+		t.Fatalf("pooled transaction not rebroadcast")
+	}
+	select {
+	case ev := <-events:
+		if len(ev.Txs) != 1 || ev.Txs[0].Hash() != queued.Hash() {
+			t.Errorf("rebroadcast mismatch: have %v, want %x", ev.Txs, queued.Hash())
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("transaction not rebroadcast")
+	}
+}
+
 // Benchmarks the speed of validating the contents of the pending queue of the
 // transaction pool.
 func BenchmarkPendingDemotion100(b *testing.B)   { benchmarkPendingDemotion(b, 100) }
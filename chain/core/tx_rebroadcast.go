@@ -0,0 +1,53 @@
+package core
+
+import (
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/event"
+	"github.com/Tinachain/Tina/chain/log"
+)
+
+//订阅需要重新广播的交易
+func (pool *TxPool) SubscribeTxRebroadcastEvent(ch chan<- TxRebroadcastEvent) event.Subscription {
+	return pool.scope.Track(pool.rebroadcastFeed.Subscribe(ch))
+}
+
+//得到本地账号还没有打包的可执行交易
+func (pool *TxPool) localPending() types.Transactions {
+
+	var txs types.Transactions
+	for addr := range pool.locals.accounts {
+		if pending := pool.pending[addr]; pending != nil {
+			txs = append(txs, pending.Flatten()...)
+		}
+	}
+	return txs
+}
+
+//重新广播本地账号还没有打包的可执行交易，防止交易在提交后因为节点断开而丢失
+func (pool *TxPool) rebroadcastLocals() {
+
+	pool.mu.RLock()
+	txs := pool.localPending()
+	pool.mu.RUnlock()
+
+	if len(txs) == 0 {
+		return
+	}
+	log.Debug("Rebroadcasting local transactions", "count", len(txs))
+	go pool.rebroadcastFeed.Send(TxRebroadcastEvent{txs})
+}
+
+//立即重新广播交易池中的交易，交易不在交易池中时返回false
+func (pool *TxPool) Rebroadcast(hash common.Hash) bool {
+
+	pool.mu.RLock()
+	tx := pool.all[hash]
+	pool.mu.RUnlock()
+
+	if tx == nil {
+		return false
+	}
+	go pool.rebroadcastFeed.Send(TxRebroadcastEvent{types.Transactions{tx}})
+	return true
+}
@@ -0,0 +1,177 @@
+package core
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+//交易池最多保存的定时交易数量
+const maxScheduledTxs = 1024
+
+var (
+	ErrScheduledKnown = errors.New("known scheduled transaction")
+	ErrScheduledFull  = errors.New("too many scheduled transactions")
+)
+
+//定时交易: 交易在区块高度达到Block之前保存在交易池中，既不广播也不打包
+type ScheduledTx struct {
+	Block uint64             //可以被打包的最低区块高度
+	Tx    *types.Transaction //已签名的交易
+}
+
+//添加定时交易，生效区块不高于下一个区块时直接作为本地交易加入交易池
+func (pool *TxPool) AddScheduled(tx *types.Transaction, block uint64) error {
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	hash := tx.Hash()
+	if _, ok := pool.scheduled[hash]; ok || pool.all[hash] != nil {
+		return ErrScheduledKnown
+	}
+	if err := tx.Validate(); err != nil {
+		return err
+	}
+	from, err := types.Sender(types.HomesteadSigner{}, tx)
+	if err != nil {
+		return ErrInvalidSender
+	}
+	if pool.currentState.GetNonce(from) > tx.Nonce() {
+		return ErrNonceTooLow
+	}
+
+	if next := pool.chain.CurrentBlock().NumberU64() + 1; block <= next {
+		replace, err := pool.add(tx, !pool.config.NoLocals)
+		if err != nil {
+			return err
+		}
+		if !replace {
+			pool.promoteExecutables([]common.Address{from})
+		}
+		return nil
+	}
+	if len(pool.scheduled) >= maxScheduledTxs {
+		return ErrScheduledFull
+	}
+	pool.scheduled[hash] = &ScheduledTx{Block: block, Tx: tx}
+	pool.saveScheduled()
+
+	log.Info("Scheduled transaction", "hash", hash, "from", from, "nonce", tx.Nonce(), "block", block)
+	return nil
+}
+
+//取消还没有生效的定时交易
+func (pool *TxPool) RemoveScheduled(hash common.Hash) bool {
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if _, ok := pool.scheduled[hash]; !ok {
+		return false
+	}
+	delete(pool.scheduled, hash)
+	pool.saveScheduled()
+	return true
+}
+
+//得到还没有生效的定时交易，按生效区块以及Nonce排序
+func (pool *TxPool) Scheduled() []*ScheduledTx {
+
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	scheduled := make([]*ScheduledTx, 0, len(pool.scheduled))
+	for _, stx := range pool.scheduled {
+		scheduled = append(scheduled, stx)
+	}
+	sort.Slice(scheduled, func(i, j int) bool {
+		if scheduled[i].Block != scheduled[j].Block {
+			return scheduled[i].Block < scheduled[j].Block
+		}
+		return scheduled[i].Tx.Nonce() < scheduled[j].Tx.Nonce()
+	})
+	return scheduled
+}
+
+//将下一个区块可以打包的定时交易加入交易池，注意! 此方法假定池锁已被保留！
+func (pool *TxPool) promoteScheduled(head uint64) {
+
+	var txs types.Transactions
+	for hash, stx := range pool.scheduled {
+		if stx.Block <= head+1 {
+			txs = append(txs, stx.Tx)
+			delete(pool.scheduled, hash)
+		}
+	}
+	if len(txs) == 0 {
+		return
+	}
+	//同一账号的交易按Nonce依次加入
+	sort.Sort(types.TxByNonce(txs))
+	for i, err := range pool.addTxsLocked(txs, !pool.config.NoLocals) {
+		if err != nil {
+			log.Warn("Failed to activate scheduled transaction", "hash", txs[i].Hash(), "err", err)
+			continue
+		}
+		log.Info("Activated scheduled transaction", "hash", txs[i].Hash(), "head", head)
+	}
+	pool.saveScheduled()
+}
+
+//从磁盘加载定时交易
+func (pool *TxPool) loadScheduled() {
+
+	input, err := os.Open(pool.config.Scheduled)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warn("Failed to load scheduled transactions", "err", err)
+		}
+		return
+	}
+	defer input.Close()
+
+	stream := rlp.NewStream(input, 0)
+	for len(pool.scheduled) < maxScheduledTxs {
+		stx := new(ScheduledTx)
+		if err := stream.Decode(stx); err != nil {
+			if err != io.EOF {
+				log.Warn("Failed to decode scheduled transaction", "err", err)
+			}
+			break
+		}
+		pool.scheduled[stx.Tx.Hash()] = stx
+	}
+	log.Info("Loaded scheduled transactions", "transactions", len(pool.scheduled))
+}
+
+//将定时交易写入磁盘，注意! 此方法假定池锁已被保留！
+func (pool *TxPool) saveScheduled() {
+
+	if pool.config.Scheduled == "" {
+		return
+	}
+	output, err := os.OpenFile(pool.config.Scheduled+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		log.Warn("Failed to save scheduled transactions", "err", err)
+		return
+	}
+	for _, stx := range pool.scheduled {
+		if err = rlp.Encode(output, stx); err != nil {
+			break
+		}
+	}
+	output.Close()
+	if err == nil {
+		err = os.Rename(pool.config.Scheduled+".new", pool.config.Scheduled)
+	}
+	if err != nil {
+		log.Warn("Failed to save scheduled transactions", "err", err)
+	}
+}
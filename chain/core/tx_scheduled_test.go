@@ -0,0 +1,122 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/api"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/event"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+// scheduledTestChain is a chain whose head number can be moved by the test.
+type scheduledTestChain struct {
+	statedb *state.StateDB
+	head    uint64
+	feed    event.Feed
+}
+
+func (c *scheduledTestChain) CurrentBlock() *types.Block {
+	return types.NewBlock(&types.Header{Number: new(big.Int).SetUint64(c.head), GasLimit: big.NewInt(1000000)}, nil, nil, nil)
+}
+
+func (c *scheduledTestChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	return c.CurrentBlock()
+}
+
+func (c *scheduledTestChain) StateAt(common.Hash) (*state.StateDB, error) {
+	return c.statedb, nil
+}
+
+func (c *scheduledTestChain) SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Subscription {
+	return c.feed.Subscribe(ch)
+}
+
+func (c *scheduledTestChain) SubscribeRemovedTransactionEvent(ch chan<- RemovedTransactionEvent) event.Subscription {
+	return new(event.Feed).Subscribe(ch)
+}
+
+func (c *scheduledTestChain) Boker() bokerapi.Api { return nil }
+
+// Tests that scheduled transactions are held outside of the pool until the
+// block before their activation block is the head, and that invalid ones are
+// rejected upfront.
+func TestScheduledTransactions(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	chain := &scheduledTestChain{statedb: statedb, head: 10}
+
+	config := DefaultTxPoolConfig
+	config.Journal, config.Scheduled = "", ""
+	pool := NewTxPool(config, params.TestChainConfig, chain)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	statedb.AddBalance(from, big.NewInt(1000000000))
+	signed := func(nonce uint64) *types.Transaction {
+		tx := types.NewTransaction(protocol.Normal, protocol.NormalCall, nonce, common.HexToAddress("0x01"), big.NewInt(100), big.NewInt(21000), big.NewInt(1), nil)
+		tx, _ = types.SignTx(tx, types.HomesteadSigner{}, key)
+		return tx
+	}
+
+	held := signed(0)
+	if err := pool.AddScheduled(held, 20); err != nil {
+		t.Fatalf("failed to schedule transaction: %v", err)
+	}
+	if err := pool.AddScheduled(held, 20); err != ErrScheduledKnown {
+		t.Errorf("rescheduling: have %v, want %v", err, ErrScheduledKnown)
+	}
+	if pool.Get(held.Hash()) != nil {
+		t.Errorf("scheduled transaction entered the pool before activation")
+	}
+	//生效区块就是下一个区块时直接加入交易池
+	next := signed(1)
+	if err := pool.AddScheduled(next, 11); err != nil {
+		t.Fatalf("failed to add transaction of the next block: %v", err)
+	}
+	if pool.Get(next.Hash()) == nil {
+		t.Errorf("transaction of the next block not added to the pool")
+	}
+	cancelled := signed(2)
+	if err := pool.AddScheduled(cancelled, 100); err != nil {
+		t.Fatalf("failed to schedule transaction: %v", err)
+	}
+	if !pool.RemoveScheduled(cancelled.Hash()) || pool.RemoveScheduled(cancelled.Hash()) {
+		t.Errorf("cancelling a scheduled transaction did not remove it exactly once")
+	}
+
+	statedb.SetNonce(from, 1)
+	if err := pool.AddScheduled(signed(0), 30); err != ErrScheduledKnown {
+		t.Errorf("stale nonce of known transaction: have %v, want %v", err, ErrScheduledKnown)
+	}
+	other, _ := crypto.GenerateKey()
+	stale, _ := types.SignTx(types.NewTransaction(protocol.Normal, protocol.NormalCall, 0, common.HexToAddress("0x01"), big.NewInt(100), big.NewInt(21000), big.NewInt(1), nil), types.HomesteadSigner{}, other)
+	statedb.SetNonce(crypto.PubkeyToAddress(other.PublicKey), 1)
+	if err := pool.AddScheduled(stale, 30); err != ErrNonceTooLow {
+		t.Errorf("stale nonce: have %v, want %v", err, ErrNonceTooLow)
+	}
+	statedb.SetNonce(from, 0)
+
+	pool.mu.Lock()
+	pool.promoteScheduled(18)
+	pool.mu.Unlock()
+	if len(pool.Scheduled()) != 1 {
+		t.Fatalf("transaction activated early: %d scheduled", len(pool.Scheduled()))
+	}
+	pool.mu.Lock()
+	pool.promoteScheduled(19)
+	pool.mu.Unlock()
+	if len(pool.Scheduled()) != 0 || pool.Get(held.Hash()) == nil {
+		t.Fatalf("transaction not activated at its block")
+	}
+	if pending, _ := pool.Pending(); len(pending[from]) != 2 {
+		t.Errorf("pending transactions mismatch: have %d, want 2", len(pending[from]))
+	}
+}
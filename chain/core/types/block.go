@@ -70,7 +70,7 @@ func (n *BlockNonce) UnmarshalText(input []byte) error {
 // Header represents a block header in the Ethereum blockchain.
 type Header struct {
 	ParentHash  common.Hash        `json:"parentHash"       gencodec:"required"`  //该区块的父区块的Hash值
-	UncleHash   common.Hash        `json:"sha3Uncles"       gencodec:"required"`  //该区块的叔区块的Hash值
+	UncleHash   common.Hash        `json:"sha3Uncles"`                            //该区块的叔区块的Hash值(DPoS区块的RPC输出可以省略)
 	Validator   common.Address     `json:"validator"        gencodec:"required"`  //该区块的验证者
 	Coinbase    common.Address     `json:"coinbase"         gencodec:"required"`  //打包该区块矿工的地址，矿工费和发现区块的奖励会被发送到该地址
 	Root        common.Hash        `json:"stateRoot"        gencodec:"required"`  //Merkle树根节点的Hash，以太坊中的交易状态信息是以Merkle状态树的形式进行存储的，Root是该状态树的根节点的Hash值
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/common"
+)
+
+//股权分红记录
+type StockPayout struct {
+	Account common.Address //股权账号
+	Amount  *big.Int       //分配的数量
+}
+
+//区块的奖励及Gas分配情况（在区块定稿时计算，与回执一起保存）
+type BlockRewards struct {
+	Producer            common.Address //出块节点
+	ProducerReward      *big.Int       //出块奖励
+	GasFees             *big.Int       //区块中交易的Gas费用
+	GasPoolContribution *big.Int       //放入股权Gas池的数量
+	StockPayouts        []StockPayout  //本区块中的股权分红
+}
+
+func newBlockRewards() *BlockRewards {
+	return &BlockRewards{
+		ProducerReward:      new(big.Int),
+		GasFees:             new(big.Int),
+		GasPoolContribution: new(big.Int),
+	}
+}
+
+//拷贝奖励记录
+func (r *BlockRewards) Copy() *BlockRewards {
+
+	cpy := &BlockRewards{
+		Producer:            r.Producer,
+		ProducerReward:      new(big.Int).Set(r.ProducerReward),
+		GasFees:             new(big.Int).Set(r.GasFees),
+		GasPoolContribution: new(big.Int).Set(r.GasPoolContribution),
+	}
+	if len(r.StockPayouts) > 0 {
+		cpy.StockPayouts = make([]StockPayout, len(r.StockPayouts))
+		copy(cpy.StockPayouts, r.StockPayouts)
+	}
+	return cpy
+}
@@ -0,0 +1,35 @@
+package types
+
+import "github.com/Tinachain/Tina/chain/boker/protocol"
+
+//区块的累计统计(从开始统计的区块累计到当前区块，两个区块相减即得到区间内的统计)
+type BlockStats struct {
+	First      uint64 //开始统计的区块
+	Txs        uint64 //累计交易数
+	GasUsed    uint64 //累计使用的Gas
+	GasLimit   uint64 //累计的Gas上限
+	ExtraBytes uint64 //累计扩展交易的数据字节数
+}
+
+//按UTC日期统计的规范链数据
+type DailyStats struct {
+	Blocks          uint64 //区块数
+	Txs             uint64 //交易数
+	ExtraBytes      uint64 //扩展交易的数据字节数
+	ActiveAddresses uint64 //发出或接收交易的不同账号数
+}
+
+//按交易类型的累计统计(从开始统计的区块累计到当前区块)
+type TxTypeStats struct {
+	First uint64         //开始统计的区块
+	Types []*TxTypeUsage //按主要类型、次要类型排序
+}
+
+//一种交易类型的累计用量
+type TxTypeUsage struct {
+	Major   protocol.TxMajor //主要交易类型
+	Minor   protocol.TxMinor //次要交易类型
+	Txs     uint64           //累计交易数
+	GasUsed uint64           //累计使用的Gas
+	Bytes   uint64           //累计交易编码的字节数
+}
@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/ethdb"
 	"github.com/Tinachain/Tina/chain/rlp"
@@ -33,19 +34,20 @@ func TestBlockEncoding(t *testing.T) {
 	dposCtx, _ := NewDposContext(db)
 	inputBlock := Block{
 		header: &Header{
-			Difficulty:  big.NewInt(131072),
-			GasLimit:    big.NewInt(3141592),
-			GasUsed:     big.NewInt(21000),
-			Validator:   common.HexToAddress("8888f1f195afa192cfee860698584c030f4c9db1"),
-			Coinbase:    common.HexToAddress("8888f1f195afa192cfee860698584c030f4c9db1"),
-			MixDigest:   common.HexToHash("bd4472abb6659ebe3ee06ee4d7b72a00a9f4d001caca51342001075469aff498"),
-			Root:        common.HexToHash("ef1552a40b7165c3cd773806b9e0c165b75356e0314bf0706f279c729f51e017"),
-			Nonce:       EncodeNonce(uint64(0xa13a5a8c8f2bb1c4)),
-			Time:        big.NewInt(1426516743),
-			DposContext: dposCtx.ToProto(),
+			Difficulty: big.NewInt(131072),
+			GasLimit:   big.NewInt(3141592),
+			GasUsed:    big.NewInt(21000),
+			Validator:  common.HexToAddress("8888f1f195afa192cfee860698584c030f4c9db1"),
+			Coinbase:   common.HexToAddress("8888f1f195afa192cfee860698584c030f4c9db1"),
+			MixDigest:  common.HexToHash("bd4472abb6659ebe3ee06ee4d7b72a00a9f4d001caca51342001075469aff498"),
+			Root:       common.HexToHash("ef1552a40b7165c3cd773806b9e0c165b75356e0314bf0706f279c729f51e017"),
+			Nonce:      EncodeNonce(uint64(0xa13a5a8c8f2bb1c4)),
+			Time:       big.NewInt(1426516743),
+			DposProto:  dposCtx.ToProto(),
+			BokerProto: &BokerBackendProto{},
 		},
 	}
-	tx1 := NewTransaction(Binary, 0, common.HexToAddress("095e7baea6a6c7c4c2dfeb977efac326af552d87"), big.NewInt(10), big.NewInt(50000), big.NewInt(10), nil)
+	tx1 := NewTransaction(protocol.Normal, protocol.NormalCall, 0, common.HexToAddress("095e7baea6a6c7c4c2dfeb977efac326af552d87"), big.NewInt(10), big.NewInt(50000), big.NewInt(10), nil)
 	tx1, _ = tx1.WithSignature(HomesteadSigner{}, common.Hex2Bytes("9bea4c4daac7c7c52e093e6a4c35dbbcf8856f1af7b059ba20253e70848d094f8a8fae537ce25ed8cb5af9adac3f141af69bd515bd2ba031522df09b97dd72b100"))
 	inputBlock.transactions = []*Transaction{tx1}
 	inputHash := inputBlock.Hash()
@@ -2,6 +2,7 @@ package types
 
 import (
 	"errors"
+	"io"
 	"math/big"
 	"strconv"
 
@@ -21,6 +22,7 @@ type BokerBackendProto struct {
 	StocksHash      common.Hash `json:"stocksRoot"      gencodec:"required"`
 	OwnerHash       common.Hash `json:"ownerRoot"      gencodec:"required"`
 	GasPoolHash     common.Hash `json:"gasPoolRoot"      gencodec:"required"`
+	GovernanceHash  common.Hash `json:"governanceRoot"   gencodec:"required"`
 }
 
 func (p *BokerBackendProto) Root() (h common.Hash) {
@@ -32,11 +34,76 @@ func (p *BokerBackendProto) Root() (h common.Hash) {
 	rlp.Encode(hw, p.StocksHash)
 	rlp.Encode(hw, p.OwnerHash)
 	rlp.Encode(hw, p.GasPoolHash)
+	if p.GovernanceHash != (common.Hash{}) {
+		rlp.Encode(hw, p.GovernanceHash)
+	}
 	hw.Sum(h[:0])
 	return h
 }
 
-func ToBokerProto(singleHash, contractsHash, singleStockHash, stocksHash, ownerHash, gasPoolHash common.Hash) *BokerBackendProto {
+//区块头中Boker上下文的编码，治理树为空（治理分叉之前）时不包含治理树的根，与分叉之前的区块头编码一致
+type bokerProtoRLP struct {
+	SingleHash      common.Hash
+	ContractsHash   common.Hash
+	SingleStockHash common.Hash
+	StocksHash      common.Hash
+	OwnerHash       common.Hash
+	GasPoolHash     common.Hash
+	Governance      []common.Hash `rlp:"tail"`
+}
+
+func (p *BokerBackendProto) EncodeRLP(w io.Writer) error {
+
+	//没有Boker上下文的区块头(例如clique区块)与之前一样编码为空列表
+	if p == nil {
+		return rlp.Encode(w, []interface{}{})
+	}
+	enc := &bokerProtoRLP{
+		SingleHash:      p.SingleHash,
+		ContractsHash:   p.ContractsHash,
+		SingleStockHash: p.SingleStockHash,
+		StocksHash:      p.StocksHash,
+		OwnerHash:       p.OwnerHash,
+		GasPoolHash:     p.GasPoolHash,
+	}
+	if p.GovernanceHash != (common.Hash{}) {
+		enc.Governance = []common.Hash{p.GovernanceHash}
+	}
+	return rlp.Encode(w, enc)
+}
+
+func (p *BokerBackendProto) DecodeRLP(s *rlp.Stream) error {
+
+	var dec bokerProtoRLP
+	if err := s.Decode(&dec); err != nil {
+		return err
+	}
+	if len(dec.Governance) > 1 {
+		return errors.New("invalid boker proto: too many elements")
+	}
+	*p = BokerBackendProto{
+		SingleHash:      dec.SingleHash,
+		ContractsHash:   dec.ContractsHash,
+		SingleStockHash: dec.SingleStockHash,
+		StocksHash:      dec.StocksHash,
+		OwnerHash:       dec.OwnerHash,
+		GasPoolHash:     dec.GasPoolHash,
+	}
+	if len(dec.Governance) == 1 {
+		p.GovernanceHash = dec.Governance[0]
+	}
+	return nil
+}
+
+//治理树为空时使用空哈希，没有任何提案之前区块头中不出现治理树的根
+func governanceRoot(root common.Hash) common.Hash {
+	if root == EmptyRootHash {
+		return common.Hash{}
+	}
+	return root
+}
+
+func ToBokerProto(singleHash, contractsHash, singleStockHash, stocksHash, ownerHash, gasPoolHash, governanceHash common.Hash) *BokerBackendProto {
 
 	return &BokerBackendProto{
 		SingleHash:      singleHash,
@@ -45,6 +112,7 @@ func ToBokerProto(singleHash, contractsHash, singleStockHash, stocksHash, ownerH
 		StocksHash:      stocksHash,
 		OwnerHash:       ownerHash,
 		GasPoolHash:     gasPoolHash,
+		GovernanceHash:  governanceHash,
 	}
 }
 
@@ -55,7 +123,11 @@ type BokerContext struct {
 	stocksTrie          *trie.Trie
 	ownerTrie           *trie.Trie
 	gasPoolTrie         *trie.Trie
+	governanceTrie      *trie.Trie
 	db                  ethdb.Database
+	rewards             *BlockRewards //当前区块的奖励记录（不参与共识）
+
+	contractRecords []*protocol.BaseContractRecord //当前区块中基础合约的变更记录（不参与共识）
 }
 
 func NewSingleContractTrie(root common.Hash, db ethdb.Database) (*trie.Trie, error) {
@@ -82,6 +154,10 @@ func NewGasPoolTrie(root common.Hash, db ethdb.Database) (*trie.Trie, error) {
 	return trie.NewTrieWithPrefix(root, protocol.GasPoolPrefix, db)
 }
 
+func NewGovernanceTrie(root common.Hash, db ethdb.Database) (*trie.Trie, error) {
+	return trie.NewTrieWithPrefix(root, protocol.GovernancePrefix, db)
+}
+
 func NewBokerContext(db ethdb.Database) (*BokerContext, error) {
 
 	//log.Info("Create Tinachain Single Stock Trie")
@@ -125,6 +201,12 @@ func NewBokerContext(db ethdb.Database) (*BokerContext, error) {
 		return nil, err
 	}
 
+	governanceTrie, err := NewGovernanceTrie(common.Hash{}, db)
+	if err != nil {
+		log.Error("Create Tinachain Governance Trie", "err", err)
+		return nil, err
+	}
+
 	return &BokerContext{
 		singleContractsTrie: singleContractsTrie,
 		contractsTrie:       contractsTrie,
@@ -132,6 +214,7 @@ func NewBokerContext(db ethdb.Database) (*BokerContext, error) {
 		stocksTrie:          stocksTrie,
 		ownerTrie:           ownerTrie,
 		gasPoolTrie:         gasPoolTrie,
+		governanceTrie:      governanceTrie,
 		db:                  db,
 	}, nil
 }
@@ -179,6 +262,12 @@ func NewBokerContextFromProto(db ethdb.Database, ctxProto *BokerBackendProto) (*
 		return nil, err
 	}
 
+	governanceTrie, err := NewGovernanceTrie(ctxProto.GovernanceHash, db)
+	if err != nil {
+		log.Error("Create Tinachain Governance Trie", "err", err)
+		return nil, err
+	}
+
 	return &BokerContext{
 		singleContractsTrie: singleContractsTrie,
 		contractsTrie:       contractsTrie,
@@ -186,6 +275,7 @@ func NewBokerContextFromProto(db ethdb.Database, ctxProto *BokerBackendProto) (*
 		stocksTrie:          stocksTrie,
 		ownerTrie:           ownerTrie,
 		gasPoolTrie:         gasPoolTrie,
+		governanceTrie:      governanceTrie,
 		db:                  db,
 	}, nil
 }
@@ -198,6 +288,7 @@ func (s *BokerContext) Copy() *BokerContext {
 	stocksTrie := *s.stocksTrie
 	ownerTrie := *s.ownerTrie
 	gasPoolTrie := *s.gasPoolTrie
+	governanceTrie := *s.governanceTrie
 
 	return &BokerContext{
 		singleContractsTrie: &singleContractsTrie,
@@ -206,6 +297,9 @@ func (s *BokerContext) Copy() *BokerContext {
 		stocksTrie:          &stocksTrie,
 		ownerTrie:           &ownerTrie,
 		gasPoolTrie:         &gasPoolTrie,
+		governanceTrie:      &governanceTrie,
+		rewards:             s.Rewards().Copy(),
+		contractRecords:     append([]*protocol.BaseContractRecord(nil), s.contractRecords...),
 	}
 }
 
@@ -218,6 +312,9 @@ func (s *BokerContext) Root() (h common.Hash) {
 	rlp.Encode(hw, s.stocksTrie.Hash())
 	rlp.Encode(hw, s.ownerTrie.Hash())
 	rlp.Encode(hw, s.gasPoolTrie.Hash())
+	if root := governanceRoot(s.governanceTrie.Hash()); root != (common.Hash{}) {
+		rlp.Encode(hw, root)
+	}
 	hw.Sum(h[:0])
 	return h
 }
@@ -234,6 +331,9 @@ func (s *BokerContext) RevertToSnapShot(snapshot *BokerContext) {
 	s.stocksTrie = snapshot.stocksTrie
 	s.ownerTrie = snapshot.ownerTrie
 	s.gasPoolTrie = snapshot.gasPoolTrie
+	s.governanceTrie = snapshot.governanceTrie
+	s.rewards = snapshot.rewards
+	s.contractRecords = snapshot.contractRecords
 }
 
 func (s *BokerContext) FromProto(dcp *BokerBackendProto) error {
@@ -266,6 +366,11 @@ func (s *BokerContext) FromProto(dcp *BokerBackendProto) error {
 	}
 
 	s.gasPoolTrie, err = NewGasPoolTrie(dcp.GasPoolHash, s.db)
+	if err != nil {
+		return err
+	}
+
+	s.governanceTrie, err = NewGovernanceTrie(dcp.GovernanceHash, s.db)
 	return err
 }
 
@@ -278,6 +383,7 @@ func (s *BokerContext) ToProto() *BokerBackendProto {
 		StocksHash:      s.stocksTrie.Hash(),
 		OwnerHash:       s.ownerTrie.Hash(),
 		GasPoolHash:     s.gasPoolTrie.Hash(),
+		GovernanceHash:  governanceRoot(s.governanceTrie.Hash()),
 	}
 }
 
@@ -313,6 +419,11 @@ func (s *BokerContext) CommitTo(dbw trie.DatabaseWriter) (*BokerBackendProto, er
 		return nil, err
 	}
 
+	governanceHash, err := s.governanceTrie.CommitTo(dbw)
+	if err != nil {
+		return nil, err
+	}
+
 	return &BokerBackendProto{
 		SingleHash:      singleContractsRoot,
 		ContractsHash:   contractsRoot,
@@ -320,6 +431,7 @@ func (s *BokerContext) CommitTo(dbw trie.DatabaseWriter) (*BokerBackendProto, er
 		StocksHash:      stocksRoot,
 		OwnerHash:       ownerRoot,
 		GasPoolHash:     gasPoolRoot,
+		GovernanceHash:  governanceRoot(governanceHash),
 	}, nil
 }
 
@@ -329,12 +441,26 @@ func (s *BokerContext) SingleStockTrie() *trie.Trie               { return s.sin
 func (s *BokerContext) StocksTrie() *trie.Trie                    { return s.stocksTrie }
 func (s *BokerContext) OwnerTrie() *trie.Trie                     { return s.ownerTrie }
 func (s *BokerContext) GasPoolTrie() *trie.Trie                   { return s.gasPoolTrie }
+func (s *BokerContext) GovernanceTrie() *trie.Trie                { return s.governanceTrie }
 func (s *BokerContext) SetContracts(contractsTrie *trie.Trie)     { s.contractsTrie = contractsTrie }
 func (s *BokerContext) SetSingleStock(singleStockTrie *trie.Trie) { s.singleStockTrie = singleStockTrie }
 func (s *BokerContext) SetStocks(stocksTrie *trie.Trie)           { s.stocksTrie = stocksTrie }
 func (s *BokerContext) SetOwner(ownerTrie *trie.Trie)             { s.ownerTrie = ownerTrie }
 func (s *BokerContext) SetGasPool(gasPoolTrie *trie.Trie)         { s.gasPoolTrie = gasPoolTrie }
-func (s *BokerContext) DB() ethdb.Database                        { return s.db }
+
+//得到当前区块的奖励记录
+func (s *BokerContext) Rewards() *BlockRewards {
+	if s.rewards == nil {
+		s.rewards = newBlockRewards()
+	}
+	return s.rewards
+}
+
+//记录一笔股权分红
+func (s *BokerContext) AddStockPayout(account common.Address, amount *big.Int) {
+	rewards := s.Rewards()
+	rewards.StockPayouts = append(rewards.StockPayouts, StockPayout{Account: account, Amount: new(big.Int).Set(amount)})
+}
 func (s *BokerContext) SetSingleContracts(singleContractsTrie *trie.Trie) {
 	s.singleContractsTrie = singleContractsTrie
 }
@@ -1009,3 +1135,31 @@ func (s *BokerContext) CancelUserBaseContract(address common.Address, from commo
 func (s *BokerContext) GetSingleContractsType(address common.Address) (protocol.BaseContractType, error) {
 	return s.getSingleContractsTrie(address)
 }
+
+//得到所有已注册的基础合约地址
+func (s *BokerContext) GetContracts() ([]common.Address, error) {
+	return s.getContractsTrie()
+}
+
+//判断地址是否为已注册的基础合约，并返回合约类型
+func (s *BokerContext) GetBaseContract(address common.Address) (protocol.BaseContractType, bool) {
+
+	if exist, _ := s.existContract(address); !exist {
+		return protocol.System, false
+	}
+	contractType, err := s.getSingleContractsTrie(address)
+	if err != nil {
+		return protocol.System, false
+	}
+	return contractType, true
+}
+
+//得到当前区块中基础合约的变更记录
+func (s *BokerContext) ContractRecords() []*protocol.BaseContractRecord {
+	return s.contractRecords
+}
+
+//添加一条基础合约的变更记录（与回执一起保存在本地数据库中，不写入合约树）
+func (s *BokerContext) AddContractRecord(record *protocol.BaseContractRecord) {
+	s.contractRecords = append(s.contractRecords, record)
+}
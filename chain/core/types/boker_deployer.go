@@ -0,0 +1,59 @@
+package types
+
+import (
+	"bytes"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/trie"
+)
+
+func deployerKey(address common.Address) []byte {
+	return append(common.CopyBytes(protocol.DeployerPrefix), address.Bytes()...)
+}
+
+//判断账号是否在部署合约的白名单中
+func (s *BokerContext) IsDeployer(address common.Address) bool {
+
+	if s.contractsTrie == nil {
+		return false
+	}
+	value, err := s.contractsTrie.TryGet(deployerKey(address))
+	return err == nil && len(value) > 0
+}
+
+//将账号加入或者移出部署合约的白名单
+func (s *BokerContext) SetDeployer(address common.Address, approved bool) error {
+
+	log.Info("(s *BokerContext) SetDeployer", "address", address.String(), "approved", approved)
+
+	if s.contractsTrie == nil {
+		return protocol.ErrPointerIsNil
+	}
+	if approved {
+		return s.contractsTrie.TryUpdate(deployerKey(address), []byte{1})
+	}
+	return s.contractsTrie.TryDelete(deployerKey(address))
+}
+
+//得到部署合约白名单中的所有账号
+func (s *BokerContext) GetDeployers() []common.Address {
+
+	var deployers []common.Address
+	if s.contractsTrie == nil {
+		return deployers
+	}
+	//迭代器返回的键包含合约树自身的前缀
+	prefix := append(common.CopyBytes(protocol.ContractsPrefix), protocol.DeployerPrefix...)
+	it := trie.NewIterator(s.contractsTrie.PrefixIterator(protocol.DeployerPrefix))
+	for it.Next() {
+		if !bytes.HasPrefix(it.Key, prefix) {
+			break
+		}
+		if key := it.Key[len(prefix):]; len(key) == common.AddressLength {
+			deployers = append(deployers, common.BytesToAddress(key))
+		}
+	}
+	return deployers
+}
@@ -0,0 +1,85 @@
+package types
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+	"github.com/Tinachain/Tina/chain/trie"
+)
+
+func gasPoolFundKey(account common.Address) []byte {
+	return append(common.CopyBytes(protocol.GasPoolFundPrefix), account.Bytes()...)
+}
+
+//得到账号累计注入股权Gas池的数量
+func (s *BokerContext) GasPoolContribution(account common.Address) *big.Int {
+
+	amount := new(big.Int)
+	if s.contractsTrie == nil {
+		return amount
+	}
+	value, err := s.contractsTrie.TryGet(gasPoolFundKey(account))
+	if err != nil || len(value) == 0 {
+		return amount
+	}
+	if err := rlp.DecodeBytes(value, amount); err != nil {
+		return new(big.Int)
+	}
+	return amount
+}
+
+//记录账号注入股权Gas池的数量并放入股权Gas池，返回注入后股权Gas池中的数量
+func (s *BokerContext) AddGasPoolContribution(account common.Address, amount *big.Int) (uint64, error) {
+
+	log.Info("(s *BokerContext) AddGasPoolContribution", "account", account.String(), "amount", amount)
+
+	if s.contractsTrie == nil {
+		return 0, protocol.ErrPointerIsNil
+	}
+	if amount.Sign() <= 0 || !amount.IsUint64() || s.GetGasPool()+amount.Uint64() < s.GetGasPool() {
+		return 0, protocol.ErrInvalidGasPoolFunding
+	}
+	value, err := rlp.EncodeToBytes(new(big.Int).Add(s.GasPoolContribution(account), amount))
+	if err != nil {
+		return 0, err
+	}
+	if err := s.contractsTrie.TryUpdate(gasPoolFundKey(account), value); err != nil {
+		return 0, err
+	}
+	return s.AddGasPool(amount.Uint64()), nil
+}
+
+//得到所有注入过股权Gas池的账号(按地址排序)以及各自累计注入的数量
+func (s *BokerContext) GasPoolContributions() ([]common.Address, []*big.Int) {
+
+	var (
+		accounts []common.Address
+		amounts  []*big.Int
+	)
+	if s.contractsTrie == nil {
+		return accounts, amounts
+	}
+	//迭代器返回的键包含合约树自身的前缀
+	prefix := append(common.CopyBytes(protocol.ContractsPrefix), protocol.GasPoolFundPrefix...)
+	it := trie.NewIterator(s.contractsTrie.PrefixIterator(protocol.GasPoolFundPrefix))
+	for it.Next() {
+		if !bytes.HasPrefix(it.Key, prefix) {
+			break
+		}
+		key := it.Key[len(prefix):]
+		if len(key) != common.AddressLength {
+			continue
+		}
+		amount := new(big.Int)
+		if err := rlp.DecodeBytes(it.Value, amount); err != nil {
+			continue
+		}
+		accounts = append(accounts, common.BytesToAddress(key))
+		amounts = append(amounts, amount)
+	}
+	return accounts, amounts
+}
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/ethdb"
+)
+
+func TestGasPoolContributions(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	bokerContext, err := NewBokerContext(db)
+	if err != nil {
+		t.Fatalf("failed to create boker context: %v", err)
+	}
+	alice, bob := common.HexToAddress("0xa1"), common.HexToAddress("0xb0")
+
+	bokerContext.AddGasPool(100)
+	for _, funding := range []struct {
+		account common.Address
+		amount  int64
+	}{{bob, 50}, {alice, 20}, {bob, 30}} {
+		if _, err := bokerContext.AddGasPoolContribution(funding.account, big.NewInt(funding.amount)); err != nil {
+			t.Fatalf("failed to contribute: %v", err)
+		}
+	}
+	if pool := bokerContext.GetGasPool(); pool != 200 {
+		t.Errorf("gas pool mismatch: have %d, want 200", pool)
+	}
+	accounts, amounts := bokerContext.GasPoolContributions()
+	if len(accounts) != 2 || accounts[0] != alice || amounts[0].Int64() != 20 || accounts[1] != bob || amounts[1].Int64() != 80 {
+		t.Errorf("contributions mismatch: have %v %v", accounts, amounts)
+	}
+	if amount := bokerContext.GasPoolContribution(bob); amount.Int64() != 80 {
+		t.Errorf("bob contribution mismatch: have %v, want 80", amount)
+	}
+
+	//数量必须大于0并且不能使Gas池溢出
+	for _, amount := range []*big.Int{new(big.Int), new(big.Int).SetUint64(math.MaxUint64)} {
+		if _, err := bokerContext.AddGasPoolContribution(alice, amount); err != protocol.ErrInvalidGasPoolFunding {
+			t.Errorf("amount %v: have %v, want %v", amount, err, protocol.ErrInvalidGasPoolFunding)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package types
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+func proposalKey(id uint64) []byte {
+	key := make([]byte, len(protocol.ProposalKeyPrefix)+8)
+	copy(key, protocol.ProposalKeyPrefix)
+	binary.BigEndian.PutUint64(key[len(protocol.ProposalKeyPrefix):], id)
+	return key
+}
+
+func proposalVoteKey(id uint64, voter common.Address) []byte {
+	key := make([]byte, len(protocol.ProposalVotePrefix)+8+common.AddressLength)
+	copy(key, protocol.ProposalVotePrefix)
+	binary.BigEndian.PutUint64(key[len(protocol.ProposalVotePrefix):], id)
+	copy(key[len(protocol.ProposalVotePrefix)+8:], voter.Bytes())
+	return key
+}
+
+func proposalWeightKey(id uint64, account common.Address) []byte {
+	key := make([]byte, len(protocol.ProposalWeightPrefix)+8+common.AddressLength)
+	copy(key, protocol.ProposalWeightPrefix)
+	binary.BigEndian.PutUint64(key[len(protocol.ProposalWeightPrefix):], id)
+	copy(key[len(protocol.ProposalWeightPrefix)+8:], account.Bytes())
+	return key
+}
+
+//得到已产生的提案数量
+func (s *BokerContext) GetProposalCount() uint64 {
+
+	countRLP, err := s.governanceTrie.TryGet(protocol.ProposalCountKey)
+	if err != nil || len(countRLP) == 0 {
+		return 0
+	}
+	var count uint64
+	if err := rlp.DecodeBytes(countRLP, &count); err != nil {
+		log.Error("failed to decode proposal count", "error", err)
+		return 0
+	}
+	return count
+}
+
+func (s *BokerContext) setProposal(proposal *protocol.Proposal) error {
+
+	proposalRLP, err := rlp.EncodeToBytes(proposal)
+	if err != nil {
+		return err
+	}
+	return s.governanceTrie.TryUpdate(proposalKey(proposal.Id), proposalRLP)
+}
+
+//添加一个新提案，返回提案编号（从1开始）；同时快照所有股权账号当前的投票权重，之后的股权变更不影响该提案的计票
+func (s *BokerContext) AddProposal(proposal *protocol.Proposal) (uint64, error) {
+
+	id := s.GetProposalCount() + 1
+	proposal.Id = id
+	proposal.Yes, proposal.No, proposal.Total = new(big.Int), new(big.Int), new(big.Int)
+	proposal.State = protocol.ProposalVoting
+
+	for _, stock := range s.GetStocks() {
+		weight := s.StockWeight(stock.Account)
+		if weight.Sign() <= 0 {
+			continue
+		}
+		weightRLP, err := rlp.EncodeToBytes(weight)
+		if err != nil {
+			return 0, err
+		}
+		if err := s.governanceTrie.TryUpdate(proposalWeightKey(id, stock.Account), weightRLP); err != nil {
+			return 0, err
+		}
+		proposal.Total.Add(proposal.Total, weight)
+	}
+	if err := s.setProposal(proposal); err != nil {
+		return 0, err
+	}
+
+	countRLP, err := rlp.EncodeToBytes(id)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.governanceTrie.TryUpdate(protocol.ProposalCountKey, countRLP); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+//根据编号得到提案
+func (s *BokerContext) GetProposal(id uint64) (*protocol.Proposal, error) {
+
+	proposalRLP, err := s.governanceTrie.TryGet(proposalKey(id))
+	if err != nil {
+		return nil, err
+	}
+	if len(proposalRLP) == 0 {
+		return nil, protocol.ErrUnknownProposal
+	}
+	proposal := new(protocol.Proposal)
+	if err := rlp.DecodeBytes(proposalRLP, proposal); err != nil {
+		log.Error("failed to decode proposal", "id", id, "error", err)
+		return nil, err
+	}
+	return proposal, nil
+}
+
+//得到所有提案
+func (s *BokerContext) GetProposals() []*protocol.Proposal {
+
+	count := s.GetProposalCount()
+	proposals := make([]*protocol.Proposal, 0, count)
+	for id := uint64(1); id <= count; id++ {
+		if proposal, err := s.GetProposal(id); err == nil {
+			proposals = append(proposals, proposal)
+		}
+	}
+	return proposals
+}
+
+//判断账号是否已经对提案投票
+func (s *BokerContext) HasVoted(id uint64, voter common.Address) bool {
+
+	v, err := s.governanceTrie.TryGet(proposalVoteKey(id, voter))
+	return err == nil && len(v) > 0
+}
+
+//得到账号在提案产生时快照的投票权重
+func (s *BokerContext) ProposalWeight(id uint64, account common.Address) *big.Int {
+
+	weightRLP, err := s.governanceTrie.TryGet(proposalWeightKey(id, account))
+	if err != nil || len(weightRLP) == 0 {
+		return new(big.Int)
+	}
+	weight := new(big.Int)
+	if err := rlp.DecodeBytes(weightRLP, weight); err != nil {
+		log.Error("failed to decode proposal weight", "id", id, "error", err)
+		return new(big.Int)
+	}
+	return weight
+}
+
+//使用提案产生时快照的权重对提案进行投票（每个账号只能投票一次）
+func (s *BokerContext) VoteProposal(id uint64, voter common.Address, approve bool, epoch uint64) error {
+
+	proposal, err := s.GetProposal(id)
+	if err != nil {
+		return err
+	}
+	if proposal.State != protocol.ProposalVoting || epoch >= proposal.EndEpoch {
+		return protocol.ErrProposalClosed
+	}
+	if s.HasVoted(id, voter) {
+		return protocol.ErrAlreadyVoted
+	}
+	weight := s.ProposalWeight(id, voter)
+	if weight.Sign() <= 0 {
+		return protocol.ErrNoVotingWeight
+	}
+
+	if approve {
+		proposal.Yes.Add(proposal.Yes, weight)
+	} else {
+		proposal.No.Add(proposal.No, weight)
+	}
+	voteRLP, err := rlp.EncodeToBytes(weight)
+	if err != nil {
+		return err
+	}
+	if err := s.governanceTrie.TryUpdate(proposalVoteKey(id, voter), voteRLP); err != nil {
+		return err
+	}
+	return s.setProposal(proposal)
+}
+
+//设置提案状态
+func (s *BokerContext) SetProposalState(id uint64, state protocol.ProposalState) error {
+
+	proposal, err := s.GetProposal(id)
+	if err != nil {
+		return err
+	}
+	proposal.State = state
+	return s.setProposal(proposal)
+}
+
+//得到账号按股权计算的投票权重（冻结的股权没有投票权）
+func (s *BokerContext) StockWeight(account common.Address) *big.Int {
+
+	stock := s.GetStock(account)
+	if stock == nil || stock.State == protocol.Frozen {
+		return new(big.Int)
+	}
+	return new(big.Int).SetUint64(stock.Number)
+}
@@ -0,0 +1,96 @@
+package types
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+// Tests that the boker proto keeps its pre-governance encoding until the
+// governance trie holds a proposal.
+func TestBokerProtoGovernanceEncoding(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	bokerContext, err := NewBokerContext(db)
+	if err != nil {
+		t.Fatalf("failed to create boker context: %v", err)
+	}
+	proto := bokerContext.ToProto()
+	if proto.GovernanceHash != (common.Hash{}) {
+		t.Fatalf("empty governance trie in proto: %x", proto.GovernanceHash)
+	}
+	legacy, _ := rlp.EncodeToBytes([]common.Hash{proto.SingleHash, proto.ContractsHash, proto.SingleStockHash, proto.StocksHash, proto.OwnerHash, proto.GasPoolHash})
+	if enc, _ := rlp.EncodeToBytes(proto); !bytes.Equal(enc, legacy) {
+		t.Fatalf("encoding mismatch: have %x, want %x", enc, legacy)
+	}
+	if proto.Root() != bokerContext.Root() {
+		t.Fatalf("root mismatch: proto %x, context %x", proto.Root(), bokerContext.Root())
+	}
+	if enc, err := rlp.EncodeToBytes((*BokerBackendProto)(nil)); err != nil || !bytes.Equal(enc, []byte{0xc0}) {
+		t.Fatalf("nil proto encoding mismatch: have %x, %v, want c0", enc, err)
+	}
+
+	if _, err := bokerContext.AddProposal(&protocol.Proposal{Kind: protocol.ParamChange}); err != nil {
+		t.Fatalf("failed to add proposal: %v", err)
+	}
+	proto = bokerContext.ToProto()
+	if proto.GovernanceHash == (common.Hash{}) {
+		t.Fatalf("governance root missing after proposal")
+	}
+	enc, _ := rlp.EncodeToBytes(proto)
+	dec := new(BokerBackendProto)
+	if err := rlp.DecodeBytes(enc, dec); err != nil {
+		t.Fatalf("failed to decode proto: %v", err)
+	}
+	if *dec != *proto {
+		t.Fatalf("proto mismatch: have %+v, want %+v", dec, proto)
+	}
+	if proto.Root() != bokerContext.Root() {
+		t.Fatalf("root mismatch: proto %x, context %x", proto.Root(), bokerContext.Root())
+	}
+}
+
+// Tests that votes are weighted by the stock snapshotted at proposal creation.
+func TestProposalWeightSnapshot(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	bokerContext, err := NewBokerContext(db)
+	if err != nil {
+		t.Fatalf("failed to create boker context: %v", err)
+	}
+	manager := common.HexToAddress("0x0a")
+	alice, bob, carol := common.HexToAddress("0xa1"), common.HexToAddress("0xb0"), common.HexToAddress("0xc0")
+	bokerContext.setOwnerTrie(manager)
+	bokerContext.SetStock(manager, alice, 60)
+	bokerContext.SetStock(manager, bob, 40)
+
+	id, err := bokerContext.AddProposal(&protocol.Proposal{Kind: protocol.ParamChange, EndEpoch: 2})
+	if err != nil {
+		t.Fatalf("failed to add proposal: %v", err)
+	}
+	//提案产生之后的股权变更不影响计票
+	bokerContext.CleanStock(manager, alice)
+	bokerContext.SetStock(manager, carol, 60)
+
+	if err := bokerContext.VoteProposal(id, alice, true, 1); err != nil {
+		t.Fatalf("failed to vote: %v", err)
+	}
+	if err := bokerContext.VoteProposal(id, carol, true, 1); err != protocol.ErrNoVotingWeight {
+		t.Errorf("vote without snapshot weight: have %v, want %v", err, protocol.ErrNoVotingWeight)
+	}
+	if err := bokerContext.VoteProposal(id, alice, false, 1); err != protocol.ErrAlreadyVoted {
+		t.Errorf("second vote: have %v, want %v", err, protocol.ErrAlreadyVoted)
+	}
+	if err := bokerContext.VoteProposal(id, bob, false, 2); err != protocol.ErrProposalClosed {
+		t.Errorf("vote after end: have %v, want %v", err, protocol.ErrProposalClosed)
+	}
+	proposal, err := bokerContext.GetProposal(id)
+	if err != nil {
+		t.Fatalf("failed to get proposal: %v", err)
+	}
+	if proposal.Yes.Int64() != 60 || proposal.No.Sign() != 0 || proposal.Total.Int64() != 100 {
+		t.Errorf("tally mismatch: yes %v, no %v, total %v", proposal.Yes, proposal.No, proposal.Total)
+	}
+}
@@ -0,0 +1,151 @@
+package types
+
+import (
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+//得到待生效的系统基础合约变更
+func (s *BokerContext) GetPendingContract() (*protocol.PendingContract, error) {
+
+	if s.contractsTrie == nil {
+		return nil, protocol.ErrPointerIsNil
+	}
+	pendingRLP, err := s.contractsTrie.TryGet(protocol.PendingContractKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(pendingRLP) == 0 {
+		return nil, protocol.ErrNoPendingContract
+	}
+	pending := new(protocol.PendingContract)
+	if err := rlp.DecodeBytes(pendingRLP, pending); err != nil {
+		log.Error("failed to decode pending contract", "error", err)
+		return nil, err
+	}
+	return pending, nil
+}
+
+func (s *BokerContext) setPendingContract(pending *protocol.PendingContract) error {
+
+	pendingRLP, err := rlp.EncodeToBytes(pending)
+	if err != nil {
+		return err
+	}
+	return s.contractsTrie.TryUpdate(protocol.PendingContractKey, pendingRLP)
+}
+
+//判断账号是否有权变更系统基础合约（股权管理者或者当前周期的验证者）
+func (s *BokerContext) IsContractAuthority(account common.Address, validators []common.Address) bool {
+
+	if manager := s.GetStockManager(); manager != (common.Address{}) && manager == account {
+		return true
+	}
+	for _, v := range validators {
+		if v == account {
+			return true
+		}
+	}
+	return false
+}
+
+//提出系统基础合约变更，需要等待SystemContractDelay个区块后才能确认生效（股权管理者可以替换已存在的变更）
+//还没有系统基础合约时没有需要保护的合约，变更可以立即确认
+func (s *BokerContext) ProposeSystemContract(address common.Address, proposer common.Address, number uint64) error {
+
+	log.Info("(s *BokerContext) ProposeSystemContract", "address", address.String(), "proposer", proposer.String(), "number", number)
+
+	effective := number
+	if current, err := s.GetSystemContractAddress(); err == nil {
+		if current == address {
+			return protocol.ErrContractExist
+		}
+		effective = number + protocol.SystemContractDelay
+	}
+	if _, err := s.GetPendingContract(); err == nil && proposer != s.GetStockManager() {
+		return protocol.ErrPendingContractExist
+	}
+	return s.setPendingContract(&protocol.PendingContract{
+		Address:        address,
+		Proposer:       proposer,
+		ProposedBlock:  number,
+		EffectiveBlock: effective,
+		Approvals:      []common.Address{proposer},
+	})
+}
+
+//确认待生效的系统基础合约变更，股权管理者确认或者超过2/3的验证者确认后返回ready，同时清除待生效的变更
+func (s *BokerContext) ConfirmSystemContract(address common.Address, from common.Address, number uint64, validators []common.Address) (bool, error) {
+
+	log.Info("(s *BokerContext) ConfirmSystemContract", "address", address.String(), "from", from.String(), "number", number)
+
+	pending, err := s.GetPendingContract()
+	if err != nil {
+		return false, err
+	}
+	if pending.Address != address {
+		return false, protocol.ErrPendingContractMismatch
+	}
+	if number < pending.EffectiveBlock {
+		return false, protocol.ErrPendingContractLocked
+	}
+
+	approved := false
+	for _, v := range pending.Approvals {
+		if v == from {
+			approved = true
+			break
+		}
+	}
+	if !approved {
+		pending.Approvals = append(pending.Approvals, from)
+	}
+
+	if from == s.GetStockManager() || isSupermajority(pending.Approvals, validators) {
+		return true, s.contractsTrie.TryDelete(protocol.PendingContractKey)
+	}
+	return false, s.setPendingContract(pending)
+}
+
+//判断确认账号中的验证者是否超过验证者总数的2/3
+func isSupermajority(approvals []common.Address, validators []common.Address) bool {
+
+	if len(validators) == 0 {
+		return false
+	}
+	count := 0
+	for _, v := range validators {
+		for _, a := range approvals {
+			if a == v {
+				count++
+				break
+			}
+		}
+	}
+	return count*3 > len(validators)*2
+}
+
+//移除当前的系统基础合约
+func (s *BokerContext) RemoveSystemContract() error {
+
+	current, err := s.GetSystemContractAddress()
+	if err != nil {
+		return nil
+	}
+	contracts, err := s.getContractsTrie()
+	if err != nil {
+		return err
+	}
+	if err := s.deleteSingleContractsTrie(current); err != nil {
+		return err
+	}
+	for i, v := range contracts {
+		if v == current {
+			contracts = append(contracts[:i], contracts[i+1:]...)
+			break
+		}
+	}
+	return s.setContractsTrie(contracts)
+}
@@ -0,0 +1,49 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/ethdb"
+)
+
+// Tests that the first system contract can be confirmed right away, while
+// replacing an existing one waits for the confirmation delay.
+func TestProposeSystemContractDelay(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	bokerContext, err := NewBokerContext(db)
+	if err != nil {
+		t.Fatalf("failed to create boker context: %v", err)
+	}
+	manager := common.HexToAddress("0x0a")
+	first, second := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	bokerContext.setOwnerTrie(manager)
+
+	//还没有系统基础合约
+	if err := bokerContext.ProposeSystemContract(first, manager, 100); err != nil {
+		t.Fatalf("failed to propose first contract: %v", err)
+	}
+	ready, err := bokerContext.ConfirmSystemContract(first, manager, 100, nil)
+	if err != nil || !ready {
+		t.Fatalf("first contract confirmation: have ready %v, err %v, want ready", ready, err)
+	}
+	if err := bokerContext.setSingleContractsTrie(first, protocol.System); err != nil {
+		t.Fatalf("failed to set first contract type: %v", err)
+	}
+	if err := bokerContext.setContractsTrie([]common.Address{first}); err != nil {
+		t.Fatalf("failed to set first contract: %v", err)
+	}
+
+	//替换已存在的系统基础合约
+	if err := bokerContext.ProposeSystemContract(second, manager, 200); err != nil {
+		t.Fatalf("failed to propose replacement: %v", err)
+	}
+	if _, err := bokerContext.ConfirmSystemContract(second, manager, 200, nil); err != protocol.ErrPendingContractLocked {
+		t.Fatalf("early confirmation: have %v, want %v", err, protocol.ErrPendingContractLocked)
+	}
+	ready, err = bokerContext.ConfirmSystemContract(second, manager, 200+protocol.SystemContractDelay, nil)
+	if err != nil || !ready {
+		t.Fatalf("delayed confirmation: have ready %v, err %v, want ready", ready, err)
+	}
+}
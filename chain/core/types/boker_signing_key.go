@@ -0,0 +1,41 @@
+package types
+
+import (
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/log"
+)
+
+func signingKeyKey(validator common.Address) []byte {
+	return append(common.CopyBytes(protocol.SigningKeyPrefix), validator.Bytes()...)
+}
+
+//得到验证者当前的出块签名账号，没有设置时使用验证者自身的账号
+func (s *BokerContext) SigningKey(validator common.Address) common.Address {
+
+	if s.contractsTrie == nil {
+		return validator
+	}
+	value, err := s.contractsTrie.TryGet(signingKeyKey(validator))
+	if err != nil || len(value) != common.AddressLength {
+		return validator
+	}
+	return common.BytesToAddress(value)
+}
+
+//设置验证者的出块签名账号，签名账号与验证者相同时删除设置
+func (s *BokerContext) SetSigningKey(validator common.Address, key common.Address) error {
+
+	log.Info("(s *BokerContext) SetSigningKey", "validator", validator.String(), "key", key.String())
+
+	if s.contractsTrie == nil {
+		return protocol.ErrPointerIsNil
+	}
+	if (key == common.Address{}) {
+		return protocol.ErrToIsNil
+	}
+	if key == validator {
+		return s.contractsTrie.TryDelete(signingKeyKey(validator))
+	}
+	return s.contractsTrie.TryUpdate(signingKeyKey(validator), key.Bytes())
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+func validatorInfoKey(validator common.Address) []byte {
+	return append(common.CopyBytes(protocol.ValidatorInfoPrefix), validator.Bytes()...)
+}
+
+//校验验证者运营信息中每个字段的长度
+func CheckValidatorInfo(info *protocol.ValidatorInfo) error {
+
+	for _, field := range []string{info.Name, info.Website, info.Contact} {
+		if len(field) > protocol.MaxValidatorInfoLength {
+			return protocol.ErrInvalidValidatorInfo
+		}
+	}
+	return nil
+}
+
+//得到验证者登记的运营信息，没有登记时返回nil
+func (s *BokerContext) ValidatorInfo(validator common.Address) (*protocol.ValidatorInfo, error) {
+
+	if s.contractsTrie == nil {
+		return nil, protocol.ErrPointerIsNil
+	}
+	value, err := s.contractsTrie.TryGet(validatorInfoKey(validator))
+	if err != nil || len(value) == 0 {
+		return nil, err
+	}
+	info := new(protocol.ValidatorInfo)
+	if err := rlp.DecodeBytes(value, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+//登记或更新验证者的运营信息，信息全部为空时删除登记
+func (s *BokerContext) SetValidatorInfo(validator common.Address, info *protocol.ValidatorInfo) error {
+
+	log.Info("(s *BokerContext) SetValidatorInfo", "validator", validator.String(), "name", info.Name)
+
+	if s.contractsTrie == nil {
+		return protocol.ErrPointerIsNil
+	}
+	if err := CheckValidatorInfo(info); err != nil {
+		return err
+	}
+	if (*info == protocol.ValidatorInfo{}) {
+		return s.contractsTrie.TryDelete(validatorInfoKey(validator))
+	}
+	value, err := rlp.EncodeToBytes(info)
+	if err != nil {
+		return err
+	}
+	return s.contractsTrie.TryUpdate(validatorInfoKey(validator), value)
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/ethdb"
+)
+
+func TestValidatorInfo(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	bokerContext, err := NewBokerContext(db)
+	if err != nil {
+		t.Fatalf("failed to create boker context: %v", err)
+	}
+	validator := common.HexToAddress("0x01")
+
+	if info, err := bokerContext.ValidatorInfo(validator); info != nil || err != nil {
+		t.Fatalf("unregistered validator: have %v (%v), want nil", info, err)
+	}
+	want := protocol.ValidatorInfo{Name: "tina-node", Website: "https://tinachain.io", Contact: "ops@tinachain.io"}
+	if err := bokerContext.SetValidatorInfo(validator, &want); err != nil {
+		t.Fatalf("failed to set info: %v", err)
+	}
+	if info, err := bokerContext.ValidatorInfo(validator); err != nil || info == nil || *info != want {
+		t.Fatalf("info mismatch: have %v (%v), want %v", info, err, want)
+	}
+
+	long := protocol.ValidatorInfo{Name: strings.Repeat("x", protocol.MaxValidatorInfoLength+1)}
+	if err := bokerContext.SetValidatorInfo(validator, &long); err != protocol.ErrInvalidValidatorInfo {
+		t.Fatalf("oversized field: have %v, want %v", err, protocol.ErrInvalidValidatorInfo)
+	}
+
+	//信息全部为空时删除登记
+	if err := bokerContext.SetValidatorInfo(validator, &protocol.ValidatorInfo{}); err != nil {
+		t.Fatalf("failed to clear info: %v", err)
+	}
+	if info, err := bokerContext.ValidatorInfo(validator); info != nil || err != nil {
+		t.Fatalf("cleared validator: have %v (%v), want nil", info, err)
+	}
+}
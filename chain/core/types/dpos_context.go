@@ -312,14 +312,26 @@ func (dc *DposContext) GetValidatorCnt(validator common.Address) (*big.Int, erro
 }
 
 func (dc *DposContext) SetValidatorVotes(validators []common.Address, votes []*big.Int) error {
+	return dc.setValidatorVotes(validators, votes, func(votes *big.Int) []byte {
+		return []byte(strconv.Itoa(int(votes.Int64())))
+	})
+}
+
+//写入按投票权重选出的验证者，权重可能超过int64的范围，按完整的十进制字符串保存（只在投票权重分叉之后使用）
+func (dc *DposContext) SetWeightedValidatorVotes(validators []common.Address, votes []*big.Int) error {
+	return dc.setValidatorVotes(validators, votes, func(votes *big.Int) []byte {
+		return []byte(votes.String())
+	})
+}
+
+func (dc *DposContext) setValidatorVotes(validators []common.Address, votes []*big.Int, encode func(*big.Int) []byte) error {
 
 	//清空验证人
 	dc.Clean()
 
 	//重建验证人
 	for index, validator := range validators {
-		cnt := votes[index].Int64()
-		if err := dc.validatorTrie.TryUpdate(validator.Bytes(), []byte(strconv.Itoa(int(cnt)))); err != nil {
+		if err := dc.validatorTrie.TryUpdate(validator.Bytes(), encode(votes[index])); err != nil {
 			return fmt.Errorf("failed to TryUpdate validator: %s", err)
 		}
 	}
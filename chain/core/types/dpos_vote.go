@@ -0,0 +1,108 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/rlp"
+	"github.com/Tinachain/Tina/chain/trie"
+)
+
+func voterKey(voter common.Address) []byte {
+	return append(common.CopyBytes(protocol.VoterPrefix), voter.Bytes()...)
+}
+
+//得到账号的投票记录(没有投票时为nil)
+func (dc *DposContext) GetVoteRecord(voter common.Address) (*protocol.VoteRecord, error) {
+
+	recordRLP, err := dc.voteTrie.TryGet(voterKey(voter))
+	if err != nil || len(recordRLP) == 0 {
+		return nil, err
+	}
+	record := new(protocol.VoteRecord)
+	if err := rlp.DecodeBytes(recordRLP, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+//记录账号的一次投票：再次投票给同一个候选者时累加数量，投票给其它候选者时替换原有记录，
+//两种情况都会将投票周期更新为当前周期(重新计算衰减以及失效)
+func (dc *DposContext) AddVoteRecord(voter, candidate common.Address, amount *big.Int, epoch uint64) error {
+
+	record, err := dc.GetVoteRecord(voter)
+	if err != nil {
+		return err
+	}
+	if record == nil || record.Candidate != candidate {
+		record = &protocol.VoteRecord{Candidate: candidate, Amount: new(big.Int)}
+	}
+	record.Amount = new(big.Int).Add(record.Amount, amount)
+	record.Epoch = epoch
+
+	recordRLP, err := rlp.EncodeToBytes(record)
+	if err != nil {
+		return err
+	}
+	return dc.voteTrie.TryUpdate(voterKey(voter), recordRLP)
+}
+
+//得到全部的投票记录(按账号地址排序)
+func (dc *DposContext) VoteRecords() ([]common.Address, []*protocol.VoteRecord, error) {
+
+	var (
+		voters  []common.Address
+		records []*protocol.VoteRecord
+	)
+	//迭代器返回的键包含投票树自身的前缀
+	prefix := append(common.CopyBytes(protocol.VotePrefix), protocol.VoterPrefix...)
+	it := trie.NewIterator(dc.voteTrie.PrefixIterator(protocol.VoterPrefix))
+	for it.Next() {
+		if !bytes.HasPrefix(it.Key, prefix) {
+			break
+		}
+		key := it.Key[len(prefix):]
+		if len(key) != common.AddressLength {
+			continue
+		}
+		record := new(protocol.VoteRecord)
+		if err := rlp.DecodeBytes(it.Value, record); err != nil {
+			return nil, nil, err
+		}
+		voters = append(voters, common.BytesToAddress(key))
+		records = append(records, record)
+	}
+	return voters, records, it.Err
+}
+
+//得到验证者在选举中的得票数(SetValidatorVotes以及SetWeightedValidatorVotes写入十进制字符串, InsertValidator写入RLP编码的十进制字符串)
+func (dc *DposContext) GetValidatorVotes(validator common.Address) *big.Int {
+
+	value := dc.validatorTrie.Get(validator.Bytes())
+	votes, ok := new(big.Int).SetString(string(value), 10)
+	if ok {
+		return votes
+	}
+	var decoded string
+	if err := rlp.DecodeBytes(value, &decoded); err == nil {
+		if votes, ok := new(big.Int).SetString(decoded, 10); ok {
+			return votes
+		}
+	}
+	return new(big.Int)
+}
+
+//得到验证者在指定周期内已经产生的区块数量(由出块时的updateMintCnt写入)
+func (dc *DposContext) GetMintCnt(epoch int64, validator common.Address) uint64 {
+
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(epoch))
+	value := dc.voteTrie.Get(append(key, validator.Bytes()...))
+	if len(value) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(value)
+}
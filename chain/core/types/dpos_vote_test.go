@@ -0,0 +1,68 @@
+package types
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/ethdb"
+)
+
+func TestValidatorVotesAndMintCnt(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	dposContext, err := NewDposContext(db)
+	if err != nil {
+		t.Fatalf("failed to create dpos context: %v", err)
+	}
+	genesis, elected := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+
+	//创世验证者由InsertValidator写入RLP编码的得票数，选举的验证者由SetValidatorVotes写入十进制字符串
+	dposContext.SetEpochTrie([]common.Address{})
+	if err := dposContext.InsertValidator(genesis, big.NewInt(700)); err != nil {
+		t.Fatalf("failed to insert validator: %v", err)
+	}
+	dposContext.ValidatorTrie().Update(elected.Bytes(), []byte("1234"))
+	for validator, want := range map[common.Address]int64{genesis: 700, elected: 1234, common.HexToAddress("0x03"): 0} {
+		if votes := dposContext.GetValidatorVotes(validator); votes.Int64() != want {
+			t.Errorf("validator %x: votes mismatch: have %v, want %d", validator, votes, want)
+		}
+	}
+
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, 5)
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, 3)
+	dposContext.VoteTrie().Update(append(key, genesis.Bytes()...), value)
+	if cnt := dposContext.GetMintCnt(5, genesis); cnt != 3 {
+		t.Errorf("mint count mismatch: have %d, want 3", cnt)
+	}
+	if cnt := dposContext.GetMintCnt(6, genesis); cnt != 0 {
+		t.Errorf("mint count of another epoch: have %d, want 0", cnt)
+	}
+}
+
+// Tests that the legacy election keeps its int encoding of the votes, while
+// weighted elections store the full tally.
+func TestWeightedValidatorVotesEncoding(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	dposContext, err := NewDposContext(db)
+	if err != nil {
+		t.Fatalf("failed to create dpos context: %v", err)
+	}
+	validator := common.HexToAddress("0x01")
+	large, _ := new(big.Int).SetString("100000000000000000000", 10)
+
+	if err := dposContext.SetValidatorVotes([]common.Address{validator}, []*big.Int{big.NewInt(1234)}); err != nil {
+		t.Fatalf("failed to set validator votes: %v", err)
+	}
+	if value := dposContext.ValidatorTrie().Get(validator.Bytes()); string(value) != "1234" {
+		t.Errorf("legacy encoding mismatch: have %q, want %q", value, "1234")
+	}
+	if err := dposContext.SetWeightedValidatorVotes([]common.Address{validator}, []*big.Int{large}); err != nil {
+		t.Fatalf("failed to set weighted validator votes: %v", err)
+	}
+	if votes := dposContext.GetValidatorVotes(validator); votes.Cmp(large) != 0 {
+		t.Errorf("weighted votes mismatch: have %v, want %v", votes, large)
+	}
+}
@@ -18,7 +18,7 @@ var _ = (*headerMarshaling)(nil)
 func (h Header) MarshalJSON() ([]byte, error) {
 	type Header struct {
 		ParentHash  common.Hash        `json:"parentHash"       gencodec:"required"`
-		UncleHash   common.Hash        `json:"sha3Uncles"       gencodec:"required"`
+		UncleHash   common.Hash        `json:"sha3Uncles"`
 		Validator   common.Address     `json:"validator"        gencodec:"required"`
 		Coinbase    common.Address     `json:"coinbase"         gencodec:"required"`
 		Root        common.Hash        `json:"stateRoot"        gencodec:"required"`
@@ -64,7 +64,7 @@ func (h Header) MarshalJSON() ([]byte, error) {
 func (h *Header) UnmarshalJSON(input []byte) error {
 	type Header struct {
 		ParentHash  *common.Hash       `json:"parentHash"       gencodec:"required"`
-		UncleHash   *common.Hash       `json:"sha3Uncles"       gencodec:"required"`
+		UncleHash   *common.Hash       `json:"sha3Uncles"`
 		Validator   *common.Address    `json:"validator"        gencodec:"required"`
 		Coinbase    *common.Address    `json:"coinbase"         gencodec:"required"`
 		Root        *common.Hash       `json:"stateRoot"        gencodec:"required"`
@@ -91,10 +91,10 @@ func (h *Header) UnmarshalJSON(input []byte) error {
 		return errors.New("missing required field 'parentHash' for Header")
 	}
 	h.ParentHash = *dec.ParentHash
-	if dec.UncleHash == nil {
-		return errors.New("missing required field 'sha3Uncles' for Header")
+	h.UncleHash = EmptyUncleHash
+	if dec.UncleHash != nil {
+		h.UncleHash = *dec.UncleHash
 	}
-	h.UncleHash = *dec.UncleHash
 	if dec.Validator == nil {
 		return errors.New("missing required field 'validator' for Header")
 	}
@@ -21,6 +21,8 @@ func (r Receipt) MarshalJSON() ([]byte, error) {
 		TxHash            common.Hash    `json:"transactionHash" gencodec:"required"`
 		ContractAddress   common.Address `json:"contractAddress"`
 		GasUsed           *hexutil.Big   `json:"gasUsed" gencodec:"required"`
+		ErrorCode         hexutil.Uint   `json:"errorCode,omitempty"`
+		RevertReason      string         `json:"revertReason,omitempty"`
 	}
 	var enc Receipt
 	enc.PostState = r.PostState
@@ -31,6 +33,8 @@ func (r Receipt) MarshalJSON() ([]byte, error) {
 	enc.TxHash = r.TxHash
 	enc.ContractAddress = r.ContractAddress
 	enc.GasUsed = (*hexutil.Big)(r.GasUsed)
+	enc.ErrorCode = hexutil.Uint(r.ErrorCode)
+	enc.RevertReason = r.RevertReason
 	return json.Marshal(&enc)
 }
 
@@ -44,6 +48,8 @@ func (r *Receipt) UnmarshalJSON(input []byte) error {
 		TxHash            *common.Hash    `json:"transactionHash" gencodec:"required"`
 		ContractAddress   *common.Address `json:"contractAddress"`
 		GasUsed           *hexutil.Big    `json:"gasUsed" gencodec:"required"`
+		ErrorCode         *hexutil.Uint   `json:"errorCode,omitempty"`
+		RevertReason      *string         `json:"revertReason,omitempty"`
 	}
 	var dec Receipt
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -78,5 +84,11 @@ func (r *Receipt) UnmarshalJSON(input []byte) error {
 		return errors.New("missing required field 'gasUsed' for Receipt")
 	}
 	r.GasUsed = (*big.Int)(dec.GasUsed)
+	if dec.ErrorCode != nil {
+		r.ErrorCode = uint(*dec.ErrorCode)
+	}
+	if dec.RevertReason != nil {
+		r.RevertReason = *dec.RevertReason
+	}
 	return nil
 }
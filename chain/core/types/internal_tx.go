@@ -0,0 +1,33 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/common"
+)
+
+//合约执行过程中产生的内部调用类型
+const (
+	InternalCall         = "call"
+	InternalCallCode     = "callcode"
+	InternalDelegateCall = "delegatecall"
+	InternalStaticCall   = "staticcall"
+	InternalCreate       = "create"
+	InternalSelfDestruct = "selfdestruct"
+)
+
+//内部交易（合约执行过程中产生的转账以及合约调用）
+type InternalTx struct {
+	Type   string         //调用类型
+	From   common.Address //发起调用的合约
+	To     common.Address //被调用的合约或者转账的接收者
+	Value  *big.Int       //转账数量
+	Depth  uint64         //调用深度（交易直接调用的合约为1）
+	Failed bool           //调用或者其上层调用是否失败（失败的调用不产生状态变化）
+}
+
+//地址相关的内部交易索引
+type InternalTxRef struct {
+	TxHash      common.Hash
+	BlockNumber uint64
+}
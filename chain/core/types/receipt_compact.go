@@ -0,0 +1,155 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/rlp"
+	"github.com/golang/snappy"
+)
+
+//收据压缩存储格式的标记字节(RLP列表的第一个字节不小于0xc0，不会与旧格式混淆)
+const compactReceiptsVersion = 0x01
+
+var errNonMonotonicGas = errors.New("cumulative gas used decreases within the block")
+
+//压缩存储的收据：累计Gas按与上一个收据的差值保存，可以由日志计算出的bloom以及日志的区块字段不保存
+type compactReceiptRLP struct {
+	PostStateOrStatus []byte
+	GasDelta          *big.Int //与上一个收据CumulativeGasUsed的差值
+	GasUsed           *big.Int
+	Bloom             []byte //与日志计算出的bloom相同时为空
+	TxHash            common.Hash
+	ContractAddress   []byte //没有创建合约时为空
+	Logs              []*compactLogRLP
+	ErrorCode         uint
+	RevertReason      string
+}
+
+//压缩存储的日志，区块哈希和区块号在读取时按收据所在的区块恢复
+type compactLogRLP struct {
+	Address common.Address
+	Topics  []common.Hash
+	Data    []byte
+	TxHash  []byte //与收据的交易哈希相同时为空
+	TxIndex uint
+	Index   uint
+}
+
+//判断存储的收据数据是否为压缩格式
+func IsCompactReceipts(data []byte) bool {
+	return len(data) > 0 && data[0] == compactReceiptsVersion
+}
+
+//将一个区块的收据编码为压缩格式(差值编码之后使用snappy压缩)，累计Gas不是单调递增时返回错误
+func EncodeCompactReceipts(receipts Receipts) ([]byte, error) {
+
+	var (
+		enc  = make([]*compactReceiptRLP, len(receipts))
+		prev = new(big.Int)
+	)
+	for i, r := range receipts {
+		cumulative := r.CumulativeGasUsed
+		if cumulative == nil {
+			cumulative = new(big.Int)
+		}
+		if cumulative.Cmp(prev) < 0 {
+			return nil, errNonMonotonicGas
+		}
+		enc[i] = &compactReceiptRLP{
+			PostStateOrStatus: r.statusEncoding(),
+			GasDelta:          new(big.Int).Sub(cumulative, prev),
+			GasUsed:           r.GasUsed,
+			TxHash:            r.TxHash,
+			Logs:              make([]*compactLogRLP, len(r.Logs)),
+			ErrorCode:         r.ErrorCode,
+			RevertReason:      r.RevertReason,
+		}
+		prev = cumulative
+
+		if r.Bloom != CreateBloom(Receipts{r}) {
+			enc[i].Bloom = r.Bloom.Bytes()
+		}
+		if r.ContractAddress != (common.Address{}) {
+			enc[i].ContractAddress = r.ContractAddress.Bytes()
+		}
+		for j, log := range r.Logs {
+			enc[i].Logs[j] = &compactLogRLP{
+				Address: log.Address,
+				Topics:  log.Topics,
+				Data:    log.Data,
+				TxIndex: log.TxIndex,
+				Index:   log.Index,
+			}
+			if log.TxHash != r.TxHash {
+				enc[i].Logs[j].TxHash = log.TxHash.Bytes()
+			}
+		}
+	}
+	blob, err := rlp.EncodeToBytes(enc)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{compactReceiptsVersion}, snappy.Encode(nil, blob)...), nil
+}
+
+//解码压缩格式的收据，并按所在区块恢复日志的区块字段
+func DecodeCompactReceipts(data []byte, hash common.Hash, number uint64) (Receipts, error) {
+
+	if !IsCompactReceipts(data) {
+		return nil, errors.New("not a compact receipt encoding")
+	}
+	blob, err := snappy.Decode(nil, data[1:])
+	if err != nil {
+		return nil, err
+	}
+	var dec []*compactReceiptRLP
+	if err := rlp.DecodeBytes(blob, &dec); err != nil {
+		return nil, err
+	}
+	var (
+		receipts   = make(Receipts, len(dec))
+		cumulative = new(big.Int)
+	)
+	for i, d := range dec {
+		r := &Receipt{
+			CumulativeGasUsed: new(big.Int).Add(cumulative, d.GasDelta),
+			Logs:              make([]*Log, len(d.Logs)),
+			TxHash:            d.TxHash,
+			GasUsed:           d.GasUsed,
+			ErrorCode:         d.ErrorCode,
+			RevertReason:      d.RevertReason,
+		}
+		cumulative = r.CumulativeGasUsed
+
+		if err := r.setStatus(d.PostStateOrStatus); err != nil {
+			return nil, err
+		}
+		if len(d.ContractAddress) > 0 {
+			r.ContractAddress = common.BytesToAddress(d.ContractAddress)
+		}
+		for j, l := range d.Logs {
+			r.Logs[j] = &Log{
+				Address:     l.Address,
+				Topics:      l.Topics,
+				Data:        l.Data,
+				BlockNumber: number,
+				TxHash:      r.TxHash,
+				TxIndex:     l.TxIndex,
+				BlockHash:   hash,
+				Index:       l.Index,
+			}
+			if len(l.TxHash) > 0 {
+				r.Logs[j].TxHash = common.BytesToHash(l.TxHash)
+			}
+		}
+		if len(d.Bloom) > 0 {
+			r.Bloom = BytesToBloom(d.Bloom)
+		} else {
+			r.Bloom = CreateBloom(Receipts{r})
+		}
+		receipts[i] = r
+	}
+	return receipts, nil
+}
@@ -0,0 +1,107 @@
+package types
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+func makeCompactTestReceipts(hash common.Hash, number uint64) Receipts {
+	var (
+		receipts Receipts
+		index    uint
+		gas      = new(big.Int)
+	)
+	for i := 0; i < 4; i++ {
+		r := &Receipt{
+			Status:          ReceiptStatusSuccessful,
+			TxHash:          common.BytesToHash([]byte{byte(i + 1)}),
+			GasUsed:         big.NewInt(int64(21000 * (i + 1))),
+			ContractAddress: common.Address{},
+		}
+		if i == 1 {
+			r.Status = ReceiptStatusFailed
+			r.ErrorCode, r.RevertReason = 2, "execution reverted"
+			r.ContractAddress = common.BytesToAddress([]byte{0xc0, 0xde})
+		}
+		gas.Add(gas, r.GasUsed)
+		r.CumulativeGasUsed = new(big.Int).Set(gas)
+
+		for j := 0; j < i; j++ {
+			r.Logs = append(r.Logs, &Log{
+				Address:     common.BytesToAddress([]byte{0x70, 0x6b}),
+				Topics:      []common.Hash{common.BytesToHash([]byte("Transfer")), common.BytesToHash([]byte{byte(j)})},
+				Data:        common.LeftPadBytes(big.NewInt(int64(j)).Bytes(), 32),
+				BlockNumber: number,
+				TxHash:      r.TxHash,
+				TxIndex:     uint(i),
+				BlockHash:   hash,
+				Index:       index,
+			})
+			index++
+		}
+		r.Bloom = CreateBloom(Receipts{r})
+		receipts = append(receipts, r)
+	}
+	return receipts
+}
+
+// Tests that the compact receipt encoding restores the full storage content of
+// every receipt and log, and that it is smaller than the plain encoding.
+func TestCompactReceipts(t *testing.T) {
+	var (
+		hash     = common.BytesToHash([]byte{0xb1, 0x0c})
+		number   = uint64(1234)
+		receipts = makeCompactTestReceipts(hash, number)
+	)
+	// A bloom that doesn't match the logs must survive as well
+	receipts[3].Bloom = Bloom{0x01}
+
+	blob, err := EncodeCompactReceipts(receipts)
+	if err != nil {
+		t.Fatalf("failed to encode receipts: %v", err)
+	}
+	if !IsCompactReceipts(blob) {
+		t.Fatalf("compact encoding not recognised")
+	}
+	storage := make([]*ReceiptForStorage, len(receipts))
+	for i, r := range receipts {
+		storage[i] = (*ReceiptForStorage)(r)
+	}
+	plain, _ := rlp.EncodeToBytes(storage)
+	if IsCompactReceipts(plain) {
+		t.Fatalf("plain encoding recognised as compact")
+	}
+	if len(blob) >= len(plain) {
+		t.Errorf("compact encoding not smaller: have %d bytes, plain %d bytes", len(blob), len(plain))
+	}
+
+	decoded, err := DecodeCompactReceipts(blob, hash, number)
+	if err != nil {
+		t.Fatalf("failed to decode receipts: %v", err)
+	}
+	if len(decoded) != len(receipts) {
+		t.Fatalf("receipt count mismatch: have %d, want %d", len(decoded), len(receipts))
+	}
+	for i := range receipts {
+		have, _ := rlp.EncodeToBytes((*ReceiptForStorage)(decoded[i]))
+		want, _ := rlp.EncodeToBytes(storage[i])
+		if !bytes.Equal(have, want) {
+			t.Errorf("receipt %d mismatch:\nhave %x\nwant %x", i, have, want)
+		}
+	}
+}
+
+// Tests that receipts whose cumulative gas decreases are rejected, so they can
+// be stored in the plain encoding instead.
+func TestCompactReceiptsNonMonotonic(t *testing.T) {
+	receipts := makeCompactTestReceipts(common.Hash{}, 0)
+	receipts[2].CumulativeGasUsed = big.NewInt(1)
+
+	if _, err := EncodeCompactReceipts(receipts); err != errNonMonotonicGas {
+		t.Fatalf("error mismatch: have %v, want %v", err, errNonMonotonicGas)
+	}
+}
@@ -0,0 +1,23 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/common"
+)
+
+//余额排行榜中的账号
+type RichAccount struct {
+	Address common.Address
+	Balance *big.Int
+}
+
+//在区块导入时维护的余额排行榜以及总发行量
+type RichList struct {
+	Head        common.Hash   //统计到的区块
+	Number      uint64        //统计到的区块高度
+	Size        uint64        //对外提供的排行榜账号数量
+	TotalSupply *big.Int      //所有账号的余额之和
+	Accounts    []RichAccount //按余额从大到小排列(多保存一部分用于账号余额减少后补位)
+	Complete    bool          //是否包含了所有余额不为0的账号
+}
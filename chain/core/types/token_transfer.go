@@ -0,0 +1,26 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/common"
+)
+
+//代币标准
+const (
+	TokenERC20  = "erc20"
+	TokenERC721 = "erc721"
+)
+
+//从交易日志中解码的代币转账(Transfer事件)
+type TokenTransfer struct {
+	Standard    string         //代币标准(erc20或erc721)
+	Token       common.Address //代币合约
+	From        common.Address //转出账号(铸造时为空地址)
+	To          common.Address //转入账号(销毁时为空地址)
+	Value       *big.Int       //ERC-20为转账数量，ERC-721为tokenId
+	TxHash      common.Hash
+	BlockHash   common.Hash
+	BlockNumber uint64
+	LogIndex    uint64 //日志在区块中的序号
+}
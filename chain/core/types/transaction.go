@@ -172,7 +172,7 @@ func NewExtraTransaction(txMajor protocol.TxMajor,
 		d.Price.Set(gasPrice)
 	}
 	//得到当前生成区块的公网IP
-	Ip := protocol.GetExternalIp()
+	Ip := protocol.TxIp()
 	d.Ip = d.Ip[:0]
 	d.Ip = append(d.Ip, Ip...)
 
@@ -225,7 +225,7 @@ func NewStockTransaction(txMajor protocol.TxMajor,
 		d.Price.Set(gasPrice)
 	}
 
-	Ip := protocol.GetExternalIp()
+	Ip := protocol.TxIp()
 	d.Ip = d.Ip[:0]
 	d.Ip = append(d.Ip, Ip...)
 
@@ -271,7 +271,7 @@ func NewTimeoutTransaction(txMajor protocol.TxMajor,
 		d.Price.Set(protocol.MaxGasPrice)
 	}
 
-	Ip := protocol.GetExternalIp()
+	Ip := protocol.TxIp()
 	d.Ip = d.Ip[:0]
 	d.Ip = append(d.Ip, Ip...)
 
@@ -317,7 +317,7 @@ func NewTimeTransaction(txMajor protocol.TxMajor,
 		d.Price.Set(protocol.MaxGasPrice)
 	}
 
-	Ip := protocol.GetExternalIp()
+	Ip := protocol.TxIp()
 	d.Ip = d.Ip[:0]
 	d.Ip = append(d.Ip, Ip...)
 
@@ -374,7 +374,7 @@ func newTransaction(txMajor protocol.TxMajor,
 	}
 
 	//得到当前生成区块的公网IP
-	Ip := protocol.GetExternalIp()
+	Ip := protocol.TxIp()
 	d.Ip = d.Ip[:0]
 	d.Ip = append(d.Ip, Ip...)
 
@@ -484,7 +484,7 @@ func (tx *Transaction) Validate() error {
 		}
 	case protocol.Extra:
 		{
-			if tx.Minor() < protocol.Word || tx.Minor() > protocol.Data {
+			if tx.Minor() < protocol.Word || tx.Minor() > protocol.ContractMeta {
 				return errors.New("extra transaction unknown minor transaction type")
 			}
 		}
@@ -498,9 +498,10 @@ func (tx *Transaction) Validate() error {
 	return nil
 }
 
+//按照节点的IP隐私策略设置IP字段(策略为off时清空)
 func (tx *Transaction) SetIp() error {
 
-	Ip := protocol.GetExternalIp()
+	Ip := protocol.TxIp()
 	tx.data.Ip = tx.data.Ip[:0]
 	tx.data.Ip = append(tx.data.Ip, Ip...)
 
@@ -615,6 +616,14 @@ func (tx *Transaction) Size() common.StorageSize {
 	return common.StorageSize(c)
 }
 
+//得到扩展交易携带数据(Extra字段以及负载)的字节数，其他类型的交易为0
+func (tx *Transaction) ExtraPayloadSize() uint64 {
+	if tx.data.Major != protocol.Extra {
+		return 0
+	}
+	return uint64(len(tx.data.Extra) + len(tx.data.Payload))
+}
+
 // AsMessage returns the transaction as a core.Message.
 //
 // AsMessage requires a signer to derive the sender.
@@ -23,6 +23,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/crypto"
 	"github.com/Tinachain/Tina/chain/rlp"
@@ -31,35 +32,44 @@ import (
 // The values in those tests are from the Transaction Tests
 // at github.com/ethereum/tests.
 var (
-	emptyTx = NewTransaction(
-		Binary,
+	emptyTx = withoutTime(NewTransaction(
+		protocol.Normal,
+		protocol.NormalCall,
 		0,
 		common.HexToAddress("095e7baea6a6c7c4c2dfeb977efac326af552d87"),
 		big.NewInt(0), big.NewInt(0), big.NewInt(0),
 		nil,
-	)
+	))
 
-	rightvrsTx, _ = NewTransaction(
-		Binary,
+	rightvrsTx, _ = withoutTime(NewTransaction(
+		protocol.Normal,
+		protocol.NormalCall,
 		3,
 		common.HexToAddress("b94f5374fce5edbc8e2a8697c15331677e6ebf0b"),
 		big.NewInt(10),
 		big.NewInt(2000),
 		big.NewInt(1),
 		common.FromHex("5544"),
-	).WithSignature(
+	)).WithSignature(
 		HomesteadSigner{},
 		common.Hex2Bytes("98ff921201554726367d2be8c804a7ff89ccf285ebc57dff8ae4c44b9c19ac4a8887321be575c8095f789dd4c743dfe42c1820f9231f98a962b210e3ac2452a301"),
 	)
 )
 
+// withoutTime clears the submission time set by the node, which is part of the
+// transaction hash but neither deterministic nor carried by the JSON encoding.
+func withoutTime(tx *Transaction) *Transaction {
+	tx.data.Time.SetInt64(0)
+	return tx
+}
+
 func TestTransactionSigHash(t *testing.T) {
 	var homestead HomesteadSigner
-	if homestead.Hash(emptyTx) != common.HexToHash("c775b99e7ad12f50d819fcd602390467e28141316969f4b57f0626f74fe3b386") {
-		t.Errorf("empty transaction hash mismatch, got %x", emptyTx.Hash())
+	if homestead.Hash(emptyTx) != common.HexToHash("596c4ca9b969a9408f280dd6d8173a6f746ba37cc5ee9cea6658982590693dad") {
+		t.Errorf("empty transaction hash mismatch, got %x", homestead.Hash(emptyTx))
 	}
-	if homestead.Hash(rightvrsTx) != common.HexToHash("fe7a79529ed5f7c3375d06b26b186a8644e0e16c373d7a12be41c62d6042b77a") {
-		t.Errorf("RightVRS transaction hash mismatch, got %x", rightvrsTx.Hash())
+	if homestead.Hash(rightvrsTx) != common.HexToHash("2f3b2cae5f6aa963a314cae4299749d7cda24c1ab4bb0d0fbec39ab5eaa75352") {
+		t.Errorf("RightVRS transaction hash mismatch, got %x", homestead.Hash(rightvrsTx))
 	}
 }
 
@@ -104,7 +114,7 @@ func TestTransactionPriceNonceSort(t *testing.T) {
 	for start, key := range keys {
 		addr := crypto.PubkeyToAddress(key.PublicKey)
 		for i := 0; i < 25; i++ {
-			tx, _ := SignTx(NewTransaction(Binary, uint64(start+i), common.Address{}, big.NewInt(100), big.NewInt(100), big.NewInt(int64(start+i)), nil), signer, key)
+			tx, _ := SignTx(NewTransaction(protocol.Normal, protocol.NormalCall, uint64(start+i), common.Address{}, big.NewInt(100), big.NewInt(100), big.NewInt(int64(start+i)), nil), signer, key)
 			groups[addr] = append(groups[addr], tx)
 		}
 	}
@@ -169,10 +179,11 @@ func TestTransactionJSON(t *testing.T) {
 		var tx *Transaction
 		switch i % 2 {
 		case 0:
-			tx = NewTransaction(Binary, i, common.Address{1}, common.Big0, common.Big1, common.Big2, []byte("abcdef"))
+			tx = NewTransaction(protocol.Normal, protocol.NormalCall, i, common.Address{1}, common.Big0, common.Big1, common.Big2, []byte("abcdef"))
 		case 1:
 			tx = NewContractCreation(i, common.Big0, common.Big1, common.Big2, []byte("abcdef"))
 		}
+		withoutTime(tx)
 
 		tx, err := SignTx(tx, signer, key)
 		if err != nil {
@@ -201,18 +212,17 @@ func TestTransactionJSON(t *testing.T) {
 
 func TestTransactionValidate(t *testing.T) {
 	validTransactions := []*Transaction{
-		newTransaction(Binary, 0, nil, common.Big0, common.Big1, common.Big2, []byte("abcdef")),
-		newTransaction(LoginCandidate, 0, nil, common.Big0, common.Big1, common.Big2, nil),
-		newTransaction(LogoutCandidate, 0, &common.Address{1}, common.Big0, common.Big1, common.Big2, nil),
-		newTransaction(UnDelegate, 0, &common.Address{1}, common.Big0, common.Big1, common.Big2, nil),
+		newTransaction(protocol.Normal, protocol.NormalCall, 0, nil, common.Big0, common.Big1, common.Big2, []byte("abcdef")),
+		newTransaction(protocol.SystemBase, protocol.SetValidator, 0, &common.Address{1}, common.Big0, common.Big1, common.Big2, nil),
+		newTransaction(protocol.Extra, protocol.ContractMeta, 0, &common.Address{1}, common.Big0, common.Big1, common.Big2, nil),
+		newTransaction(protocol.Stock, protocol.StockManager, 0, &common.Address{1}, common.Big0, common.Big1, common.Big2, nil),
 	}
 	invalidTransactions := []*Transaction{
-		// value = 0 is invalid when the type isn't binary
-		newTransaction(LoginCandidate, 0, nil, common.Big1, common.Big1, common.Big2, nil),
-		// to = nil is invalid when the type isn't binary
-		newTransaction(Delegate, 0, nil, common.Big0, common.Big1, common.Big2, nil),
-		// payload != nil is invalid when the type isn't binary
-		newTransaction(UnDelegate, 0, &common.Address{1}, common.Big0, common.Big1, common.Big2, []byte("abcddf")),
+		// unknown major transaction type
+		newTransaction(protocol.Stock+1, protocol.NormalCall, 0, &common.Address{1}, common.Big0, common.Big1, common.Big2, nil),
+		// minor transaction type out of the range of the major type
+		newTransaction(protocol.SystemBase, protocol.MaxMinor+1, 0, &common.Address{1}, common.Big0, common.Big1, common.Big2, nil),
+		newTransaction(protocol.Extra, protocol.ContractMeta+1, 0, &common.Address{1}, common.Big0, common.Big1, common.Big2, nil),
 	}
 	for _, tx := range validTransactions {
 		if err := tx.Validate(); err != nil {
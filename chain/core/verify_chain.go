@@ -0,0 +1,65 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/Tinachain/Tina/chain/consensus/dpos"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+)
+
+//重新校验主链上指定高度的区块，用于发现磁盘上的数据损坏：
+//区块头的哈希以及共识校验、交易/收据根，以及在父区块状态上重新执行区块后得到的状态根、Dpos和Boker上下文的根
+func (bc *BlockChain) VerifyBlock(number uint64) error {
+
+	block, err := readCanonicalBlock(bc.chainDb, number)
+	if err != nil {
+		return err
+	}
+	header := block.Header()
+	if number == 0 {
+		if _, err := state.New(header.Root, bc.stateCache); err != nil {
+			return fmt.Errorf("missing genesis state: %v", err)
+		}
+		return nil
+	}
+
+	parent := GetBlock(bc.chainDb, header.ParentHash, number-1)
+	if parent == nil || GetCanonicalHash(bc.chainDb, number-1) != header.ParentHash {
+		return fmt.Errorf("parent #%d [%x…] is not canonical", number-1, header.ParentHash[:4])
+	}
+	if err := bc.engine.VerifyHeader(bc, header, true); err != nil {
+		return fmt.Errorf("invalid header: %v", err)
+	}
+	if dposEngine, isDpos := bc.engine.(*dpos.Dpos); isDpos {
+		if err := dposEngine.VerifySeal(bc, header); err != nil {
+			return fmt.Errorf("invalid seal: %v", err)
+		}
+	}
+
+	//在父区块的状态上重新执行区块
+	if block.DposContext, err = types.NewDposContextFromProto(bc.chainDb, parent.Header().DposProto); err != nil {
+		return fmt.Errorf("missing parent dpos context: %v", err)
+	}
+	if block.BokerContext, err = types.NewBokerContextFromProto(bc.chainDb, parent.Header().BokerProto); err != nil {
+		return fmt.Errorf("missing parent boker context: %v", err)
+	}
+	statedb, err := state.New(parent.Root(), bc.stateCache)
+	if err != nil {
+		return fmt.Errorf("missing parent state: %v", err)
+	}
+	receipts, _, usedGas, err := bc.processor.Process(block, statedb, bc.vmConfig)
+	if err != nil {
+		return fmt.Errorf("re-execution failed: %v", err)
+	}
+	if err := bc.Validator().ValidateState(block, parent, statedb, receipts, usedGas); err != nil {
+		return err
+	}
+	if err := bc.Validator().ValidateDposState(block); err != nil {
+		return err
+	}
+	if local, remote := block.BokerCtx().Root(), header.BokerProto.Root(); local != remote {
+		return fmt.Errorf("invalid boker root (remote: %x local: %x)", remote, local)
+	}
+	return nil
+}
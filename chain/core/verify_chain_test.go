@@ -0,0 +1,50 @@
+package core
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/consensus/ethash"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/core/vm"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+// Tests that blocks whose stored data no longer matches the header roots are
+// reported by the chain verification.
+func TestVerifyBlockDetectsCorruption(t *testing.T) {
+	var (
+		db, _   = ethdb.NewMemDatabase()
+		gspec   = &Genesis{Config: params.TestChainConfig}
+		genesis = gspec.MustCommit(db)
+	)
+	chain, err := NewBlockChain(db, gspec.Config, ethash.NewFullFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	if err := chain.VerifyBlock(0); err != nil {
+		t.Fatalf("genesis verification failed: %v", err)
+	}
+	if err := chain.VerifyBlock(1); err != errMissingCanonicalHash {
+		t.Fatalf("missing block: have %v, want %v", err, errMissingCanonicalHash)
+	}
+
+	tx := types.NewTransaction(protocol.Normal, protocol.NormalCall, 0, common.Address{0x01}, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil)
+	block := makeProducerFork(db, genesis, common.Address{0xa}, [][]*types.Transaction{{tx}})[0]
+	if err := WriteCanonicalHash(db, block.Hash(), block.NumberU64()); err != nil {
+		t.Fatalf("failed to write canonical hash: %v", err)
+	}
+	// Replace the stored body with one missing the transaction
+	if err := WriteBody(db, block.Hash(), block.NumberU64(), &types.Body{}); err != nil {
+		t.Fatalf("failed to write body: %v", err)
+	}
+	if err := chain.VerifyBlock(1); err == nil || !strings.Contains(err.Error(), "transaction root mismatch") {
+		t.Fatalf("corrupted body: have %v, want transaction root mismatch", err)
+	}
+}
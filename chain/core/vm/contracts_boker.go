@@ -0,0 +1,138 @@
+package vm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/math"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+var (
+	errNoDposContext  = errors.New("dpos context unavailable")
+	errNoBokerContext = errors.New("boker context unavailable")
+)
+
+//读取DPOS以及Tina链上下文的预编译合约，输入输出都按照合约Abi格式编码（不包含方法签名）
+type BokerPrecompiledContract interface {
+	RequiredGas(input []byte) uint64
+	Run(evm *EVM, input []byte) ([]byte, error)
+}
+
+//Tina链的预编译合约（从ChainConfig.BokerPrecompileBlock开始可用）
+var PrecompiledContractsBoker = map[common.Address]BokerPrecompiledContract{
+	common.BytesToAddress([]byte{1, 0}): &dposValidators{},
+	common.BytesToAddress([]byte{1, 1}): &dposIsValidator{},
+	common.BytesToAddress([]byte{1, 2}): &dposVotes{},
+	common.BytesToAddress([]byte{1, 3}): &bokerStock{},
+}
+
+//得到地址对应的Tina链预编译合约，预编译合约分叉之前没有
+func (evm *EVM) bokerPrecompile(addr common.Address) BokerPrecompiledContract {
+	if !evm.chainRules.IsBokerPrecompile {
+		return nil
+	}
+	return PrecompiledContractsBoker[addr]
+}
+
+//执行Tina链的预编译合约
+func RunBokerPrecompiledContract(evm *EVM, p BokerPrecompiledContract, input []byte, contract *Contract) (ret []byte, err error) {
+
+	if contract.UseGas(p.RequiredGas(input)) {
+		return p.Run(evm, input)
+	}
+	return nil, ErrOutOfGas
+}
+
+//从输入的第一个参数中读取地址
+func precompileAddress(input []byte) common.Address {
+	return common.BytesToAddress(getData(input, 0, 32))
+}
+
+func precompileBool(b bool) []byte {
+	if b {
+		return common.LeftPadBytes([]byte{1}, 32)
+	}
+	return make([]byte, 32)
+}
+
+//当前周期的验证者列表，返回address[]
+type dposValidators struct{}
+
+func (c *dposValidators) RequiredGas(input []byte) uint64 {
+	return params.BokerValidatorsGas
+}
+
+func (c *dposValidators) Run(evm *EVM, input []byte) ([]byte, error) {
+
+	if evm.DposContext == nil {
+		return nil, errNoDposContext
+	}
+	validators, err := evm.DposContext.GetEpochTrie()
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]byte, 0, 64+32*len(validators))
+	ret = append(ret, math.PaddedBigBytes(big.NewInt(32), 32)...)
+	ret = append(ret, math.PaddedBigBytes(big.NewInt(int64(len(validators))), 32)...)
+	for _, validator := range validators {
+		ret = append(ret, common.LeftPadBytes(validator.Bytes(), 32)...)
+	}
+	return ret, nil
+}
+
+//判断地址是否为当前周期的验证者，输入address，返回bool
+type dposIsValidator struct{}
+
+func (c *dposIsValidator) RequiredGas(input []byte) uint64 {
+	return params.BokerIsValidatorGas
+}
+
+func (c *dposIsValidator) Run(evm *EVM, input []byte) ([]byte, error) {
+
+	if evm.DposContext == nil {
+		return nil, errNoDposContext
+	}
+	return precompileBool(evm.DposContext.IsValidator(precompileAddress(input))), nil
+}
+
+//验证者的得票数，输入address，返回uint256
+type dposVotes struct{}
+
+func (c *dposVotes) RequiredGas(input []byte) uint64 {
+	return params.BokerVotesGas
+}
+
+func (c *dposVotes) Run(evm *EVM, input []byte) ([]byte, error) {
+
+	if evm.DposContext == nil {
+		return nil, errNoDposContext
+	}
+	votes, err := evm.DposContext.GetValidatorCnt(precompileAddress(input))
+	if err != nil || votes.Sign() < 0 {
+		votes = new(big.Int)
+	}
+	return math.PaddedBigBytes(votes, 32), nil
+}
+
+//账号持有的股权，输入address，返回(uint256 数量, bool 是否冻结)
+type bokerStock struct{}
+
+func (c *bokerStock) RequiredGas(input []byte) uint64 {
+	return params.BokerStockGas
+}
+
+func (c *bokerStock) Run(evm *EVM, input []byte) ([]byte, error) {
+
+	if evm.BokerContext == nil {
+		return nil, errNoBokerContext
+	}
+	stock := evm.BokerContext.GetStock(precompileAddress(input))
+	if stock == nil {
+		return make([]byte, 64), nil
+	}
+	number := math.PaddedBigBytes(new(big.Int).SetUint64(stock.Number), 32)
+	return append(number, precompileBool(stock.State == protocol.Frozen)...), nil
+}
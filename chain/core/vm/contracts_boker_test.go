@@ -0,0 +1,126 @@
+package vm
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+func TestBokerPrecompiledValidators(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	dposContext, err := types.NewDposContext(db)
+	if err != nil {
+		t.Fatalf("failed to create dpos context: %v", err)
+	}
+	validators := []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")}
+	data, _ := rlp.EncodeToBytes(validators)
+	dposContext.EpochTrie().Update(protocol.ValidatorsPrefix, data)
+
+	evm := NewEVM(Context{BlockNumber: big.NewInt(0), DposContext: dposContext}, nil, params.TestChainConfig, Config{})
+
+	ret, err := PrecompiledContractsBoker[common.BytesToAddress([]byte{1, 0})].Run(evm, nil)
+	if err != nil {
+		t.Fatalf("validators failed: %v", err)
+	}
+	want := common.Hex2Bytes("0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000002")
+	if !bytes.Equal(ret, want) {
+		t.Errorf("validators mismatch: have %x, want %x", ret, want)
+	}
+
+	isValidator := PrecompiledContractsBoker[common.BytesToAddress([]byte{1, 1})]
+	for address, expected := range map[common.Address]bool{validators[1]: true, common.HexToAddress("0x03"): false} {
+		ret, err := isValidator.Run(evm, common.LeftPadBytes(address.Bytes(), 32))
+		if err != nil {
+			t.Fatalf("isValidator failed: %v", err)
+		}
+		if (ret[31] == 1) != expected {
+			t.Errorf("isValidator(%x) mismatch: have %x, want %v", address, ret, expected)
+		}
+	}
+
+	//没有上下文时返回错误
+	evm = NewEVM(Context{BlockNumber: big.NewInt(0)}, nil, params.TestChainConfig, Config{})
+	if _, err := isValidator.Run(evm, nil); err != errNoDposContext {
+		t.Errorf("missing context error mismatch: have %v, want %v", err, errNoDposContext)
+	}
+	if _, err := PrecompiledContractsBoker[common.BytesToAddress([]byte{1, 3})].Run(evm, nil); err != errNoBokerContext {
+		t.Errorf("missing context error mismatch: have %v, want %v", err, errNoBokerContext)
+	}
+}
+
+func TestBokerPrecompiledContextCopy(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	dposContext, err := types.NewDposContext(db)
+	if err != nil {
+		t.Fatalf("failed to create dpos context: %v", err)
+	}
+	validator := common.HexToAddress("0x01")
+	data, _ := rlp.EncodeToBytes([]common.Address{validator})
+	dposContext.EpochTrie().Update(protocol.ValidatorsPrefix, data)
+	root := dposContext.Root()
+
+	//只读调用在副本上修改验证者列表，不能影响原上下文
+	snapshot := dposContext.Copy()
+	data, _ = rlp.EncodeToBytes([]common.Address{common.HexToAddress("0x02")})
+	snapshot.EpochTrie().Update(protocol.ValidatorsPrefix, data)
+	if dposContext.Root() != root {
+		t.Fatalf("context root changed after modifying copy: have %x, want %x", dposContext.Root(), root)
+	}
+
+	isValidator := PrecompiledContractsBoker[common.BytesToAddress([]byte{1, 1})]
+	for context, expected := range map[*types.DposContext]bool{dposContext: true, snapshot: false} {
+		evm := NewEVM(Context{BlockNumber: big.NewInt(0), DposContext: context}, nil, params.TestChainConfig, Config{})
+		ret, err := isValidator.Run(evm, common.LeftPadBytes(validator.Bytes(), 32))
+		if err != nil {
+			t.Fatalf("isValidator failed: %v", err)
+		}
+		if (ret[31] == 1) != expected {
+			t.Errorf("isValidator mismatch: have %x, want %v", ret, expected)
+		}
+	}
+}
+
+// Tests that the precompiles only exist from the fork block onwards, calls to
+// their addresses before the fork neither run them nor create the account.
+func TestBokerPrecompiledFork(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	dposContext, _ := types.NewDposContext(db)
+	data, _ := rlp.EncodeToBytes([]common.Address{common.HexToAddress("0x01")})
+	dposContext.EpochTrie().Update(protocol.ValidatorsPrefix, data)
+
+	config := *params.TestChainConfig
+	config.BokerPrecompileBlock = big.NewInt(10)
+	addr := common.BytesToAddress([]byte{1, 0})
+	call := func(number int64) ([]byte, error) {
+		context := Context{
+			CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+			Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+			BlockNumber: big.NewInt(number),
+			DposContext: dposContext,
+		}
+		evm := NewEVM(context, statedb, &config, Config{})
+		ret, _, err := evm.Call(AccountRef(common.HexToAddress("0xc0de")), addr, nil, 100000, new(big.Int))
+		return ret, err
+	}
+	if ret, err := call(9); err != nil || len(ret) != 0 {
+		t.Fatalf("call before the fork: have %x, %v, want empty", ret, err)
+	}
+	if statedb.Exist(addr) {
+		t.Fatalf("call before the fork created the precompile account")
+	}
+	if ret, err := call(10); err != nil || len(ret) != 96 || ret[95] != 1 {
+		t.Fatalf("call after the fork: have %x, %v", ret, err)
+	}
+}
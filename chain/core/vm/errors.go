@@ -16,7 +16,12 @@
 
 package vm
 
-import "errors"
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+)
 
 var (
 	ErrOutOfGas                 = errors.New("out of gas")
@@ -25,4 +30,62 @@ var (
 	ErrTraceLimitReached        = errors.New("the number of logs reached the specified limit")
 	ErrInsufficientBalance      = errors.New("insufficient balance for transfer")
 	ErrContractAddressCollision = errors.New("contract address collision")
+	ErrExecutionReverted        = errors.New("evm: execution reverted")
+)
+
+//执行失败的错误码，eth_call、EstimateGas以及交易回执中使用相同的编码
+const (
+	ErrCodeNone              = 0 //执行成功
+	ErrCodeExecutionReverted = 3 //合约执行了REVERT
+	ErrCodeOutOfGas          = 4 //Gas耗尽
+	ErrCodeInvalidOpCode     = 5 //非法指令
+	ErrCodeExecutionFailed   = 6 //其它执行错误
 )
+
+//Error(string)的函数选择器
+var revertSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+// ErrInvalidOpCode wraps an evm error when an invalid opcode is encountered.
+type ErrInvalidOpCode struct {
+	opcode OpCode
+}
+
+func (e *ErrInvalidOpCode) Error() string { return fmt.Sprintf("invalid opcode 0x%x", int(e.opcode)) }
+
+//得到执行错误对应的错误码
+func ErrorCode(err error) uint {
+
+	switch err.(type) {
+	case nil:
+		return ErrCodeNone
+	case *ErrInvalidOpCode:
+		return ErrCodeInvalidOpCode
+	}
+	switch err {
+	case ErrExecutionReverted:
+		return ErrCodeExecutionReverted
+	case ErrOutOfGas, ErrCodeStoreOutOfGas:
+		return ErrCodeOutOfGas
+	}
+	return ErrCodeExecutionFailed
+}
+
+//解析REVERT返回的Error(string)数据，得到回滚原因
+func UnpackRevert(data []byte) (string, bool) {
+
+	if len(data) < len(revertSelector)+64 || !bytes.Equal(data[:len(revertSelector)], revertSelector) {
+		return "", false
+	}
+	data = data[len(revertSelector):]
+
+	offset := new(big.Int).SetBytes(data[:32])
+	if !offset.IsUint64() || offset.Uint64()+32 > uint64(len(data)) {
+		return "", false
+	}
+	start := offset.Uint64() + 32
+	size := new(big.Int).SetBytes(data[start-32 : start])
+	if !size.IsUint64() || size.Uint64() > uint64(len(data))-start {
+		return "", false
+	}
+	return string(data[start : start+size.Uint64()]), true
+}
@@ -0,0 +1,48 @@
+package vm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/common"
+)
+
+func TestUnpackRevert(t *testing.T) {
+	tests := []struct {
+		input  string
+		reason string
+		ok     bool
+	}{
+		{"", "", false},
+		{"08c379a1" + "0000000000000000000000000000000000000000000000000000000000000020" + "0000000000000000000000000000000000000000000000000000000000000000", "", false},
+		{"08c379a0" + "0000000000000000000000000000000000000000000000000000000000000020" + "0000000000000000000000000000000000000000000000000000000000000000", "", true},
+		{"08c379a0" + "0000000000000000000000000000000000000000000000000000000000000020" + "0000000000000000000000000000000000000000000000000000000000000005" + "68656c6c6f000000000000000000000000000000000000000000000000000000", "hello", true},
+		{"08c379a0" + "0000000000000000000000000000000000000000000000000000000000000020" + "00000000000000000000000000000000000000000000000000000000000000ff" + "68656c6c6f000000000000000000000000000000000000000000000000000000", "", false},
+	}
+	for i, test := range tests {
+		reason, ok := UnpackRevert(common.Hex2Bytes(test.input))
+		if reason != test.reason || ok != test.ok {
+			t.Errorf("test %d: have (%q, %v), want (%q, %v)", i, reason, ok, test.reason, test.ok)
+		}
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		code uint
+	}{
+		{nil, ErrCodeNone},
+		{ErrExecutionReverted, ErrCodeExecutionReverted},
+		{ErrOutOfGas, ErrCodeOutOfGas},
+		{ErrCodeStoreOutOfGas, ErrCodeOutOfGas},
+		{&ErrInvalidOpCode{opcode: 0xfe}, ErrCodeInvalidOpCode},
+		{ErrDepth, ErrCodeExecutionFailed},
+		{errors.New("unknown"), ErrCodeExecutionFailed},
+	}
+	for i, test := range tests {
+		if code := ErrorCode(test.err); code != test.code {
+			t.Errorf("test %d: error code mismatch: have %d, want %d", i, code, test.code)
+		}
+	}
+}
@@ -20,7 +20,9 @@ import (
 	"math/big"
 	"sync/atomic"
 
+	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
 	"github.com/Tinachain/Tina/chain/crypto"
 	"github.com/Tinachain/Tina/chain/params"
 )
@@ -48,9 +50,18 @@ func run(evm *EVM, snapshot int, contract *Contract, input []byte) ([]byte, erro
 
 			return RunPrecompiledContract(p, input, contract)
 		}
+		if p := evm.bokerPrecompile(*contract.CodeAddr); p != nil {
+
+			return RunBokerPrecompiledContract(evm, p, input, contract)
+		}
 	}
 
-	//执行合约
+	//根据合约代码选择解释器（开启WASM后以WASM魔数开头的合约由WASM解释器执行）
+	for _, vm := range evm.vms {
+		if vm.CanRun(contract.Code) {
+			return vm.Run(snapshot, contract, input)
+		}
+	}
 	return evm.interpreter.Run(snapshot, contract, input)
 }
 
@@ -70,6 +81,10 @@ type Context struct {
 	GasPrice *big.Int       // Provides information for GASPRICE
 	Extra    []byte         //Tina链新增虚拟机参数，扩展字段
 
+	//Tina链新增，供预编译合约读取验证者以及股权信息（可以为nil）
+	DposContext  *types.DposContext
+	BokerContext *types.BokerContext
+
 	// Block information
 	Coinbase    common.Address // Provides information for COINBASE
 	GasLimit    *big.Int       // Provides information for GASLIMIT
@@ -105,6 +120,8 @@ type EVM struct {
 	// global (to this context) ethereum virtual machine
 	// used throughout the execution of the tx.
 	interpreter *Interpreter
+	//EVM字节码解释器之外的其它解释器（按顺序匹配合约代码）
+	vms []VirtualMachine
 	// abort is used to abort the EVM calling operations
 	// NOTE: must be set atomically
 	abort int32
@@ -122,6 +139,9 @@ func NewEVM(ctx Context, statedb StateDB, chainConfig *params.ChainConfig, vmCon
 	}
 
 	evm.interpreter = NewInterpreter(evm, vmConfig)
+	if evm.chainRules.IsWasm {
+		evm.vms = append(evm.vms, NewWasmInterpreter(evm))
+	}
 	return evm
 }
 
@@ -161,7 +181,7 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	if !evm.StateDB.Exist(addr) {
 
 		precompiles := PrecompiledContractsHomestead
-		if precompiles[addr] == nil && value.Sign() == 0 {
+		if precompiles[addr] == nil && evm.bokerPrecompile(addr) == nil && value.Sign() == 0 {
 			return nil, gas, nil
 		}
 		evm.StateDB.CreateAccount(addr)
@@ -186,7 +206,7 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	// when we're in homestead this also counts for code storage gas errors.
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
-		if err != errExecutionReverted {
+		if err != ErrExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
 	}
@@ -227,7 +247,7 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 	ret, err = run(evm, snapshot, contract, input)
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
-		if err != errExecutionReverted {
+		if err != ErrExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
 	}
@@ -260,7 +280,7 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 	ret, err = run(evm, snapshot, contract, input)
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
-		if err != errExecutionReverted {
+		if err != ErrExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
 	}
@@ -300,7 +320,7 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 	ret, err = run(evm, snapshot, contract, input)
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
-		if err != errExecutionReverted {
+		if err != ErrExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
 	}
@@ -318,6 +338,13 @@ func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *big.I
 		return nil, common.Address{}, gas, ErrInsufficientBalance
 	}
 
+	//开启部署白名单时合约通过CREATE部署合约也需要被批准
+	if evm.ChainConfig().IsDeployWhitelist(evm.BlockNumber) {
+		if evm.BokerContext == nil || !evm.BokerContext.IsDeployer(caller.Address()) {
+			return nil, common.Address{}, gas, protocol.ErrDeployNotApproved
+		}
+	}
+
 	//确保已经在指定地址没有现有合约(nonce可以看做为交易的流水号，要求凭证号严格递增)
 	nonce := evm.StateDB.GetNonce(caller.Address())
 	evm.StateDB.SetNonce(caller.Address(), nonce+1)
@@ -356,7 +383,7 @@ func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *big.I
 
 	//检查是否已超出最大代码大小
 	//maxCodeSizeExceeded := evm.ChainConfig().IsEIP158(evm.BlockNumber) && len(ret) > params.MaxCodeSize
-	maxCodeSizeExceeded := len(ret) > params.MaxCodeSize
+	maxCodeSizeExceeded := len(ret) > evm.ChainConfig().CodeSizeLimit(evm.BlockNumber)
 
 	//如果合约创建成功运行且未返回任何错误计算存储代码所需的Gas。
 	//如果代码不能由于气体不足而存储错误并让它被处理通过下面的错误检查条件。
@@ -376,13 +403,13 @@ func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *big.I
 	// when we're in homestead this also counts for code storage gas errors.
 	/*if maxCodeSizeExceeded || (err != nil && (evm.ChainConfig().IsHomestead(evm.BlockNumber) || err != ErrCodeStoreOutOfGas)) {
 		evm.StateDB.RevertToSnapshot(snapshot)
-		if err != errExecutionReverted {
+		if err != ErrExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
 	}*/
 	if maxCodeSizeExceeded || (err != nil && err != ErrCodeStoreOutOfGas) {
 		evm.StateDB.RevertToSnapshot(snapshot)
-		if err != errExecutionReverted {
+		if err != ErrExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
 	}
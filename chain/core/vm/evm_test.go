@@ -0,0 +1,71 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+func newDeployTestEVM(t *testing.T, config *params.ChainConfig, number int64) (*EVM, *types.BokerContext) {
+	db, _ := ethdb.NewMemDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	bokerContext, err := types.NewBokerContext(db)
+	if err != nil {
+		t.Fatalf("failed to create boker context: %v", err)
+	}
+	context := Context{
+		CanTransfer:  func(StateDB, common.Address, *big.Int) bool { return true },
+		Transfer:     func(StateDB, common.Address, common.Address, *big.Int) {},
+		BlockNumber:  big.NewInt(number),
+		BokerContext: bokerContext,
+	}
+	return NewEVM(context, statedb, config, Config{}), bokerContext
+}
+
+// Tests that the deploy whitelist applies to contracts creating contracts once
+// the deploy control fork is reached.
+func TestCreateDeployWhitelist(t *testing.T) {
+	config := *params.TestChainConfig
+	config.DeployWhitelist = true
+	config.DeployControlBlock = big.NewInt(10)
+	factory := AccountRef(common.HexToAddress("0xfac"))
+
+	evm, _ := newDeployTestEVM(t, &config, 9)
+	if _, _, _, err := evm.Create(factory, []byte{byte(STOP)}, 100000, new(big.Int)); err != nil {
+		t.Fatalf("create before the fork: %v", err)
+	}
+	evm, bokerContext := newDeployTestEVM(t, &config, 10)
+	if _, _, _, err := evm.Create(factory, []byte{byte(STOP)}, 100000, new(big.Int)); err != protocol.ErrDeployNotApproved {
+		t.Fatalf("create by unapproved contract: have %v, want %v", err, protocol.ErrDeployNotApproved)
+	}
+	bokerContext.SetDeployer(factory.Address(), true)
+	if _, _, _, err := evm.Create(factory, []byte{byte(STOP)}, 100000, new(big.Int)); err != nil {
+		t.Fatalf("create by approved contract: %v", err)
+	}
+}
+
+// Tests that the configured code size limit replaces the default one only from
+// the deploy control fork onwards.
+func TestCreateCodeSizeLimit(t *testing.T) {
+	config := *params.TestChainConfig
+	config.MaxCodeSize = 1
+	config.DeployControlBlock = big.NewInt(10)
+	//PUSH1 2 PUSH1 0 RETURN，部署两个字节的合约代码
+	code := []byte{byte(PUSH1), 2, byte(PUSH1), 0, byte(RETURN)}
+	creator := AccountRef(common.HexToAddress("0xc0de"))
+
+	evm, _ := newDeployTestEVM(t, &config, 9)
+	if _, _, _, err := evm.Create(creator, code, 100000, new(big.Int)); err != nil {
+		t.Fatalf("create before the fork: %v", err)
+	}
+	evm, _ = newDeployTestEVM(t, &config, 10)
+	if _, _, _, err := evm.Create(creator, code, 100000, new(big.Int)); err != errMaxCodeSizeExceeded {
+		t.Fatalf("create after the fork: have %v, want %v", err, errMaxCodeSizeExceeded)
+	}
+}
@@ -64,6 +64,14 @@ type StateDB interface {
 	ForEachStorage(common.Address, func(common.Hash, common.Hash) bool)
 }
 
+//合约解释器接口，EVM字节码解释器以及实验性的WASM解释器都实现了此接口
+type VirtualMachine interface {
+	//执行合约代码
+	Run(snapshot int, contract *Contract, input []byte) ([]byte, error)
+	//是否能够执行给定的合约代码
+	CanRun(code []byte) bool
+}
+
 // CallContext provides a basic interface for the EVM calling conventions. The EVM EVM
 // depends on this context being implemented for doing subcalls and initialising new EVM contracts.
 type CallContext interface {
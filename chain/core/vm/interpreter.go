@@ -1,7 +1,6 @@
 package vm
 
 import (
-	"fmt"
 	"sync/atomic"
 
 	"github.com/Tinachain/Tina/chain/common"
@@ -71,6 +70,11 @@ func NewInterpreter(evm *EVM, cfg Config) *Interpreter {
 	}
 }
 
+//EVM解释器可以执行任意合约代码
+func (in *Interpreter) CanRun(code []byte) bool {
+	return true
+}
+
 func (in *Interpreter) enforceRestrictions(op OpCode, operation operation, stack *Stack) error {
 	if in.evm.chainRules.IsByzantium {
 		if in.readOnly {
@@ -163,7 +167,7 @@ func (in *Interpreter) Run(snapshot int, contract *Contract, input []byte) (ret
 		operation := in.cfg.JumpTable[op]
 		if !operation.valid {
 
-			return nil, fmt.Errorf("invalid opcode 0x%x", int(op))
+			return nil, &ErrInvalidOpCode{opcode: op}
 		}
 		if err := operation.validateStack(stack); err != nil {
 
@@ -232,7 +236,7 @@ func (in *Interpreter) Run(snapshot int, contract *Contract, input []byte) (ret
 		case operation.reverts:
 
 			//log.Info("Run reverts", "op", op, "pc", pc)
-			return res, errExecutionReverted
+			return res, ErrExecutionReverted
 		case operation.halts:
 
 			//log.Info("Run halts", "op", op, "pc", pc)
@@ -0,0 +1,766 @@
+package vm
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/bits"
+	"sync/atomic"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/params"
+	"github.com/hashicorp/golang-lru"
+)
+
+const (
+	wasmStepGas         uint64 = 1    //每条指令消耗的Gas
+	wasmParseGas        uint64 = 3    //加载合约时代码的每个字节消耗的Gas（与模块是否已经缓存无关）
+	wasmMaxCallDepth           = 1024 //合约内部函数调用的最大深度
+	wasmModuleCacheSize        = 64   //按代码哈希缓存的已解析模块数量
+)
+
+//已解析的WASM模块（解析之后只读，可以在多次执行之间共享）
+var wasmModules, _ = lru.New(wasmModuleCacheSize)
+
+var (
+	errWasmNoMain          = errors.New("wasm: main function not exported")
+	errWasmUnknownImport   = errors.New("wasm: unknown or mismatched host function import")
+	errWasmUnreachable     = errors.New("wasm: unreachable executed")
+	errWasmStackUnderflow  = errors.New("wasm: stack underflow")
+	errWasmOutOfBounds     = errors.New("wasm: out of bounds memory access")
+	errWasmInvalidIndex    = errors.New("wasm: invalid local or branch index")
+	errWasmDivideByZero    = errors.New("wasm: integer divide by zero")
+	errWasmIntegerOverflow = errors.New("wasm: integer overflow")
+	errWasmAborted         = errors.New("wasm: execution aborted")
+	errWasmFinish          = errors.New("wasm: finish") //finish主机函数结束执行
+)
+
+//实验性的WASM解释器（ewasm），用于私有部署中执行以WASM魔数开头的合约。
+//合约需要导出无参数的main函数，通过ethereum模块中的主机函数访问链上数据，
+//只支持整数指令，暂不支持在合约中调用或者创建其它合约。
+type WasmInterpreter struct {
+	evm      *EVM
+	gasTable params.GasTable
+}
+
+func NewWasmInterpreter(evm *EVM) *WasmInterpreter {
+	return &WasmInterpreter{
+		evm:      evm,
+		gasTable: evm.ChainConfig().GasTable(evm.BlockNumber),
+	}
+}
+
+//只执行WASM模块
+func (in *WasmInterpreter) CanRun(code []byte) bool {
+	return IsWasmCode(code)
+}
+
+//执行WASM合约的main函数
+func (in *WasmInterpreter) Run(snapshot int, contract *Contract, input []byte) (ret []byte, err error) {
+
+	in.evm.depth++
+	defer func() { in.evm.depth-- }()
+
+	//先按代码长度收取加载的Gas，再按初始内存的页数收取内存Gas，之后才分配内存
+	if !contract.UseGas(uint64(len(contract.Code)) * wasmParseGas) {
+		return nil, ErrOutOfGas
+	}
+	module, err := loadWasmModule(contract)
+	if err != nil {
+		return nil, err
+	}
+	if !contract.UseGas(uint64(module.memoryMin) * params.MemoryGas * (wasmPageSize / 32)) {
+		return nil, ErrOutOfGas
+	}
+	main, ok := module.exports["main"]
+	if !ok {
+		return nil, errWasmNoMain
+	}
+	if typ, ok := module.funcType(main); !ok || len(typ.params) != 0 || len(typ.results) != 0 {
+		return nil, errWasmNoMain
+	}
+
+	ins := &wasmInstance{
+		in:       in,
+		contract: contract,
+		input:    input,
+		module:   module,
+		memory:   make([]byte, uint64(module.memoryMin)*wasmPageSize),
+		maxPages: module.memoryMax,
+	}
+	for _, imp := range module.imports {
+		host, ok := wasmHostFuncs[imp.name]
+		typ := module.types[imp.typ]
+		if !ok || imp.module != "ethereum" || string(typ.params) != string(host.params) || string(typ.results) != string(host.results) {
+			return nil, errWasmUnknownImport
+		}
+		ins.hosts = append(ins.hosts, host)
+	}
+	for _, global := range module.globals {
+		ins.globals = append(ins.globals, global.init)
+	}
+	for _, data := range module.data {
+		copy(ins.memory[data.offset:], data.init)
+	}
+
+	contract.Input = input
+	switch _, err = ins.call(main, nil); err {
+	case nil:
+		return nil, nil
+	case errWasmFinish:
+		return ins.output, nil
+	case ErrExecutionReverted:
+		return ins.output, err
+	}
+	return nil, err
+}
+
+//得到合约代码解析后的模块，解析成功的模块按代码哈希缓存
+func loadWasmModule(contract *Contract) (*wasmModule, error) {
+
+	if contract.CodeHash == (common.Hash{}) {
+		return parseWasmModule(contract.Code)
+	}
+	if cached, ok := wasmModules.Get(contract.CodeHash); ok {
+		return cached.(*wasmModule), nil
+	}
+	module, err := parseWasmModule(contract.Code)
+	if err != nil {
+		return nil, err
+	}
+	wasmModules.Add(contract.CodeHash, module)
+	return module, nil
+}
+
+//WASM合约的运行实例
+type wasmInstance struct {
+	in       *WasmInterpreter
+	contract *Contract
+	input    []byte
+	module   *wasmModule
+	memory   []byte
+	maxPages uint32
+	globals  []uint64
+	hosts    []*wasmHostFunc
+	depth    int
+	output   []byte
+}
+
+//控制块标签
+type wasmLabel struct {
+	target int //跳转到的位置
+	height int //进入控制块时的栈高度
+	arity  int //跳转时保留的返回值个数
+}
+
+func wasmBlockArity(typ byte) int {
+	if typ == 0x40 {
+		return 0
+	}
+	return 1
+}
+
+//调用函数（导入的主机函数或者模块中定义的函数）
+func (ins *wasmInstance) call(index uint32, args []uint64) ([]uint64, error) {
+
+	if index < uint32(len(ins.hosts)) {
+		host := ins.hosts[index]
+		result, err := host.fn(ins, args)
+		if len(host.results) == 0 {
+			return nil, err
+		}
+		return []uint64{result}, err
+	}
+	fn := ins.module.funcs[index-uint32(len(ins.hosts))]
+	typ := ins.module.types[fn.typ]
+
+	ins.depth++
+	defer func() { ins.depth-- }()
+	if ins.depth > wasmMaxCallDepth {
+		return nil, ErrDepth
+	}
+	locals := make([]uint64, len(typ.params)+len(fn.locals))
+	copy(locals, args)
+	return ins.execute(fn, typ, locals)
+}
+
+func (ins *wasmInstance) memoryAt(offset, size uint64) ([]byte, error) {
+	if offset+size < offset || offset+size > uint64(len(ins.memory)) {
+		return nil, errWasmOutOfBounds
+	}
+	return ins.memory[offset : offset+size], nil
+}
+
+//执行函数体
+func (ins *wasmInstance) execute(fn *wasmFunction, typ wasmFuncType, locals []uint64) ([]uint64, error) {
+
+	var (
+		r      = &wasmReader{data: fn.code}
+		stack  []uint64
+		labels []wasmLabel
+		err    error
+	)
+	pop := func() uint64 {
+		if len(stack) == 0 {
+			err = errWasmStackUnderflow
+			return 0
+		}
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return v
+	}
+	push := func(v uint64) {
+		stack = append(stack, v)
+	}
+	results := func() ([]uint64, error) {
+		n := len(typ.results)
+		if len(stack) < n {
+			return nil, errWasmStackUnderflow
+		}
+		return append([]uint64(nil), stack[len(stack)-n:]...), nil
+	}
+	//跳转到第depth层控制块，跳出函数体时返回true
+	branch := func(depth uint32) bool {
+		if int(depth) >= len(labels) {
+			return true
+		}
+		label := labels[len(labels)-1-int(depth)]
+		if len(stack) < label.height+label.arity {
+			err = errWasmStackUnderflow
+			return false
+		}
+		stack = append(stack[:label.height], stack[len(stack)-label.arity:]...)
+		labels = labels[:len(labels)-1-int(depth)]
+		r.pos = label.target
+		return false
+	}
+
+	for {
+		if atomic.LoadInt32(&ins.in.evm.abort) != 0 {
+			return nil, errWasmAborted
+		}
+		if !ins.contract.UseGas(wasmStepGas) {
+			return nil, ErrOutOfGas
+		}
+		pos := r.pos
+		op, e := r.byte()
+		if e != nil {
+			return nil, e
+		}
+
+		switch {
+		case op == 0x00: //unreachable
+			return nil, errWasmUnreachable
+		case op == 0x01: //nop
+		case op == 0x02 || op == 0x03: //block loop
+			bt, _ := r.byte()
+			label := wasmLabel{target: fn.blocks[pos].endPos + 1, height: len(stack), arity: wasmBlockArity(bt)}
+			if op == 0x03 {
+				label.target, label.arity = pos, 0
+			}
+			labels = append(labels, label)
+		case op == 0x04: //if
+			bt, _ := r.byte()
+			cond := uint32(pop())
+			block := fn.blocks[pos]
+			labels = append(labels, wasmLabel{target: block.endPos + 1, height: len(stack), arity: wasmBlockArity(bt)})
+			if cond == 0 {
+				if block.elsePos >= 0 {
+					r.pos = block.elsePos + 1
+				} else {
+					r.pos = block.endPos
+				}
+			}
+		case op == 0x05: //else，then分支执行结束后跳到end
+			r.pos = labels[len(labels)-1].target - 1
+		case op == 0x0b: //end
+			if len(labels) == 0 {
+				return results()
+			}
+			labels = labels[:len(labels)-1]
+		case op == 0x0c: //br
+			depth, _ := r.u32()
+			if branch(depth) {
+				return results()
+			}
+		case op == 0x0d: //br_if
+			depth, _ := r.u32()
+			if uint32(pop()) != 0 && err == nil && branch(depth) {
+				return results()
+			}
+		case op == 0x0e: //br_table
+			n, _ := r.u32()
+			targets := make([]uint32, n+1)
+			for i := range targets {
+				targets[i], _ = r.u32()
+			}
+			index := uint32(pop())
+			if index > n {
+				index = n
+			}
+			if err == nil && branch(targets[index]) {
+				return results()
+			}
+		case op == 0x0f: //return
+			return results()
+		case op == 0x10: //call
+			index, _ := r.u32()
+			callee, _ := ins.module.funcType(index)
+			if len(stack) < len(callee.params) {
+				return nil, errWasmStackUnderflow
+			}
+			args := append([]uint64(nil), stack[len(stack)-len(callee.params):]...)
+			stack = stack[:len(stack)-len(callee.params)]
+			ret, e := ins.call(index, args)
+			if e != nil {
+				return nil, e
+			}
+			stack = append(stack, ret...)
+		case op == 0x1a: //drop
+			pop()
+		case op == 0x1b: //select
+			c, b, a := uint32(pop()), pop(), pop()
+			if c != 0 {
+				push(a)
+			} else {
+				push(b)
+			}
+		case op >= 0x20 && op <= 0x22: //local.get local.set local.tee
+			index, _ := r.u32()
+			if index >= uint32(len(locals)) {
+				return nil, errWasmInvalidIndex
+			}
+			switch op {
+			case 0x20:
+				push(locals[index])
+			case 0x21:
+				locals[index] = pop()
+			case 0x22:
+				locals[index] = pop()
+				push(locals[index])
+			}
+		case op == 0x23: //global.get
+			index, _ := r.u32()
+			push(ins.globals[index])
+		case op == 0x24: //global.set
+			index, _ := r.u32()
+			ins.globals[index] = pop()
+		case op >= 0x28 && op <= 0x35: //load
+			r.u32()
+			offset, _ := r.u32()
+			size, signed, is32 := wasmLoadOp(op)
+			mem, e := ins.memoryAt(uint64(uint32(pop()))+uint64(offset), size)
+			if e != nil {
+				return nil, e
+			}
+			var v uint64
+			for i := int(size) - 1; i >= 0; i-- {
+				v = v<<8 | uint64(mem[i])
+			}
+			if signed && size < 8 {
+				shift := 64 - 8*size
+				v = uint64(int64(v<<shift) >> shift)
+			}
+			if is32 {
+				v = uint64(uint32(v))
+			}
+			push(v)
+		case op >= 0x36 && op <= 0x3e: //store
+			r.u32()
+			offset, _ := r.u32()
+			v := pop()
+			mem, e := ins.memoryAt(uint64(uint32(pop()))+uint64(offset), wasmStoreSize(op))
+			if e != nil {
+				return nil, e
+			}
+			for i := range mem {
+				mem[i] = byte(v >> (8 * uint(i)))
+			}
+		case op == 0x3f: //memory.size
+			r.byte()
+			push(uint64(len(ins.memory) / wasmPageSize))
+		case op == 0x40: //memory.grow
+			r.byte()
+			pages := uint32(pop())
+			current := uint32(len(ins.memory) / wasmPageSize)
+			if uint64(current)+uint64(pages) > uint64(ins.maxPages) {
+				push(uint64(math.MaxUint32))
+				break
+			}
+			if !ins.contract.UseGas(uint64(pages) * params.MemoryGas * (wasmPageSize / 32)) {
+				return nil, ErrOutOfGas
+			}
+			ins.memory = append(ins.memory, make([]byte, uint64(pages)*wasmPageSize)...)
+			push(uint64(current))
+		case op == 0x41: //i32.const
+			v, _ := r.sleb(32)
+			push(uint64(uint32(v)))
+		case op == 0x42: //i64.const
+			v, _ := r.sleb(64)
+			push(uint64(v))
+		case op == 0x45: //i32.eqz
+			push(wasmBool(uint32(pop()) == 0))
+		case op >= 0x46 && op <= 0x4f:
+			b, a := pop(), pop()
+			push(wasmBool(wasmCompare(op-0x46, int64(int32(a)), int64(int32(b)), uint64(uint32(a)), uint64(uint32(b)))))
+		case op == 0x50: //i64.eqz
+			push(wasmBool(pop() == 0))
+		case op >= 0x51 && op <= 0x5a:
+			b, a := pop(), pop()
+			push(wasmBool(wasmCompare(op-0x51, int64(a), int64(b), a, b)))
+		case op == 0x67: //i32.clz
+			push(uint64(bits.LeadingZeros32(uint32(pop()))))
+		case op == 0x68: //i32.ctz
+			push(uint64(bits.TrailingZeros32(uint32(pop()))))
+		case op == 0x69: //i32.popcnt
+			push(uint64(bits.OnesCount32(uint32(pop()))))
+		case op >= 0x6a && op <= 0x78:
+			b, a := uint32(pop()), uint32(pop())
+			v, e := wasmI32Binary(op-0x6a, a, b)
+			if e != nil {
+				return nil, e
+			}
+			push(uint64(v))
+		case op == 0x79: //i64.clz
+			push(uint64(bits.LeadingZeros64(pop())))
+		case op == 0x7a: //i64.ctz
+			push(uint64(bits.TrailingZeros64(pop())))
+		case op == 0x7b: //i64.popcnt
+			push(uint64(bits.OnesCount64(pop())))
+		case op >= 0x7c && op <= 0x8a:
+			b, a := pop(), pop()
+			v, e := wasmI64Binary(op-0x7c, a, b)
+			if e != nil {
+				return nil, e
+			}
+			push(v)
+		case op == 0xa7: //i32.wrap_i64
+			push(uint64(uint32(pop())))
+		case op == 0xac: //i64.extend_i32_s
+			push(uint64(int64(int32(pop()))))
+		case op == 0xad: //i64.extend_i32_u
+			push(uint64(uint32(pop())))
+		default:
+			return nil, errWasmMalformed
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func wasmBool(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+//读取内存指令的字节数、是否有符号以及结果是否为i32
+func wasmLoadOp(op byte) (uint64, bool, bool) {
+	switch op {
+	case 0x28:
+		return 4, false, true
+	case 0x29:
+		return 8, false, false
+	case 0x2c, 0x2d:
+		return 1, op == 0x2c, true
+	case 0x2e, 0x2f:
+		return 2, op == 0x2e, true
+	case 0x30, 0x31:
+		return 1, op == 0x30, false
+	case 0x32, 0x33:
+		return 2, op == 0x32, false
+	}
+	return 4, op == 0x34, false
+}
+
+//写入内存指令的字节数
+func wasmStoreSize(op byte) uint64 {
+	switch op {
+	case 0x36, 0x3e:
+		return 4
+	case 0x37:
+		return 8
+	case 0x3a, 0x3c:
+		return 1
+	}
+	return 2
+}
+
+//比较指令（顺序为eq ne lt_s lt_u gt_s gt_u le_s le_u ge_s ge_u）
+func wasmCompare(op byte, sa, sb int64, ua, ub uint64) bool {
+	switch op {
+	case 0:
+		return ua == ub
+	case 1:
+		return ua != ub
+	case 2:
+		return sa < sb
+	case 3:
+		return ua < ub
+	case 4:
+		return sa > sb
+	case 5:
+		return ua > ub
+	case 6:
+		return sa <= sb
+	case 7:
+		return ua <= ub
+	case 8:
+		return sa >= sb
+	}
+	return ua >= ub
+}
+
+//i32二元运算（顺序为add sub mul div_s div_u rem_s rem_u and or xor shl shr_s shr_u rotl rotr）
+func wasmI32Binary(op byte, a, b uint32) (uint32, error) {
+	switch op {
+	case 0:
+		return a + b, nil
+	case 1:
+		return a - b, nil
+	case 2:
+		return a * b, nil
+	case 3, 4, 5, 6:
+		if b == 0 {
+			return 0, errWasmDivideByZero
+		}
+		switch op {
+		case 3:
+			if int32(a) == math.MinInt32 && int32(b) == -1 {
+				return 0, errWasmIntegerOverflow
+			}
+			return uint32(int32(a) / int32(b)), nil
+		case 4:
+			return a / b, nil
+		case 5:
+			if int32(b) == -1 {
+				return 0, nil
+			}
+			return uint32(int32(a) % int32(b)), nil
+		}
+		return a % b, nil
+	case 7:
+		return a & b, nil
+	case 8:
+		return a | b, nil
+	case 9:
+		return a ^ b, nil
+	case 10:
+		return a << (b % 32), nil
+	case 11:
+		return uint32(int32(a) >> (b % 32)), nil
+	case 12:
+		return a >> (b % 32), nil
+	case 13:
+		return bits.RotateLeft32(a, int(b%32)), nil
+	}
+	return bits.RotateLeft32(a, -int(b%32)), nil
+}
+
+//i64二元运算（顺序同i32）
+func wasmI64Binary(op byte, a, b uint64) (uint64, error) {
+	switch op {
+	case 0:
+		return a + b, nil
+	case 1:
+		return a - b, nil
+	case 2:
+		return a * b, nil
+	case 3, 4, 5, 6:
+		if b == 0 {
+			return 0, errWasmDivideByZero
+		}
+		switch op {
+		case 3:
+			if int64(a) == math.MinInt64 && int64(b) == -1 {
+				return 0, errWasmIntegerOverflow
+			}
+			return uint64(int64(a) / int64(b)), nil
+		case 4:
+			return a / b, nil
+		case 5:
+			if int64(b) == -1 {
+				return 0, nil
+			}
+			return uint64(int64(a) % int64(b)), nil
+		}
+		return a % b, nil
+	case 7:
+		return a & b, nil
+	case 8:
+		return a | b, nil
+	case 9:
+		return a ^ b, nil
+	case 10:
+		return a << (b % 64), nil
+	case 11:
+		return uint64(int64(a) >> (b % 64)), nil
+	case 12:
+		return a >> (b % 64), nil
+	case 13:
+		return bits.RotateLeft64(a, int(b%64)), nil
+	}
+	return bits.RotateLeft64(a, -int(b%64)), nil
+}
+
+//ethereum模块中的主机函数
+type wasmHostFunc struct {
+	params  []byte
+	results []byte
+	fn      func(ins *wasmInstance, args []uint64) (uint64, error)
+}
+
+func wasmHost(params []byte, results []byte, fn func(ins *wasmInstance, args []uint64) (uint64, error)) *wasmHostFunc {
+	return &wasmHostFunc{params: params, results: results, fn: fn}
+}
+
+var (
+	wasmNone = []byte{}
+	wasmI32s = func(n int) []byte {
+		types := make([]byte, n)
+		for i := range types {
+			types[i] = wasmI32
+		}
+		return types
+	}
+)
+
+var wasmHostFuncs = map[string]*wasmHostFunc{
+	"useGas": wasmHost([]byte{wasmI64}, wasmNone, func(ins *wasmInstance, args []uint64) (uint64, error) {
+		if !ins.contract.UseGas(args[0]) {
+			return 0, ErrOutOfGas
+		}
+		return 0, nil
+	}),
+	"getGasLeft": wasmHost(wasmNone, []byte{wasmI64}, func(ins *wasmInstance, args []uint64) (uint64, error) {
+		return ins.contract.Gas, nil
+	}),
+	"getAddress": wasmHost(wasmI32s(1), wasmNone, func(ins *wasmInstance, args []uint64) (uint64, error) {
+		return 0, ins.write(args[0], ins.contract.Address().Bytes())
+	}),
+	"getCaller": wasmHost(wasmI32s(1), wasmNone, func(ins *wasmInstance, args []uint64) (uint64, error) {
+		return 0, ins.write(args[0], ins.contract.Caller().Bytes())
+	}),
+	"getCallValue": wasmHost(wasmI32s(1), wasmNone, func(ins *wasmInstance, args []uint64) (uint64, error) {
+		//128位小端序
+		value := make([]byte, 16)
+		for i, b := range common.LeftPadBytes(ins.contract.Value().Bytes(), 16) {
+			value[15-i] = b
+		}
+		return 0, ins.write(args[0], value)
+	}),
+	"getCallDataSize": wasmHost(wasmNone, wasmI32s(1), func(ins *wasmInstance, args []uint64) (uint64, error) {
+		return uint64(len(ins.input)), nil
+	}),
+	"callDataCopy": wasmHost(wasmI32s(3), wasmNone, func(ins *wasmInstance, args []uint64) (uint64, error) {
+		if !ins.contract.UseGas(params.CopyGas * toWordSize(args[2])) {
+			return 0, ErrOutOfGas
+		}
+		return 0, ins.write(args[0], getDataBig(ins.input, new(big.Int).SetUint64(args[1]), new(big.Int).SetUint64(args[2])))
+	}),
+	"storageStore": wasmHost(wasmI32s(2), wasmNone, func(ins *wasmInstance, args []uint64) (uint64, error) {
+		if ins.in.evm.interpreter.readOnly {
+			return 0, errWriteProtection
+		}
+		key, err := ins.memoryAt(args[0], 32)
+		if err != nil {
+			return 0, err
+		}
+		value, err := ins.memoryAt(args[1], 32)
+		if err != nil {
+			return 0, err
+		}
+		var (
+			address = ins.contract.Address()
+			current = ins.in.evm.StateDB.GetState(address, common.BytesToHash(key))
+			gas     = params.SstoreResetGas
+		)
+		if current == (common.Hash{}) && !allZero(value) {
+			gas = params.SstoreSetGas
+		} else if current != (common.Hash{}) && allZero(value) {
+			ins.in.evm.StateDB.AddRefund(new(big.Int).SetUint64(params.SstoreRefundGas))
+		}
+		if !ins.contract.UseGas(gas) {
+			return 0, ErrOutOfGas
+		}
+		ins.in.evm.StateDB.SetState(address, common.BytesToHash(key), common.BytesToHash(value))
+		return 0, nil
+	}),
+	"storageLoad": wasmHost(wasmI32s(2), wasmNone, func(ins *wasmInstance, args []uint64) (uint64, error) {
+		if !ins.contract.UseGas(ins.in.gasTable.SLoad) {
+			return 0, ErrOutOfGas
+		}
+		key, err := ins.memoryAt(args[0], 32)
+		if err != nil {
+			return 0, err
+		}
+		value := ins.in.evm.StateDB.GetState(ins.contract.Address(), common.BytesToHash(key))
+		return 0, ins.write(args[1], value.Bytes())
+	}),
+	"getBlockNumber": wasmHost(wasmNone, []byte{wasmI64}, func(ins *wasmInstance, args []uint64) (uint64, error) {
+		return ins.in.evm.BlockNumber.Uint64(), nil
+	}),
+	"getBlockTimestamp": wasmHost(wasmNone, []byte{wasmI64}, func(ins *wasmInstance, args []uint64) (uint64, error) {
+		return ins.in.evm.Time.Uint64(), nil
+	}),
+	"log": wasmHost(wasmI32s(7), wasmNone, func(ins *wasmInstance, args []uint64) (uint64, error) {
+		if ins.in.evm.interpreter.readOnly {
+			return 0, errWriteProtection
+		}
+		count := args[2]
+		if count > 4 {
+			return 0, errWasmInvalidIndex
+		}
+		if !ins.contract.UseGas(params.LogGas + count*params.LogTopicGas + args[1]*params.LogDataGas) {
+			return 0, ErrOutOfGas
+		}
+		data, err := ins.memoryAt(args[0], args[1])
+		if err != nil {
+			return 0, err
+		}
+		topics := make([]common.Hash, count)
+		for i := range topics {
+			topic, err := ins.memoryAt(args[3+i], 32)
+			if err != nil {
+				return 0, err
+			}
+			topics[i] = common.BytesToHash(topic)
+		}
+		ins.in.evm.StateDB.AddLog(&types.Log{
+			Address:     ins.contract.Address(),
+			Topics:      topics,
+			Data:        common.CopyBytes(data),
+			BlockNumber: ins.in.evm.BlockNumber.Uint64(),
+		})
+		return 0, nil
+	}),
+	"finish": wasmHost(wasmI32s(2), wasmNone, func(ins *wasmInstance, args []uint64) (uint64, error) {
+		data, err := ins.memoryAt(args[0], args[1])
+		if err != nil {
+			return 0, err
+		}
+		ins.output = common.CopyBytes(data)
+		return 0, errWasmFinish
+	}),
+	"revert": wasmHost(wasmI32s(2), wasmNone, func(ins *wasmInstance, args []uint64) (uint64, error) {
+		data, err := ins.memoryAt(args[0], args[1])
+		if err != nil {
+			return 0, err
+		}
+		ins.output = common.CopyBytes(data)
+		return 0, ErrExecutionReverted
+	}),
+}
+
+//将数据写入合约内存
+func (ins *wasmInstance) write(offset uint64, data []byte) error {
+	mem, err := ins.memoryAt(offset, uint64(len(data)))
+	if err != nil {
+		return err
+	}
+	copy(mem, data)
+	return nil
+}
@@ -0,0 +1,588 @@
+package vm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+//WASM模块魔数以及版本（合约代码以此开头时由WASM解释器执行）
+var wasmMagic = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+const (
+	wasmI32 byte = 0x7f
+	wasmI64 byte = 0x7e
+
+	wasmPageSize = 65536 //内存页大小
+	wasmMaxPages = 256   //合约最多使用的内存页数（16M）
+)
+
+var errWasmMalformed = errors.New("wasm: malformed module")
+
+//判断合约代码是否为WASM模块
+func IsWasmCode(code []byte) bool {
+	return bytes.HasPrefix(code, wasmMagic)
+}
+
+//函数签名
+type wasmFuncType struct {
+	params  []byte
+	results []byte
+}
+
+//控制块（block、loop、if）对应的else以及end位置
+type wasmBlock struct {
+	elsePos int //没有else时为-1
+	endPos  int
+}
+
+//模块中定义的函数
+type wasmFunction struct {
+	typ    uint32
+	locals []byte
+	code   []byte
+	blocks map[int]wasmBlock
+}
+
+//导入的函数（只支持ethereum模块中的主机函数）
+type wasmImport struct {
+	module string
+	name   string
+	typ    uint32
+}
+
+type wasmGlobal struct {
+	typ     byte
+	mutable bool
+	init    uint64
+}
+
+type wasmData struct {
+	offset uint32
+	init   []byte
+}
+
+//解析后的WASM模块
+type wasmModule struct {
+	types     []wasmFuncType
+	imports   []wasmImport
+	funcs     []*wasmFunction
+	memoryMin uint32
+	memoryMax uint32
+	globals   []wasmGlobal
+	exports   map[string]uint32
+	data      []wasmData
+}
+
+//函数索引对应的签名（导入的函数在前）
+func (m *wasmModule) funcType(index uint32) (wasmFuncType, bool) {
+	if index < uint32(len(m.imports)) {
+		return m.types[m.imports[index].typ], true
+	}
+	index -= uint32(len(m.imports))
+	if index >= uint32(len(m.funcs)) {
+		return wasmFuncType{}, false
+	}
+	return m.types[m.funcs[index].typ], true
+}
+
+type wasmReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *wasmReader) byte() (byte, error) {
+	if r.pos >= len(r.data) {
+		return 0, errWasmMalformed
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *wasmReader) bytes(n uint32) ([]byte, error) {
+	if uint64(r.pos)+uint64(n) > uint64(len(r.data)) {
+		return nil, errWasmMalformed
+	}
+	b := r.data[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b, nil
+}
+
+//读取LEB128编码的无符号整数
+func (r *wasmReader) uleb(bits uint) (uint64, error) {
+	var (
+		result uint64
+		shift  uint
+	)
+	for {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		result |= uint64(b&0x7f) << shift
+		shift += 7
+		if b&0x80 == 0 {
+			if shift < 64 && result>>bits != 0 {
+				return 0, errWasmMalformed
+			}
+			return result, nil
+		}
+		if shift >= bits+7 {
+			return 0, errWasmMalformed
+		}
+	}
+}
+
+//读取LEB128编码的有符号整数
+func (r *wasmReader) sleb(bits uint) (int64, error) {
+	var (
+		result int64
+		shift  uint
+		b      byte
+		err    error
+	)
+	for {
+		if b, err = r.byte(); err != nil {
+			return 0, err
+		}
+		result |= int64(b&0x7f) << shift
+		shift += 7
+		if b&0x80 == 0 {
+			break
+		}
+		if shift >= bits+7 {
+			return 0, errWasmMalformed
+		}
+	}
+	if shift < 64 && b&0x40 != 0 {
+		result |= -1 << shift
+	}
+	return result, nil
+}
+
+func (r *wasmReader) u32() (uint32, error) {
+	v, err := r.uleb(32)
+	return uint32(v), err
+}
+
+func (r *wasmReader) name() (string, error) {
+	n, err := r.u32()
+	if err != nil {
+		return "", err
+	}
+	b, err := r.bytes(n)
+	return string(b), err
+}
+
+func (r *wasmReader) valueTypes() ([]byte, error) {
+	n, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	types, err := r.bytes(n)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range types {
+		if t != wasmI32 && t != wasmI64 {
+			return nil, fmt.Errorf("wasm: unsupported value type 0x%x", t)
+		}
+	}
+	return types, nil
+}
+
+//读取常量表达式（只支持i32.const、i64.const）
+func (r *wasmReader) constExpr() (byte, uint64, error) {
+	op, err := r.byte()
+	if err != nil {
+		return 0, 0, err
+	}
+	var (
+		typ   byte
+		value int64
+	)
+	switch op {
+	case 0x41:
+		typ = wasmI32
+		value, err = r.sleb(32)
+		value = int64(uint32(value))
+	case 0x42:
+		typ = wasmI64
+		value, err = r.sleb(64)
+	default:
+		return 0, 0, fmt.Errorf("wasm: unsupported constant expression 0x%x", op)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	if end, err := r.byte(); err != nil || end != 0x0b {
+		return 0, 0, errWasmMalformed
+	}
+	return typ, uint64(value), nil
+}
+
+//解析WASM模块，只支持整数运算、单个线性内存以及ethereum主机函数
+func parseWasmModule(code []byte) (*wasmModule, error) {
+
+	if !IsWasmCode(code) {
+		return nil, errWasmMalformed
+	}
+	var (
+		r         = &wasmReader{data: code, pos: len(wasmMagic)}
+		m         = &wasmModule{exports: make(map[string]uint32)}
+		funcTypes []uint32
+		last      byte
+	)
+	for r.pos < len(r.data) {
+
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		payload, err := r.bytes(size)
+		if err != nil {
+			return nil, err
+		}
+		if id == 0 {
+			continue
+		}
+		if id <= last {
+			return nil, errWasmMalformed
+		}
+		last = id
+
+		s := &wasmReader{data: payload}
+		count, err := s.u32()
+		if err != nil {
+			return nil, err
+		}
+		switch id {
+		case 1: //类型
+			for i := uint32(0); i < count; i++ {
+				if form, err := s.byte(); err != nil || form != 0x60 {
+					return nil, errWasmMalformed
+				}
+				params, err := s.valueTypes()
+				if err != nil {
+					return nil, err
+				}
+				results, err := s.valueTypes()
+				if err != nil {
+					return nil, err
+				}
+				if len(results) > 1 {
+					return nil, errors.New("wasm: multiple results are not supported")
+				}
+				m.types = append(m.types, wasmFuncType{params: params, results: results})
+			}
+		case 2: //导入
+			for i := uint32(0); i < count; i++ {
+				module, err := s.name()
+				if err != nil {
+					return nil, err
+				}
+				name, err := s.name()
+				if err != nil {
+					return nil, err
+				}
+				if kind, err := s.byte(); err != nil || kind != 0 {
+					return nil, errors.New("wasm: only function imports are supported")
+				}
+				typ, err := s.u32()
+				if err != nil {
+					return nil, err
+				}
+				if typ >= uint32(len(m.types)) {
+					return nil, errWasmMalformed
+				}
+				m.imports = append(m.imports, wasmImport{module: module, name: name, typ: typ})
+			}
+		case 3: //函数
+			for i := uint32(0); i < count; i++ {
+				typ, err := s.u32()
+				if err != nil {
+					return nil, err
+				}
+				if typ >= uint32(len(m.types)) {
+					return nil, errWasmMalformed
+				}
+				funcTypes = append(funcTypes, typ)
+			}
+		case 5: //内存
+			if count > 1 {
+				return nil, errWasmMalformed
+			}
+			if count == 1 {
+				flags, err := s.byte()
+				if err != nil {
+					return nil, err
+				}
+				if m.memoryMin, err = s.u32(); err != nil {
+					return nil, err
+				}
+				m.memoryMax = wasmMaxPages
+				if flags == 1 {
+					if m.memoryMax, err = s.u32(); err != nil {
+						return nil, err
+					}
+				}
+				if m.memoryMax > wasmMaxPages {
+					m.memoryMax = wasmMaxPages
+				}
+				if m.memoryMin > m.memoryMax {
+					return nil, errors.New("wasm: memory too large")
+				}
+			}
+		case 6: //全局变量
+			for i := uint32(0); i < count; i++ {
+				typ, err := s.byte()
+				if err != nil {
+					return nil, err
+				}
+				mutable, err := s.byte()
+				if err != nil {
+					return nil, err
+				}
+				init, value, err := s.constExpr()
+				if err != nil {
+					return nil, err
+				}
+				if init != typ {
+					return nil, errWasmMalformed
+				}
+				m.globals = append(m.globals, wasmGlobal{typ: typ, mutable: mutable == 1, init: value})
+			}
+		case 7: //导出
+			for i := uint32(0); i < count; i++ {
+				name, err := s.name()
+				if err != nil {
+					return nil, err
+				}
+				kind, err := s.byte()
+				if err != nil {
+					return nil, err
+				}
+				index, err := s.u32()
+				if err != nil {
+					return nil, err
+				}
+				if kind == 0 {
+					m.exports[name] = index
+				}
+			}
+		case 10: //代码
+			if count != uint32(len(funcTypes)) {
+				return nil, errWasmMalformed
+			}
+			for i := uint32(0); i < count; i++ {
+				size, err := s.u32()
+				if err != nil {
+					return nil, err
+				}
+				body, err := s.bytes(size)
+				if err != nil {
+					return nil, err
+				}
+				fn, err := parseWasmFunction(body)
+				if err != nil {
+					return nil, err
+				}
+				fn.typ = funcTypes[i]
+				m.funcs = append(m.funcs, fn)
+			}
+		case 11: //数据
+			for i := uint32(0); i < count; i++ {
+				if index, err := s.u32(); err != nil || index != 0 {
+					return nil, errWasmMalformed
+				}
+				typ, offset, err := s.constExpr()
+				if err != nil {
+					return nil, err
+				}
+				if typ != wasmI32 {
+					return nil, errWasmMalformed
+				}
+				size, err := s.u32()
+				if err != nil {
+					return nil, err
+				}
+				init, err := s.bytes(size)
+				if err != nil {
+					return nil, err
+				}
+				m.data = append(m.data, wasmData{offset: uint32(offset), init: init})
+			}
+		default:
+			return nil, fmt.Errorf("wasm: unsupported section %d", id)
+		}
+		if s.pos != len(s.data) {
+			return nil, errWasmMalformed
+		}
+	}
+	if len(funcTypes) != len(m.funcs) {
+		return nil, errWasmMalformed
+	}
+	return m, m.validate()
+}
+
+//检查函数调用、全局变量以及数据段的索引
+func (m *wasmModule) validate() error {
+
+	for _, fn := range m.funcs {
+		r := &wasmReader{data: fn.code}
+		for r.pos < len(r.data) {
+			op, _ := r.byte()
+			switch op {
+			case 0x10:
+				index, _ := r.u32()
+				if _, ok := m.funcType(index); !ok {
+					return errWasmMalformed
+				}
+			case 0x23, 0x24:
+				index, _ := r.u32()
+				if index >= uint32(len(m.globals)) || (op == 0x24 && !m.globals[index].mutable) {
+					return errWasmMalformed
+				}
+			default:
+				r.pos--
+				if err := skipWasmInstruction(r); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, data := range m.data {
+		if uint64(data.offset)+uint64(len(data.init)) > uint64(m.memoryMin)*wasmPageSize {
+			return errWasmMalformed
+		}
+	}
+	return nil
+}
+
+//解析函数体，记录控制块的else以及end位置
+func parseWasmFunction(body []byte) (*wasmFunction, error) {
+
+	r := &wasmReader{data: body}
+	groups, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	fn := &wasmFunction{blocks: make(map[int]wasmBlock)}
+	for i := uint32(0); i < groups; i++ {
+		n, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		typ, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if typ != wasmI32 && typ != wasmI64 {
+			return nil, fmt.Errorf("wasm: unsupported value type 0x%x", typ)
+		}
+		if uint64(len(fn.locals))+uint64(n) > 1024 {
+			return nil, errors.New("wasm: too many locals")
+		}
+		for j := uint32(0); j < n; j++ {
+			fn.locals = append(fn.locals, typ)
+		}
+	}
+	fn.code = body[r.pos:]
+
+	code := &wasmReader{data: fn.code}
+	var open []int
+	for code.pos < len(code.data) {
+		pos := code.pos
+		op, _ := code.byte()
+		switch op {
+		case 0x02, 0x03, 0x04:
+			open = append(open, pos)
+			fn.blocks[pos] = wasmBlock{elsePos: -1}
+		case 0x05:
+			if len(open) == 0 || fn.code[open[len(open)-1]] != 0x04 {
+				return nil, errWasmMalformed
+			}
+			block := fn.blocks[open[len(open)-1]]
+			block.elsePos = pos
+			fn.blocks[open[len(open)-1]] = block
+		case 0x0b:
+			if len(open) == 0 {
+				//函数体结束
+				if code.pos != len(code.data) {
+					return nil, errWasmMalformed
+				}
+				return fn, nil
+			}
+			block := fn.blocks[open[len(open)-1]]
+			block.endPos = pos
+			fn.blocks[open[len(open)-1]] = block
+			open = open[:len(open)-1]
+		}
+		code.pos--
+		if err := skipWasmInstruction(code); err != nil {
+			return nil, err
+		}
+	}
+	return nil, errWasmMalformed
+}
+
+//跳过一条指令（包括立即数），不支持的指令返回错误
+func skipWasmInstruction(r *wasmReader) error {
+
+	op, err := r.byte()
+	if err != nil {
+		return err
+	}
+	switch {
+	case op == 0x00 || op == 0x01 || op == 0x05 || op == 0x0b || op == 0x0f || op == 0x1a || op == 0x1b:
+		return nil
+	case op >= 0x02 && op <= 0x04:
+		typ, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if typ != 0x40 && typ != wasmI32 && typ != wasmI64 {
+			return errWasmMalformed
+		}
+		return nil
+	case op == 0x0c || op == 0x0d || op == 0x10 || (op >= 0x20 && op <= 0x24):
+		_, err = r.u32()
+		return err
+	case op == 0x0e:
+		n, err := r.u32()
+		if err != nil {
+			return err
+		}
+		for i := uint32(0); i <= n; i++ {
+			if _, err := r.u32(); err != nil {
+				return err
+			}
+		}
+		return nil
+	case (op >= 0x28 && op <= 0x29) || (op >= 0x2c && op <= 0x37) || (op >= 0x3a && op <= 0x3e):
+		if _, err := r.u32(); err != nil {
+			return err
+		}
+		_, err = r.u32()
+		return err
+	case op == 0x3f || op == 0x40:
+		if b, err := r.byte(); err != nil || b != 0 {
+			return errWasmMalformed
+		}
+		return nil
+	case op == 0x41:
+		_, err = r.sleb(32)
+		return err
+	case op == 0x42:
+		_, err = r.sleb(64)
+		return err
+	case (op >= 0x45 && op <= 0x5a) || (op >= 0x67 && op <= 0x8a) || op == 0xa7 || op == 0xac || op == 0xad:
+		return nil
+	}
+	return fmt.Errorf("wasm: unsupported instruction 0x%x", op)
+}
@@ -0,0 +1,192 @@
+package vm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+func wasmSection(id byte, payload ...byte) []byte {
+	section := []byte{id}
+	for size := len(payload); ; size >>= 7 {
+		if size < 0x80 {
+			section = append(section, byte(size))
+			break
+		}
+		section = append(section, byte(size)|0x80)
+	}
+	return append(section, payload...)
+}
+
+func wasmModuleCode(sections ...[]byte) []byte {
+	code := append([]byte{}, wasmMagic...)
+	for _, section := range sections {
+		code = append(code, section...)
+	}
+	return code
+}
+
+func wasmTestEVM(wasm bool) (*EVM, *state.StateDB) {
+	db, _ := ethdb.NewMemDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	config := *params.TestChainConfig
+	if wasm {
+		config.WasmBlock = big.NewInt(0)
+	}
+	ctx := Context{
+		CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+		Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+		BlockNumber: big.NewInt(1),
+		Time:        big.NewInt(0),
+		GasLimit:    big.NewInt(10000000),
+	}
+	return NewEVM(ctx, statedb, &config, Config{}), statedb
+}
+
+func TestWasmLoop(t *testing.T) {
+	//计算5的阶乘并通过finish返回
+	body := []byte{2, 1, 0x7e, 1, 0x7f,
+		0x42, 1, 0x21, 0,
+		0x41, 5, 0x21, 1,
+		0x03, 0x40,
+		0x20, 0, 0x20, 1, 0xad, 0x7e, 0x21, 0,
+		0x20, 1, 0x41, 1, 0x6b, 0x22, 1, 0x0d, 0,
+		0x0b,
+		0x41, 0, 0x20, 0, 0x37, 3, 0,
+		0x41, 0, 0x41, 8, 0x10, 0,
+		0x0b}
+	code := wasmModuleCode(
+		wasmSection(1, 2, 0x60, 2, 0x7f, 0x7f, 0, 0x60, 0, 0),
+		wasmSection(2, append(append([]byte{1, 8}, "ethereum"...), append(append([]byte{6}, "finish"...), 0, 0)...)...),
+		wasmSection(3, 1, 1),
+		wasmSection(5, 1, 0, 1),
+		wasmSection(7, append(append([]byte{1, 4}, "main"...), 0, 1)...),
+		wasmSection(10, append([]byte{1, byte(len(body))}, body...)...),
+	)
+	address := common.HexToAddress("0xc0de")
+	evm, statedb := wasmTestEVM(true)
+	statedb.SetCode(address, code)
+
+	ret, _, err := evm.Call(AccountRef(common.Address{}), address, nil, 100000, new(big.Int))
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if len(ret) != 8 || binary.LittleEndian.Uint64(ret) != 120 {
+		t.Fatalf("result mismatch: have %x, want 120", ret)
+	}
+
+	//未开启WASM时由EVM执行（首字节为STOP）
+	evm, statedb = wasmTestEVM(false)
+	statedb.SetCode(address, code)
+	if ret, _, err = evm.Call(AccountRef(common.Address{}), address, nil, 100000, new(big.Int)); err != nil || len(ret) != 0 {
+		t.Fatalf("evm execution mismatch: ret %x, err %v", ret, err)
+	}
+}
+
+func TestWasmStorage(t *testing.T) {
+	//没有输入时revert，否则将输入的前32字节写入0号存储并读出返回
+	imp := func(name string, typ byte) []byte {
+		return append(append(append([]byte{8}, "ethereum"...), byte(len(name))), append([]byte(name), 0, typ)...)
+	}
+	imports := []byte{6}
+	for _, i := range [][]byte{imp("getCallDataSize", 0), imp("revert", 1), imp("callDataCopy", 2), imp("storageStore", 1), imp("storageLoad", 1), imp("finish", 1)} {
+		imports = append(imports, i...)
+	}
+	body := []byte{0,
+		0x10, 0, 0x45, 0x04, 0x40,
+		0x41, 0, 0x41, 0, 0x10, 1,
+		0x05,
+		0x41, 0, 0x41, 0, 0x41, 32, 0x10, 2,
+		0x41, 32, 0x41, 0, 0x10, 3,
+		0x41, 32, 0x41, 0xc0, 0, 0x10, 4,
+		0x41, 0xc0, 0, 0x41, 32, 0x10, 5,
+		0x0b,
+		0x0b}
+	code := wasmModuleCode(
+		wasmSection(1, 4, 0x60, 0, 1, 0x7f, 0x60, 2, 0x7f, 0x7f, 0, 0x60, 3, 0x7f, 0x7f, 0x7f, 0, 0x60, 0, 0),
+		wasmSection(2, imports...),
+		wasmSection(3, 1, 3),
+		wasmSection(5, 1, 0, 1),
+		wasmSection(7, append(append([]byte{1, 4}, "main"...), 0, 6)...),
+		wasmSection(10, append([]byte{1, byte(len(body))}, body...)...),
+	)
+	address := common.HexToAddress("0xc0de")
+	evm, statedb := wasmTestEVM(true)
+	statedb.SetCode(address, code)
+
+	if _, _, err := evm.Call(AccountRef(common.Address{}), address, nil, 100000, new(big.Int)); err != ErrExecutionReverted {
+		t.Fatalf("empty input error mismatch: have %v, want %v", err, ErrExecutionReverted)
+	}
+	input := common.HexToHash("0x1234").Bytes()
+	ret, _, err := evm.Call(AccountRef(common.Address{}), address, input, 100000, new(big.Int))
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if !bytes.Equal(ret, input) {
+		t.Fatalf("result mismatch: have %x, want %x", ret, input)
+	}
+	if value := statedb.GetState(address, common.Hash{}); value != common.BytesToHash(input) {
+		t.Fatalf("storage mismatch: have %x, want %x", value, input)
+	}
+}
+
+func TestWasmMalformed(t *testing.T) {
+	//浮点指令不被支持
+	code := wasmModuleCode(
+		wasmSection(1, 1, 0x60, 0, 0),
+		wasmSection(3, 1, 0),
+		wasmSection(7, append(append([]byte{1, 4}, "main"...), 0, 0)...),
+		wasmSection(10, 1, 8, 0, 0x43, 0, 0, 0, 0, 0x1a, 0x0b),
+	)
+	if _, err := parseWasmModule(code); err == nil {
+		t.Fatal("expected float instruction to be rejected")
+	}
+}
+
+// Tests that loading a module charges for the code and the initial memory
+// before allocating it, and that parsed modules are cached by code hash.
+func TestWasmLoadGas(t *testing.T) {
+	newCode := func(pages ...byte) []byte {
+		return wasmModuleCode(
+			wasmSection(1, 1, 0x60, 0, 0),
+			wasmSection(3, 1, 0),
+			wasmSection(5, append([]byte{1, 0}, pages...)...),
+			wasmSection(7, append(append([]byte{1, 4}, "main"...), 0, 0)...),
+			wasmSection(10, 1, 2, 0, 0x0b),
+		)
+	}
+	address := common.HexToAddress("0xc0de")
+
+	//200页初始内存需要的Gas超过调用提供的Gas
+	large := newCode(0xc8, 0x01)
+	evm, statedb := wasmTestEVM(true)
+	statedb.SetCode(address, large)
+	if _, _, err := evm.Call(AccountRef(common.Address{}), address, nil, 100000, new(big.Int)); err != ErrOutOfGas {
+		t.Fatalf("large memory error mismatch: have %v, want %v", err, ErrOutOfGas)
+	}
+
+	small := newCode(1)
+	evm, statedb = wasmTestEVM(true)
+	statedb.SetCode(address, small)
+	_, left, err := evm.Call(AccountRef(common.Address{}), address, nil, 100000, new(big.Int))
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	want := uint64(len(small))*wasmParseGas + params.MemoryGas*(wasmPageSize/32) + wasmStepGas
+	if used := 100000 - left; used != want {
+		t.Errorf("gas mismatch: have %d, want %d", used, want)
+	}
+	if !wasmModules.Contains(statedb.GetCodeHash(address)) {
+		t.Errorf("parsed module not cached")
+	}
+	//缓存命中时收取的Gas不变
+	if _, again, err := evm.Call(AccountRef(common.Address{}), address, nil, 100000, new(big.Int)); err != nil || again != left {
+		t.Errorf("cached call mismatch: have %d, %v, want %d", again, err, left)
+	}
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+//投票合约voteCandidate(address)方法的选择器
+var voteCandidateSelector = crypto.Keccak256([]byte("voteCandidate(address)"))[:4]
+
+//投票权重分叉之后记录投票交易(投票数量为交易转入投票合约的数量)
+func recordVote(dposContext *types.DposContext, msg types.Message, epoch uint64) error {
+
+	data := msg.Data()
+	if len(data) != len(voteCandidateSelector)+common.HashLength || !bytes.Equal(data[:len(voteCandidateSelector)], voteCandidateSelector) {
+		return protocol.ErrInvalidVote
+	}
+	candidate := common.BytesToAddress(data[len(voteCandidateSelector):])
+	return dposContext.AddVoteRecord(msg.From(), candidate, msg.Value(), epoch)
+}
+
+//按投票记录的权重计票并选出验证者(得票相同时按地址排序)，没有有效投票时保留当前的验证者
+func electByVoteWeight(config *params.VoteWeightConfig, dposContext *types.DposContext, statedb *state.StateDB, epoch uint64) error {
+
+	voters, records, err := dposContext.VoteRecords()
+	if err != nil {
+		return err
+	}
+	var (
+		candidates []common.Address
+		tallies    = make(map[common.Address]*big.Int)
+	)
+	for i, record := range records {
+		age := uint64(0)
+		if epoch > record.Epoch {
+			age = epoch - record.Epoch
+		}
+		weight := config.Weight(record.Amount, statedb.GetBalance(voters[i]), age)
+		if weight.Sign() <= 0 {
+			continue
+		}
+		tally, ok := tallies[record.Candidate]
+		if !ok {
+			tally = new(big.Int)
+			tallies[record.Candidate] = tally
+			candidates = append(candidates, record.Candidate)
+		}
+		tally.Add(tally, weight)
+	}
+	if len(candidates) == 0 {
+		log.Warn("No weighted votes, keeping the current validators", "epoch", epoch)
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if cmp := tallies[candidates[i]].Cmp(tallies[candidates[j]]); cmp != 0 {
+			return cmp > 0
+		}
+		return bytes.Compare(candidates[i].Bytes(), candidates[j].Bytes()) < 0
+	})
+	if len(candidates) > protocol.MaxValidatorSize {
+		candidates = candidates[:protocol.MaxValidatorSize]
+	}
+	votes := make([]*big.Int, len(candidates))
+	for i, candidate := range candidates {
+		votes[i] = tallies[candidate]
+	}
+	log.Info("Elected validators by vote weight", "epoch", epoch, "validators", len(candidates))
+	return dposContext.SetWeightedValidatorVotes(candidates, votes)
+}
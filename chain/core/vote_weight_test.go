@@ -0,0 +1,96 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+// voteMsg creates a voteCandidate call of the given amount.
+func voteMsg(voter, candidate common.Address, amount int64) types.Message {
+	data := append(common.CopyBytes(voteCandidateSelector), common.LeftPadBytes(candidate.Bytes(), 32)...)
+	return types.NewMessage(voter, &common.Address{}, 0, big.NewInt(amount), new(big.Int), new(big.Int), nil, data, nil, nil, false, protocol.SystemBase, protocol.VoteUser)
+}
+
+// Tests that votes are recorded per voter, and that the election tallies them
+// by the configured weight, dropping votes that were not cast again in time.
+func TestElectByVoteWeight(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	dposContext, err := types.NewDposContext(db)
+	if err != nil {
+		t.Fatalf("failed to create dpos context: %v", err)
+	}
+	current := common.HexToAddress("0x01")
+	dposContext.SetEpochTrie([]common.Address{current})
+
+	var (
+		alice, bob, carol = common.HexToAddress("0xa1"), common.HexToAddress("0xb0"), common.HexToAddress("0xc0")
+		early, late       = common.HexToAddress("0xe1"), common.HexToAddress("0xe2")
+	)
+	votes := []struct {
+		msg   types.Message
+		epoch uint64
+	}{
+		{voteMsg(alice, early, 100), 1},
+		{voteMsg(bob, late, 60), 3},
+		{voteMsg(bob, late, 10), 4},   // same candidate: amounts add up
+		{voteMsg(carol, early, 5), 2}, // replaced below
+		{voteMsg(carol, late, 20), 4},
+	}
+	for i, vote := range votes {
+		if err := recordVote(dposContext, vote.msg, vote.epoch); err != nil {
+			t.Fatalf("vote %d: failed to record: %v", i, err)
+		}
+	}
+	if err := recordVote(dposContext, types.NewMessage(alice, nil, 0, big.NewInt(1), new(big.Int), new(big.Int), nil, []byte{1, 2, 3}, nil, nil, false, protocol.SystemBase, protocol.VoteUser), 4); err != protocol.ErrInvalidVote {
+		t.Fatalf("malformed vote: have %v, want %v", err, protocol.ErrInvalidVote)
+	}
+	if record, _ := dposContext.GetVoteRecord(carol); record == nil || record.Candidate != late || record.Amount.Int64() != 20 || record.Epoch != 4 {
+		t.Fatalf("carol record mismatch: %+v", record)
+	}
+	statedb.AddBalance(alice, big.NewInt(500))
+
+	tests := []struct {
+		config *params.VoteWeightConfig
+		epoch  uint64
+		winner common.Address
+		votes  int64
+	}{
+		// Snapshot weight: early 100, late 90
+		{&params.VoteWeightConfig{Mode: params.VoteWeightSnapshot}, 4, early, 100},
+		// Live weight: early 500 (alice balance), late 0
+		{&params.VoteWeightConfig{Mode: params.VoteWeightLive}, 4, early, 500},
+		// Halving per epoch: early 12, late 90
+		{&params.VoteWeightConfig{Mode: params.VoteWeightSnapshot, DecayPercent: 50}, 4, late, 90},
+		// Votes older than 3 epochs expire
+		{&params.VoteWeightConfig{Mode: params.VoteWeightSnapshot, RevoteEpochs: 3}, 4, late, 90},
+	}
+	for i, tt := range tests {
+		if err := electByVoteWeight(tt.config, dposContext, statedb, tt.epoch); err != nil {
+			t.Fatalf("test %d: failed to elect: %v", i, err)
+		}
+		validators, _ := dposContext.GetEpochTrie()
+		if len(validators) != protocol.MaxValidatorSize || validators[0] != tt.winner {
+			t.Errorf("test %d: validators mismatch: have %x, want %x", i, validators, tt.winner)
+		}
+		if have := dposContext.GetValidatorVotes(tt.winner); have.Int64() != tt.votes {
+			t.Errorf("test %d: votes mismatch: have %v, want %d", i, have, tt.votes)
+		}
+	}
+
+	// Without any valid vote the current validators are kept
+	expired := &params.VoteWeightConfig{Mode: params.VoteWeightSnapshot, RevoteEpochs: 1}
+	if err := electByVoteWeight(expired, dposContext, statedb, 10); err != nil {
+		t.Fatalf("failed to elect: %v", err)
+	}
+	if validators, _ := dposContext.GetEpochTrie(); len(validators) != 1 || validators[0] != late {
+		t.Errorf("validators changed without votes: %x", validators)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"math/big"
 	"os"
 	"strings"
@@ -16,6 +17,8 @@ import (
 	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/consensus"
+	"github.com/Tinachain/Tina/chain/consensus/dpos"
 	"github.com/Tinachain/Tina/chain/core"
 	"github.com/Tinachain/Tina/chain/core/state"
 	"github.com/Tinachain/Tina/chain/core/types"
@@ -179,6 +182,162 @@ func (api *PrivateMinerAPI) GetHashrate() uint64 {
 	return uint64(api.e.miner.HashRate())
 }
 
+//出块节点的运行状态
+type MinerStatus struct {
+	Mining         bool           `json:"mining"`         //是否正在出块
+	Coinbase       common.Address `json:"coinbase"`       //出块账号
+	InValidatorSet bool           `json:"inValidatorSet"` //是否在当前验证者集合中
+	NextSlot       *hexutil.Big   `json:"nextSlot"`       //本节点下一个出块时间（当前周期内，无则为空）
+	LastBlock      *hexutil.Big   `json:"lastBlock"`      //本节点最后产生的区块号（无则为空）
+	LastBlockHash  *common.Hash   `json:"lastBlockHash"`  //本节点最后产生的区块哈希
+	LastBlockTime  *hexutil.Big   `json:"lastBlockTime"`  //本节点最后产生的区块时间
+}
+
+//得到当前节点的出块状态，便于外部系统在不重启进程的情况下管理验证者轮换
+func (api *PrivateMinerAPI) Status() (*MinerStatus, error) {
+
+	chain := api.e.BlockChain()
+	current := chain.CurrentBlock()
+	if current == nil {
+		return nil, ErrCurrentBlock
+	}
+	genesis := chain.GetBlockByNumber(0)
+	if genesis == nil {
+		return nil, ErrBlockChain
+	}
+	status := &MinerStatus{Mining: api.e.IsMining()}
+
+	coinbase, err := api.e.Coinbase()
+	if err != nil {
+		return status, nil
+	}
+	status.Coinbase = coinbase
+
+	//判断是否在当前验证者集合中，并计算本周期内的下一个出块时间
+	dposContext, err := types.NewDposContextFromProto(api.e.ChainDb(), current.Header().DposProto)
+	if err != nil {
+		return nil, err
+	}
+	status.InValidatorSet = dposContext.IsValidator(coinbase)
+	if status.InValidatorSet {
+		status.NextSlot = nextProducerSlot(dposContext, coinbase, genesis.Time().Int64(), time.Now().Unix())
+	}
+
+	if header := lastProducedHeader(chain, current.Header(), coinbase); header != nil {
+		hash := header.Hash()
+		status.LastBlock = (*hexutil.Big)(header.Number)
+		status.LastBlockHash = &hash
+		status.LastBlockTime = (*hexutil.Big)(header.Time)
+	}
+	return status, nil
+}
+
+//计算验证者在当前周期内从now开始的下一个出块时间，无则返回nil
+func nextProducerSlot(dposContext *types.DposContext, validator common.Address, firstTimer, now int64) *hexutil.Big {
+
+	slot := dpos.NextSlot(now-firstTimer) + firstTimer
+	for i := int64(0); i < protocol.EpochInterval/protocol.BlockInterval; i++ {
+		producer, err := dposContext.GetCurrentNowProducer(firstTimer, slot)
+		if err != nil {
+			return nil
+		}
+		if producer == validator {
+			return (*hexutil.Big)(big.NewInt(slot))
+		}
+		slot += protocol.BlockInterval
+	}
+	return nil
+}
+
+//在最近一个周期的区块中查找验证者最后产生的区块头，无则返回nil
+func lastProducedHeader(chain consensus.ChainReader, header *types.Header, validator common.Address) *types.Header {
+
+	for i := int64(0); header != nil && header.Number.Sign() > 0 && i < protocol.EpochInterval/protocol.BlockInterval; i++ {
+		if header.Validator == validator {
+			return header
+		}
+		header = chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	}
+	return nil
+}
+
+//汇总出块账号在区块范围内的出块奖励、Gas费用、放入股权Gas池的数量以及得到的股权分红，便于运维人员对账
+func (api *PrivateMinerAPI) GetEarnings(ctx context.Context, address common.Address, fromBlock, toBlock rpc.BlockNumber) (*Earnings, error) {
+	return api.e.earnings(ctx, address, fromBlock, toBlock)
+}
+
+//公开的Dpos网络状态API
+type PublicDposAPI struct {
+	e *Ethereum
+}
+
+func NewPublicDposAPI(e *Ethereum) *PublicDposAPI {
+	return &PublicDposAPI{e: e}
+}
+
+//得到当前周期验证者的在线情况(根据验证者广播的心跳), 用于区分验证者离线和网络分区
+func (api *PublicDposAPI) GetValidatorLiveness() ([]*ValidatorLiveness, error) {
+	return api.e.ValidatorLiveness()
+}
+
+//得到指定区块的投票快照(全部投票记录以及每个候选者的汇总)，可以作为链下分红或者治理的登记日数据
+func (api *PublicDposAPI) GetVotesAt(blockNr rpc.BlockNumber) (*VoteSnapshot, error) {
+	return api.e.votesAt(blockNr)
+}
+
+//得到本地时钟相对于NTP服务器以及其它验证者的偏差估计
+func (api *PublicDposAPI) GetClockDrift() *ClockDrift {
+	return api.e.clock.Drift()
+}
+
+//提供给验证者运维人员使用的Dpos调试API
+type PrivateDposAPI struct {
+	e *Ethereum
+}
+
+func NewPrivateDposAPI(e *Ethereum) *PrivateDposAPI {
+	return &PrivateDposAPI{e: e}
+}
+
+//组装（但不封装、不广播）本节点当前将要产生的区块，返回选中的交易、使用的Gas、系统交易以及结果根哈希
+func (api *PrivateDposAPI) PreviewBlock() (map[string]interface{}, error) {
+
+	block, receipts, err := api.e.Miner().PreviewBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	signer := types.MakeSigner(api.e.chainConfig, block.Number())
+	txs := make([]map[string]interface{}, 0, len(block.Transactions()))
+	systemTxs := make([]common.Hash, 0)
+	for i, tx := range block.Transactions() {
+		from, _ := types.Sender(signer, tx)
+		fields := map[string]interface{}{
+			"hash":     tx.Hash(),
+			"from":     from,
+			"to":       tx.To(),
+			"major":    tx.Major(),
+			"minor":    tx.Minor(),
+			"nonce":    hexutil.Uint64(tx.Nonce()),
+			"gasPrice": (*hexutil.Big)(tx.GasPrice()),
+		}
+		if i < len(receipts) {
+			fields["gasUsed"] = (*hexutil.Big)(receipts[i].GasUsed)
+			fields["status"] = hexutil.Uint(receipts[i].Status)
+		}
+		txs = append(txs, fields)
+
+		if tx.Major() != protocol.Normal {
+			systemTxs = append(systemTxs, tx.Hash())
+		}
+	}
+
+	fields := ethapi.RPCMarshalHeader(block.Header(), false)
+	fields["transactions"] = txs
+	fields["systemTransactions"] = systemTxs
+	return fields, nil
+}
+
 //以太坊全节点相关API的集合，通过私有管理端点公开。
 type PrivateAdminAPI struct {
 	eth *Ethereum
@@ -220,6 +379,11 @@ func hasAllBlocks(chain *core.BlockChain, bs []*types.Block) bool {
 	return true
 }
 
+//得到节点的运行状态（同步状态、连接节点数量、链头区块时间以及验证者出块情况）
+func (api *PrivateAdminAPI) NodeStatus() *NodeStatus {
+	return api.eth.NodeStatus()
+}
+
 //从本地文件导入区块链
 func (api *PrivateAdminAPI) ImportChain(file string) (bool, error) {
 	// Make sure the can access the file to import
@@ -237,36 +401,64 @@ func (api *PrivateAdminAPI) ImportChain(file string) (bool, error) {
 	}
 
 	// Run actual the import in pre-configured batches
-	stream := rlp.NewStream(reader, 0)
-
-	blocks, index := make([]*types.Block, 0, 2500), 0
-	for batch := 0; ; batch++ {
-		// Load a batch of blocks from the input file
-		for len(blocks) < cap(blocks) {
-			block := new(types.Block)
-			if err := stream.Decode(block); err == io.EOF {
-				break
-			} else if err != nil {
-				return false, fmt.Errorf("block %d: failed to parse: %v", index, err)
-			}
-			blocks = append(blocks, block)
-			index++
-		}
-		if len(blocks) == 0 {
-			break
-		}
+	if _, err := importBlocks(api.eth.BlockChain(), reader); err != nil {
+		return false, err
+	}
+	return true, nil
+}
 
-		if hasAllBlocks(api.eth.BlockChain(), blocks) {
-			blocks = blocks[:0]
-			continue
-		}
-		// Import the batch and reset the buffer
-		if _, err := api.eth.BlockChain().InsertChain(blocks); err != nil {
-			return false, fmt.Errorf("batch %d: failed to insert: %v", batch, err)
+//将区块(通常为周期内的第一个区块)的状态检查点导出到本地文件，本节点是验证者时附带验证者签名
+func (api *PrivateAdminAPI) ExportCheckpoint(file string, blockNr rpc.BlockNumber) (*CheckpointResult, error) {
+
+	var block *types.Block
+	if blockNr == rpc.LatestBlockNumber || blockNr == rpc.PendingBlockNumber {
+		block = api.eth.blockchain.CurrentBlock()
+	} else {
+		block = api.eth.blockchain.GetBlockByNumber(uint64(blockNr))
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", blockNr)
+	}
+	return api.eth.WriteCheckpoint(file, block)
+}
+
+//在后台重新校验[from, to]范围内的主链区块(区块头、交易/收据根，以及重新执行后的状态、Dpos和Boker上下文的根)，
+//通过admin_verifyChainStatus查询进度以及校验失败的区块
+func (api *PrivateAdminAPI) VerifyChain(from, to rpc.BlockNumber) (*ChainVerification, error) {
+
+	head := api.eth.blockchain.CurrentBlock().NumberU64()
+	resolve := func(number rpc.BlockNumber) uint64 {
+		if number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber {
+			return head
 		}
-		blocks = blocks[:0]
+		return uint64(number)
 	}
-	return true, nil
+	return api.eth.verifier.start(resolve(from), resolve(to))
+}
+
+//得到最近一次主链校验的进度，没有运行过校验时返回null
+func (api *PrivateAdminAPI) VerifyChainStatus() *ChainVerification {
+	return api.eth.verifier.progress()
+}
+
+//在节点运行时将区块链数据库的一致快照以及节点列表文件写入path目录(不包括节点私钥以及keystore)
+func (api *PrivateAdminAPI) CreateSnapshot(path string) (*SnapshotManifest, error) {
+	return api.eth.createSnapshot(path)
+}
+
+//将path目录中的快照复制到节点目录，节点重新启动时用快照替换区块链数据库
+func (api *PrivateAdminAPI) RestoreSnapshot(path string) (*SnapshotManifest, error) {
+	return api.eth.restoreSnapshot(path)
+}
+
+//检查链头区块的数据，损坏时回滚到最后一个完整可用的区块并重新补齐交易索引，之后节点从该区块继续同步
+func (api *PrivateAdminAPI) RepairChain() (*core.RewindReport, error) {
+	return api.eth.repairChain()
+}
+
+//中止正在运行的主链校验，没有运行中的校验时返回false
+func (api *PrivateAdminAPI) AbortVerifyChain() bool {
+	return api.eth.verifier.stop()
 }
 
 //公开的以太坊全节点API，通过公共调试端点
@@ -296,7 +488,7 @@ func (api *PublicDebugAPI) DumpBlock(blockNr rpc.BlockNumber) (state.Dump, error
 	if block == nil {
 		return state.Dump{}, fmt.Errorf("block #%d not found", blockNr)
 	}
-	stateDb, err := api.eth.BlockChain().StateAt(block.Root())
+	stateDb, err := api.eth.stateAt(block.Header())
 	if err != nil {
 		return state.Dump{}, err
 	}
@@ -415,7 +607,7 @@ func (api *PrivateDebugAPI) traceBlock(block *types.Block, logConfig *vm.LogConf
 	if err := api.eth.engine.VerifyHeader(blockchain, block.Header(), true); err != nil {
 		return false, structLogger.StructLogs(), err
 	}
-	statedb, err := blockchain.StateAt(blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1).Root())
+	statedb, err := api.eth.stateAt(blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1).Header())
 	if err != nil {
 		return false, structLogger.StructLogs(), err
 	}
@@ -451,6 +643,13 @@ func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, txHash common.
 
 	log.Info("(api *PrivateDebugAPI) TraceTransaction")
 
+	//节点为跟踪设置的执行期限，JavaScript跟踪器的超时在此期限内生效
+	if timeout := api.eth.ApiBackend.RPCTimeout("debug_traceTransaction"); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	var tracer vm.Tracer
 	if config != nil && config.Tracer != nil {
 		timeout := defaultTraceTimeout
@@ -498,16 +697,29 @@ func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, txHash common.
 	// Run the transaction with tracing enabled.
 	log.Info("****TraceTransaction****")
 	vmenv := vm.NewEVM(context, statedb, api.config, vm.Config{Debug: true, Tracer: tracer})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			vmenv.Cancel()
+		case <-done:
+		}
+	}()
+	dposContext, bokerContext := core.CopyContexts(api.eth.BlockChain().CurrentBlock())
 	ret, gas, failed, err := core.NormalMessage(vmenv,
 		msg,
 		new(core.GasPool).AddGas(tx.Gas()),
 		new(big.Int).SetInt64(protocol.MaxBlockSize),
-		api.eth.BlockChain().CurrentBlock().DposCtx(),
-		api.eth.BlockChain().CurrentBlock().BokerCtx(),
+		dposContext,
+		bokerContext,
 		api.eth.Boker())
 	if err != nil {
 		return nil, fmt.Errorf("tracing failed: %v", err)
 	}
+	if _, ok := tracer.(*vm.StructLogger); ok && ctx.Err() != nil {
+		return nil, protocol.ErrRPCTimeout
+	}
 	switch tracer := tracer.(type) {
 	case *vm.StructLogger:
 		return &ethapi.ExecutionResult{
@@ -537,11 +749,12 @@ func (api *PrivateDebugAPI) computeTxEnv(blockHash common.Hash, txIndex int) (co
 	if parent == nil {
 		return nil, vm.Context{}, nil, fmt.Errorf("block parent %x not found", block.ParentHash())
 	}
-	statedb, err := api.eth.BlockChain().StateAt(parent.Root())
+	statedb, err := api.eth.stateAt(parent.Header())
 	if err != nil {
 		return nil, vm.Context{}, nil, err
 	}
 	txs := block.Transactions()
+	dposContext, bokerContext := core.CopyContexts(api.eth.BlockChain().CurrentBlock())
 
 	// Recompute transactions up to the target index.
 	signer := types.MakeSigner(api.config, block.Number())
@@ -560,8 +773,8 @@ func (api *PrivateDebugAPI) computeTxEnv(blockHash common.Hash, txIndex int) (co
 			msg,
 			gp,
 			sp,
-			api.eth.BlockChain().CurrentBlock().DposCtx(),
-			api.eth.BlockChain().CurrentBlock().BokerCtx(),
+			dposContext,
+			bokerContext,
 			api.eth.Boker())
 		if err != nil {
 			return nil, vm.Context{}, nil, fmt.Errorf("tx %x failed: %v", tx.Hash(), err)
@@ -583,6 +796,35 @@ func (api *PrivateDebugAPI) GetBadBlocks(ctx context.Context) ([]core.BadBlockAr
 	return api.eth.BlockChain().BadBlocks()
 }
 
+// GetBlockImportStats returns the per stage timings (sender recovery, execution,
+// validation, Dpos/Boker context commit, trie commit and database write) of the
+// most recently imported blocks, in import order.
+func (api *PrivateDebugAPI) GetBlockImportStats(ctx context.Context) []*core.BlockImportStats {
+	return api.eth.BlockChain().ImportStats()
+}
+
+// VerifyBloomIndex cross-checks the indexed bloombits sections in the given range
+// (all indexed sections by default) against the header blooms, and the header
+// blooms against the blooms recomputed from the stored receipts. Corrupted
+// sections make eth_getLogs silently miss events and can be rebuilt with
+// debug_rebuildBloomIndex.
+func (api *PrivateDebugAPI) VerifyBloomIndex(fromSection, toSection *hexutil.Uint64) (*BloomIndexVerification, error) {
+	from, to := uint64(0), uint64(math.MaxUint64)
+	if fromSection != nil {
+		from = uint64(*fromSection)
+	}
+	if toSection != nil {
+		to = uint64(*toSection)
+	}
+	return api.eth.verifyBloomIndex(from, to)
+}
+
+// RebuildBloomIndex drops the bloombits sections starting with fromSection and
+// regenerates them in the background, returning the number of sections dropped.
+func (api *PrivateDebugAPI) RebuildBloomIndex(fromSection hexutil.Uint64) hexutil.Uint64 {
+	return hexutil.Uint64(api.eth.rebuildBloomIndex(uint64(fromSection)))
+}
+
 // StorageRangeResult is the result of a debug_storageRangeAt API call.
 type StorageRangeResult struct {
 	Storage storageMap   `json:"storage"`
@@ -596,17 +838,72 @@ type storageEntry struct {
 	Value common.Hash  `json:"value"`
 }
 
+// StorageRangeProofResult is a storage range together with the merkle proofs of
+// its boundaries. The start proof covers the requested start key and the end
+// proof covers the last key of the range, so a client holding the storage root
+// can verify that no slot was left out between the two.
+type StorageRangeProofResult struct {
+	StorageRangeResult
+	StorageRoot common.Hash     `json:"storageRoot"`
+	StartProof  []hexutil.Bytes `json:"startProof"`
+	EndProof    []hexutil.Bytes `json:"endProof"`
+}
+
 // StorageRangeAt returns the storage at the given block height and transaction index.
-func (api *PrivateDebugAPI) StorageRangeAt(ctx context.Context, blockHash common.Hash, txIndex int, contractAddress common.Address, keyStart hexutil.Bytes, maxResult int) (StorageRangeResult, error) {
+func (api *PrivateDebugAPI) StorageRangeAt(ctx context.Context, blockHash common.Hash, txIndex int, contractAddress common.Address, keyStart hexutil.Bytes, maxResult int) (StorageRangeProofResult, error) {
 	_, _, statedb, err := api.computeTxEnv(blockHash, txIndex)
 	if err != nil {
-		return StorageRangeResult{}, err
+		return StorageRangeProofResult{}, err
 	}
 	st := statedb.StorageTrie(contractAddress)
 	if st == nil {
-		return StorageRangeResult{}, fmt.Errorf("account %x doesn't exist", contractAddress)
+		return StorageRangeProofResult{}, fmt.Errorf("account %x doesn't exist", contractAddress)
+	}
+	return storageRangeProof(st, keyStart, storageRangeAt(st, keyStart, maxResult))
+}
+
+// storageProver is implemented by storage tries able to produce merkle proofs.
+type storageProver interface {
+	Prove(hashedKey []byte, fromLevel uint, proofDb trie.DatabaseWriter) error
+}
+
+// proofList collects the nodes of a merkle proof in path order.
+type proofList []hexutil.Bytes
+
+func (l *proofList) Put(key []byte, value []byte) error {
+	*l = append(*l, common.CopyBytes(value))
+	return nil
+}
+
+// storageRangeProof attaches the boundary proofs to a storage range.
+func storageRangeProof(st state.Trie, start []byte, result StorageRangeResult) (StorageRangeProofResult, error) {
+	prover, ok := st.(storageProver)
+	if !ok {
+		return StorageRangeProofResult{}, fmt.Errorf("storage trie %T cannot produce proofs", st)
+	}
+	proof := StorageRangeProofResult{
+		StorageRangeResult: result,
+		StorageRoot:        st.Hash(),
+		StartProof:         []hexutil.Bytes{},
+		EndProof:           []hexutil.Bytes{},
+	}
+	startKey := make([]byte, common.HashLength)
+	copy(startKey, start)
+	if err := prover.Prove(startKey, 0, (*proofList)(&proof.StartProof)); err != nil {
+		return StorageRangeProofResult{}, err
+	}
+	var last []byte
+	for key := range result.Storage {
+		if last == nil || bytes.Compare(key[:], last) > 0 {
+			last = common.CopyBytes(key[:])
+		}
+	}
+	if last != nil {
+		if err := prover.Prove(last, 0, (*proofList)(&proof.EndProof)); err != nil {
+			return StorageRangeProofResult{}, err
+		}
 	}
-	return storageRangeAt(st, keyStart, maxResult), nil
+	return proof, nil
 }
 
 func storageRangeAt(st state.Trie, start []byte, maxResult int) StorageRangeResult {
@@ -688,11 +985,12 @@ func (api *PrivateDebugAPI) getModifiedAccounts(startBlock, endBlock *types.Bloc
 		return nil, fmt.Errorf("start block height (%d) must be less than end block height (%d)", startBlock.Number().Uint64(), endBlock.Number().Uint64())
 	}
 
-	oldTrie, err := trie.NewSecure(startBlock.Root(), api.eth.chainDb, 0)
+	triedb := api.eth.blockchain.TrieDB()
+	oldTrie, err := trie.NewSecure(startBlock.Root(), triedb, 0)
 	if err != nil {
 		return nil, err
 	}
-	newTrie, err := trie.NewSecure(endBlock.Root(), api.eth.chainDb, 0)
+	newTrie, err := trie.NewSecure(endBlock.Root(), triedb, 0)
 	if err != nil {
 		return nil, err
 	}
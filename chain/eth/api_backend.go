@@ -19,11 +19,14 @@ package eth
 import (
 	"context"
 	"math/big"
+	"time"
 
 	"github.com/Tinachain/Tina/chain/accounts"
 	"github.com/Tinachain/Tina/chain/boker/api"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/common/math"
+	"github.com/Tinachain/Tina/chain/consensus/dpos"
 	"github.com/Tinachain/Tina/chain/core"
 	"github.com/Tinachain/Tina/chain/core/bloombits"
 	"github.com/Tinachain/Tina/chain/core/state"
@@ -33,6 +36,7 @@ import (
 	"github.com/Tinachain/Tina/chain/eth/gasprice"
 	"github.com/Tinachain/Tina/chain/ethdb"
 	"github.com/Tinachain/Tina/chain/event"
+	"github.com/Tinachain/Tina/chain/internal/ethapi"
 	"github.com/Tinachain/Tina/chain/log"
 	"github.com/Tinachain/Tina/chain/params"
 	"github.com/Tinachain/Tina/chain/rpc"
@@ -40,21 +44,51 @@ import (
 
 // EthApiBackend implements ethapi.Backend for full nodes
 type EthApiBackend struct {
-	eth *Ethereum
-	gpo *gasprice.Oracle
+	eth   *Ethereum
+	gpo   *gasprice.Oracle
+	cache *ethapi.ResponseCache
 }
 
 func (b *EthApiBackend) ChainConfig() *params.ChainConfig {
 	return b.eth.chainConfig
 }
 
+func (b *EthApiBackend) EthCompatible() bool {
+	return b.eth.config.EthCompatible
+}
+
+func (b *EthApiBackend) UnprotectedTxsAllowed() bool {
+	return b.eth.config.AllowUnprotectedTxs
+}
+
+func (b *EthApiBackend) RPCTimeout(method string) time.Duration {
+	return b.eth.config.RPCDeadline(method)
+}
+
+//已确认(不可逆)的区块高度，没有使用Dpos共识时只有创世区块被认为不可逆
+func (b *EthApiBackend) FinalizedNumber() uint64 {
+	if engine, ok := b.eth.engine.(*dpos.Dpos); ok {
+		if header, err := engine.ConfirmedBlockHeader(b.eth.blockchain); err == nil {
+			return header.Number.Uint64()
+		}
+	}
+	return 0
+}
+
+func (b *EthApiBackend) ResponseCache() *ethapi.ResponseCache {
+	return b.cache
+}
+
+func (b *EthApiBackend) PendingSpeculative() bool {
+	return b.eth.miner.Speculative()
+}
+
 func (b *EthApiBackend) CurrentBlock() *types.Block {
 	return b.eth.blockchain.CurrentBlock()
 }
 
-func (b *EthApiBackend) SetHead(number uint64) {
-	b.eth.protocolManager.downloader.Cancel()
-	b.eth.blockchain.SetHead(number)
+func (b *EthApiBackend) SetHead(number uint64) (*core.RewindReport, error) {
+	return b.eth.rewindChain(number)
 }
 
 func (b *EthApiBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error) {
@@ -70,6 +104,10 @@ func (b *EthApiBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNum
 	return b.eth.blockchain.GetHeaderByNumber(uint64(blockNr)), nil
 }
 
+func (b *EthApiBackend) HeaderByHash(ctx context.Context, blockHash common.Hash) (*types.Header, error) {
+	return b.eth.blockchain.GetHeaderByHash(blockHash), nil
+}
+
 func (b *EthApiBackend) BlockByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Block, error) {
 	// Pending block is only known by the miner
 	if blockNr == rpc.PendingBlockNumber {
@@ -94,7 +132,16 @@ func (b *EthApiBackend) StateAndHeaderByNumber(ctx context.Context, blockNr rpc.
 	if header == nil || err != nil {
 		return nil, nil, err
 	}
-	stateDb, err := b.eth.BlockChain().StateAt(header.Root)
+	stateDb, err := b.eth.stateAt(header)
+	return stateDb, header, err
+}
+
+func (b *EthApiBackend) StateAndHeaderByHash(ctx context.Context, blockHash common.Hash) (*state.StateDB, *types.Header, error) {
+	header, err := b.HeaderByHash(ctx, blockHash)
+	if header == nil || err != nil {
+		return nil, nil, err
+	}
+	stateDb, err := b.eth.stateAt(header)
 	return stateDb, header, err
 }
 
@@ -115,6 +162,12 @@ func (b *EthApiBackend) GetEVM(ctx context.Context, msg core.Message, state *sta
 	vmError := func() error { return nil }
 
 	context := core.NewEVMContext(msg, header, b.eth.BlockChain(), nil)
+	if header.DposProto != nil {
+		context.DposContext, _ = types.NewDposContextFromProto(b.eth.chainDb, header.DposProto)
+	}
+	if header.BokerProto != nil {
+		context.BokerContext, _ = types.NewBokerContextFromProto(b.eth.chainDb, header.BokerProto)
+	}
 	return vm.NewEVM(context, state, b.eth.chainConfig, vmCfg), vmError, nil
 }
 
@@ -137,9 +190,43 @@ func (b *EthApiBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscri
 func (b *EthApiBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
 
 	log.Info("(b *EthApiBackend) SendTx", "Nonce", signedTx.Nonce())
+	if b.eth.config.ReadOnly {
+		return errReadOnly
+	}
 	return b.eth.txPool.AddLocal(signedTx)
 }
 
+func (b *EthApiBackend) ScheduleTx(ctx context.Context, signedTx *types.Transaction, block uint64) error {
+	if b.eth.config.ReadOnly {
+		return errReadOnly
+	}
+	return b.eth.txPool.AddScheduled(signedTx, block)
+}
+
+func (b *EthApiBackend) DryRunTx(ctx context.Context, from common.Address, tx *types.Transaction) (*types.Receipt, error) {
+	return core.DryRunTransaction(b.eth.blockchain, from, tx)
+}
+
+func (b *EthApiBackend) ScheduledTxs() []*core.ScheduledTx {
+	return b.eth.txPool.Scheduled()
+}
+
+func (b *EthApiBackend) CancelScheduledTx(txHash common.Hash) bool {
+	return b.eth.txPool.RemoveScheduled(txHash)
+}
+
+func (b *EthApiBackend) RebroadcastTx(txHash common.Hash) bool {
+	return b.eth.txPool.Rebroadcast(txHash)
+}
+
+func (b *EthApiBackend) PoolTransaction(txHash common.Hash) *core.PoolTx {
+	return b.eth.txPool.Lookup(txHash)
+}
+
+func (b *EthApiBackend) PoolTransactionsByNonce(addr common.Address, from, to uint64) []*core.PoolTx {
+	return b.eth.txPool.NonceRange(addr, from, to)
+}
+
 func (b *EthApiBackend) GetPoolTransactions() (types.Transactions, error) {
 	pending, err := b.eth.txPool.Pending()
 	if err != nil {
@@ -239,3 +326,16 @@ func (b *EthApiBackend) GetLocalValidator() common.Address {
 
 	return b.eth.GetLocalValidator()
 }
+
+func (b *EthApiBackend) GetStock(ctx context.Context, address common.Address) (*protocol.StockAccount, error) {
+	return b.eth.blockchain.CurrentBlock().BokerCtx().GetStock(address), nil
+}
+
+func (b *EthApiBackend) GetStockManager(ctx context.Context) (common.Address, error) {
+	return b.eth.blockchain.CurrentBlock().BokerCtx().GetStockManager(), nil
+}
+
+func (b *EthApiBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error) {
+	tx, blockHash, blockNumber, index := core.GetTransaction(b.eth.chainDb, txHash)
+	return tx, blockHash, blockNumber, index, nil
+}
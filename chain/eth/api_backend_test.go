@@ -0,0 +1,108 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/event"
+	"github.com/Tinachain/Tina/chain/p2p"
+	"github.com/Tinachain/Tina/chain/p2p/discover"
+)
+
+// Tests that a read-only replica refuses to mine and to accept transactions
+// instead of touching its transaction pool.
+func TestReadOnlyWrites(t *testing.T) {
+	eth := &Ethereum{config: &Config{ReadOnly: true}}
+	backend := &EthApiBackend{eth: eth}
+
+	tx := types.NewTransaction(protocol.Normal, protocol.NormalCall, 0, common.Address{0x01}, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil)
+	if err := backend.SendTx(context.Background(), tx); err != errReadOnly {
+		t.Errorf("send: have %v, want %v", err, errReadOnly)
+	}
+	if err := backend.ScheduleTx(context.Background(), tx, 10); err != errReadOnly {
+		t.Errorf("schedule: have %v, want %v", err, errReadOnly)
+	}
+	if err := eth.StartMining(true); err != errReadOnly {
+		t.Errorf("mining: have %v, want %v", err, errReadOnly)
+	}
+}
+
+// remoteTxPool records the remote transactions delivered by the handler.
+type remoteTxPool struct {
+	added []*types.Transaction
+}
+
+func (p *remoteTxPool) AddRemotes(txs []*types.Transaction) []error {
+	p.added = append(p.added, txs...)
+	return make([]error, len(txs))
+}
+
+func (p *remoteTxPool) Pending() (map[common.Address]types.Transactions, error) { return nil, nil }
+
+func (p *remoteTxPool) SubscribeTxPreEvent(ch chan<- core.TxPreEvent) event.Subscription {
+	return nil
+}
+
+func (p *remoteTxPool) SubscribeTxRebroadcastEvent(ch chan<- core.TxRebroadcastEvent) event.Subscription {
+	return nil
+}
+
+// Tests that a read-only replica drops the transactions announced by peers.
+func TestReadOnlyDropsRemoteTxs(t *testing.T) {
+	tx := types.NewTransaction(protocol.Normal, protocol.NormalCall, 0, common.Address{0x01}, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil)
+
+	for _, readOnly := range []bool{true, false} {
+		pool := new(remoteTxPool)
+		pm := &ProtocolManager{acceptTxs: 1, readOnly: readOnly, txpool: pool}
+
+		app, rw := p2p.MsgPipe()
+		p := newPeer(eth63, p2p.NewPeer(discover.NodeID{0x01}, "peer", nil), rw)
+		go p2p.Send(app, TxMsg, []*types.Transaction{tx})
+
+		if err := pm.handleMsg(p); err != nil {
+			t.Fatalf("read only %v: failed to handle transactions: %v", readOnly, err)
+		}
+		want := 1
+		if readOnly {
+			want = 0
+		}
+		if len(pool.added) != want {
+			t.Errorf("read only %v: delivered transactions mismatch: have %d, want %d", readOnly, len(pool.added), want)
+		}
+		app.Close()
+	}
+}
+
+// Tests that the state of a block can be resolved by its hash, and that unknown
+// blocks and blocks without state are reported.
+func TestStateAndHeaderByHash(t *testing.T) {
+	eth, cleanup := newRewindTestNode(t, 2, 0, false)
+	defer cleanup()
+	backend := &EthApiBackend{eth: eth}
+
+	block := eth.blockchain.GetBlockByNumber(1)
+	statedb, header, err := backend.StateAndHeaderByHash(context.Background(), block.Hash())
+	if err != nil {
+		t.Fatalf("failed to get state: %v", err)
+	}
+	if header.Hash() != block.Hash() || statedb.GetBalance(testBank).Sign() == 0 {
+		t.Errorf("state mismatch: header %x, balance %v", header.Hash(), statedb.GetBalance(testBank))
+	}
+
+	//未知的区块返回nil而不是错误
+	if statedb, header, err := backend.StateAndHeaderByHash(context.Background(), common.Hash{0x01}); statedb != nil || header != nil || err != nil {
+		t.Errorf("unknown block: have %v/%v, err %v", statedb, header, err)
+	}
+
+	//区块的状态不在数据库中
+	orphan := &types.Header{ParentHash: block.Hash(), Number: big.NewInt(2), Root: common.Hash{0x02}, Difficulty: big.NewInt(1), DposProto: &types.DposContextProto{}, BokerProto: &types.BokerBackendProto{}}
+	core.WriteHeader(eth.chainDb, orphan)
+	if statedb, _, err := backend.StateAndHeaderByHash(context.Background(), orphan.Hash()); statedb != nil || err == nil {
+		t.Errorf("missing state: have %v, err %v", statedb, err)
+	}
+}
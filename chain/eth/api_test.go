@@ -17,13 +17,17 @@
 package eth
 
 import (
+	"math/big"
 	"reflect"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
 	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
 )
 
 var dumper = spew.ConfigState{Indent: "    "}
@@ -86,3 +90,69 @@ func TestStorageRangeAt(t *testing.T) {
 		}
 	}
 }
+
+// headerChain is a chain reader over a fixed set of headers.
+type headerChain map[common.Hash]*types.Header
+
+func (hc headerChain) Config() *params.ChainConfig  { return params.TestChainConfig }
+func (hc headerChain) CurrentHeader() *types.Header { return nil }
+func (hc headerChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	return hc[hash]
+}
+func (hc headerChain) GetHeaderByNumber(number uint64) *types.Header  { return nil }
+func (hc headerChain) GetHeaderByHash(hash common.Hash) *types.Header { return hc[hash] }
+func (hc headerChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	return nil
+}
+
+// Tests that the miner status finds the next slot of a validator within the
+// epoch and the last block it produced.
+func TestMinerStatusSlots(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	dposContext, _ := types.NewDposContext(db)
+
+	var (
+		v1, v2, v3 = common.HexToAddress("0x01"), common.HexToAddress("0x02"), common.HexToAddress("0x03")
+		outsider   = common.HexToAddress("0x04")
+		firstTimer = int64(1000)
+	)
+	dposContext.SetEpochTrie([]common.Address{v1, v2, v3})
+
+	tests := []struct {
+		validator common.Address
+		now       int64
+		slot      int64
+	}{
+		{v1, firstTimer, firstTimer},
+		{v2, firstTimer, firstTimer + protocol.BlockInterval},
+		{v1, firstTimer + 1, firstTimer + 3*protocol.BlockInterval},
+		{v3, firstTimer + 2*protocol.BlockInterval, firstTimer + 2*protocol.BlockInterval},
+	}
+	for i, tt := range tests {
+		if slot := nextProducerSlot(dposContext, tt.validator, firstTimer, tt.now); slot == nil || slot.ToInt().Int64() != tt.slot {
+			t.Errorf("test %d: slot mismatch: have %v, want %d", i, slot, tt.slot)
+		}
+	}
+	if slot := nextProducerSlot(dposContext, outsider, firstTimer, firstTimer); slot != nil {
+		t.Errorf("outsider: have slot %v", slot)
+	}
+
+	//v1产生了区块1，v2产生了区块2和3
+	chain := make(headerChain)
+	var parent common.Hash
+	var head *types.Header
+	for i, validator := range []common.Address{v1, v2, v2} {
+		head = &types.Header{ParentHash: parent, Number: big.NewInt(int64(i + 1)), Time: big.NewInt(firstTimer + int64(i)*protocol.BlockInterval), Validator: validator, DposProto: &types.DposContextProto{}, BokerProto: &types.BokerBackendProto{}}
+		parent = head.Hash()
+		chain[parent] = head
+	}
+	if header := lastProducedHeader(chain, head, v1); header == nil || header.Number.Int64() != 1 {
+		t.Errorf("last block of v1 mismatch: have %v, want 1", header)
+	}
+	if header := lastProducedHeader(chain, head, v2); header == nil || header.Number.Int64() != 3 {
+		t.Errorf("last block of v2 mismatch: have %v, want 3", header)
+	}
+	if header := lastProducedHeader(chain, head, v3); header != nil {
+		t.Errorf("last block of v3: have %v, want none", header.Number)
+	}
+}
@@ -0,0 +1,57 @@
+package eth
+
+import (
+	"fmt"
+
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/eth/downloader"
+	"github.com/Tinachain/Tina/chain/log"
+)
+
+//得到区块的状态，状态不可用时按节点是否为归档节点返回明确的错误
+func (s *Ethereum) stateAt(header *types.Header) (*state.StateDB, error) {
+
+	statedb, err := s.blockchain.StateAt(header.Root)
+	if err == nil {
+		return statedb, nil
+	}
+	if s.config.Archive {
+		//归档节点缺少历史状态说明数据已经损坏
+		return nil, fmt.Errorf("state of block #%d is missing on this archive node, the database may be damaged (see admin_verifyChain): %v", header.Number, err)
+	}
+	return nil, fmt.Errorf("state of block #%d is unavailable on this node (historical state is only guaranteed on archive nodes), try an archive node", header.Number)
+}
+
+//检查归档模式的要求：只能全量同步，并且数据库中保存了每个区块的状态(快速同步过的数据库缺少快照区块之前的状态)
+func checkArchive(config *Config, chain *core.BlockChain) error {
+
+	if config.SyncMode != downloader.FullSync {
+		return fmt.Errorf("archive mode requires full sync, have %v", config.SyncMode)
+	}
+	//快速同步只下载快照区块的状态，之后的区块都有状态，因此按二分查找第一个有状态的区块
+	head := chain.CurrentBlock().NumberU64()
+	hasState := func(number uint64) bool {
+		header := chain.GetHeaderByNumber(number)
+		if header == nil {
+			return false
+		}
+		_, err := chain.StateAt(header.Root)
+		return err == nil
+	}
+	low, high := uint64(1), head
+	for low < high {
+		mid := (low + high) / 2
+		if hasState(mid) {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	if head > 0 && low > 1 {
+		return fmt.Errorf("archive mode: the state before block #%d is missing (database was fast synced), remove the chain data and resync with --archive", low)
+	}
+	log.Info("Archive mode enabled, historical state is retained for every block", "head", head)
+	return nil
+}
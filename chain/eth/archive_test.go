@@ -0,0 +1,85 @@
+package eth
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/consensus/ethash"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/core/vm"
+	"github.com/Tinachain/Tina/chain/eth/downloader"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+// newArchiveTestChain creates a chain of the given number of blocks, of which
+// the first missing ones reference a state that is not in the database, like
+// the blocks before the pivot of a fast synced chain.
+func newArchiveTestChain(t *testing.T, blocks, missing int) *core.BlockChain {
+	db, _ := ethdb.NewMemDatabase()
+	genesis := (&core.Genesis{Config: params.TestChainConfig, Alloc: core.GenesisAlloc{testBank: {Balance: big.NewInt(1000000)}}}).MustCommit(db)
+
+	parent := genesis
+	for i := 1; i <= blocks; i++ {
+		root := genesis.Root()
+		if i <= missing {
+			root = common.Hash{byte(i)}
+		}
+		header := &types.Header{ParentHash: parent.Hash(), Number: big.NewInt(int64(i)), Root: root, Difficulty: big.NewInt(1), GasLimit: genesis.GasLimit(), DposProto: &types.DposContextProto{}, BokerProto: &types.BokerBackendProto{}}
+		block := types.NewBlockWithHeader(header)
+		core.WriteTd(db, block.Hash(), block.NumberU64(), big.NewInt(int64(i+1)))
+		core.WriteBlock(db, block)
+		core.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		core.WriteHeadBlockHash(db, block.Hash())
+		core.WriteHeadHeaderHash(db, block.Hash())
+		parent = block
+	}
+	chain, err := core.NewBlockChain(db, params.TestChainConfig, ethash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	return chain
+}
+
+// Tests that a missing historical state is reported as damage on an archive
+// node and as unavailable on a pruned node.
+func TestArchiveStateAt(t *testing.T) {
+	chain := newArchiveTestChain(t, 4, 2)
+	defer chain.Stop()
+
+	eth := &Ethereum{config: &Config{}, blockchain: chain}
+	if statedb, err := eth.stateAt(chain.GetHeaderByNumber(3)); err != nil || statedb.GetBalance(testBank).Sign() == 0 {
+		t.Errorf("available state: have %v, err %v", statedb, err)
+	}
+	if _, err := eth.stateAt(chain.GetHeaderByNumber(1)); err == nil || !strings.Contains(err.Error(), "try an archive node") {
+		t.Errorf("pruned node: have %v, want unavailable state error", err)
+	}
+	eth.config.Archive = true
+	if _, err := eth.stateAt(chain.GetHeaderByNumber(1)); err == nil || !strings.Contains(err.Error(), "missing on this archive node") {
+		t.Errorf("archive node: have %v, want damaged database error", err)
+	}
+}
+
+// Tests that archive mode is only accepted on full synced databases holding the
+// state of every block.
+func TestCheckArchive(t *testing.T) {
+	full := newArchiveTestChain(t, 6, 0)
+	defer full.Stop()
+
+	if err := checkArchive(&Config{SyncMode: downloader.FullSync}, full); err != nil {
+		t.Errorf("full synced chain: %v", err)
+	}
+	if err := checkArchive(&Config{SyncMode: downloader.FastSync}, full); err == nil {
+		t.Errorf("fast sync mode accepted")
+	}
+
+	fast := newArchiveTestChain(t, 6, 3)
+	defer fast.Stop()
+
+	if err := checkArchive(&Config{SyncMode: downloader.FullSync}, fast); err == nil || !strings.Contains(err.Error(), "before block #4") {
+		t.Errorf("fast synced chain: have %v, want missing state before #4", err)
+	}
+}
@@ -9,12 +9,15 @@ import (
 	"sync/atomic"
 
 	"github.com/Tinachain/Tina/chain/accounts"
+	"github.com/Tinachain/Tina/chain/accounts/secretstore"
 	"github.com/Tinachain/Tina/chain/boker/api"
+	"github.com/Tinachain/Tina/chain/boker/bridge"
 	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/common/hexutil"
 	"github.com/Tinachain/Tina/chain/consensus"
 	"github.com/Tinachain/Tina/chain/consensus/dpos"
+	"github.com/Tinachain/Tina/chain/consensus/dpos/remotesigner"
 	"github.com/Tinachain/Tina/chain/core"
 	"github.com/Tinachain/Tina/chain/core/bloombits"
 	"github.com/Tinachain/Tina/chain/core/types"
@@ -47,6 +50,7 @@ type Ethereum struct {
 	chainConfig     *params.ChainConfig            //配置信息
 	shutdownChan    chan bool                      // Channel for shutting down the ethereum
 	stopDbUpgrade   func() error                   // stop chain db sequential key upgrade
+	stopTxBackfill  func() error                   // stop transaction lookup backfill
 	txPool          *core.TxPool                   //交易池
 	blockchain      *core.BlockChain               //链对象
 	protocolManager *ProtocolManager               //网络协议管理
@@ -61,12 +65,19 @@ type Ethereum struct {
 	miner           *miner.Miner                   //挖矿类
 	gasPrice        *big.Int                       //Gas单价
 	coinbase        common.Address                 //挖矿账号
-	password        string                         //挖矿账号的密码
+	password        *secretstore.Secret            //挖矿账号的密码(在内存中加密保存)
 	selfValidator   common.Address                 //设置当前出块节点为挖矿节点
 	networkId       uint64                         //网络ID
 	netRPCService   *ethapi.PublicNetAPI           //网络Api接口
 	lock            sync.RWMutex                   // Protects the variadic fields (e.g. gas price and coinbase)
 	boker           bokerapi.Api                   //Tina链新增加的接口
+	remoteSigner    *remotesigner.Signer           //远程签名服务(配置时使用)
+	bridgeRelayer   *bridge.Relayer                //跨链桥中继服务(配置时使用)
+	recoveryDir     string                         //回滚区块链时备份被丢弃区块的目录
+	instanceDir     string                         //节点的数据目录(临时节点为空)
+	checkpoints     *checkpointExporter            //周期检查点导出服务(配置时使用)
+	clock           *clockMonitor                  //本地时钟偏差检查
+	verifier        *chainVerifier                 //主链数据完整性校验
 }
 
 func (s *Ethereum) AddLesServer(ls LesServer) {
@@ -83,11 +94,21 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	if !config.SyncMode.IsValid() {
 		return nil, fmt.Errorf("invalid sync mode %d", config.SyncMode)
 	}
+	//设置交易IP字段的隐私策略
+	if err := protocol.SetIpPolicy(protocol.IpPolicy{Embed: config.TxIpMode, Redact: config.RPCIpRedaction, Salt: config.TxIpSalt}); err != nil {
+		return nil, err
+	}
 	chainDb, err := CreateDB(ctx, config, "chaindata")
 	if err != nil {
 		return nil, err
 	}
+	if config.DatabaseRepair {
+		if err := repairDatabase(chainDb); err != nil {
+			return nil, err
+		}
+	}
 	stopDbUpgrade := upgradeDeduplicateData(chainDb)
+	stopTxBackfill := upgradeTxLookups(chainDb)
 
 	//得到配置信息
 	chainConfig, genesisHash, genesisErr := core.SetupGenesisBlock(chainDb, config.Genesis)
@@ -104,11 +125,14 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		engine:         dpos.New(&params.DposConfig{}, chainDb),
 		shutdownChan:   make(chan bool),
 		stopDbUpgrade:  stopDbUpgrade,
+		stopTxBackfill: stopTxBackfill,
 		networkId:      config.NetworkId,
 		gasPrice:       config.GasPrice,
 		coinbase:       config.Coinbase,
 		bloomRequests:  make(chan chan *bloombits.Retrieval),
 		bloomIndexer:   NewBloomIndexer(chainDb, params.BloomBitsBlocks),
+		recoveryDir:    ctx.ResolvePath("recovery"),
+		instanceDir:    ctx.ResolvePath(""),
 	}
 
 	if !config.SkipBcVersionCheck {
@@ -124,6 +148,24 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	if err != nil {
 		return nil, err
 	}
+	if config.Archive {
+		if err := checkArchive(config, eth.blockchain); err != nil {
+			return nil, err
+		}
+	}
+	eth.blockchain.SetTrieCache(config.TrieDirtyCache, config.Archive)
+	if config.InternalTxIndex {
+		eth.blockchain.EnableInternalTxIndex()
+	}
+	if config.TokenIndex {
+		eth.blockchain.EnableTokenIndex()
+	}
+	if config.NameIndex {
+		eth.blockchain.EnableNameIndex()
+	}
+	if config.RichListSize > 0 {
+		eth.blockchain.EnableRichList(config.RichListSize)
+	}
 
 	// Rewind the chain in case of an incompatible config upgrade.
 	if compat, ok := genesisErr.(*params.ConfigCompatError); ok {
@@ -133,9 +175,19 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	}
 	eth.bloomIndexer.Start(eth.blockchain)
 
+	//只读副本不接收交易，也就不需要交易日志、定时交易文件以及重新广播，交易池只保留最小的容量
+	if config.ReadOnly {
+		config.TxPool.Journal, config.TxPool.Scheduled = "", ""
+		config.TxPool.NoLocals, config.TxPool.Rebroadcast = true, 0
+		config.TxPool.AccountSlots, config.TxPool.GlobalSlots = 1, 1
+		config.TxPool.AccountQueue, config.TxPool.GlobalQueue = 1, 1
+	}
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = ctx.ResolvePath(config.TxPool.Journal)
 	}
+	if config.TxPool.Scheduled != "" {
+		config.TxPool.Scheduled = ctx.ResolvePath(config.TxPool.Scheduled)
+	}
 
 	//新建交易池
 	eth.txPool = core.NewTxPool(config.TxPool, eth.chainConfig, eth.blockchain)
@@ -151,19 +203,29 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		eth.blockchain, chainDb); err != nil {
 		return nil, err
 	}
+	eth.protocolManager.readOnly = config.ReadOnly
 
 	//新建矿工
 	eth.miner = miner.New(eth, eth.chainConfig, eth.EventMux(), eth.engine)
 	eth.miner.SetExtra(makeExtraData(config.ExtraData))
+	eth.miner.SetVirtualPending(config.VirtualPending)
+
+	//本地时钟偏差超过上限时拒绝出块
+	eth.clock = newClockMonitor(eth, config.MaxClockDrift)
+	eth.miner.SetSealGuard(eth.clock.sealGuard)
 
 	//新建后台
-	eth.ApiBackend = &EthApiBackend{eth, nil}
+	eth.ApiBackend = &EthApiBackend{eth, nil, ethapi.NewResponseCache(config.RPCCacheSize)}
 	gpoParams := config.GPO
 	if gpoParams.Default == nil {
 		gpoParams.Default = config.GasPrice
 	}
 	eth.ApiBackend.gpo = gasprice.NewOracle(eth.ApiBackend, gpoParams)
 
+	if config.CheckpointDir != "" {
+		eth.checkpoints = newCheckpointExporter(eth, ctx.ResolvePath(config.CheckpointDir))
+	}
+	eth.verifier = newChainVerifier(chainDb, eth.blockchain)
 	return eth, nil
 }
 
@@ -190,6 +252,9 @@ func makeExtraData(extra []byte) []byte {
 
 //创建链DB
 func CreateDB(ctx *node.ServiceContext, config *Config, name string) (ethdb.Database, error) {
+	if err := applyStagedSnapshot(ctx.ResolvePath(name)); err != nil {
+		return nil, err
+	}
 	db, err := ctx.OpenDatabase(name, config.DatabaseCache, config.DatabaseHandles)
 	if err != nil {
 		return nil, err
@@ -226,11 +291,26 @@ func (s *Ethereum) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   downloader.NewPublicDownloaderAPI(s.protocolManager.downloader, s.eventMux, s.boker),
 			Public:    true,
+		}, {
+			Namespace: "downloader",
+			Version:   "1.0",
+			Service:   downloader.NewPublicDownloaderStatsAPI(s.protocolManager.downloader),
+			Public:    true,
 		}, {
 			Namespace: "miner",
 			Version:   "1.0",
 			Service:   NewPrivateMinerAPI(s),
 			Public:    false,
+		}, {
+			Namespace: "dpos",
+			Version:   "1.0",
+			Service:   NewPublicDposAPI(s),
+			Public:    true,
+		}, {
+			Namespace: "dpos",
+			Version:   "1.0",
+			Service:   NewPrivateDposAPI(s),
+			Public:    false,
 		}, {
 			Namespace: "eth",
 			Version:   "1.0",
@@ -337,6 +417,10 @@ func (self *Ethereum) SetLocalValidator(validator common.Address) error {
 //启动挖矿
 func (s *Ethereum) StartMining(local bool) error {
 
+	if s.config.ReadOnly {
+		return errReadOnly
+	}
+
 	//得到当前的coinbase，并检测当前coinbase是否为nil
 	coinbase, err := s.Coinbase()
 	if err != nil {
@@ -344,14 +428,17 @@ func (s *Ethereum) StartMining(local bool) error {
 		return fmt.Errorf("coinbase missing: %v", err)
 	}
 
-	//根据当前的挖矿账号得到Dpos使用的签名函数
+	//根据出块签名账号(没有设置时为挖矿账号)得到Dpos使用的签名函数
 	if dpos, ok := s.engine.(*dpos.Dpos); ok {
-		wallet, err := s.accountManager.Find(accounts.Account{Address: coinbase})
-		if wallet == nil || err != nil {
-			log.Error("Coinbase account unavailable locally", "err", err)
-			return fmt.Errorf("signer missing: %v", err)
+		signer := coinbase
+		if (s.config.SigningKey != common.Address{}) {
+			signer = s.config.SigningKey
 		}
-		dpos.Authorize(coinbase, wallet.SignHash)
+		signFn, err := s.signerFn(signer)
+		if err != nil {
+			return err
+		}
+		dpos.Authorize(coinbase, signer, signFn)
 	}
 
 	if local {
@@ -365,6 +452,29 @@ func (s *Ethereum) StartMining(local bool) error {
 	return nil
 }
 
+//得到区块签名函数，配置了远程签名服务时由签名服务签名，否则使用本地已解锁的账号
+func (s *Ethereum) signerFn(signer common.Address) (dpos.SignerFn, error) {
+
+	if s.config.RemoteSigner != nil && s.config.RemoteSigner.URL != "" {
+		if s.remoteSigner == nil {
+			remote, err := remotesigner.New(s.config.RemoteSigner)
+			if err != nil {
+				log.Error("Failed to connect remote signer", "url", s.config.RemoteSigner.URL, "err", err)
+				return nil, fmt.Errorf("remote signer: %v", err)
+			}
+			s.remoteSigner = remote
+		}
+		log.Info("Using remote signer for block sealing", "url", s.remoteSigner.URL(), "signer", signer)
+		return s.remoteSigner.SignHash, nil
+	}
+	wallet, err := s.accountManager.Find(accounts.Account{Address: signer})
+	if wallet == nil || err != nil {
+		log.Error("Signing account unavailable locally", "signer", signer, "err", err)
+		return nil, fmt.Errorf("signer missing: %v", err)
+	}
+	return wallet.SignHash, nil
+}
+
 func (s *Ethereum) Boker() bokerapi.Api {
 
 	return s.boker
@@ -378,13 +488,15 @@ func (s *Ethereum) SetBoker(boker bokerapi.Api) {
 
 func (s *Ethereum) Password() string {
 
-	return s.password
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.password.Open()
 }
 
 func (s *Ethereum) SetPassword(password string) {
 
 	s.lock.Lock()
-	s.password = password
+	s.password = secretstore.Seal(password)
 	s.lock.Unlock()
 }
 
@@ -395,8 +507,14 @@ func (s *Ethereum) DecodeParams(code []byte) ([]byte, error) {
 	return nil, nil
 }
 
+//得到提交系统交易时交易池中已有相同交易的处理方式
+func (s *Ethereum) SystemTxDedup() string {
+	return s.config.SystemTxDedup
+}
+
 func (s *Ethereum) StopMining()                        { s.miner.Stop() }
 func (s *Ethereum) IsMining() bool                     { return s.miner.Mining() }
+func (s *Ethereum) IsReadOnly() bool                   { return s.config.ReadOnly }
 func (s *Ethereum) Miner() *miner.Miner                { return s.miner }
 func (s *Ethereum) AccountManager() *accounts.Manager  { return s.accountManager }
 func (s *Ethereum) BlockChain() *core.BlockChain       { return s.blockchain }
@@ -443,6 +561,26 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 	if s.lesServer != nil {
 		s.lesServer.Start(srvr)
 	}
+
+	//启动跨链桥中继服务(只读副本不提交释放交易)
+	if s.config.Bridge != nil && s.config.Bridge.URL != "" && !s.config.ReadOnly {
+		relayer, err := bridge.New(s.config.Bridge, s)
+		if err != nil {
+			log.Error("Failed to start bridge relayer", "url", s.config.Bridge.URL, "err", err)
+		} else {
+			s.bridgeRelayer = relayer
+			s.bridgeRelayer.Start()
+		}
+	}
+
+	//启动验证者心跳以及时钟偏差检查
+	go s.heartbeatLoop()
+	go s.clock.loop()
+
+	//启动周期检查点导出服务
+	if s.checkpoints != nil {
+		s.checkpoints.Start()
+	}
 	return nil
 }
 
@@ -451,7 +589,14 @@ func (s *Ethereum) Stop() error {
 	if s.stopDbUpgrade != nil {
 		s.stopDbUpgrade()
 	}
+	if s.stopTxBackfill != nil {
+		s.stopTxBackfill()
+	}
 
+	if s.checkpoints != nil {
+		s.checkpoints.Stop()
+	}
+	s.verifier.stop()
 	s.bloomIndexer.Close()
 	s.blockchain.Stop()
 	s.protocolManager.Stop()
@@ -460,8 +605,14 @@ func (s *Ethereum) Stop() error {
 		s.lesServer.Stop()
 	}
 
+	if s.bridgeRelayer != nil {
+		s.bridgeRelayer.Stop()
+	}
 	s.txPool.Stop()
 	s.miner.Stop()
+	if s.remoteSigner != nil {
+		s.remoteSigner.Close()
+	}
 	s.eventMux.Stop()
 	s.chainDb.Close()
 	close(s.shutdownChan)
@@ -56,19 +56,19 @@ func NewContractBackend(apiBackend ethapi.Backend) *ContractBackend {
 
 // CodeAt retrieves any code associated with the contract from the local API.
 func (b *ContractBackend) CodeAt(ctx context.Context, contract common.Address, blockNum *big.Int) ([]byte, error) {
-	return b.bcapi.GetCode(ctx, contract, toBlockNumber(blockNum))
+	return b.bcapi.GetCode(ctx, contract, rpc.BlockNumberOrHashWithNumber(toBlockNumber(blockNum)))
 }
 
 // CodeAt retrieves any code associated with the contract from the local API.
 func (b *ContractBackend) PendingCodeAt(ctx context.Context, contract common.Address) ([]byte, error) {
-	return b.bcapi.GetCode(ctx, contract, rpc.PendingBlockNumber)
+	return b.bcapi.GetCode(ctx, contract, rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber))
 }
 
 // ContractCall implements bind.ContractCaller executing an Ethereum contract
 // call with the specified data as the input. The pending flag requests execution
 // against the pending block, not the stable head of the chain.
 func (b *ContractBackend) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNum *big.Int) ([]byte, error) {
-	out, err := b.bcapi.Call(ctx, toCallArgs(msg), toBlockNumber(blockNum))
+	out, err := b.bcapi.Call(ctx, toCallArgs(msg), rpc.BlockNumberOrHashWithNumber(toBlockNumber(blockNum)))
 	return out, err
 }
 
@@ -76,7 +76,7 @@ func (b *ContractBackend) CallContract(ctx context.Context, msg ethereum.CallMsg
 // call with the specified data as the input. The pending flag requests execution
 // against the pending block, not the stable head of the chain.
 func (b *ContractBackend) PendingCallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
-	out, err := b.bcapi.Call(ctx, toCallArgs(msg), rpc.PendingBlockNumber)
+	out, err := b.bcapi.Call(ctx, toCallArgs(msg), rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber))
 	return out, err
 }
 
@@ -108,7 +108,7 @@ func toBlockNumber(num *big.Int) rpc.BlockNumber {
 // PendingAccountNonce implements bind.ContractTransactor retrieving the current
 // pending nonce associated with an account.
 func (b *ContractBackend) PendingNonceAt(ctx context.Context, account common.Address) (nonce uint64, err error) {
-	out, err := b.txapi.GetTransactionCount(ctx, account, rpc.PendingBlockNumber)
+	out, err := b.txapi.GetTransactionCount(ctx, account, rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber))
 	if out != nil {
 		nonce = uint64(*out)
 	}
@@ -0,0 +1,167 @@
+package eth
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/node"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+const (
+	blockExportPath = "/debug/block/" //GET /debug/block/<高度|哈希|latest>.rlp 下载区块的RLP编码
+	blockImportPath = "/debug/import" //POST /debug/import 导入RLP编码的区块流(测试环境使用)
+	rawBlockPath    = "/eth/block/"   //GET /eth/block/<高度|哈希|latest>.rlp 下载区块的RLP编码(受eth模块控制，可以公开)
+	rawHeaderPath   = "/eth/header/"  //GET /eth/header/<高度|哈希|latest>.rlp 下载区块头的RLP编码
+	importBatchSize = 2500            //导入区块时每批插入的区块数量
+	rlpContentType  = "application/octet-stream"
+)
+
+// 导入区块的结果
+type blockImportResult struct {
+	Imported int    `json:"imported"`        //解析的区块数量(包括已经存在的区块)
+	Error    string `json:"error,omitempty"` //导入失败的原因
+}
+
+// 节点在HTTP RPC端口上提供的区块下载和导入接口，分别受所在模块控制
+func (s *Ethereum) HTTPRoutes() []node.HTTPRoute {
+	return []node.HTTPRoute{
+		{Namespace: "debug", Pattern: blockExportPath, Handler: s.rlpExportHandler(blockExportPath, false)},
+		{Namespace: "admin", Pattern: blockImportPath, Handler: http.HandlerFunc(s.serveBlockImport)},
+		{Namespace: "eth", Pattern: rawBlockPath, Handler: s.rlpExportHandler(rawBlockPath, false)},
+		{Namespace: "eth", Pattern: rawHeaderPath, Handler: s.rlpExportHandler(rawHeaderPath, true)},
+	}
+}
+
+// 以流的方式输出区块(headerOnly为真时只输出区块头)的RLP编码，避免JSON中的十六进制字符串占用双倍的内存
+func (s *Ethereum) rlpExportHandler(prefix string, headerOnly bool) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method != "GET" {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, prefix)
+		if !strings.HasSuffix(name, ".rlp") {
+			http.Error(w, "block path must end in .rlp", http.StatusNotFound)
+			return
+		}
+		block, err := s.lookupBlock(strings.TrimSuffix(name, ".rlp"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if block == nil {
+			http.Error(w, "block not found", http.StatusNotFound)
+			return
+		}
+		var value interface{} = block
+		if headerOnly {
+			value = block.Header()
+		}
+		w.Header().Set("Content-Type", rlpContentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%d.rlp", block.NumberU64()))
+		if err := rlp.Encode(w, value); err != nil {
+			log.Warn("Failed to stream block", "number", block.NumberU64(), "header", headerOnly, "err", err)
+		}
+	})
+}
+
+// 根据区块高度(十进制或0x十六进制)、区块哈希或latest得到区块
+func (s *Ethereum) lookupBlock(id string) (*types.Block, error) {
+
+	chain := s.blockchain
+	switch {
+	case id == "latest":
+		return chain.CurrentBlock(), nil
+	case len(id) == 2+2*common.HashLength && strings.HasPrefix(id, "0x"):
+		hash := common.HexToHash(id)
+		return chain.GetBlockByHash(hash), nil
+	case strings.HasPrefix(id, "0x"):
+		number, err := hexutil.DecodeUint64(id)
+		if err != nil {
+			return nil, err
+		}
+		return chain.GetBlockByNumber(number), nil
+	default:
+		number, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block %q", id)
+		}
+		return chain.GetBlockByNumber(number), nil
+	}
+}
+
+// 导入请求体中的RLP编码区块流(可以使用Content-Encoding: gzip压缩)，用于搭建测试环境
+func (s *Ethereum) serveBlockImport(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var reader io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer gz.Close()
+		reader = gz
+	}
+	imported, err := importBlocks(s.blockchain, reader)
+
+	result := &blockImportResult{Imported: imported}
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		result.Error = err.Error()
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+// 从RLP编码的区块流中分批导入区块，跳过已经存在的批次，返回解析的区块数量
+func importBlocks(chain *core.BlockChain, reader io.Reader) (int, error) {
+
+	stream := rlp.NewStream(reader, 0)
+
+	blocks, index := make([]*types.Block, 0, importBatchSize), 0
+	for batch := 0; ; batch++ {
+		// Load a batch of blocks from the input file
+		for len(blocks) < cap(blocks) {
+			block := new(types.Block)
+			if err := stream.Decode(block); err == io.EOF {
+				break
+			} else if err != nil {
+				return index, fmt.Errorf("block %d: failed to parse: %v", index, err)
+			}
+			blocks = append(blocks, block)
+			index++
+		}
+		if len(blocks) == 0 {
+			break
+		}
+
+		if hasAllBlocks(chain, blocks) {
+			blocks = blocks[:0]
+			continue
+		}
+		// Import the batch and reset the buffer
+		if _, err := chain.InsertChain(blocks); err != nil {
+			return index, fmt.Errorf("batch %d: failed to insert: %v", batch, err)
+		}
+		blocks = blocks[:0]
+	}
+	return index, nil
+}
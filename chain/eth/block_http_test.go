@@ -0,0 +1,74 @@
+package eth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+// Tests that the public download routes stream the RLP encoding of blocks and
+// headers looked up by number, hash or latest, and reject malformed requests.
+func TestRawBlockRoutes(t *testing.T) {
+	eth, cleanup := newRewindTestNode(t, 3, 0, false)
+	defer cleanup()
+
+	mux := http.NewServeMux()
+	for _, route := range eth.HTTPRoutes() {
+		mux.Handle(route.Pattern, route.Handler)
+	}
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	block := eth.blockchain.GetBlockByNumber(2)
+	for _, id := range []string{"2", "0x2", block.Hash().Hex()} {
+		resp, err := http.Get(fmt.Sprintf("%s%s%s.rlp", server.URL, rawBlockPath, id))
+		if err != nil {
+			t.Fatalf("block %s: request failed: %v", id, err)
+		}
+		have := new(types.Block)
+		err = rlp.Decode(resp.Body, have)
+		resp.Body.Close()
+		if err != nil || have.Hash() != block.Hash() || len(have.Transactions()) != 1 {
+			t.Errorf("block %s: have %x, err %v, want %x", id, have.Hash(), err, block.Hash())
+		}
+		if resp.Header.Get("Content-Type") != rlpContentType || resp.Header.Get("Content-Disposition") != "attachment; filename=2.rlp" {
+			t.Errorf("block %s: headers mismatch: %v", id, resp.Header)
+		}
+	}
+	resp, err := http.Get(server.URL + rawHeaderPath + "latest.rlp")
+	if err != nil {
+		t.Fatalf("header request failed: %v", err)
+	}
+	header := new(types.Header)
+	err = rlp.Decode(resp.Body, header)
+	resp.Body.Close()
+	if head := eth.blockchain.CurrentBlock(); err != nil || header.Hash() != head.Hash() {
+		t.Errorf("latest header: have %x, err %v, want %x", header.Hash(), err, head.Hash())
+	}
+
+	tests := []struct {
+		method, path string
+		status       int
+	}{
+		{"GET", rawBlockPath + "9.rlp", http.StatusNotFound},
+		{"GET", rawBlockPath + "2", http.StatusNotFound},
+		{"GET", rawHeaderPath + "two.rlp", http.StatusBadRequest},
+		{"GET", rawHeaderPath + "0xzz.rlp", http.StatusBadRequest},
+		{"POST", rawBlockPath + "2.rlp", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, server.URL+tt.path, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: request failed: %v", tt.method, tt.path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s %s: status mismatch: have %d, want %d", tt.method, tt.path, resp.StatusCode, tt.status)
+		}
+	}
+}
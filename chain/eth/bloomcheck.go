@@ -0,0 +1,132 @@
+package eth
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/bitutil"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/bloombits"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+//bloombits段校验发现的问题
+type BloomSectionFault struct {
+	Section hexutil.Uint64   `json:"section"`
+	Head    common.Hash      `json:"head"`             //段内最后一个区块
+	Blocks  []hexutil.Uint64 `json:"blocks,omitempty"` //区块头的bloom与收据计算结果不一致(或者缺少收据)的区块
+	Bits    []uint           `json:"bits,omitempty"`   //与区块头bloom不一致的bloombits位
+	Error   string           `json:"error,omitempty"`
+}
+
+//bloombits索引的校验结果
+type BloomIndexVerification struct {
+	From     hexutil.Uint64       `json:"from"`     //校验的第一个段
+	To       hexutil.Uint64       `json:"to"`       //校验的最后一个段
+	Indexed  uint64               `json:"indexed"`  //已经建立索引的段数量
+	Verified uint64               `json:"verified"` //已经校验的段数量
+	Elapsed  float64              `json:"elapsed"`  //校验用时(秒)
+	Faults   []*BloomSectionFault `json:"faults"`
+}
+
+//校验[from, to]范围内已经建立索引的bloombits段：按区块头的bloom重新生成每一位并与数据库中的数据比较，
+//同时用收据重新计算每个区块的bloom，检查区块头与收据是否一致
+func (s *Ethereum) verifyBloomIndex(from, to uint64) (*BloomIndexVerification, error) {
+
+	indexed, _, _ := s.bloomIndexer.Sections()
+	if indexed == 0 {
+		return nil, fmt.Errorf("no bloom sections indexed yet")
+	}
+	if to >= indexed {
+		to = indexed - 1
+	}
+	if from > to {
+		return nil, fmt.Errorf("invalid section range: from %d > to %d (indexed %d)", from, to, indexed)
+	}
+	var (
+		start  = time.Now()
+		result = &BloomIndexVerification{
+			From:    hexutil.Uint64(from),
+			To:      hexutil.Uint64(to),
+			Indexed: indexed,
+			Faults:  []*BloomSectionFault{},
+		}
+	)
+	for section := from; section <= to; section++ {
+		if fault := s.verifyBloomSection(section); fault != nil {
+			log.Error("Bloom section verification failed", "section", section, "blocks", len(fault.Blocks), "bits", len(fault.Bits), "err", fault.Error)
+			result.Faults = append(result.Faults, fault)
+		}
+		result.Verified++
+	}
+	result.Elapsed = time.Since(start).Seconds()
+
+	log.Info("Bloom index verified", "from", from, "to", to, "faults", len(result.Faults), "elapsed", common.PrettyDuration(time.Since(start)))
+	return result, nil
+}
+
+//校验一个bloombits段，没有问题时返回nil
+func (s *Ethereum) verifyBloomSection(section uint64) *BloomSectionFault {
+
+	size := params.BloomBitsBlocks
+	fault := &BloomSectionFault{
+		Section: hexutil.Uint64(section),
+		Head:    core.GetCanonicalHash(s.chainDb, (section+1)*size-1),
+	}
+	gen, err := bloombits.NewGenerator(uint(size))
+	if err != nil {
+		fault.Error = err.Error()
+		return fault
+	}
+	for number := section * size; number < (section+1)*size; number++ {
+		hash := core.GetCanonicalHash(s.chainDb, number)
+		header := core.GetHeader(s.chainDb, hash, number)
+		if header == nil {
+			fault.Error = fmt.Sprintf("canonical header #%d missing", number)
+			return fault
+		}
+		gen.AddBloom(uint(number-section*size), header.Bloom)
+
+		receipts := core.GetBlockReceipts(s.chainDb, hash, number)
+		if len(receipts) == 0 && header.TxHash != types.EmptyRootHash {
+			fault.Blocks = append(fault.Blocks, hexutil.Uint64(number))
+		} else if types.CreateBloom(receipts) != header.Bloom {
+			fault.Blocks = append(fault.Blocks, hexutil.Uint64(number))
+		}
+	}
+	for i := 0; i < types.BloomBitLength; i++ {
+		want, err := gen.Bitset(uint(i))
+		if err != nil {
+			fault.Error = err.Error()
+			return fault
+		}
+		compressed, err := core.GetBloomBits(s.chainDb, uint(i), section, fault.Head)
+		if err != nil {
+			fault.Bits = append(fault.Bits, uint(i))
+			continue
+		}
+		have, err := bitutil.DecompressBytes(compressed, int(size)/8)
+		if err != nil || !bytes.Equal(have, want) {
+			fault.Bits = append(fault.Bits, uint(i))
+		}
+	}
+	if len(fault.Blocks) == 0 && len(fault.Bits) == 0 {
+		return nil
+	}
+	return fault
+}
+
+//删除从fromSection开始的bloombits段并在后台按区块头重新建立索引，返回需要重建的段数量
+func (s *Ethereum) rebuildBloomIndex(fromSection uint64) uint64 {
+
+	dropped := s.bloomIndexer.Rewind(fromSection)
+	if dropped > 0 {
+		log.Warn("Rebuilding bloom index", "from", fromSection, "sections", dropped)
+	}
+	return dropped
+}
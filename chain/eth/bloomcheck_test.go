@@ -0,0 +1,102 @@
+package eth
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+// newBloomTestNode creates a node with a single indexed bloombits section, in
+// which block 10 carries a log of the given address.
+func newBloomTestNode(t *testing.T, address common.Address) *Ethereum {
+	db, _ := ethdb.NewMemDatabase()
+
+	receipts := types.Receipts{{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{{Address: address}}}}
+	backend := &BloomIndexer{db: db, size: params.BloomBitsBlocks}
+	if err := backend.Reset(0, common.Hash{}); err != nil {
+		t.Fatalf("failed to reset bloom indexer: %v", err)
+	}
+	for i := uint64(0); i < params.BloomBitsBlocks; i++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(i), TxHash: types.EmptyRootHash, DposProto: &types.DposContextProto{}, BokerProto: &types.BokerBackendProto{}}
+		if i > 0 {
+			header.ParentHash = core.GetCanonicalHash(db, i-1)
+		}
+		if i == 10 {
+			header.TxHash = common.Hash{0x01}
+			header.Bloom = types.CreateBloom(receipts)
+		}
+		core.WriteHeader(db, header)
+		core.WriteCanonicalHash(db, header.Hash(), i)
+		if i == 10 {
+			core.WriteBlockReceipts(db, header.Hash(), i, receipts)
+		}
+		backend.Process(header)
+	}
+	if err := backend.Commit(); err != nil {
+		t.Fatalf("failed to commit bloom section: %v", err)
+	}
+	var count [8]byte
+	binary.BigEndian.PutUint64(count[:], 1)
+	ethdb.NewTable(db, string(core.BloomBitsIndexPrefix)).Put([]byte("count"), count[:])
+
+	return &Ethereum{chainDb: db, bloomIndexer: NewBloomIndexer(db, params.BloomBitsBlocks)}
+}
+
+// Tests that the bloom index verification reports the blocks whose header bloom
+// disagrees with their receipts and the corrupted bloombits vectors.
+func TestVerifyBloomIndex(t *testing.T) {
+	address := common.HexToAddress("0x01")
+	eth := newBloomTestNode(t, address)
+	defer eth.bloomIndexer.Close()
+
+	result, err := eth.verifyBloomIndex(0, 5)
+	if err != nil {
+		t.Fatalf("failed to verify: %v", err)
+	}
+	if result.From != 0 || result.To != 0 || result.Indexed != 1 || result.Verified != 1 || len(result.Faults) != 0 {
+		t.Errorf("intact index mismatch: %+v", result)
+	}
+	if _, err := eth.verifyBloomIndex(1, 5); err == nil {
+		t.Errorf("range past the indexed sections accepted")
+	}
+
+	//收据与区块头不一致，并且破坏一位bloombits
+	head := core.GetCanonicalHash(eth.chainDb, params.BloomBitsBlocks-1)
+	core.WriteBlockReceipts(eth.chainDb, core.GetCanonicalHash(eth.chainDb, 10), 10, types.Receipts{{Status: types.ReceiptStatusSuccessful}})
+	core.WriteBloomBits(eth.chainDb, 7, 0, head, []byte{0xff})
+
+	result, err = eth.verifyBloomIndex(0, 0)
+	if err != nil {
+		t.Fatalf("failed to verify: %v", err)
+	}
+	if len(result.Faults) != 1 {
+		t.Fatalf("faults mismatch: have %d, want 1", len(result.Faults))
+	}
+	fault := result.Faults[0]
+	if fault.Head != head || len(fault.Blocks) != 1 || fault.Blocks[0] != 10 || len(fault.Bits) != 1 || fault.Bits[0] != 7 {
+		t.Errorf("fault mismatch: %+v", fault)
+	}
+}
+
+// Tests that rebuilding the bloom index drops the sections from the requested
+// one on, and that nothing is dropped past the indexed sections.
+func TestRebuildBloomIndex(t *testing.T) {
+	eth := newBloomTestNode(t, common.HexToAddress("0x01"))
+	defer eth.bloomIndexer.Close()
+
+	if dropped := eth.rebuildBloomIndex(1); dropped != 0 {
+		t.Errorf("rebuild past the indexed sections: dropped %d, want 0", dropped)
+	}
+	if dropped := eth.rebuildBloomIndex(0); dropped != 1 {
+		t.Errorf("dropped sections mismatch: have %d, want 1", dropped)
+	}
+	if _, err := eth.verifyBloomIndex(0, 0); err == nil {
+		t.Errorf("verified a dropped index")
+	}
+}
@@ -0,0 +1,200 @@
+package eth
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Tinachain/Tina/chain/accounts"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/consensus/dpos"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/log"
+)
+
+//监听区块头事件的通道大小
+const chainHeadChanSize = 10
+
+//导出的周期检查点
+type CheckpointResult struct {
+	File    string           `json:"file"`    //检查点文件
+	Number  uint64           `json:"number"`  //检查点区块高度
+	Hash    common.Hash      `json:"hash"`    //检查点区块哈希(导入时作为可信哈希)
+	Epoch   uint64           `json:"epoch"`   //检查点区块所在的周期
+	Nodes   int              `json:"nodes"`   //导出的树节点以及合约代码数量
+	Signers []common.Address `json:"signers"` //签名了检查点的验证者
+}
+
+//周期检查点导出服务：每个周期的第一个区块成为当前区块以后，将其检查点写入配置的目录
+type checkpointExporter struct {
+	eth     *Ethereum
+	dir     string
+	pending chan *types.Block //等待导出的周期区块(导出时间较长，正在导出时跳过新的周期)
+	quit    chan struct{}
+	wg      sync.WaitGroup
+}
+
+func newCheckpointExporter(eth *Ethereum, dir string) *checkpointExporter {
+	return &checkpointExporter{
+		eth:     eth,
+		dir:     dir,
+		pending: make(chan *types.Block, 1),
+		quit:    make(chan struct{}),
+	}
+}
+
+func (c *checkpointExporter) Start() {
+
+	log.Info("Epoch checkpoint exporter started", "dir", c.dir)
+	c.wg.Add(2)
+	go c.loop()
+	go c.export()
+}
+
+func (c *checkpointExporter) Stop() {
+
+	close(c.quit)
+	c.wg.Wait()
+	log.Info("Epoch checkpoint exporter stopped")
+}
+
+//监听新的区块头，找出每个周期的第一个区块
+func (c *checkpointExporter) loop() {
+
+	defer c.wg.Done()
+
+	chain := c.eth.BlockChain()
+	headCh := make(chan core.ChainHeadEvent, chainHeadChanSize)
+	headSub := chain.SubscribeChainHeadEvent(headCh)
+	defer headSub.Unsubscribe()
+
+	genesisTime := chain.Genesis().Time().Int64()
+	for {
+		select {
+		case ev := <-headCh:
+			parent := chain.GetHeader(ev.Block.ParentHash(), ev.Block.NumberU64()-1)
+			if parent == nil || ev.Block.NumberU64() <= 1 {
+				continue
+			}
+			if dpos.EpochOf(ev.Block.Time().Int64(), genesisTime) <= dpos.EpochOf(parent.Time.Int64(), genesisTime) {
+				continue
+			}
+			select {
+			case c.pending <- ev.Block:
+			default:
+				log.Warn("Skipping epoch checkpoint, previous export in progress", "number", ev.Block.Number())
+			}
+		case <-headSub.Err():
+			return
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+//在单独的线程中导出检查点，避免阻塞区块头的通知
+func (c *checkpointExporter) export() {
+
+	defer c.wg.Done()
+
+	for {
+		select {
+		case block := <-c.pending:
+			if err := os.MkdirAll(c.dir, 0700); err != nil {
+				log.Error("Failed to create checkpoint directory", "dir", c.dir, "err", err)
+				continue
+			}
+			file := filepath.Join(c.dir, fmt.Sprintf("checkpoint-%d.rlp", block.NumberU64()))
+			if _, err := c.eth.WriteCheckpoint(file, block); err != nil {
+				log.Error("Failed to export epoch checkpoint", "number", block.Number(), "err", err)
+			}
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+//将区块的检查点写入文件，本节点是该周期的验证者时使用验证者的出块签名账号签名
+func (s *Ethereum) WriteCheckpoint(file string, block *types.Block) (*CheckpointResult, error) {
+
+	checkpoint, err := s.blockchain.NewCheckpoint(block)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.signCheckpoint(checkpoint); err != nil {
+		log.Warn("Exporting unsigned checkpoint", "number", block.Number(), "err", err)
+	}
+
+	//先写入临时文件，完成以后再重命名，避免留下不完整的检查点
+	tmp := file + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	writer := bufio.NewWriter(out)
+	nodes, err := s.blockchain.ExportCheckpoint(writer, checkpoint)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, file)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+
+	signers, err := checkpoint.Signers(s.chainDb)
+	if err != nil {
+		return nil, err
+	}
+	result := &CheckpointResult{
+		File:    file,
+		Number:  block.NumberU64(),
+		Hash:    block.Hash(),
+		Epoch:   dpos.EpochOf(block.Time().Int64(), s.blockchain.Genesis().Time().Int64()),
+		Nodes:   nodes,
+		Signers: signers,
+	}
+	log.Info("Exported epoch checkpoint", "file", file, "number", result.Number, "hash", result.Hash, "nodes", nodes, "signers", len(signers))
+	return result, nil
+}
+
+//使用本节点验证者当前的出块签名账号签名检查点
+func (s *Ethereum) signCheckpoint(checkpoint *core.Checkpoint) error {
+
+	validator, err := s.Coinbase()
+	if err != nil {
+		return err
+	}
+	header := checkpoint.Block.Header()
+	dposContext, err := types.NewDposContextFromProto(s.chainDb, header.DposProto)
+	if err != nil {
+		return err
+	}
+	if !dposContext.IsValidator(validator) {
+		return fmt.Errorf("%x is not a validator of the epoch", validator)
+	}
+	bokerContext, err := types.NewBokerContextFromProto(s.chainDb, header.BokerProto)
+	if err != nil {
+		return err
+	}
+	signer := bokerContext.SigningKey(validator)
+	signFn, err := s.signerFn(signer)
+	if err != nil {
+		return err
+	}
+	hash := checkpoint.SigHash()
+	sig, err := signFn(accounts.Account{Address: signer}, hash[:])
+	if err != nil {
+		return err
+	}
+	checkpoint.Signatures = append(checkpoint.Signatures, sig)
+	return nil
+}
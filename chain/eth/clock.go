@@ -0,0 +1,131 @@
+package eth
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/metrics"
+	"github.com/Tinachain/Tina/chain/p2p/discover"
+)
+
+const (
+	clockCheckInterval = 10 * time.Minute //NTP检查的间隔
+	clockMinPeerBeats  = 3                //由验证者心跳估计时钟偏差至少需要的心跳数量
+)
+
+var (
+	ntpDriftGauge  = metrics.NewGauge("eth/clock/drift/ntp")  //与NTP服务器的时间偏差(毫秒)
+	peerDriftGauge = metrics.NewGauge("eth/clock/drift/peer") //相对于验证者心跳的时间偏差(毫秒)
+)
+
+//本地时钟的偏差估计, 偏差为正表示本地时钟超前
+type ClockDrift struct {
+	NTPDrift    *int64 `json:"ntpDrift"`    //与NTP服务器的时间偏差(毫秒，为空表示未能测量)
+	NTPChecked  int64  `json:"ntpChecked"`  //最后一次成功的NTP检查时间
+	PeerDrift   *int64 `json:"peerDrift"`   //相对于其它验证者心跳时间的偏差中位数(毫秒，心跳不足时为空)
+	PeerSamples int    `json:"peerSamples"` //参与估计的验证者心跳数量
+	Drift       int64  `json:"drift"`       //估计的时钟偏差(毫秒，优先使用NTP)
+	MaxDrift    int64  `json:"maxDrift"`    //允许出块的最大偏差(毫秒，0为不限制)
+	SealAllowed bool   `json:"sealAllowed"` //是否允许出块
+}
+
+//时钟偏差检查服务：启动时以及之后定期与NTP服务器比较本地时钟，同时根据验证者心跳估计偏差
+type clockMonitor struct {
+	eth      *Ethereum
+	maxDrift time.Duration
+
+	ntpDrift   *time.Duration
+	ntpChecked time.Time
+	lock       sync.RWMutex
+}
+
+func newClockMonitor(eth *Ethereum, maxDrift time.Duration) *clockMonitor {
+	return &clockMonitor{eth: eth, maxDrift: maxDrift}
+}
+
+//定期进行NTP检查
+func (c *clockMonitor) loop() {
+
+	ticker := time.NewTicker(clockCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		c.checkNTP()
+		select {
+		case <-ticker.C:
+		case <-c.eth.shutdownChan:
+			return
+		}
+	}
+}
+
+//与NTP服务器比较本地时钟
+func (c *clockMonitor) checkNTP() {
+
+	drift, err := discover.MeasureClockDrift()
+	if err != nil {
+		log.Debug("NTP clock check failed", "err", err)
+		return
+	}
+	c.lock.Lock()
+	c.ntpDrift, c.ntpChecked = &drift, time.Now()
+	c.lock.Unlock()
+
+	ntpDriftGauge.Update(int64(drift / time.Millisecond))
+	if c.exceeds(drift) {
+		log.Warn("System clock drift exceeds the sealing bound, validator slots will be skipped", "drift", drift, "max", c.maxDrift)
+	} else {
+		log.Debug("NTP clock check done", "drift", drift)
+	}
+}
+
+//偏差是否超过允许出块的上限
+func (c *clockMonitor) exceeds(drift time.Duration) bool {
+	return c.maxDrift > 0 && (drift > c.maxDrift || drift < -c.maxDrift)
+}
+
+//得到当前的时钟偏差估计
+func (c *clockMonitor) Drift() *ClockDrift {
+
+	result := &ClockDrift{MaxDrift: int64(c.maxDrift / time.Millisecond)}
+	estimate := time.Duration(0)
+
+	offsets := c.eth.protocolManager.heartbeats.offsets(time.Now())
+	result.PeerSamples = len(offsets)
+	if len(offsets) >= clockMinPeerBeats {
+		sort.Sort(durationSlice(offsets))
+		median := offsets[len(offsets)/2]
+		peer := int64(median / time.Millisecond)
+		result.PeerDrift, estimate = &peer, median
+		peerDriftGauge.Update(peer)
+	}
+
+	c.lock.RLock()
+	if c.ntpDrift != nil {
+		ntp := int64(*c.ntpDrift / time.Millisecond)
+		result.NTPDrift, result.NTPChecked, estimate = &ntp, c.ntpChecked.Unix(), *c.ntpDrift
+	}
+	c.lock.RUnlock()
+
+	result.Drift = int64(estimate / time.Millisecond)
+	result.SealAllowed = !c.exceeds(estimate)
+	return result
+}
+
+//出块前检查本地时钟偏差
+func (c *clockMonitor) sealGuard() error {
+
+	if drift := c.Drift(); !drift.SealAllowed {
+		return fmt.Errorf("clock drift %dms exceeds %dms", drift.Drift, drift.MaxDrift)
+	}
+	return nil
+}
+
+type durationSlice []time.Duration
+
+func (s durationSlice) Len() int           { return len(s) }
+func (s durationSlice) Less(i, j int) bool { return s[i] < s[j] }
+func (s durationSlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
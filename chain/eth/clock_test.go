@@ -0,0 +1,47 @@
+package eth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/common"
+)
+
+// Tests that the clock drift is estimated from validator heartbeats once enough
+// are known, that NTP measurements take precedence and that sealing is refused
+// beyond the configured bound.
+func TestClockDrift(t *testing.T) {
+	heartbeats := newHeartbeatSet()
+	monitor := newClockMonitor(&Ethereum{protocolManager: &ProtocolManager{heartbeats: heartbeats}}, 2*time.Second)
+
+	now := time.Now()
+	beat := func(validator byte, ahead time.Duration, peer string) {
+		heartbeats.beats[common.Address{validator}] = &receivedHeartbeat{
+			data:     &heartbeatData{Time: uint64(now.Add(-ahead).Unix())},
+			received: now,
+			peer:     peer,
+		}
+	}
+	beat(1, 10*time.Second, "peer")
+	beat(2, 10*time.Second, "peer")
+	beat(3, 10*time.Second, "") // own heartbeat is no sample
+
+	if drift := monitor.Drift(); drift.PeerDrift != nil || !drift.SealAllowed {
+		t.Fatalf("drift estimated from too few heartbeats: %+v", drift)
+	}
+	beat(4, 11*time.Second, "peer")
+
+	drift := monitor.Drift()
+	if drift.PeerSamples != 3 || drift.PeerDrift == nil || *drift.PeerDrift < 9000 || *drift.PeerDrift > 11000 {
+		t.Fatalf("peer drift mismatch: %+v", drift)
+	}
+	if drift.SealAllowed || monitor.sealGuard() == nil {
+		t.Errorf("sealing allowed with drift %dms", drift.Drift)
+	}
+
+	ntp := 500 * time.Millisecond
+	monitor.ntpDrift, monitor.ntpChecked = &ntp, now
+	if drift := monitor.Drift(); drift.Drift != 500 || !drift.SealAllowed || monitor.sealGuard() != nil {
+		t.Errorf("NTP drift not preferred: %+v", drift)
+	}
+}
@@ -20,9 +20,13 @@ import (
 	"math/big"
 	"os"
 	"os/user"
+	"time"
 
+	"github.com/Tinachain/Tina/chain/boker/bridge"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/consensus/dpos/remotesigner"
 	"github.com/Tinachain/Tina/chain/core"
 	"github.com/Tinachain/Tina/chain/eth/downloader"
 	"github.com/Tinachain/Tina/chain/eth/gasprice"
@@ -41,8 +45,27 @@ var DefaultConfig = Config{
 		Blocks:     10,
 		Percentile: 50,
 	},
+	RPCTimeout: time.Minute,
+	RPCTimeouts: map[string]time.Duration{
+		"eth_call": 5 * time.Second,
+	},
+	MaxClockDrift: 2 * time.Second,
+	SystemTxDedup: SystemTxDedupReject,
+
+	TxIpMode:       protocol.IpModeFull,
+	RPCIpRedaction: protocol.IpModeFull,
+
+	TrieDirtyCache: core.DefaultTrieDirtyCache,
+	RPCCacheSize:   4096,
 }
 
+//节点提交系统交易时交易池中已有相同交易的处理方式
+const (
+	SystemTxDedupOff    = "off"    //不检查，每次都提交新的交易
+	SystemTxDedupReject = "reject" //拒绝提交并返回错误
+	SystemTxDedupReuse  = "reuse"  //不提交新的交易，返回交易池中已有的交易
+)
+
 func init() {
 	home := os.Getenv("HOME")
 	if home == "" {
@@ -55,26 +78,58 @@ func init() {
 //go:generate gencodec -type Config -field-override configMarshaling -formats toml -out gen_config.go
 
 type Config struct {
-	Genesis                 *core.Genesis       `toml:",omitempty"` //genesis块，如果数据库为空则插入。如果为nil，则使用以太坊主网块。
-	NetworkId               uint64              //用于选择要连接的其它节点的网络ID
-	SyncMode                downloader.SyncMode //是否同步模式
-	LightServ               int                 `toml:",omitempty"` // Maximum percentage of time allowed for serving LES requests
-	LightPeers              int                 `toml:",omitempty"` // Maximum number of LES client peers
-	SkipBcVersionCheck      bool                `toml:"-"`
-	DatabaseHandles         int                 `toml:"-"`
+	Genesis                 *core.Genesis             `toml:",omitempty"` //genesis块，如果数据库为空则插入。如果为nil，则使用以太坊主网块。
+	NetworkId               uint64                    //用于选择要连接的其它节点的网络ID
+	SyncMode                downloader.SyncMode       //是否同步模式
+	LightServ               int                       `toml:",omitempty"` // Maximum percentage of time allowed for serving LES requests
+	LightPeers              int                       `toml:",omitempty"` // Maximum number of LES client peers
+	Checkpoint              *params.TrustedCheckpoint `toml:",omitempty"` //轻节点使用的可信检查点(为空时从创世区块开始同步)
+	SkipBcVersionCheck      bool                      `toml:"-"`
+	DatabaseHandles         int                       `toml:"-"`
 	DatabaseCache           int
-	Coinbase                common.Address    `toml:",omitempty"` //矿工账号
-	MinerThreads            int               `toml:",omitempty"` //挖矿线程数量
-	ExtraData               []byte            `toml:",omitempty"` //扩展字段
-	GasPrice                *big.Int          //交易价格
-	TxPool                  core.TxPoolConfig //交易池配置
-	GPO                     gasprice.Config   //Gas配置
-	EnablePreimageRecording bool              //是否允许跟踪VM中的SHA3 preimages
-	DocRoot                 string            `toml:"-"`
-	PowFake                 bool              `toml:"-"`
-	PowTest                 bool              `toml:"-"`
-	PowShared               bool              `toml:"-"`
-	Dpos                    bool              `toml:"-"`
+	Coinbase                common.Address           `toml:",omitempty"` //矿工账号
+	SigningKey              common.Address           `toml:",omitempty"` //出块签名账号(为空时使用矿工账号签名)
+	RemoteSigner            *remotesigner.Config     `toml:",omitempty"` //远程签名服务(设置后不再使用本地账号签名区块)
+	Bridge                  *bridge.Config           `toml:",omitempty"` //跨链桥中继服务(设置后挖矿账号中继外部链上的销毁事件)
+	MinerThreads            int                      `toml:",omitempty"` //挖矿线程数量
+	ExtraData               []byte                   `toml:",omitempty"` //扩展字段
+	GasPrice                *big.Int                 //交易价格
+	TxPool                  core.TxPoolConfig        //交易池配置
+	GPO                     gasprice.Config          //Gas配置
+	EnablePreimageRecording bool                     //是否允许跟踪VM中的SHA3 preimages
+	InternalTxIndex         bool                     //是否在导入区块时索引内部交易
+	TokenIndex              bool                     //是否在导入区块时索引代币转账
+	NameIndex               bool                     //是否在导入区块时按名称索引交易
+	RichListSize            int                      `toml:",omitempty"` //余额排行榜的账号数量(0为不维护排行榜)
+	EthCompatible           bool                     `toml:",omitempty"` //eth_*接口是否严格按以太坊格式输出(Tina扩展字段通过boker_*接口获取)
+	AllowUnprotectedTxs     bool                     `toml:",omitempty"` //EIP155生效后是否仍然接受未包含链ID的交易(这类交易可以在其他链上重放)
+	RPCTimeout              time.Duration            `toml:",omitempty"` //读取区块、执行调用、查询日志以及跟踪等RPC请求的默认执行期限(0为不限制)
+	RPCTimeouts             map[string]time.Duration `toml:",omitempty"` //按方法名(如eth_call)设置的执行期限，优先于默认期限
+	CheckpointDir           string                   `toml:",omitempty"` //每个周期导出状态检查点的目录(为空时不导出)
+	MaxClockDrift           time.Duration            `toml:",omitempty"` //本地时钟偏差超过该值时拒绝出块(0为不限制)
+	SystemTxDedup           string                   `toml:",omitempty"` //交易池中已有相同的系统交易(交易类型、发送账号、接收地址以及负载都相同)时的处理方式
+	TxIpMode                string                   `toml:",omitempty"` //节点提交交易时写入IP字段的方式(full、subnet、hash或off)
+	TxIpSalt                string                   `toml:",omitempty"` //hash方式使用的盐
+	RPCIpRedaction          string                   `toml:",omitempty"` //RPC输出交易IP字段的方式(full、subnet、hash或off)
+	DatabaseRepair          bool                     `toml:",omitempty"` //启动时链头数据损坏则截断到最后一个完整可用的区块并重建交易索引
+	ReadOnly                bool                     `toml:",omitempty"` //只读副本模式：只跟随同步区块，不出块、不接收交易
+	Archive                 bool                     `toml:",omitempty"` //归档模式：保证每个历史区块的状态都可以查询(只能全量同步)
+	TrieDirtyCache          int                      `toml:",omitempty"` //状态树脏节点缓存上限(MB)，超过后在后台写入磁盘
+	RPCCacheSize            int                      `toml:",omitempty"` //已确认区块的不可变RPC查询结果的缓存条目数量(0为不缓存)
+	VirtualPending          bool                     `toml:",omitempty"` //不出块时pending查询使用试执行交易池交易组装的虚拟区块
+	DocRoot                 string                   `toml:"-"`
+	PowFake                 bool                     `toml:"-"`
+	PowTest                 bool                     `toml:"-"`
+	PowShared               bool                     `toml:"-"`
+	Dpos                    bool                     `toml:"-"`
+}
+
+//得到RPC方法的执行期限，没有单独设置时使用默认期限，0为不限制
+func (c *Config) RPCDeadline(method string) time.Duration {
+	if timeout, ok := c.RPCTimeouts[method]; ok {
+		return timeout
+	}
+	return c.RPCTimeout
 }
 
 type configMarshaling struct {
@@ -133,3 +133,83 @@ func upgradeDeduplicateData(db ethdb.Database) func() error {
 		return <-errc
 	}
 }
+
+var txLookupBackfill = []byte("dbUpgrade_20180601txLookupBackfill")
+
+// upgradeTxLookups walks the canonical chain and writes the lookup entries of
+// transactions that don't have one (blocks imported by releases that lost or
+// never wrote them), so that transactions and receipts can be retrieved by hash.
+// The upgrade only starts once the deduplication upgrade finished, otherwise it
+// is retried on the next start. Returns a stop function that blocks until the
+// process has been safely stopped.
+func upgradeTxLookups(db ethdb.Database) func() error {
+	// If the database is already converted or empty, bail out
+	data, _ := db.Get(txLookupBackfill)
+	if len(data) > 0 && data[0] == 42 {
+		return nil
+	}
+	head := core.GetHeadBlockHash(db)
+	if head == (common.Hash{}) {
+		db.Put(txLookupBackfill, []byte{42})
+		return nil
+	}
+	if data, _ := db.Get(deduplicateData); len(data) == 0 || data[0] != 42 {
+		return nil
+	}
+	last := core.GetBlockNumber(db, head)
+	if last == core.MissingNumber {
+		return nil
+	}
+	// Start the backfill on a new goroutine
+	log.Info("Backfilling transaction lookup entries", "head", last)
+	stop := make(chan chan error)
+
+	go func() {
+		var (
+			backfilled uint64
+			failed     error
+		)
+		for number := uint64(0); failed == nil && number <= last; number++ {
+			hash := core.GetCanonicalHash(db, number)
+			if hash == (common.Hash{}) {
+				continue
+			}
+			block := core.GetBlock(db, hash, number)
+			if block == nil {
+				continue
+			}
+			for _, tx := range block.Transactions() {
+				if blockHash, _, _ := core.GetTxLookupEntry(db, tx.Hash()); blockHash == (common.Hash{}) {
+					failed = core.WriteTxLookupEntries(db, block)
+					backfilled++
+					break
+				}
+			}
+			if number%100000 == 0 && number > 0 {
+				log.Info("Backfilling transaction lookup entries", "number", number, "blocks", backfilled)
+			}
+			// Check for termination, or continue after a bit of a timeout
+			select {
+			case errc := <-stop:
+				errc <- nil
+				return
+			case <-time.After(time.Microsecond * 100):
+			}
+		}
+		// Upgrade finished, mark a such and terminate
+		if failed == nil {
+			log.Info("Transaction lookup backfill successful", "blocks", backfilled)
+			db.Put(txLookupBackfill, []byte{42})
+		} else {
+			log.Error("Transaction lookup backfill failed", "blocks", backfilled, "err", failed)
+		}
+		errc := <-stop
+		errc <- failed
+	}()
+	// Assembly the cancellation callback
+	return func() error {
+		errc := make(chan error)
+		stop <- errc
+		return <-errc
+	}
+}
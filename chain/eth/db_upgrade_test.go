@@ -0,0 +1,67 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+)
+
+// Tests that the lookup backfill indexes the transactions of canonical blocks
+// missing their lookup entries and marks the database as upgraded.
+func TestUpgradeTxLookups(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+
+	var (
+		parent common.Hash
+		txs    []*types.Transaction
+	)
+	for i := 0; i < 3; i++ {
+		tx := types.NewTransaction(protocol.Normal, protocol.NormalCall, uint64(i), common.Address{0x01}, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil)
+		header := &types.Header{ParentHash: parent, Number: big.NewInt(int64(i)), DposProto: &types.DposContextProto{}, BokerProto: &types.BokerBackendProto{}}
+		block := types.NewBlock(header, []*types.Transaction{tx}, nil, nil)
+		if err := core.WriteBlock(db, block); err != nil {
+			t.Fatalf("failed to write block %d: %v", i, err)
+		}
+		core.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		core.WriteHeadBlockHash(db, block.Hash())
+		parent = block.Hash()
+		txs = append(txs, tx)
+	}
+	//去重升级未完成时不进行回填
+	if stop := upgradeTxLookups(db); stop != nil {
+		stop()
+		t.Fatalf("backfill started before the deduplication upgrade")
+	}
+	db.Put(deduplicateData, []byte{42})
+
+	stop := upgradeTxLookups(db)
+	if stop == nil {
+		t.Fatalf("backfill not started")
+	}
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if data, _ := db.Get(txLookupBackfill); len(data) > 0 && data[0] == 42 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("backfill did not finish")
+		}
+	}
+	if err := stop(); err != nil {
+		t.Fatalf("backfill failed: %v", err)
+	}
+	for i, tx := range txs {
+		if hash, number, index := core.GetTxLookupEntry(db, tx.Hash()); hash != core.GetCanonicalHash(db, uint64(i)) || number != uint64(i) || index != 0 {
+			t.Errorf("transaction %d: lookup mismatch: have %x/%d/%d", i, hash, number, index)
+		}
+	}
+	if stop := upgradeTxLookups(db); stop != nil {
+		stop()
+		t.Errorf("backfill restarted on an upgraded database")
+	}
+}
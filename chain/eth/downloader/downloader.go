@@ -115,6 +115,10 @@ type Downloader struct {
 	syncStatsState       stateSyncStats
 	syncStatsLock        sync.RWMutex // Lock protecting the sync stats fields
 
+	syncStatsStarted     time.Time // Time the current sync cycle started at
+	syncStatsStartBlock  uint64    // Current block number when the sync cycle started
+	syncStatsStartStates uint64    // Number of pulled states when the sync cycle started
+
 	lightchain LightChain
 	blockchain BlockChain
 
@@ -246,24 +250,28 @@ func (d *Downloader) Progress() ethereum.SyncProgress {
 	d.syncStatsLock.RLock()
 	defer d.syncStatsLock.RUnlock()
 
-	current := uint64(0)
-	switch d.mode {
-	case FullSync:
-		current = d.blockchain.CurrentBlock().NumberU64()
-	case FastSync:
-		current = d.blockchain.CurrentFastBlock().NumberU64()
-	case LightSync:
-		current = d.lightchain.CurrentHeader().Number.Uint64()
-	}
 	return ethereum.SyncProgress{
 		StartingBlock: d.syncStatsChainOrigin,
-		CurrentBlock:  current,
+		CurrentBlock:  d.currentHead(),
 		HighestBlock:  d.syncStatsChainHeight,
 		PulledStates:  d.syncStatsState.processed,
 		KnownStates:   d.syncStatsState.processed + d.syncStatsState.pending,
 	}
 }
 
+// currentHead returns the number of the head the current sync mode progresses.
+func (d *Downloader) currentHead() uint64 {
+	switch d.mode {
+	case FullSync:
+		return d.blockchain.CurrentBlock().NumberU64()
+	case FastSync:
+		return d.blockchain.CurrentFastBlock().NumberU64()
+	case LightSync:
+		return d.lightchain.CurrentHeader().Number.Uint64()
+	}
+	return 0
+}
+
 // Synchronising returns whether the downloader is currently retrieving blocks.
 func (d *Downloader) Synchronising() bool {
 	return atomic.LoadInt32(&d.synchronising) > 0
@@ -435,6 +443,9 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 		d.syncStatsChainOrigin = origin
 	}
 	d.syncStatsChainHeight = height
+	d.syncStatsStarted = time.Now()
+	d.syncStatsStartBlock = d.currentHead()
+	d.syncStatsStartStates = d.syncStatsState.processed
 	d.syncStatsLock.Unlock()
 
 	// Initiate the sync using a concurrent header and content retrieval algorithm
@@ -57,6 +57,10 @@ type peerConnection struct {
 	blockThroughput   float64                  // Number of blocks (bodies) measured to be retrievable per second
 	receiptThroughput float64                  // Number of receipts measured to be retrievable per second
 	stateThroughput   float64                  // Number of node data pieces measured to be retrievable per second
+	headersDelivered  uint64                   // Total number of headers delivered by the peer
+	bodiesDelivered   uint64                   // Total number of block bodies delivered by the peer
+	receiptsDelivered uint64                   // Total number of receipts delivered by the peer
+	statesDelivered   uint64                   // Total number of node data pieces delivered by the peer
 	rtt               time.Duration            // Request round trip time to track responsiveness (QoS)
 	headerStarted     time.Time                // Time instance when the last header fetch was started
 	blockStarted      time.Time                // Time instance when the last block (body) fetch was started
@@ -220,40 +224,40 @@ func (p *peerConnection) FetchNodeData(hashes []common.Hash) error {
 // requests. Its estimated header retrieval throughput is updated with that measured
 // just now.
 func (p *peerConnection) SetHeadersIdle(delivered int) {
-	p.setIdle(p.headerStarted, delivered, &p.headerThroughput, &p.headerIdle)
+	p.setIdle(p.headerStarted, delivered, &p.headerThroughput, &p.headersDelivered, &p.headerIdle)
 }
 
 // SetBlocksIdle sets the peer to idle, allowing it to execute new block retrieval
 // requests. Its estimated block retrieval throughput is updated with that measured
 // just now.
 func (p *peerConnection) SetBlocksIdle(delivered int) {
-	p.setIdle(p.blockStarted, delivered, &p.blockThroughput, &p.blockIdle)
+	p.setIdle(p.blockStarted, delivered, &p.blockThroughput, &p.bodiesDelivered, &p.blockIdle)
 }
 
 // SetBodiesIdle sets the peer to idle, allowing it to execute block body retrieval
 // requests. Its estimated body retrieval throughput is updated with that measured
 // just now.
 func (p *peerConnection) SetBodiesIdle(delivered int) {
-	p.setIdle(p.blockStarted, delivered, &p.blockThroughput, &p.blockIdle)
+	p.setIdle(p.blockStarted, delivered, &p.blockThroughput, &p.bodiesDelivered, &p.blockIdle)
 }
 
 // SetReceiptsIdle sets the peer to idle, allowing it to execute new receipt
 // retrieval requests. Its estimated receipt retrieval throughput is updated
 // with that measured just now.
 func (p *peerConnection) SetReceiptsIdle(delivered int) {
-	p.setIdle(p.receiptStarted, delivered, &p.receiptThroughput, &p.receiptIdle)
+	p.setIdle(p.receiptStarted, delivered, &p.receiptThroughput, &p.receiptsDelivered, &p.receiptIdle)
 }
 
 // SetNodeDataIdle sets the peer to idle, allowing it to execute new state trie
 // data retrieval requests. Its estimated state retrieval throughput is updated
 // with that measured just now.
 func (p *peerConnection) SetNodeDataIdle(delivered int) {
-	p.setIdle(p.stateStarted, delivered, &p.stateThroughput, &p.stateIdle)
+	p.setIdle(p.stateStarted, delivered, &p.stateThroughput, &p.statesDelivered, &p.stateIdle)
 }
 
 // setIdle sets the peer to idle, allowing it to execute new retrieval requests.
 // Its estimated retrieval throughput is updated with that measured just now.
-func (p *peerConnection) setIdle(started time.Time, delivered int, throughput *float64, idle *int32) {
+func (p *peerConnection) setIdle(started time.Time, delivered int, throughput *float64, total *uint64, idle *int32) {
 	// Irrelevant of the scaling, make sure the peer ends up idle
 	defer atomic.StoreInt32(idle, 0)
 
@@ -268,8 +272,16 @@ func (p *peerConnection) setIdle(started time.Time, delivered int, throughput *f
 	// Otherwise update the throughput with a new measurement
 	elapsed := time.Since(started) + 1 // +1 (ns) to ensure non-zero divisor
 	measured := float64(delivered) / (float64(elapsed) / float64(time.Second))
+	*total += uint64(delivered)
 
-	*throughput = (1-measurementImpact)*(*throughput) + measurementImpact*measured
+	// Without a previous estimate (new peer or after a timeout) take the measurement
+	// as is. Large blocks on high-latency links yield few measurements per minute,
+	// so blending from zero would keep the request sizes minimal for a long time.
+	if *throughput == 0 {
+		*throughput = measured
+	} else {
+		*throughput = (1-measurementImpact)*(*throughput) + measurementImpact*measured
+	}
 	p.rtt = time.Duration((1-measurementImpact)*float64(p.rtt) + measurementImpact*float64(elapsed))
 
 	p.log.Trace("Peer throughput measurements updated",
@@ -0,0 +1,128 @@
+package downloader
+
+import (
+	"context"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+// Phases of a synchronisation cycle reported by DetailedProgress.
+const (
+	PhaseIdle    = "idle"    // No synchronisation in progress
+	PhaseHeaders = "headers" // Light sync retrieving headers
+	PhaseBlocks  = "blocks"  // Retrieving and importing blocks (or receipts)
+	PhaseState   = "state"   // Fast sync retrieving the pivot state trie
+)
+
+// syncProgressInterval is the interval progress subscriptions are notified at.
+const syncProgressInterval = 2 * time.Second
+
+// DetailedProgress is the synchronisation progress together with the rates
+// measured since the sync cycle started and the resulting time estimate.
+type DetailedProgress struct {
+	Syncing         bool           `json:"syncing"`
+	Phase           string         `json:"phase"`
+	Mode            string         `json:"mode"`
+	StartingBlock   hexutil.Uint64 `json:"startingBlock"`
+	CurrentBlock    hexutil.Uint64 `json:"currentBlock"`
+	HighestBlock    hexutil.Uint64 `json:"highestBlock"`
+	PulledStates    hexutil.Uint64 `json:"pulledStates"`
+	KnownStates     hexutil.Uint64 `json:"knownStates"`
+	BlocksPerSecond float64        `json:"blocksPerSecond"`
+	StatesPerSecond float64        `json:"statesPerSecond"`
+	ETA             *uint64        `json:"eta"` // Estimated seconds left, nil if unknown
+}
+
+// DetailedProgress retrieves the synchronisation progress along with the
+// current phase, import rates and an estimate of the time left.
+func (d *Downloader) DetailedProgress() *DetailedProgress {
+	syncing := d.Synchronising()
+
+	d.syncStatsLock.RLock()
+	defer d.syncStatsLock.RUnlock()
+
+	current := d.currentHead()
+	progress := &DetailedProgress{
+		Syncing:       syncing,
+		Phase:         PhaseIdle,
+		Mode:          d.mode.String(),
+		StartingBlock: hexutil.Uint64(d.syncStatsChainOrigin),
+		CurrentBlock:  hexutil.Uint64(current),
+		HighestBlock:  hexutil.Uint64(d.syncStatsChainHeight),
+		PulledStates:  hexutil.Uint64(d.syncStatsState.processed),
+		KnownStates:   hexutil.Uint64(d.syncStatsState.processed + d.syncStatsState.pending),
+	}
+	if !syncing {
+		return progress
+	}
+	switch {
+	case d.mode == LightSync:
+		progress.Phase = PhaseHeaders
+	case d.mode == FastSync && d.syncStatsState.pending > 0:
+		progress.Phase = PhaseState
+	default:
+		progress.Phase = PhaseBlocks
+	}
+	if elapsed := time.Since(d.syncStatsStarted).Seconds(); elapsed > 0 {
+		if current > d.syncStatsStartBlock {
+			progress.BlocksPerSecond = float64(current-d.syncStatsStartBlock) / elapsed
+		}
+		if d.syncStatsState.processed > d.syncStatsStartStates {
+			progress.StatesPerSecond = float64(d.syncStatsState.processed-d.syncStatsStartStates) / elapsed
+		}
+	}
+	// The state trie size is unknown up front, so only the block phases can be
+	// estimated; the state phase reports its rate without a time estimate.
+	if progress.Phase != PhaseState && progress.BlocksPerSecond > 0 {
+		left := uint64(0)
+		if d.syncStatsChainHeight > current {
+			left = d.syncStatsChainHeight - current
+		}
+		eta := uint64(float64(left) / progress.BlocksPerSecond)
+		progress.ETA = &eta
+	}
+	return progress
+}
+
+// SyncProgressDetailed returns the synchronisation progress including the
+// current phase, import rates and the estimated time left.
+func (api *PublicDownloaderAPI) SyncProgressDetailed() *DetailedProgress {
+	return api.d.DetailedProgress()
+}
+
+// SyncProgress creates a subscription that periodically emits the detailed
+// synchronisation progress while the node is syncing, and a final update once
+// it becomes idle.
+func (api *PublicDownloaderAPI) SyncProgress(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		ticker := time.NewTicker(syncProgressInterval)
+		defer ticker.Stop()
+
+		wasSyncing := false
+		for {
+			select {
+			case <-ticker.C:
+				progress := api.d.DetailedProgress()
+				if progress.Syncing || wasSyncing {
+					notifier.Notify(rpcSub.ID, progress)
+				}
+				wasSyncing = progress.Syncing
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
@@ -0,0 +1,82 @@
+package downloader
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/core/types"
+)
+
+// progressChain is a chain stuck at a fixed head, for both the block and the
+// light sync modes.
+type progressChain struct {
+	BlockChain
+	head uint64
+}
+
+func (c *progressChain) CurrentHeader() *types.Header {
+	return &types.Header{Number: new(big.Int).SetUint64(c.head)}
+}
+
+func (c *progressChain) CurrentBlock() *types.Block {
+	return types.NewBlockWithHeader(c.CurrentHeader())
+}
+
+func (c *progressChain) CurrentFastBlock() *types.Block {
+	return types.NewBlockWithHeader(c.CurrentHeader())
+}
+
+// Tests that the detailed progress reports the sync phase of each mode, and
+// the import rates and time estimate measured since the sync cycle started.
+func TestDetailedProgress(t *testing.T) {
+	chain := &progressChain{head: 100}
+	d := &Downloader{mode: FullSync, blockchain: chain, lightchain: chain}
+
+	//没有同步时只报告区块号
+	progress := (&PublicDownloaderAPI{d: d}).SyncProgressDetailed()
+	if progress.Syncing || progress.Phase != PhaseIdle || progress.CurrentBlock != 100 || progress.ETA != nil || progress.BlocksPerSecond != 0 {
+		t.Errorf("idle progress mismatch: %+v", progress)
+	}
+
+	//10秒内从区块50同步到区块100，还剩200个区块
+	d.synchronising = 1
+	d.syncStatsChainOrigin, d.syncStatsChainHeight = 40, 300
+	d.syncStatsStarted = time.Now().Add(-10 * time.Second)
+	d.syncStatsStartBlock = 50
+
+	progress = d.DetailedProgress()
+	if progress.Phase != PhaseBlocks || progress.Mode != "full" || progress.StartingBlock != 40 || progress.HighestBlock != 300 {
+		t.Errorf("full sync progress mismatch: %+v", progress)
+	}
+	if progress.BlocksPerSecond < 4.9 || progress.BlocksPerSecond > 5 {
+		t.Errorf("block rate mismatch: have %v, want ~5", progress.BlocksPerSecond)
+	}
+	if progress.ETA == nil || *progress.ETA < 39 || *progress.ETA > 40 {
+		t.Errorf("eta mismatch: have %v, want ~40", progress.ETA)
+	}
+
+	d.mode = LightSync
+	if progress := d.DetailedProgress(); progress.Phase != PhaseHeaders || progress.Mode != "light" {
+		t.Errorf("light sync phase mismatch: %s/%s", progress.Phase, progress.Mode)
+	}
+
+	//快速同步下载状态时状态总量未知，因此没有时间估计
+	d.mode = FastSync
+	d.syncStatsStartStates = 1000
+	d.syncStatsState = stateSyncStats{processed: 3000, pending: 500}
+	progress = d.DetailedProgress()
+	if progress.Phase != PhaseState || progress.ETA != nil || progress.PulledStates != 3000 || progress.KnownStates != 3500 {
+		t.Errorf("state sync progress mismatch: %+v", progress)
+	}
+	if progress.StatesPerSecond < 199 || progress.StatesPerSecond > 200 {
+		t.Errorf("state rate mismatch: have %v, want ~200", progress.StatesPerSecond)
+	}
+
+	//没有进展时速度未知
+	d.mode = FullSync
+	d.syncStatsStartBlock = 100
+	if progress := d.DetailedProgress(); progress.Phase != PhaseBlocks || progress.BlocksPerSecond != 0 || progress.ETA != nil {
+		t.Errorf("stalled progress mismatch: %+v", progress)
+	}
+}
@@ -0,0 +1,85 @@
+package downloader
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// PeerStats is the download performance of a single peer as estimated by the
+// downloader. Request sizes towards the peer are derived from these values.
+type PeerStats struct {
+	ID                string  `json:"id"`
+	Version           int     `json:"version"`
+	HeaderThroughput  float64 `json:"headerThroughput"`  // Headers per second
+	BlockThroughput   float64 `json:"blockThroughput"`   // Block bodies per second
+	ReceiptThroughput float64 `json:"receiptThroughput"` // Receipts per second
+	StateThroughput   float64 `json:"stateThroughput"`   // Node data pieces per second
+	RTT               int64   `json:"rtt"`               // Measured round trip time in milliseconds
+	HeadersDelivered  uint64  `json:"headersDelivered"`
+	BodiesDelivered   uint64  `json:"bodiesDelivered"`
+	ReceiptsDelivered uint64  `json:"receiptsDelivered"`
+	StatesDelivered   uint64  `json:"statesDelivered"`
+	BlockCapacity     int     `json:"blockCapacity"`   // Bodies asked for in the next request
+	ReceiptCapacity   int     `json:"receiptCapacity"` // Receipts asked for in the next request
+}
+
+// Stats is the quality of service state of the downloader together with the
+// per-peer estimates.
+type Stats struct {
+	TargetRTT  int64        `json:"targetRTT"`  // Round trip time requests are sized for, in milliseconds
+	RequestTTL int64        `json:"requestTTL"` // Timeout allowance of a single request, in milliseconds
+	Confidence float64      `json:"confidence"` // Confidence in the estimated round trip time
+	Peers      []*PeerStats `json:"peers"`      // Peers ordered by block body throughput
+}
+
+// Stats retrieves the current download estimates of the downloader and its peers.
+func (d *Downloader) Stats() *Stats {
+	rtt := d.requestRTT()
+	stats := &Stats{
+		TargetRTT:  int64(rtt / time.Millisecond),
+		RequestTTL: int64(d.requestTTL() / time.Millisecond),
+		Confidence: float64(atomic.LoadUint64(&d.rttConfidence)) / 1000000.0,
+		Peers:      []*PeerStats{},
+	}
+	for _, p := range d.peers.AllPeers() {
+		p.lock.RLock()
+		peer := &PeerStats{
+			ID:                p.id,
+			Version:           p.version,
+			HeaderThroughput:  p.headerThroughput,
+			BlockThroughput:   p.blockThroughput,
+			ReceiptThroughput: p.receiptThroughput,
+			StateThroughput:   p.stateThroughput,
+			RTT:               int64(p.rtt / time.Millisecond),
+			HeadersDelivered:  p.headersDelivered,
+			BodiesDelivered:   p.bodiesDelivered,
+			ReceiptsDelivered: p.receiptsDelivered,
+			StatesDelivered:   p.statesDelivered,
+		}
+		p.lock.RUnlock()
+
+		peer.BlockCapacity, peer.ReceiptCapacity = p.BlockCapacity(rtt), p.ReceiptCapacity(rtt)
+		stats.Peers = append(stats.Peers, peer)
+	}
+	sort.Slice(stats.Peers, func(i, j int) bool {
+		return stats.Peers[i].BlockThroughput > stats.Peers[j].BlockThroughput
+	})
+	return stats
+}
+
+// PublicDownloaderStatsAPI exposes the download estimates of the downloader.
+type PublicDownloaderStatsAPI struct {
+	d *Downloader
+}
+
+// NewPublicDownloaderStatsAPI creates an API serving the download estimates.
+func NewPublicDownloaderStatsAPI(d *Downloader) *PublicDownloaderStatsAPI {
+	return &PublicDownloaderStatsAPI{d}
+}
+
+// Stats returns the target round trip time of download requests and the
+// throughput, round trip time and request sizes estimated for each peer.
+func (api *PublicDownloaderStatsAPI) Stats() *Stats {
+	return api.d.Stats()
+}
@@ -0,0 +1,76 @@
+package downloader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/log"
+)
+
+// Tests that the first measurement of a peer seeds its throughput directly,
+// later ones are blended in, and a timeout starts the estimate over.
+func TestPeerThroughputSeeding(t *testing.T) {
+	p := newPeerConnection("peer", 63, nil, log.New("peer", "peer"))
+
+	p.blockStarted = time.Now().Add(-time.Second)
+	p.SetBodiesIdle(100)
+	if p.blockThroughput < 90 || p.blockThroughput > 100 {
+		t.Fatalf("seeded throughput mismatch: have %v, want ~100", p.blockThroughput)
+	}
+	seeded := p.blockThroughput
+
+	p.blockStarted = time.Now().Add(-time.Second)
+	p.SetBodiesIdle(200)
+	if want := (1-measurementImpact)*seeded + measurementImpact*200; p.blockThroughput > want || p.blockThroughput < want-20 {
+		t.Errorf("blended throughput mismatch: have %v, want ~%v", p.blockThroughput, want)
+	}
+
+	//超时后吞吐量归零，下一次测量重新作为初始值
+	p.SetBodiesIdle(0)
+	if p.blockThroughput != 0 {
+		t.Errorf("throughput after timeout: have %v, want 0", p.blockThroughput)
+	}
+	p.blockStarted = time.Now().Add(-time.Second)
+	p.SetBodiesIdle(50)
+	if p.blockThroughput < 45 || p.blockThroughput > 50 {
+		t.Errorf("reseeded throughput mismatch: have %v, want ~50", p.blockThroughput)
+	}
+	if p.bodiesDelivered != 350 || p.headersDelivered != 0 {
+		t.Errorf("delivered mismatch: have %d bodies, %d headers, want 350, 0", p.bodiesDelivered, p.headersDelivered)
+	}
+}
+
+// Tests that the downloader stats report the estimates of every peer ordered by
+// block body throughput.
+func TestDownloaderStats(t *testing.T) {
+	d := &Downloader{peers: newPeerSet(), rttEstimate: uint64(rttMaxEstimate), rttConfidence: 500000}
+
+	stats := NewPublicDownloaderStatsAPI(d).Stats()
+	if stats.Peers == nil || len(stats.Peers) != 0 {
+		t.Errorf("peers without any registered: have %v, want empty", stats.Peers)
+	}
+	if stats.TargetRTT != int64(d.requestRTT()/time.Millisecond) || stats.RequestTTL != int64(ttlLimit/time.Millisecond) || stats.Confidence != 0.5 {
+		t.Errorf("qos mismatch: have %+v", stats)
+	}
+
+	slow := newPeerConnection("slow", 62, nil, log.New("peer", "slow"))
+	fast := newPeerConnection("fast", 63, nil, log.New("peer", "fast"))
+	for _, p := range []*peerConnection{slow, fast} {
+		if err := d.peers.Register(p); err != nil {
+			t.Fatalf("failed to register %s: %v", p.id, err)
+		}
+	}
+	slow.blockThroughput, slow.bodiesDelivered = 10, 20
+	fast.blockThroughput, fast.receiptsDelivered = 1000, 30
+
+	stats = d.Stats()
+	if len(stats.Peers) != 2 || stats.Peers[0].ID != "fast" || stats.Peers[1].ID != "slow" {
+		t.Fatalf("peer order mismatch: have %v", stats.Peers)
+	}
+	if peer := stats.Peers[0]; peer.Version != 63 || peer.ReceiptsDelivered != 30 || peer.BlockCapacity != MaxBlockFetch {
+		t.Errorf("fast peer mismatch: %+v", peer)
+	}
+	if peer := stats.Peers[1]; peer.BodiesDelivered != 20 || peer.BlockCapacity != slow.BlockCapacity(d.requestRTT()) {
+		t.Errorf("slow peer mismatch: %+v", peer)
+	}
+}
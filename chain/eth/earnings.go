@@ -0,0 +1,90 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//单次汇总允许的最大区块范围
+const maxEarningsBlocks = 100000
+
+//出块账号在区块范围内的收益汇总(来自每个区块的奖励记录)
+type Earnings struct {
+	Address             common.Address `json:"address"`
+	FromBlock           hexutil.Uint64 `json:"fromBlock"`
+	ToBlock             hexutil.Uint64 `json:"toBlock"`
+	Blocks              hexutil.Uint64 `json:"blocks"`              //范围内产生的区块数
+	ProducerReward      *hexutil.Big   `json:"producerReward"`      //出块奖励中出块节点保留的部分
+	StockPayouts        *hexutil.Big   `json:"stockPayouts"`        //作为股权账号得到的股权分红
+	Total               *hexutil.Big   `json:"total"`               //计入账号余额的合计(出块奖励与股权分红之和)
+	GasFees             *hexutil.Big   `json:"gasFees"`             //所产生区块中交易的Gas费用
+	GasPoolContribution *hexutil.Big   `json:"gasPoolContribution"` //所产生区块放入股权Gas池的数量
+	MissingRecords      hexutil.Uint64 `json:"missingRecords"`      //没有奖励记录的区块数(记录出现之前导入的区块)
+}
+
+//按每个区块的奖励记录汇总账号在[from, to]范围内的出块收益以及股权分红，结束区块超过当前区块时截断到当前区块
+func (s *Ethereum) earnings(ctx context.Context, address common.Address, fromBlock, toBlock rpc.BlockNumber) (*Earnings, error) {
+
+	if fromBlock == rpc.PendingBlockNumber || toBlock == rpc.PendingBlockNumber {
+		return nil, fmt.Errorf("pending block has no reward records")
+	}
+	head := s.blockchain.CurrentBlock().NumberU64()
+	from, to := uint64(fromBlock), uint64(toBlock)
+	if fromBlock == rpc.LatestBlockNumber {
+		from = head
+	}
+	if toBlock == rpc.LatestBlockNumber || to > head {
+		to = head
+	}
+	if from > to {
+		return nil, fmt.Errorf("invalid block range: from %d > to %d", from, to)
+	}
+	if to-from >= maxEarningsBlocks {
+		return nil, fmt.Errorf("block range too large: %d blocks, at most %d", to-from+1, maxEarningsBlocks)
+	}
+
+	var (
+		blocks, missing            uint64
+		reward, payouts, fees, gas = new(big.Int), new(big.Int), new(big.Int), new(big.Int)
+	)
+	for number := from; number <= to; number++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		hash := core.GetCanonicalHash(s.chainDb, number)
+		rewards := core.GetBlockRewards(s.chainDb, hash, number)
+		if rewards == nil {
+			missing++
+			continue
+		}
+		if rewards.Producer == address {
+			blocks++
+			reward.Add(reward, rewards.ProducerReward)
+			fees.Add(fees, rewards.GasFees)
+			gas.Add(gas, rewards.GasPoolContribution)
+		}
+		for _, payout := range rewards.StockPayouts {
+			if payout.Account == address {
+				payouts.Add(payouts, payout.Amount)
+			}
+		}
+	}
+	return &Earnings{
+		Address:             address,
+		FromBlock:           hexutil.Uint64(from),
+		ToBlock:             hexutil.Uint64(to),
+		Blocks:              hexutil.Uint64(blocks),
+		ProducerReward:      (*hexutil.Big)(reward),
+		StockPayouts:        (*hexutil.Big)(payouts),
+		Total:               (*hexutil.Big)(new(big.Int).Add(reward, payouts)),
+		GasFees:             (*hexutil.Big)(fees),
+		GasPoolContribution: (*hexutil.Big)(gas),
+		MissingRecords:      hexutil.Uint64(missing),
+	}, nil
+}
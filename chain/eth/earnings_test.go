@@ -0,0 +1,78 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/consensus/ethash"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/core/vm"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+// Tests that earnings are summed from the reward records of the produced blocks
+// and the stock payouts, and that blocks without records are counted.
+func TestEarnings(t *testing.T) {
+	var (
+		db, _     = ethdb.NewMemDatabase()
+		genesis   = (&core.Genesis{Config: params.TestChainConfig}).MustCommit(db)
+		producer  = common.Address{0xaa}
+		other     = common.Address{0xbb}
+		producers = []common.Address{producer, other, producer}
+	)
+	//区块1没有奖励记录
+	parent := genesis
+	for i, coinbase := range producers {
+		header := &types.Header{ParentHash: parent.Hash(), Number: big.NewInt(int64(i + 1)), Root: genesis.Root(), Coinbase: coinbase, DposProto: &types.DposContextProto{}, BokerProto: &types.BokerBackendProto{}}
+		block := types.NewBlock(header, nil, nil, nil)
+		core.WriteBlock(db, block)
+		core.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		core.WriteHeadBlockHash(db, block.Hash())
+		if i > 0 {
+			core.WriteBlockRewards(db, block.Hash(), block.NumberU64(), &types.BlockRewards{
+				Producer:            coinbase,
+				ProducerReward:      big.NewInt(100),
+				GasFees:             big.NewInt(10),
+				GasPoolContribution: big.NewInt(2),
+				StockPayouts:        []types.StockPayout{{Account: other, Amount: big.NewInt(5)}, {Account: producer, Amount: big.NewInt(7)}},
+			})
+		}
+		parent = block
+	}
+	chain, err := core.NewBlockChain(db, params.TestChainConfig, ethash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+	eth := &Ethereum{blockchain: chain, chainDb: db}
+
+	earnings, err := eth.earnings(context.Background(), producer, 0, rpc.LatestBlockNumber)
+	if err != nil {
+		t.Fatalf("failed to summarize earnings: %v", err)
+	}
+	//创世区块以及区块1没有记录，区块3由producer产生，区块2和3都向producer分红
+	if earnings.FromBlock != 0 || earnings.ToBlock != 3 || earnings.Blocks != 1 || earnings.MissingRecords != 2 {
+		t.Errorf("range mismatch: have %+v", earnings)
+	}
+	if earnings.ProducerReward.ToInt().Int64() != 100 || earnings.StockPayouts.ToInt().Int64() != 14 || earnings.Total.ToInt().Int64() != 114 {
+		t.Errorf("earnings mismatch: reward %v, payouts %v, total %v", earnings.ProducerReward, earnings.StockPayouts, earnings.Total)
+	}
+	if earnings.GasFees.ToInt().Int64() != 10 || earnings.GasPoolContribution.ToInt().Int64() != 2 {
+		t.Errorf("gas mismatch: fees %v, pool %v", earnings.GasFees, earnings.GasPoolContribution)
+	}
+
+	if earnings, err := eth.earnings(context.Background(), other, 2, 10); err != nil || earnings.ToBlock != 3 || earnings.Blocks != 1 || earnings.StockPayouts.ToInt().Int64() != 10 {
+		t.Errorf("truncated range: have %+v, err %v", earnings, err)
+	}
+	if _, err := eth.earnings(context.Background(), producer, 3, 2); err == nil {
+		t.Errorf("inverted range accepted")
+	}
+	if _, err := eth.earnings(context.Background(), producer, 0, rpc.PendingBlockNumber); err == nil {
+		t.Errorf("pending range accepted")
+	}
+}
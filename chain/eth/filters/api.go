@@ -26,11 +26,13 @@ import (
 	"time"
 
 	"github.com/Tinachain/Tina/chain/boker/api"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/common/hexutil"
 	"github.com/Tinachain/Tina/chain/core/types"
 	"github.com/Tinachain/Tina/chain/ethdb"
 	"github.com/Tinachain/Tina/chain/event"
+	"github.com/Tinachain/Tina/chain/internal/ethapi"
 	"github.com/Tinachain/Tina/chain/rpc"
 )
 
@@ -60,6 +62,7 @@ type PublicFilterAPI struct {
 	filtersMu sync.Mutex
 	filters   map[rpc.ID]*filter
 	boker     bokerapi.Api
+	watcher   *addressWatcher
 }
 
 // NewPublicFilterAPI returns a new PublicFilterAPI instance.
@@ -70,6 +73,7 @@ func NewPublicFilterAPI(backend Backend, lightMode bool, boker bokerapi.Api) *Pu
 		chainDb: backend.ChainDb(),
 		events:  NewEventSystem(backend.EventMux(), backend, lightMode),
 		filters: make(map[rpc.ID]*filter),
+		watcher: newAddressWatcher(),
 	}
 	go api.timeoutLoop()
 
@@ -216,7 +220,37 @@ func (api *PublicFilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, er
 		for {
 			select {
 			case h := <-headers:
-				notifier.Notify(rpcSub.ID, h)
+				notifier.Notify(rpcSub.ID, ethapi.RPCMarshalHeader(h, api.backend.EthCompatible()))
+			case <-rpcSub.Err():
+				headersSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				headersSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+//创建订阅，每个新的链头推送精简的区块头(包含验证者以及Dpos/Boker状态根)
+func (api *PublicFilterAPI) CompactHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		headers := make(chan *types.Header)
+		headersSub := api.events.SubscribeNewHeads(headers)
+
+		for {
+			select {
+			case h := <-headers:
+				notifier.Notify(rpcSub.ID, ethapi.RPCMarshalCompactHeader(h))
 			case <-rpcSub.Err():
 				headersSub.Unsubscribe()
 				return
@@ -333,11 +367,14 @@ func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([
 		crit.ToBlock = big.NewInt(rpc.LatestBlockNumber.Int64())
 	}
 	// Create and run the filter to get all the logs
+	ctx, cancel := withRPCTimeout(ctx, api.backend, "eth_getLogs")
+	defer cancel()
+
 	filter := New(api.backend, crit.FromBlock.Int64(), crit.ToBlock.Int64(), crit.Addresses, crit.Topics)
 
 	logs, err := filter.Logs(ctx)
 	if err != nil {
-		return nil, err
+		return nil, logsError(ctx, err)
 	}
 	return returnLogs(logs), err
 }
@@ -381,15 +418,34 @@ func (api *PublicFilterAPI) GetFilterLogs(ctx context.Context, id rpc.ID) ([]*ty
 		end = f.crit.ToBlock.Int64()
 	}
 	// Create and run the filter to get all the logs
+	ctx, cancel := withRPCTimeout(ctx, api.backend, "eth_getFilterLogs")
+	defer cancel()
+
 	filter := New(api.backend, begin, end, f.crit.Addresses, f.crit.Topics)
 
 	logs, err := filter.Logs(ctx)
 	if err != nil {
-		return nil, err
+		return nil, logsError(ctx, err)
 	}
 	return returnLogs(logs), nil
 }
 
+// withRPCTimeout applies the configured deadline of the given RPC method to ctx.
+func withRPCTimeout(ctx context.Context, backend Backend, method string) (context.Context, context.CancelFunc) {
+	if timeout := backend.RPCTimeout(method); timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// logsError reports a filter aborted by the method deadline as a timeout.
+func logsError(ctx context.Context, err error) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return protocol.ErrRPCTimeout
+	}
+	return err
+}
+
 // GetFilterChanges returns the logs for the filter with the given id since
 // last time it was called. This can be used for polling.
 //
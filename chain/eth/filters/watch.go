@@ -0,0 +1,260 @@
+package filters
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/internal/ethapi"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//节点最多关注的账号数量
+const maxWatchedAddresses = 1000
+
+//关注账号的事件类型
+const (
+	AddressEventTransaction = "transaction" //账号发出或接收的交易
+	AddressEventBalance     = "balance"     //账号余额变化
+	AddressEventStock       = "stock"       //账号股权数量变化
+)
+
+var errTooManyWatched = errors.New("too many watched addresses")
+
+//关注账号的事件
+type AddressEvent struct {
+	Type            string          `json:"type"`
+	Address         common.Address  `json:"address"`
+	BlockNumber     hexutil.Uint64  `json:"blockNumber"`
+	BlockHash       common.Hash     `json:"blockHash"`
+	TransactionHash *common.Hash    `json:"transactionHash,omitempty"`
+	Direction       string          `json:"direction,omitempty"` //in、out或self
+	From            *common.Address `json:"from,omitempty"`
+	To              *common.Address `json:"to,omitempty"`
+	Value           *hexutil.Big    `json:"value,omitempty"`
+	Balance         *hexutil.Big    `json:"balance,omitempty"`
+	PreviousBalance *hexutil.Big    `json:"previousBalance,omitempty"`
+	Stock           *hexutil.Uint64 `json:"stock,omitempty"`
+	PreviousStock   *hexutil.Uint64 `json:"previousStock,omitempty"`
+}
+
+//节点关注的账号列表
+type addressWatcher struct {
+	mu        sync.RWMutex
+	addresses map[common.Address]struct{}
+}
+
+func newAddressWatcher() *addressWatcher {
+	return &addressWatcher{addresses: make(map[common.Address]struct{})}
+}
+
+func (w *addressWatcher) add(address common.Address) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.addresses[address]; ok {
+		return false, nil
+	}
+	if len(w.addresses) >= maxWatchedAddresses {
+		return false, errTooManyWatched
+	}
+	w.addresses[address] = struct{}{}
+	return true, nil
+}
+
+func (w *addressWatcher) remove(address common.Address) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.addresses[address]; !ok {
+		return false
+	}
+	delete(w.addresses, address)
+	return true
+}
+
+func (w *addressWatcher) list() []common.Address {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	addresses := make([]common.Address, 0, len(w.addresses))
+	for address := range w.addresses {
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+func (w *addressWatcher) contains(address common.Address) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	_, ok := w.addresses[address]
+	return ok
+}
+
+//将账号加入关注列表, 新加入时返回true
+func (api *PublicFilterAPI) WatchAddress(address common.Address) (bool, error) {
+	return api.watcher.add(address)
+}
+
+//将账号移出关注列表, 账号不在列表中时返回false
+func (api *PublicFilterAPI) UnwatchAddress(address common.Address) bool {
+	return api.watcher.remove(address)
+}
+
+//得到关注列表中的账号
+func (api *PublicFilterAPI) WatchedAddresses() []common.Address {
+	return api.watcher.list()
+}
+
+//创建订阅, 在区块导入时推送关注账号的交易、余额以及股权变化
+//轻节点没有本地状态, 只推送交易事件
+func (api *PublicFilterAPI) AddressEvents(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		chainEvents := make(chan core.ChainEvent, chainEvChanSize)
+		chainSub := api.backend.SubscribeChainEvent(chainEvents)
+		defer chainSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-chainEvents:
+				for _, event := range api.addressEvents(ev.Block) {
+					notifier.Notify(rpcSub.ID, event)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+//得到区块中关注账号的事件
+func (api *PublicFilterAPI) addressEvents(block *types.Block) []*AddressEvent {
+
+	watched := api.watcher.list()
+	if len(watched) == 0 {
+		return nil
+	}
+	var (
+		events []*AddressEvent
+		hash   = block.Hash()
+		number = block.NumberU64()
+	)
+	newEvent := func(typ string, address common.Address) *AddressEvent {
+		return &AddressEvent{Type: typ, Address: address, BlockNumber: hexutil.Uint64(number), BlockHash: hash}
+	}
+
+	for _, tx := range block.Transactions() {
+		from, err := types.Sender(ethapi.TxSigner(tx), tx)
+		if err != nil {
+			continue
+		}
+		txHash, to := tx.Hash(), tx.To()
+		for _, address := range []common.Address{from, toAddress(to)} {
+			if address == (common.Address{}) || !api.watcher.contains(address) {
+				continue
+			}
+			event := newEvent(AddressEventTransaction, address)
+			event.TransactionHash, event.From, event.To = &txHash, &from, to
+			event.Value = (*hexutil.Big)(tx.Value())
+			switch {
+			case from == address && to != nil && *to == address:
+				event.Direction = "self"
+			case from == address:
+				event.Direction = "out"
+			default:
+				event.Direction = "in"
+			}
+			events = append(events, event)
+			if to != nil && *to == from {
+				break
+			}
+		}
+	}
+
+	if api.events.lightMode || number == 0 {
+		return events
+	}
+	return append(events, api.stateEvents(block, watched, newEvent)...)
+}
+
+//比较区块与父区块的状态, 得到关注账号的余额和股权变化
+func (api *PublicFilterAPI) stateEvents(block *types.Block, watched []common.Address, newEvent func(string, common.Address) *AddressEvent) []*AddressEvent {
+
+	parent := core.GetHeader(api.chainDb, block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil
+	}
+	//通过后端读取状态，最近区块的状态可能还没有写入磁盘
+	current, _, err := api.backend.StateAndHeaderByHash(context.Background(), block.Hash())
+	if err != nil {
+		log.Debug("Failed to open state for watched addresses", "number", block.NumberU64(), "err", err)
+		return nil
+	}
+	previous, _, err := api.backend.StateAndHeaderByHash(context.Background(), block.ParentHash())
+	if err != nil {
+		log.Debug("Failed to open parent state for watched addresses", "number", parent.Number, "err", err)
+		return nil
+	}
+
+	var events []*AddressEvent
+	for _, address := range watched {
+		balance, prevBalance := current.GetBalance(address), previous.GetBalance(address)
+		if balance.Cmp(prevBalance) != 0 {
+			event := newEvent(AddressEventBalance, address)
+			event.Balance, event.PreviousBalance = (*hexutil.Big)(balance), (*hexutil.Big)(prevBalance)
+			events = append(events, event)
+		}
+	}
+
+	if block.Header().BokerProto == nil || parent.BokerProto == nil {
+		return events
+	}
+	currentCtx, err := types.NewBokerContextFromProto(api.chainDb, block.Header().BokerProto)
+	if err != nil {
+		return events
+	}
+	previousCtx, err := types.NewBokerContextFromProto(api.chainDb, parent.BokerProto)
+	if err != nil {
+		return events
+	}
+	for _, address := range watched {
+		stock, prevStock := stockNumber(currentCtx, address), stockNumber(previousCtx, address)
+		if stock != prevStock {
+			event := newEvent(AddressEventStock, address)
+			event.Stock, event.PreviousStock = &stock, &prevStock
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func stockNumber(bokerContext *types.BokerContext, address common.Address) hexutil.Uint64 {
+	if stock := bokerContext.GetStock(address); stock != nil {
+		return hexutil.Uint64(stock.Number)
+	}
+	return 0
+}
+
+func toAddress(to *common.Address) common.Address {
+	if to == nil {
+		return common.Address{}
+	}
+	return *to
+}
@@ -0,0 +1,142 @@
+package filters
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/event"
+)
+
+// Tests that the watch list only accepts an address once and is limited in size.
+func TestWatchAddresses(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	backend := &testBackend{new(event.TypeMux), db, 0, new(event.Feed), new(event.Feed), new(event.Feed), new(event.Feed)}
+	api := NewPublicFilterAPI(backend, false, nil)
+
+	address := common.HexToAddress("0x01")
+	if added, err := api.WatchAddress(address); !added || err != nil {
+		t.Errorf("first watch: have %v, err %v", added, err)
+	}
+	if added, err := api.WatchAddress(address); added || err != nil {
+		t.Errorf("second watch: have %v, err %v", added, err)
+	}
+	if watched := api.WatchedAddresses(); len(watched) != 1 || watched[0] != address {
+		t.Errorf("watched addresses mismatch: %v", watched)
+	}
+	if !api.UnwatchAddress(address) || api.UnwatchAddress(address) {
+		t.Errorf("unwatch mismatch")
+	}
+	if watched := api.WatchedAddresses(); watched == nil || len(watched) != 0 {
+		t.Errorf("watched addresses after unwatch: %v", watched)
+	}
+
+	for i := 0; i < maxWatchedAddresses; i++ {
+		if _, err := api.WatchAddress(common.BigToAddress(big.NewInt(int64(i + 1)))); err != nil {
+			t.Fatalf("address %d: failed to watch: %v", i, err)
+		}
+	}
+	if _, err := api.WatchAddress(common.HexToAddress("0xffff")); err != errTooManyWatched {
+		t.Errorf("watch beyond the limit: have %v, want %v", err, errTooManyWatched)
+	}
+}
+
+// Tests that the events of a block report the transactions of the watched
+// addresses and their balance and stock changes against the parent block.
+func TestAddressEvents(t *testing.T) {
+	var (
+		db, _       = ethdb.NewMemDatabase()
+		backend     = &testBackend{new(event.TypeMux), db, 0, new(event.Feed), new(event.Feed), new(event.Feed), new(event.Feed)}
+		api         = NewPublicFilterAPI(backend, false, nil)
+		keyA, _     = crypto.GenerateKey()
+		keyC, _     = crypto.GenerateKey()
+		keyD, _     = crypto.GenerateKey()
+		addrA       = crypto.PubkeyToAddress(keyA.PublicKey)
+		addrB       = common.HexToAddress("0xb0")
+		addrC       = crypto.PubkeyToAddress(keyC.PublicKey)
+		dposContext = &types.DposContextProto{}
+	)
+	sign := func(key *ecdsa.PrivateKey, nonce uint64, to common.Address) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(protocol.Normal, protocol.NormalCall, nonce, to, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil), types.HomesteadSigner{}, key)
+		return tx
+	}
+	commit := func(balance int64, stock uint64) (common.Hash, *types.BokerBackendProto) {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+		statedb.AddBalance(addrA, big.NewInt(balance))
+		root, err := statedb.CommitTo(db, false)
+		if err != nil {
+			t.Fatalf("failed to commit state: %v", err)
+		}
+		bokerContext, _ := types.NewBokerContext(db)
+		if stock > 0 {
+			if err := bokerContext.SetStock(common.Address{}, addrA, stock); err != nil {
+				t.Fatalf("failed to set stock: %v", err)
+			}
+		}
+		proto, err := bokerContext.CommitTo(db)
+		if err != nil {
+			t.Fatalf("failed to commit boker context: %v", err)
+		}
+		return root, proto
+	}
+	root, proto := commit(100, 0)
+	parent := &types.Header{Number: big.NewInt(1), Root: root, DposProto: dposContext, BokerProto: proto}
+	core.WriteHeader(db, parent)
+
+	root, proto = commit(150, 10)
+	header := &types.Header{ParentHash: parent.Hash(), Number: big.NewInt(2), Root: root, DposProto: dposContext, BokerProto: proto}
+	block := types.NewBlock(header, types.Transactions{
+		sign(keyA, 0, addrB),
+		sign(keyC, 0, addrA),
+		sign(keyA, 1, addrA),
+		sign(keyD, 0, addrC),
+	}, nil, nil)
+	core.WriteHeader(db, block.Header())
+
+	if events := api.addressEvents(block); events != nil {
+		t.Errorf("events without watched addresses: %v", events)
+	}
+	api.WatchAddress(addrA)
+	api.WatchAddress(addrB)
+
+	want := []struct {
+		typ       string
+		address   common.Address
+		direction string
+	}{
+		{AddressEventTransaction, addrA, "out"},
+		{AddressEventTransaction, addrB, "in"},
+		{AddressEventTransaction, addrA, "in"},
+		{AddressEventTransaction, addrA, "self"},
+		{AddressEventBalance, addrA, ""},
+		{AddressEventStock, addrA, ""},
+	}
+	events := api.addressEvents(block)
+	if len(events) != len(want) {
+		t.Fatalf("events mismatch: have %d, want %d", len(events), len(want))
+	}
+	for i, w := range want {
+		if have := events[i]; have.Type != w.typ || have.Address != w.address || have.Direction != w.direction || have.BlockHash != block.Hash() || have.BlockNumber != 2 {
+			t.Errorf("event %d mismatch: have %s/%x/%s, want %s/%x/%s", i, have.Type, have.Address, have.Direction, w.typ, w.address, w.direction)
+		}
+	}
+	if event := events[4]; event.Balance.ToInt().Int64() != 150 || event.PreviousBalance.ToInt().Int64() != 100 {
+		t.Errorf("balance change mismatch: have %v, previous %v", event.Balance, event.PreviousBalance)
+	}
+	if event := events[5]; *event.Stock != 10 || *event.PreviousStock != 0 {
+		t.Errorf("stock change mismatch: have %d, previous %d", *event.Stock, *event.PreviousStock)
+	}
+
+	//轻节点没有本地状态，只推送交易事件
+	api.events.lightMode = true
+	if events := api.addressEvents(block); len(events) != 4 {
+		t.Errorf("light mode events mismatch: have %d, want 4", len(events))
+	}
+}
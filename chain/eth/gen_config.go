@@ -4,12 +4,16 @@ package eth
 
 import (
 	"math/big"
+	"time"
 
+	"github.com/Tinachain/Tina/chain/boker/bridge"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/consensus/dpos/remotesigner"
 	"github.com/Tinachain/Tina/chain/core"
 	"github.com/Tinachain/Tina/chain/eth/downloader"
 	"github.com/Tinachain/Tina/chain/eth/gasprice"
+	"github.com/Tinachain/Tina/chain/params"
 )
 
 var _ = (*configMarshaling)(nil)
@@ -20,24 +24,48 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		Genesis            *core.Genesis `toml:",omitempty"`
 		NetworkId          uint64
 		SyncMode           downloader.SyncMode
-		LightServ          int  `toml:",omitempty"`
-		LightPeers         int  `toml:",omitempty"`
-		SkipBcVersionCheck bool `toml:"-"`
-		DatabaseHandles    int  `toml:"-"`
+		LightServ          int                       `toml:",omitempty"`
+		LightPeers         int                       `toml:",omitempty"`
+		Checkpoint         *params.TrustedCheckpoint `toml:",omitempty"`
+		SkipBcVersionCheck bool                      `toml:"-"`
+		DatabaseHandles    int                       `toml:"-"`
 		DatabaseCache      int
 		//Validator               common.Address `toml:",omitempty"`
-		Coinbase                common.Address `toml:",omitempty"`
-		MinerThreads            int            `toml:",omitempty"`
-		ExtraData               hexutil.Bytes  `toml:",omitempty"`
+		Coinbase                common.Address       `toml:",omitempty"`
+		SigningKey              common.Address       `toml:",omitempty"`
+		RemoteSigner            *remotesigner.Config `toml:",omitempty"`
+		Bridge                  *bridge.Config       `toml:",omitempty"`
+		MinerThreads            int                  `toml:",omitempty"`
+		ExtraData               hexutil.Bytes        `toml:",omitempty"`
 		GasPrice                *big.Int
 		TxPool                  core.TxPoolConfig
 		GPO                     gasprice.Config
 		EnablePreimageRecording bool
-		DocRoot                 string `toml:"-"`
-		PowFake                 bool   `toml:"-"`
-		PowTest                 bool   `toml:"-"`
-		PowShared               bool   `toml:"-"`
-		Dpos                    bool   `toml:"-"`
+		InternalTxIndex         bool
+		TokenIndex              bool
+		NameIndex               bool
+		RichListSize            int                      `toml:",omitempty"`
+		EthCompatible           bool                     `toml:",omitempty"`
+		AllowUnprotectedTxs     bool                     `toml:",omitempty"`
+		RPCTimeout              time.Duration            `toml:",omitempty"`
+		RPCTimeouts             map[string]time.Duration `toml:",omitempty"`
+		CheckpointDir           string                   `toml:",omitempty"`
+		MaxClockDrift           time.Duration            `toml:",omitempty"`
+		SystemTxDedup           string                   `toml:",omitempty"`
+		TxIpMode                string                   `toml:",omitempty"`
+		TxIpSalt                string                   `toml:",omitempty"`
+		RPCIpRedaction          string                   `toml:",omitempty"`
+		DatabaseRepair          bool                     `toml:",omitempty"`
+		ReadOnly                bool                     `toml:",omitempty"`
+		Archive                 bool                     `toml:",omitempty"`
+		TrieDirtyCache          int                      `toml:",omitempty"`
+		RPCCacheSize            int                      `toml:",omitempty"`
+		VirtualPending          bool                     `toml:",omitempty"`
+		DocRoot                 string                   `toml:"-"`
+		PowFake                 bool                     `toml:"-"`
+		PowTest                 bool                     `toml:"-"`
+		PowShared               bool                     `toml:"-"`
+		Dpos                    bool                     `toml:"-"`
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -45,17 +73,41 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.SyncMode = c.SyncMode
 	enc.LightServ = c.LightServ
 	enc.LightPeers = c.LightPeers
+	enc.Checkpoint = c.Checkpoint
 	enc.SkipBcVersionCheck = c.SkipBcVersionCheck
 	enc.DatabaseHandles = c.DatabaseHandles
 	enc.DatabaseCache = c.DatabaseCache
 	//enc.Validator = c.Validator
 	enc.Coinbase = c.Coinbase
+	enc.SigningKey = c.SigningKey
+	enc.RemoteSigner = c.RemoteSigner
+	enc.Bridge = c.Bridge
 	enc.MinerThreads = c.MinerThreads
 	enc.ExtraData = c.ExtraData
 	enc.GasPrice = c.GasPrice
 	enc.TxPool = c.TxPool
 	enc.GPO = c.GPO
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
+	enc.InternalTxIndex = c.InternalTxIndex
+	enc.TokenIndex = c.TokenIndex
+	enc.NameIndex = c.NameIndex
+	enc.RichListSize = c.RichListSize
+	enc.EthCompatible = c.EthCompatible
+	enc.AllowUnprotectedTxs = c.AllowUnprotectedTxs
+	enc.RPCTimeout = c.RPCTimeout
+	enc.RPCTimeouts = c.RPCTimeouts
+	enc.CheckpointDir = c.CheckpointDir
+	enc.MaxClockDrift = c.MaxClockDrift
+	enc.SystemTxDedup = c.SystemTxDedup
+	enc.TxIpMode = c.TxIpMode
+	enc.TxIpSalt = c.TxIpSalt
+	enc.RPCIpRedaction = c.RPCIpRedaction
+	enc.DatabaseRepair = c.DatabaseRepair
+	enc.ReadOnly = c.ReadOnly
+	enc.Archive = c.Archive
+	enc.TrieDirtyCache = c.TrieDirtyCache
+	enc.RPCCacheSize = c.RPCCacheSize
+	enc.VirtualPending = c.VirtualPending
 	enc.DocRoot = c.DocRoot
 	enc.PowFake = c.PowFake
 	enc.PowTest = c.PowTest
@@ -70,24 +122,48 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		Genesis                 *core.Genesis `toml:",omitempty"`
 		NetworkId               *uint64
 		SyncMode                *downloader.SyncMode
-		LightServ               *int  `toml:",omitempty"`
-		LightPeers              *int  `toml:",omitempty"`
-		SkipBcVersionCheck      *bool `toml:"-"`
-		DatabaseHandles         *int  `toml:"-"`
+		LightServ               *int                      `toml:",omitempty"`
+		LightPeers              *int                      `toml:",omitempty"`
+		Checkpoint              *params.TrustedCheckpoint `toml:",omitempty"`
+		SkipBcVersionCheck      *bool                     `toml:"-"`
+		DatabaseHandles         *int                      `toml:"-"`
 		DatabaseCache           *int
-		Validator               *common.Address `toml:",omitempty"`
-		Coinbase                *common.Address `toml:",omitempty"`
-		MinerThreads            *int            `toml:",omitempty"`
-		ExtraData               *hexutil.Bytes  `toml:",omitempty"`
+		Validator               *common.Address      `toml:",omitempty"`
+		Coinbase                *common.Address      `toml:",omitempty"`
+		SigningKey              *common.Address      `toml:",omitempty"`
+		RemoteSigner            *remotesigner.Config `toml:",omitempty"`
+		Bridge                  *bridge.Config       `toml:",omitempty"`
+		MinerThreads            *int                 `toml:",omitempty"`
+		ExtraData               *hexutil.Bytes       `toml:",omitempty"`
 		GasPrice                *big.Int
 		TxPool                  *core.TxPoolConfig
 		GPO                     *gasprice.Config
 		EnablePreimageRecording *bool
-		DocRoot                 *string `toml:"-"`
-		PowFake                 *bool   `toml:"-"`
-		PowTest                 *bool   `toml:"-"`
-		PowShared               *bool   `toml:"-"`
-		Dpos                    *bool   `toml:"-"`
+		InternalTxIndex         *bool
+		TokenIndex              *bool
+		NameIndex               *bool
+		RichListSize            *int                     `toml:",omitempty"`
+		EthCompatible           *bool                    `toml:",omitempty"`
+		AllowUnprotectedTxs     *bool                    `toml:",omitempty"`
+		RPCTimeout              *time.Duration           `toml:",omitempty"`
+		RPCTimeouts             map[string]time.Duration `toml:",omitempty"`
+		CheckpointDir           *string                  `toml:",omitempty"`
+		MaxClockDrift           *time.Duration           `toml:",omitempty"`
+		SystemTxDedup           *string                  `toml:",omitempty"`
+		TxIpMode                *string                  `toml:",omitempty"`
+		TxIpSalt                *string                  `toml:",omitempty"`
+		RPCIpRedaction          *string                  `toml:",omitempty"`
+		DatabaseRepair          *bool                    `toml:",omitempty"`
+		ReadOnly                *bool                    `toml:",omitempty"`
+		Archive                 *bool                    `toml:",omitempty"`
+		TrieDirtyCache          *int                     `toml:",omitempty"`
+		RPCCacheSize            *int                     `toml:",omitempty"`
+		VirtualPending          *bool                    `toml:",omitempty"`
+		DocRoot                 *string                  `toml:"-"`
+		PowFake                 *bool                    `toml:"-"`
+		PowTest                 *bool                    `toml:"-"`
+		PowShared               *bool                    `toml:"-"`
+		Dpos                    *bool                    `toml:"-"`
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -108,6 +184,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.LightPeers != nil {
 		c.LightPeers = *dec.LightPeers
 	}
+	if dec.Checkpoint != nil {
+		c.Checkpoint = dec.Checkpoint
+	}
 	if dec.SkipBcVersionCheck != nil {
 		c.SkipBcVersionCheck = *dec.SkipBcVersionCheck
 	}
@@ -120,6 +199,15 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.Coinbase != nil {
 		c.Coinbase = *dec.Coinbase
 	}
+	if dec.SigningKey != nil {
+		c.SigningKey = *dec.SigningKey
+	}
+	if dec.RemoteSigner != nil {
+		c.RemoteSigner = dec.RemoteSigner
+	}
+	if dec.Bridge != nil {
+		c.Bridge = dec.Bridge
+	}
 	if dec.MinerThreads != nil {
 		c.MinerThreads = *dec.MinerThreads
 	}
@@ -138,6 +226,66 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.EnablePreimageRecording != nil {
 		c.EnablePreimageRecording = *dec.EnablePreimageRecording
 	}
+	if dec.InternalTxIndex != nil {
+		c.InternalTxIndex = *dec.InternalTxIndex
+	}
+	if dec.TokenIndex != nil {
+		c.TokenIndex = *dec.TokenIndex
+	}
+	if dec.NameIndex != nil {
+		c.NameIndex = *dec.NameIndex
+	}
+	if dec.RichListSize != nil {
+		c.RichListSize = *dec.RichListSize
+	}
+	if dec.EthCompatible != nil {
+		c.EthCompatible = *dec.EthCompatible
+	}
+	if dec.AllowUnprotectedTxs != nil {
+		c.AllowUnprotectedTxs = *dec.AllowUnprotectedTxs
+	}
+	if dec.RPCTimeout != nil {
+		c.RPCTimeout = *dec.RPCTimeout
+	}
+	if dec.RPCTimeouts != nil {
+		c.RPCTimeouts = dec.RPCTimeouts
+	}
+	if dec.CheckpointDir != nil {
+		c.CheckpointDir = *dec.CheckpointDir
+	}
+	if dec.MaxClockDrift != nil {
+		c.MaxClockDrift = *dec.MaxClockDrift
+	}
+	if dec.SystemTxDedup != nil {
+		c.SystemTxDedup = *dec.SystemTxDedup
+	}
+	if dec.TxIpMode != nil {
+		c.TxIpMode = *dec.TxIpMode
+	}
+	if dec.TxIpSalt != nil {
+		c.TxIpSalt = *dec.TxIpSalt
+	}
+	if dec.RPCIpRedaction != nil {
+		c.RPCIpRedaction = *dec.RPCIpRedaction
+	}
+	if dec.DatabaseRepair != nil {
+		c.DatabaseRepair = *dec.DatabaseRepair
+	}
+	if dec.ReadOnly != nil {
+		c.ReadOnly = *dec.ReadOnly
+	}
+	if dec.Archive != nil {
+		c.Archive = *dec.Archive
+	}
+	if dec.TrieDirtyCache != nil {
+		c.TrieDirtyCache = *dec.TrieDirtyCache
+	}
+	if dec.RPCCacheSize != nil {
+		c.RPCCacheSize = *dec.RPCCacheSize
+	}
+	if dec.VirtualPending != nil {
+		c.VirtualPending = *dec.VirtualPending
+	}
 	if dec.DocRoot != nil {
 		c.DocRoot = *dec.DocRoot
 	}
@@ -75,6 +75,7 @@ type ProtocolManager struct {
 	chaindb     ethdb.Database
 	chainconfig *params.ChainConfig
 	maxPeers    int
+	readOnly    bool
 
 	downloader *downloader.Downloader //负责所有向相邻个体主动发起的同步流程
 	fetcher    *fetcher.Fetcher       //累积所有其他个体发送来的有关新数据的宣布消息，并在自身对照后，安排相应的获取请求
@@ -82,10 +83,13 @@ type ProtocolManager struct {
 
 	SubProtocols []p2p.Protocol
 
-	eventMux      *event.TypeMux
-	txCh          chan core.TxPreEvent
-	txSub         event.Subscription
-	minedBlockSub *event.TypeMuxSubscription
+	eventMux       *event.TypeMux
+	txCh           chan core.TxPreEvent
+	txSub          event.Subscription
+	rebroadcastCh  chan core.TxRebroadcastEvent
+	rebroadcastSub event.Subscription
+	minedBlockSub  *event.TypeMuxSubscription
+	heartbeats     *heartbeatSet //每个验证者最近一次的心跳
 
 	// channels for fetcher, syncer, txsyncLoop
 	newPeerCh   chan *peer
@@ -116,6 +120,7 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 		noMorePeers: make(chan struct{}),
 		txsyncCh:    make(chan *txsync),
 		quitSync:    make(chan struct{}),
+		heartbeats:  newHeartbeatSet(),
 	}
 
 	//判断是否允许快速同步
@@ -221,6 +226,8 @@ func (pm *ProtocolManager) Start(maxPeers int) {
 	//广播新出现的交易对象
 	pm.txCh = make(chan core.TxPreEvent, txChanSize)
 	pm.txSub = pm.txpool.SubscribeTxPreEvent(pm.txCh)
+	pm.rebroadcastCh = make(chan core.TxRebroadcastEvent, 16)
+	pm.rebroadcastSub = pm.txpool.SubscribeTxRebroadcastEvent(pm.rebroadcastCh)
 
 	log.Info("ProtocolManager txBroadcastLoop")
 	go pm.txBroadcastLoop()
@@ -243,8 +250,9 @@ func (pm *ProtocolManager) Start(maxPeers int) {
 func (pm *ProtocolManager) Stop() {
 	log.Info("Stopping Ethereum protocol")
 
-	pm.txSub.Unsubscribe()         // quits txBroadcastLoop
-	pm.minedBlockSub.Unsubscribe() // quits blockBroadcastLoop
+	pm.txSub.Unsubscribe()          // quits txBroadcastLoop
+	pm.rebroadcastSub.Unsubscribe() // stops rebroadcast events
+	pm.minedBlockSub.Unsubscribe()  // quits blockBroadcastLoop
 
 	// Quit the sync loop.
 	// After this send has completed, no new peers will be accepted.
@@ -563,7 +571,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 				return errResp(ErrDecode, "msg %v: %v", msg, err)
 			}
 			// Retrieve the requested state entry, stopping if enough was found
-			if entry, err := pm.chaindb.Get(hash.Bytes()); err == nil {
+			if entry, err := pm.blockchain.TrieNode(hash); err == nil {
 				data = append(data, entry)
 				bytes += len(entry)
 			}
@@ -682,7 +690,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 
 	case msg.Code == TxMsg: //交易信息返回, 在我们没用同步完成之前不会接收交易信息
 		// Transactions arrived, make sure we have a valid and fresh chain to handle them
-		if atomic.LoadUint32(&pm.acceptTxs) == 0 {
+		if atomic.LoadUint32(&pm.acceptTxs) == 0 || pm.readOnly {
 			break
 		}
 		// Transactions can be processed, parse all of them and deliver to the pool
@@ -699,6 +707,23 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 		pm.txpool.AddRemotes(txs)
 
+	case p.version >= eth63 && msg.Code == HeartbeatMsg: //验证者心跳, 校验通过且是新的心跳时继续转发
+		var heartbeat heartbeatData
+		if err := msg.Decode(&heartbeat); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		p.MarkHeartbeat(heartbeat.ID())
+
+		//本节点的验证者集合可能落后于对端，校验失败时只丢弃心跳
+		fresh, err := pm.heartbeats.add(pm.chaindb, pm.blockchain.CurrentBlock().Header(), &heartbeat, p.id, time.Now())
+		if err != nil {
+			log.Trace("Dropped validator heartbeat", "peer", p.id, "validator", heartbeat.Validator, "err", err)
+			break
+		}
+		if fresh {
+			pm.BroadcastHeartbeat(&heartbeat)
+		}
+
 	default:
 		return errResp(ErrInvalidMsgCode, "%v", msg.Code)
 	}
@@ -751,6 +776,16 @@ func (pm *ProtocolManager) BroadcastTx(hash common.Hash, tx *types.Transaction)
 	log.Trace("Broadcast transaction", "hash", hash, "recipients", len(peers))
 }
 
+//向所有节点重新广播交易(包括已经收到过交易的节点，它们可能已经丢弃了该交易)
+func (pm *ProtocolManager) RebroadcastTxs(txs types.Transactions) {
+
+	peers := pm.peers.Peers()
+	for _, peer := range peers {
+		peer.SendTransactions(txs)
+	}
+	log.Debug("Rebroadcast transactions", "count", len(txs), "recipients", len(peers))
+}
+
 //广播新挖掘出的区块(等待本节点的新挖掘出区块事件)
 func (self *ProtocolManager) minedBroadcastLoop() {
 
@@ -771,6 +806,9 @@ func (self *ProtocolManager) txBroadcastLoop() {
 		case event := <-self.txCh:
 			self.BroadcastTx(event.Tx.Hash(), event.Tx)
 
+		case event := <-self.rebroadcastCh:
+			self.RebroadcastTxs(event.Txs)
+
 		// Err() channel will be closed when unsubscribing.
 		case <-self.txSub.Err():
 			return
@@ -785,6 +823,7 @@ type EthNodeInfo struct {
 	Difficulty *big.Int    `json:"difficulty"` // Total difficulty of the host's blockchain
 	Genesis    common.Hash `json:"genesis"`    // SHA3 hash of the host's genesis block
 	Head       common.Hash `json:"head"`       // SHA3 hash of the host's best owned block
+	Number     uint64      `json:"number"`     // Number of the host's best owned block
 }
 
 // NodeInfo retrieves some protocol metadata about the running host node.
@@ -795,5 +834,6 @@ func (self *ProtocolManager) NodeInfo() *EthNodeInfo {
 		Difficulty: self.blockchain.GetTd(currentBlock.Hash(), currentBlock.NumberU64()),
 		Genesis:    self.blockchain.Genesis().Hash(),
 		Head:       currentBlock.Hash(),
+		Number:     currentBlock.NumberU64(),
 	}
 }
@@ -0,0 +1,251 @@
+package eth
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/consensus/dpos"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+const (
+	heartbeatInterval = 3 * protocol.BlockInterval //验证者发送心跳的间隔(秒)
+	heartbeatTimeout  = 3 * heartbeatInterval      //超过该时间(秒)没有收到心跳则认为验证者离线
+	heartbeatMaxDrift = protocol.BlockInterval     //心跳时间允许超前本地时间的秒数
+)
+
+//心跳签名哈希的前缀，防止与交易以及区块签名哈希混淆
+var heartbeatPrefix = []byte("tina-heartbeat")
+
+var (
+	errHeartbeatStale     = errors.New("heartbeat expired or from the future")
+	errHeartbeatSignature = errors.New("heartbeat not signed by an epoch validator")
+)
+
+//验证者心跳的网络数据包，由验证者的出块签名账号签名
+type heartbeatData struct {
+	Validator common.Address //验证者
+	Number    uint64         //验证者当前的区块高度
+	Hash      common.Hash    //验证者当前的区块哈希
+	Time      uint64         //发送时间
+	Signature []byte         //出块签名账号对签名哈希的签名[R || S || V]
+}
+
+//验证者需要签名的心跳哈希
+func (h *heartbeatData) SigHash() common.Hash {
+
+	data, _ := rlp.EncodeToBytes([]interface{}{
+		heartbeatPrefix,
+		h.Validator,
+		h.Number,
+		h.Hash,
+		h.Time,
+	})
+	return crypto.Keccak256Hash(data)
+}
+
+//心跳的唯一标识(用于转发去重)
+func (h *heartbeatData) ID() common.Hash {
+	return crypto.Keccak256Hash(h.SigHash().Bytes(), h.Signature)
+}
+
+//从签名中恢复出签名账号
+func (h *heartbeatData) signer() (common.Address, error) {
+
+	if len(h.Signature) != 65 || h.Signature[64] > 1 {
+		return common.Address{}, errHeartbeatSignature
+	}
+	hash := h.SigHash()
+	pub, err := crypto.Ecrecover(hash[:], h.Signature)
+	if err != nil || len(pub) == 0 || pub[0] != 4 {
+		return common.Address{}, errHeartbeatSignature
+	}
+	var signer common.Address
+	copy(signer[:], crypto.Keccak256(pub[1:])[12:])
+	return signer, nil
+}
+
+//验证者的在线情况
+type ValidatorLiveness struct {
+	Validator common.Address `json:"validator"` //验证者
+	Online    bool           `json:"online"`    //最近是否收到过心跳
+	LastSeen  int64          `json:"lastSeen"`  //最后一次收到心跳的时间(0为从未收到)
+	Number    hexutil.Uint64 `json:"number"`    //心跳中验证者的区块高度
+	Hash      common.Hash    `json:"hash"`      //心跳中验证者的区块哈希
+	Peer      string         `json:"peer"`      //转发该心跳的节点(本节点产生的心跳为空)
+}
+
+//收到的心跳
+type receivedHeartbeat struct {
+	data     *heartbeatData
+	received time.Time
+	peer     string
+}
+
+//每个验证者最近一次的心跳
+type heartbeatSet struct {
+	beats map[common.Address]*receivedHeartbeat
+	lock  sync.RWMutex
+}
+
+func newHeartbeatSet() *heartbeatSet {
+	return &heartbeatSet{beats: make(map[common.Address]*receivedHeartbeat)}
+}
+
+//校验心跳并记录, 返回是否是该验证者更新的心跳(需要继续转发)
+func (hs *heartbeatSet) add(db ethdb.Database, head *types.Header, heartbeat *heartbeatData, peer string, now time.Time) (bool, error) {
+
+	if age := now.Unix() - int64(heartbeat.Time); age > heartbeatTimeout || age < -heartbeatMaxDrift {
+		return false, errHeartbeatStale
+	}
+	hs.lock.RLock()
+	known, ok := hs.beats[heartbeat.Validator]
+	hs.lock.RUnlock()
+	if ok && known.data.Time >= heartbeat.Time {
+		return false, nil
+	}
+
+	//签名者必须是当前周期的验证者或者其出块签名账号
+	signer, err := heartbeat.signer()
+	if err != nil {
+		return false, err
+	}
+	dposContext, err := types.NewDposContextFromProto(db, head.DposProto)
+	if err != nil {
+		return false, err
+	}
+	if !dposContext.IsValidator(heartbeat.Validator) {
+		return false, errHeartbeatSignature
+	}
+	if signer != heartbeat.Validator {
+		bokerContext, err := types.NewBokerContextFromProto(db, head.BokerProto)
+		if err != nil {
+			return false, err
+		}
+		if bokerContext.SigningKey(heartbeat.Validator) != signer {
+			return false, errHeartbeatSignature
+		}
+	}
+
+	hs.lock.Lock()
+	defer hs.lock.Unlock()
+	if known, ok := hs.beats[heartbeat.Validator]; ok && known.data.Time >= heartbeat.Time {
+		return false, nil
+	}
+	hs.beats[heartbeat.Validator] = &receivedHeartbeat{data: heartbeat, received: now, peer: peer}
+	return true, nil
+}
+
+//得到验证者的在线情况
+func (hs *heartbeatSet) liveness(validators []common.Address, now time.Time) []*ValidatorLiveness {
+
+	hs.lock.RLock()
+	defer hs.lock.RUnlock()
+
+	result := make([]*ValidatorLiveness, 0, len(validators))
+	for _, validator := range validators {
+		liveness := &ValidatorLiveness{Validator: validator}
+		if beat, ok := hs.beats[validator]; ok {
+			liveness.Online = now.Unix()-int64(beat.data.Time) <= heartbeatTimeout
+			liveness.LastSeen = beat.received.Unix()
+			liveness.Number = hexutil.Uint64(beat.data.Number)
+			liveness.Hash = beat.data.Hash
+			liveness.Peer = beat.peer
+		}
+		result = append(result, liveness)
+	}
+	return result
+}
+
+//其它验证者未过期心跳的接收时间与发送时间之差(包含传播延迟)
+func (hs *heartbeatSet) offsets(now time.Time) []time.Duration {
+
+	hs.lock.RLock()
+	defer hs.lock.RUnlock()
+
+	var offsets []time.Duration
+	for _, beat := range hs.beats {
+		if beat.peer == "" || now.Sub(beat.received) > time.Duration(heartbeatTimeout)*time.Second {
+			continue
+		}
+		offsets = append(offsets, beat.received.Sub(time.Unix(int64(beat.data.Time), 0)))
+	}
+	return offsets
+}
+
+//向不知道该心跳的节点转发
+func (pm *ProtocolManager) BroadcastHeartbeat(heartbeat *heartbeatData) {
+
+	peers := pm.peers.PeersWithoutHeartbeat(heartbeat.ID())
+	for _, peer := range peers {
+		peer.SendHeartbeat(heartbeat)
+	}
+	log.Trace("Broadcast heartbeat", "validator", heartbeat.Validator, "number", heartbeat.Number, "recipients", len(peers))
+}
+
+//本节点作为验证者出块时定期发送心跳
+func (s *Ethereum) heartbeatLoop() {
+
+	ticker := time.NewTicker(time.Duration(heartbeatInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if !s.IsMining() {
+				continue
+			}
+			if err := s.sendHeartbeat(); err != nil {
+				log.Debug("Failed to send validator heartbeat", "err", err)
+			}
+		case <-s.shutdownChan:
+			return
+		}
+	}
+}
+
+//签名并广播本节点的心跳
+func (s *Ethereum) sendHeartbeat() error {
+
+	engine, ok := s.engine.(*dpos.Dpos)
+	if !ok {
+		return nil
+	}
+	validator, err := s.Coinbase()
+	if err != nil {
+		return err
+	}
+	head := s.blockchain.CurrentBlock().Header()
+	heartbeat := &heartbeatData{
+		Validator: validator,
+		Number:    head.Number.Uint64(),
+		Hash:      head.Hash(),
+		Time:      uint64(time.Now().Unix()),
+	}
+	if heartbeat.Signature, err = engine.SignHash(heartbeat.SigHash()); err != nil {
+		return err
+	}
+	if _, err := s.protocolManager.heartbeats.add(s.chainDb, head, heartbeat, "", time.Now()); err != nil {
+		return err
+	}
+	s.protocolManager.BroadcastHeartbeat(heartbeat)
+	return nil
+}
+
+//得到当前周期全部验证者的在线情况
+func (s *Ethereum) ValidatorLiveness() ([]*ValidatorLiveness, error) {
+
+	validators, err := s.blockchain.CurrentBlock().DposCtx().GetEpochTrie()
+	if err != nil {
+		return nil, err
+	}
+	return s.protocolManager.heartbeats.liveness(validators, time.Now()), nil
+}
@@ -0,0 +1,91 @@
+package eth
+
+import (
+	"crypto/ecdsa"
+	"testing"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+// Tests that heartbeats are only accepted when fresh and signed by an epoch
+// validator or its signing key, and that they are reflected in the liveness.
+func TestHeartbeatLiveness(t *testing.T) {
+	validatorKey, _ := crypto.GenerateKey()
+	signingKey, _ := crypto.GenerateKey()
+	strangerKey, _ := crypto.GenerateKey()
+	validator := crypto.PubkeyToAddress(validatorKey.PublicKey)
+	offline := common.Address{0xff}
+
+	db, _ := ethdb.NewMemDatabase()
+	genesis := (&core.Genesis{Config: params.TestChainConfig}).MustCommit(db)
+	head := genesis.Header()
+
+	dposContext, _ := types.NewDposContextFromProto(db, head.DposProto)
+	if err := dposContext.SetEpochTrie([]common.Address{validator, offline}); err != nil {
+		t.Fatalf("failed to set validators: %v", err)
+	}
+	dposProto, err := dposContext.CommitTo(db)
+	if err != nil {
+		t.Fatalf("failed to commit dpos context: %v", err)
+	}
+	bokerContext, _ := types.NewBokerContextFromProto(db, head.BokerProto)
+	if err := bokerContext.SetSigningKey(validator, crypto.PubkeyToAddress(signingKey.PublicKey)); err != nil {
+		t.Fatalf("failed to set signing key: %v", err)
+	}
+	bokerProto, err := bokerContext.CommitTo(db)
+	if err != nil {
+		t.Fatalf("failed to commit boker context: %v", err)
+	}
+	head.DposProto, head.BokerProto = dposProto, bokerProto
+
+	now := time.Now()
+	sign := func(key *ecdsa.PrivateKey, age int64, number uint64) *heartbeatData {
+		heartbeat := &heartbeatData{Validator: validator, Number: number, Hash: common.Hash{byte(number)}, Time: uint64(now.Unix() - age)}
+		hash := heartbeat.SigHash()
+		heartbeat.Signature, _ = crypto.Sign(hash[:], key)
+		return heartbeat
+	}
+	tests := []struct {
+		heartbeat *heartbeatData
+		fresh     bool
+		fail      bool
+	}{
+		{heartbeat: sign(strangerKey, 0, 1), fail: true},
+		{heartbeat: sign(signingKey, heartbeatTimeout+1, 1), fail: true},
+		{heartbeat: sign(signingKey, -heartbeatMaxDrift-1, 1), fail: true},
+		{heartbeat: sign(signingKey, 1, 1), fresh: true},
+		{heartbeat: sign(signingKey, 1, 1), fresh: false},
+		{heartbeat: sign(validatorKey, 2, 1), fresh: false},
+		{heartbeat: sign(validatorKey, 0, 2), fresh: true},
+	}
+	set := newHeartbeatSet()
+	for i, tt := range tests {
+		fresh, err := set.add(db, head, tt.heartbeat, "peer", now)
+		if tt.fail != (err != nil) {
+			t.Errorf("test %d: error mismatch: have %v, want failure %v", i, err, tt.fail)
+		}
+		if fresh != tt.fresh {
+			t.Errorf("test %d: freshness mismatch: have %v, want %v", i, fresh, tt.fresh)
+		}
+	}
+
+	liveness := set.liveness([]common.Address{validator, offline}, now)
+	if len(liveness) != 2 {
+		t.Fatalf("liveness count mismatch: have %d, want 2", len(liveness))
+	}
+	if !liveness[0].Online || liveness[0].Number != 2 || liveness[0].Peer != "peer" {
+		t.Errorf("validator liveness mismatch: have %+v", liveness[0])
+	}
+	if liveness[1].Online || liveness[1].LastSeen != 0 {
+		t.Errorf("offline validator reported alive: have %+v", liveness[1])
+	}
+	if later := set.liveness([]common.Address{validator}, now.Add(time.Duration(heartbeatTimeout+1)*time.Second)); later[0].Online {
+		t.Errorf("validator still online after heartbeat timeout")
+	}
+}
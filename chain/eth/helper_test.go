@@ -27,9 +27,14 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/Tinachain/Tina/chain/boker/api"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/consensus"
+	"github.com/Tinachain/Tina/chain/consensus/dpos"
 	"github.com/Tinachain/Tina/chain/consensus/ethash"
 	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/state"
 	"github.com/Tinachain/Tina/chain/core/types"
 	"github.com/Tinachain/Tina/chain/core/vm"
 	"github.com/Tinachain/Tina/chain/crypto"
@@ -46,13 +51,25 @@ var (
 	testBank       = crypto.PubkeyToAddress(testBankKey.PublicKey)
 )
 
+// chainMakerFaker is a fake ethash engine paying the same block rewards as
+// GenerateChain, so that the generated blocks can be imported.
+type chainMakerFaker struct {
+	*ethash.Ethash
+}
+
+func (e chainMakerFaker) Finalize(chain consensus.ChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, receipts []*types.Receipt, dposContext *types.DposContext, bokerContext *types.BokerContext, boker bokerapi.Api) (*types.Block, error) {
+	dpos.AccumulateRewards(chain.Config(), state, header, uncles, txs, bokerContext, boker, 0)
+	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
+	return types.NewBlock(header, txs, uncles, receipts), nil
+}
+
 // newTestProtocolManager creates a new protocol manager for testing purposes,
 // with the given number of blocks already known, and potential notification
 // channels for different events.
 func newTestProtocolManager(mode downloader.SyncMode, blocks int, generator func(int, *core.BlockGen), newtx chan<- []*types.Transaction) (*ProtocolManager, error) {
 	var (
 		evmux  = new(event.TypeMux)
-		engine = ethash.NewFaker()
+		engine = chainMakerFaker{ethash.NewFaker()}
 		db, _  = ethdb.NewMemDatabase()
 		gspec  = &core.Genesis{
 			Config: params.TestChainConfig,
@@ -61,7 +78,12 @@ func newTestProtocolManager(mode downloader.SyncMode, blocks int, generator func
 		genesis       = gspec.MustCommit(db)
 		blockchain, _ = core.NewBlockChain(db, gspec.Config, engine, vm.Config{})
 	)
-	chain, _ := core.GenerateChain(gspec.Config, genesis, db, blocks, generator)
+	chain, _ := core.GenerateChain(gspec.Config, genesis, db, blocks, nil, func(i int, gen *core.BlockGen) {
+		gen.OffsetTime(0)
+		if generator != nil {
+			generator(i, gen)
+		}
+	})
 	if _, err := blockchain.InsertChain(chain); err != nil {
 		panic(err)
 	}
@@ -88,9 +110,10 @@ func newTestProtocolManagerMust(t *testing.T, mode downloader.SyncMode, blocks i
 
 // testTxPool is a fake, helper transaction pool for testing purposes
 type testTxPool struct {
-	txFeed event.Feed
-	pool   []*types.Transaction        // Collection of all transactions
-	added  chan<- []*types.Transaction // Notification channel for new transactions
+	txFeed          event.Feed
+	rebroadcastFeed event.Feed
+	pool            []*types.Transaction        // Collection of all transactions
+	added           chan<- []*types.Transaction // Notification channel for new transactions
 
 	lock sync.RWMutex // Protects the transaction pool
 }
@@ -128,9 +151,13 @@ func (p *testTxPool) SubscribeTxPreEvent(ch chan<- core.TxPreEvent) event.Subscr
 	return p.txFeed.Subscribe(ch)
 }
 
+func (p *testTxPool) SubscribeTxRebroadcastEvent(ch chan<- core.TxRebroadcastEvent) event.Subscription {
+	return p.rebroadcastFeed.Subscribe(ch)
+}
+
 // newTestTransaction create a new dummy transaction.
 func newTestTransaction(from *ecdsa.PrivateKey, nonce uint64, datasize int) *types.Transaction {
-	tx := types.NewTransaction(types.Binary, nonce, common.Address{}, big.NewInt(0), big.NewInt(100000), big.NewInt(0), make([]byte, datasize))
+	tx := types.NewTransaction(protocol.Normal, protocol.NormalCall, nonce, common.Address{}, big.NewInt(0), big.NewInt(100000), big.NewInt(0), make([]byte, datasize))
 	tx, _ = types.SignTx(tx, types.HomesteadSigner{}, from)
 	return tx
 }
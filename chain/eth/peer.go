@@ -39,6 +39,7 @@ var (
 const (
 	maxKnownTxs      = 32768 // Maximum transactions hashes to keep in the known list (prevent DOS)
 	maxKnownBlocks   = 1024  // Maximum block hashes to keep in the known list (prevent DOS)
+	maxKnownBeats    = 1024  //对端已知的心跳哈希的最大数量
 	handshakeTimeout = 5 * time.Second
 )
 
@@ -65,6 +66,7 @@ type peer struct {
 
 	knownTxs    *set.Set // Set of transaction hashes known to be known by this peer
 	knownBlocks *set.Set // Set of block hashes known to be known by this peer
+	knownBeats  *set.Set //对端已知的验证者心跳哈希
 }
 
 func newPeer(version int, p *p2p.Peer, rw p2p.MsgReadWriter) *peer {
@@ -77,6 +79,7 @@ func newPeer(version int, p *p2p.Peer, rw p2p.MsgReadWriter) *peer {
 		id:          fmt.Sprintf("%x", id[:8]),
 		knownTxs:    set.New(),
 		knownBlocks: set.New(),
+		knownBeats:  set.New(),
 	}
 }
 
@@ -130,6 +133,20 @@ func (p *peer) MarkTransaction(hash common.Hash) {
 	p.knownTxs.Add(hash)
 }
 
+//标记对端已知的心跳，不再向其转发
+func (p *peer) MarkHeartbeat(hash common.Hash) {
+	for p.knownBeats.Size() >= maxKnownBeats {
+		p.knownBeats.Pop()
+	}
+	p.knownBeats.Add(hash)
+}
+
+//向对端发送验证者心跳
+func (p *peer) SendHeartbeat(heartbeat *heartbeatData) error {
+	p.MarkHeartbeat(heartbeat.ID())
+	return p2p.Send(p.rw, HeartbeatMsg, heartbeat)
+}
+
 // SendTransactions sends transactions to the peer and includes the hashes
 // in its transaction hash set for future reference.
 func (p *peer) SendTransactions(txs types.Transactions) error {
@@ -372,6 +389,18 @@ func (ps *peerSet) PeersWithoutBlock(hash common.Hash) []*peer {
 	return list
 }
 
+// Peers retrieves a list of all the registered peers.
+func (ps *peerSet) Peers() []*peer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	list := make([]*peer, 0, len(ps.peers))
+	for _, p := range ps.peers {
+		list = append(list, p)
+	}
+	return list
+}
+
 // PeersWithoutTx retrieves a list of peers that do not have a given transaction
 // in their set of known hashes.
 func (ps *peerSet) PeersWithoutTx(hash common.Hash) []*peer {
@@ -387,6 +416,20 @@ func (ps *peerSet) PeersWithoutTx(hash common.Hash) []*peer {
 	return list
 }
 
+//得到不知道该心跳且支持心跳消息的节点
+func (ps *peerSet) PeersWithoutHeartbeat(hash common.Hash) []*peer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	list := make([]*peer, 0, len(ps.peers))
+	for _, p := range ps.peers {
+		if p.version >= eth63 && !p.knownBeats.Has(hash) {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
 // BestPeer retrieves the known peer with the currently highest total difficulty.
 func (ps *peerSet) BestPeer() *peer {
 	ps.lock.RLock()
@@ -41,7 +41,7 @@ var ProtocolName = "eth"
 var ProtocolVersions = []uint{eth63, eth62}
 
 // Number of implemented message corresponding to different protocol versions.
-var ProtocolLengths = []uint64{17, 8}
+var ProtocolLengths = []uint64{18, 8}
 
 const ProtocolMaxMsgSize = 10 * 1024 * 1024 //协议消息大小的最大上限
 
@@ -62,6 +62,9 @@ const (
 	NodeDataMsg    = 0x0e
 	GetReceiptsMsg = 0x0f
 	ReceiptsMsg    = 0x10
+
+	//Tina扩展消息(eth/63)
+	HeartbeatMsg = 0x11 //验证者心跳
 )
 
 type errCode int
@@ -106,6 +109,10 @@ type txPool interface {
 	// SubscribeTxPreEvent should return an event subscription of
 	// TxPreEvent and send events to the given channel.
 	SubscribeTxPreEvent(chan<- core.TxPreEvent) event.Subscription
+
+	// SubscribeTxRebroadcastEvent should return an event subscription of
+	// TxRebroadcastEvent and send events to the given channel.
+	SubscribeTxRebroadcastEvent(chan<- core.TxRebroadcastEvent) event.Subscription
 }
 
 // statusData is the network packet for the status message.
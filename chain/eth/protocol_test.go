@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core"
 	"github.com/Tinachain/Tina/chain/core/types"
 	"github.com/Tinachain/Tina/chain/crypto"
 	"github.com/Tinachain/Tina/chain/eth/downloader"
@@ -174,6 +175,43 @@ func testSendTransactions(t *testing.T, protocol int) {
 	wg.Wait()
 }
 
+// Tests that rebroadcast transactions are sent to every peer, even to the ones
+// that are already known to have them.
+func TestRebroadcastTransactions62(t *testing.T) { testRebroadcastTransactions(t, 62) }
+func TestRebroadcastTransactions63(t *testing.T) { testRebroadcastTransactions(t, 63) }
+
+func testRebroadcastTransactions(t *testing.T, protocol int) {
+	pm := newTestProtocolManagerMust(t, downloader.FullSync, 0, nil, nil)
+	p, _ := newTestPeer("peer", protocol, pm, true)
+	defer pm.Stop()
+	defer p.close()
+
+	for i := 0; pm.peers.Len() == 0; i++ {
+		if i == 100 {
+			t.Fatalf("peer not registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tx := newTestTransaction(testAccount, 0, 0)
+	p.MarkTransaction(tx.Hash())
+	pm.txpool.(*testTxPool).rebroadcastFeed.Send(core.TxRebroadcastEvent{Txs: types.Transactions{tx}})
+
+	msg, err := p.app.ReadMsg()
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	if msg.Code != TxMsg {
+		t.Fatalf("got code %d, want TxMsg", msg.Code)
+	}
+	var txs []*types.Transaction
+	if err := msg.Decode(&txs); err != nil {
+		t.Fatalf("failed to decode transactions: %v", err)
+	}
+	if len(txs) != 1 || txs[0].Hash() != tx.Hash() {
+		t.Errorf("rebroadcast mismatch: have %v, want %x", txs, tx.Hash())
+	}
+}
+
 // Tests that the custom union field encoder and decoder works correctly.
 func TestGetBlockHeadersDataEncodeDecode(t *testing.T) {
 	// Create a "random" hash for testing
@@ -217,3 +255,19 @@ func TestGetBlockHeadersDataEncodeDecode(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the protocol node info reports the number of the head block next
+// to its hash.
+func TestNodeInfo(t *testing.T) {
+	pm := newTestProtocolManagerMust(t, downloader.FullSync, 3, nil, nil)
+	defer pm.Stop()
+
+	info := pm.NodeInfo()
+	head := pm.blockchain.CurrentBlock()
+	if info.Number != 3 || info.Head != head.Hash() {
+		t.Errorf("head mismatch: have #%d [%x], want #3 [%x]", info.Number, info.Head, head.Hash())
+	}
+	if info.Difficulty.Cmp(pm.blockchain.GetTd(head.Hash(), head.NumberU64())) != 0 {
+		t.Errorf("difficulty mismatch: have %v", info.Difficulty)
+	}
+}
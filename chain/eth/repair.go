@@ -0,0 +1,50 @@
+package eth
+
+import (
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/log"
+)
+
+//启动时检查链头区块的数据，损坏时将链截断到最后一个完整可用的区块，并清除交易索引补齐的完成标记，
+//之后的交易索引补齐会重新检查所有主链区块，节点从截断后的区块继续同步
+func repairDatabase(db ethdb.Database) error {
+
+	head := core.GetHeadBlockHash(db)
+	from, to, err := core.RepairHead(db)
+	if err != nil {
+		return err
+	}
+	if core.GetHeadBlockHash(db) == head {
+		return nil
+	}
+	log.Warn("Chain database truncated to the last verifiable block, resyncing", "from", from, "to", to)
+	return db.Delete(txLookupBackfill)
+}
+
+//运行中检查链头区块的数据，损坏时回滚到最后一个完整可用的区块(被丢弃的区块导出到备份文件)并重新补齐交易索引
+func (s *Ethereum) repairChain() (*core.RewindReport, error) {
+
+	//最近区块的状态可能还在内存中，通过节点缓存读取
+	current := s.blockchain.CurrentBlock().NumberU64()
+	block, err := core.FindRepairPoint(s.chainDb, state.NewDatabaseWithNodes(s.blockchain.TrieDB()), current)
+	if err != nil {
+		return nil, err
+	}
+	if block.NumberU64() == current {
+		return &core.RewindReport{From: current, To: current}, nil
+	}
+	report, err := s.rewindChain(block.NumberU64())
+	if err != nil {
+		return nil, err
+	}
+	if s.stopTxBackfill != nil {
+		s.stopTxBackfill()
+	}
+	if err := s.chainDb.Delete(txLookupBackfill); err != nil {
+		return report, err
+	}
+	s.stopTxBackfill = upgradeTxLookups(s.chainDb)
+	return report, nil
+}
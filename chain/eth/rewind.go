@@ -0,0 +1,125 @@
+package eth
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/consensus/dpos"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+//将区块链回滚到指定高度, 不允许回滚到已确认(不可逆)的区块以下, 无法得到已确认的区块(包括非dpos共识)时不回滚;
+//回滚前将被丢弃的区块导出到备份文件, 回滚后将其中的交易重新放入交易池(只读副本不接收交易，全部丢弃)
+func (s *Ethereum) rewindChain(number uint64) (*core.RewindReport, error) {
+
+	current := s.blockchain.CurrentBlock()
+	report := &core.RewindReport{
+		From:    current.NumberU64(),
+		To:      number,
+		Dropped: make(map[common.Hash]string),
+	}
+	engine, ok := s.engine.(*dpos.Dpos)
+	if !ok {
+		return nil, protocol.ErrUnknownFinalized
+	}
+	header, err := engine.ConfirmedBlockHeader(s.blockchain)
+	if err != nil {
+		log.Warn("Refused to rewind without the confirmed block", "err", err)
+		return nil, protocol.ErrUnknownFinalized
+	}
+	report.Finalized = header.Number.Uint64()
+	if number < report.Finalized {
+		return nil, protocol.ErrRewindFinalized
+	}
+
+	//收集将被丢弃的主链区块并导出到备份文件，导出失败时不进行回滚
+	var abandoned types.Blocks
+	for n := number + 1; n <= current.NumberU64(); n++ {
+		if block := s.blockchain.GetBlockByNumber(n); block != nil {
+			abandoned = append(abandoned, block)
+		}
+	}
+	if len(abandoned) > 0 {
+		file, err := s.exportAbandoned(report.From, number, abandoned)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export abandoned blocks: %v", err)
+		}
+		report.Abandoned, report.RecoveryFile = len(abandoned), file
+	}
+
+	s.protocolManager.downloader.Cancel()
+	if err := s.blockchain.SetHead(number); err != nil {
+		return nil, err
+	}
+	head := s.blockchain.CurrentBlock()
+	report.To = head.NumberU64()
+
+	//被丢弃区块中的交易已经不在主链上，删除交易索引后重新放入交易池
+	var txs types.Transactions
+	for _, block := range abandoned {
+		for _, tx := range block.Transactions() {
+			core.DeleteTxLookupEntry(s.chainDb, tx.Hash())
+			txs = append(txs, tx)
+		}
+	}
+	report.Reinjected = make([]common.Hash, 0, len(txs))
+	if s.config.ReadOnly {
+		//只读副本不接收交易，交易池只切换到新的链头
+		for _, tx := range txs {
+			report.Dropped[tx.Hash()] = errReadOnly.Error()
+		}
+		txs = nil
+	}
+	for i, err := range s.txPool.Reinject(head.Header(), txs) {
+		if err != nil {
+			report.Dropped[txs[i].Hash()] = err.Error()
+		} else {
+			report.Reinjected = append(report.Reinjected, txs[i].Hash())
+		}
+	}
+	log.Warn("Rewound blockchain", "from", report.From, "to", report.To, "abandoned", report.Abandoned,
+		"recovery", report.RecoveryFile, "reinjected", len(report.Reinjected), "dropped", len(report.Dropped))
+	return report, nil
+}
+
+//将被丢弃的区块按admin_exportChain的格式写入备份目录，返回备份文件的路径
+func (s *Ethereum) exportAbandoned(from, to uint64, blocks types.Blocks) (string, error) {
+
+	dir := s.recoveryDir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("sethead-%d-%d-%d.rlp", from, to, time.Now().Unix()))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", err
+	}
+	writer := bufio.NewWriter(file)
+	for _, block := range blocks {
+		if err = rlp.Encode(writer, block); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,146 @@
+package eth
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/consensus/dpos"
+	"github.com/Tinachain/Tina/chain/consensus/ethash"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/core/vm"
+	"github.com/Tinachain/Tina/chain/eth/downloader"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+// newRewindTestNode creates a node with a chain of the given number of blocks,
+// each carrying a transfer of the test bank, on top of the genesis block and
+// confirmed up to the given height.
+func newRewindTestNode(t *testing.T, blocks int, confirmed uint64, readOnly bool) (*Ethereum, func()) {
+	db, _ := ethdb.NewMemDatabase()
+	genesis := (&core.Genesis{Config: params.TestChainConfig, Alloc: core.GenesisAlloc{testBank: {Balance: big.NewInt(1000000)}}}).MustCommit(db)
+
+	parent := genesis
+	for i := 1; i <= blocks; i++ {
+		header := &types.Header{ParentHash: parent.Hash(), Number: big.NewInt(int64(i)), Root: genesis.Root(), Difficulty: big.NewInt(1), GasLimit: genesis.GasLimit(), DposProto: &types.DposContextProto{}, BokerProto: &types.BokerBackendProto{}}
+		tx := types.NewTransaction(protocol.Normal, protocol.NormalCall, uint64(i-1), common.Address{0x01}, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil)
+		tx, _ = types.SignTx(tx, types.HomesteadSigner{}, testBankKey)
+		block := types.NewBlock(header, types.Transactions{tx}, nil, nil)
+		core.WriteTd(db, block.Hash(), block.NumberU64(), big.NewInt(int64(i+1)))
+		core.WriteBlock(db, block)
+		core.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		core.WriteHeadBlockHash(db, block.Hash())
+		core.WriteHeadHeaderHash(db, block.Hash())
+		if block.NumberU64() == confirmed {
+			db.Put(protocol.ConfirmedBlockHead, block.Hash().Bytes())
+		}
+		parent = block
+	}
+	chain, err := core.NewBlockChain(db, params.TestChainConfig, ethash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	poolConfig := core.DefaultTxPoolConfig
+	poolConfig.Journal = ""
+	pool := core.NewTxPool(poolConfig, params.TestChainConfig, chain)
+
+	dir, err := ioutil.TempDir("", "rewind")
+	if err != nil {
+		t.Fatalf("failed to create recovery dir: %v", err)
+	}
+	eth := &Ethereum{
+		config:          &Config{ReadOnly: readOnly},
+		chainDb:         db,
+		blockchain:      chain,
+		txPool:          pool,
+		engine:          dpos.New(&params.DposConfig{}, db),
+		protocolManager: &ProtocolManager{downloader: new(downloader.Downloader)},
+		recoveryDir:     dir,
+	}
+	return eth, func() {
+		pool.Stop()
+		chain.Stop()
+		os.RemoveAll(dir)
+	}
+}
+
+// Tests that the chain can be rewound down to the confirmed block, exporting
+// the abandoned blocks, but never below it.
+func TestRewindChain(t *testing.T) {
+	eth, cleanup := newRewindTestNode(t, 6, 3, false)
+	defer cleanup()
+
+	if _, err := eth.rewindChain(2); err != protocol.ErrRewindFinalized {
+		t.Fatalf("below confirmed: have %v, want %v", err, protocol.ErrRewindFinalized)
+	}
+	if head := eth.blockchain.CurrentBlock().NumberU64(); head != 6 {
+		t.Errorf("head changed by refused rewind: have %d, want 6", head)
+	}
+
+	report, err := eth.rewindChain(4)
+	if err != nil {
+		t.Fatalf("failed to rewind above confirmed: %v", err)
+	}
+	if report.From != 6 || report.To != 4 || report.Finalized != 3 || report.Abandoned != 2 {
+		t.Errorf("report mismatch: have %+v", report)
+	}
+	if len(report.Reinjected) != 2 || len(report.Dropped) != 0 {
+		t.Errorf("reinjection mismatch: reinjected %d, dropped %v", len(report.Reinjected), report.Dropped)
+	}
+	if _, err := os.Stat(report.RecoveryFile); err != nil {
+		t.Errorf("recovery file missing: %v", err)
+	}
+	if head := eth.blockchain.CurrentBlock().NumberU64(); head != 4 {
+		t.Errorf("head mismatch: have %d, want 4", head)
+	}
+
+	if report, err := eth.rewindChain(3); err != nil || report.To != 3 {
+		t.Errorf("rewind to confirmed: have %+v, err %v", report, err)
+	}
+}
+
+// Tests that the chain is not rewound when the confirmed block is unknown,
+// either because it cannot be loaded or because the engine is not dpos.
+func TestRewindChainUnknownFinalized(t *testing.T) {
+	eth, cleanup := newRewindTestNode(t, 4, 0, false)
+	defer cleanup()
+
+	if _, err := eth.rewindChain(3); err != protocol.ErrUnknownFinalized {
+		t.Errorf("missing confirmed block: have %v, want %v", err, protocol.ErrUnknownFinalized)
+	}
+	eth.engine = ethash.NewFaker()
+	if _, err := eth.rewindChain(3); err != protocol.ErrUnknownFinalized {
+		t.Errorf("non-dpos engine: have %v, want %v", err, protocol.ErrUnknownFinalized)
+	}
+	if head := eth.blockchain.CurrentBlock().NumberU64(); head != 4 {
+		t.Errorf("head changed by refused rewind: have %d, want 4", head)
+	}
+}
+
+// Tests that a read-only replica drops the transactions of the abandoned blocks
+// instead of reinjecting them into its transaction pool.
+func TestRewindChainReadOnly(t *testing.T) {
+	eth, cleanup := newRewindTestNode(t, 6, 3, true)
+	defer cleanup()
+
+	report, err := eth.rewindChain(4)
+	if err != nil {
+		t.Fatalf("failed to rewind: %v", err)
+	}
+	if len(report.Reinjected) != 0 || len(report.Dropped) != 2 {
+		t.Errorf("reinjection mismatch: reinjected %d, dropped %v", len(report.Reinjected), report.Dropped)
+	}
+	for hash, reason := range report.Dropped {
+		if reason != errReadOnly.Error() {
+			t.Errorf("tx %x: drop reason mismatch: have %q, want %q", hash, reason, errReadOnly)
+		}
+	}
+	if pending, queued := eth.txPool.Stats(); pending+queued != 0 {
+		t.Errorf("transactions pooled on read-only node: pending %d, queued %d", pending, queued)
+	}
+}
@@ -0,0 +1,201 @@
+package eth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/log"
+)
+
+const (
+	snapshotManifest  = "snapshot.json"    //快照描述文件
+	snapshotChainData = "chaindata"        //快照中的区块链数据库目录
+	stagedChainSuffix = ".restore"         //等待下次启动时替换区块链数据库的目录后缀
+	stagingSuffix     = ".restore.partial" //正在复制的恢复目录后缀(复制完成后改名)
+)
+
+//快照中附带的节点文件(只复制节点列表，节点私钥、keystore以及节点数据库不会进入快照)
+var snapshotFiles = []string{"static-nodes.json", "trusted-nodes.json"}
+
+var errSnapshotMemoryDB = errors.New("snapshots require a persistent chain database")
+
+//在线快照的描述
+type SnapshotManifest struct {
+	Genesis common.Hash `json:"genesis"` //快照所属链的创世区块
+	Number  uint64      `json:"number"`  //快照中的链头区块
+	Hash    common.Hash `json:"hash"`
+	Entries int         `json:"entries"` //复制的数据库条目数量
+	Files   []string    `json:"files"`   //附带的节点文件
+	Created int64       `json:"created"` //创建时间(unix秒)
+}
+
+//在节点运行时将区块链数据库的一致快照以及节点列表文件写入path目录
+func (s *Ethereum) createSnapshot(path string) (*SnapshotManifest, error) {
+
+	db, ok := s.chainDb.(*ethdb.LDBDatabase)
+	if !ok {
+		return nil, errSnapshotMemoryDB
+	}
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(path, snapshotManifest)); err == nil {
+		return nil, fmt.Errorf("snapshot already exists in %s", path)
+	}
+	start := time.Now()
+	//链头的状态可能还在内存中，复制之前先写入磁盘
+	if err := s.blockchain.TrieDB().Commit(s.blockchain.CurrentBlock().Root()); err != nil {
+		return nil, err
+	}
+	entries, err := db.CopySnapshot(filepath.Join(path, snapshotChainData))
+	if err != nil {
+		return nil, err
+	}
+	manifest := &SnapshotManifest{
+		Genesis: s.blockchain.Genesis().Hash(),
+		Entries: entries,
+		Files:   []string{},
+		Created: time.Now().Unix(),
+	}
+	//链头以快照中的数据为准(复制开始后导入的区块不在快照中)
+	copied, err := ethdb.NewLDBDatabase(filepath.Join(path, snapshotChainData), 16, 16)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Hash = core.GetHeadBlockHash(copied)
+	manifest.Number = core.GetBlockNumber(copied, manifest.Hash)
+	copied.Close()
+
+	for _, name := range snapshotFiles {
+		src := filepath.Join(s.instanceDir, name)
+		if !common.FileExist(src) {
+			continue
+		}
+		if err := copyFile(filepath.Join(path, name), src); err != nil {
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files, name)
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(path, snapshotManifest), data, 0600); err != nil {
+		return nil, err
+	}
+	log.Info("Created chain snapshot", "path", path, "number", manifest.Number, "hash", manifest.Hash,
+		"entries", entries, "elapsed", common.PrettyDuration(time.Since(start)))
+	return manifest, nil
+}
+
+//将快照复制到节点目录中，节点下次启动时用快照替换区块链数据库；
+//节点目录中没有的节点列表文件直接从快照复制
+func (s *Ethereum) restoreSnapshot(path string) (*SnapshotManifest, error) {
+
+	if s.instanceDir == "" {
+		return nil, errSnapshotMemoryDB
+	}
+	data, err := ioutil.ReadFile(filepath.Join(path, snapshotManifest))
+	if err != nil {
+		return nil, err
+	}
+	manifest := new(SnapshotManifest)
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid snapshot manifest: %v", err)
+	}
+	if genesis := s.blockchain.Genesis().Hash(); manifest.Genesis != genesis {
+		return nil, fmt.Errorf("snapshot of another chain (genesis %x, local %x)", manifest.Genesis, genesis)
+	}
+	chaindata := filepath.Join(s.instanceDir, snapshotChainData)
+	staging, staged := chaindata+stagingSuffix, chaindata+stagedChainSuffix
+
+	if err := os.RemoveAll(staging); err != nil {
+		return nil, err
+	}
+	if err := copyDir(staging, filepath.Join(path, snapshotChainData)); err != nil {
+		os.RemoveAll(staging)
+		return nil, err
+	}
+	if err := os.RemoveAll(staged); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(staging, staged); err != nil {
+		return nil, err
+	}
+	for _, name := range manifest.Files {
+		dst := filepath.Join(s.instanceDir, filepath.Base(name))
+		if common.FileExist(dst) {
+			continue
+		}
+		if err := copyFile(dst, filepath.Join(path, filepath.Base(name))); err != nil {
+			return nil, err
+		}
+	}
+	log.Warn("Chain snapshot staged, restart the node to apply it", "path", path, "number", manifest.Number, "hash", manifest.Hash)
+	return manifest, nil
+}
+
+//启动时如果有等待恢复的快照，用其替换区块链数据库
+func applyStagedSnapshot(chaindata string) error {
+
+	staged := chaindata + stagedChainSuffix
+	if chaindata == "" || !common.FileExist(staged) {
+		return nil
+	}
+	if err := os.RemoveAll(chaindata); err != nil {
+		return err
+	}
+	if err := os.Rename(staged, chaindata); err != nil {
+		return err
+	}
+	log.Warn("Restored chain database from snapshot", "path", chaindata)
+	return nil
+}
+
+func copyDir(dst, src string) error {
+
+	files, err := ioutil.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, 0700); err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		if err := copyFile(filepath.Join(dst, file.Name()), filepath.Join(src, file.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(dst, src string) error {
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
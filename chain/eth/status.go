@@ -0,0 +1,138 @@
+package eth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core/types"
+)
+
+const (
+	statusSlotWindow = int64(100)                         //统计验证者出块情况的最近时间片数量
+	readyMaxBlockAge = int64(12) * protocol.BlockInterval //链头区块超过该时间（秒）未更新则认为节点未就绪
+)
+
+var (
+	errNodeSyncing = errors.New("node is syncing")
+	errNoPeers     = errors.New("node has no peers")
+	errStaleHead   = errors.New("chain head is stale")
+	errReadOnly    = errors.New("write methods are disabled on a read-only node")
+)
+
+//节点的运行状态，供负载均衡以及k8s探针使用
+type NodeStatus struct {
+	Syncing      bool             `json:"syncing"`             //是否正在同步
+	CurrentBlock hexutil.Uint64   `json:"currentBlock"`        //当前区块号
+	HighestBlock hexutil.Uint64   `json:"highestBlock"`        //已知的最高区块号
+	Peers        int              `json:"peers"`               //连接的节点数量
+	LastBlockAge int64            `json:"lastBlockAge"`        //链头区块距今的秒数
+	Validator    *ValidatorStatus `json:"validator,omitempty"` //本节点为验证者时的出块情况
+	ReadOnly     bool             `json:"readOnly,omitempty"`  //是否为只读副本
+}
+
+//验证者在最近时间片内的出块情况
+type ValidatorStatus struct {
+	Address       common.Address `json:"address"`       //出块账号
+	AssignedSlots int            `json:"assignedSlots"` //最近分配给本节点的时间片数量
+	ProducedSlots int            `json:"producedSlots"` //其中本节点实际出块的数量
+}
+
+//得到节点当前的运行状态
+func (s *Ethereum) NodeStatus() *NodeStatus {
+
+	progress := s.Downloader().Progress()
+	status := &NodeStatus{
+		Syncing:      s.Downloader().Synchronising() && progress.CurrentBlock < progress.HighestBlock,
+		HighestBlock: hexutil.Uint64(progress.HighestBlock),
+		Peers:        s.protocolManager.peers.Len(),
+		ReadOnly:     s.config.ReadOnly,
+	}
+	current := s.blockchain.CurrentBlock()
+	if current == nil {
+		return status
+	}
+	status.CurrentBlock = hexutil.Uint64(current.NumberU64())
+	if status.HighestBlock < status.CurrentBlock {
+		status.HighestBlock = status.CurrentBlock
+	}
+	status.LastBlockAge = time.Now().Unix() - current.Time().Int64()
+
+	if s.IsMining() {
+		if coinbase, err := s.Coinbase(); err == nil {
+			status.Validator = s.validatorStatus(current, coinbase)
+		}
+	}
+	return status
+}
+
+//统计最近时间片内分配给验证者的时间片以及实际产生的区块
+func (s *Ethereum) validatorStatus(current *types.Block, validator common.Address) *ValidatorStatus {
+
+	status := &ValidatorStatus{Address: validator}
+	genesis := s.blockchain.GetBlockByNumber(0)
+	if genesis == nil {
+		return status
+	}
+	dposContext, err := types.NewDposContextFromProto(s.chainDb, current.Header().DposProto)
+	if err != nil {
+		return status
+	}
+	firstTimer := genesis.Time().Int64()
+	end := (time.Now().Unix()-firstTimer)/protocol.BlockInterval*protocol.BlockInterval + firstTimer
+	start := end - statusSlotWindow*protocol.BlockInterval
+	if start < firstTimer {
+		start = firstTimer
+	}
+
+	//在已结束的时间片中统计分配给本节点的数量
+	for slot := start + protocol.BlockInterval; slot < end; slot += protocol.BlockInterval {
+		producer, err := dposContext.GetCurrentNowProducer(firstTimer, slot)
+		if err != nil {
+			return status
+		}
+		if producer == validator {
+			status.AssignedSlots++
+		}
+	}
+
+	//统计同一时间范围内本节点产生的区块
+	for header := current.Header(); header != nil && header.Number.Sign() > 0 && header.Time.Int64() > start; {
+		if header.Time.Int64() < end && header.Validator == validator {
+			status.ProducedSlots++
+		}
+		header = s.blockchain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	}
+	return status
+}
+
+//实现node.HealthChecker接口
+func (s *Ethereum) HealthStatus() interface{} {
+	return s.NodeStatus()
+}
+
+//区块链不可用时认为节点不健康
+func (s *Ethereum) Healthy() error {
+
+	if s.blockchain.CurrentBlock() == nil {
+		return ErrCurrentBlock
+	}
+	return nil
+}
+
+//正在同步、没有连接任何节点（本节点出块时除外）或链头长时间未更新时认为节点未就绪
+func (s *Ethereum) Ready() error {
+
+	status := s.NodeStatus()
+	switch {
+	case status.Syncing:
+		return errNodeSyncing
+	case status.Peers == 0 && !s.IsMining():
+		return errNoPeers
+	case status.LastBlockAge > readyMaxBlockAge:
+		return errStaleHead
+	}
+	return nil
+}
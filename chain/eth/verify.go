@@ -0,0 +1,177 @@
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/log"
+)
+
+const (
+	verifyLogInterval = 8 * time.Second //校验过程中输出进度日志的间隔
+	maxVerifyFailures = 256             //保留的校验失败区块数量
+)
+
+var errVerifyRunning = errors.New("chain verification already running")
+
+//区块校验失败的原因
+type VerifyFailure struct {
+	Number hexutil.Uint64 `json:"number"`
+	Hash   common.Hash    `json:"hash"`
+	Error  string         `json:"error"`
+}
+
+//主链数据完整性校验的进度
+type ChainVerification struct {
+	From     hexutil.Uint64   `json:"from"`
+	To       hexutil.Uint64   `json:"to"`
+	Current  hexutil.Uint64   `json:"current"`  //最后校验的区块
+	Verified uint64           `json:"verified"` //已校验的区块数量
+	Running  bool             `json:"running"`
+	Aborted  bool             `json:"aborted"`
+	Started  int64            `json:"started"` //开始时间(unix秒)
+	Elapsed  float64          `json:"elapsed"` //已用时间(秒)
+	Failures []*VerifyFailure `json:"failures"`
+}
+
+//在后台逐个重新校验主链区块，同一时间只运行一个校验任务
+type chainVerifier struct {
+	db     ethdb.Database
+	chain  *core.BlockChain
+	lock   sync.Mutex
+	status *ChainVerification
+	abort  chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newChainVerifier(db ethdb.Database, chain *core.BlockChain) *chainVerifier {
+	return &chainVerifier{db: db, chain: chain}
+}
+
+//开始校验[from, to]范围内的主链区块
+func (v *chainVerifier) start(from, to uint64) (*ChainVerification, error) {
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if v.status != nil && v.status.Running {
+		return nil, errVerifyRunning
+	}
+	if head := v.chain.CurrentBlock().NumberU64(); to > head {
+		to = head
+	}
+	if from > to {
+		return nil, fmt.Errorf("invalid range: from %d > to %d", from, to)
+	}
+	v.status = &ChainVerification{
+		From:     hexutil.Uint64(from),
+		To:       hexutil.Uint64(to),
+		Current:  hexutil.Uint64(from),
+		Running:  true,
+		Started:  time.Now().Unix(),
+		Failures: []*VerifyFailure{},
+	}
+	v.abort = make(chan struct{})
+	v.wg.Add(1)
+	go v.run(from, to, v.abort)
+
+	return v.copyStatus(), nil
+}
+
+func (v *chainVerifier) run(from, to uint64, abort chan struct{}) {
+
+	defer v.wg.Done()
+
+	log.Info("Chain verification started", "from", from, "to", to)
+	var (
+		start  = time.Now()
+		logged = time.Now()
+	)
+	for number := from; number <= to; number++ {
+		select {
+		case <-abort:
+			v.finish(start, true)
+			log.Warn("Chain verification aborted", "number", number)
+			return
+		default:
+		}
+		err := v.chain.VerifyBlock(number)
+
+		v.lock.Lock()
+		v.status.Current, v.status.Verified = hexutil.Uint64(number), v.status.Verified+1
+		if err != nil && len(v.status.Failures) < maxVerifyFailures {
+			v.status.Failures = append(v.status.Failures, &VerifyFailure{
+				Number: hexutil.Uint64(number),
+				Hash:   core.GetCanonicalHash(v.db, number),
+				Error:  err.Error(),
+			})
+		}
+		v.lock.Unlock()
+
+		if err != nil {
+			log.Error("Chain verification failed", "number", number, "err", err)
+		}
+		if time.Since(logged) > verifyLogInterval {
+			log.Info("Verifying chain", "number", number, "to", to, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	v.finish(start, false)
+
+	v.lock.Lock()
+	failures := len(v.status.Failures)
+	v.lock.Unlock()
+	log.Info("Chain verification finished", "from", from, "to", to, "failures", failures, "elapsed", common.PrettyDuration(time.Since(start)))
+}
+
+func (v *chainVerifier) finish(start time.Time, aborted bool) {
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	v.status.Running, v.status.Aborted = false, aborted
+	v.status.Elapsed = time.Since(start).Seconds()
+}
+
+//中止正在运行的校验任务，没有运行中的任务时返回false
+func (v *chainVerifier) stop() bool {
+
+	v.lock.Lock()
+	running := v.status != nil && v.status.Running
+	if running {
+		close(v.abort)
+		v.status.Running = false
+	}
+	v.lock.Unlock()
+
+	v.wg.Wait()
+	return running
+}
+
+//得到最近一次校验任务的进度，没有运行过校验时返回nil
+func (v *chainVerifier) progress() *ChainVerification {
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	return v.copyStatus()
+}
+
+func (v *chainVerifier) copyStatus() *ChainVerification {
+
+	if v.status == nil {
+		return nil
+	}
+	status := *v.status
+	status.Failures = append([]*VerifyFailure{}, v.status.Failures...)
+	if status.Running {
+		status.Elapsed = time.Since(time.Unix(status.Started, 0)).Seconds()
+	}
+	return &status
+}
@@ -0,0 +1,135 @@
+package eth
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/consensus/dpos"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/params"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//一个账号在快照区块中的投票记录
+type VoteSnapshotRecord struct {
+	Voter     common.Address `json:"voter"`
+	Candidate common.Address `json:"candidate"`
+	Amount    *hexutil.Big   `json:"amount"`           //投票时转入投票合约的数量
+	Epoch     hexutil.Uint64 `json:"epoch"`            //最后一次投票的周期
+	Weight    *hexutil.Big   `json:"weight,omitempty"` //按快照区块所在周期计算的投票权重(投票权重分叉之前为空)
+}
+
+//一个候选者在快照区块中得到的投票
+type VoteSnapshotTally struct {
+	Candidate common.Address `json:"candidate"`
+	Voters    hexutil.Uint64 `json:"voters"`
+	Amount    *hexutil.Big   `json:"amount"`
+	Weight    *hexutil.Big   `json:"weight,omitempty"`
+}
+
+//指定区块的投票快照(来自该区块的Dpos投票树，不随之后的投票变化)
+type VoteSnapshot struct {
+	BlockNumber hexutil.Uint64        `json:"blockNumber"`
+	BlockHash   common.Hash           `json:"blockHash"`
+	Epoch       hexutil.Uint64        `json:"epoch"`
+	Mode        params.VoteWeightMode `json:"mode,omitempty"` //投票权重的计算方式(投票权重分叉之前为空)
+	Votes       []*VoteSnapshotRecord `json:"votes"`          //按投票账号地址排序
+	Candidates  []*VoteSnapshotTally  `json:"candidates"`     //按权重(分叉之前按数量)从高到低排序，相同时按地址排序
+}
+
+//从指定区块的历史投票树得到全部投票记录以及每个候选者的汇总；live方式的权重需要该区块的状态(余额)
+func (s *Ethereum) votesAt(blockNr rpc.BlockNumber) (*VoteSnapshot, error) {
+
+	var header *types.Header
+	switch blockNr {
+	case rpc.PendingBlockNumber:
+		return nil, protocol.ErrUnknownBlock
+	case rpc.LatestBlockNumber:
+		header = s.blockchain.CurrentHeader()
+	default:
+		header = s.blockchain.GetHeaderByNumber(uint64(blockNr))
+	}
+	genesis := s.blockchain.GetHeaderByNumber(0)
+	if header == nil || genesis == nil {
+		return nil, protocol.ErrUnknownBlock
+	}
+	dposContext, err := types.NewDposContextFromProto(s.chainDb, header.DposProto)
+	if err != nil {
+		return nil, err
+	}
+	voters, records, err := dposContext.VoteRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	epoch := dpos.EpochOf(header.Time.Int64(), genesis.Time.Int64())
+	snapshot := &VoteSnapshot{
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		BlockHash:   header.Hash(),
+		Epoch:       hexutil.Uint64(epoch),
+		Votes:       make([]*VoteSnapshotRecord, 0, len(records)),
+		Candidates:  []*VoteSnapshotTally{},
+	}
+	config := s.blockchain.Config()
+	weighted := config.IsVoteWeight(header.Number)
+	balance := func(common.Address) *big.Int { return new(big.Int) }
+	if weighted {
+		snapshot.Mode = config.VoteWeight.Mode
+		if config.VoteWeight.Mode == params.VoteWeightLive {
+			statedb, err := s.stateAt(header)
+			if err != nil {
+				return nil, err
+			}
+			balance = statedb.GetBalance
+		}
+	}
+
+	tallies := make(map[common.Address]*VoteSnapshotTally)
+	for i, record := range records {
+		vote := &VoteSnapshotRecord{
+			Voter:     voters[i],
+			Candidate: record.Candidate,
+			Amount:    (*hexutil.Big)(record.Amount),
+			Epoch:     hexutil.Uint64(record.Epoch),
+		}
+		tally, ok := tallies[record.Candidate]
+		if !ok {
+			tally = &VoteSnapshotTally{Candidate: record.Candidate, Amount: new(hexutil.Big)}
+			if weighted {
+				tally.Weight = new(hexutil.Big)
+			}
+			tallies[record.Candidate] = tally
+			snapshot.Candidates = append(snapshot.Candidates, tally)
+		}
+		tally.Voters++
+		(*big.Int)(tally.Amount).Add((*big.Int)(tally.Amount), record.Amount)
+
+		if weighted {
+			age := uint64(0)
+			if epoch > record.Epoch {
+				age = epoch - record.Epoch
+			}
+			weight := config.VoteWeight.Weight(record.Amount, balance(voters[i]), age)
+			vote.Weight = (*hexutil.Big)(weight)
+			(*big.Int)(tally.Weight).Add((*big.Int)(tally.Weight), weight)
+		}
+		snapshot.Votes = append(snapshot.Votes, vote)
+	}
+
+	sort.Slice(snapshot.Candidates, func(i, j int) bool {
+		a, b := snapshot.Candidates[i], snapshot.Candidates[j]
+		x, y := a.Amount, b.Amount
+		if weighted {
+			x, y = a.Weight, b.Weight
+		}
+		if cmp := (*big.Int)(x).Cmp((*big.Int)(y)); cmp != 0 {
+			return cmp > 0
+		}
+		return bytes.Compare(a.Candidate.Bytes(), b.Candidate.Bytes()) < 0
+	})
+	return snapshot, nil
+}
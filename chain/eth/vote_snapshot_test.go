@@ -0,0 +1,109 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/consensus/ethash"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/core/vm"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+// Tests that vote snapshots are read from the vote trie of the requested block,
+// tallied by amount before the vote weight fork and by weight afterwards.
+func TestVotesAt(t *testing.T) {
+	var (
+		db, _      = ethdb.NewMemDatabase()
+		config     = *params.TestChainConfig
+		candidateA = common.HexToAddress("0x0a")
+		candidateB = common.HexToAddress("0x0b")
+	)
+	config.VoteWeight = &params.VoteWeightConfig{Block: big.NewInt(2), Mode: params.VoteWeightSnapshot, DecayPercent: 50}
+	genesis := (&core.Genesis{Config: &config}).MustCommit(db)
+
+	dposContext, err := types.NewDposContext(db)
+	if err != nil {
+		t.Fatalf("failed to create dpos context: %v", err)
+	}
+	//区块1在周期0，区块2在周期2
+	votes := [][]struct {
+		voter, candidate common.Address
+		amount           int64
+	}{
+		{{common.HexToAddress("0x01"), candidateA, 100}, {common.HexToAddress("0x02"), candidateB, 300}},
+		{{common.HexToAddress("0x03"), candidateA, 400}},
+	}
+	parent := genesis
+	for i, batch := range votes {
+		epoch := uint64(2 * i)
+		for _, vote := range batch {
+			if err := dposContext.AddVoteRecord(vote.voter, vote.candidate, big.NewInt(vote.amount), epoch); err != nil {
+				t.Fatalf("failed to record vote: %v", err)
+			}
+		}
+		proto, err := dposContext.CommitTo(db)
+		if err != nil {
+			t.Fatalf("failed to commit dpos context: %v", err)
+		}
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     big.NewInt(int64(i + 1)),
+			Time:       new(big.Int).Add(genesis.Time(), big.NewInt(int64(epoch)*protocol.EpochInterval)),
+			Root:       genesis.Root(),
+			DposProto:  proto,
+			BokerProto: &types.BokerBackendProto{},
+		}
+		block := types.NewBlock(header, nil, nil, nil)
+		core.WriteBlock(db, block)
+		core.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		core.WriteHeadBlockHash(db, block.Hash())
+		core.WriteHeadHeaderHash(db, block.Hash())
+		parent = block
+	}
+	chain, err := core.NewBlockChain(db, &config, ethash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+	eth := &Ethereum{blockchain: chain, chainDb: db}
+
+	//投票权重分叉之前按数量排序，没有权重
+	snapshot, err := eth.votesAt(1)
+	if err != nil {
+		t.Fatalf("block 1: failed to retrieve votes: %v", err)
+	}
+	if snapshot.Epoch != 0 || snapshot.Mode != "" || len(snapshot.Votes) != 2 || len(snapshot.Candidates) != 2 {
+		t.Fatalf("block 1: snapshot mismatch: %+v", snapshot)
+	}
+	if tally := snapshot.Candidates[0]; tally.Candidate != candidateB || tally.Voters != 1 || tally.Amount.ToInt().Int64() != 300 || tally.Weight != nil {
+		t.Errorf("block 1: first candidate mismatch: %+v", tally)
+	}
+
+	//分叉之后按衰减后的权重排序: 100/4 + 400 对 300/4
+	snapshot, err = eth.votesAt(rpc.LatestBlockNumber)
+	if err != nil {
+		t.Fatalf("latest: failed to retrieve votes: %v", err)
+	}
+	if snapshot.BlockNumber != 2 || snapshot.Epoch != 2 || snapshot.Mode != params.VoteWeightSnapshot || len(snapshot.Votes) != 3 {
+		t.Fatalf("latest: snapshot mismatch: %+v", snapshot)
+	}
+	if tally := snapshot.Candidates[0]; tally.Candidate != candidateA || tally.Voters != 2 || tally.Amount.ToInt().Int64() != 500 || tally.Weight.ToInt().Int64() != 425 {
+		t.Errorf("latest: first candidate mismatch: %+v", tally)
+	}
+	if tally := snapshot.Candidates[1]; tally.Candidate != candidateB || tally.Weight.ToInt().Int64() != 75 {
+		t.Errorf("latest: second candidate mismatch: %+v", tally)
+	}
+
+	if _, err := eth.votesAt(3); err != protocol.ErrUnknownBlock {
+		t.Errorf("unknown block: have %v, want %v", err, protocol.ErrUnknownBlock)
+	}
+	if _, err := eth.votesAt(rpc.PendingBlockNumber); err != protocol.ErrUnknownBlock {
+		t.Errorf("pending block: have %v, want %v", err, protocol.ErrUnknownBlock)
+	}
+}
@@ -17,6 +17,8 @@
 package ethdb
 
 import (
+	"fmt"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -149,6 +151,56 @@ func (db *LDBDatabase) NewIterator() iterator.Iterator {
 	return db.db.NewIterator(nil, nil)
 }
 
+// CopySnapshot writes a consistent point-in-time copy of the database into a
+// new LevelDB database at path, without blocking writes to the live database.
+// It returns the number of entries copied.
+func (db *LDBDatabase) CopySnapshot(path string) (int, error) {
+	if _, err := os.Stat(path); err == nil {
+		return 0, fmt.Errorf("snapshot destination %s already exists", path)
+	}
+	snap, err := db.db.GetSnapshot()
+	if err != nil {
+		return 0, err
+	}
+	defer snap.Release()
+
+	out, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	var (
+		it    = snap.NewIterator(nil, nil)
+		batch = new(leveldb.Batch)
+		size  int
+		count int
+	)
+	defer it.Release()
+
+	for it.Next() {
+		batch.Put(it.Key(), it.Value())
+		size += len(it.Key()) + len(it.Value())
+		count++
+
+		if size >= IdealBatchSize {
+			if err := out.Write(batch, nil); err != nil {
+				return count, err
+			}
+			batch.Reset()
+			size = 0
+		}
+	}
+	if err := it.Error(); err != nil {
+		return count, err
+	}
+	if err := out.Write(batch, nil); err != nil {
+		return count, err
+	}
+	db.log.Info("Copied database snapshot", "path", path, "entries", count)
+	return count, nil
+}
+
 func (db *LDBDatabase) Close() {
 	// Stop the metrics collection to avoid internal database races
 	db.quitLock.Lock()
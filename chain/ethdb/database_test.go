@@ -190,3 +190,49 @@ func testParallelPutGet(db ethdb.Database, t *testing.T) {
 	}
 	pending.Wait()
 }
+
+func TestLDB_CopySnapshot(t *testing.T) {
+	db, remove := newTestLDB()
+	defer remove()
+
+	for i, v := range test_values {
+		if err := db.Put([]byte(strconv.Itoa(i)), []byte(v)); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+	}
+	dirname, err := ioutil.TempDir(os.TempDir(), "ethdb_snapshot_")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dirname)
+
+	path := dirname + "/copy"
+	count, err := db.CopySnapshot(path)
+	if err != nil {
+		t.Fatalf("snapshot failed: %v", err)
+	}
+	if count != len(test_values) {
+		t.Fatalf("copied entries mismatch: have %d, want %d", count, len(test_values))
+	}
+	// Writes after the snapshot must not affect the copy
+	db.Put([]byte("later"), []byte("value"))
+
+	copied, err := ethdb.NewLDBDatabase(path, 0, 0)
+	if err != nil {
+		t.Fatalf("failed to open snapshot: %v", err)
+	}
+	defer copied.Close()
+
+	for i, v := range test_values {
+		data, err := copied.Get([]byte(strconv.Itoa(i)))
+		if err != nil || !bytes.Equal(data, []byte(v)) {
+			t.Fatalf("entry %d mismatch: have %q (%v), want %q", i, data, err, v)
+		}
+	}
+	if ok, _ := copied.Has([]byte("later")); ok {
+		t.Fatalf("write after the snapshot leaked into the copy")
+	}
+	if _, err := db.CopySnapshot(path); err == nil {
+		t.Fatalf("snapshot into existing destination succeeded")
+	}
+}
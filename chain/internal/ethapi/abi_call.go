@@ -0,0 +1,283 @@
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/Tinachain/Tina/chain/accounts/abi"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core/vm"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+var (
+	errNoContractABI  = errors.New("no abi given and none registered for contract")
+	errUnknownMethod  = errors.New("method not found in abi")
+	errArgumentsCount = errors.New("mismatched number of method arguments")
+)
+
+//本地登记的合约Abi（系统基础合约在启动合约服务时登记）
+var (
+	contractABIs     = make(map[common.Address]string)
+	contractABIsLock sync.RWMutex
+)
+
+//登记合约的Abi，供boker_callContract在没有传入Abi时使用
+func RegisterContractABI(address common.Address, abiJson string) {
+
+	contractABIsLock.Lock()
+	defer contractABIsLock.Unlock()
+	contractABIs[address] = abiJson
+}
+
+func registeredContractABI(address common.Address) (string, bool) {
+
+	contractABIsLock.RLock()
+	defer contractABIsLock.RUnlock()
+	abiJson, ok := contractABIs[address]
+	return abiJson, ok
+}
+
+//调用合约方法的参数
+type CallContractArgs struct {
+	From     common.Address    `json:"from"`
+	To       common.Address    `json:"to"`
+	Abi      string            `json:"abi"`    //合约Abi（为空时使用本地或链上登记的Abi）
+	Method   string            `json:"method"` //方法名称
+	Args     []json.RawMessage `json:"args"`   //方法参数（按Abi中的类型解析）
+	Gas      hexutil.Big       `json:"gas"`
+	GasPrice hexutil.Big       `json:"gasPrice"`
+	Value    hexutil.Big       `json:"value"`
+}
+
+//调用合约方法的RPC输出格式
+type RPCContractResult struct {
+	Data    hexutil.Bytes          `json:"data"`    //原始返回数据
+	Outputs map[string]interface{} `json:"outputs"` //按Abi解码后的返回值（没有名称的返回值使用序号）
+}
+
+//根据Abi在服务端编码参数并执行Call，返回解码后的结果
+func (s *PublicBokerAPI) CallContract(ctx context.Context, args CallContractArgs, blockNr rpc.BlockNumber) (*RPCContractResult, error) {
+
+	ctx, cancel := withRPCTimeout(ctx, s.b, "boker_callContract")
+	defer cancel()
+
+	abiJson := args.Abi
+	if abiJson == "" {
+		abiJson, _ = registeredContractABI(args.To)
+	}
+	if abiJson == "" {
+		//使用链上登记的合约元数据中的Abi
+		if meta, err := s.GetContractMeta(ctx, args.To); err == nil && meta != nil {
+			abiJson = meta.Abi
+		}
+	}
+	if abiJson == "" {
+		return nil, errNoContractABI
+	}
+	contractABI, err := abi.JSON(strings.NewReader(abiJson))
+	if err != nil {
+		return nil, err
+	}
+	method, ok := contractABI.Methods[args.Method]
+	if !ok {
+		return nil, errUnknownMethod
+	}
+	if len(args.Args) != len(method.Inputs) {
+		return nil, errArgumentsCount
+	}
+
+	inputs := make([]interface{}, len(method.Inputs))
+	for i, input := range method.Inputs {
+		if inputs[i], err = abiArgument(input.Type, args.Args[i]); err != nil {
+			return nil, fmt.Errorf("argument %d (%s): %v", i, input.Name, err)
+		}
+	}
+	data, err := contractABI.Pack(args.Method, inputs...)
+	if err != nil {
+		return nil, err
+	}
+
+	to := args.To
+	callArgs := CallArgs{
+		From:     args.From,
+		To:       &to,
+		Gas:      args.Gas,
+		GasPrice: args.GasPrice,
+		Value:    args.Value,
+		Data:     data,
+	}
+	result, _, vmerr, err := NewPublicBlockChainAPI(s.b).doCall(ctx, callArgs, rpc.BlockNumberOrHashWithNumber(blockNr), vm.Config{DisableGasMetering: true})
+	if err != nil {
+		return nil, err
+	}
+	if vmerr != nil {
+		return nil, newCallError(result, vmerr)
+	}
+
+	outputs, err := abiOutputs(contractABI, method, result)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCContractResult{Data: result, Outputs: outputs}, nil
+}
+
+//将JSON参数转换为Abi类型对应的Go类型
+func abiArgument(t abi.Type, raw json.RawMessage) (interface{}, error) {
+
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+
+		value, err := jsonInteger(raw)
+		if err != nil {
+			return nil, err
+		}
+		if t.Type == reflect.TypeOf(&big.Int{}) {
+			return value, nil
+		}
+		v := reflect.New(t.Type).Elem()
+		if t.T == abi.IntTy {
+			if !value.IsInt64() || v.OverflowInt(value.Int64()) {
+				return nil, fmt.Errorf("integer %v overflows %v", value, t.Type)
+			}
+			v.SetInt(value.Int64())
+		} else {
+			if value.Sign() < 0 || !value.IsUint64() || v.OverflowUint(value.Uint64()) {
+				return nil, fmt.Errorf("integer %v overflows %v", value, t.Type)
+			}
+			v.SetUint(value.Uint64())
+		}
+		return v.Interface(), nil
+	case abi.BoolTy:
+
+		var value bool
+		err := json.Unmarshal(raw, &value)
+		return value, err
+	case abi.StringTy:
+
+		var value string
+		err := json.Unmarshal(raw, &value)
+		return value, err
+	case abi.AddressTy:
+
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+		if !common.IsHexAddress(value) {
+			return nil, fmt.Errorf("invalid address %q", value)
+		}
+		return common.HexToAddress(value), nil
+	case abi.BytesTy:
+
+		var value hexutil.Bytes
+		err := json.Unmarshal(raw, &value)
+		return []byte(value), err
+	case abi.FixedBytesTy, abi.HashTy, abi.FunctionTy:
+
+		var value hexutil.Bytes
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+		v := reflect.New(t.Type).Elem()
+		if v.Kind() != reflect.Array || len(value) > v.Len() {
+			return nil, fmt.Errorf("invalid %v value", t)
+		}
+		reflect.Copy(v, reflect.ValueOf([]byte(value)))
+		return v.Interface(), nil
+	case abi.SliceTy, abi.ArrayTy:
+
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, err
+		}
+		var v reflect.Value
+		if t.T == abi.SliceTy {
+			v = reflect.MakeSlice(t.Type, len(items), len(items))
+		} else {
+			if len(items) != t.Size {
+				return nil, fmt.Errorf("expected %d array items, got %d", t.Size, len(items))
+			}
+			v = reflect.New(t.Type).Elem()
+		}
+		for i, item := range items {
+			elem, err := abiArgument(*t.Elem, item)
+			if err != nil {
+				return nil, err
+			}
+			v.Index(i).Set(reflect.ValueOf(elem))
+		}
+		return v.Interface(), nil
+	}
+	return nil, fmt.Errorf("unsupported abi type %v", t)
+}
+
+//整数参数可以是JSON数字、十进制字符串或者0x开头的十六进制字符串
+func jsonInteger(raw json.RawMessage) (*big.Int, error) {
+
+	text := strings.Trim(strings.TrimSpace(string(raw)), `"`)
+	value, ok := new(big.Int).SetString(text, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %s", raw)
+	}
+	return value, nil
+}
+
+//按Abi解码方法的返回值
+func abiOutputs(contractABI abi.ABI, method abi.Method, result []byte) (map[string]interface{}, error) {
+
+	outputs := make(map[string]interface{})
+	if len(method.Outputs) == 0 || len(result) == 0 {
+		return outputs, nil
+	}
+
+	values, err := contractABI.UnpackValues(method.Name, result)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, output := range method.Outputs {
+		name := output.Name
+		if name == "" {
+			name = fmt.Sprintf("%d", i)
+		}
+		outputs[name] = rpcABIValue(reflect.ValueOf(values[i]))
+	}
+	return outputs, nil
+}
+
+//将解码后的值转换为适合JSON输出的格式
+func rpcABIValue(v reflect.Value) interface{} {
+
+	switch value := v.Interface().(type) {
+	case *big.Int:
+		return (*hexutil.Big)(value)
+	case []byte:
+		return hexutil.Bytes(value)
+	case common.Address, common.Hash:
+		return value
+	}
+	switch v.Kind() {
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return hexutil.Bytes(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = rpcABIValue(v.Index(i))
+		}
+		return items
+	}
+	return v.Interface()
+}
@@ -0,0 +1,41 @@
+package ethapi
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/accounts/abi"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+)
+
+const multiReturnABI = `[{"constant":true,"inputs":[],"name":"info","outputs":[{"name":"count","type":"uint256"},{"name":"owner","type":"address"},{"name":"","type":"bool"}],"type":"function"}]`
+
+// Tests that every value of a method returning several outputs is decoded.
+func TestABIOutputsMultiReturn(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(multiReturnABI))
+	if err != nil {
+		t.Fatalf("failed to parse abi: %v", err)
+	}
+	owner := common.HexToAddress("0x0102030405060708090a0b0c0d0e0f1011121314")
+
+	var result []byte
+	result = append(result, common.LeftPadBytes(big.NewInt(42).Bytes(), 32)...)
+	result = append(result, common.LeftPadBytes(owner.Bytes(), 32)...)
+	result = append(result, common.LeftPadBytes([]byte{1}, 32)...)
+
+	outputs, err := abiOutputs(contractABI, contractABI.Methods["info"], result)
+	if err != nil {
+		t.Fatalf("failed to decode outputs: %v", err)
+	}
+	if count, ok := outputs["count"].(*hexutil.Big); !ok || count.ToInt().Int64() != 42 {
+		t.Errorf("count mismatch: have %v, want 42", outputs["count"])
+	}
+	if have, ok := outputs["owner"].(common.Address); !ok || have != owner {
+		t.Errorf("owner mismatch: have %v, want %x", outputs["owner"], owner)
+	}
+	if flag, ok := outputs["2"].(bool); !ok || !flag {
+		t.Errorf("unnamed output mismatch: have %v, want true", outputs["2"])
+	}
+}
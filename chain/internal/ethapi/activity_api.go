@@ -0,0 +1,246 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+const (
+	maxActivityBlocks    = 10000 //单次查询允许的最大区块范围
+	defaultActivityLimit = 100   //默认每页返回的记录数
+	maxActivityLimit     = 1000  //每页最多返回的记录数
+)
+
+//账号活动的类型
+const (
+	ActivityTransaction = "transaction" //账号发出或接收的交易
+	ActivityInternal    = "internal"    //合约执行中产生的内部交易(需要节点开启--internaltxindex)
+	ActivityStock       = "stock"       //涉及账号的股权交易
+	ActivityReward      = "reward"      //出块奖励
+	ActivityStockPayout = "stockPayout" //股权分红
+)
+
+var (
+	errActivityRange = errors.New("invalid block range for account activity")
+	errActivitySpan  = errors.New("account activity block range too large")
+)
+
+//分页参数
+type ActivityPage struct {
+	Offset hexutil.Uint64 `json:"offset"`
+	Limit  hexutil.Uint64 `json:"limit"` //为0时使用默认值
+}
+
+//账号的一条活动记录
+type RPCAccountActivity struct {
+	Kind             string           `json:"kind"`
+	BlockNumber      hexutil.Uint64   `json:"blockNumber"`
+	BlockHash        common.Hash      `json:"blockHash"`
+	Timestamp        *hexutil.Big     `json:"timestamp"`
+	TransactionHash  *common.Hash     `json:"transactionHash"`  //奖励和分红为空
+	TransactionIndex *hexutil.Uint    `json:"transactionIndex"` //奖励和分红为空
+	Major            protocol.TxMajor `json:"major"`
+	Minor            protocol.TxMinor `json:"minor"`
+	From             common.Address   `json:"from"`
+	To               *common.Address  `json:"to"`    //创建合约时为空
+	Value            *hexutil.Big     `json:"value"` //股权交易为股权数量
+	Direction        string           `json:"direction"`
+	Failed           bool             `json:"failed,omitempty"`
+}
+
+//一页账号活动
+type RPCAccountActivityPage struct {
+	Address    common.Address        `json:"address"`
+	FromBlock  hexutil.Uint64        `json:"fromBlock"`
+	ToBlock    hexutil.Uint64        `json:"toBlock"`
+	Total      hexutil.Uint64        `json:"total"` //区块范围内的记录总数
+	Activities []*RPCAccountActivity `json:"activities"`
+}
+
+//资金相对于查询账号的方向
+func activityDirection(address, from common.Address, to *common.Address) string {
+	switch {
+	case from == address && to != nil && *to == address:
+		return "self"
+	case from == address:
+		return "out"
+	}
+	return "in"
+}
+
+//将查询的区块范围转换为区块号，结束区块超过当前区块时截断到当前区块
+func activityRange(b Backend, fromBlock, toBlock rpc.BlockNumber) (uint64, uint64, error) {
+
+	if fromBlock == rpc.PendingBlockNumber || toBlock == rpc.PendingBlockNumber {
+		return 0, 0, protocol.ErrUnknownBlock
+	}
+	head := b.CurrentBlock().NumberU64()
+	from, to := uint64(fromBlock), uint64(toBlock)
+	if fromBlock == rpc.LatestBlockNumber {
+		from = head
+	}
+	if toBlock == rpc.LatestBlockNumber || to > head {
+		to = head
+	}
+	if from > to {
+		return 0, 0, errActivityRange
+	}
+	if to-from >= maxActivityBlocks {
+		return 0, 0, errActivitySpan
+	}
+	return from, to, nil
+}
+
+//按时间顺序返回账号在区块范围内的交易、内部交易、股权交易、出块奖励以及股权分红，
+//同一区块内按交易顺序排列(内部交易紧跟在所属交易之后)，奖励和分红排在区块的最后
+func (s *PublicBokerAPI) GetAccountActivity(ctx context.Context, address common.Address, fromBlock, toBlock rpc.BlockNumber, page *ActivityPage) (*RPCAccountActivityPage, error) {
+
+	from, to, err := activityRange(s.b, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, limit := uint64(0), uint64(defaultActivityLimit)
+	if page != nil {
+		offset = uint64(page.Offset)
+		if page.Limit != 0 {
+			limit = uint64(page.Limit)
+		}
+	}
+	if limit > maxActivityLimit {
+		limit = maxActivityLimit
+	}
+
+	//通过内部交易索引得到涉及该账号的交易
+	internals := make(map[common.Hash]bool)
+	for _, ref := range core.GetInternalTxRefs(s.b.ChainDb(), address) {
+		if ref.BlockNumber >= from && ref.BlockNumber <= to {
+			internals[ref.TxHash] = true
+		}
+	}
+
+	var activities []*RPCAccountActivity
+	for number := from; number <= to; number++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		block, err := s.b.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			break
+		}
+		items, err := s.blockActivity(ctx, address, block, internals)
+		if err != nil {
+			return nil, err
+		}
+		activities = append(activities, items...)
+	}
+
+	result := &RPCAccountActivityPage{
+		Address:    address,
+		FromBlock:  hexutil.Uint64(from),
+		ToBlock:    hexutil.Uint64(to),
+		Total:      hexutil.Uint64(len(activities)),
+		Activities: []*RPCAccountActivity{},
+	}
+	if offset < uint64(len(activities)) {
+		end := offset + limit
+		if end > uint64(len(activities)) {
+			end = uint64(len(activities))
+		}
+		result.Activities = activities[offset:end]
+	}
+	return result, nil
+}
+
+//得到单个区块中与账号相关的活动
+func (s *PublicBokerAPI) blockActivity(ctx context.Context, address common.Address, block *types.Block, internals map[common.Hash]bool) ([]*RPCAccountActivity, error) {
+
+	var (
+		activities []*RPCAccountActivity
+		receipts   types.Receipts
+		hash       = block.Hash()
+		number     = block.NumberU64()
+	)
+	newActivity := func(kind string) *RPCAccountActivity {
+		return &RPCAccountActivity{
+			Kind:        kind,
+			BlockNumber: hexutil.Uint64(number),
+			BlockHash:   hash,
+			Timestamp:   (*hexutil.Big)(block.Time()),
+		}
+	}
+
+	for i, tx := range block.Transactions() {
+
+		txHash, index := tx.Hash(), hexutil.Uint(i)
+		sender, _ := types.Sender(TxSigner(tx), tx)
+		if sender == address || (tx.To() != nil && *tx.To() == address) {
+
+			if receipts == nil {
+				var err error
+				if receipts, err = s.b.GetReceipts(ctx, hash); err != nil {
+					return nil, err
+				}
+			}
+			kind := ActivityTransaction
+			if tx.Major() == protocol.Stock {
+				kind = ActivityStock
+			}
+			activity := newActivity(kind)
+			activity.TransactionHash, activity.TransactionIndex = &txHash, &index
+			activity.Major, activity.Minor = tx.Major(), tx.Minor()
+			activity.From, activity.To = sender, tx.To()
+			activity.Value = (*hexutil.Big)(tx.Value())
+			activity.Direction = activityDirection(address, sender, tx.To())
+			if i < len(receipts) && len(receipts[i].PostState) == 0 {
+				activity.Failed = receipts[i].Status == types.ReceiptStatusFailed
+			}
+			activities = append(activities, activity)
+		}
+
+		if !internals[txHash] {
+			continue
+		}
+		for _, itx := range core.GetInternalTxs(s.b.ChainDb(), txHash) {
+			if itx.From != address && itx.To != address {
+				continue
+			}
+			to := itx.To
+			activity := newActivity(ActivityInternal)
+			activity.TransactionHash, activity.TransactionIndex = &txHash, &index
+			activity.Major, activity.Minor = tx.Major(), tx.Minor()
+			activity.From, activity.To = itx.From, &to
+			activity.Value = (*hexutil.Big)(itx.Value)
+			activity.Direction = activityDirection(address, itx.From, &to)
+			activity.Failed = itx.Failed
+			activities = append(activities, activity)
+		}
+	}
+
+	if rewards := core.GetBlockRewards(s.b.ChainDb(), hash, number); rewards != nil {
+		if rewards.Producer == address && rewards.ProducerReward != nil && rewards.ProducerReward.Sign() > 0 {
+			activity := newActivity(ActivityReward)
+			activity.To, activity.Value, activity.Direction = &address, (*hexutil.Big)(new(big.Int).Set(rewards.ProducerReward)), "in"
+			activities = append(activities, activity)
+		}
+		for _, payout := range rewards.StockPayouts {
+			if payout.Account == address {
+				activity := newActivity(ActivityStockPayout)
+				activity.To, activity.Value, activity.Direction = &address, (*hexutil.Big)(payout.Amount), "in"
+				activities = append(activities, activity)
+			}
+		}
+	}
+	return activities, nil
+}
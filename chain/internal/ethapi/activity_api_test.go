@@ -0,0 +1,163 @@
+package ethapi
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+type activityTestBackend struct {
+	Backend
+	db       ethdb.Database
+	blocks   []*types.Block
+	receipts map[common.Hash]types.Receipts
+	head     *types.Block
+}
+
+func (b *activityTestBackend) ChainDb() ethdb.Database    { return b.db }
+func (b *activityTestBackend) CurrentBlock() *types.Block { return b.head }
+
+func (b *activityTestBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	if int(number) >= len(b.blocks) {
+		return nil, nil
+	}
+	return b.blocks[number], nil
+}
+
+func (b *activityTestBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	return b.receipts[hash], nil
+}
+
+// Tests that the account activity combines the transactions, internal calls,
+// stock moves and rewards of an account in chain order, and pages through them.
+func TestGetAccountActivity(t *testing.T) {
+	var (
+		db, _    = ethdb.NewMemDatabase()
+		key, _   = crypto.GenerateKey()
+		other, _ = crypto.GenerateKey()
+		account  = crypto.PubkeyToAddress(key.PublicKey)
+		peer     = crypto.PubkeyToAddress(other.PublicKey)
+		contract = common.HexToAddress("0xc0")
+		backend  = &activityTestBackend{db: db, receipts: make(map[common.Hash]types.Receipts)}
+	)
+	sign := func(key *ecdsa.PrivateKey, major protocol.TxMajor, minor protocol.TxMinor, nonce uint64, to common.Address, value int64) *types.Transaction {
+		tx, err := types.SignTx(types.NewTransaction(major, minor, nonce, to, big.NewInt(value), big.NewInt(21000), big.NewInt(1), nil), types.HomesteadSigner{}, key)
+		if err != nil {
+			t.Fatalf("failed to sign transaction: %v", err)
+		}
+		return tx
+	}
+	addBlock := func(txs types.Transactions, receipts types.Receipts) *types.Block {
+		header := &types.Header{Number: big.NewInt(int64(len(backend.blocks))), Time: big.NewInt(int64(len(backend.blocks))), DposProto: &types.DposContextProto{}, BokerProto: &types.BokerBackendProto{}}
+		block := types.NewBlockWithHeader(header).WithBody(txs, nil)
+		backend.blocks = append(backend.blocks, block)
+		backend.receipts[block.Hash()] = receipts
+		backend.head = block
+		return block
+	}
+	succeeded := func() *types.Receipt { return &types.Receipt{Status: types.ReceiptStatusSuccessful} }
+
+	addBlock(nil, nil)
+	//区块1: 转出、失败的转入、股权转移、合约内部转入以及出块奖励和股权分红
+	transfer := sign(key, protocol.Normal, protocol.NormalCall, 0, peer, 5)
+	incoming := sign(other, protocol.Normal, protocol.NormalCall, 0, account, 7)
+	stock := sign(key, protocol.Stock, protocol.StockTransfer, 1, peer, 3)
+	call := sign(other, protocol.Normal, protocol.NormalCall, 1, contract, 0)
+	block := addBlock(types.Transactions{transfer, incoming, stock, call}, types.Receipts{succeeded(), {Status: types.ReceiptStatusFailed}, succeeded(), succeeded()})
+	core.WriteInternalTxs(db, block, map[common.Hash][]*types.InternalTx{
+		call.Hash(): {{Type: "CALL", From: contract, To: account, Value: big.NewInt(2), Depth: 1}},
+	})
+	core.WriteBlockRewards(db, block.Hash(), block.NumberU64(), &types.BlockRewards{
+		Producer:       account,
+		ProducerReward: big.NewInt(11),
+		StockPayouts:   []types.StockPayout{{Account: peer, Amount: big.NewInt(4)}, {Account: account, Amount: big.NewInt(6)}},
+	})
+	//区块2: 转给自己
+	self := sign(key, protocol.Normal, protocol.NormalCall, 2, account, 1)
+	addBlock(types.Transactions{self}, types.Receipts{succeeded()})
+
+	api := NewPublicBokerAPI(backend)
+	page, err := api.GetAccountActivity(context.Background(), account, 0, rpc.LatestBlockNumber, nil)
+	if err != nil {
+		t.Fatalf("failed to get activity: %v", err)
+	}
+	want := []struct {
+		kind      string
+		direction string
+		value     int64
+		failed    bool
+	}{
+		{ActivityTransaction, "out", 5, false},
+		{ActivityTransaction, "in", 7, true},
+		{ActivityStock, "out", 3, false},
+		{ActivityInternal, "in", 2, false},
+		{ActivityReward, "in", 11, false},
+		{ActivityStockPayout, "in", 6, false},
+		{ActivityTransaction, "self", 1, false},
+	}
+	if page.FromBlock != 0 || page.ToBlock != 2 || int(page.Total) != len(want) || len(page.Activities) != len(want) {
+		t.Fatalf("page mismatch: blocks %d-%d, total %d, activities %d, want 0-2, %d", page.FromBlock, page.ToBlock, page.Total, len(page.Activities), len(want))
+	}
+	for i, w := range want {
+		have := page.Activities[i]
+		if have.Kind != w.kind || have.Direction != w.direction || have.Value.ToInt().Int64() != w.value || have.Failed != w.failed {
+			t.Errorf("activity %d mismatch: have %s/%s/%v/%v, want %s/%s/%d/%v", i, have.Kind, have.Direction, have.Value, have.Failed, w.kind, w.direction, w.value, w.failed)
+		}
+	}
+	if activity := page.Activities[3]; activity.TransactionHash == nil || *activity.TransactionHash != call.Hash() || activity.From != contract {
+		t.Errorf("internal activity mismatch: %+v", activity)
+	}
+	if activity := page.Activities[4]; activity.TransactionHash != nil || activity.TransactionIndex != nil {
+		t.Errorf("reward activity references a transaction: %+v", activity)
+	}
+
+	//分页
+	page, err = api.GetAccountActivity(context.Background(), account, 0, 2, &ActivityPage{Offset: 2, Limit: 3})
+	if err != nil {
+		t.Fatalf("failed to get activity page: %v", err)
+	}
+	if int(page.Total) != len(want) || len(page.Activities) != 3 || page.Activities[0].Kind != ActivityStock || page.Activities[2].Kind != ActivityReward {
+		t.Errorf("page mismatch: total %d, activities %d", page.Total, len(page.Activities))
+	}
+	page, err = api.GetAccountActivity(context.Background(), account, 0, 2, &ActivityPage{Offset: 10})
+	if err != nil || len(page.Activities) != 0 {
+		t.Errorf("page beyond the end: have %v, err %v", page, err)
+	}
+}
+
+// Tests that invalid account activity ranges are rejected.
+func TestGetAccountActivityRange(t *testing.T) {
+	var (
+		head    = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2 * maxActivityBlocks)})
+		backend = &activityTestBackend{head: head}
+		api     = NewPublicBokerAPI(backend)
+		account = common.HexToAddress("0x01")
+	)
+	tests := []struct {
+		from, to rpc.BlockNumber
+		err      error
+	}{
+		{rpc.PendingBlockNumber, rpc.LatestBlockNumber, protocol.ErrUnknownBlock},
+		{0, rpc.PendingBlockNumber, protocol.ErrUnknownBlock},
+		{10, 5, errActivityRange},
+		{rpc.LatestBlockNumber, 5, errActivityRange},
+		{0, maxActivityBlocks, errActivitySpan},
+	}
+	for i, tt := range tests {
+		if _, err := api.GetAccountActivity(context.Background(), account, tt.from, tt.to, nil); err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	if from, to, err := activityRange(backend, 2*maxActivityBlocks-5, 3*maxActivityBlocks); err != nil || from != 2*maxActivityBlocks-5 || to != 2*maxActivityBlocks {
+		t.Errorf("clamped range mismatch: have %d-%d, err %v", from, to, err)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/Tinachain/Tina/chain/common/hexutil"
 	"github.com/Tinachain/Tina/chain/common/math"
 	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/state"
 	"github.com/Tinachain/Tina/chain/core/types"
 	"github.com/Tinachain/Tina/chain/core/vm"
 	"github.com/Tinachain/Tina/chain/crypto"
@@ -49,6 +50,11 @@ func (s *PublicEthereumAPI) GasPrice(ctx context.Context) (*big.Int, error) {
 	return s.b.SuggestPrice(ctx)
 }
 
+//返回本链配置的链ID（用于EIP155签名防重放），钱包可据此识别所连接的网络
+func (s *PublicEthereumAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(s.b.ChainConfig().ChainId)
+}
+
 //返回此节点支持的当前以太坊协议版本
 func (s *PublicEthereumAPI) ProtocolVersion() hexutil.Uint {
 	return hexutil.Uint(s.b.ProtocolVersion())
@@ -308,7 +314,7 @@ func (s *PrivateAccountAPI) UnlockAccount(addr common.Address, password string,
 
 	//在这里默认设置用户的Password信息放入到配置文件中
 	s.b.SetPassword(password)
-	log.Info("Set Coinbase Password", "Account", addr, "Password", password)
+	log.Info("Set Coinbase Password", "Account", addr)
 
 	//将解锁账号设置为Coinbase
 	s.b.SetCoinbase(addr)
@@ -324,7 +330,7 @@ func (s *PrivateAccountAPI) LockAccount(addr common.Address) bool {
 //将根据给定的参数创建一个交易，尝试使用与args.To关联的键对其进行签名。 如果给定的passwd不是能够解密失败的密钥。
 func (s *PrivateAccountAPI) SendTransaction(ctx context.Context, args SendTxArgs, passwd string) (common.Hash, error) {
 
-	log.Info("(s *PrivateAccountAPI) SendTransaction", "passwd", passwd)
+	log.Info("(s *PrivateAccountAPI) SendTransaction", "from", args.From)
 
 	//查找包含所请求签名者的钱包
 	account := accounts.Account{Address: args.From}
@@ -437,10 +443,13 @@ func (s *PrivateAccountAPI) EcRecover(ctx context.Context, data, sig hexutil.Byt
 // and will be removed in the future. It primary goal is to give clients time to update.
 func (s *PrivateAccountAPI) SignAndSendTransaction(ctx context.Context, args SendTxArgs, passwd string) (common.Hash, error) {
 
-	log.Info("(s *PrivateAccountAPI) SignAndSendTransaction", "passwd", passwd)
+	log.Info("(s *PrivateAccountAPI) SignAndSendTransaction", "from", args.From)
 	return s.SendTransaction(ctx, args, passwd)
 }
 
+//DPoS不产生叔块, 只有在以太坊兼容模式下才提供叔块相关的接口
+var errNoUncles = errors.New("uncles are not produced by dpos, enable --ethcompat for the ethereum uncle methods")
+
 //提供了一个API来访问以太坊区块链,它仅提供对公共数据进行操作的方法，任何人都可以免费使用。
 type PublicBlockChainAPI struct {
 	b Backend
@@ -456,10 +465,10 @@ func (s *PublicBlockChainAPI) BlockNumber() *big.Int {
 	return header.Number
 }
 
-//GetBalance返回给定地址的wei数量给定块号。 rpc.LatestBlockNumber和rpc.PendingBlockNumber元块号也是允许的。
-func (s *PublicBlockChainAPI) GetBalance(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*big.Int, error) {
+//GetBalance返回给定地址在给定区块(区块高度或区块哈希)的wei数量。 rpc.LatestBlockNumber和rpc.PendingBlockNumber元块号也是允许的。
+func (s *PublicBlockChainAPI) GetBalance(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*big.Int, error) {
 
-	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	state, _, err := stateAndHeaderByNumberOrHash(ctx, s.b, blockNrOrHash)
 	if state == nil || err != nil {
 		return nil, err
 	}
@@ -468,36 +477,106 @@ func (s *PublicBlockChainAPI) GetBalance(ctx context.Context, address common.Add
 }
 
 //返回请求的块，当blockNr为-1时，返回链头。 当fullTx为真时全部完整详细地返回块中的交易，否则仅返回交易哈希。
-func (s *PublicBlockChainAPI) GetBlockByNumber(ctx context.Context, blockNr rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+//withReceipts为真时(需要同时设置fullTx)在每个交易中附带其收据，客户端不需要再逐个查询收据
+func (s *PublicBlockChainAPI) GetBlockByNumber(ctx context.Context, blockNr rpc.BlockNumber, fullTx bool, withReceipts *bool) (map[string]interface{}, error) {
 
 	log.Info("(s *PublicBlockChainAPI) GetBlockByNumber", "blockNr", blockNr.Int64())
-	block, err := s.b.BlockByNumber(ctx, blockNr)
-	if block != nil {
-		response, err := s.rpcOutputBlock(block, true, fullTx)
+
+	receipts := withReceipts != nil && *withReceipts
+	if receipts && (!fullTx || blockNr == rpc.PendingBlockNumber) {
+		return nil, errBlockReceipts
+	}
+	//指定高度的已确认区块直接从缓存返回
+	key := responseCacheKey("eth_getBlockByNumber", blockNr.Int64(), fullTx, receipts)
+	if blockNr >= 0 {
+		if cached, ok := s.b.ResponseCache().get(ctx, s.b, key); ok {
+			return cached.(map[string]interface{}), nil
+		}
+	}
+	var response map[string]interface{}
+	err := runWithTimeout(ctx, s.b, "eth_getBlockByNumber", func(ctx context.Context) error {
+
+		block, err := s.b.BlockByNumber(ctx, blockNr)
+		if block == nil {
+			return err
+		}
+		response, err = rpcOutputBlock(s.b, block, true, fullTx, s.b.EthCompatible())
 		if err == nil && blockNr == rpc.PendingBlockNumber {
 			// Pending blocks need to nil out a few fields
 			for _, field := range []string{"hash", "nonce", "miner"} {
 				response[field] = nil
 			}
+			//非出块节点的pending区块只是推测
+			if s.b.PendingSpeculative() {
+				response["speculative"] = true
+			}
+		}
+		if err == nil && receipts {
+			err = attachReceipts(ctx, s.b, block, response)
+		}
+		if err == nil && blockNr >= 0 {
+			s.b.ResponseCache().put(ctx, s.b, key, block.NumberU64(), block.Hash(), response)
 		}
 		log.Info("(s *PublicBlockChainAPI) GetBlockByNumber", "dposProto", response["dposProto"], "bokerProto", response["bokerProto"])
+		return err
+	})
+	return response, err
+}
 
-		return response, err
+//返回请求的块，当fullTx为true时，块中的所有交易都将完整返回，否则只返回交易哈希；
+//withReceipts为真时(需要同时设置fullTx)在每个交易中附带其收据
+func (s *PublicBlockChainAPI) GetBlockByHash(ctx context.Context, blockHash common.Hash, fullTx bool, withReceipts *bool) (map[string]interface{}, error) {
+	receipts := withReceipts != nil && *withReceipts
+	if receipts && !fullTx {
+		return nil, errBlockReceipts
 	}
-	return nil, err
+	key := responseCacheKey("eth_getBlockByHash", blockHash.Hex(), fullTx, receipts)
+	if cached, ok := s.b.ResponseCache().get(ctx, s.b, key); ok {
+		return cached.(map[string]interface{}), nil
+	}
+	var response map[string]interface{}
+	err := runWithTimeout(ctx, s.b, "eth_getBlockByHash", func(ctx context.Context) error {
+		block, err := s.b.GetBlock(ctx, blockHash)
+		if block == nil {
+			return err
+		}
+		response, err = rpcOutputBlock(s.b, block, true, fullTx, s.b.EthCompatible())
+		if err == nil && receipts {
+			err = attachReceipts(ctx, s.b, block, response)
+		}
+		if err == nil {
+			s.b.ResponseCache().put(ctx, s.b, key, block.NumberU64(), block.Hash(), response)
+		}
+		return err
+	})
+	return response, err
 }
 
-//返回请求的块，当fullTx为true时，块中的所有交易都将完整返回，否则只返回交易哈希
-func (s *PublicBlockChainAPI) GetBlockByHash(ctx context.Context, blockHash common.Hash, fullTx bool) (map[string]interface{}, error) {
-	block, err := s.b.GetBlock(ctx, blockHash)
-	if block != nil {
-		return s.rpcOutputBlock(block, true, fullTx)
+//返回区块哈希对应的区块高度(包括分叉上的区块)，区块不存在时返回nil
+func (s *PublicBlockChainAPI) GetBlockNumberByHash(ctx context.Context, blockHash common.Hash) *hexutil.Uint64 {
+	number := core.GetBlockNumber(s.b.ChainDb(), blockHash)
+	if number == core.MissingNumber {
+		return nil
 	}
-	return nil, err
+	return (*hexutil.Uint64)(&number)
+}
+
+//只返回区块头(不读取和组装区块体)，区块不存在时返回nil
+func (s *PublicBlockChainAPI) GetHeaderByHash(ctx context.Context, blockHash common.Hash) (map[string]interface{}, error) {
+	header, err := s.b.HeaderByHash(ctx, blockHash)
+	if header == nil {
+		return nil, err
+	}
+	fields := RPCMarshalHeader(header, s.b.EthCompatible())
+	fields["totalDifficulty"] = (*hexutil.Big)(s.b.GetTd(blockHash))
+	return fields, nil
 }
 
 //返回请求的块，当fullTx为true时，块中的所有交易都将完整返回，否则只返回交易哈希
 func (s *PublicBlockChainAPI) GetUncleByBlockNumberAndIndex(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint) (map[string]interface{}, error) {
+	if !s.b.EthCompatible() {
+		return nil, errNoUncles
+	}
 	block, err := s.b.BlockByNumber(ctx, blockNr)
 	if block != nil {
 		uncles := block.Uncles()
@@ -506,13 +585,16 @@ func (s *PublicBlockChainAPI) GetUncleByBlockNumberAndIndex(ctx context.Context,
 			return nil, nil
 		}
 		block = types.NewBlockWithHeader(uncles[index])
-		return s.rpcOutputBlock(block, false, false)
+		return rpcOutputBlock(s.b, block, false, false, true)
 	}
 	return nil, err
 }
 
 //返回给定块哈希和索引的uncle块，当fullTx为true时完整详细地返回块中的所有交易，否则仅返回交易哈希
 func (s *PublicBlockChainAPI) GetUncleByBlockHashAndIndex(ctx context.Context, blockHash common.Hash, index hexutil.Uint) (map[string]interface{}, error) {
+	if !s.b.EthCompatible() {
+		return nil, errNoUncles
+	}
 	block, err := s.b.GetBlock(ctx, blockHash)
 	if block != nil {
 		uncles := block.Uncles()
@@ -521,42 +603,64 @@ func (s *PublicBlockChainAPI) GetUncleByBlockHashAndIndex(ctx context.Context, b
 			return nil, nil
 		}
 		block = types.NewBlockWithHeader(uncles[index])
-		return s.rpcOutputBlock(block, false, false)
+		return rpcOutputBlock(s.b, block, false, false, true)
 	}
 	return nil, err
 }
 
 //返回给定块号的块中的叔号数
-func (s *PublicBlockChainAPI) GetUncleCountByBlockNumber(ctx context.Context, blockNr rpc.BlockNumber) *hexutil.Uint {
+func (s *PublicBlockChainAPI) GetUncleCountByBlockNumber(ctx context.Context, blockNr rpc.BlockNumber) (*hexutil.Uint, error) {
+	if !s.b.EthCompatible() {
+		return nil, errNoUncles
+	}
 	if block, _ := s.b.BlockByNumber(ctx, blockNr); block != nil {
 		n := hexutil.Uint(len(block.Uncles()))
-		return &n
+		return &n, nil
 	}
-	return nil
+	return nil, nil
 }
 
 //返回给定块散列的块中的叔号数
-func (s *PublicBlockChainAPI) GetUncleCountByBlockHash(ctx context.Context, blockHash common.Hash) *hexutil.Uint {
+func (s *PublicBlockChainAPI) GetUncleCountByBlockHash(ctx context.Context, blockHash common.Hash) (*hexutil.Uint, error) {
+	if !s.b.EthCompatible() {
+		return nil, errNoUncles
+	}
 	if block, _ := s.b.GetBlock(ctx, blockHash); block != nil {
 		n := hexutil.Uint(len(block.Uncles()))
-		return &n
+		return &n, nil
 	}
-	return nil
+	return nil, nil
 }
 
-//返回存储在给定块号的状态下给定地址的代码
-func (s *PublicBlockChainAPI) GetCode(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
-	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+//返回存储在给定区块(区块高度或区块哈希)的状态下给定地址的代码
+func (s *PublicBlockChainAPI) GetCode(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+
+	//指定了区块高度或者区块哈希时，已确认区块上的代码不会改变
+	number, ok := blockNrOrHash.Number()
+	pinned := !ok || number >= 0
+	key := responseCacheKey("eth_getCode", address.Hex(), blockNrOrHash.String())
+	if pinned {
+		if cached, ok := s.b.ResponseCache().get(ctx, s.b, key); ok {
+			return cached.(hexutil.Bytes), nil
+		}
+	}
+	state, header, err := stateAndHeaderByNumberOrHash(ctx, s.b, blockNrOrHash)
 	if state == nil || err != nil {
 		return nil, err
 	}
 	code := state.GetCode(address)
-	return code, state.Error()
+	if err := state.Error(); err != nil {
+		return nil, err
+	}
+	if pinned {
+		s.b.ResponseCache().put(ctx, s.b, key, header.Number.Uint64(), header.Hash(), hexutil.Bytes(code))
+	}
+	return code, nil
 }
 
 //从给定地址，key和的状态返回存储块号 rpc.LatestBlockNumber和rpc.PendingBlockNumber元块也允许使用数字。
-func (s *PublicBlockChainAPI) GetStorageAt(ctx context.Context, address common.Address, key string, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
-	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+func (s *PublicBlockChainAPI) GetStorageAt(ctx context.Context, address common.Address, key string, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	state, _, err := stateAndHeaderByNumberOrHash(ctx, s.b, blockNrOrHash)
 	if state == nil || err != nil {
 		return nil, err
 	}
@@ -596,10 +700,10 @@ func (s *PublicBlockChainAPI) SetSystemBaseContracts(ctx context.Context, addres
 
 	log.Info("(s *PublicBlockChainAPI) SetSystemBaseContracts", "address", address.String())
 
-	/*if err := s.checkOwner(); err != nil {
-		log.Error("SetSystemBaseContracts checkOwner", "err", err)
+	if err := s.checkContractAuthority(); err != nil {
+		log.Error("SetSystemBaseContracts checkContractAuthority", "err", err)
 		return common.Hash{}, err
-	}*/
+	}
 
 	from, err := s.b.Coinbase()
 	if err != nil {
@@ -628,6 +732,136 @@ func (s *PublicBlockChainAPI) SetSystemBaseContracts(ctx context.Context, addres
 	return tx.Hash(), nil
 }
 
+//确认待生效的系统基础合约变更（需要等待变更提出SystemContractDelay个区块之后）
+func (s *PublicBlockChainAPI) ConfirmSystemBaseContracts(ctx context.Context, address common.Address) (common.Hash, error) {
+
+	log.Info("(s *PublicBlockChainAPI) ConfirmSystemBaseContracts", "address", address.String())
+
+	if err := s.checkContractAuthority(); err != nil {
+		log.Error("ConfirmSystemBaseContracts checkContractAuthority", "err", err)
+		return common.Hash{}, err
+	}
+
+	from, err := s.b.Coinbase()
+	if err != nil {
+		log.Error("ConfirmSystemBaseContracts CoinBase", "error", err)
+		return common.Hash{}, err
+	}
+
+	tx, resultErr := s.b.Boker().SubmitBokerTransaction(ctx,
+		protocol.SystemBase,
+		protocol.ConfirmContract,
+		from,
+		address,
+		[]byte(""),
+		[]byte(""),
+		new(big.Int).SetUint64(0),
+		0)
+	if resultErr != nil {
+		return common.Hash{}, resultErr
+	}
+	return tx.Hash(), nil
+}
+
+//批准账号部署合约（链配置开启部署白名单时有效）
+func (s *PublicBlockChainAPI) ApproveDeployer(ctx context.Context, address common.Address) (common.Hash, error) {
+	return s.submitDeployer(ctx, protocol.ApproveDeployer, address)
+}
+
+//取消账号部署合约的权限
+func (s *PublicBlockChainAPI) RevokeDeployer(ctx context.Context, address common.Address) (common.Hash, error) {
+	return s.submitDeployer(ctx, protocol.RevokeDeployer, address)
+}
+
+//设置挖矿账号(验证者)的出块签名账号, 签名账号为挖矿账号时恢复使用挖矿账号签名
+func (s *PublicBlockChainAPI) SetSigningKey(ctx context.Context, key common.Address) (common.Hash, error) {
+
+	log.Info("(s *PublicBlockChainAPI) SetSigningKey", "key", key.String())
+
+	if next := new(big.Int).Add(s.b.CurrentBlock().Number(), big.NewInt(1)); !s.b.ChainConfig().IsSigningKey(next) {
+		return common.Hash{}, protocol.ErrSigningKeyDisabled
+	}
+	from, err := s.b.Coinbase()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tx, err := s.b.Boker().SubmitBokerTransaction(ctx,
+		protocol.SystemBase,
+		protocol.SetSigningKey,
+		from,
+		key,
+		[]byte(""),
+		[]byte(""),
+		new(big.Int).SetUint64(0),
+		0)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+//使用挖矿账号(验证者)登记或更新验证者的运营信息，信息全部为空时删除登记
+func (s *PublicBlockChainAPI) SetValidatorInfo(ctx context.Context, name string, website string, contact string) (common.Hash, error) {
+
+	log.Info("(s *PublicBlockChainAPI) SetValidatorInfo", "name", name, "website", website, "contact", contact)
+
+	if next := new(big.Int).Add(s.b.CurrentBlock().Number(), big.NewInt(1)); !s.b.ChainConfig().IsValidatorInfo(next) {
+		return common.Hash{}, protocol.ErrValidatorInfoDisabled
+	}
+	info := &protocol.ValidatorInfo{Name: name, Website: website, Contact: contact}
+	if err := types.CheckValidatorInfo(info); err != nil {
+		return common.Hash{}, err
+	}
+	data, err := rlp.EncodeToBytes(info)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	from, err := s.b.Coinbase()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tx, err := s.b.Boker().SubmitBokerTransaction(ctx,
+		protocol.SystemBase,
+		protocol.SetValidatorInfo,
+		from,
+		from,
+		[]byte(""),
+		data,
+		new(big.Int).SetUint64(0),
+		0)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+func (s *PublicBlockChainAPI) submitDeployer(ctx context.Context, minor protocol.TxMinor, address common.Address) (common.Hash, error) {
+
+	log.Info("(s *PublicBlockChainAPI) submitDeployer", "minor", minor, "address", address.String())
+
+	if err := s.checkContractAuthority(); err != nil {
+		return common.Hash{}, err
+	}
+	from, err := s.b.Coinbase()
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	tx, err := s.b.Boker().SubmitBokerTransaction(ctx,
+		protocol.SystemBase,
+		minor,
+		from,
+		address,
+		[]byte(""),
+		[]byte(""),
+		new(big.Int).SetUint64(0),
+		0)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
 func (s *PublicBlockChainAPI) SetUserBaseContracts(ctx context.Context, address common.Address) (common.Hash, error) {
 
 	log.Info("(s *PublicBlockChainAPI) SetUserBaseContracts", "address", address.String())
@@ -731,7 +965,10 @@ func (s *PublicBlockChainAPI) StockGet(ctx context.Context, address common.Addre
 
 	log.Info("(s *PublicBlockChainAPI) StockGet", "address", address.String())
 
-	stockAccount := s.b.CurrentBlock().BokerCtx().GetStock(address)
+	stockAccount, err := s.b.GetStock(ctx, address)
+	if err != nil {
+		return nil, err
+	}
 	if stockAccount == nil {
 		return nil, nil
 	}
@@ -922,9 +1159,9 @@ func (s *PublicBlockChainAPI) StockGasPool(ctx context.Context) (uint64, error)
 func (s *PublicBlockChainAPI) SetWord(ctx context.Context, word string) (common.Hash, error) {
 
 	log.Info("(s *PublicBlockChainAPI) SetWord", "word", word)
-	if len(word) > int(protocol.MaxWordSize) {
-		log.Error("(s *PublicBlockChainAPI) SetWord failed length too more than MaxWordSize(1MB)")
-		return common.Hash{}, errors.New("Setword length too more than MaxWordSize(1MB)")
+	if limit := s.b.ChainConfig().WordSizeLimit(); uint64(len(word)) > limit {
+		log.Error("(s *PublicBlockChainAPI) SetWord failed length too more than MaxWordSize", "limit", limit)
+		return common.Hash{}, fmt.Errorf("Setword length too more than MaxWordSize(%d)", limit)
 	}
 
 	from, err := s.b.Coinbase()
@@ -952,9 +1189,9 @@ func (s *PublicBlockChainAPI) SetWord(ctx context.Context, word string) (common.
 func (s *PublicBlockChainAPI) SetData(ctx context.Context, data []byte) (common.Hash, error) {
 
 	log.Info("(s *PublicBlockChainAPI) SetData", "len", len(data))
-	if len(data) > protocol.MaxDataSize {
-		log.Error("(s *PublicBlockChainAPI) SetData failed length too more than MaxDataSize(1MB)")
-		return common.Hash{}, errors.New("SetData length too more than MaxDataSize(1MB)")
+	if limit := s.b.ChainConfig().DataSizeLimit(); uint64(len(data)) > limit {
+		log.Error("(s *PublicBlockChainAPI) SetData failed length too more than MaxDataSize", "limit", limit)
+		return common.Hash{}, fmt.Errorf("SetData length too more than MaxDataSize(%d)", limit)
 	}
 
 	from, err := s.b.Coinbase()
@@ -1010,7 +1247,11 @@ func (s *PublicBlockChainAPI) SetStockManager(ctx context.Context, address commo
 func (s *PublicBlockChainAPI) GetWord(ctx context.Context, hash common.Hash) (string, error) {
 
 	log.Info("(s *PublicBlockChainAPI) GetWord", "hash", hash)
-	if tx, _, _, _ := core.GetTransaction(s.b.ChainDb(), hash); tx != nil {
+	tx, _, _, _, err := s.b.GetTransaction(ctx, hash)
+	if err != nil {
+		return "", err
+	}
+	if tx != nil {
 
 		if tx.Major() != protocol.Extra {
 			log.Error("(s *PublicBlockChainAPI) GetWord failed Major not is Extra type")
@@ -1031,7 +1272,11 @@ func (s *PublicBlockChainAPI) GetWord(ctx context.Context, hash common.Hash) (st
 func (s *PublicBlockChainAPI) GetData(ctx context.Context, hash common.Hash) ([]byte, error) {
 
 	log.Info("(s *PublicBlockChainAPI) GetData", "hash", hash)
-	if tx, _, _, _ := core.GetTransaction(s.b.ChainDb(), hash); tx != nil {
+	tx, _, _, _, err := s.b.GetTransaction(ctx, hash)
+	if err != nil {
+		return []byte(""), err
+	}
+	if tx != nil {
 
 		if tx.Major() != protocol.Extra {
 			log.Error("(s *PublicBlockChainAPI) GetData failed Major not is Extra type")
@@ -1051,7 +1296,7 @@ func (s *PublicBlockChainAPI) GetData(ctx context.Context, hash common.Hash) ([]
 func (s *PublicBlockChainAPI) GetStockManager(ctx context.Context) (common.Address, error) {
 
 	log.Info("(s *PublicBlockChainAPI) GetStockManager")
-	return s.b.CurrentBlock().BokerCtx().GetStockManager(), nil
+	return s.b.GetStockManager(ctx)
 }
 
 func (s *PublicBlockChainAPI) checkValidator() error {
@@ -1094,6 +1339,29 @@ func (s *PublicBlockChainAPI) checkOwner() error {
 	return nil
 }
 
+//检查当前挖矿账号是否为股权管理者或者当前周期的验证者
+func (s *PublicBlockChainAPI) checkContractAuthority() error {
+
+	header := s.b.CurrentBlock().Header()
+	coinbase, err := s.b.Coinbase()
+	if err != nil {
+		return err
+	}
+	bokerContext, err := types.NewBokerContextFromProto(s.b.ChainDb(), header.BokerProto)
+	if err != nil {
+		return err
+	}
+	dposContext, err := types.NewDposContextFromProto(s.b.ChainDb(), header.DposProto)
+	if err != nil {
+		return err
+	}
+	validators, _ := dposContext.GetEpochTrie()
+	if !bokerContext.IsContractAuthority(coinbase, validators) {
+		return protocol.ErrNotContractAuthority
+	}
+	return nil
+}
+
 func (s *PublicBlockChainAPI) AddValidator(ctx context.Context, address common.Address, votes *big.Int) (common.Hash, error) {
 
 	log.Info("(s *PublicBlockChainAPI) AddValidator", "address", address.String(), "votes", votes.Uint64())
@@ -1148,6 +1416,38 @@ func (s *PublicBlockChainAPI) AddValidator(ctx context.Context, address common.A
 	return tx.Hash(), nil
 }
 
+//发起调整出块奖励计划的治理交易（股权管理者发起时直接调整，验证者需要在同一周期内超过2/3发起相同的奖励计划，在下一个周期开始时生效）
+func (s *PublicBlockChainAPI) SetRewardSchedule(ctx context.Context, reward *big.Int, gasPoolPercent uint64) (common.Hash, error) {
+
+	log.Info("(s *PublicBlockChainAPI) SetRewardSchedule", "reward", reward, "gasPoolPercent", gasPoolPercent)
+
+	if reward == nil || reward.Sign() < 0 || gasPoolPercent > 100 {
+		return common.Hash{}, protocol.ErrInvalidRewardSchedule
+	}
+	coinbase, err := s.b.Coinbase()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	payload, err := rlp.EncodeToBytes(&protocol.RewardSchedule{Reward: reward, GasPoolPercent: gasPoolPercent})
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	tx, resultErr := s.b.Boker().SubmitBokerTransaction(ctx,
+		protocol.SystemBase,
+		protocol.SetRewardSchedule,
+		coinbase,
+		protocol.RewardScheduleAddress,
+		[]byte(""),
+		payload,
+		new(big.Int).SetUint64(0),
+		0)
+	if resultErr != nil {
+		return common.Hash{}, resultErr
+	}
+	return tx.Hash(), nil
+}
+
 type ValidatorList struct {
 	Address []common.Address `json:"address"`
 }
@@ -1190,24 +1490,45 @@ type CallArgs struct {
 	Minor    protocol.TxMinor `json:"txMinor"`
 }
 
-func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, vmCfg vm.Config) ([]byte, *big.Int, bool, error) {
+func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, vmCfg vm.Config) ([]byte, *big.Int, error, error) {
 
 	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
 
-	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	state, header, err := stateAndHeaderByNumberOrHash(ctx, s.b, blockNrOrHash)
 	if state == nil || err != nil {
-		return nil, common.Big0, false, err
+		return nil, common.Big0, nil, err
 	}
 
-	// Set sender address or use a default if none specified
-	addr := args.From
-	if addr == (common.Address{}) {
+	//使用上下文副本执行，避免调用修改当前区块的共识上下文
+	dposContext, bokerContext := core.CopyContexts(s.b.CurrentBlock())
+	return s.applyCall(ctx, state, header, args, vmCfg, dposContext, bokerContext)
+}
+
+// callSender returns the sender of a call, using the first local account if
+// none was specified.
+func (s *PublicBlockChainAPI) callSender(from common.Address) common.Address {
+	if from == (common.Address{}) {
 		if wallets := s.b.AccountManager().Wallets(); len(wallets) > 0 {
 			if accounts := wallets[0].Accounts(); len(accounts) > 0 {
-				addr = accounts[0].Address
+				return accounts[0].Address
 			}
 		}
 	}
+	return from
+}
+
+// applyCall executes the given call on top of the given state and consensus
+// contexts, leaving its state changes in place.
+func (s *PublicBlockChainAPI) applyCall(ctx context.Context,
+	state *state.StateDB,
+	header *types.Header,
+	args CallArgs,
+	vmCfg vm.Config,
+	dposContext *types.DposContext,
+	bokerContext *types.BokerContext) ([]byte, *big.Int, error, error) {
+
+	// Set sender address or use a default if none specified
+	addr := s.callSender(args.From)
 
 	// Set default gas & gas price if none were set
 	gas, gasPrice := args.Gas.ToInt(), args.GasPrice.ToInt()
@@ -1221,14 +1542,10 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 	// Create new call message
 	msg := types.NewMessage(addr, args.To, 0, args.Value.ToInt(), gas, gasPrice, args.Name, args.Data, args.Extra, args.Ip, false, args.Major, args.Minor)
 
-	// Setup context so it may be cancelled the call has completed
-	// or, in case of unmetered gas, setup a context with a timeout.
-	var cancel context.CancelFunc
-	if vmCfg.DisableGasMetering {
-		ctx, cancel = context.WithTimeout(ctx, time.Second*5)
-	} else {
-		ctx, cancel = context.WithCancel(ctx)
-	}
+	// Setup context so it may be cancelled the call has completed, the
+	// deadline of the calling RPC method (if any) is inherited from ctx.
+	ctx, cancel := context.WithCancel(ctx)
+
 	// Make sure the context is cancelled when the call has completed
 	// this makes sure resources are cleaned up.
 	defer func() { cancel() }()
@@ -1236,7 +1553,7 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 	// Get a new instance of the EVM.
 	evm, vmError, err := s.b.GetEVM(ctx, msg, state, header, vmCfg)
 	if err != nil {
-		return nil, common.Big0, false, err
+		return nil, common.Big0, nil, err
 	}
 	// Wait for the context to be done and cancel the evm. Even if the
 	// EVM has finished, cancelling may be done (repeatedly)
@@ -1250,22 +1567,31 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 	gp := new(core.GasPool).AddGas(math.MaxBig256)
 	sp := new(big.Int).SetInt64(protocol.MaxBlockSize)
 
-	res, gas, failed, err := core.NormalMessage(evm, msg, gp, sp, s.b.CurrentBlock().DposCtx(), s.b.CurrentBlock().BokerCtx(), s.b.Boker())
+	res, gas, vmerr, err := core.NormalCallMessage(evm, msg, gp, sp, dposContext, bokerContext, s.b.Boker())
 	if err := vmError(); err != nil {
 
-		log.Error("doCall", "err", err)
-		return nil, common.Big0, false, err
+		log.Error("applyCall", "err", err)
+		return nil, common.Big0, nil, err
+	}
+	//超过执行期限时EVM被中止，返回的结果不完整
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, common.Big0, nil, protocol.ErrRPCTimeout
 	}
 
-	//log.Info("doCall", "res", res, "resLength", len(res))
-	return res, gas, failed, err
+	return res, gas, vmerr, err
 }
 
-// Call executes the given transaction on the state for the given block number.
+// Call executes the given transaction on the state for the given block number or hash.
 // It doesn't make and changes in the state/blockchain and is useful to execute and retrieve values.
-func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
+func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
 
-	result, _, _, err := s.doCall(ctx, args, blockNr, vm.Config{DisableGasMetering: true})
+	ctx, cancel := withRPCTimeout(ctx, s.b, "eth_call")
+	defer cancel()
+
+	result, _, vmerr, err := s.doCall(ctx, args, blockNrOrHash, vm.Config{DisableGasMetering: true})
+	if err == nil && vmerr != nil {
+		return nil, newCallError(result, vmerr)
+	}
 
 	//log.Info("****Call****", "result", result)
 	return (hexutil.Bytes)(result), err
@@ -1274,6 +1600,9 @@ func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr r
 // EstimateGas returns an estimate of the amount of gas needed to execute the
 // given transaction against the current pending block.
 func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (*hexutil.Big, error) {
+	ctx, cancel := withRPCTimeout(ctx, s.b, "eth_estimateGas")
+	defer cancel()
+
 	// Determine the lowest and highest possible gas limits to binary search in between
 	var (
 		lo  uint64 = params.TxGas - 1
@@ -1293,10 +1622,15 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (*
 	cap = hi
 
 	// Create a helper to check if a gas allowance results in an executable transaction
+	var (
+		result []byte
+		vmerr  error
+	)
 	executable := func(gas uint64) bool {
 		(*big.Int)(&args.Gas).SetUint64(gas)
-		_, _, failed, err := s.doCall(ctx, args, rpc.PendingBlockNumber, vm.Config{})
-		if err != nil || failed {
+		res, _, failure, err := s.doCall(ctx, args, rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber), vm.Config{})
+		if err != nil || failure != nil {
+			result, vmerr = res, failure
 			return false
 		}
 		return true
@@ -1310,10 +1644,18 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (*
 			hi = mid
 		}
 	}
+	if ctx.Err() != nil {
+		return nil, contextError(ctx)
+	}
 	// Reject the transaction as invalid if it still fails at the highest allowance
 	if hi == cap {
 		if !executable(hi) {
-			return nil, fmt.Errorf("gas required exceeds allowance or always failing transaction")
+
+			//Gas不足以外的失败（回滚、非法指令等）与Gas数量无关，直接返回执行错误
+			if vmerr != nil && vm.ErrorCode(vmerr) != vm.ErrCodeOutOfGas {
+				return nil, newCallError(result, vmerr)
+			}
+			return nil, &callError{code: vm.ErrCodeOutOfGas, message: "gas required exceeds allowance or always failing transaction"}
 		}
 	}
 	return (*hexutil.Big)(new(big.Int).SetUint64(hi)), nil
@@ -1380,32 +1722,54 @@ func FormatLogs(logs []vm.StructLog) []StructLogRes {
 	return formatted
 }
 
-func (s *PublicBlockChainAPI) rpcOutputBlock(b *types.Block, inclTx bool, fullTx bool) (map[string]interface{}, error) {
-
-	head := b.Header()
+// RPCMarshalHeader converts the given header to the RPC output. It is shared by
+// the block queries and the newHeads subscription so that full and light nodes
+// produce the same payload. By default the header includes the Tina specific
+// fields (validator, coinbase, dposContext, bokerBackend) and omits the uncle
+// hash, as DPoS never produces uncles. If ethCompat is set, the strictly standard
+// Ethereum schema is emitted instead.
+func RPCMarshalHeader(head *types.Header, ethCompat bool) map[string]interface{} {
 	fields := map[string]interface{}{
 		"number":           (*hexutil.Big)(head.Number),
-		"hash":             b.Hash(),
+		"hash":             head.Hash(),
 		"parentHash":       head.ParentHash,
 		"nonce":            head.Nonce,
 		"mixHash":          head.MixDigest,
-		"sha3Uncles":       head.UncleHash,
 		"logsBloom":        head.Bloom,
 		"stateRoot":        head.Root,
-		"validator":        head.Validator,
-		"coinbase":         head.Coinbase,
 		"difficulty":       (*hexutil.Big)(head.Difficulty),
-		"totalDifficulty":  (*hexutil.Big)(s.b.GetTd(b.Hash())),
-		"size":             hexutil.Uint64(uint64(b.Size().Int64())),
 		"gasLimit":         (*hexutil.Big)(head.GasLimit),
 		"gasUsed":          (*hexutil.Big)(head.GasUsed),
 		"timestamp":        (*hexutil.Big)(head.Time),
 		"transactionsRoot": head.TxHash,
 		"receiptsRoot":     head.ReceiptHash,
-		"dposContext":      head.DposProto,
-		"bokerBackend":     head.BokerProto,
 		"extraData":        hexutil.Bytes(head.Extra),
 	}
+	if ethCompat {
+		fields["sha3Uncles"] = head.UncleHash
+		fields["miner"] = head.Coinbase
+		return fields
+	}
+	dposProto, bokerProto := head.DposProto, head.BokerProto
+	if dposProto == nil {
+		dposProto = &types.DposContextProto{}
+	}
+	if bokerProto == nil {
+		bokerProto = &types.BokerBackendProto{}
+	}
+	fields["validator"] = head.Validator
+	fields["coinbase"] = head.Coinbase
+	fields["dposContext"] = dposProto
+	fields["bokerBackend"] = bokerProto
+	return fields
+}
+
+//将区块转换为RPC输出, ethCompat为真时只输出标准的以太坊字段
+func rpcOutputBlock(backend Backend, b *types.Block, inclTx bool, fullTx bool, ethCompat bool) (map[string]interface{}, error) {
+
+	fields := RPCMarshalHeader(b.Header(), ethCompat)
+	fields["totalDifficulty"] = (*hexutil.Big)(backend.GetTd(b.Hash()))
+	fields["size"] = hexutil.Uint64(uint64(b.Size().Int64()))
 
 	if inclTx {
 		formatTx := func(tx *types.Transaction) (interface{}, error) {
@@ -1414,7 +1778,7 @@ func (s *PublicBlockChainAPI) rpcOutputBlock(b *types.Block, inclTx bool, fullTx
 
 		if fullTx {
 			formatTx = func(tx *types.Transaction) (interface{}, error) {
-				return newRPCTransactionFromBlockHash(b, tx.Hash()), nil
+				return outputTransaction(newRPCTransactionFromBlockHash(b, tx.Hash()), ethCompat), nil
 			}
 		}
 
@@ -1429,14 +1793,16 @@ func (s *PublicBlockChainAPI) rpcOutputBlock(b *types.Block, inclTx bool, fullTx
 		fields["transactions"] = transactions
 	}
 
-	uncles := b.Uncles()
-	uncleHashes := make([]common.Hash, len(uncles))
-	for i, uncle := range uncles {
-		uncleHashes[i] = uncle.Hash()
+	if ethCompat {
+		uncles := b.Uncles()
+		uncleHashes := make([]common.Hash, len(uncles))
+		for i, uncle := range uncles {
+			uncleHashes[i] = uncle.Hash()
+		}
+		fields["uncles"] = uncleHashes
 	}
-	fields["uncles"] = uncleHashes
 
-	log.Info("(s *PublicBlockChainAPI) rpcOutputBlock", "dposProto", fields["dposProto"], "bokerProto", fields["bokerProto"])
+	log.Info("rpcOutputBlock", "dposProto", fields["dposProto"], "bokerProto", fields["bokerProto"])
 
 	return fields, nil
 }
@@ -1483,7 +1849,7 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		Name:       string(tx.Name()[:]),
 		Encryption: tx.Encryption(),
 		Extra:      hexutil.Bytes(tx.Extra()),
-		Ip:         string(tx.Ip()[:]),
+		Ip:         protocol.RedactIp(tx.Ip()),
 		Nonce:      hexutil.Uint64(tx.Nonce()),
 		To:         tx.To(),
 		Value:      (*hexutil.Big)(tx.Value()),
@@ -1494,57 +1860,93 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 
 	result.Time = time.Unix(tx.Time().Int64(), 0).String()
 
-	switch result.Major {
+	result.MajorNotes, result.MinorNotes = txTypeNotes(result.Major, result.Minor)
+
+	if blockHash != (common.Hash{}) {
+		result.BlockHash = blockHash
+		result.BlockNumber = (*hexutil.Big)(new(big.Int).SetUint64(blockNumber))
+		result.TransactionIndex = hexutil.Uint(index)
+	}
+	return result
+}
+
+//交易主要类型和次要类型的名称
+func txTypeNotes(major protocol.TxMajor, minor protocol.TxMinor) (majorNotes string, minorNotes string) {
+
+	switch major {
 
 	case protocol.Normal:
-		result.MajorNotes = "Normal"
-		result.MinorNotes = ""
+		majorNotes = "Normal"
+		switch minor {
+		case protocol.Sponsored:
+			minorNotes = "Sponsored"
+		default:
+			minorNotes = ""
+		}
 	case protocol.SystemBase:
-		result.MajorNotes = "SystemBase"
+		majorNotes = "SystemBase"
 
-		switch result.Minor {
+		switch minor {
 		case protocol.SetValidator:
-			result.MinorNotes = "SetValidator"
+			minorNotes = "SetValidator"
 		case protocol.SetSystemContract:
-			result.MinorNotes = "SetSystemContract"
+			minorNotes = "SetSystemContract"
 		case protocol.RegisterCandidate:
-			result.MinorNotes = "RegisterCandidate"
+			minorNotes = "RegisterCandidate"
 		case protocol.VoteUser:
-			result.MinorNotes = "VoteUser"
+			minorNotes = "VoteUser"
 		case protocol.VoteCancel:
-			result.MinorNotes = "VoteCancel"
+			minorNotes = "VoteCancel"
 		case protocol.VoteEpoch:
-			result.MinorNotes = "VoteEpoch"
+			minorNotes = "VoteEpoch"
+		case protocol.ConfirmContract:
+			minorNotes = "ConfirmContract"
+		case protocol.ApproveDeployer:
+			minorNotes = "ApproveDeployer"
+		case protocol.RevokeDeployer:
+			minorNotes = "RevokeDeployer"
+		case protocol.SetSigningKey:
+			minorNotes = "SetSigningKey"
+		case protocol.BridgeLock:
+			minorNotes = "BridgeLock"
+		case protocol.BridgeRelease:
+			minorNotes = "BridgeRelease"
+		case protocol.MultiSend:
+			minorNotes = "MultiSend"
+		case protocol.SetCommission:
+			minorNotes = "SetCommission"
+		case protocol.ClaimRewards:
+			minorNotes = "ClaimRewards"
+		case protocol.SetValidatorInfo:
+			minorNotes = "SetValidatorInfo"
+		case protocol.FundGasPool:
+			minorNotes = "FundGasPool"
 		default:
-			result.MinorNotes = ""
+			minorNotes = ""
 		}
 	case protocol.UserBase:
-		result.MajorNotes = "UserBase"
+		majorNotes = "UserBase"
 
-		switch result.Minor {
+		switch minor {
 		case protocol.SetUserContract:
-			result.MinorNotes = "SetUserContract"
+			minorNotes = "SetUserContract"
 		case protocol.CancelUserContract:
-			result.MinorNotes = "CancelUserContract"
+			minorNotes = "CancelUserContract"
 		default:
-			result.MinorNotes = ""
+			minorNotes = ""
 		}
 	case protocol.Extra:
-		result.MajorNotes = "Extra"
-		switch result.Minor {
+		majorNotes = "Extra"
+		switch minor {
 		case protocol.Word:
-			result.MinorNotes = "Word"
+			minorNotes = "Word"
 		case protocol.Data:
-			result.MinorNotes = "Data"
+			minorNotes = "Data"
+		case protocol.ContractMeta:
+			minorNotes = "ContractMeta"
 		}
 	}
-
-	if blockHash != (common.Hash{}) {
-		result.BlockHash = blockHash
-		result.BlockNumber = (*hexutil.Big)(new(big.Int).SetUint64(blockNumber))
-		result.TransactionIndex = hexutil.Uint(index)
-	}
-	return result
+	return majorNotes, minorNotes
 }
 
 func newRPCPendingTransaction(tx *types.Transaction) *RPCTransaction {
@@ -1605,16 +2007,16 @@ func (s *PublicTransactionPoolAPI) GetBlockTransactionCountByHash(ctx context.Co
 	return nil
 }
 
-func (s *PublicTransactionPoolAPI) GetTransactionByBlockNumberAndIndex(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint) *RPCTransaction {
+func (s *PublicTransactionPoolAPI) GetTransactionByBlockNumberAndIndex(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint) interface{} {
 	if block, _ := s.b.BlockByNumber(ctx, blockNr); block != nil {
-		return newRPCTransactionFromBlockIndex(block, uint64(index))
+		return outputTransaction(newRPCTransactionFromBlockIndex(block, uint64(index)), s.b.EthCompatible())
 	}
 	return nil
 }
 
-func (s *PublicTransactionPoolAPI) GetTransactionByBlockHashAndIndex(ctx context.Context, blockHash common.Hash, index hexutil.Uint) *RPCTransaction {
+func (s *PublicTransactionPoolAPI) GetTransactionByBlockHashAndIndex(ctx context.Context, blockHash common.Hash, index hexutil.Uint) interface{} {
 	if block, _ := s.b.GetBlock(ctx, blockHash); block != nil {
-		return newRPCTransactionFromBlockIndex(block, uint64(index))
+		return outputTransaction(newRPCTransactionFromBlockIndex(block, uint64(index)), s.b.EthCompatible())
 	}
 	return nil
 }
@@ -1635,9 +2037,9 @@ func (s *PublicTransactionPoolAPI) GetRawTransactionByBlockHashAndIndex(ctx cont
 	return nil
 }
 
-// GetTransactionCount returns the number of transactions the given address has sent for the given block number
-func (s *PublicTransactionPoolAPI) GetTransactionCount(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*hexutil.Uint64, error) {
-	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+// GetTransactionCount returns the number of transactions the given address has sent for the given block number or hash
+func (s *PublicTransactionPoolAPI) GetTransactionCount(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Uint64, error) {
+	state, _, err := stateAndHeaderByNumberOrHash(ctx, s.b, blockNrOrHash)
 	if state == nil || err != nil {
 		return nil, err
 	}
@@ -1646,13 +2048,18 @@ func (s *PublicTransactionPoolAPI) GetTransactionCount(ctx context.Context, addr
 }
 
 // GetTransactionByHash returns the transaction for the given hash
-func (s *PublicTransactionPoolAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) *RPCTransaction {
+func (s *PublicTransactionPoolAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) interface{} {
+	return outputTransaction(rpcTransactionByHash(s.b, hash), s.b.EthCompatible())
+}
+
+//根据哈希查找已打包或交易池中的交易
+func rpcTransactionByHash(b Backend, hash common.Hash) *RPCTransaction {
 	// Try to return an already finalized transaction
-	if tx, blockHash, blockNumber, index := core.GetTransaction(s.b.ChainDb(), hash); tx != nil {
+	if tx, blockHash, blockNumber, index := core.GetTransaction(b.ChainDb(), hash); tx != nil {
 		return newRPCTransaction(tx, blockHash, blockNumber, index)
 	}
 	// No finalized transaction, try to retrieve it from the pool
-	if tx := s.b.GetPoolTransaction(hash); tx != nil {
+	if tx := b.GetPoolTransaction(hash); tx != nil {
 		return newRPCPendingTransaction(tx)
 	}
 	// Transaction unknown, return as such
@@ -1676,30 +2083,59 @@ func (s *PublicTransactionPoolAPI) GetRawTransactionByHash(ctx context.Context,
 }
 
 // GetTransactionReceipt returns the transaction receipt for the given transaction hash.
-func (s *PublicTransactionPoolAPI) GetTransactionReceipt(hash common.Hash) (map[string]interface{}, error) {
-	tx, blockHash, blockNumber, index := core.GetTransaction(s.b.ChainDb(), hash)
+func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+
+	key := responseCacheKey("eth_getTransactionReceipt", hash.Hex())
+	if cached, ok := s.b.ResponseCache().get(ctx, s.b, key); ok {
+		return cached.(map[string]interface{}), nil
+	}
+	fields, err := rpcTransactionReceipt(s.b, hash, s.b.EthCompatible())
+	if fields != nil && err == nil {
+		s.b.ResponseCache().put(ctx, s.b, key, uint64(fields["blockNumber"].(hexutil.Uint64)), fields["blockHash"].(common.Hash), fields)
+	}
+	return fields, err
+}
+
+//得到交易收据的RPC输出, ethCompat为真时不输出Tina扩展的交易字段和执行错误信息
+func rpcTransactionReceipt(b Backend, hash common.Hash, ethCompat bool) (map[string]interface{}, error) {
+	tx, receipt, blockHash, blockNumber, index := core.GetTransactionReceipt(b.ChainDb(), hash)
 	if tx == nil {
 		return nil, nil
 	}
-	receipt, _, _, _ := core.GetReceipt(s.b.ChainDb(), hash) // Old receipts don't have the lookup data available
+
+	//交易已知但收据数据缺失时返回错误而不是不完整的收据
+	if receipt == nil {
+		log.Warn("Transaction receipt missing", "hash", hash, "number", blockNumber, "block", blockHash)
+		return nil, protocol.ErrReceiptMissing
+	}
 	from, _ := types.Sender(types.HomesteadSigner{}, tx)
 
+	fields := rpcReceiptFields(receipt, ethCompat)
+	fields["blockHash"] = blockHash
+	fields["blockNumber"] = hexutil.Uint64(blockNumber)
+	fields["transactionHash"] = hash
+	fields["transactionIndex"] = hexutil.Uint64(index)
+	fields["from"] = from
+	fields["to"] = tx.To()
+	fields["logsBloom"] = receipt.Bloom
+
+	if !ethCompat {
+		fields["major"] = tx.Major()
+		fields["minor"] = tx.Minor()
+		fields["extra"] = hexutil.Bytes(tx.Extra())
+		fields["ip"] = protocol.RedactIp(tx.Ip())
+	}
+	return fields, nil
+}
+
+//得到收据自身的执行结果字段(状态、Gas、创建的合约、日志以及执行错误)，不包括交易和区块字段
+func rpcReceiptFields(receipt *types.Receipt, ethCompat bool) map[string]interface{} {
+
 	fields := map[string]interface{}{
-		"blockHash":         blockHash,
-		"blockNumber":       hexutil.Uint64(blockNumber),
-		"transactionHash":   hash,
-		"transactionIndex":  hexutil.Uint64(index),
-		"major":             tx.Major(),
-		"minor":             tx.Minor(),
-		"from":              from,
-		"to":                tx.To(),
-		"extra":             hexutil.Bytes(tx.Extra()),
-		"ip":                string(tx.Ip()[:]),
 		"gasUsed":           (*hexutil.Big)(receipt.GasUsed),
 		"cumulativeGasUsed": (*hexutil.Big)(receipt.CumulativeGasUsed),
 		"contractAddress":   nil,
 		"logs":              receipt.Logs,
-		"logsBloom":         receipt.Bloom,
 	}
 
 	// Assign receipt status or post state.
@@ -1708,6 +2144,12 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(hash common.Hash) (map[
 	} else {
 		fields["status"] = hexutil.Uint(receipt.Status)
 	}
+	if receipt.ErrorCode != vm.ErrCodeNone && !ethCompat {
+		fields["errorCode"] = hexutil.Uint(receipt.ErrorCode)
+		if receipt.RevertReason != "" {
+			fields["revertReason"] = receipt.RevertReason
+		}
+	}
 	if receipt.Logs == nil {
 		fields["logs"] = [][]*types.Log{}
 	}
@@ -1715,7 +2157,7 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(hash common.Hash) (map[
 	if receipt.ContractAddress != (common.Address{}) {
 		fields["contractAddress"] = receipt.ContractAddress
 	}
-	return fields, nil
+	return fields
 }
 
 // sign is a helper function that signs a transaction with the private key of the given address.
@@ -1755,6 +2197,8 @@ type SendTxArgs struct {
 	Nonce      *hexutil.Uint64  `json:"nonce"`
 	Major      protocol.TxMajor `json:"major"`
 	Minor      protocol.TxMinor `json:"minor"`
+
+	Sponsorship hexutil.Bytes `json:"sponsorship"` //代付账号的签名(eth_sponsorTransaction)，不为空时Gas由代付账号支付
 }
 
 // prepareSendTxArgs is a helper function that fills in default values for unspecified tx fields.
@@ -1792,32 +2236,108 @@ func (args *SendTxArgs) SetDefaults(ctx context.Context, b Backend) error {
 	return nil
 }
 
-//这里需要进行判断
-func (args *SendTxArgs) ToTransaction() (*types.Transaction, error) {
+//在签名之前检查交易参数，SendTransaction、SignTransaction以及Resend共用
+func (args *SendTxArgs) Validate() error {
+
+	//必需的字段（由SetDefaults填充）
+	if args.Nonce == nil || args.Gas == nil || args.GasPrice == nil || args.Value == nil {
+		return protocol.ErrMissingTxField
+	}
+
+	//代付交易只能是有接收地址的普通交易
+	if len(args.Sponsorship) > 0 {
+		if args.Major != protocol.Normal || (args.Minor != protocol.NormalCall && args.Minor != protocol.Sponsored) {
+			return protocol.ErrUnknownMinor
+		}
+		if len(args.Sponsorship) != 65 || args.To == nil {
+			return protocol.ErrInvalidSponsor
+		}
+	}
 
-	//判断交易地址是否为空
+	//主要交易类型与次要交易类型的组合
+	switch args.Major {
+	case protocol.Normal:
+		if args.Minor == protocol.Sponsored && len(args.Sponsorship) == 0 {
+			return protocol.ErrInvalidSponsor
+		}
+	case protocol.SystemBase:
+		if args.Minor <= protocol.MinMinor || args.Minor >= protocol.MaxMinor {
+			return protocol.ErrUnknownMinor
+		}
+	case protocol.UserBase:
+		if args.Minor > protocol.CancelUserContract {
+			return protocol.ErrUnknownMinor
+		}
+	case protocol.Extra:
+		if args.Minor > protocol.ContractMeta {
+			return protocol.ErrUnknownMinor
+		}
+	case protocol.Stock:
+		if args.Minor > protocol.StockUnFrozen {
+			return protocol.ErrUnknownMinor
+		}
+	default:
+		return protocol.ErrUnknownMajor
+	}
+
+	//只有普通交易可以不指定接收地址（创建合约）
 	if args.To == nil {
+		if args.Major != protocol.Normal {
+			return protocol.ErrMissingRecipient
+		}
+		if len(args.Data) == 0 {
+			return protocol.ErrMissingPayload
+		}
+	}
+
+	//数据大小
+	switch {
+	case int64(len(args.Extra)) > protocol.MaxExtraSize:
+		return protocol.ErrPayloadTooLarge
+	case (args.Major == protocol.Normal || args.Major == protocol.SystemBase) && common.StorageSize(len(args.Data)) > protocol.MaxNormalSize:
+		return protocol.ErrPayloadTooLarge
+	}
+	return nil
+}
 
-		if args.Major == protocol.SystemBase {
+func (args *SendTxArgs) ToTransaction() (*types.Transaction, error) {
 
-			return nil, errors.New("System Base contract transaction type not found contract address")
-		} else if args.Major == protocol.SystemBase {
-			return nil, errors.New("User Base contract transaction type not found contract address")
-		} else if args.Major == protocol.Normal {
-			return types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), (*big.Int)(args.Gas), (*big.Int)(args.GasPrice), args.Data), nil
-		} else if args.Major == protocol.Extra {
-			return nil, nil
-		} else {
-			return nil, errors.New("unknown transaction type")
+	if err := args.Validate(); err != nil {
+		return nil, err
+	}
+
+	//没有接收地址的只能是创建合约的普通交易
+	if args.To == nil {
+		return types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), (*big.Int)(args.Gas), (*big.Int)(args.GasPrice), args.Data), nil
+	}
+
+	//代付交易的扩展数据为代付账号的签名(V为0/1)
+	if len(args.Sponsorship) > 0 {
+		sig := common.CopyBytes(args.Sponsorship)
+		if sig[64] >= 27 {
+			sig[64] -= 27
 		}
+		tx := types.NewTransaction(protocol.Normal, protocol.Sponsored, uint64(*args.Nonce), *args.To, (*big.Int)(args.Value), (*big.Int)(args.Gas), (*big.Int)(args.GasPrice), args.Data)
+		tx.SetExtra(sig)
+		return tx, nil
 	}
+	return types.NewTransaction(args.Major, args.Minor, uint64(*args.Nonce), *args.To, (*big.Int)(args.Value), (*big.Int)(args.Gas), (*big.Int)(args.GasPrice), args.Data), nil
+}
+
+//检查EIP155签名的交易中的链ID是否与本链一致，未使用EIP155签名的交易不包含链ID，
+//在number区块EIP155生效后只有allowUnprotected为true时才接受
+func checkChainId(config *params.ChainConfig, number *big.Int, tx *types.Transaction, allowUnprotected bool) error {
 
-	//设置交易地址
-	to := common.Address{}
-	if args.To != nil {
-		to = *args.To
+	if !tx.Protected() {
+		if config.IsEIP155(number) && !allowUnprotected {
+			return protocol.ErrUnprotectedTx
+		}
+		return nil
+	}
+	if config.ChainId == nil || tx.ChainId().Cmp(config.ChainId) != 0 {
+		return protocol.ErrInvalidChainId
 	}
-	return types.NewTransaction(args.Major, args.Minor, uint64(*args.Nonce), to, (*big.Int)(args.Value), (*big.Int)(args.Gas), (*big.Int)(args.GasPrice), args.Data), nil
+	return nil
 }
 
 func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (common.Hash, error) {
@@ -1828,6 +2348,13 @@ func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (c
 		return common.Hash{}, err
 	}
 
+	//拒绝为其他链签名的交易，防止交易在不同的Tina网络之间重放
+	next := new(big.Int).Add(b.CurrentBlock().Number(), big.NewInt(1))
+	if err := checkChainId(b.ChainConfig(), next, tx, b.UnprotectedTxsAllowed()); err != nil {
+		log.Error("SubmitTransaction ChainId", "error", err, "chainId", tx.ChainId())
+		return common.Hash{}, err
+	}
+
 	//设置IP地址
 	tx.SetIp()
 	log.Info("SubmitTransaction SetIp", "Ip", string(tx.Ip()[:]), "tx.Hash", tx.Hash().String())
@@ -1840,7 +2367,7 @@ func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (c
 
 	//如果to为空得到签名者，并进行签名
 	if tx.To() == nil {
-		from, err := types.Sender(types.HomesteadSigner{}, tx)
+		from, err := types.Sender(TxSigner(tx), tx)
 		if err != nil {
 			log.Error("SubmitTransaction Sender", "error", err)
 			return common.Hash{}, err
@@ -1937,9 +2464,10 @@ func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, encod
 			"hash", tx.Hash().String())
 	}
 
-	sender, err := types.Sender(types.HomesteadSigner{}, tx)
+	sender, err := types.Sender(TxSigner(tx), tx)
 	if err != nil {
-		panic(fmt.Errorf("invalid transaction: %v", err))
+		log.Error("SendRawTransaction Sender", "error", err)
+		return common.Hash{}, err
 	}
 	log.Info("(s *PublicTransactionPoolAPI) SendRawTransaction types.Sender", "from", sender.String())
 
@@ -1958,6 +2486,48 @@ func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, encod
 	return hash, resultErr
 }
 
+//解码后的原始交易, 用于钱包开发者校验客户端对Tina扩展交易格式的编码
+type RPCDecodedTransaction struct {
+	*RPCTransaction
+	Signed    bool         `json:"signed"`            //是否已签名(未签名时from为空)
+	Protected bool         `json:"protected"`         //是否使用EIP155签名
+	ChainId   *hexutil.Big `json:"chainId,omitempty"` //EIP155签名中的链ID
+	Error     string       `json:"error,omitempty"`   //签名无法恢复或交易类型校验失败的原因
+}
+
+//RLP解码已签名或未签名的原始交易, 返回完整的交易视图(包括扩展字段以及恢复出的发送者), 不会提交交易
+func (s *PublicTransactionPoolAPI) DecodeRawTransaction(encodedTx hexutil.Bytes) (*RPCDecodedTransaction, error) {
+
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return nil, err
+	}
+	result := &RPCDecodedTransaction{
+		RPCTransaction: newRPCPendingTransaction(tx),
+		Signed:         tx.R().Sign() != 0 || tx.S().Sign() != 0,
+	}
+	result.From = common.Address{}
+
+	if result.Signed {
+		var signer types.Signer = types.HomesteadSigner{}
+		if tx.Protected() {
+			result.Protected = true
+			result.ChainId = (*hexutil.Big)(tx.ChainId())
+			signer = types.NewEIP155Signer(tx.ChainId())
+		}
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			result.Error = err.Error()
+			return result, nil
+		}
+		result.From = from
+	}
+	if err := tx.Validate(); err != nil {
+		result.Error = err.Error()
+	}
+	return result, nil
+}
+
 // Sign calculates an ECDSA signature for:
 // keccack256("\x19Ethereum Signed Message:\n" + len(message) + message).
 //
@@ -2025,13 +2595,13 @@ func (s *PublicTransactionPoolAPI) SignTransaction(ctx context.Context, args Sen
 
 // PendingTransactions returns the transactions that are in the transaction pool and have a from address that is one of
 // the accounts this node manages.
-func (s *PublicTransactionPoolAPI) PendingTransactions() ([]*RPCTransaction, error) {
+func (s *PublicTransactionPoolAPI) PendingTransactions() ([]interface{}, error) {
 	pending, err := s.b.GetPoolTransactions()
 	if err != nil {
 		return nil, err
 	}
 
-	transactions := make([]*RPCTransaction, 0, len(pending))
+	transactions := make([]interface{}, 0, len(pending))
 	for _, tx := range pending {
 		var signer types.Signer = types.HomesteadSigner{}
 		/*if tx.Protected() {
@@ -2039,7 +2609,7 @@ func (s *PublicTransactionPoolAPI) PendingTransactions() ([]*RPCTransaction, err
 		}*/
 		from, _ := types.Sender(signer, tx)
 		if _, err := s.b.AccountManager().Find(accounts.Account{Address: from}); err == nil {
-			transactions = append(transactions, newRPCPendingTransaction(tx))
+			transactions = append(transactions, outputTransaction(newRPCPendingTransaction(tx), s.b.EthCompatible()))
 		}
 	}
 	return transactions, nil
@@ -2183,9 +2753,11 @@ func (api *PrivateDebugAPI) ChaindbCompact() error {
 	return nil
 }
 
-// SetHead rewinds the head of the blockchain to a previous block.
-func (api *PrivateDebugAPI) SetHead(number hexutil.Uint64) {
-	api.b.SetHead(uint64(number))
+// SetHead rewinds the head of the blockchain to a previous block. Rewinding below
+// the confirmed block is refused, the abandoned blocks are exported to a recovery
+// file and their transactions are reinjected into the transaction pool.
+func (api *PrivateDebugAPI) SetHead(number hexutil.Uint64) (*core.RewindReport, error) {
+	return api.b.SetHead(uint64(number))
 }
 
 // PublicNetAPI offers network related RPC methods
@@ -2213,3 +2785,25 @@ func (s *PublicNetAPI) PeerCount() hexutil.Uint {
 func (s *PublicNetAPI) Version() string {
 	return fmt.Sprintf("%d", s.networkVersion)
 }
+
+// NetNodeInfo is the peering information of the host node reported by net_nodeInfo.
+type NetNodeInfo struct {
+	*p2p.NodeInfo
+	Discovery   bool   `json:"discovery"`        // Whether the V4 node discovery is running
+	DiscoveryV5 bool   `json:"discoveryV5"`      // Whether the V5 topic discovery is running
+	V5Addr      string `json:"v5Addr,omitempty"` // UDP listening address of the V5 discovery
+}
+
+// NodeInfo returns the enode URL, listening addresses, discovery status and the
+// head of every running protocol, allowing validators to peer with each other.
+func (s *PublicNetAPI) NodeInfo() *NetNodeInfo {
+	info := &NetNodeInfo{
+		NodeInfo:    s.net.NodeInfo(),
+		Discovery:   !s.net.NoDiscovery,
+		DiscoveryV5: s.net.DiscoveryV5,
+	}
+	if s.net.DiscoveryV5 {
+		info.V5Addr = s.net.DiscoveryV5Addr
+	}
+	return info
+}
@@ -1,25 +1,304 @@
 package ethapi
 
 import (
+	"bytes"
+	"context"
 	"math/big"
 	"testing"
 
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
 	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/p2p"
+	"github.com/Tinachain/Tina/chain/p2p/discover"
+	"github.com/Tinachain/Tina/chain/params"
+	"github.com/Tinachain/Tina/chain/rlp"
 )
 
 func TestToTransaction(t *testing.T) {
 	nonce := uint64(0)
 	args := &SendTxArgs{
-		Type:     types.ApplyCandidate,
+		Major:    protocol.Normal,
 		Nonce:    (*hexutil.Uint64)(&nonce),
 		Gas:      (*hexutil.Big)(big.NewInt(0)),
 		GasPrice: (*hexutil.Big)(big.NewInt(0)),
 		Value:    (*hexutil.Big)(big.NewInt(0)),
+		Data:     hexutil.Bytes{0x60, 0x00},
 		To:       nil,
 	}
-	tx := args.toTransaction()
+	tx, err := args.ToTransaction()
+	if err != nil {
+		t.Fatalf("failed to convert contract creation: %v", err)
+	}
 	if tx.To() != nil {
 		t.Errorf("transaction receiptent nil is expected, but got %x", tx.To())
 	}
 }
+
+// Tests that transaction arguments are rejected with typed errors before they
+// are signed.
+func TestSendTxArgsValidate(t *testing.T) {
+	to := common.HexToAddress("0x01")
+	valid := func() *SendTxArgs {
+		nonce := uint64(0)
+		return &SendTxArgs{
+			To:       &to,
+			Nonce:    (*hexutil.Uint64)(&nonce),
+			Gas:      (*hexutil.Big)(big.NewInt(21000)),
+			GasPrice: (*hexutil.Big)(big.NewInt(1)),
+			Value:    (*hexutil.Big)(big.NewInt(0)),
+		}
+	}
+
+	tests := []struct {
+		modify func(args *SendTxArgs)
+		err    error
+	}{
+		{func(args *SendTxArgs) {}, nil},
+		{func(args *SendTxArgs) { args.Gas = nil }, protocol.ErrMissingTxField},
+		{func(args *SendTxArgs) { args.Major = protocol.Stock + 1 }, protocol.ErrUnknownMajor},
+		{func(args *SendTxArgs) { args.Major, args.Minor = protocol.SystemBase, protocol.MinMinor }, protocol.ErrUnknownMinor},
+		{func(args *SendTxArgs) { args.Major, args.Minor = protocol.Extra, protocol.ContractMeta+1 }, protocol.ErrUnknownMinor},
+		{func(args *SendTxArgs) { args.Major, args.Minor = protocol.SystemBase, protocol.SetValidator }, nil},
+		{func(args *SendTxArgs) { args.Major, args.Minor, args.To = protocol.SystemBase, protocol.SetValidator, nil }, protocol.ErrMissingRecipient},
+		{func(args *SendTxArgs) { args.To = nil }, protocol.ErrMissingPayload},
+		{func(args *SendTxArgs) { args.Extra = make(hexutil.Bytes, protocol.MaxExtraSize+1) }, protocol.ErrPayloadTooLarge},
+		{func(args *SendTxArgs) { args.Minor = protocol.Sponsored }, protocol.ErrInvalidSponsor},
+		{func(args *SendTxArgs) { args.Minor, args.Sponsorship = protocol.Sponsored, make(hexutil.Bytes, 65) }, nil},
+		{func(args *SendTxArgs) { args.Sponsorship, args.To = make(hexutil.Bytes, 65), nil }, protocol.ErrInvalidSponsor},
+	}
+	for i, tt := range tests {
+		args := valid()
+		tt.modify(args)
+		if err := args.Validate(); err != tt.err {
+			t.Errorf("test %d: have %v, want %v", i, err, tt.err)
+		}
+		if _, err := args.ToTransaction(); err != tt.err {
+			t.Errorf("test %d: conversion: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
+
+// chainIdTestBackend serves a chain config and head block, and records the
+// submitted transactions.
+type chainIdTestBackend struct {
+	Backend
+	config           *params.ChainConfig
+	head             *types.Block
+	allowUnprotected bool
+	sent             []*types.Transaction
+}
+
+func (b *chainIdTestBackend) ChainConfig() *params.ChainConfig { return b.config }
+func (b *chainIdTestBackend) CurrentBlock() *types.Block       { return b.head }
+func (b *chainIdTestBackend) UnprotectedTxsAllowed() bool      { return b.allowUnprotected }
+
+func (b *chainIdTestBackend) SendTx(ctx context.Context, tx *types.Transaction) error {
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+// Tests that only transactions signed for this chain are submitted, and that
+// unprotected transactions are refused after EIP155 unless explicitly allowed.
+func TestSubmitTransactionChainId(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sign := func(signer types.Signer) *types.Transaction {
+		tx := types.NewTransaction(protocol.Normal, protocol.NormalCall, 0, common.Address{0x01}, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil)
+		tx, _ = types.SignTx(tx, signer, key)
+		return tx
+	}
+	config := *params.TestChainConfig
+	config.EIP155Block = big.NewInt(2)
+
+	tests := []struct {
+		tx               *types.Transaction
+		head             int64
+		allowUnprotected bool
+		err              error
+	}{
+		{sign(types.NewEIP155Signer(config.ChainId)), 1, false, nil},
+		{sign(types.NewEIP155Signer(big.NewInt(2))), 1, false, protocol.ErrInvalidChainId},
+		{sign(types.HomesteadSigner{}), 0, false, nil},
+		{sign(types.HomesteadSigner{}), 1, false, protocol.ErrUnprotectedTx},
+		{sign(types.HomesteadSigner{}), 1, true, nil},
+	}
+	for i, test := range tests {
+		backend := &chainIdTestBackend{config: &config, head: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(test.head)}), allowUnprotected: test.allowUnprotected}
+		if _, err := SubmitTransaction(context.Background(), backend, test.tx); err != test.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, test.err)
+			continue
+		}
+		if submitted := len(backend.sent) == 1; submitted != (test.err == nil) {
+			t.Errorf("test %d: submitted mismatch: have %v, want %v", i, submitted, test.err == nil)
+		}
+	}
+}
+
+// Tests that raw transactions are decoded into the full transaction view with
+// the recovered sender, and that invalid signatures and types are reported.
+func TestDecodeRawTransaction(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		from   = crypto.PubkeyToAddress(key.PublicKey)
+		api    = NewPublicTransactionPoolAPI(nil, new(AddrLocker))
+		extra  = types.NewExtraTransaction(protocol.Extra, protocol.Word, 3, common.Address{0x01}, big.NewInt(0), big.NewInt(100000), big.NewInt(1), []byte("name"), []byte("word"), 1)
+	)
+	encode := func(tx *types.Transaction) hexutil.Bytes {
+		blob, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			t.Fatalf("failed to encode transaction: %v", err)
+		}
+		return blob
+	}
+	sign := func(tx *types.Transaction, signer types.Signer) *types.Transaction {
+		tx, err := types.SignTx(tx, signer, key)
+		if err != nil {
+			t.Fatalf("failed to sign transaction: %v", err)
+		}
+		return tx
+	}
+
+	//未签名的交易没有发送者
+	decoded, err := api.DecodeRawTransaction(encode(extra))
+	if err != nil {
+		t.Fatalf("failed to decode unsigned transaction: %v", err)
+	}
+	if decoded.Signed || decoded.From != (common.Address{}) || decoded.Error != "" {
+		t.Errorf("unsigned transaction mismatch: signed %v, from %x, error %q", decoded.Signed, decoded.From, decoded.Error)
+	}
+	if decoded.Major != protocol.Extra || decoded.Minor != protocol.Word || string(decoded.Extra) != "word" || decoded.Name != "name" || decoded.Encryption != 1 || decoded.Nonce != 3 {
+		t.Errorf("extended fields mismatch: %+v", decoded.RPCTransaction)
+	}
+
+	decoded, err = api.DecodeRawTransaction(encode(sign(extra, types.HomesteadSigner{})))
+	if err != nil || !decoded.Signed || decoded.Protected || decoded.ChainId != nil || decoded.From != from {
+		t.Errorf("homestead transaction mismatch: %+v, err %v", decoded, err)
+	}
+	decoded, err = api.DecodeRawTransaction(encode(sign(extra, types.NewEIP155Signer(big.NewInt(5)))))
+	if err != nil || !decoded.Protected || decoded.ChainId.ToInt().Int64() != 5 || decoded.From != from {
+		t.Errorf("eip155 transaction mismatch: %+v, err %v", decoded, err)
+	}
+
+	//签名无法恢复或交易类型无效时仍然返回解码结果
+	sig := make([]byte, 65)
+	sig[0] = 1
+	copy(sig[32:64], bytes.Repeat([]byte{0xff}, 32))
+	invalid, err := extra.WithSignature(types.HomesteadSigner{}, sig)
+	if err != nil {
+		t.Fatalf("failed to attach signature: %v", err)
+	}
+	if decoded, err := api.DecodeRawTransaction(encode(invalid)); err != nil || !decoded.Signed || decoded.Error != types.ErrInvalidSig.Error() {
+		t.Errorf("invalid signature mismatch: %+v, err %v", decoded, err)
+	}
+	unknown := types.NewTransaction(protocol.Extra, protocol.TxMinor(200), 0, common.Address{0x01}, big.NewInt(0), big.NewInt(21000), big.NewInt(1), nil)
+	if decoded, err := api.DecodeRawTransaction(encode(unknown)); err != nil || decoded.Error == "" {
+		t.Errorf("unknown minor type accepted: %+v, err %v", decoded, err)
+	}
+
+	if _, err := api.DecodeRawTransaction(hexutil.Bytes{0x01, 0x02}); err == nil {
+		t.Errorf("malformed encoding decoded")
+	}
+}
+
+type headerTestBackend struct {
+	Backend
+	db        ethdb.Database
+	ethCompat bool
+}
+
+func (b *headerTestBackend) ChainDb() ethdb.Database { return b.db }
+func (b *headerTestBackend) EthCompatible() bool     { return b.ethCompat }
+
+func (b *headerTestBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return core.GetHeader(b.db, hash, core.GetBlockNumber(b.db, hash)), nil
+}
+
+func (b *headerTestBackend) GetTd(hash common.Hash) *big.Int {
+	return core.GetTd(b.db, hash, core.GetBlockNumber(b.db, hash))
+}
+
+// Tests that blocks are resolved to their number by hash, including the blocks
+// of side forks, and that their headers are returned without the body.
+func TestGetHeaderByHash(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	backend := &headerTestBackend{db: db}
+	api := NewPublicBlockChainAPI(backend)
+
+	//区块3及其分叉区块，分叉区块不在主链上
+	canonical := &types.Header{Number: big.NewInt(3), Extra: []byte("canonical"), DposProto: &types.DposContextProto{}, BokerProto: &types.BokerBackendProto{}}
+	side := &types.Header{Number: big.NewInt(3), Extra: []byte("side"), DposProto: &types.DposContextProto{}, BokerProto: &types.BokerBackendProto{}}
+	for i, header := range []*types.Header{canonical, side} {
+		core.WriteHeader(db, header)
+		core.WriteTd(db, header.Hash(), 3, big.NewInt(int64(10+i)))
+	}
+	core.WriteCanonicalHash(db, canonical.Hash(), 3)
+
+	for _, header := range []*types.Header{canonical, side} {
+		if number := api.GetBlockNumberByHash(context.Background(), header.Hash()); number == nil || *number != 3 {
+			t.Errorf("block %x: number mismatch: have %v, want 3", header.Hash(), number)
+		}
+	}
+	if number := api.GetBlockNumberByHash(context.Background(), common.Hash{0x01}); number != nil {
+		t.Errorf("unknown block: have number %d", *number)
+	}
+
+	fields, err := api.GetHeaderByHash(context.Background(), side.Hash())
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+	if fields["hash"] != side.Hash() || fields["totalDifficulty"].(*hexutil.Big).ToInt().Int64() != 11 || fields["validator"] == nil {
+		t.Errorf("header fields mismatch: %v", fields)
+	}
+	if _, ok := fields["transactions"]; ok {
+		t.Errorf("header carries the block body")
+	}
+	backend.ethCompat = true
+	if fields, _ := api.GetHeaderByHash(context.Background(), canonical.Hash()); fields["miner"] == nil || fields["validator"] != nil {
+		t.Errorf("compatible header fields mismatch: %v", fields)
+	}
+	if fields, err := api.GetHeaderByHash(context.Background(), common.Hash{0x01}); fields != nil || err != nil {
+		t.Errorf("unknown header: have %v, err %v", fields, err)
+	}
+}
+
+// Tests that net_nodeInfo reports the discovery status next to the enode URL
+// and the head of every running protocol.
+func TestNetNodeInfo(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	server := &p2p.Server{Config: p2p.Config{
+		PrivateKey:  key,
+		MaxPeers:    1,
+		NoDiscovery: true,
+		ListenAddr:  "127.0.0.1:0",
+		Protocols: []p2p.Protocol{{
+			Name:     "eth",
+			NodeInfo: func() interface{} { return "head" },
+		}},
+	}}
+	api := NewPublicNetAPI(server, 1)
+
+	info := api.NodeInfo()
+	if info.Discovery || info.DiscoveryV5 || info.V5Addr != "" {
+		t.Errorf("discovery mismatch: have %v/%v/%q, want disabled", info.Discovery, info.DiscoveryV5, info.V5Addr)
+	}
+	if info.Protocols["eth"] != "head" {
+		t.Errorf("protocol info mismatch: have %v, want head", info.Protocols["eth"])
+	}
+
+	discovering := &p2p.Server{Config: p2p.Config{DiscoveryV5: true, DiscoveryV5Addr: "127.0.0.1:30304"}}
+	if info := NewPublicNetAPI(discovering, 1).NodeInfo(); !info.Discovery || !info.DiscoveryV5 || info.V5Addr != "127.0.0.1:30304" {
+		t.Errorf("discovery mismatch: have %v/%v/%q", info.Discovery, info.DiscoveryV5, info.V5Addr)
+	}
+
+	if err := server.Start(); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+	defer server.Stop()
+	if info := api.NodeInfo(); info.Enode != server.Self().String() || info.ID != discover.PubkeyID(&key.PublicKey).String() {
+		t.Errorf("enode mismatch: have %s, want %s", info.Enode, server.Self())
+	}
+}
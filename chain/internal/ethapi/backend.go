@@ -20,9 +20,11 @@ package ethapi
 import (
 	"context"
 	"math/big"
+	"time"
 
 	"github.com/Tinachain/Tina/chain/accounts"
 	"github.com/Tinachain/Tina/chain/boker/api"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/core"
 	"github.com/Tinachain/Tina/chain/core/state"
@@ -47,10 +49,12 @@ type Backend interface {
 	AccountManager() *accounts.Manager
 
 	//链的 API
-	SetHead(number uint64)
+	SetHead(number uint64) (*core.RewindReport, error)
 	HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error)
+	HeaderByHash(ctx context.Context, blockHash common.Hash) (*types.Header, error)
 	BlockByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Block, error)
 	StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*state.StateDB, *types.Header, error)
+	StateAndHeaderByHash(ctx context.Context, blockHash common.Hash) (*state.StateDB, *types.Header, error)
 	GetBlock(ctx context.Context, blockHash common.Hash) (*types.Block, error)
 	GetReceipts(ctx context.Context, blockHash common.Hash) (types.Receipts, error)
 	GetTd(blockHash common.Hash) *big.Int
@@ -63,11 +67,24 @@ type Backend interface {
 	GetPoolTransactions() (types.Transactions, error)
 	GetPoolTransaction(txHash common.Hash) *types.Transaction
 	GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error)
+	ScheduleTx(ctx context.Context, signedTx *types.Transaction, block uint64) error
+	ScheduledTxs() []*core.ScheduledTx
+	CancelScheduledTx(txHash common.Hash) bool
+	RebroadcastTx(txHash common.Hash) bool
+	PoolTransaction(txHash common.Hash) *core.PoolTx
+	PoolTransactionsByNonce(addr common.Address, from, to uint64) []*core.PoolTx
 	Stats() (pending int, queued int)
+	DryRunTx(ctx context.Context, from common.Address, tx *types.Transaction) (*types.Receipt, error) //在当前区块之上试执行未签名的交易
 	TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions)
 	SubscribeTxPreEvent(chan<- core.TxPreEvent) event.Subscription
 	ChainConfig() *params.ChainConfig
 	CurrentBlock() *types.Block
+	EthCompatible() bool                    //eth_*接口是否严格按以太坊格式输出
+	UnprotectedTxsAllowed() bool            //EIP155生效后是否仍然接受未包含链ID的交易
+	RPCTimeout(method string) time.Duration //RPC方法的执行期限(0为不限制)
+	FinalizedNumber() uint64                //已确认(不可逆)的区块高度
+	ResponseCache() *ResponseCache          //不可变查询结果的缓存(nil为不缓存)
+	PendingSpeculative() bool               //pending查询的结果是否来自试执行交易池交易组装的虚拟区块
 
 	//获取Tina链的接口
 	Coinbase() (common.Address, error)
@@ -77,6 +94,11 @@ type Backend interface {
 	Boker() bokerapi.Api
 	DecodeParams(code []byte) ([]byte, error)
 	GetLocalValidator() common.Address
+
+	//Boker上下文查询(轻节点通过ODR获取并校验Merkle证明)
+	GetStock(ctx context.Context, address common.Address) (*protocol.StockAccount, error)
+	GetStockManager(ctx context.Context) (common.Address, error)
+	GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error)
 }
 
 func GetAPIs(apiBackend Backend, boker bokerapi.Api) []rpc.API {
@@ -121,6 +143,41 @@ func GetAPIs(apiBackend Backend, boker bokerapi.Api) []rpc.API {
 			Version:   "1.0",
 			Service:   NewPrivateAccountAPI(apiBackend, nonceLock),
 			Public:    false,
+		}, {
+			Namespace: "boker",
+			Version:   "1.0",
+			Service:   NewPublicBokerAPI(apiBackend),
+			Public:    true,
+		}, {
+			Namespace: "gov",
+			Version:   "1.0",
+			Service:   NewPublicGovAPI(apiBackend),
+			Public:    true,
+		}, {
+			Namespace: "stats",
+			Version:   "1.0",
+			Service:   NewPublicStatsAPI(apiBackend),
+			Public:    true,
+		}, {
+			Namespace: "bridge",
+			Version:   "1.0",
+			Service:   NewPublicBridgeAPI(apiBackend),
+			Public:    true,
+		}, {
+			Namespace: "delegation",
+			Version:   "1.0",
+			Service:   NewPublicDelegationAPI(apiBackend),
+			Public:    true,
+		}, {
+			Namespace: "token",
+			Version:   "1.0",
+			Service:   NewPublicTokenAPI(apiBackend),
+			Public:    true,
+		}, {
+			Namespace: "deposit",
+			Version:   "1.0",
+			Service:   NewPrivateDepositAPI(apiBackend),
+			Public:    false,
 		},
 	}
 }
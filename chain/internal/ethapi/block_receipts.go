@@ -0,0 +1,57 @@
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/log"
+)
+
+var errBlockReceipts = errors.New("receipts can only be included with full transactions of a sealed block")
+
+//附带收据的交易输出：在交易的字段之后增加receipt字段
+type rpcTransactionWithReceipt struct {
+	tx      interface{}
+	receipt map[string]interface{}
+}
+
+func (t *rpcTransactionWithReceipt) MarshalJSON() ([]byte, error) {
+
+	blob, err := json.Marshal(t.tx)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(blob, &fields); err != nil {
+		return nil, err
+	}
+	if fields["receipt"], err = json.Marshal(t.receipt); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+//在区块输出的每个完整交易中附带其收据(状态、Gas、创建的合约、日志以及执行错误)，收据一次从区块读取
+func attachReceipts(ctx context.Context, b Backend, block *types.Block, response map[string]interface{}) error {
+
+	txs, ok := response["transactions"].([]interface{})
+	if !ok || len(txs) == 0 {
+		return nil
+	}
+	receipts, err := b.GetReceipts(ctx, block.Hash())
+	if err != nil {
+		return err
+	}
+	if len(receipts) != len(txs) {
+		log.Warn("Block receipts missing", "number", block.Number(), "hash", block.Hash(), "txs", len(txs), "receipts", len(receipts))
+		return protocol.ErrReceiptMissing
+	}
+	ethCompat := b.EthCompatible()
+	for i, tx := range txs {
+		txs[i] = &rpcTransactionWithReceipt{tx: tx, receipt: rpcReceiptFields(receipts[i], ethCompat)}
+	}
+	return nil
+}
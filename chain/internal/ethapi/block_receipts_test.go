@@ -0,0 +1,50 @@
+package ethapi
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+)
+
+func TestTransactionWithReceiptJSON(t *testing.T) {
+	var (
+		hash    = common.HexToHash("0x1234")
+		address = common.HexToAddress("0xc0de")
+		receipt = &types.Receipt{
+			Status:            types.ReceiptStatusSuccessful,
+			GasUsed:           big.NewInt(21000),
+			CumulativeGasUsed: big.NewInt(42000),
+			ContractAddress:   address,
+			Logs:              []*types.Log{{Address: address, TxHash: hash}},
+		}
+	)
+	blob, err := json.Marshal(&rpcTransactionWithReceipt{
+		tx:      &RPCTransaction{Hash: hash, Nonce: 7},
+		receipt: rpcReceiptFields(receipt, false),
+	})
+	if err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+	var output struct {
+		Hash    common.Hash `json:"hash"`
+		Nonce   string      `json:"nonce"`
+		Receipt struct {
+			Status          string            `json:"status"`
+			GasUsed         string            `json:"gasUsed"`
+			ContractAddress *common.Address   `json:"contractAddress"`
+			Logs            []json.RawMessage `json:"logs"`
+		} `json:"receipt"`
+	}
+	if err := json.Unmarshal(blob, &output); err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+	if output.Hash != hash || output.Nonce != "0x7" {
+		t.Errorf("transaction fields mismatch: %s", blob)
+	}
+	if r := output.Receipt; r.Status != "0x1" || r.GasUsed != "0x5208" || r.ContractAddress == nil || *r.ContractAddress != address || len(r.Logs) != 1 {
+		t.Errorf("receipt fields mismatch: %s", blob)
+	}
+}
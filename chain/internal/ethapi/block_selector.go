@@ -0,0 +1,40 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+var errNoBlockSelector = errors.New("invalid arguments; neither block number nor hash specified")
+
+//按区块高度或区块哈希得到区块状态和区块头，区块不存在时返回nil，
+//按哈希查询并要求主链区块(requireCanonical)时，哈希对应的区块已被分叉替换则返回错误
+func stateAndHeaderByNumberOrHash(ctx context.Context, b Backend, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+
+	if blockNr, ok := blockNrOrHash.Number(); ok {
+		return b.StateAndHeaderByNumber(ctx, blockNr)
+	}
+	hash, ok := blockNrOrHash.Hash()
+	if !ok {
+		return nil, nil, errNoBlockSelector
+	}
+	state, header, err := b.StateAndHeaderByHash(ctx, hash)
+	if header == nil || err != nil {
+		return nil, nil, err
+	}
+	if blockNrOrHash.RequireCanonical {
+		canonical, err := b.HeaderByNumber(ctx, rpc.BlockNumber(header.Number.Int64()))
+		if err != nil {
+			return nil, nil, err
+		}
+		if canonical == nil || canonical.Hash() != hash {
+			return nil, nil, protocol.ErrNonCanonicalBlock
+		}
+	}
+	return state, header, nil
+}
@@ -0,0 +1,388 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//提供Boker相关的公开查询API
+type PublicBokerAPI struct {
+	b Backend
+}
+
+func NewPublicBokerAPI(b Backend) *PublicBokerAPI {
+	return &PublicBokerAPI{b}
+}
+
+//股权分红的RPC输出格式
+type RPCStockPayout struct {
+	Account common.Address `json:"account"`
+	Amount  *hexutil.Big   `json:"amount"`
+}
+
+//区块奖励及Gas分配的RPC输出格式
+type RPCBlockRewards struct {
+	BlockHash           common.Hash      `json:"blockHash"`
+	BlockNumber         *hexutil.Big     `json:"blockNumber"`
+	Producer            common.Address   `json:"producer"`
+	ProducerReward      *hexutil.Big     `json:"producerReward"`
+	GasFees             *hexutil.Big     `json:"gasFees"`
+	GasPoolContribution *hexutil.Big     `json:"gasPoolContribution"`
+	StockPayouts        []RPCStockPayout `json:"stockPayouts"`
+}
+
+//得到指定区块的出块奖励、交易Gas费用、放入股权Gas池的数量以及股权分红
+func (s *PublicBokerAPI) GetBlockRewards(ctx context.Context, blockNr rpc.BlockNumber) (*RPCBlockRewards, error) {
+
+	if blockNr == rpc.PendingBlockNumber {
+		return nil, protocol.ErrUnknownBlock
+	}
+	header, err := s.b.HeaderByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, protocol.ErrUnknownBlock
+	}
+
+	hash := header.Hash()
+	rewards := core.GetBlockRewards(s.b.ChainDb(), hash, header.Number.Uint64())
+	if rewards == nil {
+		return nil, nil
+	}
+
+	payouts := make([]RPCStockPayout, len(rewards.StockPayouts))
+	for i, payout := range rewards.StockPayouts {
+		payouts[i] = RPCStockPayout{Account: payout.Account, Amount: (*hexutil.Big)(payout.Amount)}
+	}
+	return &RPCBlockRewards{
+		BlockHash:           hash,
+		BlockNumber:         (*hexutil.Big)(header.Number),
+		Producer:            rewards.Producer,
+		ProducerReward:      (*hexutil.Big)(rewards.ProducerReward),
+		GasFees:             (*hexutil.Big)(rewards.GasFees),
+		GasPoolContribution: (*hexutil.Big)(rewards.GasPoolContribution),
+		StockPayouts:        payouts,
+	}, nil
+}
+
+//基础合约变更记录的RPC输出格式
+type RPCBaseContractRecord struct {
+	Address     common.Address              `json:"address"`
+	Type        protocol.BaseContractType   `json:"type"`   //0:系统基础合约 1:用户基础合约
+	Action      protocol.BaseContractAction `json:"action"` //0:设置 1:取消 2:治理提案升级
+	From        common.Address              `json:"from"`
+	TxHash      *common.Hash                `json:"transactionHash"`
+	BlockNumber hexutil.Uint64              `json:"blockNumber"`
+}
+
+//基础合约的RPC输出格式
+type RPCBaseContracts struct {
+	BlockHash      common.Hash             `json:"blockHash"`
+	BlockNumber    *hexutil.Big            `json:"blockNumber"`
+	SystemContract *common.Address         `json:"systemContract"`
+	UserContracts  []common.Address        `json:"userContracts"`
+	History        []RPCBaseContractRecord `json:"history"`
+}
+
+//得到指定区块中已注册的系统基础合约和用户基础合约，以及它们的设置、取消历史
+func (s *PublicBokerAPI) GetBaseContracts(ctx context.Context, blockNr rpc.BlockNumber) (*RPCBaseContracts, error) {
+
+	if blockNr == rpc.PendingBlockNumber {
+		return nil, protocol.ErrUnknownBlock
+	}
+	header, err := s.b.HeaderByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, protocol.ErrUnknownBlock
+	}
+	bokerContext, err := types.NewBokerContextFromProto(s.b.ChainDb(), header.BokerProto)
+	if err != nil {
+		return nil, err
+	}
+
+	contracts, err := bokerContext.GetContracts()
+	if err != nil {
+		return nil, err
+	}
+	result := &RPCBaseContracts{
+		BlockHash:     header.Hash(),
+		BlockNumber:   (*hexutil.Big)(header.Number),
+		UserContracts: make([]common.Address, 0),
+		History:       make([]RPCBaseContractRecord, 0),
+	}
+	for _, address := range contracts {
+		contractType, ok := bokerContext.GetBaseContract(address)
+		if !ok {
+			continue
+		}
+		if contractType == protocol.System {
+			system := address
+			result.SystemContract = &system
+		} else {
+			result.UserContracts = append(result.UserContracts, address)
+		}
+	}
+
+	//合约的变更历史不参与共识，从本地数据库中读取
+	for _, record := range core.GetContractHistory(s.b.ChainDb(), header.Hash(), header.Number.Uint64()) {
+		item := RPCBaseContractRecord{
+			Address:     record.Address,
+			Type:        record.Type,
+			Action:      record.Action,
+			From:        record.From,
+			BlockNumber: hexutil.Uint64(record.BlockNumber),
+		}
+		if record.TxHash != (common.Hash{}) {
+			hash := record.TxHash
+			item.TxHash = &hash
+		}
+		result.History = append(result.History, item)
+	}
+	return result, nil
+}
+
+//待生效的系统基础合约变更的RPC输出格式
+type RPCPendingContract struct {
+	Address        common.Address   `json:"address"`
+	Proposer       common.Address   `json:"proposer"`
+	ProposedBlock  hexutil.Uint64   `json:"proposedBlock"`
+	EffectiveBlock hexutil.Uint64   `json:"effectiveBlock"`
+	Approvals      []common.Address `json:"approvals"`
+	Confirmable    bool             `json:"confirmable"` //是否已到达可以确认生效的区块
+}
+
+//得到指定区块中部署合约白名单内的账号
+func (s *PublicBokerAPI) GetDeployers(ctx context.Context, blockNr rpc.BlockNumber) ([]common.Address, error) {
+
+	if blockNr == rpc.PendingBlockNumber {
+		return nil, protocol.ErrUnknownBlock
+	}
+	header, err := s.b.HeaderByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, protocol.ErrUnknownBlock
+	}
+	bokerContext, err := types.NewBokerContextFromProto(s.b.ChainDb(), header.BokerProto)
+	if err != nil {
+		return nil, err
+	}
+	deployers := bokerContext.GetDeployers()
+	if deployers == nil {
+		deployers = []common.Address{}
+	}
+	return deployers, nil
+}
+
+//得到指定区块中验证者的出块签名账号(没有设置时为验证者自身)
+func (s *PublicBokerAPI) GetSigningKey(ctx context.Context, validator common.Address, blockNr rpc.BlockNumber) (common.Address, error) {
+
+	if blockNr == rpc.PendingBlockNumber {
+		return common.Address{}, protocol.ErrUnknownBlock
+	}
+	header, err := s.b.HeaderByNumber(ctx, blockNr)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if header == nil {
+		return common.Address{}, protocol.ErrUnknownBlock
+	}
+	bokerContext, err := types.NewBokerContextFromProto(s.b.ChainDb(), header.BokerProto)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return bokerContext.SigningKey(validator), nil
+}
+
+//得到指定区块中待生效的系统基础合约变更，没有则返回空
+func (s *PublicBokerAPI) GetPendingSystemContract(ctx context.Context, blockNr rpc.BlockNumber) (*RPCPendingContract, error) {
+
+	if blockNr == rpc.PendingBlockNumber {
+		return nil, protocol.ErrUnknownBlock
+	}
+	header, err := s.b.HeaderByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, protocol.ErrUnknownBlock
+	}
+	bokerContext, err := types.NewBokerContextFromProto(s.b.ChainDb(), header.BokerProto)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := bokerContext.GetPendingContract()
+	if err == protocol.ErrNoPendingContract {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &RPCPendingContract{
+		Address:        pending.Address,
+		Proposer:       pending.Proposer,
+		ProposedBlock:  hexutil.Uint64(pending.ProposedBlock),
+		EffectiveBlock: hexutil.Uint64(pending.EffectiveBlock),
+		Approvals:      pending.Approvals,
+		Confirmable:    header.Number.Uint64()+1 >= pending.EffectiveBlock,
+	}, nil
+}
+
+//登记合约元数据的参数
+type ContractMetaArgs struct {
+	Address common.Address `json:"address"`
+	Name    string         `json:"name"`
+	Abi     string         `json:"abi"`     //合约Abi（为空时只登记abiHash）
+	AbiHash *common.Hash   `json:"abiHash"` //Abi哈希（传入Abi时可以省略）
+	Source  string         `json:"source"`  //源码链接
+}
+
+//合约元数据的RPC输出格式
+type RPCContractMeta struct {
+	Address     common.Address `json:"address"`
+	Name        string         `json:"name"`
+	AbiHash     common.Hash    `json:"abiHash"`
+	Abi         string         `json:"abi"`
+	Source      string         `json:"source"`
+	Registrant  common.Address `json:"registrant"`
+	TxHash      common.Hash    `json:"transactionHash"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+}
+
+//使用当前挖矿账号提交一笔扩展交易，登记合约的名称、Abi哈希以及源码链接
+func (s *PublicBokerAPI) RegisterContractMeta(ctx context.Context, args ContractMetaArgs) (common.Hash, error) {
+
+	log.Info("(s *PublicBokerAPI) RegisterContractMeta", "address", args.Address.String(), "name", args.Name)
+
+	if next := new(big.Int).Add(s.b.CurrentBlock().Number(), big.NewInt(1)); !s.b.ChainConfig().IsContractMeta(next) {
+		return common.Hash{}, protocol.ErrContractMetaDisabled
+	}
+
+	meta := protocol.ContractMetadata{Name: args.Name, Abi: args.Abi, Source: args.Source}
+	if args.AbiHash != nil {
+		meta.AbiHash = *args.AbiHash
+	} else if args.Abi != "" {
+		meta.AbiHash = crypto.Keccak256Hash([]byte(args.Abi))
+	}
+	extra, err := rlp.EncodeToBytes(&meta)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if _, err := core.DecodeContractMeta(extra); err != nil {
+		return common.Hash{}, err
+	}
+	if int64(len(extra)) > protocol.MaxExtraSize {
+		return common.Hash{}, protocol.ErrInvalidContractMeta
+	}
+
+	from, err := s.b.Coinbase()
+	if err != nil {
+		log.Error("RegisterContractMeta CoinBase", "error", err)
+		return common.Hash{}, err
+	}
+	tx, err := s.b.Boker().SubmitBokerTransaction(ctx,
+		protocol.Extra,
+		protocol.ContractMeta,
+		from,
+		args.Address,
+		[]byte(args.Name),
+		extra,
+		new(big.Int).SetUint64(0),
+		0)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+//得到合约最近一次登记的元数据，没有登记则返回空
+func (s *PublicBokerAPI) GetContractMeta(ctx context.Context, address common.Address) (*RPCContractMeta, error) {
+
+	hash := core.GetContractMetaLookup(s.b.ChainDb(), address)
+	if hash == (common.Hash{}) {
+		return nil, nil
+	}
+	tx, _, number, _, err := s.b.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, nil
+	}
+	meta, err := core.DecodeContractMeta(tx.Extra())
+	if err != nil {
+		return nil, err
+	}
+	registrant, err := types.Sender(types.MakeSigner(s.b.ChainConfig(), new(big.Int).SetUint64(number)), tx)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCContractMeta{
+		Address:     address,
+		Name:        meta.Name,
+		AbiHash:     meta.AbiHash,
+		Abi:         meta.Abi,
+		Source:      meta.Source,
+		Registrant:  registrant,
+		TxHash:      hash,
+		BlockNumber: hexutil.Uint64(number),
+	}, nil
+}
+
+//返回包含Tina扩展字段(验证者、Dpos和Boker上下文)的区块, 不受以太坊兼容模式影响
+func (s *PublicBokerAPI) GetBlockByNumber(ctx context.Context, blockNr rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	var response map[string]interface{}
+	err := runWithTimeout(ctx, s.b, "boker_getBlockByNumber", func(ctx context.Context) error {
+		block, err := s.b.BlockByNumber(ctx, blockNr)
+		if block == nil {
+			return err
+		}
+		response, err = rpcOutputBlock(s.b, block, true, fullTx, false)
+		if err == nil && blockNr == rpc.PendingBlockNumber {
+			for _, field := range []string{"hash", "nonce", "validator"} {
+				response[field] = nil
+			}
+		}
+		return err
+	})
+	return response, err
+}
+
+//返回包含Tina扩展字段的区块, 不受以太坊兼容模式影响
+func (s *PublicBokerAPI) GetBlockByHash(ctx context.Context, blockHash common.Hash, fullTx bool) (map[string]interface{}, error) {
+	var response map[string]interface{}
+	err := runWithTimeout(ctx, s.b, "boker_getBlockByHash", func(ctx context.Context) error {
+		block, err := s.b.GetBlock(ctx, blockHash)
+		if block == nil {
+			return err
+		}
+		response, err = rpcOutputBlock(s.b, block, true, fullTx, false)
+		return err
+	})
+	return response, err
+}
+
+//返回包含Tina扩展字段(交易类型、名称、IP等)的交易, 不受以太坊兼容模式影响
+func (s *PublicBokerAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) *RPCTransaction {
+	return rpcTransactionByHash(s.b, hash)
+}
+
+//返回包含Tina扩展字段和执行错误信息的交易收据, 不受以太坊兼容模式影响
+func (s *PublicBokerAPI) GetTransactionReceipt(hash common.Hash) (map[string]interface{}, error) {
+	return rpcTransactionReceipt(s.b, hash, false)
+}
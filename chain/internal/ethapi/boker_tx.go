@@ -0,0 +1,178 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/params"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+//扩展交易和股权交易使用的固定Gas和Gas价格
+const (
+	BokerTxGas      = 90000
+	BokerTxGasPrice = 50 * params.Shannon
+)
+
+//按主要交易类型构造未签名的Boker交易。节点通过挖矿账号提交的交易和boker_buildSystemTx返回的交易使用相同的编码:
+//  SystemBase/UserBase: Gas和Gas价格使用protocol.MaxGasLimit/MaxGasPrice, extra作为交易数据(input)
+//  SystemBase/MultiSend: Gas为core.MultiSendGas, Gas价格为BokerTxGasPrice, extra作为交易数据(input)
+//  Extra/Stock:         Gas为BokerTxGas, Gas价格为BokerTxGasPrice, extra作为扩展数据, name和encryption原样保留
+func NewBokerTransaction(major protocol.TxMajor, minor protocol.TxMinor, nonce uint64, to common.Address, value *big.Int, name []byte, extra []byte, encryption uint8) (*types.Transaction, error) {
+
+	if value == nil {
+		value = new(big.Int)
+	}
+	switch major {
+	case protocol.SystemBase, protocol.UserBase:
+		if major == protocol.SystemBase && minor == protocol.MultiSend {
+			items, _, err := core.DecodeMultiSend(extra)
+			if err != nil {
+				return nil, err
+			}
+			return types.NewTransaction(major, minor, nonce, to, value,
+				core.MultiSendGas(extra, len(items)), new(big.Int).SetUint64(BokerTxGasPrice), extra), nil
+		}
+		return types.NewBaseTransaction(major, minor, nonce, to, value, extra), nil
+	case protocol.Extra:
+		return types.NewExtraTransaction(major, minor, nonce, to, value,
+			new(big.Int).SetUint64(BokerTxGas), new(big.Int).SetUint64(BokerTxGasPrice), name, extra, encryption), nil
+	case protocol.Stock:
+		return types.NewStockTransaction(major, minor, nonce, to, value,
+			new(big.Int).SetUint64(BokerTxGas), new(big.Int).SetUint64(BokerTxGasPrice), name, extra, encryption), nil
+	}
+	return nil, protocol.ErrUnknownMajor
+}
+
+//得到当前区块使用的交易签名者以及EIP155签名的链ID(未启用EIP155时为nil)
+func currentSigner(b Backend) (types.Signer, *big.Int) {
+	if config := b.ChainConfig(); config.IsEIP155(b.CurrentBlock().Number()) {
+		return types.NewEIP155Signer(config.ChainId), config.ChainId
+	}
+	return types.HomesteadSigner{}, nil
+}
+
+//得到恢复交易发送者使用的签名者, 离线签名的交易可能使用EIP155签名也可能使用V为27/28的签名
+func TxSigner(tx *types.Transaction) types.Signer {
+	if tx.Protected() {
+		return types.NewEIP155Signer(tx.ChainId())
+	}
+	return types.HomesteadSigner{}
+}
+
+//构造Boker交易的参数
+type BuildTxArgs struct {
+	From       common.Address   `json:"from"`  //签名账号, 用于得到默认的Nonce
+	Major      protocol.TxMajor `json:"major"` //SystemBase/UserBase/Extra/Stock
+	Minor      protocol.TxMinor `json:"minor"`
+	Nonce      *hexutil.Uint64  `json:"nonce"` //为空时使用交易池中的下一个Nonce
+	To         common.Address   `json:"to"`
+	Value      *hexutil.Big     `json:"value"`
+	Name       hexutil.Bytes    `json:"name"`
+	Extra      hexutil.Bytes    `json:"extra"` //系统交易的载荷(一般为RLP编码的参数)
+	Encryption uint8            `json:"encryption"`
+}
+
+//未签名的Boker交易
+type RPCUnsignedTransaction struct {
+	Tx          *RPCTransaction `json:"tx"`          //交易视图
+	Raw         hexutil.Bytes   `json:"raw"`         //未签名交易的RLP编码
+	SigningHash common.Hash     `json:"signingHash"` //需要签名的哈希
+	ChainId     *hexutil.Big    `json:"chainId"`     //EIP155签名使用的链ID, 为空时使用V为27/28的签名
+}
+
+//按参数构造未签名的Boker交易, Nonce为空时使用交易池中的下一个Nonce
+func (s *PublicBokerAPI) buildTx(ctx context.Context, args BuildTxArgs) (*types.Transaction, error) {
+
+	if args.Nonce == nil {
+		nonce, err := s.b.GetPoolNonce(ctx, args.From)
+		if err != nil {
+			return nil, err
+		}
+		args.Nonce = (*hexutil.Uint64)(&nonce)
+	}
+	tx, err := NewBokerTransaction(args.Major, args.Minor, uint64(*args.Nonce), args.To, (*big.Int)(args.Value), args.Name, args.Extra, args.Encryption)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Validate(); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+//返回未签名的Boker交易(系统交易、股权交易以及扩展交易), 供外部或离线签名者签名后通过eth_sendRawTransaction提交
+func (s *PublicBokerAPI) BuildSystemTx(ctx context.Context, args BuildTxArgs) (*RPCUnsignedTransaction, error) {
+
+	tx, err := s.buildTx(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	signer, chainId := currentSigner(s.b)
+
+	result := &RPCUnsignedTransaction{
+		Tx:          newRPCPendingTransaction(tx),
+		Raw:         raw,
+		SigningHash: signer.Hash(tx),
+		ChainId:     (*hexutil.Big)(chainId),
+	}
+	result.Tx.From = args.From
+	return result, nil
+}
+
+//Boker交易试执行的结果
+type RPCTxValidation struct {
+	Valid    bool         `json:"valid"`    //交易可以被打包并且执行成功
+	Reverted bool         `json:"reverted"` //交易可以被打包但合约执行失败
+	GasUsed  *hexutil.Big `json:"gasUsed"`  //试执行使用的Gas
+}
+
+//在当前区块之上试执行Boker交易(不需要签名也不提交)，交易会被拒绝时返回带有错误码的错误(所有权、股权余额、验证者身份以及负载大小等)，
+//供前端在提交治理等操作之前预先校验
+func (s *PublicBokerAPI) ValidateTransaction(ctx context.Context, args BuildTxArgs) (*RPCTxValidation, error) {
+
+	tx, err := s.buildTx(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	//与提交交易时相同的类型、接收地址以及负载大小检查
+	nonce, gas, price := hexutil.Uint64(tx.Nonce()), hexutil.Big(*tx.Gas()), hexutil.Big(*tx.GasPrice())
+	sendArgs := SendTxArgs{
+		From:       args.From,
+		To:         &args.To,
+		Gas:        &gas,
+		GasPrice:   &price,
+		Value:      (*hexutil.Big)(tx.Value()),
+		Data:       tx.Data(),
+		Extra:      tx.Extra(),
+		Nonce:      &nonce,
+		Major:      tx.Major(),
+		Minor:      tx.Minor(),
+		Name:       tx.Name(),
+		Encryption: tx.Encryption(),
+	}
+	if err := sendArgs.Validate(); err != nil {
+		return nil, err
+	}
+
+	receipt, err := s.b.DryRunTx(ctx, args.From, tx)
+	if err != nil {
+		return nil, err
+	}
+	failed := receipt.Status == types.ReceiptStatusFailed
+	return &RPCTxValidation{
+		Valid:    !failed,
+		Reverted: failed,
+		GasUsed:  (*hexutil.Big)(receipt.GasUsed),
+	}, nil
+}
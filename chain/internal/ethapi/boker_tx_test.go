@@ -0,0 +1,125 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/params"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+type buildTxTestBackend struct {
+	Backend
+	config *params.ChainConfig
+	head   *types.Block
+	nonce  uint64
+	err    error
+}
+
+func (b *buildTxTestBackend) ChainConfig() *params.ChainConfig { return b.config }
+func (b *buildTxTestBackend) CurrentBlock() *types.Block       { return b.head }
+
+func (b *buildTxTestBackend) GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error) {
+	return b.nonce, b.err
+}
+
+// Tests that unsigned Boker transactions are built with the gas rules of their
+// major type and the signing hash of the current signer, and that a signature
+// over that hash recovers the signing account.
+func TestBuildSystemTx(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		from    = crypto.PubkeyToAddress(key.PublicKey)
+		config  = *params.TestChainConfig
+		backend = &buildTxTestBackend{config: &config, head: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(10)}), nonce: 7}
+		api     = NewPublicBokerAPI(backend)
+	)
+	config.EIP155Block = big.NewInt(5)
+
+	//Nonce为空时使用交易池中的下一个Nonce
+	result, err := api.BuildSystemTx(context.Background(), BuildTxArgs{From: from, Major: protocol.Extra, Minor: protocol.Word, To: common.Address{0x01}, Name: []byte("name"), Extra: []byte("word")})
+	if err != nil {
+		t.Fatalf("failed to build extra transaction: %v", err)
+	}
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(result.Raw, tx); err != nil {
+		t.Fatalf("failed to decode raw transaction: %v", err)
+	}
+	if tx.Nonce() != 7 || tx.Gas().Uint64() != BokerTxGas || tx.GasPrice().Uint64() != BokerTxGasPrice || string(tx.Extra()) != "word" {
+		t.Errorf("extra transaction mismatch: nonce %d, gas %v, price %v, extra %q", tx.Nonce(), tx.Gas(), tx.GasPrice(), tx.Extra())
+	}
+	if result.Tx.From != from || result.Tx.Hash != tx.Hash() || result.ChainId.ToInt().Cmp(config.ChainId) != 0 {
+		t.Errorf("result mismatch: from %x, hash %x, chain id %v", result.Tx.From, result.Tx.Hash, result.ChainId)
+	}
+	signer := types.NewEIP155Signer(config.ChainId)
+	if result.SigningHash != signer.Hash(tx) {
+		t.Errorf("signing hash mismatch: have %x, want %x", result.SigningHash, signer.Hash(tx))
+	}
+	sig, _ := crypto.Sign(result.SigningHash.Bytes(), key)
+	signed, err := tx.WithSignature(signer, sig)
+	if err != nil {
+		t.Fatalf("failed to attach signature: %v", err)
+	}
+	if sender, err := types.Sender(TxSigner(signed), signed); err != nil || sender != from {
+		t.Errorf("sender mismatch: have %x, err %v, want %x", sender, err, from)
+	}
+
+	//EIP155之前使用V为27/28的签名
+	backend.head = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)})
+	nonce := hexutil.Uint64(2)
+	result, err = api.BuildSystemTx(context.Background(), BuildTxArgs{From: from, Major: protocol.SystemBase, Minor: protocol.SetValidator, Nonce: &nonce, To: common.Address{0x02}, Extra: []byte{0xc0}})
+	if err != nil {
+		t.Fatalf("failed to build system transaction: %v", err)
+	}
+	if err := rlp.DecodeBytes(result.Raw, tx); err != nil {
+		t.Fatalf("failed to decode raw transaction: %v", err)
+	}
+	if result.ChainId != nil || result.SigningHash != (types.HomesteadSigner{}).Hash(tx) {
+		t.Errorf("homestead signing mismatch: chain id %v, hash %x", result.ChainId, result.SigningHash)
+	}
+	if tx.Nonce() != 2 || tx.Gas().Cmp(protocol.MaxGasLimit) != 0 || tx.GasPrice().Cmp(protocol.MaxGasPrice) != 0 || len(tx.Data()) != 1 {
+		t.Errorf("system transaction mismatch: nonce %d, gas %v, price %v, data %x", tx.Nonce(), tx.Gas(), tx.GasPrice(), tx.Data())
+	}
+
+	items, _ := rlp.EncodeToBytes([]protocol.MultiSendItem{{Recipient: common.Address{0x03}, Amount: big.NewInt(1)}, {Recipient: common.Address{0x04}, Amount: big.NewInt(2)}})
+	result, err = api.BuildSystemTx(context.Background(), BuildTxArgs{From: from, Major: protocol.SystemBase, Minor: protocol.MultiSend, Nonce: &nonce, Extra: items})
+	if err != nil {
+		t.Fatalf("failed to build multi send transaction: %v", err)
+	}
+	if want := core.MultiSendGas(items, 2); result.Tx.Gas.ToInt().Cmp(want) != 0 {
+		t.Errorf("multi send gas mismatch: have %v, want %v", result.Tx.Gas, want)
+	}
+}
+
+// Tests that invalid Boker transactions are rejected before being built.
+func TestBuildSystemTxErrors(t *testing.T) {
+	errNonce := errors.New("nonce unavailable")
+	backend := &buildTxTestBackend{config: params.TestChainConfig, head: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)}), err: errNonce}
+	api := NewPublicBokerAPI(backend)
+
+	nonce := hexutil.Uint64(0)
+	tests := []struct {
+		args BuildTxArgs
+		err  error
+	}{
+		{BuildTxArgs{Major: protocol.Normal, Minor: protocol.NormalCall, Nonce: &nonce}, protocol.ErrUnknownMajor},
+		{BuildTxArgs{Major: protocol.SystemBase, Minor: protocol.MultiSend, Nonce: &nonce, Extra: []byte{0x01}}, protocol.ErrInvalidMultiSend},
+		{BuildTxArgs{Major: protocol.Extra, Minor: protocol.TxMinor(200), Nonce: &nonce}, nil},
+		{BuildTxArgs{Major: protocol.Extra, Minor: protocol.Word}, errNonce},
+	}
+	//错误为nil的用例只要求交易被拒绝
+	for i, tt := range tests {
+		_, err := api.BuildSystemTx(context.Background(), tt.args)
+		if err == nil || (tt.err != nil && err != tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
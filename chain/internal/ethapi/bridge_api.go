@@ -0,0 +1,182 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//提供跨链桥（锁定、释放以及查询锁定记录）的API
+type PublicBridgeAPI struct {
+	b Backend
+}
+
+func NewPublicBridgeAPI(b Backend) *PublicBridgeAPI {
+	return &PublicBridgeAPI{b}
+}
+
+//外部链上的一次销毁事件
+type BridgeReleaseArgs struct {
+	RemoteTx  common.Hash    `json:"remoteTx"`  //销毁事件所在的交易
+	LogIndex  hexutil.Uint64 `json:"logIndex"`  //销毁事件在区块中的日志序号
+	Recipient common.Address `json:"recipient"` //本链上的接收账号
+	Amount    *hexutil.Big   `json:"amount"`    //释放数量
+}
+
+func (args *BridgeReleaseArgs) toProtocol() (*protocol.BridgeReleaseArgs, error) {
+	if args.Amount == nil || args.Amount.ToInt().Sign() <= 0 {
+		return nil, protocol.ErrInvalidBridgeRelease
+	}
+	return &protocol.BridgeReleaseArgs{
+		RemoteTx:  args.RemoteTx,
+		LogIndex:  uint64(args.LogIndex),
+		Recipient: args.Recipient,
+		Amount:    args.Amount.ToInt(),
+	}, nil
+}
+
+//锁定记录的RPC输出格式
+type RPCBridgeLock struct {
+	Nonce     hexutil.Uint64 `json:"nonce"`
+	Sender    common.Address `json:"sender"`
+	Recipient common.Address `json:"recipient"`
+	Amount    *hexutil.Big   `json:"amount"`
+	Hash      common.Hash    `json:"hash"` //锁定记录的承诺哈希
+	Slot      common.Hash    `json:"slot"` //承诺哈希在跨链桥系统账号中的存储位置(用于eth_getProof)
+}
+
+//销毁事件释放状态的RPC输出格式
+type RPCBridgeRelease struct {
+	Id        common.Hash    `json:"id"`
+	Approvals hexutil.Uint64 `json:"approvals"` //已确认的验证者数量
+	Required  hexutil.Uint64 `json:"required"`  //释放需要的确认数量
+	Released  bool           `json:"released"`
+}
+
+//锁定from账号中的amount跨链转出到外部链上的recipient账号
+func (s *PublicBridgeAPI) Lock(ctx context.Context, from common.Address, recipient common.Address, amount hexutil.Big) (common.Hash, error) {
+
+	log.Info("(s *PublicBridgeAPI) Lock", "from", from, "recipient", recipient, "amount", amount.ToInt())
+
+	if next := new(big.Int).Add(s.b.CurrentBlock().Number(), big.NewInt(1)); !s.b.ChainConfig().IsBridge(next) {
+		return common.Hash{}, protocol.ErrBridgeDisabled
+	}
+	if amount.ToInt().Sign() <= 0 {
+		return common.Hash{}, protocol.ErrInvalidBridgeAmount
+	}
+	tx, err := s.b.Boker().SubmitBokerTransaction(ctx,
+		protocol.SystemBase,
+		protocol.BridgeLock,
+		from,
+		recipient,
+		[]byte(""),
+		[]byte(""),
+		amount.ToInt(),
+		0)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+//使用当前挖矿账号(验证者)确认外部链上的销毁事件，确认数达到要求后释放锁定的资产
+func (s *PublicBridgeAPI) Release(ctx context.Context, args BridgeReleaseArgs) (common.Hash, error) {
+
+	log.Info("(s *PublicBridgeAPI) Release", "remoteTx", args.RemoteTx, "logIndex", args.LogIndex, "recipient", args.Recipient)
+
+	if next := new(big.Int).Add(s.b.CurrentBlock().Number(), big.NewInt(1)); !s.b.ChainConfig().IsBridge(next) {
+		return common.Hash{}, protocol.ErrBridgeDisabled
+	}
+	release, err := args.toProtocol()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	from, err := s.b.Coinbase()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	data, err := rlp.EncodeToBytes(release)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tx, err := s.b.Boker().SubmitBokerTransaction(ctx,
+		protocol.SystemBase,
+		protocol.BridgeRelease,
+		from,
+		protocol.BridgeAddress,
+		[]byte(""),
+		data,
+		new(big.Int).SetUint64(0),
+		0)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+//得到指定区块中的锁定次数
+func (s *PublicBridgeAPI) GetLockCount(ctx context.Context, blockNr rpc.BlockNumber) (hexutil.Uint64, error) {
+
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(core.GetBridgeLockCount(state)), state.Error()
+}
+
+//得到指定区块中的锁定记录
+func (s *PublicBridgeAPI) GetLock(ctx context.Context, nonce hexutil.Uint64, blockNr rpc.BlockNumber) (*RPCBridgeLock, error) {
+
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	record, err := core.GetBridgeLock(state, uint64(nonce))
+	if err != nil {
+		return nil, err
+	}
+	return &RPCBridgeLock{
+		Nonce:     nonce,
+		Sender:    record.Sender,
+		Recipient: record.Recipient,
+		Amount:    (*hexutil.Big)(record.Amount),
+		Hash:      core.BridgeLockHash(record),
+		Slot:      core.BridgeLockSlot(record.Nonce),
+	}, state.Error()
+}
+
+//得到外部链销毁事件在指定区块中的确认以及释放状态
+func (s *PublicBridgeAPI) GetRelease(ctx context.Context, args BridgeReleaseArgs, blockNr rpc.BlockNumber) (*RPCBridgeRelease, error) {
+
+	release, err := args.toProtocol()
+	if err != nil {
+		return nil, err
+	}
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	dposContext, err := types.NewDposContextFromProto(s.b.ChainDb(), header.DposProto)
+	if err != nil {
+		return nil, err
+	}
+	validators, err := dposContext.GetEpochTrie()
+	if err != nil {
+		return nil, err
+	}
+	id := core.BridgeReleaseId(release)
+	return &RPCBridgeRelease{
+		Id:        id,
+		Approvals: hexutil.Uint64(core.GetBridgeApprovals(state, id)),
+		Required:  hexutil.Uint64(core.BridgeRequiredApprovals(len(validators))),
+		Released:  core.IsBridgeReleased(state, id),
+	}, state.Error()
+}
@@ -0,0 +1,38 @@
+package ethapi
+
+import (
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core/vm"
+)
+
+//合约调用失败时返回给客户端的错误，错误码与交易回执中的errorCode一致
+type callError struct {
+	code    uint
+	message string
+	data    interface{}
+}
+
+func (e *callError) Error() string          { return e.message }
+func (e *callError) ErrorCode() int         { return int(e.code) }
+func (e *callError) ErrorData() interface{} { return e.data }
+
+//回滚错误附带的数据
+type revertData struct {
+	Reason string        `json:"reason,omitempty"`
+	Data   hexutil.Bytes `json:"data"`
+}
+
+//根据虚拟机的执行错误以及返回数据创建调用错误
+func newCallError(ret []byte, vmerr error) *callError {
+
+	err := &callError{code: vm.ErrorCode(vmerr), message: vmerr.Error()}
+	if vmerr == vm.ErrExecutionReverted {
+
+		reason, ok := vm.UnpackRevert(ret)
+		if ok {
+			err.message += ": " + reason
+		}
+		err.data = &revertData{Reason: reason, Data: ret}
+	}
+	return err
+}
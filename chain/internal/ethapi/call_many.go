@@ -0,0 +1,133 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/common/math"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/vm"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//一组调用中单个调用失败的原因
+type CallManyError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+//一组调用中单个调用的执行结果
+type CallManyResult struct {
+	ReturnValue hexutil.Bytes  `json:"returnValue"`
+	GasUsed     *hexutil.Big   `json:"gasUsed"`
+	Error       *CallManyError `json:"error,omitempty"`
+}
+
+//状态值在执行前后的变化
+type ValueDiff struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+//帐户在执行前后的变化，只包含发生变化的字段
+type AccountDiff struct {
+	Balance *ValueDiff                 `json:"balance,omitempty"`
+	Nonce   *ValueDiff                 `json:"nonce,omitempty"`
+	Code    *ValueDiff                 `json:"code,omitempty"`
+	Storage map[common.Hash]*ValueDiff `json:"storage,omitempty"`
+}
+
+//一组调用的执行结果以及累计的状态变化
+type CallManyResults struct {
+	Results   []*CallManyResult               `json:"results"`
+	StateDiff map[common.Address]*AccountDiff `json:"stateDiff"`
+}
+
+//在指定区块上依次执行一组调用，每个调用在前一个调用修改后的状态上执行，用于交易提交前的多步模拟
+func (s *PublicBlockChainAPI) CallMany(ctx context.Context, calls []CallArgs, blockNrOrHash rpc.BlockNumberOrHash) (*CallManyResults, error) {
+
+	ctx, cancel := withRPCTimeout(ctx, s.b, "eth_callMany")
+	defer cancel()
+
+	statedb, header, err := stateAndHeaderByNumberOrHash(ctx, s.b, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	original := statedb.Copy()
+
+	//所有调用共享同一份上下文副本，使投票、注册等操作对后续调用可见
+	dposContext, bokerContext := core.CopyContexts(s.b.CurrentBlock())
+
+	results := &CallManyResults{Results: make([]*CallManyResult, len(calls))}
+	for i, args := range calls {
+
+		if ctx.Err() != nil {
+			return nil, contextError(ctx)
+		}
+		snapshot := statedb.Snapshot()
+		from := s.callSender(args.From)
+		balance := new(big.Int).Set(statedb.GetBalance(from))
+
+		res, gas, vmerr, err := s.applyCall(ctx, statedb, header, args, vm.Config{}, dposContext, bokerContext)
+		if err != nil {
+
+			statedb.RevertToSnapshot(snapshot)
+			results.Results[i] = &CallManyResult{GasUsed: (*hexutil.Big)(new(big.Int)), Error: &CallManyError{Code: -32000, Message: err.Error()}}
+			continue
+		}
+
+		//执行时发送者的余额被设置为最大值，这里恢复为真实余额扣除本次调用的花费
+		spent := new(big.Int).Sub(math.MaxBig256, statedb.GetBalance(from))
+		if balance.Cmp(spent) < 0 {
+
+			statedb.RevertToSnapshot(snapshot)
+			results.Results[i] = &CallManyResult{GasUsed: (*hexutil.Big)(new(big.Int)), Error: &CallManyError{Code: -32000, Message: core.ErrInsufficientFunds.Error()}}
+			continue
+		}
+		statedb.SetBalance(from, balance.Sub(balance, spent))
+
+		result := &CallManyResult{ReturnValue: res, GasUsed: (*hexutil.Big)(gas)}
+		if vmerr != nil {
+			callErr := newCallError(res, vmerr)
+			result.Error = &CallManyError{Code: callErr.ErrorCode(), Message: callErr.Error(), Data: callErr.ErrorData()}
+		}
+		results.Results[i] = result
+	}
+	results.StateDiff = stateDiff(original, statedb)
+	return results, nil
+}
+
+//比较执行前后的状态，得到发生变化的帐户
+func stateDiff(original, statedb *state.StateDB) map[common.Address]*AccountDiff {
+
+	diffs := make(map[common.Address]*AccountDiff)
+	for addr, keys := range statedb.DirtyStorage() {
+
+		diff := new(AccountDiff)
+		if from, to := original.GetBalance(addr), statedb.GetBalance(addr); from.Cmp(to) != 0 {
+			diff.Balance = &ValueDiff{From: (*hexutil.Big)(from), To: (*hexutil.Big)(to)}
+		}
+		if from, to := original.GetNonce(addr), statedb.GetNonce(addr); from != to {
+			diff.Nonce = &ValueDiff{From: hexutil.Uint64(from), To: hexutil.Uint64(to)}
+		}
+		if original.GetCodeHash(addr) != statedb.GetCodeHash(addr) {
+			diff.Code = &ValueDiff{From: hexutil.Bytes(original.GetCode(addr)), To: hexutil.Bytes(statedb.GetCode(addr))}
+		}
+		for _, key := range keys {
+			if from, to := original.GetState(addr, key), statedb.GetState(addr, key); from != to {
+				if diff.Storage == nil {
+					diff.Storage = make(map[common.Hash]*ValueDiff)
+				}
+				diff.Storage[key] = &ValueDiff{From: from, To: to}
+			}
+		}
+		if diff.Balance != nil || diff.Nonce != nil || diff.Code != nil || diff.Storage != nil {
+			diffs[addr] = diff
+		}
+	}
+	return diffs
+}
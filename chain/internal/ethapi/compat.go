@@ -0,0 +1,55 @@
+package ethapi
+
+import (
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+)
+
+//以太坊兼容模式下交易的RPC输出格式, 只包含标准的eth_*字段, Tina扩展的字段通过boker_*接口获取
+type RPCEthTransaction struct {
+	BlockHash        common.Hash     `json:"blockHash"`
+	BlockNumber      *hexutil.Big    `json:"blockNumber"`
+	From             common.Address  `json:"from"`
+	Gas              *hexutil.Big    `json:"gas"`
+	GasPrice         *hexutil.Big    `json:"gasPrice"`
+	Hash             common.Hash     `json:"hash"`
+	Input            hexutil.Bytes   `json:"input"`
+	Nonce            hexutil.Uint64  `json:"nonce"`
+	To               *common.Address `json:"to"`
+	TransactionIndex hexutil.Uint    `json:"transactionIndex"`
+	Value            *hexutil.Big    `json:"value"`
+	V                *hexutil.Big    `json:"v"`
+	R                *hexutil.Big    `json:"r"`
+	S                *hexutil.Big    `json:"s"`
+}
+
+//去掉Tina扩展的字段
+func newRPCEthTransaction(tx *RPCTransaction) *RPCEthTransaction {
+	return &RPCEthTransaction{
+		BlockHash:        tx.BlockHash,
+		BlockNumber:      tx.BlockNumber,
+		From:             tx.From,
+		Gas:              tx.Gas,
+		GasPrice:         tx.GasPrice,
+		Hash:             tx.Hash,
+		Input:            tx.Input,
+		Nonce:            tx.Nonce,
+		To:               tx.To,
+		TransactionIndex: tx.TransactionIndex,
+		Value:            tx.Value,
+		V:                tx.V,
+		R:                tx.R,
+		S:                tx.S,
+	}
+}
+
+//按输出模式得到交易的RPC输出, 交易不存在时返回nil
+func outputTransaction(tx *RPCTransaction, ethCompat bool) interface{} {
+	if tx == nil {
+		return nil
+	}
+	if ethCompat {
+		return newRPCEthTransaction(tx)
+	}
+	return tx
+}
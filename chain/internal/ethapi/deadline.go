@@ -0,0 +1,41 @@
+package ethapi
+
+import (
+	"context"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+)
+
+//为RPC方法设置执行期限，期限由节点配置决定(0为不限制)
+func withRPCTimeout(ctx context.Context, b Backend, method string) (context.Context, context.CancelFunc) {
+	if timeout := b.RPCTimeout(method); timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+//将超过执行期限转换为带错误码的协议错误
+func contextError(ctx context.Context) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return protocol.ErrRPCTimeout
+	}
+	return ctx.Err()
+}
+
+//在执行期限内执行fn，超过期限时立即返回，fn在后台执行完后丢弃结果，
+//避免不检查context的数据库读取长时间占用RPC处理
+func runWithTimeout(ctx context.Context, b Backend, method string, fn func(ctx context.Context) error) error {
+
+	ctx, cancel := withRPCTimeout(ctx, b, method)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- fn(ctx) }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return contextError(ctx)
+	}
+}
@@ -0,0 +1,126 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/accounts"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/consensus/dpos"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//提供委托奖励（出块节点的佣金比例以及投票者的委托奖励）的API
+type PublicDelegationAPI struct {
+	b Backend
+}
+
+func NewPublicDelegationAPI(b Backend) *PublicDelegationAPI {
+	return &PublicDelegationAPI{b}
+}
+
+//出块节点佣金比例的RPC输出格式
+type RPCCommission struct {
+	Producer   common.Address `json:"producer"`
+	Registered bool           `json:"registered"` //没有登记时出块奖励全部归出块节点
+	Percent    hexutil.Uint64 `json:"percent"`    //出块节点保留的比例（百分比）
+	Pending    *hexutil.Big   `json:"pending"`    //本周期内留给投票者、尚未分配的奖励
+}
+
+//账号委托奖励的RPC输出格式
+type RPCDelegationRewards struct {
+	Account common.Address `json:"account"`
+	Accrued *hexutil.Big   `json:"accrued"` //已分配、尚未领取的奖励
+	Claimed *hexutil.Big   `json:"claimed"` //累计已领取的奖励
+}
+
+//使用from账号(需要在本节点解锁)登记出块节点的佣金比例，出块奖励中其余的部分在每个周期结束时按投票权重分配给投票者
+func (s *PublicDelegationAPI) SetCommission(ctx context.Context, from common.Address, percent hexutil.Uint64) (common.Hash, error) {
+
+	log.Info("(s *PublicDelegationAPI) SetCommission", "from", from, "percent", percent)
+
+	if percent > 100 {
+		return common.Hash{}, protocol.ErrInvalidCommission
+	}
+	data, err := rlp.EncodeToBytes(uint64(percent))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.submit(ctx, protocol.SetCommission, from, data)
+}
+
+//使用from账号(需要在本节点解锁)领取全部已分配的委托奖励
+func (s *PublicDelegationAPI) ClaimRewards(ctx context.Context, from common.Address) (common.Hash, error) {
+
+	log.Info("(s *PublicDelegationAPI) ClaimRewards", "from", from)
+	return s.submit(ctx, protocol.ClaimRewards, from, nil)
+}
+
+//公开的接口不能代替任意账号签名交易，from必须是本节点已经解锁的账号
+func (s *PublicDelegationAPI) checkUnlocked(from common.Address) error {
+
+	account := accounts.Account{Address: from}
+	wallet, err := s.b.AccountManager().Find(account)
+	if err != nil {
+		return err
+	}
+	if _, err := wallet.SignHash(account, make([]byte, common.HashLength)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *PublicDelegationAPI) submit(ctx context.Context, minor protocol.TxMinor, from common.Address, data []byte) (common.Hash, error) {
+
+	if err := s.checkUnlocked(from); err != nil {
+		return common.Hash{}, err
+	}
+	tx, err := s.b.Boker().SubmitBokerTransaction(ctx,
+		protocol.SystemBase,
+		minor,
+		from,
+		protocol.DelegationAddress,
+		[]byte(""),
+		data,
+		new(big.Int).SetUint64(0),
+		0)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+//得到出块节点在指定区块中的佣金比例以及本周期内留给投票者的奖励
+func (s *PublicDelegationAPI) GetCommission(ctx context.Context, producer common.Address, blockNr rpc.BlockNumber) (*RPCCommission, error) {
+
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	percent, registered := dpos.GetCommission(state, producer)
+	return &RPCCommission{
+		Producer:   producer,
+		Registered: registered,
+		Percent:    hexutil.Uint64(percent),
+		Pending:    (*hexutil.Big)(dpos.GetPendingDelegation(state, producer)),
+	}, state.Error()
+}
+
+//得到账号在指定区块中已分配以及已领取的委托奖励
+func (s *PublicDelegationAPI) GetRewards(ctx context.Context, account common.Address, blockNr rpc.BlockNumber) (*RPCDelegationRewards, error) {
+
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	accrued, claimed := dpos.GetDelegationRewards(state, account)
+	return &RPCDelegationRewards{
+		Account: account,
+		Accrued: (*hexutil.Big)(accrued),
+		Claimed: (*hexutil.Big)(claimed),
+	}, state.Error()
+}
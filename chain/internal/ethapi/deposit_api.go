@@ -0,0 +1,316 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/accounts"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//跟踪的充值地址集合在数据库中的键
+var depositSetsKey = []byte("deposit-sets")
+
+//提供由扩展公钥派生的只读充值地址的跟踪以及余额、入账查询的API
+type PrivateDepositAPI struct {
+	b Backend
+}
+
+//创建API并恢复数据库中保存的充值地址集合
+func NewPrivateDepositAPI(b Backend) *PrivateDepositAPI {
+
+	api := &PrivateDepositAPI{b}
+	data, _ := b.ChainDb().Get(depositSetsKey)
+	if len(data) == 0 {
+		return api
+	}
+	var sets []accounts.DepositSet
+	if err := rlp.DecodeBytes(data, &sets); err != nil {
+		log.Error("Invalid deposit address sets", "err", err)
+		return api
+	}
+	for _, set := range sets {
+		if _, err := b.AccountManager().TrackDeposits(set); err != nil {
+			log.Warn("Failed to restore deposit address set", "name", set.Name, "err", err)
+		}
+	}
+	return api
+}
+
+//充值地址集合的RPC输出格式
+type RPCDepositSet struct {
+	Name      string               `json:"name"`
+	Xpub      string               `json:"xpub"`
+	Start     hexutil.Uint64       `json:"start"`
+	Count     hexutil.Uint64       `json:"count"`
+	Addresses []*RPCDepositAddress `json:"addresses,omitempty"`
+}
+
+//充值地址的RPC输出格式
+type RPCDepositAddress struct {
+	Index   hexutil.Uint64 `json:"index"` //子公钥序号
+	Address common.Address `json:"address"`
+	Balance *hexutil.Big   `json:"balance,omitempty"`
+}
+
+//充值地址集合的余额
+type RPCDepositBalances struct {
+	Name        string               `json:"name"`
+	BlockNumber hexutil.Uint64       `json:"blockNumber"`
+	BlockHash   common.Hash          `json:"blockHash"`
+	Total       *hexutil.Big         `json:"total"`
+	Addresses   []*RPCDepositAddress `json:"addresses"`
+}
+
+//充值地址收到的一笔转账
+type RPCDepositTransfer struct {
+	Kind            string         `json:"kind"` //transaction或internal
+	BlockNumber     hexutil.Uint64 `json:"blockNumber"`
+	BlockHash       common.Hash    `json:"blockHash"`
+	TransactionHash common.Hash    `json:"transactionHash"`
+	Index           hexutil.Uint64 `json:"index"` //接收地址的子公钥序号
+	From            common.Address `json:"from"`
+	To              common.Address `json:"to"`
+	Value           *hexutil.Big   `json:"value"`
+}
+
+//充值地址集合在区块范围内的入账
+type RPCDepositIncoming struct {
+	Name      string                `json:"name"`
+	FromBlock hexutil.Uint64        `json:"fromBlock"`
+	ToBlock   hexutil.Uint64        `json:"toBlock"`
+	Total     *hexutil.Big          `json:"total"`
+	Transfers []*RPCDepositTransfer `json:"transfers"`
+}
+
+func newRPCDepositSet(set accounts.DepositSet, addresses []accounts.DepositAddress) *RPCDepositSet {
+
+	result := &RPCDepositSet{
+		Name:  set.Name,
+		Xpub:  set.Xpub,
+		Start: hexutil.Uint64(set.Start),
+		Count: hexutil.Uint64(set.Count),
+	}
+	for _, address := range addresses {
+		result.Addresses = append(result.Addresses, &RPCDepositAddress{Index: hexutil.Uint64(address.Index), Address: address.Address})
+	}
+	return result
+}
+
+//将跟踪的充值地址集合写入数据库
+func (s *PrivateDepositAPI) store() error {
+
+	data, err := rlp.EncodeToBytes(s.b.AccountManager().DepositSets())
+	if err != nil {
+		return err
+	}
+	return s.b.ChainDb().Put(depositSetsKey, data)
+}
+
+//由扩展公钥派生子公钥序号为[start, start+count)的地址并开始跟踪，同名的集合会被替换
+func (s *PrivateDepositAPI) Track(name string, xpub string, start, count hexutil.Uint64) (*RPCDepositSet, error) {
+
+	if uint64(start) >= 1<<32 || uint64(count) >= 1<<32 {
+		return nil, accounts.ErrInvalidDepositSet
+	}
+	set := accounts.DepositSet{Name: name, Xpub: xpub, Start: uint32(start), Count: uint32(count)}
+	addresses, err := s.b.AccountManager().TrackDeposits(set)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store(); err != nil {
+		return nil, err
+	}
+	return newRPCDepositSet(set, addresses), nil
+}
+
+//停止跟踪充值地址集合
+func (s *PrivateDepositAPI) Untrack(name string) (bool, error) {
+
+	if !s.b.AccountManager().UntrackDeposits(name) {
+		return false, nil
+	}
+	return true, s.store()
+}
+
+//得到所有跟踪的充值地址集合(不包括地址)
+func (s *PrivateDepositAPI) List() []*RPCDepositSet {
+
+	sets := s.b.AccountManager().DepositSets()
+	result := make([]*RPCDepositSet, 0, len(sets))
+	for _, set := range sets {
+		result = append(result, newRPCDepositSet(set, nil))
+	}
+	return result
+}
+
+//得到充值地址集合中的地址
+func (s *PrivateDepositAPI) GetAddresses(name string) ([]*RPCDepositAddress, error) {
+
+	addresses, err := s.b.AccountManager().DepositAddresses(name)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*RPCDepositAddress, 0, len(addresses))
+	for _, address := range addresses {
+		result = append(result, &RPCDepositAddress{Index: hexutil.Uint64(address.Index), Address: address.Address})
+	}
+	return result, nil
+}
+
+//得到充值地址集合中每个地址在指定区块的余额以及余额之和
+func (s *PrivateDepositAPI) GetBalances(ctx context.Context, name string, blockNr rpc.BlockNumber) (*RPCDepositBalances, error) {
+
+	addresses, err := s.b.AccountManager().DepositAddresses(name)
+	if err != nil {
+		return nil, err
+	}
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	result := &RPCDepositBalances{
+		Name:        name,
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		BlockHash:   header.Hash(),
+		Addresses:   make([]*RPCDepositAddress, 0, len(addresses)),
+	}
+	total := new(big.Int)
+	for _, address := range addresses {
+		balance := state.GetBalance(address.Address)
+		if err := state.Error(); err != nil {
+			return nil, err
+		}
+		total.Add(total, balance)
+		result.Addresses = append(result.Addresses, &RPCDepositAddress{
+			Index:   hexutil.Uint64(address.Index),
+			Address: address.Address,
+			Balance: (*hexutil.Big)(balance),
+		})
+	}
+	result.Total = (*hexutil.Big)(total)
+	return result, nil
+}
+
+//得到充值地址集合在区块范围内收到的成功转账(包括内部交易，需要节点开启--internaltxindex)，
+//股权交易不计入
+func (s *PrivateDepositAPI) GetIncoming(ctx context.Context, name string, fromBlock, toBlock rpc.BlockNumber) (*RPCDepositIncoming, error) {
+
+	addresses, err := s.b.AccountManager().DepositAddresses(name)
+	if err != nil {
+		return nil, err
+	}
+	from, to, err := activityRange(s.b, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make(map[common.Address]uint32, len(addresses))
+	internals := make(map[common.Hash]bool)
+	for _, address := range addresses {
+		indexes[address.Address] = address.Index
+		for _, ref := range core.GetInternalTxRefs(s.b.ChainDb(), address.Address) {
+			if ref.BlockNumber >= from && ref.BlockNumber <= to {
+				internals[ref.TxHash] = true
+			}
+		}
+	}
+
+	result := &RPCDepositIncoming{
+		Name:      name,
+		FromBlock: hexutil.Uint64(from),
+		ToBlock:   hexutil.Uint64(to),
+		Transfers: []*RPCDepositTransfer{},
+	}
+	total := new(big.Int)
+	for number := from; number <= to; number++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		block, err := s.b.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			break
+		}
+		transfers, err := s.blockIncoming(ctx, block, indexes, internals)
+		if err != nil {
+			return nil, err
+		}
+		for _, transfer := range transfers {
+			total.Add(total, transfer.Value.ToInt())
+		}
+		result.Transfers = append(result.Transfers, transfers...)
+	}
+	result.Total = (*hexutil.Big)(total)
+	return result, nil
+}
+
+//得到单个区块中充值地址收到的转账
+func (s *PrivateDepositAPI) blockIncoming(ctx context.Context, block *types.Block, indexes map[common.Address]uint32, internals map[common.Hash]bool) ([]*RPCDepositTransfer, error) {
+
+	var (
+		transfers []*RPCDepositTransfer
+		receipts  types.Receipts
+		hash      = block.Hash()
+		number    = hexutil.Uint64(block.NumberU64())
+	)
+	for i, tx := range block.Transactions() {
+
+		txHash := tx.Hash()
+		if tx.To() != nil && tx.Major() != protocol.Stock && tx.Value().Sign() > 0 {
+			if index, ok := indexes[*tx.To()]; ok {
+
+				if receipts == nil {
+					var err error
+					if receipts, err = s.b.GetReceipts(ctx, hash); err != nil {
+						return nil, err
+					}
+				}
+				failed := i < len(receipts) && len(receipts[i].PostState) == 0 && receipts[i].Status == types.ReceiptStatusFailed
+				if !failed {
+					sender, _ := types.Sender(TxSigner(tx), tx)
+					transfers = append(transfers, &RPCDepositTransfer{
+						Kind:            ActivityTransaction,
+						BlockNumber:     number,
+						BlockHash:       hash,
+						TransactionHash: txHash,
+						Index:           hexutil.Uint64(index),
+						From:            sender,
+						To:              *tx.To(),
+						Value:           (*hexutil.Big)(tx.Value()),
+					})
+				}
+			}
+		}
+
+		if !internals[txHash] {
+			continue
+		}
+		for _, itx := range core.GetInternalTxs(s.b.ChainDb(), txHash) {
+			index, ok := indexes[itx.To]
+			if !ok || itx.Failed || itx.Value == nil || itx.Value.Sign() <= 0 {
+				continue
+			}
+			transfers = append(transfers, &RPCDepositTransfer{
+				Kind:            ActivityInternal,
+				BlockNumber:     number,
+				BlockHash:       hash,
+				TransactionHash: txHash,
+				Index:           hexutil.Uint64(index),
+				From:            itx.From,
+				To:              itx.To,
+				Value:           (*hexutil.Big)(itx.Value),
+			})
+		}
+	}
+	return transfers, nil
+}
@@ -0,0 +1,99 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//账号累计注入股权Gas池数量的RPC输出格式
+type RPCGasPoolContribution struct {
+	Account common.Address `json:"account"`
+	Amount  *hexutil.Big   `json:"amount"`
+}
+
+//股权Gas池注资情况的RPC输出格式
+type RPCGasPoolFunding struct {
+	GasPool      *hexutil.Big              `json:"gasPool"`      //股权Gas池中等待分配的数量
+	Total        *hexutil.Big              `json:"total"`        //所有账号累计注入的数量
+	Contributors []*RPCGasPoolContribution `json:"contributors"` //按地址排序
+}
+
+//使用from账号向股权Gas池注入资金，注入的资金从账号中扣除，股权分币时按股权发放给股权持有者
+func (s *PublicBokerAPI) FundGasPool(ctx context.Context, from common.Address, amount *hexutil.Big) (common.Hash, error) {
+
+	log.Info("(s *PublicBokerAPI) FundGasPool", "from", from, "amount", amount)
+
+	if next := new(big.Int).Add(s.b.CurrentBlock().Number(), big.NewInt(1)); !s.b.ChainConfig().IsGasPoolFund(next) {
+		return common.Hash{}, protocol.ErrGasPoolFundingDisabled
+	}
+	if amount == nil || amount.ToInt().Sign() <= 0 || !amount.ToInt().IsUint64() {
+		return common.Hash{}, protocol.ErrInvalidGasPoolFunding
+	}
+	tx, err := s.b.Boker().SubmitBokerTransaction(ctx,
+		protocol.SystemBase,
+		protocol.FundGasPool,
+		from,
+		protocol.GasPoolAddress,
+		[]byte(""),
+		[]byte(""),
+		new(big.Int).Set(amount.ToInt()),
+		0)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+func (s *PublicBokerAPI) bokerContextAt(ctx context.Context, blockNr rpc.BlockNumber) (*types.BokerContext, error) {
+
+	if blockNr == rpc.PendingBlockNumber {
+		return nil, protocol.ErrUnknownBlock
+	}
+	header, err := s.b.HeaderByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, protocol.ErrUnknownBlock
+	}
+	return types.NewBokerContextFromProto(s.b.ChainDb(), header.BokerProto)
+}
+
+//得到账号在指定区块中累计注入股权Gas池的数量
+func (s *PublicBokerAPI) GetGasPoolContribution(ctx context.Context, account common.Address, blockNr rpc.BlockNumber) (*hexutil.Big, error) {
+
+	bokerContext, err := s.bokerContextAt(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(bokerContext.GasPoolContribution(account)), nil
+}
+
+//得到指定区块中股权Gas池的数量以及所有账号累计注入的数量
+func (s *PublicBokerAPI) GetGasPoolFunding(ctx context.Context, blockNr rpc.BlockNumber) (*RPCGasPoolFunding, error) {
+
+	bokerContext, err := s.bokerContextAt(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	accounts, amounts := bokerContext.GasPoolContributions()
+
+	total := new(big.Int)
+	contributors := make([]*RPCGasPoolContribution, 0, len(accounts))
+	for i, account := range accounts {
+		total.Add(total, amounts[i])
+		contributors = append(contributors, &RPCGasPoolContribution{Account: account, Amount: (*hexutil.Big)(amounts[i])})
+	}
+	return &RPCGasPoolFunding{
+		GasPool:      (*hexutil.Big)(new(big.Int).SetUint64(bokerContext.GetGasPool())),
+		Total:        (*hexutil.Big)(total),
+		Contributors: contributors,
+	}, nil
+}
@@ -0,0 +1,192 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//提供链上治理（提案、投票、查询）的API
+type PublicGovAPI struct {
+	b Backend
+}
+
+func NewPublicGovAPI(b Backend) *PublicGovAPI {
+	return &PublicGovAPI{b}
+}
+
+//发起提案的参数
+type GovProposalArgs struct {
+	Kind           protocol.ProposalKind `json:"kind"`           //0:调整出块奖励 1:升级系统基础合约
+	Weight         protocol.VoteWeight   `json:"weight"`         //0:按股权投票（权重在提案产生时快照，不再接受按余额投票）
+	Description    string                `json:"description"`    //提案说明
+	Target         *common.Address       `json:"target"`         //升级后的系统基础合约地址
+	Reward         *hexutil.Big          `json:"reward"`         //新的区块总奖励（单位为TinaUnit）
+	GasPoolPercent hexutil.Uint64        `json:"gasPoolPercent"` //新的股权Gas池比例
+}
+
+//提案的RPC输出格式
+type RPCProposal struct {
+	Id             hexutil.Uint64         `json:"id"`
+	Kind           protocol.ProposalKind  `json:"kind"`
+	Weight         protocol.VoteWeight    `json:"weight"`
+	Proposer       common.Address         `json:"proposer"`
+	Description    string                 `json:"description"`
+	Target         common.Address         `json:"target"`
+	Reward         *hexutil.Big           `json:"reward"`
+	GasPoolPercent hexutil.Uint64         `json:"gasPoolPercent"`
+	CreatedEpoch   hexutil.Uint64         `json:"createdEpoch"`
+	EndEpoch       hexutil.Uint64         `json:"endEpoch"`
+	Yes            *hexutil.Big           `json:"yes"`
+	No             *hexutil.Big           `json:"no"`
+	Total          *hexutil.Big           `json:"total"` //提案产生时快照的全部投票权重
+	State          protocol.ProposalState `json:"state"`
+}
+
+func newRPCProposal(p *protocol.Proposal) *RPCProposal {
+	return &RPCProposal{
+		Id:             hexutil.Uint64(p.Id),
+		Kind:           p.Kind,
+		Weight:         p.Weight,
+		Proposer:       p.Proposer,
+		Description:    p.Description,
+		Target:         p.Target,
+		Reward:         (*hexutil.Big)(p.Reward),
+		GasPoolPercent: hexutil.Uint64(p.GasPoolPercent),
+		CreatedEpoch:   hexutil.Uint64(p.CreatedEpoch),
+		EndEpoch:       hexutil.Uint64(p.EndEpoch),
+		Yes:            (*hexutil.Big)(p.Yes),
+		No:             (*hexutil.Big)(p.No),
+		Total:          (*hexutil.Big)(p.Total),
+		State:          p.State,
+	}
+}
+
+//使用当前挖矿账号提交一笔治理交易
+func (s *PublicGovAPI) submit(ctx context.Context, minor protocol.TxMinor, payload interface{}) (common.Hash, error) {
+
+	from, err := s.b.Coinbase()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	data, err := rlp.EncodeToBytes(payload)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tx, err := s.b.Boker().SubmitBokerTransaction(ctx,
+		protocol.SystemBase,
+		minor,
+		from,
+		from,
+		[]byte(""),
+		data,
+		new(big.Int).SetUint64(0),
+		0)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+//发起治理提案，投票在下一个周期开始时结束并计票，达到法定人数并通过的提案生效
+func (s *PublicGovAPI) Propose(ctx context.Context, args GovProposalArgs) (common.Hash, error) {
+
+	log.Info("(s *PublicGovAPI) Propose", "kind", args.Kind, "weight", args.Weight)
+
+	if args.Weight != protocol.WeightStock {
+		return common.Hash{}, protocol.ErrInvalidProposal
+	}
+	proposal := protocol.ProposalArgs{
+		Kind:           args.Kind,
+		Weight:         args.Weight,
+		Description:    args.Description,
+		Reward:         new(big.Int),
+		GasPoolPercent: uint64(args.GasPoolPercent),
+	}
+	switch args.Kind {
+	case protocol.ParamChange:
+		if args.Reward == nil || args.Reward.ToInt().Sign() < 0 || args.GasPoolPercent > 100 {
+			return common.Hash{}, protocol.ErrInvalidProposal
+		}
+		proposal.Reward = args.Reward.ToInt()
+	case protocol.ContractUpgrade:
+		if args.Target == nil || *args.Target == (common.Address{}) {
+			return common.Hash{}, protocol.ErrInvalidProposal
+		}
+		proposal.Target = *args.Target
+	default:
+		return common.Hash{}, protocol.ErrInvalidProposal
+	}
+	return s.submit(ctx, protocol.GovPropose, &proposal)
+}
+
+//对治理提案投票
+func (s *PublicGovAPI) Vote(ctx context.Context, id hexutil.Uint64, approve bool) (common.Hash, error) {
+
+	log.Info("(s *PublicGovAPI) Vote", "id", id, "approve", approve)
+
+	//提交前检查提案状态，使客户端能够立即得到对应的错误码
+	if bokerContext := s.b.CurrentBlock().BokerCtx(); bokerContext != nil {
+
+		proposal, err := bokerContext.GetProposal(uint64(id))
+		if err != nil {
+			return common.Hash{}, protocol.ErrUnknownProposal
+		}
+		if proposal.State != protocol.ProposalVoting {
+			return common.Hash{}, protocol.ErrProposalClosed
+		}
+		if from, err := s.b.Coinbase(); err == nil && bokerContext.HasVoted(uint64(id), from) {
+			return common.Hash{}, protocol.ErrAlreadyVoted
+		}
+	}
+	return s.submit(ctx, protocol.GovVote, &protocol.VoteArgs{Id: uint64(id), Approve: approve})
+}
+
+//得到指定区块的Boker上下文
+func (s *PublicGovAPI) bokerContextAt(ctx context.Context, blockNr rpc.BlockNumber) (*types.BokerContext, error) {
+
+	header, err := s.b.HeaderByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, protocol.ErrUnknownBlock
+	}
+	return types.NewBokerContextFromProto(s.b.ChainDb(), header.BokerProto)
+}
+
+//得到指定区块中的提案
+func (s *PublicGovAPI) GetProposal(ctx context.Context, id hexutil.Uint64, blockNr rpc.BlockNumber) (*RPCProposal, error) {
+
+	bokerContext, err := s.bokerContextAt(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	proposal, err := bokerContext.GetProposal(uint64(id))
+	if err != nil {
+		return nil, err
+	}
+	return newRPCProposal(proposal), nil
+}
+
+//得到指定区块中的所有提案
+func (s *PublicGovAPI) GetProposals(ctx context.Context, blockNr rpc.BlockNumber) ([]*RPCProposal, error) {
+
+	bokerContext, err := s.bokerContextAt(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	proposals := bokerContext.GetProposals()
+	result := make([]*RPCProposal, len(proposals))
+	for i, proposal := range proposals {
+		result[i] = newRPCProposal(proposal)
+	}
+	return result, nil
+}
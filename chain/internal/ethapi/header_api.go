@@ -0,0 +1,98 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//单次最多返回的区块头数量
+const maxHeadersRange = 1000
+
+var (
+	errHeadersRange    = errors.New("invalid header range")
+	errHeadersTooLarge = errors.New("header range too large")
+)
+
+//精简的区块头，包含校验出块者和Dpos/Boker状态所需的字段(extraData的最后65字节为出块签名)
+type RPCCompactHeader struct {
+	Number           hexutil.Uint64           `json:"number"`
+	Hash             common.Hash              `json:"hash"`
+	ParentHash       common.Hash              `json:"parentHash"`
+	StateRoot        common.Hash              `json:"stateRoot"`
+	TransactionsRoot common.Hash              `json:"transactionsRoot"`
+	ReceiptsRoot     common.Hash              `json:"receiptsRoot"`
+	Timestamp        hexutil.Uint64           `json:"timestamp"`
+	Validator        common.Address           `json:"validator"`
+	Coinbase         common.Address           `json:"coinbase"`
+	DposContext      *types.DposContextProto  `json:"dposContext"`
+	BokerBackend     *types.BokerBackendProto `json:"bokerBackend"`
+	ExtraData        hexutil.Bytes            `json:"extraData"`
+}
+
+//将区块头转换为精简的RPC输出
+func RPCMarshalCompactHeader(head *types.Header) *RPCCompactHeader {
+	dposProto, bokerProto := head.DposProto, head.BokerProto
+	if dposProto == nil {
+		dposProto = &types.DposContextProto{}
+	}
+	if bokerProto == nil {
+		bokerProto = &types.BokerBackendProto{}
+	}
+	return &RPCCompactHeader{
+		Number:           hexutil.Uint64(head.Number.Uint64()),
+		Hash:             head.Hash(),
+		ParentHash:       head.ParentHash,
+		StateRoot:        head.Root,
+		TransactionsRoot: head.TxHash,
+		ReceiptsRoot:     head.ReceiptHash,
+		Timestamp:        hexutil.Uint64(head.Time.Uint64()),
+		Validator:        head.Validator,
+		Coinbase:         head.Coinbase,
+		DposContext:      dposProto,
+		BokerBackend:     bokerProto,
+		ExtraData:        hexutil.Bytes(head.Extra),
+	}
+}
+
+//返回[from, to]范围内规范链的精简区块头(最多1000个)，超过当前区块的部分不返回
+func (s *PublicBlockChainAPI) GetHeadersByRange(ctx context.Context, from, to rpc.BlockNumber) ([]*RPCCompactHeader, error) {
+
+	if from == rpc.PendingBlockNumber || to == rpc.PendingBlockNumber {
+		return nil, errHeadersRange
+	}
+	head, err := s.b.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	start, end := uint64(from), uint64(to)
+	if from == rpc.LatestBlockNumber {
+		start = head.Number.Uint64()
+	}
+	if to == rpc.LatestBlockNumber || end > head.Number.Uint64() {
+		end = head.Number.Uint64()
+	}
+	if start > end {
+		return nil, errHeadersRange
+	}
+	if end-start >= maxHeadersRange {
+		return nil, errHeadersTooLarge
+	}
+
+	headers := make([]*RPCCompactHeader, 0, end-start+1)
+	for number := start; number <= end; number++ {
+		header, err := s.b.HeaderByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			break
+		}
+		headers = append(headers, RPCMarshalCompactHeader(header))
+	}
+	return headers, nil
+}
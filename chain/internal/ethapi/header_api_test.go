@@ -0,0 +1,68 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+// Tests that the headers of a range are returned in the compact form, clamped
+// to the current head, and that invalid and oversized ranges are rejected.
+func TestGetHeadersByRange(t *testing.T) {
+	backend := &statsTestBackend{}
+	for i := 0; i < maxHeadersRange+10; i++ {
+		backend.headers = append(backend.headers, &types.Header{Number: big.NewInt(int64(i)), Time: big.NewInt(int64(10 * i)), Validator: common.BigToAddress(big.NewInt(int64(i)))})
+	}
+	backend.headers[5].BokerProto = &types.BokerBackendProto{StocksHash: common.HexToHash("0x03")}
+	api := NewPublicBlockChainAPI(backend)
+
+	headers, err := api.GetHeadersByRange(context.Background(), 3, 6)
+	if err != nil {
+		t.Fatalf("failed to get headers: %v", err)
+	}
+	if len(headers) != 4 {
+		t.Fatalf("headers mismatch: have %d, want 4", len(headers))
+	}
+	for i, header := range headers {
+		want := backend.headers[3+i]
+		if header.Number != hexutil.Uint64(3+i) || header.Hash != want.Hash() || header.Validator != want.Validator || uint64(header.Timestamp) != want.Time.Uint64() {
+			t.Errorf("header %d mismatch: %+v", 3+i, header)
+		}
+		if header.DposContext == nil || header.BokerBackend == nil {
+			t.Errorf("header %d: missing consensus contexts", 3+i)
+		}
+	}
+	if headers[2].BokerBackend.StocksHash != common.HexToHash("0x03") {
+		t.Errorf("boker context mismatch: %+v", headers[2].BokerBackend)
+	}
+
+	//超过当前区块的部分截断
+	head := rpc.BlockNumber(len(backend.headers) - 1)
+	if headers, err := api.GetHeadersByRange(context.Background(), head-1, head+100); err != nil || len(headers) != 2 {
+		t.Errorf("clamped range: have %d headers, err %v", len(headers), err)
+	}
+	if headers, err := api.GetHeadersByRange(context.Background(), rpc.LatestBlockNumber, rpc.LatestBlockNumber); err != nil || len(headers) != 1 || int64(headers[0].Number) != int64(head) {
+		t.Errorf("latest header: have %v, err %v", headers, err)
+	}
+
+	tests := []struct {
+		from, to rpc.BlockNumber
+		err      error
+	}{
+		{rpc.PendingBlockNumber, 5, errHeadersRange},
+		{0, rpc.PendingBlockNumber, errHeadersRange},
+		{6, 3, errHeadersRange},
+		{rpc.LatestBlockNumber, 3, errHeadersRange},
+		{0, maxHeadersRange, errHeadersTooLarge},
+	}
+	for i, tt := range tests {
+		if _, err := api.GetHeadersByRange(context.Background(), tt.from, tt.to); err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
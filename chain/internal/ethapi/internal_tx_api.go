@@ -0,0 +1,67 @@
+package ethapi
+
+import (
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+)
+
+//内部交易的RPC输出格式
+type RPCInternalTx struct {
+	Type   string         `json:"type"`
+	From   common.Address `json:"from"`
+	To     common.Address `json:"to"`
+	Value  *hexutil.Big   `json:"value"`
+	Depth  hexutil.Uint64 `json:"depth"`
+	Failed bool           `json:"failed"`
+}
+
+//交易以及其产生的内部交易
+type RPCInternalTxs struct {
+	TransactionHash      common.Hash      `json:"transactionHash"`
+	BlockNumber          hexutil.Uint64   `json:"blockNumber"`
+	InternalTransactions []*RPCInternalTx `json:"internalTransactions"`
+}
+
+func newRPCInternalTx(itx *types.InternalTx) *RPCInternalTx {
+	return &RPCInternalTx{
+		Type:   itx.Type,
+		From:   itx.From,
+		To:     itx.To,
+		Value:  (*hexutil.Big)(itx.Value),
+		Depth:  hexutil.Uint64(itx.Depth),
+		Failed: itx.Failed,
+	}
+}
+
+//得到交易执行过程中产生的内部交易（需要节点开启--internaltxindex）
+func (s *PublicTransactionPoolAPI) GetInternalTransactions(hash common.Hash) []*RPCInternalTx {
+
+	itxs := core.GetInternalTxs(s.b.ChainDb(), hash)
+	result := make([]*RPCInternalTx, len(itxs))
+	for i, itx := range itxs {
+		result[i] = newRPCInternalTx(itx)
+	}
+	return result
+}
+
+//得到与地址相关的内部交易，只返回仍在主链上的交易
+func (s *PublicTransactionPoolAPI) GetInternalTransactionsByAddress(address common.Address) []*RPCInternalTxs {
+
+	result := make([]*RPCInternalTxs, 0)
+	for _, ref := range core.GetInternalTxRefs(s.b.ChainDb(), address) {
+
+		if tx, _, number, _ := core.GetTransaction(s.b.ChainDb(), ref.TxHash); tx == nil || number != ref.BlockNumber {
+			continue
+		}
+		txs := &RPCInternalTxs{TransactionHash: ref.TxHash, BlockNumber: hexutil.Uint64(ref.BlockNumber)}
+		for _, itx := range core.GetInternalTxs(s.b.ChainDb(), ref.TxHash) {
+			if itx.From == address || itx.To == address {
+				txs.InternalTransactions = append(txs.InternalTransactions, newRPCInternalTx(itx))
+			}
+		}
+		result = append(result, txs)
+	}
+	return result
+}
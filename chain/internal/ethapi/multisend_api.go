@@ -0,0 +1,80 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+//批量转账中的一笔转账
+type MultiSendRecipient struct {
+	To    common.Address `json:"to"`
+	Value *hexutil.Big   `json:"value"`
+}
+
+//编码并校验批量转账交易负载
+func encodeMultiSend(recipients []MultiSendRecipient) ([]byte, error) {
+
+	items := make([]protocol.MultiSendItem, 0, len(recipients))
+	for _, recipient := range recipients {
+		items = append(items, protocol.MultiSendItem{Recipient: recipient.To, Amount: (*big.Int)(recipient.Value)})
+	}
+	data, err := rlp.EncodeToBytes(items)
+	if err != nil {
+		return nil, protocol.ErrInvalidMultiSend
+	}
+	if _, _, err := core.DecodeMultiSend(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+//使用from账号(需要解锁)发送批量转账交易，所有转账在同一笔交易中一起生效
+func (s *PublicBokerAPI) MultiSend(ctx context.Context, from common.Address, recipients []MultiSendRecipient) (common.Hash, error) {
+
+	log.Info("(s *PublicBokerAPI) MultiSend", "from", from, "recipients", len(recipients))
+
+	if next := new(big.Int).Add(s.b.CurrentBlock().Number(), big.NewInt(1)); !s.b.ChainConfig().IsMultiSend(next) {
+		return common.Hash{}, protocol.ErrMultiSendDisabled
+	}
+	data, err := encodeMultiSend(recipients)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tx, err := s.b.Boker().SubmitBokerTransaction(ctx,
+		protocol.SystemBase,
+		protocol.MultiSend,
+		from,
+		protocol.MultiSendAddress,
+		[]byte(""),
+		data,
+		new(big.Int).SetUint64(0),
+		0)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+//返回未签名的批量转账交易，供外部或离线签名者签名，nonce为空时使用交易池中的下一个Nonce
+func (s *PublicBokerAPI) BuildMultiSend(ctx context.Context, from common.Address, recipients []MultiSendRecipient, nonce *hexutil.Uint64) (*RPCUnsignedTransaction, error) {
+
+	data, err := encodeMultiSend(recipients)
+	if err != nil {
+		return nil, err
+	}
+	return s.BuildSystemTx(ctx, BuildTxArgs{
+		From:  from,
+		Major: protocol.SystemBase,
+		Minor: protocol.MultiSend,
+		Nonce: nonce,
+		To:    protocol.MultiSendAddress,
+		Extra: data,
+	})
+}
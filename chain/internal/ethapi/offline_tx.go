@@ -0,0 +1,205 @@
+package ethapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/Tinachain/Tina/chain/accounts"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+//离线交易包的前缀(包含格式版本)
+const OfflineTxPrefix = "tinatx1:"
+
+var (
+	errOfflineTxPayload   = errors.New("invalid offline transaction payload")
+	errOfflineTxChecksum  = errors.New("offline transaction payload checksum mismatch")
+	errOfflineTxSignature = errors.New("invalid offline transaction signature")
+	errOfflineTxSigner    = errors.New("offline transaction signed by wrong account")
+)
+
+//离线交易包: 未签名的交易(包括Nonce、Gas、Gas价格以及Tina扩展字段)、签名账号以及EIP155签名的链ID(为0时使用V为27/28的签名)
+type OfflineTx struct {
+	ChainId *big.Int
+	From    common.Address
+	Tx      *types.Transaction
+}
+
+//交易包使用的签名者
+func (p *OfflineTx) signer() types.Signer {
+	if p.ChainId != nil && p.ChainId.Sign() != 0 {
+		return types.NewEIP155Signer(p.ChainId)
+	}
+	return types.HomesteadSigner{}
+}
+
+//需要签名的哈希
+func (p *OfflineTx) SigningHash() common.Hash {
+	return p.signer().Hash(p.Tx)
+}
+
+//将签名[R || S || V](V为0/1或27/28)写入交易并校验签名账号
+func (p *OfflineTx) WithSignature(sig []byte) (*types.Transaction, error) {
+
+	if len(sig) != 65 {
+		return nil, errOfflineTxSignature
+	}
+	sig = common.CopyBytes(sig)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	if sig[64] > 1 {
+		return nil, errOfflineTxSignature
+	}
+	signer := p.signer()
+	tx, err := p.Tx.WithSignature(signer, sig)
+	if err != nil {
+		return nil, err
+	}
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, errOfflineTxSignature
+	}
+	if from != p.From {
+		return nil, errOfflineTxSigner
+	}
+	return tx, nil
+}
+
+//编码离线交易包: 前缀 + base64url(RLP编码 + Keccak256校验码的前4字节), 可以保存为文件或者生成二维码
+func EncodeOfflineTx(p *OfflineTx) (string, error) {
+
+	data, err := rlp.EncodeToBytes(p)
+	if err != nil {
+		return "", err
+	}
+	data = append(data, crypto.Keccak256(data)[:4]...)
+	return OfflineTxPrefix + base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+//解码离线交易包, 交易必须是未签名的
+func DecodeOfflineTx(payload string) (*OfflineTx, error) {
+
+	payload = strings.TrimSpace(payload)
+	if !strings.HasPrefix(payload, OfflineTxPrefix) {
+		return nil, errOfflineTxPayload
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload[len(OfflineTxPrefix):])
+	if err != nil || len(data) <= 4 {
+		return nil, errOfflineTxPayload
+	}
+	data, sum := data[:len(data)-4], data[len(data)-4:]
+	if !bytes.Equal(crypto.Keccak256(data)[:4], sum) {
+		return nil, errOfflineTxChecksum
+	}
+	p := new(OfflineTx)
+	if err := rlp.DecodeBytes(data, p); err != nil {
+		return nil, err
+	}
+	if r, s, _ := p.Tx.RawSignatureValues(); r.Sign() != 0 || s.Sign() != 0 {
+		return nil, errOfflineTxPayload
+	}
+	return p, nil
+}
+
+//离线交易包的RPC输出格式
+type RPCOfflineTx struct {
+	Payload     string          `json:"payload"`     //编码后的离线交易包
+	Tx          *RPCTransaction `json:"tx"`          //交易视图, 供签名前核对
+	SigningHash common.Hash     `json:"signingHash"` //需要签名的哈希
+	ChainId     *hexutil.Big    `json:"chainId"`     //EIP155签名使用的链ID, 为空时使用V为27/28的签名
+}
+
+//签名后的交易
+type RPCSignedOfflineTx struct {
+	Raw  hexutil.Bytes  `json:"raw"` //通过eth_sendRawTransaction提交
+	Hash common.Hash    `json:"hash"`
+	From common.Address `json:"from"`
+}
+
+func newRPCOfflineTx(payload string, p *OfflineTx) *RPCOfflineTx {
+
+	result := &RPCOfflineTx{
+		Payload:     payload,
+		Tx:          newRPCPendingTransaction(p.Tx),
+		SigningHash: p.SigningHash(),
+	}
+	if p.ChainId != nil && p.ChainId.Sign() != 0 {
+		result.ChainId = (*hexutil.Big)(p.ChainId)
+	}
+	result.Tx.From = p.From
+	return result
+}
+
+//导出未签名的Boker交易的离线交易包, 在离线机器上签名signingHash后通过boker_importOfflineSignature得到最终的交易
+func (s *PublicBokerAPI) ExportOfflineTx(ctx context.Context, args BuildTxArgs) (*RPCOfflineTx, error) {
+
+	tx, err := s.buildTx(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	_, chainId := currentSigner(s.b)
+	p := &OfflineTx{ChainId: chainId, From: args.From, Tx: tx}
+	payload, err := EncodeOfflineTx(p)
+	if err != nil {
+		return nil, err
+	}
+	return newRPCOfflineTx(payload, p), nil
+}
+
+//解码离线交易包, 供离线机器在签名前核对交易内容, 不需要访问区块链
+func (s *PublicBokerAPI) DecodeOfflineTx(payload string) (*RPCOfflineTx, error) {
+
+	p, err := DecodeOfflineTx(payload)
+	if err != nil {
+		return nil, err
+	}
+	return newRPCOfflineTx(payload, p), nil
+}
+
+//将离线签名[R || S || V]写入离线交易包中的交易, 返回签名后的原始交易, 签名账号必须是交易包中的from
+func (s *PublicBokerAPI) ImportOfflineSignature(payload string, signature hexutil.Bytes) (*RPCSignedOfflineTx, error) {
+
+	p, err := DecodeOfflineTx(payload)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := p.WithSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCSignedOfflineTx{Raw: raw, Hash: tx.Hash(), From: p.From}, nil
+}
+
+//在离线机器上使用本地密钥库中from账号签名离线交易包, 返回签名[R || S || V](V为27/28)
+func (s *PrivateAccountAPI) SignOfflineTx(payload string, passwd string) (hexutil.Bytes, error) {
+
+	p, err := DecodeOfflineTx(payload)
+	if err != nil {
+		return nil, err
+	}
+	account := accounts.Account{Address: p.From}
+	wallet, err := s.am.Find(account)
+	if err != nil {
+		return nil, err
+	}
+	hash := p.SigningHash()
+	signature, err := wallet.SignHashWithPassphrase(account, passwd, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	signature[64] += 27
+	return signature, nil
+}
@@ -0,0 +1,167 @@
+package ethapi
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/accounts"
+	"github.com/Tinachain/Tina/chain/accounts/keystore"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/params"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+// Tests that an exported offline package decodes back to the same unsigned
+// transaction and that a signature over its signing hash, with V as 0/1 or
+// 27/28, yields the transaction signed directly by the account.
+func TestOfflineTxRoundTrip(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		from    = crypto.PubkeyToAddress(key.PublicKey)
+		backend = &buildTxTestBackend{config: params.TestChainConfig, head: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(10)}), nonce: 3}
+		api     = NewPublicBokerAPI(backend)
+	)
+	exported, err := api.ExportOfflineTx(context.Background(), BuildTxArgs{From: from, Major: protocol.Extra, Minor: protocol.Word, To: common.Address{0x01}, Extra: []byte("word")})
+	if err != nil {
+		t.Fatalf("failed to export package: %v", err)
+	}
+	if !strings.HasPrefix(exported.Payload, OfflineTxPrefix) || exported.Tx.From != from || exported.ChainId.ToInt().Cmp(params.TestChainConfig.ChainId) != 0 {
+		t.Fatalf("exported package mismatch: %+v", exported)
+	}
+	decoded, err := api.DecodeOfflineTx(exported.Payload)
+	if err != nil {
+		t.Fatalf("failed to decode package: %v", err)
+	}
+	if decoded.SigningHash != exported.SigningHash || decoded.Tx.Hash != exported.Tx.Hash || uint64(decoded.Tx.Nonce) != 3 {
+		t.Errorf("decoded package mismatch: have %+v, want %+v", decoded, exported)
+	}
+
+	p, _ := DecodeOfflineTx(exported.Payload)
+	signer := types.NewEIP155Signer(params.TestChainConfig.ChainId)
+	want, _ := types.SignTx(p.Tx, signer, key)
+	for _, offset := range []byte{0, 27} {
+		sig, _ := crypto.Sign(exported.SigningHash[:], key)
+		sig[64] += offset
+		signed, err := api.ImportOfflineSignature(exported.Payload, sig)
+		if err != nil {
+			t.Fatalf("v offset %d: failed to import signature: %v", offset, err)
+		}
+		tx := new(types.Transaction)
+		if err := rlp.DecodeBytes(signed.Raw, tx); err != nil || tx.Hash() != want.Hash() || signed.Hash != want.Hash() || signed.From != from {
+			t.Errorf("v offset %d: signed transaction mismatch: have %x, err %v, want %x", offset, signed.Hash, err, want.Hash())
+		}
+	}
+
+	//EIP155之前的交易包不包含链ID
+	p = &OfflineTx{From: from, Tx: p.Tx}
+	payload, _ := EncodeOfflineTx(p)
+	if decoded, err := api.DecodeOfflineTx(payload); err != nil || decoded.ChainId != nil || decoded.SigningHash != (types.HomesteadSigner{}).Hash(p.Tx) {
+		t.Errorf("homestead package mismatch: have %+v, err %v", decoded, err)
+	}
+}
+
+// Tests that malformed, corrupted and signed packages are rejected, as are
+// malformed signatures and signatures of another account.
+func TestOfflineTxErrors(t *testing.T) {
+	var (
+		key, _   = crypto.GenerateKey()
+		other, _ = crypto.GenerateKey()
+		from     = crypto.PubkeyToAddress(key.PublicKey)
+		tx       = types.NewTransaction(protocol.Extra, protocol.Word, 0, common.Address{0x01}, big.NewInt(0), big.NewInt(BokerTxGas), big.NewInt(BokerTxGasPrice), nil)
+		api      = NewPublicBokerAPI(nil)
+	)
+	payload, _ := EncodeOfflineTx(&OfflineTx{ChainId: big.NewInt(1), From: from, Tx: tx})
+	signedTx, _ := types.SignTx(tx, types.NewEIP155Signer(big.NewInt(1)), key)
+	signedPayload, _ := EncodeOfflineTx(&OfflineTx{ChainId: big.NewInt(1), From: from, Tx: signedTx})
+
+	//校验码为最后4个字节, 修改前面的数据
+	corrupted := []byte(payload)
+	if corrupted[len(OfflineTxPrefix)] == 'A' {
+		corrupted[len(OfflineTxPrefix)] = 'B'
+	} else {
+		corrupted[len(OfflineTxPrefix)] = 'A'
+	}
+	decodes := []struct {
+		payload string
+		err     error
+	}{
+		{strings.TrimPrefix(payload, OfflineTxPrefix), errOfflineTxPayload},
+		{OfflineTxPrefix + "!!!", errOfflineTxPayload},
+		{OfflineTxPrefix + "AAAA", errOfflineTxPayload},
+		{string(corrupted), errOfflineTxChecksum},
+		{signedPayload, errOfflineTxPayload},
+	}
+	for i, tt := range decodes {
+		if _, err := api.DecodeOfflineTx(tt.payload); err != tt.err {
+			t.Errorf("decode %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	if _, err := api.DecodeOfflineTx(" " + payload + "\n"); err != nil {
+		t.Errorf("failed to decode padded package: %v", err)
+	}
+
+	hash := types.NewEIP155Signer(big.NewInt(1)).Hash(tx)
+	sig, _ := crypto.Sign(hash[:], key)
+	badV := common.CopyBytes(sig)
+	badV[64] = 29
+	otherSig, _ := crypto.Sign(hash[:], other)
+	imports := []struct {
+		sig []byte
+		err error
+	}{
+		{sig[:64], errOfflineTxSignature},
+		{badV, errOfflineTxSignature},
+		{otherSig, errOfflineTxSigner},
+	}
+	for i, tt := range imports {
+		if _, err := api.ImportOfflineSignature(payload, tt.sig); err != tt.err {
+			t.Errorf("import %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
+
+// Tests that a package is signed offline with the local keystore account of
+// its sender and that locked or unknown accounts are reported.
+func TestSignOfflineTx(t *testing.T) {
+	dir, err := ioutil.TempDir("", "offline-tx")
+	if err != nil {
+		t.Fatalf("failed to create keystore dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.NewAccount("secret")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	api := &PrivateAccountAPI{am: accounts.NewManager(ks)}
+
+	tx := types.NewTransaction(protocol.Extra, protocol.Word, 0, common.Address{0x01}, big.NewInt(0), big.NewInt(BokerTxGas), big.NewInt(BokerTxGasPrice), nil)
+	payload, _ := EncodeOfflineTx(&OfflineTx{ChainId: big.NewInt(1), From: account.Address, Tx: tx})
+
+	sig, err := api.SignOfflineTx(payload, "secret")
+	if err != nil {
+		t.Fatalf("failed to sign package: %v", err)
+	}
+	if sig[64] != 27 && sig[64] != 28 {
+		t.Errorf("recovery id mismatch: have %d, want 27 or 28", sig[64])
+	}
+	if signed, err := NewPublicBokerAPI(nil).ImportOfflineSignature(payload, sig); err != nil || signed.From != account.Address {
+		t.Errorf("failed to import offline signature: have %+v, err %v", signed, err)
+	}
+
+	if _, err := api.SignOfflineTx(payload, "wrong"); err != keystore.ErrDecrypt {
+		t.Errorf("wrong passphrase: have %v, want %v", err, keystore.ErrDecrypt)
+	}
+	payload, _ = EncodeOfflineTx(&OfflineTx{ChainId: big.NewInt(1), From: common.Address{0xff}, Tx: tx})
+	if _, err := api.SignOfflineTx(payload, "secret"); err != accounts.ErrUnknownAccount {
+		t.Errorf("unknown account: have %v, want %v", err, accounts.ErrUnknownAccount)
+	}
+}
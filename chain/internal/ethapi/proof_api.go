@@ -0,0 +1,82 @@
+package ethapi
+
+import (
+	"context"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//账号及其存储的Merkle证明(EIP-1186格式)
+type RPCAccountProof struct {
+	Address      common.Address    `json:"address"`
+	AccountProof []hexutil.Bytes   `json:"accountProof"` //从状态根到账号的节点
+	Balance      *hexutil.Big      `json:"balance"`
+	CodeHash     common.Hash       `json:"codeHash"`
+	Nonce        hexutil.Uint64    `json:"nonce"`
+	StorageHash  common.Hash       `json:"storageHash"`
+	StorageProof []RPCStorageProof `json:"storageProof"`
+}
+
+//存储位置的Merkle证明
+type RPCStorageProof struct {
+	Key   common.Hash     `json:"key"`
+	Value *hexutil.Big    `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"` //从存储根到存储位置的节点
+}
+
+func toProofBytes(proof [][]byte) []hexutil.Bytes {
+	result := make([]hexutil.Bytes, len(proof))
+	for i, node := range proof {
+		result[i] = hexutil.Bytes(node)
+	}
+	return result
+}
+
+//得到账号以及指定存储位置在区块状态中的Merkle证明，外部链可以根据区块头的状态根进行验证
+func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Address, storageKeys []string, blockNrOrHash rpc.BlockNumberOrHash) (*RPCAccountProof, error) {
+
+	state, _, err := stateAndHeaderByNumberOrHash(ctx, s.b, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	accountProof, err := state.GetProof(address)
+	if err != nil {
+		return nil, err
+	}
+
+	storageHash := types.EmptyRootHash
+	storageTrie := state.StorageTrie(address)
+	if storageTrie != nil {
+		storageHash = storageTrie.Hash()
+	}
+	storageProof := make([]RPCStorageProof, len(storageKeys))
+	for i, key := range storageKeys {
+		slot := common.HexToHash(key)
+		storageProof[i] = RPCStorageProof{
+			Key:   slot,
+			Value: (*hexutil.Big)(state.GetState(address, slot).Big()),
+			Proof: []hexutil.Bytes{},
+		}
+		if storageTrie == nil {
+			continue
+		}
+		proof, err := state.GetStorageProof(address, slot)
+		if err != nil {
+			return nil, err
+		}
+		storageProof[i].Proof = toProofBytes(proof)
+	}
+
+	return &RPCAccountProof{
+		Address:      address,
+		AccountProof: toProofBytes(accountProof),
+		Balance:      (*hexutil.Big)(state.GetBalance(address)),
+		CodeHash:     state.GetCodeHash(address),
+		Nonce:        hexutil.Uint64(state.GetNonce(address)),
+		StorageHash:  storageHash,
+		StorageProof: storageProof,
+	}, state.Error()
+}
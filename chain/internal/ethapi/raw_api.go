@@ -0,0 +1,46 @@
+package ethapi
+
+import (
+	"context"
+
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/rlp"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//返回区块的RLP编码(十六进制)，区块不存在时返回nil；
+//较大的区块可以通过HTTP端口的 GET /eth/block/<高度>.rlp 以二进制流下载
+func (s *PublicBlockChainAPI) GetRawBlockByNumber(ctx context.Context, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
+
+	key := responseCacheKey("eth_getRawBlockByNumber", blockNr.Int64())
+	if blockNr >= 0 {
+		if cached, ok := s.b.ResponseCache().get(ctx, s.b, key); ok {
+			return cached.(hexutil.Bytes), nil
+		}
+	}
+	var blob hexutil.Bytes
+	err := runWithTimeout(ctx, s.b, "eth_getRawBlockByNumber", func(ctx context.Context) error {
+		block, err := s.b.BlockByNumber(ctx, blockNr)
+		if block == nil {
+			return err
+		}
+		if blob, err = rlp.EncodeToBytes(block); err != nil {
+			return err
+		}
+		if blockNr >= 0 {
+			s.b.ResponseCache().put(ctx, s.b, key, block.NumberU64(), block.Hash(), blob)
+		}
+		return nil
+	})
+	return blob, err
+}
+
+//返回区块头的RLP编码(十六进制)，区块不存在时返回nil
+func (s *PublicBlockChainAPI) GetRawHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
+
+	header, err := s.b.HeaderByNumber(ctx, blockNr)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(header)
+}
@@ -0,0 +1,75 @@
+package ethapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/rlp"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+// rawTestBackend serves the blocks of the cached headers through a response cache.
+type rawTestBackend struct {
+	cacheTestBackend
+	blocks map[uint64]*types.Block
+	cache  *ResponseCache
+}
+
+func (b *rawTestBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	return b.blocks[uint64(number)], nil
+}
+
+func (b *rawTestBackend) RPCTimeout(string) time.Duration { return 0 }
+func (b *rawTestBackend) ResponseCache() *ResponseCache   { return b.cache }
+
+// Tests that the raw block and header RPCs return the RLP encodings, serve the
+// finalized blocks from the response cache and return nil for unknown blocks.
+func TestGetRawBlockAndHeader(t *testing.T) {
+	backend := &rawTestBackend{
+		cacheTestBackend: cacheTestBackend{headers: make(map[uint64]*types.Header), finalized: 2},
+		blocks:           make(map[uint64]*types.Block),
+		cache:            NewResponseCache(16),
+	}
+	for i := uint64(0); i <= 4; i++ {
+		backend.headers[i] = cacheTestHeader(i, "")
+		backend.blocks[i] = types.NewBlockWithHeader(backend.headers[i])
+	}
+	api := NewPublicBlockChainAPI(backend)
+
+	for _, number := range []uint64{1, 3} {
+		blob, err := api.GetRawBlockByNumber(context.Background(), rpc.BlockNumber(number))
+		if err != nil {
+			t.Fatalf("block %d: failed to get raw block: %v", number, err)
+		}
+		block := new(types.Block)
+		if err := rlp.DecodeBytes(blob, block); err != nil {
+			t.Fatalf("block %d: failed to decode: %v", number, err)
+		}
+		if block.Hash() != backend.blocks[number].Hash() {
+			t.Errorf("block %d: hash mismatch: have %x, want %x", number, block.Hash(), backend.blocks[number].Hash())
+		}
+		blob, err = api.GetRawHeaderByNumber(context.Background(), rpc.BlockNumber(number))
+		if err != nil {
+			t.Fatalf("header %d: failed to get raw header: %v", number, err)
+		}
+		header := new(types.Header)
+		if err := rlp.DecodeBytes(blob, header); err != nil || header.Hash() != backend.headers[number].Hash() {
+			t.Errorf("header %d: mismatch: have %x, err %v", number, header.Hash(), err)
+		}
+	}
+
+	//只有不可逆的区块从缓存中返回
+	delete(backend.blocks, 1)
+	delete(backend.blocks, 3)
+	if blob, err := api.GetRawBlockByNumber(context.Background(), 1); err != nil || len(blob) == 0 {
+		t.Errorf("finalized block not cached: have %x, err %v", blob, err)
+	}
+	if blob, err := api.GetRawBlockByNumber(context.Background(), 3); err != nil || blob != nil {
+		t.Errorf("unfinalized block cached: have %x, err %v", blob, err)
+	}
+	if blob, err := api.GetRawHeaderByNumber(context.Background(), 10); err != nil || blob != nil {
+		t.Errorf("unknown header: have %x, err %v", blob, err)
+	}
+}
@@ -0,0 +1,11 @@
+package ethapi
+
+import (
+	"github.com/Tinachain/Tina/chain/common"
+)
+
+//立即向所有节点重新广播交易池中的交易(本地账号的交易也会按--txpool.rebroadcast的间隔定时重新广播)，
+//交易不在交易池中(已经打包或者被丢弃)时返回false
+func (s *PublicTransactionPoolAPI) RebroadcastTransaction(hash common.Hash) bool {
+	return s.b.RebroadcastTx(hash)
+}
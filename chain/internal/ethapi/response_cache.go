@@ -0,0 +1,87 @@
+package ethapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/metrics"
+	"github.com/Tinachain/Tina/chain/rpc"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+var (
+	responseCacheHitMeter   = metrics.NewMeter("rpc/cache/hits")
+	responseCacheMissMeter  = metrics.NewMeter("rpc/cache/misses")
+	responseCachePurgeMeter = metrics.NewMeter("rpc/cache/purges")
+)
+
+//缓存的查询结果以及结果所属的区块，区块不再位于主链上时结果失效
+type cachedResponse struct {
+	number uint64
+	hash   common.Hash
+	value  interface{}
+}
+
+//不可变查询结果(已确认区块、其中交易的收据以及已确认区块上的合约代码)的LRU缓存，
+//按方法名和参数缓存，减少区块浏览器等客户端重复查询的负载
+type ResponseCache struct {
+	cache *lru.Cache
+}
+
+//新建可以缓存size个查询结果的缓存，size不大于0时返回nil(不缓存)
+func NewResponseCache(size int) *ResponseCache {
+
+	if size <= 0 {
+		return nil
+	}
+	cache, _ := lru.New(size)
+	return &ResponseCache{cache: cache}
+}
+
+//生成缓存的键
+func responseCacheKey(method string, params ...interface{}) string {
+
+	key := make([]string, 0, len(params)+1)
+	key = append(key, method)
+	for _, param := range params {
+		key = append(key, fmt.Sprint(param))
+	}
+	return strings.Join(key, "/")
+}
+
+//得到缓存的查询结果；结果所属的区块已经不在主链上时(回滚到已确认区块以下)清空缓存
+func (c *ResponseCache) get(ctx context.Context, b Backend, key string) (interface{}, bool) {
+
+	if c == nil {
+		return nil, false
+	}
+	entry, ok := c.cache.Get(key)
+	if !ok {
+		responseCacheMissMeter.Mark(1)
+		return nil, false
+	}
+	response := entry.(*cachedResponse)
+	if header, _ := b.HeaderByNumber(ctx, rpc.BlockNumber(response.number)); header == nil || header.Hash() != response.hash {
+		log.Warn("Chain reorganised below finalized block, dropping cached responses", "number", response.number, "hash", response.hash)
+		c.cache.Purge()
+		responseCachePurgeMeter.Mark(1)
+		return nil, false
+	}
+	responseCacheHitMeter.Mark(1)
+	return response.value, true
+}
+
+//缓存查询结果，只有已确认(不可逆)的主链区块的结果才会被缓存
+func (c *ResponseCache) put(ctx context.Context, b Backend, key string, number uint64, hash common.Hash, value interface{}) {
+
+	if c == nil || number > b.FinalizedNumber() {
+		return
+	}
+	if header, _ := b.HeaderByNumber(ctx, rpc.BlockNumber(number)); header == nil || header.Hash() != hash {
+		return
+	}
+	c.cache.Add(key, &cachedResponse{number: number, hash: hash, value: value})
+}
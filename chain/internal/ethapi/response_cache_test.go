@@ -0,0 +1,82 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+// cacheTestBackend serves a canonical chain of headers and a finalized height,
+// the rest of the backend is left unimplemented.
+type cacheTestBackend struct {
+	Backend
+	headers   map[uint64]*types.Header
+	finalized uint64
+}
+
+func (b *cacheTestBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	return b.headers[uint64(number)], nil
+}
+
+func (b *cacheTestBackend) FinalizedNumber() uint64 {
+	return b.finalized
+}
+
+// cacheTestHeader creates a header with empty consensus contexts, which are
+// required for hashing.
+func cacheTestHeader(number uint64, extra string) *types.Header {
+	return &types.Header{
+		Number:     new(big.Int).SetUint64(number),
+		Extra:      []byte(extra),
+		DposProto:  &types.DposContextProto{},
+		BokerProto: &types.BokerBackendProto{},
+	}
+}
+
+func TestResponseCache(t *testing.T) {
+	backend := &cacheTestBackend{headers: make(map[uint64]*types.Header), finalized: 5}
+	for i := uint64(0); i <= 10; i++ {
+		backend.headers[i] = cacheTestHeader(i, "")
+	}
+	var (
+		ctx   = context.Background()
+		cache = NewResponseCache(16)
+		hash  = func(n uint64) string { return backend.headers[n].Hash().Hex() }
+	)
+	for i := uint64(0); i <= 10; i++ {
+		cache.put(ctx, backend, responseCacheKey("block", i), i, backend.headers[i].Hash(), hash(i))
+	}
+	// Only the finalized blocks are cached
+	for i := uint64(0); i <= 10; i++ {
+		value, ok := cache.get(ctx, backend, responseCacheKey("block", i))
+		if ok != (i <= backend.finalized) {
+			t.Fatalf("block %d: cached %v, finalized %d", i, ok, backend.finalized)
+		}
+		if ok && value.(string) != hash(i) {
+			t.Fatalf("block %d: cached value mismatch: have %v, want %v", i, value, hash(i))
+		}
+	}
+	// Non canonical blocks are not cached
+	side := cacheTestHeader(3, "side")
+	cache.put(ctx, backend, responseCacheKey("side"), 3, side.Hash(), "side")
+	if _, ok := cache.get(ctx, backend, responseCacheKey("side")); ok {
+		t.Fatalf("non canonical block cached")
+	}
+	// Replacing a finalized block drops all cached responses
+	backend.headers[4] = cacheTestHeader(4, "reorg")
+	if _, ok := cache.get(ctx, backend, responseCacheKey("block", 4)); ok {
+		t.Fatalf("reorged block served from cache")
+	}
+	if _, ok := cache.get(ctx, backend, responseCacheKey("block", 2)); ok {
+		t.Fatalf("cache not purged after reorg")
+	}
+	// A disabled cache never serves responses
+	var disabled *ResponseCache
+	disabled.put(ctx, backend, "block", 0, backend.headers[0].Hash(), "genesis")
+	if _, ok := disabled.get(ctx, backend, "block"); ok {
+		t.Fatalf("disabled cache served a response")
+	}
+}
@@ -0,0 +1,77 @@
+package ethapi
+
+import (
+	"errors"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+)
+
+var errRichListDisabled = errors.New("rich list not available (start the node with --richlist)")
+
+//排行榜中的账号
+type RPCRichAccount struct {
+	Rank    hexutil.Uint64 `json:"rank"`
+	Address common.Address `json:"address"`
+	Balance *hexutil.Big   `json:"balance"`
+}
+
+//余额排行榜
+type RPCRichList struct {
+	BlockNumber hexutil.Uint64    `json:"blockNumber"`
+	BlockHash   common.Hash       `json:"blockHash"`
+	TotalSupply *hexutil.Big      `json:"totalSupply"`
+	Accounts    []*RPCRichAccount `json:"accounts"`
+}
+
+//总发行量
+type RPCTotalSupply struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	TotalSupply *hexutil.Big   `json:"totalSupply"`
+}
+
+//得到当前区块余额最大的count个账号(为空时返回节点维护的全部排行榜)
+func (s *PublicBokerAPI) GetRichList(count *hexutil.Uint64) (*RPCRichList, error) {
+
+	list := core.GetRichList(s.b.ChainDb())
+	if list == nil {
+		return nil, errRichListDisabled
+	}
+	size := list.Size
+	if count != nil && uint64(*count) < size {
+		size = uint64(*count)
+	}
+	if uint64(len(list.Accounts)) < size {
+		size = uint64(len(list.Accounts))
+	}
+	result := &RPCRichList{
+		BlockNumber: hexutil.Uint64(list.Number),
+		BlockHash:   list.Head,
+		TotalSupply: (*hexutil.Big)(list.TotalSupply),
+		Accounts:    make([]*RPCRichAccount, 0, size),
+	}
+	for i, account := range list.Accounts[:size] {
+		result.Accounts = append(result.Accounts, &RPCRichAccount{
+			Rank:    hexutil.Uint64(i + 1),
+			Address: account.Address,
+			Balance: (*hexutil.Big)(account.Balance),
+		})
+	}
+	return result, nil
+}
+
+//得到当前区块所有账号的余额之和
+func (s *PublicBokerAPI) GetTotalSupply() (*RPCTotalSupply, error) {
+
+	list := core.GetRichList(s.b.ChainDb())
+	if list == nil {
+		return nil, errRichListDisabled
+	}
+	return &RPCTotalSupply{
+		BlockNumber: hexutil.Uint64(list.Number),
+		BlockHash:   list.Head,
+		TotalSupply: (*hexutil.Big)(list.TotalSupply),
+	}, nil
+}
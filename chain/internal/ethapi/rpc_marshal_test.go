@@ -0,0 +1,172 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/core/vm"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/ethdb"
+)
+
+// Tests that headers are marshalled with their hash and the Tina specific
+// context roots, and that the Ethereum compatible schema leaves them out.
+func TestRPCMarshalHeader(t *testing.T) {
+	header := &types.Header{
+		Number:     big.NewInt(7),
+		Difficulty: big.NewInt(1),
+		GasLimit:   big.NewInt(10000000),
+		GasUsed:    big.NewInt(21000),
+		Time:       big.NewInt(1500000000),
+		Validator:  common.HexToAddress("0x01"),
+		DposProto:  &types.DposContextProto{EpochHash: common.HexToHash("0xe0")},
+		BokerProto: &types.BokerBackendProto{StocksHash: common.HexToHash("0x5e")},
+	}
+	fields := RPCMarshalHeader(header, false)
+	if fields["hash"] != header.Hash() {
+		t.Errorf("hash mismatch: have %v, want %x", fields["hash"], header.Hash())
+	}
+	if fields["validator"] != header.Validator {
+		t.Errorf("validator mismatch: have %v, want %x", fields["validator"], header.Validator)
+	}
+	if proto := fields["dposContext"].(*types.DposContextProto); proto.EpochHash != header.DposProto.EpochHash {
+		t.Errorf("dpos context mismatch: have %x, want %x", proto.EpochHash, header.DposProto.EpochHash)
+	}
+	if proto := fields["bokerBackend"].(*types.BokerBackendProto); proto.StocksHash != header.BokerProto.StocksHash {
+		t.Errorf("boker backend mismatch: have %x, want %x", proto.StocksHash, header.BokerProto.StocksHash)
+	}
+	//区块查询专有的字段不属于区块头
+	for _, field := range []string{"totalDifficulty", "size", "transactions"} {
+		if _, ok := fields[field]; ok {
+			t.Errorf("header contains block field %q", field)
+		}
+	}
+
+	//兼容模式下只输出标准的以太坊字段
+	fields = RPCMarshalHeader(header, true)
+	for _, field := range []string{"validator", "coinbase", "dposContext", "bokerBackend"} {
+		if _, ok := fields[field]; ok {
+			t.Errorf("compatible header contains Tina field %q", field)
+		}
+	}
+	if fields["miner"] != header.Coinbase || fields["sha3Uncles"] != header.UncleHash {
+		t.Errorf("compatible header fields mismatch: miner %v, sha3Uncles %v", fields["miner"], fields["sha3Uncles"])
+	}
+}
+
+type compatTestBackend struct {
+	Backend
+	db        ethdb.Database
+	block     *types.Block
+	ethCompat bool
+}
+
+func (b *compatTestBackend) ChainDb() ethdb.Database                           { return b.db }
+func (b *compatTestBackend) EthCompatible() bool                               { return b.ethCompat }
+func (b *compatTestBackend) GetTd(hash common.Hash) *big.Int                   { return big.NewInt(1) }
+func (b *compatTestBackend) GetPoolTransaction(common.Hash) *types.Transaction { return nil }
+func (b *compatTestBackend) RPCTimeout(string) time.Duration                   { return 0 }
+func (b *compatTestBackend) ResponseCache() *ResponseCache                     { return nil }
+
+func (b *compatTestBackend) GetBlock(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return b.block, nil
+}
+
+// newCompatTestBackend creates a backend holding a single block with one failed
+// transaction, whose receipt carries an execution error code.
+func newCompatTestBackend(t *testing.T, ethCompat bool) (*compatTestBackend, *types.Transaction) {
+	key, _ := crypto.GenerateKey()
+	tx, err := types.SignTx(types.NewTransaction(protocol.Normal, protocol.NormalCall, 0, common.HexToAddress("0x01"), big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil), types.HomesteadSigner{}, key)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), GasLimit: big.NewInt(10000000), GasUsed: big.NewInt(21000), Time: big.NewInt(1500000000), Validator: common.HexToAddress("0xaa"), DposProto: &types.DposContextProto{}, BokerProto: &types.BokerBackendProto{}}
+	receipt := &types.Receipt{Status: types.ReceiptStatusFailed, CumulativeGasUsed: big.NewInt(21000), GasUsed: big.NewInt(21000), TxHash: tx.Hash(), ErrorCode: vm.ErrCodeExecutionReverted, RevertReason: "denied"}
+	block := types.NewBlock(header, types.Transactions{tx}, nil, types.Receipts{receipt})
+
+	db, _ := ethdb.NewMemDatabase()
+	core.WriteBlock(db, block)
+	core.WriteTxLookupEntries(db, block)
+	core.WriteBlockReceipts(db, block.Hash(), block.NumberU64(), types.Receipts{receipt})
+	return &compatTestBackend{db: db, block: block, ethCompat: ethCompat}, tx
+}
+
+// Tests that the Ethereum compatible mode strips the Tina fields from blocks,
+// transactions and receipts of the eth_ namespace, while the boker_ namespace
+// keeps serving the full Tina views.
+func TestEthCompatibleOutput(t *testing.T) {
+	if out := outputTransaction(nil, true); out != nil {
+		t.Errorf("missing transaction output: have %v, want nil", out)
+	}
+	backend, tx := newCompatTestBackend(t, true)
+	ctx := context.Background()
+
+	block, err := NewPublicBlockChainAPI(backend).GetBlockByHash(ctx, backend.block.Hash(), true, nil)
+	if err != nil {
+		t.Fatalf("failed to get block: %v", err)
+	}
+	if _, ok := block["uncles"]; !ok {
+		t.Errorf("compatible block misses uncles")
+	}
+	if _, ok := block["validator"]; ok {
+		t.Errorf("compatible block contains validator")
+	}
+	if txs := block["transactions"].([]interface{}); len(txs) != 1 {
+		t.Errorf("transaction count mismatch: have %d, want 1", len(txs))
+	} else if _, ok := txs[0].(*RPCEthTransaction); !ok {
+		t.Errorf("compatible block transaction type mismatch: have %T", txs[0])
+	}
+	txapi := NewPublicTransactionPoolAPI(backend, nil)
+	if out, ok := txapi.GetTransactionByHash(ctx, tx.Hash()).(*RPCEthTransaction); !ok || out.Hash != tx.Hash() {
+		t.Errorf("compatible transaction mismatch: have %v", out)
+	}
+	receipt, err := txapi.GetTransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		t.Fatalf("failed to get receipt: %v", err)
+	}
+	for _, field := range []string{"major", "minor", "extra", "ip", "errorCode", "revertReason"} {
+		if _, ok := receipt[field]; ok {
+			t.Errorf("compatible receipt contains Tina field %q", field)
+		}
+	}
+
+	boker := NewPublicBokerAPI(backend)
+	block, err = boker.GetBlockByHash(ctx, backend.block.Hash(), true)
+	if err != nil {
+		t.Fatalf("failed to get boker block: %v", err)
+	}
+	if block["validator"] != backend.block.Header().Validator {
+		t.Errorf("boker block validator mismatch: have %v", block["validator"])
+	}
+	if _, ok := block["uncles"]; ok {
+		t.Errorf("boker block contains uncles")
+	}
+	if txs := block["transactions"].([]interface{}); len(txs) != 1 {
+		t.Errorf("boker transaction count mismatch: have %d, want 1", len(txs))
+	} else if _, ok := txs[0].(*RPCTransaction); !ok {
+		t.Errorf("boker block transaction type mismatch: have %T", txs[0])
+	}
+	if out := boker.GetTransactionByHash(ctx, tx.Hash()); out == nil || out.Major != protocol.Normal {
+		t.Errorf("boker transaction mismatch: have %v", out)
+	}
+	receipt, err = boker.GetTransactionReceipt(tx.Hash())
+	if err != nil {
+		t.Fatalf("failed to get boker receipt: %v", err)
+	}
+	if receipt["errorCode"] != hexutil.Uint(vm.ErrCodeExecutionReverted) || receipt["revertReason"] != "denied" || receipt["minor"] != protocol.NormalCall {
+		t.Errorf("boker receipt mismatch: %v", receipt)
+	}
+
+	//非兼容模式下eth_*接口输出完整的Tina交易
+	backend.ethCompat = false
+	if _, ok := txapi.GetTransactionByHash(ctx, tx.Hash()).(*RPCTransaction); !ok {
+		t.Errorf("default transaction output is not the Tina view")
+	}
+}
@@ -0,0 +1,50 @@
+package ethapi
+
+import (
+	"context"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rlp"
+)
+
+//定时交易的RPC输出格式
+type RPCScheduledTransaction struct {
+	Block hexutil.Uint64  `json:"block"` //可以被打包的最低区块高度
+	Tx    *RPCTransaction `json:"tx"`
+}
+
+//提交已签名的定时交易，交易池保存到区块高度达到block之前再广播和打包(block不高于下一个区块时直接加入交易池)
+func (s *PublicTransactionPoolAPI) ScheduleRawTransaction(ctx context.Context, encodedTx hexutil.Bytes, block hexutil.Uint64) (common.Hash, error) {
+
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	if err := s.b.ScheduleTx(ctx, tx, uint64(block)); err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Submitted scheduled transaction", "hash", tx.Hash(), "block", uint64(block))
+	return tx.Hash(), nil
+}
+
+//得到交易池中还没有生效的定时交易，按生效区块排序
+func (s *PublicTxPoolAPI) Scheduled() []*RPCScheduledTransaction {
+
+	scheduled := s.b.ScheduledTxs()
+	result := make([]*RPCScheduledTransaction, 0, len(scheduled))
+	for _, stx := range scheduled {
+		result = append(result, &RPCScheduledTransaction{
+			Block: hexutil.Uint64(stx.Block),
+			Tx:    newRPCPendingTransaction(stx.Tx),
+		})
+	}
+	return result
+}
+
+//取消还没有生效的定时交易
+func (s *PrivateAccountAPI) CancelScheduledTransaction(hash common.Hash) bool {
+	return s.b.CancelScheduledTx(hash)
+}
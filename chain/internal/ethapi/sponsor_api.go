@@ -0,0 +1,53 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/accounts"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core"
+)
+
+//使用本节点上已解锁的payer账号为交易代付Gas, 返回填充了Nonce、Gas、Gas价格以及代付签名的交易参数,
+//发送账号使用返回的参数通过eth_sendTransaction(或签名后通过eth_sendRawTransaction)提交交易
+func (s *PublicTransactionPoolAPI) SponsorTransaction(ctx context.Context, payer common.Address, args SendTxArgs) (*SendTxArgs, error) {
+
+	if payer == args.From {
+		return nil, protocol.ErrInvalidSponsor
+	}
+	//分叉之前代付交易按普通交易处理，Gas仍然由发送账号支付
+	next := new(big.Int).Add(s.b.CurrentBlock().Number(), big.NewInt(1))
+	if !s.b.ChainConfig().IsSponsor(next) {
+		return nil, protocol.ErrSponsorDisabled
+	}
+	account := accounts.Account{Address: payer}
+	wallet, err := s.b.AccountManager().Find(account)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Nonce == nil {
+		s.nonceLock.LockAddr(args.From)
+		defer s.nonceLock.UnlockAddr(args.From)
+	}
+	if err := args.SetDefaults(ctx, s.b); err != nil {
+		return nil, err
+	}
+	args.Major, args.Minor = protocol.Normal, protocol.Sponsored
+	args.Sponsorship = make([]byte, 65)
+	tx, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := core.SponsorHash(s.b.ChainConfig().ChainId, args.From, tx)
+	signature, err := wallet.SignHash(account, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	signature[64] += 27
+	args.Sponsorship = signature
+	return &args, nil
+}
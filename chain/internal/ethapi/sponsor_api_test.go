@@ -0,0 +1,40 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+// sponsorTestBackend serves a chain config and head block, the rest of the
+// backend is left unimplemented.
+type sponsorTestBackend struct {
+	Backend
+	config *params.ChainConfig
+	head   *types.Block
+}
+
+func (b *sponsorTestBackend) ChainConfig() *params.ChainConfig { return b.config }
+func (b *sponsorTestBackend) CurrentBlock() *types.Block       { return b.head }
+
+// Tests that sponsorships are refused before the sponsor fork and for the
+// sender itself.
+func TestSponsorTransactionChecks(t *testing.T) {
+	config := *params.TestChainConfig
+	config.SponsorBlock = big.NewInt(2)
+	backend := &sponsorTestBackend{config: &config, head: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0)})}
+	api := NewPublicTransactionPoolAPI(backend, new(AddrLocker))
+
+	from, payer := common.Address{0x01}, common.Address{0x02}
+	if _, err := api.SponsorTransaction(context.Background(), from, SendTxArgs{From: from}); err != protocol.ErrInvalidSponsor {
+		t.Errorf("self sponsorship: have %v, want %v", err, protocol.ErrInvalidSponsor)
+	}
+	if _, err := api.SponsorTransaction(context.Background(), payer, SendTxArgs{From: from}); err != protocol.ErrSponsorDisabled {
+		t.Errorf("before fork: have %v, want %v", err, protocol.ErrSponsorDisabled)
+	}
+}
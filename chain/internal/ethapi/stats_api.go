@@ -0,0 +1,153 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+const (
+	defaultStatsWindow = 100 //默认统计最近的区块数
+	defaultStatsDays   = 7   //默认统计最近的天数
+	maxStatsDays       = 366 //最多统计的天数
+)
+
+var errNoBlockStats = errors.New("no block statistics in window")
+
+//区块窗口内的统计结果，统计区间为(fromBlock, toBlock]
+type RPCWindowStats struct {
+	FromBlock hexutil.Uint64 `json:"fromBlock"`
+	ToBlock   hexutil.Uint64 `json:"toBlock"`
+	Value     float64        `json:"value"`
+}
+
+//一天的统计结果
+type RPCDailyStat struct {
+	Date  string         `json:"date"` //UTC日期
+	Value hexutil.Uint64 `json:"value"`
+}
+
+//提供区块导入时累计的链上统计数据
+type PublicStatsAPI struct {
+	b Backend
+}
+
+func NewPublicStatsAPI(b Backend) *PublicStatsAPI {
+	return &PublicStatsAPI{b}
+}
+
+//得到最近window个区块的首尾区块及其累计统计，区块在开始统计之前时从开始统计的区块算起
+func (s *PublicStatsAPI) window(ctx context.Context, window *hexutil.Uint64) (from, to *types.Header, fromStats, toStats *types.BlockStats, err error) {
+
+	size := uint64(defaultStatsWindow)
+	if window != nil && *window > 0 {
+		size = uint64(*window)
+	}
+	if to, err = s.b.HeaderByNumber(ctx, rpc.LatestBlockNumber); err != nil || to == nil {
+		return nil, nil, nil, nil, errNoBlockStats
+	}
+	db := s.b.ChainDb()
+	if toStats = core.GetBlockStats(db, to.Hash(), to.Number.Uint64()); toStats == nil {
+		return nil, nil, nil, nil, errNoBlockStats
+	}
+	base := toStats.First
+	if head := to.Number.Uint64(); head > size && head-size > base {
+		base = head - size
+	}
+	if base >= to.Number.Uint64() {
+		return nil, nil, nil, nil, errNoBlockStats
+	}
+	if from, err = s.b.HeaderByNumber(ctx, rpc.BlockNumber(base)); err != nil || from == nil {
+		return nil, nil, nil, nil, errNoBlockStats
+	}
+	if fromStats = core.GetBlockStats(db, from.Hash(), base); fromStats == nil {
+		return nil, nil, nil, nil, errNoBlockStats
+	}
+	return from, to, fromStats, toStats, nil
+}
+
+//最近window个区块的平均出块间隔(秒)
+func (s *PublicStatsAPI) BlockInterval(ctx context.Context, window *hexutil.Uint64) (*RPCWindowStats, error) {
+	from, to, _, _, err := s.window(ctx, window)
+	if err != nil {
+		return nil, err
+	}
+	blocks := to.Number.Uint64() - from.Number.Uint64()
+	elapsed := to.Time.Uint64() - from.Time.Uint64()
+	return newWindowStats(from, to, float64(elapsed)/float64(blocks)), nil
+}
+
+//最近window个区块的平均交易数
+func (s *PublicStatsAPI) TxsPerBlock(ctx context.Context, window *hexutil.Uint64) (*RPCWindowStats, error) {
+	from, to, fromStats, toStats, err := s.window(ctx, window)
+	if err != nil {
+		return nil, err
+	}
+	blocks := to.Number.Uint64() - from.Number.Uint64()
+	return newWindowStats(from, to, float64(toStats.Txs-fromStats.Txs)/float64(blocks)), nil
+}
+
+//最近window个区块使用的Gas与Gas上限之比
+func (s *PublicStatsAPI) GasUsedRatio(ctx context.Context, window *hexutil.Uint64) (*RPCWindowStats, error) {
+	from, to, fromStats, toStats, err := s.window(ctx, window)
+	if err != nil {
+		return nil, err
+	}
+	var ratio float64
+	if limit := toStats.GasLimit - fromStats.GasLimit; limit > 0 {
+		ratio = float64(toStats.GasUsed-fromStats.GasUsed) / float64(limit)
+	}
+	return newWindowStats(from, to, ratio), nil
+}
+
+func newWindowStats(from, to *types.Header, value float64) *RPCWindowStats {
+	return &RPCWindowStats{
+		FromBlock: hexutil.Uint64(from.Number.Uint64()),
+		ToBlock:   hexutil.Uint64(to.Number.Uint64()),
+		Value:     value,
+	}
+}
+
+//最近days天(包括当前区块所在的一天)每天扩展交易的数据字节数，按日期从早到晚排列
+func (s *PublicStatsAPI) ExtraBytesPerDay(ctx context.Context, days *hexutil.Uint64) ([]RPCDailyStat, error) {
+	return s.daily(ctx, days, func(stats *types.DailyStats) uint64 { return stats.ExtraBytes })
+}
+
+//最近days天(包括当前区块所在的一天)每天发出或接收交易的不同账号数，按日期从早到晚排列
+func (s *PublicStatsAPI) ActiveAddresses(ctx context.Context, days *hexutil.Uint64) ([]RPCDailyStat, error) {
+	return s.daily(ctx, days, func(stats *types.DailyStats) uint64 { return stats.ActiveAddresses })
+}
+
+func (s *PublicStatsAPI) daily(ctx context.Context, days *hexutil.Uint64, value func(*types.DailyStats) uint64) ([]RPCDailyStat, error) {
+
+	count := uint64(defaultStatsDays)
+	if days != nil && *days > 0 {
+		count = uint64(*days)
+	}
+	if count > maxStatsDays {
+		count = maxStatsDays
+	}
+	head, err := s.b.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil || head == nil {
+		return nil, errNoBlockStats
+	}
+	last := core.StatsDay(head.Time.Uint64())
+	if count > last+1 {
+		count = last + 1
+	}
+
+	results := make([]RPCDailyStat, 0, count)
+	for day := last + 1 - count; day <= last; day++ {
+		stat := RPCDailyStat{Date: time.Unix(0, 0).UTC().AddDate(0, 0, int(day)).Format("2006-01-02")}
+		if stats := core.GetDailyStats(s.b.ChainDb(), day); stats != nil {
+			stat.Value = hexutil.Uint64(value(stats))
+		}
+		results = append(results, stat)
+	}
+	return results, nil
+}
@@ -0,0 +1,149 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/ethdb"
+)
+
+// Tests that the window statistics are derived from the cumulative block
+// statistics, limited to the blocks imported since the statistics started.
+func TestWindowStats(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	backend := &statsTestBackend{db: db}
+	api := NewPublicStatsAPI(backend)
+
+	//没有统计数据时返回错误
+	backend.headers = []*types.Header{{Number: big.NewInt(0), Time: big.NewInt(0)}}
+	if _, err := api.BlockInterval(context.Background(), nil); err != errNoBlockStats {
+		t.Errorf("missing stats: have %v, want %v", err, errNoBlockStats)
+	}
+	backend.headers = nil
+
+	//区块i包含i笔交易，区块4在区块3之后20秒产生，其余间隔10秒
+	var (
+		to     = common.HexToAddress("0x01")
+		parent *types.BlockStats
+	)
+	for i := 0; i < 5; i++ {
+		var txs types.Transactions
+		for j := 0; j < i; j++ {
+			txs = append(txs, types.NewTransaction(protocol.Normal, protocol.NormalCall, uint64(j), to, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil))
+		}
+		time := int64(10 * i)
+		if i == 4 {
+			time = 50
+		}
+		header := &types.Header{Number: big.NewInt(int64(i)), Time: big.NewInt(time), GasLimit: big.NewInt(100000), GasUsed: big.NewInt(int64(21000 * i))}
+		block := types.NewBlock(header, txs, nil, nil)
+		if err := core.WriteBlockStats(db, parent, block); err != nil {
+			t.Fatalf("block %d: failed to write stats: %v", i, err)
+		}
+		parent = core.GetBlockStats(db, block.Hash(), block.NumberU64())
+		backend.headers = append(backend.headers, block.Header())
+
+		//只有一个区块时窗口为空
+		if i == 0 {
+			if _, err := api.TxsPerBlock(context.Background(), nil); err != errNoBlockStats {
+				t.Errorf("empty window: have %v, want %v", err, errNoBlockStats)
+			}
+		}
+	}
+
+	window := hexutil.Uint64(2)
+	tests := []struct {
+		name   string
+		fn     func(context.Context, *hexutil.Uint64) (*RPCWindowStats, error)
+		window *hexutil.Uint64
+		from   uint64
+		value  float64
+	}{
+		{"interval", api.BlockInterval, nil, 0, 12.5},
+		{"interval", api.BlockInterval, &window, 2, 15},
+		{"txs", api.TxsPerBlock, nil, 0, 2.5},
+		{"txs", api.TxsPerBlock, &window, 2, 3.5},
+		{"gas", api.GasUsedRatio, nil, 0, 0.525},
+		{"gas", api.GasUsedRatio, &window, 2, 0.735},
+	}
+	for _, tt := range tests {
+		stats, err := tt.fn(context.Background(), tt.window)
+		if err != nil {
+			t.Errorf("%s %d-4: failed to get stats: %v", tt.name, tt.from, err)
+			continue
+		}
+		if uint64(stats.FromBlock) != tt.from || stats.ToBlock != 4 || stats.Value != tt.value {
+			t.Errorf("%s: have %d-%d %v, want %d-4 %v", tt.name, stats.FromBlock, stats.ToBlock, stats.Value, tt.from, tt.value)
+		}
+	}
+}
+
+// Tests that the daily statistics count every active address once per day and
+// report the days without blocks as zero.
+func TestDailyStats(t *testing.T) {
+	var (
+		db, _      = ethdb.NewMemDatabase()
+		backend    = &statsTestBackend{db: db}
+		key, _     = crypto.GenerateKey()
+		to         = common.HexToAddress("0x01")
+		signer     = types.HomesteadSigner{}
+		day        = int64(24 * 60 * 60)
+		extraBlock = func(number, time int64, nonce uint64, extra []byte) *types.Block {
+			tx := types.NewExtraTransaction(protocol.Extra, protocol.Word, nonce, to, new(big.Int), big.NewInt(100000), big.NewInt(1), nil, extra, 0)
+			tx, _ = types.SignTx(tx, signer, key)
+			return types.NewBlock(&types.Header{Number: big.NewInt(number), Time: big.NewInt(time)}, types.Transactions{tx}, nil, nil)
+		}
+	)
+	//第1天两个区块(相同的账号)，第2天没有区块，第3天一个区块
+	blocks := []*types.Block{
+		extraBlock(1, day+10, 0, []byte("ab")),
+		extraBlock(2, day+20, 1, []byte("cde")),
+		extraBlock(3, 3*day+5, 2, []byte("f")),
+	}
+	for _, block := range blocks {
+		batch := db.NewBatch()
+		if err := core.WriteDailyStats(db, batch, signer, block); err != nil {
+			t.Fatalf("block %d: failed to write stats: %v", block.NumberU64(), err)
+		}
+		if err := batch.Write(); err != nil {
+			t.Fatalf("block %d: failed to commit stats: %v", block.NumberU64(), err)
+		}
+		backend.headers = append(backend.headers, block.Header())
+	}
+	api := NewPublicStatsAPI(backend)
+
+	days := hexutil.Uint64(3)
+	active, err := api.ActiveAddresses(context.Background(), &days)
+	if err != nil {
+		t.Fatalf("failed to get active addresses: %v", err)
+	}
+	want := []RPCDailyStat{{"1970-01-02", 2}, {"1970-01-03", 0}, {"1970-01-04", 2}}
+	if len(active) != len(want) {
+		t.Fatalf("active address days mismatch: have %v, want %v", active, want)
+	}
+	for i := range want {
+		if active[i] != want[i] {
+			t.Errorf("day %d: have %v, want %v", i, active[i], want[i])
+		}
+	}
+	extra, err := api.ExtraBytesPerDay(context.Background(), &days)
+	if err != nil {
+		t.Fatalf("failed to get extra bytes: %v", err)
+	}
+	if extra[0].Value != 5 || extra[1].Value != 0 || extra[2].Value != 1 {
+		t.Errorf("extra bytes mismatch: %v", extra)
+	}
+
+	//超出链上天数和最大天数时截断
+	days = hexutil.Uint64(maxStatsDays + 1)
+	if extra, err = api.ExtraBytesPerDay(context.Background(), &days); err != nil || len(extra) != 4 || extra[0].Date != "1970-01-01" {
+		t.Errorf("clamped days mismatch: have %v, err %v", extra, err)
+	}
+}
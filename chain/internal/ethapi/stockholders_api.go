@@ -0,0 +1,70 @@
+package ethapi
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//股权账号在快照区块中的股权
+type RPCStockholder struct {
+	Account common.Address `json:"account"`
+	Number  hexutil.Uint64 `json:"number"`
+	Frozen  bool           `json:"frozen"` //冻结的股权不参与股权分币
+}
+
+//指定区块的股权快照(来自该区块的股权树，不随之后的股权变更变化)
+type RPCStockholders struct {
+	BlockNumber  hexutil.Uint64    `json:"blockNumber"`
+	BlockHash    common.Hash       `json:"blockHash"`
+	Total        hexutil.Uint64    `json:"total"`        //全部股权数量
+	Active       hexutil.Uint64    `json:"active"`       //没有冻结的股权数量(股权分币按此数量平分)
+	Stockholders []*RPCStockholder `json:"stockholders"` //按账号地址排序
+}
+
+//得到指定区块中所有股权账号的股权，可以作为链下分红的登记日数据
+func (s *PublicBokerAPI) GetStockholdersAt(ctx context.Context, blockNr rpc.BlockNumber) (*RPCStockholders, error) {
+
+	if blockNr == rpc.PendingBlockNumber {
+		return nil, protocol.ErrUnknownBlock
+	}
+	header, err := s.b.HeaderByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, protocol.ErrUnknownBlock
+	}
+	bokerContext, err := types.NewBokerContextFromProto(s.b.ChainDb(), header.BokerProto)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RPCStockholders{
+		BlockNumber:  hexutil.Uint64(header.Number.Uint64()),
+		BlockHash:    header.Hash(),
+		Stockholders: []*RPCStockholder{},
+	}
+	for _, stock := range bokerContext.GetStocks() {
+		frozen := stock.State == protocol.Frozen
+		result.Total += hexutil.Uint64(stock.Number)
+		if !frozen {
+			result.Active += hexutil.Uint64(stock.Number)
+		}
+		result.Stockholders = append(result.Stockholders, &RPCStockholder{
+			Account: stock.Account,
+			Number:  hexutil.Uint64(stock.Number),
+			Frozen:  frozen,
+		})
+	}
+	sort.Slice(result.Stockholders, func(i, j int) bool {
+		return bytes.Compare(result.Stockholders[i].Account.Bytes(), result.Stockholders[j].Account.Bytes()) < 0
+	})
+	return result, nil
+}
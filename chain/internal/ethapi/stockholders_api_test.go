@@ -0,0 +1,89 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+// Tests that stockholders are read from the stock trie of the requested block,
+// unaffected by later stock changes.
+func TestGetStockholdersAt(t *testing.T) {
+	var (
+		db, _   = ethdb.NewMemDatabase()
+		backend = &statsTestBackend{db: db}
+		manager = common.HexToAddress("0x0a")
+		first   = common.HexToAddress("0x02")
+		second  = common.HexToAddress("0x01")
+	)
+	dposContext, err := types.NewDposContext(db)
+	if err != nil {
+		t.Fatalf("failed to create dpos context: %v", err)
+	}
+	if err := dposContext.SetEpochTrie([]common.Address{manager}); err != nil {
+		t.Fatalf("failed to set validators: %v", err)
+	}
+	bokerContext, err := types.NewBokerContext(db)
+	if err != nil {
+		t.Fatalf("failed to create boker context: %v", err)
+	}
+	if err := bokerContext.SetStockManager(manager, manager, time.Now().Unix(), dposContext); err != nil {
+		t.Fatalf("failed to set stock manager: %v", err)
+	}
+	//区块0只有一个股权账号，区块1增加一个股权账号并冻结
+	commit := func(number int64) {
+		proto, err := bokerContext.CommitTo(db)
+		if err != nil {
+			t.Fatalf("block %d: failed to commit boker context: %v", number, err)
+		}
+		backend.headers = append(backend.headers, &types.Header{Number: big.NewInt(number), DposProto: &types.DposContextProto{}, BokerProto: proto})
+	}
+	if err := bokerContext.SetStock(manager, first, 10); err != nil {
+		t.Fatalf("failed to set stock: %v", err)
+	}
+	commit(0)
+	if err := bokerContext.SetStock(manager, second, 5); err != nil {
+		t.Fatalf("failed to set stock: %v", err)
+	}
+	if err := bokerContext.FrozenStock(manager, second); err != nil {
+		t.Fatalf("failed to freeze stock: %v", err)
+	}
+	commit(1)
+
+	api := NewPublicBokerAPI(backend)
+	result, err := api.GetStockholdersAt(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("block 0: failed to retrieve stockholders: %v", err)
+	}
+	if result.BlockHash != backend.headers[0].Hash() || result.Total != 10 || result.Active != 10 || len(result.Stockholders) != 1 {
+		t.Errorf("block 0: stockholders mismatch: %+v", result)
+	}
+
+	result, err = api.GetStockholdersAt(context.Background(), rpc.LatestBlockNumber)
+	if err != nil {
+		t.Fatalf("latest: failed to retrieve stockholders: %v", err)
+	}
+	if result.BlockNumber != 1 || result.Total != 15 || result.Active != 10 || len(result.Stockholders) != 2 {
+		t.Fatalf("latest: stockholders mismatch: %+v", result)
+	}
+	if holder := result.Stockholders[0]; holder.Account != second || holder.Number != 5 || !holder.Frozen {
+		t.Errorf("latest: first stockholder mismatch: %+v", holder)
+	}
+	if holder := result.Stockholders[1]; holder.Account != first || holder.Number != 10 || holder.Frozen {
+		t.Errorf("latest: second stockholder mismatch: %+v", holder)
+	}
+
+	if _, err := api.GetStockholdersAt(context.Background(), 2); err != protocol.ErrUnknownBlock {
+		t.Errorf("unknown block: have %v, want %v", err, protocol.ErrUnknownBlock)
+	}
+	if _, err := api.GetStockholdersAt(context.Background(), rpc.PendingBlockNumber); err != protocol.ErrUnknownBlock {
+		t.Errorf("pending block: have %v, want %v", err, protocol.ErrUnknownBlock)
+	}
+}
@@ -0,0 +1,135 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//提供区块导入时索引的ERC-20/ERC-721代币转账的查询(需要节点开启--tokenindex)
+type PublicTokenAPI struct {
+	b Backend
+}
+
+func NewPublicTokenAPI(b Backend) *PublicTokenAPI {
+	return &PublicTokenAPI{b}
+}
+
+//代币转账的RPC输出格式
+type RPCTokenTransfer struct {
+	Standard        string         `json:"standard"`
+	Token           common.Address `json:"token"`
+	From            common.Address `json:"from"`
+	To              common.Address `json:"to"`
+	Value           *hexutil.Big   `json:"value,omitempty"`   //ERC-20的转账数量
+	TokenId         *hexutil.Big   `json:"tokenId,omitempty"` //ERC-721的tokenId
+	TransactionHash common.Hash    `json:"transactionHash"`
+	BlockHash       common.Hash    `json:"blockHash"`
+	BlockNumber     hexutil.Uint64 `json:"blockNumber"`
+	LogIndex        hexutil.Uint64 `json:"logIndex"`
+}
+
+//由索引的转账累计的代币余额
+type RPCTokenBalance struct {
+	Token       common.Address `json:"token"`
+	Address     common.Address `json:"address"`
+	Standard    string         `json:"standard,omitempty"` //没有转账记录时为空
+	Balance     *hexutil.Big   `json:"balance"`            //ERC-721为持有的代币数量
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+}
+
+func newRPCTokenTransfer(transfer *types.TokenTransfer) *RPCTokenTransfer {
+
+	result := &RPCTokenTransfer{
+		Standard:        transfer.Standard,
+		Token:           transfer.Token,
+		From:            transfer.From,
+		To:              transfer.To,
+		TransactionHash: transfer.TxHash,
+		BlockHash:       transfer.BlockHash,
+		BlockNumber:     hexutil.Uint64(transfer.BlockNumber),
+		LogIndex:        hexutil.Uint64(transfer.LogIndex),
+	}
+	if transfer.Standard == types.TokenERC721 {
+		result.TokenId = (*hexutil.Big)(transfer.Value)
+	} else {
+		result.Value = (*hexutil.Big)(transfer.Value)
+	}
+	return result
+}
+
+//得到地址在主链上不高于head的代币转账，被分叉替换的区块中的转账不返回
+func (s *PublicTokenAPI) canonicalTransfers(address common.Address, from, to uint64) []*types.TokenTransfer {
+
+	var (
+		db        = s.b.ChainDb()
+		hashes    = make(map[uint64]common.Hash)
+		transfers []*types.TokenTransfer
+	)
+	for _, transfer := range core.GetTokenTransfers(db, address) {
+		if transfer.BlockNumber < from || transfer.BlockNumber > to {
+			continue
+		}
+		hash, ok := hashes[transfer.BlockNumber]
+		if !ok {
+			hash = core.GetCanonicalHash(db, transfer.BlockNumber)
+			hashes[transfer.BlockNumber] = hash
+		}
+		if hash == transfer.BlockHash {
+			transfers = append(transfers, transfer)
+		}
+	}
+	return transfers
+}
+
+//得到地址持有的代币余额，由开启索引之后的转账累计(ERC-20为数量，ERC-721为持有的代币数量)，
+//开启索引之前已经持有的余额不计入
+func (s *PublicTokenAPI) GetBalance(ctx context.Context, address common.Address, token common.Address) *RPCTokenBalance {
+
+	head := s.b.CurrentBlock().NumberU64()
+	result := &RPCTokenBalance{Token: token, Address: address, BlockNumber: hexutil.Uint64(head)}
+
+	balance := new(big.Int)
+	for _, transfer := range s.canonicalTransfers(address, 0, head) {
+		if transfer.Token != token {
+			continue
+		}
+		result.Standard = transfer.Standard
+
+		amount := transfer.Value
+		if transfer.Standard == types.TokenERC721 {
+			amount = common.Big1
+		}
+		if transfer.To == address {
+			balance.Add(balance, amount)
+		}
+		if transfer.From == address {
+			balance.Sub(balance, amount)
+		}
+	}
+	if balance.Sign() < 0 {
+		balance.SetInt64(0)
+	}
+	result.Balance = (*hexutil.Big)(balance)
+	return result
+}
+
+//按时间顺序得到地址在区块范围内转入或转出的代币转账
+func (s *PublicTokenAPI) GetTransfers(ctx context.Context, address common.Address, fromBlock, toBlock rpc.BlockNumber) ([]*RPCTokenTransfer, error) {
+
+	from, to, err := activityRange(s.b, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	transfers := s.canonicalTransfers(address, from, to)
+	result := make([]*RPCTokenTransfer, 0, len(transfers))
+	for _, transfer := range transfers {
+		result = append(result, newRPCTokenTransfer(transfer))
+	}
+	return result, nil
+}
@@ -0,0 +1,36 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+var errTxNameLength = errors.New("transaction name is empty or longer than the indexed length")
+
+//按时间顺序得到区块范围内Name字段等于name的主链交易（需要节点开启--nameindex）
+func (s *PublicTransactionPoolAPI) GetTransactionsByName(ctx context.Context, name string, fromBlock, toBlock rpc.BlockNumber) ([]interface{}, error) {
+
+	if !core.IndexableName([]byte(name)) {
+		return nil, errTxNameLength
+	}
+	from, to, err := activityRange(s.b, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]interface{}, 0)
+	for _, entry := range core.GetTxNames(s.b.ChainDb(), []byte(name)) {
+		if entry.BlockNumber < from || entry.BlockNumber > to {
+			continue
+		}
+		//交易查找表只指向主链区块，被分叉替换的区块中的交易不返回
+		tx, blockHash, number, index := core.GetTransaction(s.b.ChainDb(), entry.TxHash)
+		if tx == nil || blockHash != entry.BlockHash {
+			continue
+		}
+		result = append(result, outputTransaction(newRPCTransaction(tx, blockHash, number, index), s.b.EthCompatible()))
+	}
+	return result, nil
+}
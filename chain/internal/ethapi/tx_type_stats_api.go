@@ -0,0 +1,112 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+var errTxTypeStatsRange = errors.New("invalid block range for tx type statistics")
+
+//一种交易类型在区块范围内的用量
+type RPCTxTypeUsage struct {
+	Major      protocol.TxMajor `json:"major"`
+	MajorNotes string           `json:"majorNotes"`
+	Minor      protocol.TxMinor `json:"minor"`
+	MinorNotes string           `json:"minorNotes"`
+	Txs        hexutil.Uint64   `json:"txs"`
+	GasUsed    hexutil.Uint64   `json:"gasUsed"`
+	Bytes      hexutil.Uint64   `json:"bytes"`
+}
+
+//区块范围内按交易类型的统计，统计区间为[fromBlock, toBlock]
+type RPCTxTypeStats struct {
+	FromBlock hexutil.Uint64   `json:"fromBlock"`
+	ToBlock   hexutil.Uint64   `json:"toBlock"`
+	Types     []RPCTxTypeUsage `json:"types"`
+}
+
+//得到区块范围内每种交易类型的交易数、使用的Gas以及交易编码的字节数(由区块导入时累计的统计相减得到)，
+//开始区块在开始统计之前时从开始统计的区块算起
+func (s *PublicBokerAPI) GetTxTypeStats(ctx context.Context, fromBlock, toBlock rpc.BlockNumber) (*RPCTxTypeStats, error) {
+
+	if fromBlock == rpc.PendingBlockNumber || toBlock == rpc.PendingBlockNumber {
+		return nil, protocol.ErrUnknownBlock
+	}
+	to, err := s.b.HeaderByNumber(ctx, toBlock)
+	if err != nil || to == nil {
+		return nil, errNoBlockStats
+	}
+	from := uint64(fromBlock)
+	if fromBlock == rpc.LatestBlockNumber {
+		from = to.Number.Uint64()
+	}
+	if from > to.Number.Uint64() {
+		return nil, errTxTypeStatsRange
+	}
+
+	db := s.b.ChainDb()
+	toStats := core.GetTxTypeStats(db, to.Hash(), to.Number.Uint64())
+	if toStats == nil {
+		return nil, errNoBlockStats
+	}
+	var baseStats *types.TxTypeStats
+	if from <= toStats.First {
+		from = toStats.First
+	} else {
+		base, err := s.b.HeaderByNumber(ctx, rpc.BlockNumber(from-1))
+		if err != nil || base == nil {
+			return nil, errNoBlockStats
+		}
+		if baseStats = core.GetTxTypeStats(db, base.Hash(), from-1); baseStats == nil {
+			return nil, errNoBlockStats
+		}
+	}
+	return &RPCTxTypeStats{
+		FromBlock: hexutil.Uint64(from),
+		ToBlock:   hexutil.Uint64(to.Number.Uint64()),
+		Types:     subTxTypeStats(toStats, baseStats),
+	}, nil
+}
+
+//两个区块的累计统计相减，去掉区间内没有出现的交易类型
+func subTxTypeStats(stats, base *types.TxTypeStats) []RPCTxTypeUsage {
+
+	type txType struct {
+		major protocol.TxMajor
+		minor protocol.TxMinor
+	}
+	prior := make(map[txType]*types.TxTypeUsage)
+	if base != nil {
+		for _, usage := range base.Types {
+			prior[txType{usage.Major, usage.Minor}] = usage
+		}
+	}
+
+	results := make([]RPCTxTypeUsage, 0, len(stats.Types))
+	for _, usage := range stats.Types {
+		result := RPCTxTypeUsage{
+			Major:   usage.Major,
+			Minor:   usage.Minor,
+			Txs:     hexutil.Uint64(usage.Txs),
+			GasUsed: hexutil.Uint64(usage.GasUsed),
+			Bytes:   hexutil.Uint64(usage.Bytes),
+		}
+		if p := prior[txType{usage.Major, usage.Minor}]; p != nil {
+			result.Txs -= hexutil.Uint64(p.Txs)
+			result.GasUsed -= hexutil.Uint64(p.GasUsed)
+			result.Bytes -= hexutil.Uint64(p.Bytes)
+		}
+		if result.Txs == 0 {
+			continue
+		}
+		result.MajorNotes, result.MinorNotes = txTypeNotes(usage.Major, usage.Minor)
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,90 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+// statsTestBackend serves a canonical chain of headers and its database, the
+// rest of the backend is left unimplemented.
+type statsTestBackend struct {
+	Backend
+	db      ethdb.Database
+	headers []*types.Header
+}
+
+func (b *statsTestBackend) ChainDb() ethdb.Database {
+	return b.db
+}
+
+func (b *statsTestBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if number == rpc.LatestBlockNumber {
+		return b.headers[len(b.headers)-1], nil
+	}
+	if int(number) >= len(b.headers) {
+		return nil, nil
+	}
+	return b.headers[number], nil
+}
+
+func TestGetTxTypeStats(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	backend := &statsTestBackend{db: db}
+
+	// Block 1 carries a transfer and a word, block 2 two transfers
+	var (
+		to       = common.HexToAddress("0xc0de")
+		transfer = func(nonce uint64) *types.Transaction {
+			return types.NewTransaction(protocol.Normal, protocol.NormalCall, nonce, to, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil)
+		}
+		word    = types.NewTransaction(protocol.Extra, protocol.Word, 1, to, big.NewInt(0), big.NewInt(50000), big.NewInt(1), []byte("hello"))
+		receipt = func(gas int64) *types.Receipt { return &types.Receipt{GasUsed: big.NewInt(gas)} }
+		bodies  = [][]*types.Transaction{nil, {transfer(0), word}, {transfer(2), transfer(3)}}
+		gas     = [][]*types.Receipt{nil, {receipt(21000), receipt(30000)}, {receipt(21000), receipt(21000)}}
+	)
+	var parent *types.TxTypeStats
+	for i, txs := range bodies {
+		block := types.NewBlock(&types.Header{Number: big.NewInt(int64(i))}, txs, nil, nil)
+		if err := core.WriteTxTypeStats(db, parent, block, gas[i]); err != nil {
+			t.Fatalf("block %d: failed to write stats: %v", i, err)
+		}
+		parent = core.GetTxTypeStats(db, block.Hash(), block.NumberU64())
+		backend.headers = append(backend.headers, block.Header())
+	}
+	api := NewPublicBokerAPI(backend)
+
+	stats, err := api.GetTxTypeStats(context.Background(), 0, rpc.LatestBlockNumber)
+	if err != nil {
+		t.Fatalf("failed to retrieve stats: %v", err)
+	}
+	if stats.FromBlock != 0 || stats.ToBlock != 2 || len(stats.Types) != 2 {
+		t.Fatalf("stats mismatch: %+v", stats)
+	}
+	if normal := stats.Types[0]; normal.Major != protocol.Normal || normal.Txs != 3 || normal.GasUsed != 63000 || normal.Bytes != 3*hexutil.Uint64(transfer(0).Size()) {
+		t.Errorf("normal usage mismatch: %+v", normal)
+	}
+	if extra := stats.Types[1]; extra.Major != protocol.Extra || extra.MinorNotes != "Word" || extra.Txs != 1 || extra.GasUsed != 30000 {
+		t.Errorf("extra usage mismatch: %+v", extra)
+	}
+
+	// The range only covers the second block, the word drops out
+	stats, err = api.GetTxTypeStats(context.Background(), 2, 2)
+	if err != nil {
+		t.Fatalf("failed to retrieve stats: %v", err)
+	}
+	if len(stats.Types) != 1 || stats.Types[0].Txs != 2 || stats.Types[0].GasUsed != 42000 {
+		t.Errorf("range usage mismatch: %+v", stats.Types)
+	}
+	if _, err := api.GetTxTypeStats(context.Background(), 2, 1); err != errTxTypeStatsRange {
+		t.Errorf("inverted range: have %v, want %v", err, errTxTypeStatsRange)
+	}
+}
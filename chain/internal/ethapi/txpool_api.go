@@ -0,0 +1,60 @@
+package ethapi
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+)
+
+var errNonceRange = errors.New("fromNonce must not be greater than toNonce")
+
+//交易池中的交易及其当前状态
+type RPCPoolTransaction struct {
+	*RPCTransaction
+	Status     string         `json:"status"`     //pending(可执行)或queued(排队)
+	AddedAt    hexutil.Uint64 `json:"addedAt"`    //加入交易池的时间(Unix秒)
+	TimeInPool hexutil.Uint64 `json:"timeInPool"` //在交易池中停留的秒数
+}
+
+func newRPCPoolTransaction(ptx *core.PoolTx) *RPCPoolTransaction {
+
+	result := &RPCPoolTransaction{
+		RPCTransaction: newRPCPendingTransaction(ptx.Tx),
+		Status:         "queued",
+	}
+	result.From = ptx.From
+	if ptx.Status == core.TxStatusPending {
+		result.Status = "pending"
+	}
+	if !ptx.Added.IsZero() {
+		result.AddedAt = hexutil.Uint64(ptx.Added.Unix())
+		result.TimeInPool = hexutil.Uint64(time.Since(ptx.Added) / time.Second)
+	}
+	return result
+}
+
+//根据交易哈希得到交易池中的交易及其状态，交易不在交易池中时返回nil
+func (s *PublicTxPoolAPI) GetTransaction(hash common.Hash) *RPCPoolTransaction {
+
+	if ptx := s.b.PoolTransaction(hash); ptx != nil {
+		return newRPCPoolTransaction(ptx)
+	}
+	return nil
+}
+
+//得到交易池中账号Nonce在[fromNonce, toNonce]范围内的交易，按Nonce排序
+func (s *PublicTxPoolAPI) ContentForNonceRange(address common.Address, fromNonce, toNonce hexutil.Uint64) ([]*RPCPoolTransaction, error) {
+
+	if fromNonce > toNonce {
+		return nil, errNonceRange
+	}
+	ptxs := s.b.PoolTransactionsByNonce(address, uint64(fromNonce), uint64(toNonce))
+	txs := make([]*RPCPoolTransaction, 0, len(ptxs))
+	for _, ptx := range ptxs {
+		txs = append(txs, newRPCPoolTransaction(ptx))
+	}
+	return txs, nil
+}
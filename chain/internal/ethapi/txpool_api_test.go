@@ -0,0 +1,83 @@
+package ethapi
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+)
+
+type txPoolTestBackend struct {
+	Backend
+	txs []*core.PoolTx
+}
+
+func (b *txPoolTestBackend) PoolTransaction(hash common.Hash) *core.PoolTx {
+	for _, ptx := range b.txs {
+		if ptx.Tx.Hash() == hash {
+			return ptx
+		}
+	}
+	return nil
+}
+
+func (b *txPoolTestBackend) PoolTransactionsByNonce(addr common.Address, from, to uint64) []*core.PoolTx {
+	var ptxs []*core.PoolTx
+	for _, ptx := range b.txs {
+		if ptx.From == addr && ptx.Tx.Nonce() >= from && ptx.Tx.Nonce() <= to {
+			ptxs = append(ptxs, ptx)
+		}
+	}
+	return ptxs
+}
+
+// Tests that pooled transactions are returned with their sender, status and
+// time in the pool, and that inverted nonce ranges are rejected.
+func TestTxPoolLookup(t *testing.T) {
+	var (
+		from  = common.HexToAddress("0x01")
+		added = time.Now().Add(-time.Minute)
+		newTx = func(nonce uint64) *types.Transaction {
+			return types.NewTransaction(protocol.Normal, protocol.NormalCall, nonce, common.Address{0x02}, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil)
+		}
+		pending = &core.PoolTx{Tx: newTx(0), From: from, Status: core.TxStatusPending, Added: added}
+		queued  = &core.PoolTx{Tx: newTx(2), From: from, Status: core.TxStatusQueued}
+		api     = NewPublicTxPoolAPI(&txPoolTestBackend{txs: []*core.PoolTx{pending, queued}})
+	)
+	tx := api.GetTransaction(pending.Tx.Hash())
+	if tx == nil {
+		t.Fatalf("pending transaction not found")
+	}
+	if tx.From != from || tx.Status != "pending" || int64(tx.AddedAt) != added.Unix() || tx.TimeInPool < 59 {
+		t.Errorf("pending transaction mismatch: from %x, status %s, added %d, in pool %d", tx.From, tx.Status, tx.AddedAt, tx.TimeInPool)
+	}
+	if tx.BlockHash != (common.Hash{}) || tx.BlockNumber != nil {
+		t.Errorf("pooled transaction has a block: %x #%v", tx.BlockHash, tx.BlockNumber)
+	}
+	//恢复的交易没有加入时间
+	if tx := api.GetTransaction(queued.Tx.Hash()); tx == nil || tx.Status != "queued" || tx.AddedAt != 0 || tx.TimeInPool != 0 {
+		t.Errorf("queued transaction mismatch: %+v", tx)
+	}
+	if tx := api.GetTransaction(common.Hash{0x01}); tx != nil {
+		t.Errorf("unknown transaction found: %+v", tx)
+	}
+
+	txs, err := api.ContentForNonceRange(from, 0, 5)
+	if err != nil {
+		t.Fatalf("failed to get nonce range: %v", err)
+	}
+	if len(txs) != 2 || txs[0].Status != "pending" || txs[1].Status != "queued" {
+		t.Errorf("nonce range mismatch: %v", txs)
+	}
+	//没有交易时返回空数组而不是null
+	if txs, err := api.ContentForNonceRange(from, 3, 5); err != nil || txs == nil || len(txs) != 0 {
+		t.Errorf("empty nonce range mismatch: have %v, err %v", txs, err)
+	}
+	if _, err := api.ContentForNonceRange(from, 5, 3); err != errNonceRange {
+		t.Errorf("inverted nonce range: have %v, want %v", err, errNonceRange)
+	}
+}
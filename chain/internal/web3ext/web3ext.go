@@ -19,20 +19,189 @@ package web3ext
 
 var Modules = map[string]string{
 	"admin":      Admin_JS,
+	"boker":      Boker_JS,
+	"bridge":     Bridge_JS,
 	"chequebook": Chequebook_JS,
 	"clique":     Clique_JS,
 	"debug":      Debug_JS,
+	"delegation": Delegation_JS,
+	"deposit":    Deposit_JS,
+	"downloader": Downloader_JS,
 	"eth":        Eth_JS,
+	"gov":        Gov_JS,
 	"miner":      Miner_JS,
 	"net":        Net_JS,
 	"personal":   Personal_JS,
 	"rpc":        RPC_JS,
 	"shh":        Shh_JS,
+	"stats":      Stats_JS,
 	"swarmfs":    SWARMFS_JS,
+	"token":      Token_JS,
 	"txpool":     TxPool_JS,
 	"dpos":       Dpos_JS,
 }
 
+const Boker_JS = `
+web3._extend({
+	property: 'boker',
+	methods: [
+		new web3._extend.Method({
+			name: 'getBlockByNumber',
+			call: 'boker_getBlockByNumber',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'getBlockByHash',
+			call: 'boker_getBlockByHash',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionByHash',
+			call: 'boker_getTransactionByHash',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionReceipt',
+			call: 'boker_getTransactionReceipt',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'buildSystemTx',
+			call: 'boker_buildSystemTx',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'validateTransaction',
+			call: 'boker_validateTransaction',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'multiSend',
+			call: 'boker_multiSend',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'fundGasPool',
+			call: 'boker_fundGasPool',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'getGasPoolContribution',
+			call: 'boker_getGasPoolContribution',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter],
+			outputFormatter: web3._extend.utils.toBigNumber
+		}),
+		new web3._extend.Method({
+			name: 'getGasPoolFunding',
+			call: 'boker_getGasPoolFunding',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getStockholdersAt',
+			call: 'boker_getStockholdersAt',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'buildMultiSend',
+			call: 'boker_buildMultiSend',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'exportOfflineTx',
+			call: 'boker_exportOfflineTx',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'decodeOfflineTx',
+			call: 'boker_decodeOfflineTx',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'importOfflineSignature',
+			call: 'boker_importOfflineSignature',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'getBlockRewards',
+			call: 'boker_getBlockRewards',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getAccountActivity',
+			call: 'boker_getAccountActivity',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'getTxTypeStats',
+			call: 'boker_getTxTypeStats',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getRichList',
+			call: 'boker_getRichList',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'getTotalSupply',
+			call: 'boker_getTotalSupply',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getBaseContracts',
+			call: 'boker_getBaseContracts',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getDeployers',
+			call: 'boker_getDeployers',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getSigningKey',
+			call: 'boker_getSigningKey',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getPendingSystemContract',
+			call: 'boker_getPendingSystemContract',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'callContract',
+			call: 'boker_callContract',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'registerContractMeta',
+			call: 'boker_registerContractMeta',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getContractMeta',
+			call: 'boker_getContractMeta',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+	]
+});
+`
+
 const Chequebook_JS = `
 web3._extend({
 	property: 'chequebook',
@@ -80,6 +249,39 @@ web3._extend({
 			params: 0,
 			outputFormatter: web3._extend.utils.toBigNumber
 		}),
+		new web3._extend.Method({
+			name: 'previewBlock',
+			call: 'dpos_previewBlock',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getValidatorLiveness',
+			call: 'dpos_getValidatorLiveness',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getClockDrift',
+			call: 'dpos_getClockDrift',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getValidatorsDetailed',
+			call: 'dpos_getValidatorsDetailed',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getVotesAt',
+			call: 'dpos_getVotesAt',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getValidatorInfo',
+			call: 'dpos_getValidatorInfo',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
 	]
 });
 `
@@ -155,6 +357,76 @@ web3._extend({
 			call: 'admin_importChain',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'exportCheckpoint',
+			call: 'admin_exportCheckpoint',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'verifyChain',
+			call: 'admin_verifyChain',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'verifyChainStatus',
+			call: 'admin_verifyChainStatus',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'abortVerifyChain',
+			call: 'admin_abortVerifyChain',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'repairChain',
+			call: 'admin_repairChain',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'createSnapshot',
+			call: 'admin_createSnapshot',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'restoreSnapshot',
+			call: 'admin_restoreSnapshot',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'setHTTPCors',
+			call: 'admin_setHTTPCors',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'setHTTPVirtualHosts',
+			call: 'admin_setHTTPVirtualHosts',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'setHTTPModules',
+			call: 'admin_setHTTPModules',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'auditLog',
+			call: 'admin_auditLog',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'rpcStats',
+			call: 'admin_rpcStats'
+		}),
+		new web3._extend.Method({
+			name: 'resetRpcStats',
+			call: 'admin_resetRpcStats'
+		}),
+		new web3._extend.Method({
+			name: 'nodeStatus',
+			call: 'admin_nodeStatus'
+		}),
 		new web3._extend.Method({
 			name: 'sleepBlocks',
 			call: 'admin_sleepBlocks',
@@ -194,6 +466,10 @@ web3._extend({
 			name: 'datadir',
 			getter: 'admin_datadir'
 		}),
+		new web3._extend.Property({
+			name: 'httpConfig',
+			getter: 'admin_httpConfig'
+		}),
 	]
 });
 `
@@ -370,6 +646,22 @@ web3._extend({
 			call: 'debug_getBadBlocks',
 			params: 0,
 		}),
+		new web3._extend.Method({
+			name: 'getBlockImportStats',
+			call: 'debug_getBlockImportStats',
+			params: 0,
+		}),
+		new web3._extend.Method({
+			name: 'verifyBloomIndex',
+			call: 'debug_verifyBloomIndex',
+			params: 2,
+			inputFormatter: [null, null],
+		}),
+		new web3._extend.Method({
+			name: 'rebuildBloomIndex',
+			call: 'debug_rebuildBloomIndex',
+			params: 1,
+		}),
 		new web3._extend.Method({
 			name: 'storageRangeAt',
 			call: 'debug_storageRangeAt',
@@ -414,12 +706,35 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'getRawBlockByNumber',
+			call: 'eth_getRawBlockByNumber',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getRawHeaderByNumber',
+			call: 'eth_getRawHeaderByNumber',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'sponsorTransaction',
+			call: 'eth_sponsorTransaction',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputTransactionFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'submitTransaction',
 			call: 'eth_submitTransaction',
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'decodeRawTransaction',
+			call: 'eth_decodeRawTransaction',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'getRawTransaction',
 			call: 'eth_getRawTransactionByHash',
@@ -433,6 +748,84 @@ web3._extend({
 			params: 2,
 			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.utils.toHex]
 		}),
+		new web3._extend.Method({
+			name: 'getInternalTransactions',
+			call: 'eth_getInternalTransactions',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getInternalTransactionsByAddress',
+			call: 'eth_getInternalTransactionsByAddress',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'syncProgressDetailed',
+			call: 'eth_syncProgressDetailed',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionsByName',
+			call: 'eth_getTransactionsByName',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getBlockNumberByHash',
+			call: 'eth_getBlockNumberByHash',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getHeaderByHash',
+			call: 'eth_getHeaderByHash',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'scheduleRawTransaction',
+			call: 'eth_scheduleRawTransaction',
+			params: 2,
+			inputFormatter: [null, web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'rebroadcastTransaction',
+			call: 'eth_rebroadcastTransaction',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getProof',
+			call: 'eth_getProof',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getHeadersByRange',
+			call: 'eth_getHeadersByRange',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'watchAddress',
+			call: 'eth_watchAddress',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'unwatchAddress',
+			call: 'eth_unwatchAddress',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'watchedAddresses',
+			call: 'eth_watchedAddresses',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'callMany',
+			call: 'eth_callMany',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'getLastProducer',
 			call: 'eth_getLastProducer',
@@ -448,6 +841,34 @@ web3._extend({
 			call: 'eth_setSystemBaseContracts',
 			params: 1,		
 		}),
+		new web3._extend.Method({
+			name: 'confirmSystemBaseContracts',
+			call: 'eth_confirmSystemBaseContracts',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'approveDeployer',
+			call: 'eth_approveDeployer',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'revokeDeployer',
+			call: 'eth_revokeDeployer',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'setSigningKey',
+			call: 'eth_setSigningKey',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'setValidatorInfo',
+			call: 'eth_setValidatorInfo',
+			params: 3
+		}),
 		new web3._extend.Method({
 			name: 'setUserBaseContracts',
 			call: 'eth_setUserBaseContracts',
@@ -463,6 +884,11 @@ web3._extend({
 			call: 'eth_addValidator',
 			params: 2,
 		}),	
+		new web3._extend.Method({
+			name: 'setRewardSchedule',
+			call: 'eth_setRewardSchedule',
+			params: 2,
+		}),
 		new web3._extend.Method({
 			name: 'getBlockValidator',
 			call: 'eth_getBlockValidator',
@@ -539,6 +965,11 @@ web3._extend({
 			call: 'eth_getStockManager',
 			params: 0,
 		}),
+		new web3._extend.Method({
+			name: 'getTransactionStatus',
+			call: 'eth_getTransactionStatus',
+			params: 1,
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -553,6 +984,41 @@ web3._extend({
 				return formatted;
 			}
 		}),
+		new web3._extend.Property({
+			name: 'chainId',
+			getter: 'eth_chainId',
+			outputFormatter: web3._extend.utils.toDecimal
+		}),
+	]
+});
+`
+
+const Gov_JS = `
+web3._extend({
+	property: 'gov',
+	methods: [
+		new web3._extend.Method({
+			name: 'propose',
+			call: 'gov_propose',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'vote',
+			call: 'gov_vote',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'getProposal',
+			call: 'gov_getProposal',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getProposals',
+			call: 'gov_getProposals',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 	]
 });
 `
@@ -597,6 +1063,16 @@ web3._extend({
 			name: 'getHashrate',
 			call: 'miner_getHashrate'
 		}),
+		new web3._extend.Method({
+			name: 'status',
+			call: 'miner_status'
+		}),
+		new web3._extend.Method({
+			name: 'getEarnings',
+			call: 'miner_getEarnings',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 	],
 	properties: []
 });
@@ -611,6 +1087,10 @@ web3._extend({
 			name: 'version',
 			getter: 'net_version'
 		}),
+		new web3._extend.Property({
+			name: 'nodeInfo',
+			getter: 'net_nodeInfo'
+		}),
 	]
 });
 `
@@ -630,6 +1110,16 @@ web3._extend({
 			params: 3,
 			inputFormatter: [null, web3._extend.formatters.inputAddressFormatter, null]
 		}),
+		new web3._extend.Method({
+			name: 'signOfflineTx',
+			call: 'personal_signOfflineTx',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'cancelScheduledTransaction',
+			call: 'personal_cancelScheduledTransaction',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'ecRecover',
 			call: 'personal_ecRecover',
@@ -688,6 +1178,188 @@ web3._extend({
 });
 `
 
+const Stats_JS = `
+web3._extend({
+	property: 'stats',
+	methods: [
+		new web3._extend.Method({
+			name: 'blockInterval',
+			call: 'stats_blockInterval',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'txsPerBlock',
+			call: 'stats_txsPerBlock',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'gasUsedRatio',
+			call: 'stats_gasUsedRatio',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'extraBytesPerDay',
+			call: 'stats_extraBytesPerDay',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'activeAddresses',
+			call: 'stats_activeAddresses',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+	]
+});
+`
+
+const Downloader_JS = `
+web3._extend({
+	property: 'downloader',
+	methods: [
+		new web3._extend.Method({
+			name: 'stats',
+			call: 'downloader_stats',
+			params: 0
+		}),
+	]
+});
+`
+
+const Token_JS = `
+web3._extend({
+	property: 'token',
+	methods: [
+		new web3._extend.Method({
+			name: 'getBalance',
+			call: 'token_getBalance',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getTransfers',
+			call: 'token_getTransfers',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+	]
+});
+`
+
+const Bridge_JS = `
+web3._extend({
+	property: 'bridge',
+	methods: [
+		new web3._extend.Method({
+			name: 'lock',
+			call: 'bridge_lock',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputAddressFormatter, web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'release',
+			call: 'bridge_release',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getLockCount',
+			call: 'bridge_getLockCount',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getLock',
+			call: 'bridge_getLock',
+			params: 2,
+			inputFormatter: [web3._extend.utils.fromDecimal, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getRelease',
+			call: 'bridge_getRelease',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+	]
+});
+`
+
+const Delegation_JS = `
+web3._extend({
+	property: 'delegation',
+	methods: [
+		new web3._extend.Method({
+			name: 'setCommission',
+			call: 'delegation_setCommission',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'claimRewards',
+			call: 'delegation_claimRewards',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getCommission',
+			call: 'delegation_getCommission',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getRewards',
+			call: 'delegation_getRewards',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+	]
+});
+`
+
+const Deposit_JS = `
+web3._extend({
+	property: 'deposit',
+	methods: [
+		new web3._extend.Method({
+			name: 'track',
+			call: 'deposit_track',
+			params: 4,
+			inputFormatter: [null, null, web3._extend.utils.fromDecimal, web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'untrack',
+			call: 'deposit_untrack',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getAddresses',
+			call: 'deposit_getAddresses',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getBalances',
+			call: 'deposit_getBalances',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getIncoming',
+			call: 'deposit_getIncoming',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+	],
+	properties: [
+		new web3._extend.Property({
+			name: 'list',
+			getter: 'deposit_list'
+		}),
+	]
+});
+`
+
 const SWARMFS_JS = `
 web3._extend({
 	property: 'swarmfs',
@@ -715,7 +1387,19 @@ web3._extend({
 const TxPool_JS = `
 web3._extend({
 	property: 'txpool',
-	methods: [],
+	methods: [
+		new web3._extend.Method({
+			name: 'getTransaction',
+			call: 'txpool_getTransaction',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'contentForNonceRange',
+			call: 'txpool_contentForNonceRange',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.utils.fromDecimal, web3._extend.utils.fromDecimal]
+		}),
+	],
 	properties:
 	[
 		new web3._extend.Property({
@@ -726,6 +1410,10 @@ web3._extend({
 			name: 'inspect',
 			getter: 'txpool_inspect'
 		}),
+		new web3._extend.Property({
+			name: 'scheduled',
+			getter: 'txpool_scheduled'
+		}),
 		new web3._extend.Property({
 			name: 'status',
 			getter: 'txpool_status',
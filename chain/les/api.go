@@ -0,0 +1,61 @@
+package les
+
+import (
+	"context"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/light"
+)
+
+// PublicLightAPI provides access to light client specific chain queries that are
+// answered by LES servers and verified locally.
+type PublicLightAPI struct {
+	les *LightEthereum
+}
+
+// NewPublicLightAPI creates a new light client API.
+func NewPublicLightAPI(les *LightEthereum) *PublicLightAPI {
+	return &PublicLightAPI{les: les}
+}
+
+//交易状态的名称
+var txStatusNames = map[core.TxStatus]string{
+	core.TxStatusUnknown:  "unknown",
+	core.TxStatusQueued:   "queued",
+	core.TxStatusPending:  "pending",
+	core.TxStatusIncluded: "included",
+}
+
+// GetTransactionStatus returns the status of a transaction. For included
+// transactions the block position and receipt are returned as well, both proven
+// against the locally synced header chain.
+func (api *PublicLightAPI) GetTransactionStatus(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+	status, err := light.GetTransactionStatus(ctx, api.les.odr, hash)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{
+		"transactionHash": hash,
+		"status":          txStatusNames[status.Status],
+	}
+	if status.Status != core.TxStatusIncluded {
+		return fields, nil
+	}
+	fields["blockHash"] = status.BlockHash
+	fields["blockNumber"] = hexutil.Uint64(status.BlockNumber)
+	fields["transactionIndex"] = hexutil.Uint64(status.Index)
+	fields["gasUsed"] = (*hexutil.Big)(status.Receipt.GasUsed)
+	fields["cumulativeGasUsed"] = (*hexutil.Big)(status.Receipt.CumulativeGasUsed)
+	fields["logs"] = status.Receipt.Logs
+	if len(status.Receipt.PostState) > 0 {
+		fields["root"] = hexutil.Bytes(status.Receipt.PostState)
+	} else {
+		fields["receiptStatus"] = hexutil.Uint(status.Receipt.Status)
+	}
+	if status.Receipt.ContractAddress != (common.Address{}) {
+		fields["contractAddress"] = status.Receipt.ContractAddress
+	}
+	return fields, nil
+}
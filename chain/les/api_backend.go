@@ -18,11 +18,12 @@ package les
 
 import (
 	"context"
-	"errors"
 	"math/big"
+	"time"
 
 	"github.com/Tinachain/Tina/chain/accounts"
 	"github.com/Tinachain/Tina/chain/boker/api"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/common/math"
 	"github.com/Tinachain/Tina/chain/core"
@@ -34,6 +35,7 @@ import (
 	"github.com/Tinachain/Tina/chain/eth/gasprice"
 	"github.com/Tinachain/Tina/chain/ethdb"
 	"github.com/Tinachain/Tina/chain/event"
+	"github.com/Tinachain/Tina/chain/internal/ethapi"
 	"github.com/Tinachain/Tina/chain/light"
 	"github.com/Tinachain/Tina/chain/params"
 	"github.com/Tinachain/Tina/chain/rpc"
@@ -48,13 +50,44 @@ func (b *LesApiBackend) ChainConfig() *params.ChainConfig {
 	return b.eth.chainConfig
 }
 
+func (b *LesApiBackend) EthCompatible() bool {
+	return b.eth.config.EthCompatible
+}
+
+func (b *LesApiBackend) UnprotectedTxsAllowed() bool {
+	return b.eth.config.AllowUnprotectedTxs
+}
+
+func (b *LesApiBackend) RPCTimeout(method string) time.Duration {
+	return b.eth.config.RPCDeadline(method)
+}
+
+//轻节点不跟踪区块的确认状态，不缓存查询结果
+func (b *LesApiBackend) FinalizedNumber() uint64 {
+	return 0
+}
+
+func (b *LesApiBackend) ResponseCache() *ethapi.ResponseCache {
+	return nil
+}
+
+func (b *LesApiBackend) PendingSpeculative() bool {
+	return false
+}
+
 func (b *LesApiBackend) CurrentBlock() *types.Block {
 	return types.NewBlockWithHeader(b.eth.BlockChain().CurrentHeader())
 }
 
-func (b *LesApiBackend) SetHead(number uint64) {
+func (b *LesApiBackend) SetHead(number uint64) (*core.RewindReport, error) {
+	from := b.eth.blockchain.CurrentHeader().Number.Uint64()
+
 	b.eth.protocolManager.downloader.Cancel()
 	b.eth.blockchain.SetHead(number)
+
+	//轻节点没有区块体和交易池，只回滚区块头
+	to := b.eth.blockchain.CurrentHeader().Number.Uint64()
+	return &core.RewindReport{From: from, To: to, Dropped: make(map[common.Hash]string)}, nil
 }
 
 func (b *LesApiBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error) {
@@ -65,6 +98,10 @@ func (b *LesApiBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNum
 	return b.eth.blockchain.GetHeaderByNumberOdr(ctx, uint64(blockNr))
 }
 
+func (b *LesApiBackend) HeaderByHash(ctx context.Context, blockHash common.Hash) (*types.Header, error) {
+	return b.eth.blockchain.GetHeaderByHash(blockHash), nil
+}
+
 func (b *LesApiBackend) BlockByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Block, error) {
 	header, err := b.HeaderByNumber(ctx, blockNr)
 	if header == nil || err != nil {
@@ -81,6 +118,14 @@ func (b *LesApiBackend) StateAndHeaderByNumber(ctx context.Context, blockNr rpc.
 	return light.NewState(ctx, header, b.eth.odr), header, nil
 }
 
+func (b *LesApiBackend) StateAndHeaderByHash(ctx context.Context, blockHash common.Hash) (*state.StateDB, *types.Header, error) {
+	header, err := b.HeaderByHash(ctx, blockHash)
+	if header == nil || err != nil {
+		return nil, nil, err
+	}
+	return light.NewState(ctx, header, b.eth.odr), header, nil
+}
+
 func (b *LesApiBackend) GetBlock(ctx context.Context, blockHash common.Hash) (*types.Block, error) {
 	return b.eth.blockchain.GetBlockByHash(ctx, blockHash)
 }
@@ -103,6 +148,38 @@ func (b *LesApiBackend) SendTx(ctx context.Context, signedTx *types.Transaction)
 	return b.eth.txPool.Add(ctx, signedTx)
 }
 
+//轻节点的交易池不保存定时交易
+func (b *LesApiBackend) ScheduleTx(ctx context.Context, signedTx *types.Transaction, block uint64) error {
+	return protocol.ErrNotSupportedInLightMode
+}
+
+//轻节点没有完整的状态，不能试执行交易
+func (b *LesApiBackend) DryRunTx(ctx context.Context, from common.Address, tx *types.Transaction) (*types.Receipt, error) {
+	return nil, protocol.ErrNotSupportedInLightMode
+}
+
+func (b *LesApiBackend) ScheduledTxs() []*core.ScheduledTx {
+	return nil
+}
+
+func (b *LesApiBackend) CancelScheduledTx(txHash common.Hash) bool {
+	return false
+}
+
+//轻节点的交易由服务节点转发，不支持重新广播
+func (b *LesApiBackend) RebroadcastTx(txHash common.Hash) bool {
+	return false
+}
+
+//轻节点的交易池不区分可执行和排队的交易
+func (b *LesApiBackend) PoolTransaction(txHash common.Hash) *core.PoolTx {
+	return nil
+}
+
+func (b *LesApiBackend) PoolTransactionsByNonce(addr common.Address, from, to uint64) []*core.PoolTx {
+	return nil
+}
+
 func (b *LesApiBackend) RemoveTx(txHash common.Hash) {
 	b.eth.txPool.RemoveTx(txHash)
 }
@@ -187,7 +264,7 @@ func (b *LesApiBackend) ServiceFilter(ctx context.Context, session *bloombits.Ma
 
 func (b *LesApiBackend) Coinbase() (common.Address, error) {
 
-	return common.Address{}, errors.New("Les Type Have`t Coinbase")
+	return common.Address{}, protocol.ErrNotSupportedInLightMode
 }
 
 func (b *LesApiBackend) SetCoinbase(coinbase common.Address) {
@@ -218,3 +295,15 @@ func (b *LesApiBackend) GetLocalValidator() common.Address {
 
 	return b.eth.GetLocalValidator()
 }
+
+func (b *LesApiBackend) GetStock(ctx context.Context, address common.Address) (*protocol.StockAccount, error) {
+	return light.GetStock(ctx, b.eth.odr, b.eth.blockchain.CurrentHeader(), address)
+}
+
+func (b *LesApiBackend) GetStockManager(ctx context.Context) (common.Address, error) {
+	return light.GetStockManager(ctx, b.eth.odr, b.eth.blockchain.CurrentHeader())
+}
+
+func (b *LesApiBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error) {
+	return light.GetTransaction(ctx, b.eth.odr, txHash)
+}
@@ -23,7 +23,9 @@ import (
 	"time"
 
 	"github.com/Tinachain/Tina/chain/accounts"
+	"github.com/Tinachain/Tina/chain/accounts/secretstore"
 	"github.com/Tinachain/Tina/chain/boker/api"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/common/hexutil"
 	"github.com/Tinachain/Tina/chain/consensus"
@@ -48,6 +50,7 @@ import (
 )
 
 type LightEthereum struct {
+	config                                     *eth.Config
 	odr                                        *LesOdr
 	relay                                      *LesTxRelay
 	chainConfig                                *params.ChainConfig
@@ -69,11 +72,15 @@ type LightEthereum struct {
 	networkId                                  uint64
 	netRPCService                              *ethapi.PublicNetAPI
 	wg                                         sync.WaitGroup
-	password                                   string       //挖矿账号的密码
-	boker                                      bokerapi.Api //Tina链新增加的接口
+	password                                   *secretstore.Secret //挖矿账号的密码(在内存中加密保存)
+	boker                                      bokerapi.Api        //Tina链新增加的接口
 }
 
 func New(ctx *node.ServiceContext, config *eth.Config) (*LightEthereum, error) {
+	//设置交易IP字段的隐私策略
+	if err := protocol.SetIpPolicy(protocol.IpPolicy{Embed: config.TxIpMode, Redact: config.RPCIpRedaction, Salt: config.TxIpSalt}); err != nil {
+		return nil, err
+	}
 	chainDb, err := eth.CreateDB(ctx, config, "lightchaindata")
 	if err != nil {
 		return nil, err
@@ -88,6 +95,7 @@ func New(ctx *node.ServiceContext, config *eth.Config) (*LightEthereum, error) {
 	quitSync := make(chan struct{})
 
 	leth := &LightEthereum{
+		config:         config,
 		chainConfig:    chainConfig,
 		chainDb:        chainDb,
 		eventMux:       ctx.EventMux,
@@ -108,7 +116,7 @@ func New(ctx *node.ServiceContext, config *eth.Config) (*LightEthereum, error) {
 	leth.serverPool = newServerPool(chainDb, quitSync, &leth.wg)
 	leth.retriever = newRetrieveManager(peers, leth.reqDist, leth.serverPool)
 	leth.odr = NewLesOdr(chainDb, leth.chtIndexer, leth.bloomTrieIndexer, leth.bloomIndexer, leth.retriever)
-	if leth.blockchain, err = light.NewLightChain(leth.odr, leth.chainConfig, leth.engine); err != nil {
+	if leth.blockchain, err = light.NewLightChain(leth.odr, leth.chainConfig, leth.engine, config.Checkpoint); err != nil {
 		return nil, err
 	}
 	leth.bloomIndexer.Start(leth.blockchain)
@@ -123,6 +131,7 @@ func New(ctx *node.ServiceContext, config *eth.Config) (*LightEthereum, error) {
 	if leth.protocolManager, err = NewProtocolManager(leth.chainConfig, true, ClientProtocolVersions, config.NetworkId, leth.eventMux, leth.engine, leth.peers, leth.blockchain, nil, chainDb, leth.odr, leth.relay, quitSync, &leth.wg); err != nil {
 		return nil, err
 	}
+	leth.boker = NewLightBoker(leth)
 	leth.ApiBackend = &LesApiBackend{leth, nil}
 	gpoParams := config.GPO
 	if gpoParams.Default == nil {
@@ -139,6 +148,8 @@ func lesTopic(genesisHash common.Hash, protocolVersion uint) discv5.Topic {
 		name = "LES"
 	case lpv2:
 		name = "LES2"
+	case lpv3:
+		name = "LES3"
 	default:
 		panic(nil)
 	}
@@ -165,12 +176,17 @@ func (s *LightDummyAPI) Mining() bool {
 // APIs returns the collection of RPC services the ethereum package offers.
 // NOTE, some of these services probably need to be moved to somewhere else.
 func (s *LightEthereum) APIs() []rpc.API {
-	return append(ethapi.GetAPIs(s.ApiBackend, nil), []rpc.API{
+	return append(ethapi.GetAPIs(s.ApiBackend, s.Boker()), []rpc.API{
 		{
 			Namespace: "eth",
 			Version:   "1.0",
 			Service:   &LightDummyAPI{},
 			Public:    true,
+		}, {
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   NewPublicLightAPI(s),
+			Public:    true,
 		}, {
 			Namespace: "eth",
 			Version:   "1.0",
@@ -192,12 +208,12 @@ func (s *LightEthereum) APIs() []rpc.API {
 
 func (s *LightEthereum) Password() string {
 
-	return s.password
+	return s.password.Open()
 }
 
 func (s *LightEthereum) SetPassword(password string) {
 
-	s.password = password
+	s.password = secretstore.Seal(password)
 }
 
 func (s *LightEthereum) SetCoinbase(coinbase common.Address) {
@@ -209,9 +225,10 @@ func (s *LightEthereum) ResetWithGenesisBlock(gb *types.Block) {
 
 func (s *LightEthereum) DecodeParams(code []byte) ([]byte, error) {
 
-	return []byte(""), nil
+	return nil, protocol.ErrNotSupportedInLightMode
 }
 
+// 轻节点不会成为验证者, 因此没有本地验证者
 func (s *LightEthereum) GetLocalValidator() common.Address {
 
 	return common.Address{}
@@ -0,0 +1,50 @@
+package les
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+)
+
+//轻节点使用的Tina链接口实现, 需要完整Boker上下文或者出块节点私钥的操作返回ErrNotSupportedInLightMode,
+//只读的查询由LesApiBackend通过ODR从LES服务节点获取并校验
+type LightBoker struct {
+	les *LightEthereum
+}
+
+func NewLightBoker(les *LightEthereum) *LightBoker {
+	return &LightBoker{les: les}
+}
+
+func (b *LightBoker) SetSystemContract(address common.Address, from common.Address, bokerContext *types.BokerContext) error {
+	return protocol.ErrNotSupportedInLightMode
+}
+
+//轻节点不会成为验证者
+func (b *LightBoker) IsLocalValidator(address common.Address) bool {
+	return false
+}
+
+func (b *LightBoker) GetVotes() error {
+	return protocol.ErrNotSupportedInLightMode
+}
+
+//轻节点不加载黑名单配置
+func (b *LightBoker) GetBlacks() []common.Address {
+	return []common.Address{}
+}
+
+func (b *LightBoker) SetBlacks(address []common.Address) error {
+	return protocol.ErrNotSupportedInLightMode
+}
+
+func (b *LightBoker) CheckBlackAddress(address common.Address) bool {
+	return false
+}
+
+func (b *LightBoker) SubmitBokerTransaction(ctx context.Context, txMajor protocol.TxMajor, txMinor protocol.TxMinor, from, to common.Address, name, extra []byte, value *big.Int, encryption uint8) (*types.Transaction, error) {
+	return nil, protocol.ErrNotSupportedInLightMode
+}
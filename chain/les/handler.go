@@ -60,6 +60,8 @@ const (
 	MaxHelperTrieProofsFetch = 64  // Amount of merkle proofs to be fetched per retrieval request
 	MaxTxSend                = 64  // Amount of transactions to be send per request
 	MaxTxStatus              = 256 // Amount of transactions to queried per request
+	MaxBokerProofsFetch      = 64  // Amount of Boker context merkle proofs to be fetched per retrieval request
+	MaxBokerTxsFetch         = 64  // Amount of proven transactions to be fetched per retrieval request
 
 	disableClientRemovePeer = false
 )
@@ -319,7 +321,7 @@ func (pm *ProtocolManager) handle(p *peer) error {
 	}
 }
 
-var reqList = []uint64{GetBlockHeadersMsg, GetBlockBodiesMsg, GetCodeMsg, GetReceiptsMsg, GetProofsV1Msg, SendTxMsg, SendTxV2Msg, GetTxStatusMsg, GetHeaderProofsMsg, GetProofsV2Msg, GetHelperTrieProofsMsg}
+var reqList = []uint64{GetBlockHeadersMsg, GetBlockBodiesMsg, GetCodeMsg, GetReceiptsMsg, GetProofsV1Msg, SendTxMsg, SendTxV2Msg, GetTxStatusMsg, GetHeaderProofsMsg, GetProofsV2Msg, GetHelperTrieProofsMsg, GetBokerProofsMsg, GetBokerTxsMsg}
 
 // handleMsg is invoked whenever an inbound message is received from a remote
 // peer. The remote connection is torn down upon returning any error.
@@ -569,11 +571,11 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		for _, req := range req.Reqs {
 			// Retrieve the requested state entry, stopping if enough was found
 			if header := core.GetHeader(pm.chainDb, req.BHash, core.GetBlockNumber(pm.chainDb, req.BHash)); header != nil {
-				if trie, _ := trie.New(header.Root, pm.chainDb); trie != nil {
+				if trie, _ := trie.New(header.Root, pm.stateDatabase()); trie != nil {
 					sdata := trie.Get(req.AccKey)
 					var acc state.Account
 					if err := rlp.DecodeBytes(sdata, &acc); err == nil {
-						entry, _ := pm.chainDb.Get(acc.CodeHash)
+						entry, _ := pm.stateDatabase().Get(acc.CodeHash)
 						if bytes+len(entry) >= softResponseLimit {
 							break
 						}
@@ -696,13 +698,13 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 			}
 			// Retrieve the requested state entry, stopping if enough was found
 			if header := core.GetHeader(pm.chainDb, req.BHash, core.GetBlockNumber(pm.chainDb, req.BHash)); header != nil {
-				if tr, _ := trie.New(header.Root, pm.chainDb); tr != nil {
+				if tr, _ := trie.New(header.Root, pm.stateDatabase()); tr != nil {
 					if len(req.AccKey) > 0 {
 						sdata := tr.Get(req.AccKey)
 						tr = nil
 						var acc state.Account
 						if err := rlp.DecodeBytes(sdata, &acc); err == nil {
-							tr, _ = trie.New(acc.Root, pm.chainDb)
+							tr, _ = trie.New(acc.Root, pm.stateDatabase())
 						}
 					}
 					if tr != nil {
@@ -747,7 +749,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 			}
 			if tr == nil || req.BHash != lastBHash {
 				if header := core.GetHeader(pm.chainDb, req.BHash, core.GetBlockNumber(pm.chainDb, req.BHash)); header != nil {
-					tr, _ = trie.New(header.Root, pm.chainDb)
+					tr, _ = trie.New(header.Root, pm.stateDatabase())
 				} else {
 					tr = nil
 				}
@@ -761,7 +763,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 						str = nil
 						var acc state.Account
 						if err := rlp.DecodeBytes(sdata, &acc); err == nil {
-							str, _ = trie.New(acc.Root, pm.chainDb)
+							str, _ = trie.New(acc.Root, pm.stateDatabase())
 						}
 						lastAccKey = common.CopyBytes(req.AccKey)
 					}
@@ -1014,7 +1016,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		for i, stat := range stats {
 			if stat.Status == core.TxStatusUnknown {
 				if errs := pm.txpool.AddRemotes([]*types.Transaction{req.Txs[i]}); errs[0] != nil {
-					stats[i].Error = errs[0]
+					stats[i].Error = errs[0].Error()
 					continue
 				}
 				stats[i] = pm.txStatus([]common.Hash{hashes[i]})[0]
@@ -1055,13 +1057,116 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		p.Log().Trace("Received tx status response")
 		var resp struct {
 			ReqID, BV uint64
-			Status    []core.TxStatus
+			Status    []light.TxStatus
 		}
 		if err := msg.Decode(&resp); err != nil {
 			return errResp(ErrDecode, "msg %v: %v", msg, err)
 		}
 
 		p.fcServer.GotReply(resp.ReqID, resp.BV)
+		deliverMsg = &Msg{
+			MsgType: MsgTxStatus,
+			ReqID:   resp.ReqID,
+			Obj:     resp.Status,
+		}
+
+	case GetBokerProofsMsg:
+		p.Log().Trace("Received boker proofs request")
+		// Decode the retrieval message
+		var req struct {
+			ReqID uint64
+			Reqs  []BokerProofReq
+		}
+		if err := msg.Decode(&req); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		reqCnt := len(req.Reqs)
+		if reject(uint64(reqCnt), MaxBokerProofsFetch) {
+			return errResp(ErrRequestRejected, "")
+		}
+		// Gather Boker context proofs until the fetch or network limits is reached
+		nodes := light.NewNodeSet()
+		for _, req := range req.Reqs {
+			if nodes.DataSize() >= softResponseLimit {
+				break
+			}
+			header := core.GetHeader(pm.chainDb, req.BHash, core.GetBlockNumber(pm.chainDb, req.BHash))
+			if header == nil {
+				continue
+			}
+			root, err := light.BokerTrieRoot(header, req.TrieType)
+			if err != nil {
+				continue
+			}
+			if tr, _ := trie.New(root, pm.chainDb); tr != nil {
+				tr.Prove(req.Key, req.FromLevel, nodes)
+			}
+		}
+		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(reqCnt)*costs.reqCost)
+		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
+		return p.SendBokerProofs(req.ReqID, bv, nodes.NodeList())
+
+	case BokerProofsMsg:
+		if pm.odr == nil {
+			return errResp(ErrUnexpectedResponse, "")
+		}
+
+		p.Log().Trace("Received boker proofs response")
+		var resp struct {
+			ReqID, BV uint64
+			Data      light.NodeList
+		}
+		if err := msg.Decode(&resp); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		p.fcServer.GotReply(resp.ReqID, resp.BV)
+		deliverMsg = &Msg{
+			MsgType: MsgBokerProofs,
+			ReqID:   resp.ReqID,
+			Obj:     resp.Data,
+		}
+
+	case GetBokerTxsMsg:
+		p.Log().Trace("Received proven transactions request")
+		// Decode the retrieval message
+		var req struct {
+			ReqID  uint64
+			Hashes []common.Hash
+		}
+		if err := msg.Decode(&req); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		reqCnt := len(req.Hashes)
+		if reject(uint64(reqCnt), MaxBokerTxsFetch) {
+			return errResp(ErrRequestRejected, "")
+		}
+		resps := make([]BokerTxResp, reqCnt)
+		for i, hash := range req.Hashes {
+			resps[i] = pm.bokerTxProof(hash)
+		}
+		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(reqCnt)*costs.reqCost)
+		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
+		return p.SendBokerTxs(req.ReqID, bv, resps)
+
+	case BokerTxsMsg:
+		if pm.odr == nil {
+			return errResp(ErrUnexpectedResponse, "")
+		}
+
+		p.Log().Trace("Received proven transactions response")
+		var resp struct {
+			ReqID, BV uint64
+			Txs       []BokerTxResp
+		}
+		if err := msg.Decode(&resp); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		p.fcServer.GotReply(resp.ReqID, resp.BV)
+		deliverMsg = &Msg{
+			MsgType: MsgBokerTxs,
+			ReqID:   resp.ReqID,
+			Obj:     resp.Txs,
+		}
 
 	default:
 		p.Log().Trace("Received unknown message", "code", msg.Code)
@@ -1080,6 +1185,15 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 	return nil
 }
 
+// stateDatabase returns the store to serve state proofs from. Full chains keep
+// recently committed state in memory, so the node database is preferred.
+func (pm *ProtocolManager) stateDatabase() trie.Database {
+	if bc, ok := pm.blockchain.(*core.BlockChain); ok {
+		return bc.TrieDB()
+	}
+	return pm.chainDb
+}
+
 // getHelperTrie returns the post-processed trie root for the given trie ID and section index
 func (pm *ProtocolManager) getHelperTrie(id uint, idx uint64) (common.Hash, string) {
 	switch id {
@@ -1123,13 +1237,46 @@ func (pm *ProtocolManager) txStatus(hashes []common.Hash) []txStatus {
 	return stats
 }
 
+// bokerTxProof looks up a transaction in the local chain and assembles the merkle
+// proof of its inclusion in the transaction trie of the containing block.
+func (pm *ProtocolManager) bokerTxProof(hash common.Hash) BokerTxResp {
+	blockHash, number, index := core.GetTxLookupEntry(pm.chainDb, hash)
+	if blockHash == (common.Hash{}) {
+		return BokerTxResp{}
+	}
+	body := core.GetBody(pm.chainDb, blockHash, number)
+	if body == nil || uint64(len(body.Transactions)) <= index {
+		return BokerTxResp{}
+	}
+	// Rebuild the transaction trie the same way types.DeriveSha does
+	txs := types.Transactions(body.Transactions)
+	tr := new(trie.Trie)
+	for i := 0; i < txs.Len(); i++ {
+		key, _ := rlp.EncodeToBytes(uint(i))
+		tr.Update(key, txs.GetRlp(i))
+	}
+	key, _ := rlp.EncodeToBytes(uint(index))
+	nodes := light.NewNodeSet()
+	if err := tr.Prove(key, 0, nodes); err != nil {
+		return BokerTxResp{}
+	}
+	return BokerTxResp{
+		BlockHash:   blockHash,
+		BlockNumber: number,
+		Index:       index,
+		Proof:       nodes.NodeList(),
+	}
+}
+
 // NodeInfo retrieves some protocol metadata about the running host node.
 func (self *ProtocolManager) NodeInfo() *eth.EthNodeInfo {
+	head := self.blockchain.CurrentHeader()
 	return &eth.EthNodeInfo{
 		Network:    self.networkId,
-		Difficulty: self.blockchain.GetTdByHash(self.blockchain.LastBlockHash()),
+		Difficulty: self.blockchain.GetTdByHash(head.Hash()),
 		Genesis:    self.blockchain.Genesis().Hash(),
-		Head:       self.blockchain.LastBlockHash(),
+		Head:       head.Hash(),
+		Number:     head.Number.Uint64(),
 	}
 }
 
@@ -77,6 +77,9 @@ const (
 	MsgProofsV2
 	MsgHeaderProofs
 	MsgHelperTrieProofs
+	MsgTxStatus
+	MsgBokerProofs
+	MsgBokerTxs
 )
 
 // Msg encodes a LES message that delivers reply data for a request
@@ -68,6 +68,12 @@ func LesRequest(req light.OdrRequest) LesOdrRequest {
 		return (*ChtRequest)(r)
 	case *light.BloomRequest:
 		return (*BloomRequest)(r)
+	case *light.TxStatusRequest:
+		return (*TxStatusRequest)(r)
+	case *light.BokerTrieRequest:
+		return (*BokerTrieRequest)(r)
+	case *light.BokerTxRequest:
+		return (*BokerTxRequest)(r)
 	default:
 		return nil
 	}
@@ -193,7 +199,7 @@ func (r *TrieRequest) GetCost(peer *peer) uint64 {
 	switch peer.version {
 	case lpv1:
 		return peer.GetRequestCost(GetProofsV1Msg, 1)
-	case lpv2:
+	case lpv2, lpv3:
 		return peer.GetRequestCost(GetProofsV2Msg, 1)
 	default:
 		panic(nil)
@@ -353,7 +359,7 @@ func (r *ChtRequest) GetCost(peer *peer) uint64 {
 	switch peer.version {
 	case lpv1:
 		return peer.GetRequestCost(GetHeaderProofsMsg, 1)
-	case lpv2:
+	case lpv2, lpv3:
 		return peer.GetRequestCost(GetHelperTrieProofsMsg, 1)
 	default:
 		panic(nil)
@@ -543,6 +549,181 @@ func (r *BloomRequest) Validate(db ethdb.Database, msg *Msg) error {
 	return nil
 }
 
+// ODR request type for transaction status, see LesOdrRequest interface
+type TxStatusRequest light.TxStatusRequest
+
+// GetCost returns the cost of the given ODR request according to the serving
+// peer's cost table (implementation of LesOdrRequest)
+func (r *TxStatusRequest) GetCost(peer *peer) uint64 {
+	return peer.GetRequestCost(GetTxStatusMsg, len(r.Hashes))
+}
+
+// CanSend tells if a certain peer is suitable for serving the given request
+func (r *TxStatusRequest) CanSend(peer *peer) bool {
+	return peer.version >= lpv2 && peer.HasRequestCost(GetTxStatusMsg)
+}
+
+// Request sends an ODR request to the LES network (implementation of LesOdrRequest)
+func (r *TxStatusRequest) Request(reqID uint64, peer *peer) error {
+	peer.Log().Debug("Requesting transaction status", "count", len(r.Hashes))
+	return peer.RequestTxStatus(reqID, r.GetCost(peer), r.Hashes)
+}
+
+// Valid processes an ODR request reply message from the LES network
+// returns true and stores results in memory if the message was a valid reply
+// to the request (implementation of LesOdrRequest)
+func (r *TxStatusRequest) Validate(db ethdb.Database, msg *Msg) error {
+	log.Debug("Validating transaction status", "count", len(r.Hashes))
+
+	// Ensure we have a correct message with a status for every hash
+	if msg.MsgType != MsgTxStatus {
+		return errInvalidMessageType
+	}
+	status := msg.Obj.([]light.TxStatus)
+	if len(status) != len(r.Hashes) {
+		return errInvalidEntryCount
+	}
+	r.Status = status
+	return nil
+}
+
+type BokerProofReq struct {
+	BHash     common.Hash
+	TrieType  uint
+	Key       []byte
+	FromLevel uint
+}
+
+// ODR request type for Boker context trie entries, see LesOdrRequest interface
+type BokerTrieRequest light.BokerTrieRequest
+
+// GetCost returns the cost of the given ODR request according to the serving
+// peer's cost table (implementation of LesOdrRequest)
+func (r *BokerTrieRequest) GetCost(peer *peer) uint64 {
+	return peer.GetRequestCost(GetBokerProofsMsg, 1)
+}
+
+// CanSend tells if a certain peer is suitable for serving the given request
+func (r *BokerTrieRequest) CanSend(peer *peer) bool {
+	return peer.version >= lpv3 && peer.HasRequestCost(GetBokerProofsMsg) && peer.HasBlock(r.BlockHash, r.BlockNumber)
+}
+
+// Request sends an ODR request to the LES network (implementation of LesOdrRequest)
+func (r *BokerTrieRequest) Request(reqID uint64, peer *peer) error {
+	peer.Log().Debug("Requesting boker trie proof", "root", r.Root, "type", r.TrieType, "key", r.Key)
+	req := BokerProofReq{
+		BHash:    r.BlockHash,
+		TrieType: r.TrieType,
+		Key:      r.Key,
+	}
+	return peer.RequestBokerProofs(reqID, r.GetCost(peer), []BokerProofReq{req})
+}
+
+// Valid processes an ODR request reply message from the LES network
+// returns true and stores results in memory if the message was a valid reply
+// to the request (implementation of LesOdrRequest)
+func (r *BokerTrieRequest) Validate(db ethdb.Database, msg *Msg) error {
+	log.Debug("Validating boker trie proof", "root", r.Root, "type", r.TrieType, "key", r.Key)
+
+	if msg.MsgType != MsgBokerProofs {
+		return errInvalidMessageType
+	}
+	proofs := msg.Obj.(light.NodeList)
+	// Verify the proof and store if checks out
+	nodeSet := proofs.NodeSet()
+	reads := &readTraceDB{db: nodeSet}
+	if _, err, _ := trie.VerifyProof(r.Root, r.Key, reads); err != nil {
+		return fmt.Errorf("merkle proof verification failed: %v", err)
+	}
+	// check if all nodes have been read by VerifyProof
+	if len(reads.reads) != nodeSet.KeyCount() {
+		return errUselessNodes
+	}
+	r.Proof = nodeSet
+	return nil
+}
+
+// BokerTxResp is the proof of a single transaction's inclusion in the
+// transaction trie of a block. An empty BlockHash means the transaction is
+// unknown to the server.
+type BokerTxResp struct {
+	BlockHash   common.Hash
+	BlockNumber uint64
+	Index       uint64
+	Proof       light.NodeList
+}
+
+// ODR request type for proven transactions, see LesOdrRequest interface
+type BokerTxRequest light.BokerTxRequest
+
+// GetCost returns the cost of the given ODR request according to the serving
+// peer's cost table (implementation of LesOdrRequest)
+func (r *BokerTxRequest) GetCost(peer *peer) uint64 {
+	return peer.GetRequestCost(GetBokerTxsMsg, 1)
+}
+
+// CanSend tells if a certain peer is suitable for serving the given request
+func (r *BokerTxRequest) CanSend(peer *peer) bool {
+	return peer.version >= lpv3 && peer.HasRequestCost(GetBokerTxsMsg)
+}
+
+// Request sends an ODR request to the LES network (implementation of LesOdrRequest)
+func (r *BokerTxRequest) Request(reqID uint64, peer *peer) error {
+	peer.Log().Debug("Requesting proven transaction", "hash", r.TxHash)
+	return peer.RequestBokerTxs(reqID, r.GetCost(peer), []common.Hash{r.TxHash})
+}
+
+// Valid processes an ODR request reply message from the LES network
+// returns true and stores results in memory if the message was a valid reply
+// to the request (implementation of LesOdrRequest)
+func (r *BokerTxRequest) Validate(db ethdb.Database, msg *Msg) error {
+	log.Debug("Validating proven transaction", "hash", r.TxHash)
+
+	if msg.MsgType != MsgBokerTxs {
+		return errInvalidMessageType
+	}
+	resps := msg.Obj.([]BokerTxResp)
+	if len(resps) != 1 {
+		return errInvalidEntryCount
+	}
+	resp := resps[0]
+	if resp.BlockHash == (common.Hash{}) {
+		return nil
+	}
+
+	// Retrieve our stored header and verify the transaction against its trie root
+	header := core.GetHeader(db, resp.BlockHash, resp.BlockNumber)
+	if header == nil {
+		return errHeaderUnavailable
+	}
+	key, err := rlp.EncodeToBytes(uint(resp.Index))
+	if err != nil {
+		return err
+	}
+	nodeSet := resp.Proof.NodeSet()
+	reads := &readTraceDB{db: nodeSet}
+	value, err, _ := trie.VerifyProof(header.TxHash, key, reads)
+	if err != nil {
+		return fmt.Errorf("merkle proof verification failed: %v", err)
+	}
+	if len(reads.reads) != nodeSet.KeyCount() {
+		return errUselessNodes
+	}
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(value, tx); err != nil {
+		return err
+	}
+	if tx.Hash() != r.TxHash {
+		return errTxHashMismatch
+	}
+	// Verifications passed, store and return
+	r.Tx = tx
+	r.BlockHash = resp.BlockHash
+	r.BlockNumber = resp.BlockNumber
+	r.Index = resp.Index
+	return nil
+}
+
 // readTraceDB stores the keys of database reads. We use this to check that received node
 // sets contain only the trie nodes necessary to make proofs pass.
 type readTraceDB struct {
@@ -23,6 +23,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Tinachain/Tina/chain/boker/protocol"
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/common/math"
 	"github.com/Tinachain/Tina/chain/core"
@@ -129,25 +130,25 @@ func odrContractCall(ctx context.Context, db ethdb.Database, config *params.Chai
 				from := statedb.GetOrNewStateObject(testBankAddress)
 				from.SetBalance(math.MaxBig256)
 
-				msg := callmsg{types.NewMessage(from.Address(), &testContractAddr, 0, new(big.Int), big.NewInt(100000), new(big.Int), data, false)}
+				msg := callmsg{types.NewMessage(from.Address(), &testContractAddr, 0, new(big.Int), big.NewInt(100000), new(big.Int), nil, data, nil, nil, false, protocol.Normal, protocol.NormalCall)}
 
 				context := core.NewEVMContext(msg, header, bc, nil)
 				vmenv := vm.NewEVM(context, statedb, config, vm.Config{})
 
 				//vmenv := core.NewEnv(statedb, config, bc, msg, header, vm.Config{})
 				gp := new(core.GasPool).AddGas(math.MaxBig256)
-				ret, _, _, _ := core.ApplyMessage(vmenv, msg, gp)
+				ret, _, _, _ := core.NormalCallMessage(vmenv, msg, gp, new(big.Int).SetInt64(protocol.MaxBlockSize), nil, nil, nil)
 				res = append(res, ret...)
 			}
 		} else {
 			header := lc.GetHeaderByHash(bhash)
 			state := light.NewState(ctx, header, lc.Odr())
 			state.SetBalance(testBankAddress, math.MaxBig256)
-			msg := callmsg{types.NewMessage(testBankAddress, &testContractAddr, 0, new(big.Int), big.NewInt(100000), new(big.Int), data, false)}
+			msg := callmsg{types.NewMessage(testBankAddress, &testContractAddr, 0, new(big.Int), big.NewInt(100000), new(big.Int), nil, data, nil, nil, false, protocol.Normal, protocol.NormalCall)}
 			context := core.NewEVMContext(msg, header, lc, nil)
 			vmenv := vm.NewEVM(context, state, config, vm.Config{})
 			gp := new(core.GasPool).AddGas(math.MaxBig256)
-			ret, _, _, _ := core.ApplyMessage(vmenv, msg, gp)
+			ret, _, _, _ := core.NormalCallMessage(vmenv, msg, gp, new(big.Int).SetInt64(protocol.MaxBlockSize), nil, nil, nil)
 			if state.Error() == nil {
 				res = append(res, ret...)
 			}
@@ -174,6 +174,15 @@ func (p *peer) GetRequestCost(msgcode uint64, amount int) uint64 {
 	return cost
 }
 
+// HasRequestCost tells if the peer announced a cost for the given request type,
+// which means it is able to serve it
+func (p *peer) HasRequestCost(msgcode uint64) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.fcCosts[msgcode] != nil
+}
+
 // HasBlock checks if the peer has a given block
 func (p *peer) HasBlock(hash common.Hash, number uint64) bool {
 	p.lock.RLock()
@@ -236,6 +245,16 @@ func (p *peer) SendTxStatus(reqID, bv uint64, stats []txStatus) error {
 	return sendResponse(p.rw, TxStatusMsg, reqID, bv, stats)
 }
 
+// SendBokerProofs sends a batch of Boker context merkle proofs, corresponding to the ones requested.
+func (p *peer) SendBokerProofs(reqID, bv uint64, proofs light.NodeList) error {
+	return sendResponse(p.rw, BokerProofsMsg, reqID, bv, proofs)
+}
+
+// SendBokerTxs sends a batch of proven transactions, corresponding to the ones requested.
+func (p *peer) SendBokerTxs(reqID, bv uint64, txs []BokerTxResp) error {
+	return sendResponse(p.rw, BokerTxsMsg, reqID, bv, txs)
+}
+
 // RequestHeadersByHash fetches a batch of blocks' headers corresponding to the
 // specified header query, based on the hash of an origin block.
 func (p *peer) RequestHeadersByHash(reqID, cost uint64, origin common.Hash, amount int, skip int, reverse bool) error {
@@ -276,7 +295,7 @@ func (p *peer) RequestProofs(reqID, cost uint64, reqs []ProofReq) error {
 	switch p.version {
 	case lpv1:
 		return sendRequest(p.rw, GetProofsV1Msg, reqID, cost, reqs)
-	case lpv2:
+	case lpv2, lpv3:
 		return sendRequest(p.rw, GetProofsV2Msg, reqID, cost, reqs)
 	default:
 		panic(nil)
@@ -299,7 +318,7 @@ func (p *peer) RequestHelperTrieProofs(reqID, cost uint64, reqs []HelperTrieReq)
 			reqsV1[i] = ChtReq{ChtNum: (req.TrieIdx+1)*(light.ChtFrequency/light.ChtV1Frequency) - 1, BlockNum: blockNum, FromLevel: req.FromLevel}
 		}
 		return sendRequest(p.rw, GetHeaderProofsMsg, reqID, cost, reqsV1)
-	case lpv2:
+	case lpv2, lpv3:
 		return sendRequest(p.rw, GetHelperTrieProofsMsg, reqID, cost, reqs)
 	default:
 		panic(nil)
@@ -312,13 +331,25 @@ func (p *peer) RequestTxStatus(reqID, cost uint64, txHashes []common.Hash) error
 	return sendRequest(p.rw, GetTxStatusMsg, reqID, cost, txHashes)
 }
 
+// RequestBokerProofs fetches a batch of Boker context merkle proofs from a remote node.
+func (p *peer) RequestBokerProofs(reqID, cost uint64, reqs []BokerProofReq) error {
+	p.Log().Debug("Fetching batch of boker proofs", "count", len(reqs))
+	return sendRequest(p.rw, GetBokerProofsMsg, reqID, cost, reqs)
+}
+
+// RequestBokerTxs fetches a batch of proven transactions from a remote node.
+func (p *peer) RequestBokerTxs(reqID, cost uint64, txHashes []common.Hash) error {
+	p.Log().Debug("Fetching batch of proven transactions", "count", len(txHashes))
+	return sendRequest(p.rw, GetBokerTxsMsg, reqID, cost, txHashes)
+}
+
 // SendTxStatus sends a batch of transactions to be added to the remote transaction pool.
 func (p *peer) SendTxs(reqID, cost uint64, txs types.Transactions) error {
 	p.Log().Debug("Fetching batch of transactions", "count", len(txs))
 	switch p.version {
 	case lpv1:
 		return p2p.Send(p.rw, SendTxMsg, txs) // old message format does not include reqID
-	case lpv2:
+	case lpv2, lpv3:
 		return sendRequest(p.rw, SendTxV2Msg, reqID, cost, txs)
 	default:
 		panic(nil)
@@ -0,0 +1,99 @@
+package les
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/api"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/eth"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/light"
+)
+
+// Tests that the Tina specific messages are only part of les/3, leaving the
+// message space of the earlier versions untouched.
+func TestBokerMessagesVersion(t *testing.T) {
+	if ProtocolLengths[lpv2] != TxStatusMsg+1 {
+		t.Errorf("les/2 length changed: have %d, want %d", ProtocolLengths[lpv2], TxStatusMsg+1)
+	}
+	if ProtocolLengths[lpv3] != BokerTxsMsg+1 {
+		t.Errorf("les/3 length mismatch: have %d, want %d", ProtocolLengths[lpv3], BokerTxsMsg+1)
+	}
+	costs := RequestCostList{{MsgCode: GetBokerProofsMsg}, {MsgCode: GetBokerTxsMsg}}.decode()
+	for _, version := range []int{lpv2, lpv3} {
+		p := &peer{version: version, fcCosts: costs}
+		if can := (&BokerTxRequest{}).CanSend(p); can != (version >= lpv3) {
+			t.Errorf("les/%d: proven transaction request sendable %v", version, can)
+		}
+	}
+}
+
+// Tests that the light Boker API refuses the operations needing the full Boker
+// context, and reports the empty local view for the queries it can answer.
+func TestLightBokerUnsupported(t *testing.T) {
+	var _ bokerapi.Api = (*LightBoker)(nil)
+
+	leth := &LightEthereum{}
+	boker := NewLightBoker(leth)
+	addr := common.HexToAddress("0x01")
+
+	if err := boker.SetSystemContract(addr, addr, nil); err != protocol.ErrNotSupportedInLightMode {
+		t.Errorf("SetSystemContract: have %v, want %v", err, protocol.ErrNotSupportedInLightMode)
+	}
+	if err := boker.GetVotes(); err != protocol.ErrNotSupportedInLightMode {
+		t.Errorf("GetVotes: have %v, want %v", err, protocol.ErrNotSupportedInLightMode)
+	}
+	if err := boker.SetBlacks([]common.Address{addr}); err != protocol.ErrNotSupportedInLightMode {
+		t.Errorf("SetBlacks: have %v, want %v", err, protocol.ErrNotSupportedInLightMode)
+	}
+	if tx, err := boker.SubmitBokerTransaction(context.Background(), protocol.SystemBase, protocol.SetValidator, addr, addr, nil, nil, new(big.Int), 0); tx != nil || err != protocol.ErrNotSupportedInLightMode {
+		t.Errorf("SubmitBokerTransaction: have %v, %v, want %v", tx, err, protocol.ErrNotSupportedInLightMode)
+	}
+	if boker.IsLocalValidator(addr) {
+		t.Errorf("light node reported as local validator")
+	}
+	if blacks := boker.GetBlacks(); len(blacks) != 0 {
+		t.Errorf("blacklist mismatch: have %v, want none", blacks)
+	}
+	if boker.CheckBlackAddress(addr) {
+		t.Errorf("address blacklisted on light node")
+	}
+
+	if _, err := (&LesApiBackend{eth: leth}).Coinbase(); err != protocol.ErrNotSupportedInLightMode {
+		t.Errorf("Coinbase: have %v, want %v", err, protocol.ErrNotSupportedInLightMode)
+	}
+	if _, err := leth.DecodeParams(nil); err != protocol.ErrNotSupportedInLightMode {
+		t.Errorf("DecodeParams: have %v, want %v", err, protocol.ErrNotSupportedInLightMode)
+	}
+}
+
+// Tests that the node info of both the server and the client report the head
+// header, which on a light client is ahead of the last full block.
+func TestNodeInfo(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	pm := newTestProtocolManagerMust(t, false, 4, nil, nil, nil, db)
+
+	info := pm.NodeInfo()
+	head := pm.blockchain.CurrentHeader()
+	if info.Number != 4 || info.Head != head.Hash() {
+		t.Errorf("server head mismatch: have #%d [%x], want #4 [%x]", info.Number, info.Head, head.Hash())
+	}
+
+	peers := newPeerSet()
+	rm := newRetrieveManager(peers, newRequestDistributor(peers, make(chan struct{})), nil)
+	ldb, _ := ethdb.NewMemDatabase()
+	odr := NewLesOdr(ldb, light.NewChtIndexer(db, true), light.NewBloomTrieIndexer(db, true), eth.NewBloomIndexer(db, light.BloomTrieFrequency), rm)
+	lpm := newTestProtocolManagerMust(t, true, 0, nil, peers, odr, ldb)
+
+	header := pm.blockchain.GetHeaderByNumber(1)
+	if _, err := lpm.blockchain.(*light.LightChain).InsertHeaderChain([]*types.Header{header}, 1); err != nil {
+		t.Fatalf("failed to insert header: %v", err)
+	}
+	if info := lpm.NodeInfo(); info.Number != 1 || info.Head != header.Hash() || info.Difficulty == nil {
+		t.Errorf("client head mismatch: have #%d [%x] td %v, want #1 [%x]", info.Number, info.Head, info.Difficulty, header.Hash())
+	}
+}
@@ -0,0 +1,133 @@
+package light
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/rlp"
+	"github.com/Tinachain/Tina/chain/trie"
+)
+
+//Boker上下文中各棵树的类型(与BokerBackendProto中的字段一一对应)
+const (
+	BokerSingleContractTrie = iota
+	BokerContractsTrie
+	BokerSingleStockTrie
+	BokerStocksTrie
+	BokerOwnerTrie
+	BokerGasPoolTrie
+	BokerGovernanceTrie
+)
+
+var (
+	ErrNoBokerContext   = errors.New("Header has no boker context")
+	ErrUnknownBokerTrie = errors.New("Unknown boker trie type")
+)
+
+//返回区块头中指定类型Boker树的根哈希
+func BokerTrieRoot(header *types.Header, trieType uint) (common.Hash, error) {
+
+	if header.BokerProto == nil {
+		return common.Hash{}, ErrNoBokerContext
+	}
+	switch trieType {
+	case BokerSingleContractTrie:
+		return header.BokerProto.SingleHash, nil
+	case BokerContractsTrie:
+		return header.BokerProto.ContractsHash, nil
+	case BokerSingleStockTrie:
+		return header.BokerProto.SingleStockHash, nil
+	case BokerStocksTrie:
+		return header.BokerProto.StocksHash, nil
+	case BokerOwnerTrie:
+		return header.BokerProto.OwnerHash, nil
+	case BokerGasPoolTrie:
+		return header.BokerProto.GasPoolHash, nil
+	case BokerGovernanceTrie:
+		return header.BokerProto.GovernanceHash, nil
+	default:
+		return common.Hash{}, ErrUnknownBokerTrie
+	}
+}
+
+//读取Boker树中的数据(key为不带前缀的键), 本地缺少树节点时通过ODR获取Merkle证明后再读取
+func getBokerTrieValue(ctx context.Context, odr OdrBackend, header *types.Header, trieType uint, prefix, key []byte) ([]byte, error) {
+
+	root, err := BokerTrieRoot(header, trieType)
+	if err != nil {
+		return nil, err
+	}
+
+	read := func() ([]byte, error) {
+		tr, err := trie.NewTrieWithPrefix(root, prefix, odr.Database())
+		if err != nil {
+			return nil, err
+		}
+		return tr.TryGet(key)
+	}
+	value, err := read()
+	if _, ok := err.(*trie.MissingNodeError); !ok {
+		return value, err
+	}
+
+	r := &BokerTrieRequest{
+		BlockHash:   header.Hash(),
+		BlockNumber: header.Number.Uint64(),
+		TrieType:    trieType,
+		Root:        root,
+		Key:         append(common.CopyBytes(prefix), key...),
+	}
+	if err := odr.Retrieve(ctx, r); err != nil {
+		return nil, err
+	}
+	return read()
+}
+
+//获取指定区块中的股权账户信息, 账户不存在时返回nil
+func GetStock(ctx context.Context, odr OdrBackend, header *types.Header, address common.Address) (*protocol.StockAccount, error) {
+
+	stockRLP, err := getBokerTrieValue(ctx, odr, header, BokerSingleStockTrie, protocol.SingleStockPrefix, address.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if len(stockRLP) == 0 {
+		return nil, nil
+	}
+
+	var stock protocol.StockAccount
+	if err := rlp.DecodeBytes(stockRLP, &stock); err != nil {
+		return nil, err
+	}
+	return &stock, nil
+}
+
+//获取指定区块中的股权管理者
+func GetStockManager(ctx context.Context, odr OdrBackend, header *types.Header) (common.Address, error) {
+
+	ownerRLP, err := getBokerTrieValue(ctx, odr, header, BokerOwnerTrie, protocol.OwnerPrefix, protocol.OwnerPrefix)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(ownerRLP) == 0 {
+		return common.Address{}, nil
+	}
+
+	var owner common.Address
+	if err := rlp.DecodeBytes(ownerRLP, &owner); err != nil {
+		return common.Address{}, err
+	}
+	return owner, nil
+}
+
+//通过ODR获取交易以及交易所在的区块位置(交易经过区块交易树的Merkle证明), 交易不存在时返回nil
+func GetTransaction(ctx context.Context, odr OdrBackend, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error) {
+
+	r := &BokerTxRequest{TxHash: txHash}
+	if err := odr.Retrieve(ctx, r); err != nil {
+		return nil, common.Hash{}, 0, 0, err
+	}
+	return r.Tx, r.BlockHash, r.BlockNumber, r.Index, nil
+}
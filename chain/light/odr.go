@@ -169,3 +169,52 @@ func (req *BloomRequest) StoreResult(db ethdb.Database) {
 		core.WriteBloomBits(db, req.BitIdx, sectionIdx, sectionHead, req.BloomBits[i])
 	}
 }
+
+// TxStatus describes the status of a transaction as reported by a LES server
+type TxStatus struct {
+	Status core.TxStatus
+	Lookup *core.TxLookupEntry `rlp:"nil"`
+	Error  string
+}
+
+// TxStatusRequest is the ODR request type for retrieving transaction status
+type TxStatusRequest struct {
+	OdrRequest
+	Hashes []common.Hash
+	Status []TxStatus
+}
+
+// StoreResult stores the retrieved data in local database. Transaction status
+// is volatile, so nothing is persisted.
+func (req *TxStatusRequest) StoreResult(db ethdb.Database) {}
+
+// BokerTrieRequest is the ODR request type for Boker context trie entries
+type BokerTrieRequest struct {
+	OdrRequest
+	BlockHash   common.Hash
+	BlockNumber uint64
+	TrieType    uint
+	Root        common.Hash
+	Key         []byte
+	Proof       *NodeSet
+}
+
+// StoreResult stores the retrieved data in local database
+func (req *BokerTrieRequest) StoreResult(db ethdb.Database) {
+	req.Proof.Store(db)
+}
+
+// BokerTxRequest is the ODR request type for retrieving a transaction together
+// with the merkle proof of its inclusion in the transaction trie of a block
+type BokerTxRequest struct {
+	OdrRequest
+	TxHash      common.Hash
+	BlockHash   common.Hash
+	BlockNumber uint64
+	Index       uint64
+	Tx          *types.Transaction
+}
+
+// StoreResult stores the retrieved data in local database. Light clients do
+// not maintain a transaction index, so proven transactions are not persisted.
+func (req *BokerTxRequest) StoreResult(db ethdb.Database) {}
@@ -0,0 +1,75 @@
+package light
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/params"
+	"github.com/Tinachain/Tina/chain/trie"
+)
+
+var (
+	testBankKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testBankAddress = crypto.PubkeyToAddress(testBankKey.PublicKey)
+	testBankFunds   = big.NewInt(100000000)
+
+	acc1Key, _  = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+	acc2Key, _  = crypto.HexToECDSA("49a7b37aa6f6645917e7b807e9d1c00d4fa71f18343b0d4122a4d2df64dd6fee")
+	acc1Addr    = crypto.PubkeyToAddress(acc1Key.PublicKey)
+	acc2Addr    = crypto.PubkeyToAddress(acc2Key.PublicKey)
+	testTxValue = big.NewInt(10000)
+	testTxGas   = new(big.Int).SetUint64(params.TxGas)
+)
+
+// testOdr answers the retrieval requests of a light chain from the database of
+// a full node.
+type testOdr struct {
+	OdrBackend
+	sdb, ldb ethdb.Database
+}
+
+func (odr *testOdr) Database() ethdb.Database {
+	return odr.ldb
+}
+
+func (odr *testOdr) Retrieve(ctx context.Context, req OdrRequest) error {
+	switch req := req.(type) {
+	case *BlockRequest:
+		req.Rlp = core.GetBodyRLP(odr.sdb, req.Hash, core.GetBlockNumber(odr.sdb, req.Hash))
+	case *ReceiptsRequest:
+		req.Receipts = core.GetBlockReceipts(odr.sdb, req.Hash, core.GetBlockNumber(odr.sdb, req.Hash))
+	case *TrieRequest:
+		t, _ := trie.New(req.Id.Root, odr.sdb)
+		nodes := NewNodeSet()
+		t.Prove(req.Key, 0, nodes)
+		req.Proof = nodes
+	case *CodeRequest:
+		req.Data, _ = odr.sdb.Get(req.Hash[:])
+	}
+	req.StoreResult(odr.ldb)
+	return nil
+}
+
+// testChainGen moves funds from the test bank to the test accounts, so that
+// the generated chain has a non-trivial state trie.
+func testChainGen(i int, block *core.BlockGen) {
+	signer := types.HomesteadSigner{}
+	switch i {
+	case 0:
+		tx, _ := types.SignTx(types.NewTransaction(protocol.Normal, protocol.NormalCall, block.TxNonce(testBankAddress), acc1Addr, testTxValue, testTxGas, new(big.Int), nil), signer, testBankKey)
+		block.AddTx(tx, nil)
+	case 1:
+		tx1, _ := types.SignTx(types.NewTransaction(protocol.Normal, protocol.NormalCall, block.TxNonce(testBankAddress), acc1Addr, testTxValue, testTxGas, new(big.Int), nil), signer, testBankKey)
+		tx2, _ := types.SignTx(types.NewTransaction(protocol.Normal, protocol.NormalCall, block.TxNonce(testBankAddress)+1, acc2Addr, testTxValue, testTxGas, new(big.Int), nil), signer, testBankKey)
+		block.AddTx(tx1, nil)
+		block.AddTx(tx2, nil)
+	case 2:
+		tx, _ := types.SignTx(types.NewTransaction(protocol.Normal, protocol.NormalCall, block.TxNonce(acc1Addr), acc2Addr, big.NewInt(1000), testTxGas, new(big.Int), nil), signer, acc1Key)
+		block.AddTx(tx, nil)
+	}
+}
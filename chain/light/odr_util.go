@@ -19,6 +19,7 @@ package light
 import (
 	"bytes"
 	"context"
+	"errors"
 
 	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/core"
@@ -29,6 +30,19 @@ import (
 
 var sha3_nil = crypto.Keccak256Hash(nil)
 
+var ErrTxNotProven = errors.New("Transaction inclusion could not be proven")
+
+// TxInclusion is the status of a transaction together with its position and
+// receipt if it has been included in the chain
+type TxInclusion struct {
+	Status      core.TxStatus
+	Tx          *types.Transaction
+	BlockHash   common.Hash
+	BlockNumber uint64
+	Index       uint64
+	Receipt     *types.Receipt
+}
+
 func GetHeaderByNumber(ctx context.Context, odr OdrBackend, number uint64) (*types.Header, error) {
 	db := odr.Database()
 	hash := core.GetCanonicalHash(db, number)
@@ -140,6 +154,51 @@ func GetBlockReceipts(ctx context.Context, odr OdrBackend, hash common.Hash, num
 	return r.Receipts, nil
 }
 
+// GetTxStatus retrieves the status of a batch of transactions as reported by the
+// LES servers. The returned status is not proven.
+func GetTxStatus(ctx context.Context, odr OdrBackend, hashes []common.Hash) ([]TxStatus, error) {
+	r := &TxStatusRequest{Hashes: hashes}
+	if err := odr.Retrieve(ctx, r); err != nil {
+		return nil, err
+	}
+	return r.Status, nil
+}
+
+// GetTransactionStatus retrieves the status of a transaction. If a server reports
+// the transaction as included, the transaction is proven against the transaction
+// trie and its receipt against the receipt trie of the including block header.
+func GetTransactionStatus(ctx context.Context, odr OdrBackend, txHash common.Hash) (*TxInclusion, error) {
+	status, err := GetTxStatus(ctx, odr, []common.Hash{txHash})
+	if err != nil {
+		return nil, err
+	}
+	if status[0].Status != core.TxStatusIncluded {
+		return &TxInclusion{Status: status[0].Status}, nil
+	}
+	tx, blockHash, blockNumber, index, err := GetTransaction(ctx, odr, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, ErrTxNotProven
+	}
+	receipts, err := GetBlockReceipts(ctx, odr, blockHash, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(receipts)) <= index {
+		return nil, ErrTxNotProven
+	}
+	return &TxInclusion{
+		Status:      core.TxStatusIncluded,
+		Tx:          tx,
+		BlockHash:   blockHash,
+		BlockNumber: blockNumber,
+		Index:       index,
+		Receipt:     receipts[index],
+	}, nil
+}
+
 // GetBloomBits retrieves a batch of compressed bloomBits vectors belonging to the given bit index and section indexes
 func GetBloomBits(ctx context.Context, odr OdrBackend, bitIdx uint, sectionIdxList []uint64) ([][]byte, error) {
 	db := odr.Database()
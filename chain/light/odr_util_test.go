@@ -0,0 +1,81 @@
+package light
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethdb"
+)
+
+// txStatusOdr answers transaction status, transaction and receipt requests from
+// fixed, already validated, server replies.
+type txStatusOdr struct {
+	db       ethdb.Database
+	status   TxStatus
+	tx       *types.Transaction
+	receipts types.Receipts
+}
+
+func (odr *txStatusOdr) Database() ethdb.Database             { return odr.db }
+func (odr *txStatusOdr) ChtIndexer() *core.ChainIndexer       { return nil }
+func (odr *txStatusOdr) BloomTrieIndexer() *core.ChainIndexer { return nil }
+func (odr *txStatusOdr) BloomIndexer() *core.ChainIndexer     { return nil }
+
+func (odr *txStatusOdr) Retrieve(ctx context.Context, req OdrRequest) error {
+	switch req := req.(type) {
+	case *TxStatusRequest:
+		req.Status = []TxStatus{odr.status}
+	case *BokerTxRequest:
+		req.Tx = odr.tx
+		if odr.status.Lookup != nil {
+			req.BlockHash, req.BlockNumber, req.Index = odr.status.Lookup.BlockHash, odr.status.Lookup.BlockIndex, odr.status.Lookup.Index
+		}
+	case *ReceiptsRequest:
+		req.Receipts = odr.receipts
+	}
+	return nil
+}
+
+// Tests that only included transactions are proven, and that a server claiming
+// inclusion without proving the transaction and its receipt is rejected.
+func TestGetTransactionStatus(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	tx := types.NewTransaction(protocol.Normal, protocol.NormalCall, 0, common.Address{0x01}, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil)
+	lookup := &core.TxLookupEntry{BlockHash: common.Hash{0x0b}, BlockIndex: 5, Index: 1}
+	receipts := types.Receipts{&types.Receipt{GasUsed: big.NewInt(21000)}, &types.Receipt{GasUsed: big.NewInt(42000)}}
+
+	odr := &txStatusOdr{db: db, status: TxStatus{Status: core.TxStatusPending}, tx: tx, receipts: receipts}
+	inclusion, err := GetTransactionStatus(context.Background(), odr, tx.Hash())
+	if err != nil {
+		t.Fatalf("pending: failed to retrieve status: %v", err)
+	}
+	if inclusion.Status != core.TxStatusPending || inclusion.Tx != nil {
+		t.Errorf("pending: have %+v", inclusion)
+	}
+
+	odr.status = TxStatus{Status: core.TxStatusIncluded, Lookup: lookup}
+	inclusion, err = GetTransactionStatus(context.Background(), odr, tx.Hash())
+	if err != nil {
+		t.Fatalf("included: failed to retrieve status: %v", err)
+	}
+	if inclusion.Tx.Hash() != tx.Hash() || inclusion.BlockHash != lookup.BlockHash || inclusion.BlockNumber != 5 || inclusion.Index != 1 {
+		t.Errorf("included: position mismatch: have %+v", inclusion)
+	}
+	if inclusion.Receipt != receipts[1] {
+		t.Errorf("included: receipt mismatch: have %v, want %v", inclusion.Receipt, receipts[1])
+	}
+
+	odr.receipts = receipts[:1]
+	if _, err := GetTransactionStatus(context.Background(), odr, tx.Hash()); err != ErrTxNotProven {
+		t.Errorf("missing receipt: have %v, want %v", err, ErrTxNotProven)
+	}
+	odr.tx = nil
+	if _, err := GetTransactionStatus(context.Background(), odr, tx.Hash()); err != ErrTxNotProven {
+		t.Errorf("missing transaction: have %v, want %v", err, ErrTxNotProven)
+	}
+}
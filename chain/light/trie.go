@@ -135,6 +135,12 @@ func (t *odrTrie) GetKey(sha []byte) []byte {
 	return nil
 }
 
+func (t *odrTrie) Prove(hashedKey []byte, fromLevel uint, proofDb trie.DatabaseWriter) error {
+	return t.do(hashedKey, func() error {
+		return t.trie.Prove(hashedKey, fromLevel, proofDb)
+	})
+}
+
 // do tries and retries to execute a function until it returns with no error or
 // an error type other than MissingNodeError
 func (t *odrTrie) do(key []byte, fn func() error) error {
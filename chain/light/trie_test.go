@@ -23,10 +23,8 @@ import (
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
-	"github.com/Tinachain/Tina/chain/consensus/ethash"
 	"github.com/Tinachain/Tina/chain/core"
 	"github.com/Tinachain/Tina/chain/core/state"
-	"github.com/Tinachain/Tina/chain/core/vm"
 	"github.com/Tinachain/Tina/chain/ethdb"
 	"github.com/Tinachain/Tina/chain/params"
 	"github.com/Tinachain/Tina/chain/trie"
@@ -40,15 +38,11 @@ func TestNodeIterator(t *testing.T) {
 		genesis    = gspec.MustCommit(fulldb)
 	)
 	gspec.MustCommit(lightdb)
-	blockchain, _ := core.NewBlockChain(fulldb, params.TestChainConfig, ethash.NewFullFaker(), vm.Config{})
-	gchain, _ := core.GenerateChain(params.TestChainConfig, genesis, fulldb, 4, testChainGen)
-	if _, err := blockchain.InsertChain(gchain); err != nil {
-		panic(err)
-	}
+	gchain, _ := core.GenerateChain(params.TestChainConfig, genesis, fulldb, 4, nil, testChainGen)
 
 	ctx := context.Background()
 	odr := &testOdr{sdb: fulldb, ldb: lightdb}
-	head := blockchain.CurrentHeader()
+	head := gchain[len(gchain)-1].Header()
 	lightTrie, _ := NewStateDatabase(ctx, head, odr).OpenTrie(head.Root)
 	fullTrie, _ := state.NewDatabase(fulldb).OpenTrie(head.Root)
 	if err := diffTries(fullTrie, lightTrie); err != nil {
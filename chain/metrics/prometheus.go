@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// prometheusQuantiles are the quantiles reported for timers and histograms.
+var prometheusQuantiles = []float64{0.5, 0.75, 0.95, 0.99}
+
+// PrometheusHandler returns an HTTP handler exposing the metrics of the given
+// registry in the Prometheus text exposition format.
+func PrometheusHandler(registry metrics.Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		WritePrometheus(w, registry)
+	})
+}
+
+// WritePrometheus writes the metrics of the given registry to w in the Prometheus
+// text exposition format, sorted by name. Timers are reported in seconds.
+func WritePrometheus(w io.Writer, registry metrics.Registry) {
+	var names []string
+	all := make(map[string]interface{})
+	registry.Each(func(name string, metric interface{}) {
+		names = append(names, name)
+		all[name] = metric
+	})
+	sort.Strings(names)
+
+	buf := new(bytes.Buffer)
+	for _, name := range names {
+		key := prometheusName(name)
+		switch metric := all[name].(type) {
+		case metrics.Counter:
+			writePrometheusValue(buf, key, "counter", float64(metric.Count()))
+		case metrics.Gauge:
+			writePrometheusValue(buf, key, "gauge", float64(metric.Value()))
+		case metrics.GaugeFloat64:
+			writePrometheusValue(buf, key, "gauge", metric.Value())
+		case metrics.Meter:
+			writePrometheusValue(buf, key, "counter", float64(metric.Count()))
+		case metrics.Timer:
+			t := metric.Snapshot()
+			writePrometheusSummary(buf, key+"_seconds", t.Count(), t.Mean()*float64(t.Count())/1e9, t.Percentiles(prometheusQuantiles), 1e9)
+		case metrics.Histogram:
+			h := metric.Snapshot()
+			writePrometheusSummary(buf, key, h.Count(), float64(h.Sum()), h.Percentiles(prometheusQuantiles), 1)
+		}
+	}
+	w.Write(buf.Bytes())
+}
+
+// prometheusName converts a registry metric name into a valid Prometheus one.
+func prometheusName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func writePrometheusValue(w io.Writer, name, kind string, value float64) {
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+	fmt.Fprintf(w, "%s %v\n", name, value)
+}
+
+func writePrometheusSummary(w io.Writer, name string, count int64, sum float64, quantiles []float64, scale float64) {
+	fmt.Fprintf(w, "# TYPE %s summary\n", name)
+	for i, q := range prometheusQuantiles {
+		fmt.Fprintf(w, "%s{quantile=\"%v\"} %v\n", name, q, quantiles[i]/scale)
+	}
+	fmt.Fprintf(w, "%s_sum %v\n", name, sum)
+	fmt.Fprintf(w, "%s_count %d\n", name, count)
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	registry := metrics.NewRegistry()
+	metrics.GetOrRegisterCounter("txpool/pending/discard", registry).Inc(3)
+	metrics.GetOrRegisterGauge("p2p/peers", registry).Update(7)
+	metrics.GetOrRegisterMeter("rpc/requests", registry).Mark(2)
+	metrics.GetOrRegisterTimer("chain/inserts", registry).Update(2 * time.Second)
+
+	buf := new(bytes.Buffer)
+	WritePrometheus(buf, registry)
+	output := buf.String()
+
+	for _, want := range []string{
+		"# TYPE chain_inserts_seconds summary\nchain_inserts_seconds{quantile=\"0.5\"} 2\n",
+		"chain_inserts_seconds_sum 2\nchain_inserts_seconds_count 1\n",
+		"# TYPE p2p_peers gauge\np2p_peers 7\n",
+		"# TYPE rpc_requests counter\nrpc_requests 2\n",
+		"# TYPE txpool_pending_discard counter\ntxpool_pending_discard 3\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "chain_inserts") > strings.Index(output, "txpool_pending_discard") {
+		t.Errorf("metrics not sorted by name:\n%s", output)
+	}
+}
@@ -36,6 +36,8 @@ type Miner struct {
 	engine      consensus.Engine
 	canStart    int32 // can start indicates whether we can start the mining operation
 	shouldStart int32 // should start indicates whether we should start after sync
+
+	virtual *virtualPending //不出块时回答pending查询的虚拟待处理区块(nil为不启用)
 }
 
 func New(eth Backend, config *params.ChainConfig, mux *event.TypeMux, engine consensus.Engine) *Miner {
@@ -133,8 +135,18 @@ func (self *Miner) SetExtra(extra []byte) error {
 	return nil
 }
 
+//设置出块前的检查(如本地时钟偏差)，检查失败时跳过本节点的时间片
+func (self *Miner) SetSealGuard(guard func() error) {
+	self.worker.setSealGuard(guard)
+}
+
 // Pending returns the currently pending block and associated state.
 func (self *Miner) Pending() (*types.Block, *state.StateDB) {
+	if self.Speculative() {
+		if block, state := self.virtual.pending(); block != nil {
+			return block, state
+		}
+	}
 	return self.worker.pending()
 }
 
@@ -144,9 +156,39 @@ func (self *Miner) Pending() (*types.Block, *state.StateDB) {
 // simultaneously, please use Pending(), as the pending state can
 // change between multiple method calls
 func (self *Miner) PendingBlock() *types.Block {
+	if self.Speculative() {
+		if block, _ := self.virtual.pending(); block != nil {
+			return block
+		}
+	}
 	return self.worker.pendingBlock()
 }
 
+//启用虚拟待处理区块：本节点不出块时，pending查询使用在链头之上试执行交易池交易组装的区块，需要在提供RPC服务之前调用
+func (self *Miner) SetVirtualPending(enabled bool) {
+	if enabled {
+		self.virtual = newVirtualPending(self.worker)
+	} else {
+		self.virtual = nil
+	}
+}
+
+//pending查询的结果是否来自虚拟待处理区块(只是推测，不代表下一个区块的实际内容)
+func (self *Miner) Speculative() bool {
+	return self.virtual != nil && !self.Mining()
+}
+
+// PreviewBlock assembles the block this node would produce right now without
+// sealing or broadcasting it, returning the block and the receipts of the
+// included transactions.
+func (self *Miner) PreviewBlock() (*types.Block, types.Receipts, error) {
+	work, err := self.worker.preview()
+	if err != nil {
+		return nil, nil, err
+	}
+	return work.Block, work.receipts, nil
+}
+
 func (self *Miner) SetCoinbase(addr common.Address) {
 	self.coinbase = addr
 	self.worker.setCoinbase(addr)
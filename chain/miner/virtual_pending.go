@@ -0,0 +1,49 @@
+package miner
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/state"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/log"
+)
+
+//虚拟待处理区块在链头不变时的最长复用时间，之后重新组装以包含交易池中的新交易
+const virtualPendingRecommit = 2 * time.Second
+
+//没有出块权的节点(例如RPC节点)的虚拟待处理区块：在链头之上试执行交易池中的交易，
+//用于回答pending查询，结果只是推测，不代表下一个区块的实际内容
+type virtualPending struct {
+	worker *worker
+	lock   sync.Mutex
+	parent common.Hash //组装时的链头
+	built  time.Time   //组装时间
+	block  *types.Block
+	state  *state.StateDB
+}
+
+func newVirtualPending(worker *worker) *virtualPending {
+	return &virtualPending{worker: worker}
+}
+
+//得到虚拟待处理区块以及状态，链头变化或者超过复用时间时重新组装(只在查询时组装)；
+//组装失败时返回nil
+func (v *virtualPending) pending() (*types.Block, *state.StateDB) {
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	head := v.worker.chain.CurrentBlock().Hash()
+	if v.block == nil || v.parent != head || time.Since(v.built) > virtualPendingRecommit {
+		work, err := v.worker.preview()
+		if err != nil {
+			log.Warn("Failed to assemble virtual pending block", "parent", head, "err", err)
+			return nil, nil
+		}
+		v.parent, v.built = head, time.Now()
+		v.block, v.state = work.Block, work.state
+	}
+	return v.block, v.state.Copy()
+}
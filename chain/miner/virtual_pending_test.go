@@ -0,0 +1,52 @@
+package miner
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/core/types"
+)
+
+// Tests that the virtual pending block is reused until the head changes or the
+// recommit interval passes, and that callers can't modify the cached state.
+func TestVirtualPending(t *testing.T) {
+	w, backend := newTestWorker(t)
+	defer backend.chain.Stop()
+	defer backend.txPool.Stop()
+
+	v := newVirtualPending(w)
+	for nonce := uint64(0); nonce < 2; nonce++ {
+		tx, _ := types.SignTx(types.NewTransaction(protocol.Normal, protocol.NormalCall, nonce, common.Address{0x01}, big.NewInt(1000), big.NewInt(21000), big.NewInt(1), nil), types.HomesteadSigner{}, testBankKey)
+		if err := backend.txPool.AddLocal(tx); err != nil {
+			t.Fatalf("failed to add transaction %d: %v", nonce, err)
+		}
+		block, statedb := v.pending()
+		if block == nil {
+			t.Fatalf("transaction %d: no virtual pending block", nonce)
+		}
+		//首笔交易后组装，第二笔交易在复用时间内不会被包含
+		if len(block.Transactions()) != 1 {
+			t.Errorf("transaction %d: transactions mismatch: have %d, want 1", nonce, len(block.Transactions()))
+		}
+		statedb.AddBalance(common.Address{0x01}, big.NewInt(1))
+	}
+	if _, statedb := v.pending(); statedb.GetBalance(common.Address{0x01}).Int64() != 1000 {
+		t.Errorf("cached state modified: balance %v, want 1000", statedb.GetBalance(common.Address{0x01}))
+	}
+
+	v.built = time.Now().Add(-virtualPendingRecommit - time.Second)
+	if block, _ := v.pending(); len(block.Transactions()) != 2 {
+		t.Errorf("after recommit: transactions mismatch: have %d, want 2", len(block.Transactions()))
+	}
+	cached, _ := v.pending()
+	v.parent = common.Hash{0x01}
+	if block, _ := v.pending(); block == cached {
+		t.Errorf("virtual pending block not reassembled after a head change")
+	}
+	if head := backend.chain.CurrentBlock().NumberU64(); head != 0 {
+		t.Errorf("chain head moved to %d", head)
+	}
+}
@@ -52,6 +52,7 @@ type Work struct {
 	family       *set.Set     // family set (used for checking uncle invalidity)
 	uncles       *set.Set     // uncle set
 	tcount       int          // tx count in cycle
+	extraSize    uint64       // data carried by the extra transactions committed
 	Block        *types.Block // the new block
 	header       *types.Header
 	txs          []*types.Transaction
@@ -82,6 +83,7 @@ type worker struct {
 	chainDb        ethdb.Database
 	coinbase       common.Address
 	extra          []byte
+	sealGuard      func() error //出块前的检查
 	currentMu      sync.Mutex
 	current        *Work
 	uncleMu        sync.Mutex
@@ -153,6 +155,28 @@ func (self *worker) setExtra(extra []byte) {
 	self.extra = extra
 }
 
+func (self *worker) setSealGuard(guard func() error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.sealGuard = guard
+}
+
+//出块前的检查是否通过
+func (self *worker) sealAllowed() bool {
+	self.mu.Lock()
+	guard := self.sealGuard
+	self.mu.Unlock()
+
+	if guard == nil {
+		return true
+	}
+	if err := guard(); err != nil {
+		log.Warn("Refusing to seal block", "err", err)
+		return false
+	}
+	return true
+}
+
 func (self *worker) pending() (*types.Block, *state.StateDB) {
 	self.currentMu.Lock()
 	defer self.currentMu.Unlock()
@@ -223,6 +247,9 @@ func (self *worker) mintBlock(now int64) {
 		if self.chain.Boker().IsLocalValidator(self.coinbase) {
 
 			log.Info("mintBlock")
+			if !self.sealAllowed() {
+				return
+			}
 
 			work, err := self.createNewWork()
 			if err != nil {
@@ -269,6 +296,9 @@ func (self *worker) mintBlock(now int64) {
 		}
 
 		//可以进行挖矿出块,创建一次挖矿矿工
+		if !self.sealAllowed() {
+			return
+		}
 		work, err := self.createNewWork()
 		if err != nil {
 			log.Error("Failed to create the new work", "err", err)
@@ -424,19 +454,30 @@ func newBokerFromProto(db ethdb.Database, bokerProto *types.BokerBackendProto) (
 //为当前周期创建一个新环境。
 func (self *worker) makeCurrent(parent *types.Block, header *types.Header) error {
 
+	work, err := self.newWork(parent, header)
+	if err != nil {
+		return err
+	}
+	self.current = work
+	return nil
+}
+
+//根据父块创建一个新的工作环境（不修改当前工作环境）
+func (self *worker) newWork(parent *types.Block, header *types.Header) (*Work, error) {
+
 	//根据父块状态创建一个新的StateDB实例
 	state, err := self.chain.StateAt(parent.Root())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	dposContext, err := types.NewDposContextFromProto(self.chainDb, parent.Header().DposProto)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	bokerContext, err := types.NewBokerContextFromProto(self.chainDb, parent.Header().BokerProto)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	//创建一个work实例
@@ -462,8 +503,56 @@ func (self *worker) makeCurrent(parent *types.Block, header *types.Header) error
 	}
 
 	work.tcount = 0
-	self.current = work
-	return nil
+	return work, nil
+}
+
+//组装本节点当前将要产生的区块（不封装、不广播、不修改当前工作环境），用于调试空块或交易选择问题
+func (self *worker) preview() (*Work, error) {
+
+	self.mu.Lock()
+	coinbase, extra := self.coinbase, common.CopyBytes(self.extra)
+	self.mu.Unlock()
+
+	parent := self.chain.CurrentBlock()
+	tstamp := time.Now().Unix()
+	if parent.Time().Cmp(new(big.Int).SetInt64(tstamp)) >= 0 {
+		tstamp = parent.Time().Int64() + 1
+	}
+
+	num := parent.Number()
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     num.Add(num, common.Big1),
+		GasLimit:   core.CalcGasLimit(parent),
+		GasUsed:    new(big.Int),
+		Extra:      extra,
+		Time:       big.NewInt(tstamp),
+		Coinbase:   coinbase,
+	}
+	if err := self.engine.Prepare(self.chain, header); err != nil {
+		return nil, fmt.Errorf("got error when preparing header, err: %s", err)
+	}
+
+	work, err := self.newWork(parent, header)
+	if err != nil {
+		return nil, fmt.Errorf("got error when create mining context, err: %s", err)
+	}
+
+	pending, err := self.eth.TxPool().Pending()
+	if err != nil {
+		return nil, fmt.Errorf("got error when fetch pending transactions, err: %s", err)
+	}
+	txs := types.NewTransactionsByPriceAndNonce(work.signer, pending)
+
+	//预览时不投递待处理事件
+	work.commitTransactions(nil, txs, self.chain, coinbase)
+
+	if work.Block, err = self.engine.Finalize(self.chain, header, work.state, work.txs, nil, work.receipts, work.dposContext, work.bokerContext, self.eth.Boker()); err != nil {
+		return nil, fmt.Errorf("got error when finalize block for preview, err: %s", err)
+	}
+	work.Block.DposContext = work.dposContext
+	work.Block.BokerContext = work.bokerContext
+	return work, nil
 }
 
 func (self *worker) createNewWork() (*Work, error) {
@@ -644,6 +733,13 @@ func (env *Work) commitTransactions(mux *event.TypeMux, txs *types.TransactionsB
 			continue
 		}
 
+		//区块中扩展交易携带的数据累计不能超过链配置的上限，跳过该账号的后续交易
+		if size := tx.ExtraPayloadSize(); size > 0 && env.extraSize+size > env.config.BlockExtraLimit() {
+			log.Trace("Extra size limit exceeded for current block", "sender", from, "size", size)
+			txs.Pop()
+			continue
+		}
+
 		//开始执行交易
 		env.state.Prepare(tx.Hash(), common.Hash{}, env.tcount)
 		err, logs := env.commitTransaction(tx, bc, coinbase, gp, sp)
@@ -665,6 +761,7 @@ func (env *Work) commitTransactions(mux *event.TypeMux, txs *types.TransactionsB
 		case nil:
 			coalescedLogs = append(coalescedLogs, logs...)
 			env.tcount++
+			env.extraSize += tx.ExtraPayloadSize()
 			txs.Shift()
 
 		default:
@@ -675,7 +772,7 @@ func (env *Work) commitTransactions(mux *event.TypeMux, txs *types.TransactionsB
 		}
 	}
 
-	if len(coalescedLogs) > 0 || env.tcount > 0 {
+	if mux != nil && (len(coalescedLogs) > 0 || env.tcount > 0) {
 		// make a copy, the state caches the logs and these logs get "upgraded" from pending to mined
 		// logs by filling in the block hash when the block was mined by the local miner. This can
 		// cause a race condition if a log was "upgraded" before the PendingLogsEvent is processed.
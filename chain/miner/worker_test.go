@@ -0,0 +1,97 @@
+package miner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/accounts"
+	"github.com/Tinachain/Tina/chain/boker/api"
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/consensus/ethash"
+	"github.com/Tinachain/Tina/chain/core"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/core/vm"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/event"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+var (
+	testBankKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testBank       = crypto.PubkeyToAddress(testBankKey.PublicKey)
+)
+
+// testBackend is a miner backend over an in-memory chain and transaction pool.
+type testBackend struct {
+	db     ethdb.Database
+	chain  *core.BlockChain
+	txPool *core.TxPool
+}
+
+func (b *testBackend) AccountManager() *accounts.Manager { return nil }
+func (b *testBackend) BlockChain() *core.BlockChain      { return b.chain }
+func (b *testBackend) TxPool() *core.TxPool              { return b.txPool }
+func (b *testBackend) ChainDb() ethdb.Database           { return b.db }
+func (b *testBackend) Boker() bokerapi.Api               { return nil }
+
+func newTestWorker(t *testing.T) (*worker, *testBackend) {
+	var (
+		db, _ = ethdb.NewMemDatabase()
+		gspec = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  core.GenesisAlloc{testBank: {Balance: big.NewInt(1000000)}},
+		}
+	)
+	gspec.MustCommit(db)
+	poolConfig := core.DefaultTxPoolConfig
+	poolConfig.Journal = ""
+
+	chain, err := core.NewBlockChain(db, gspec.Config, ethash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	backend := &testBackend{
+		db:     db,
+		chain:  chain,
+		txPool: core.NewTxPool(poolConfig, gspec.Config, chain),
+	}
+	return newWorker(gspec.Config, ethash.NewFaker(), common.Address{0xc0}, backend, new(event.TypeMux)), backend
+}
+
+// Tests that previewing assembles the next block from the pending transactions
+// without touching the chain or the current mining work.
+func TestPreviewBlock(t *testing.T) {
+	w, backend := newTestWorker(t)
+	defer backend.chain.Stop()
+	defer backend.txPool.Stop()
+
+	tx, _ := types.SignTx(types.NewTransaction(protocol.Normal, protocol.NormalCall, 0, common.Address{0x01}, big.NewInt(1000), big.NewInt(21000), big.NewInt(1), nil), types.HomesteadSigner{}, testBankKey)
+	if err := backend.txPool.AddLocal(tx); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+
+	work, err := w.preview()
+	if err != nil {
+		t.Fatalf("failed to preview block: %v", err)
+	}
+	if work.Block.NumberU64() != 1 || work.Block.ParentHash() != backend.chain.CurrentBlock().Hash() {
+		t.Errorf("block position mismatch: number %d, parent %x", work.Block.NumberU64(), work.Block.ParentHash())
+	}
+	if txs := work.Block.Transactions(); len(txs) != 1 || txs[0].Hash() != tx.Hash() {
+		t.Fatalf("transactions mismatch: have %d, want 1", len(txs))
+	}
+	if len(work.receipts) != 1 || work.receipts[0].GasUsed.Uint64() != 21000 {
+		t.Errorf("receipts mismatch: have %v", work.receipts)
+	}
+	if balance := work.state.GetBalance(common.Address{0x01}); balance.Int64() != 1000 {
+		t.Errorf("recipient balance mismatch: have %v, want 1000", balance)
+	}
+	if head := backend.chain.CurrentBlock().NumberU64(); head != 0 {
+		t.Errorf("chain head moved to %d", head)
+	}
+	if w.current != nil {
+		t.Errorf("current work replaced by the preview")
+	}
+}
@@ -149,7 +149,7 @@ func (api *PrivateAdminAPI) StartRPC(host *string, port *int, cors *string, apis
 		}
 	}
 
-	if err := api.node.startHTTP(fmt.Sprintf("%s:%d", *host, *port), api.node.rpcAPIs, modules, allowedOrigins); err != nil {
+	if err := api.node.startHTTP(fmt.Sprintf("%s:%d", *host, *port), api.node.rpcAPIs, modules, allowedOrigins, api.node.config.HTTPVirtualHosts); err != nil {
 		return false, err
 	}
 	return true, nil
@@ -167,6 +167,61 @@ func (api *PrivateAdminAPI) StopRPC() (bool, error) {
 	return true, nil
 }
 
+// HTTPConfig returns the allowed origins, virtual hosts and modules of the running
+// HTTP RPC API endpoint.
+func (api *PrivateAdminAPI) HTTPConfig() (*HTTPConfig, error) {
+	api.node.lock.RLock()
+	defer api.node.lock.RUnlock()
+
+	return api.node.httpConfig()
+}
+
+// SetHTTPCors changes the comma separated list of cross-origin domains allowed by
+// the running HTTP RPC API endpoint.
+func (api *PrivateAdminAPI) SetHTTPCors(cors string) (bool, error) {
+	return api.updateHTTPConfig(func(config *HTTPConfig) { config.Cors = splitList(cors) })
+}
+
+// SetHTTPVirtualHosts changes the comma separated list of virtual hosts allowed by
+// the running HTTP RPC API endpoint.
+func (api *PrivateAdminAPI) SetHTTPVirtualHosts(vhosts string) (bool, error) {
+	return api.updateHTTPConfig(func(config *HTTPConfig) { config.VirtualHosts = splitList(vhosts) })
+}
+
+// SetHTTPModules changes the comma separated list of API modules offered by the
+// running HTTP RPC API endpoint.
+func (api *PrivateAdminAPI) SetHTTPModules(apis string) (bool, error) {
+	return api.updateHTTPConfig(func(config *HTTPConfig) { config.Modules = splitList(apis) })
+}
+
+// updateHTTPConfig applies a change to the running HTTP RPC API endpoint without
+// restarting it, persisting the new settings.
+func (api *PrivateAdminAPI) updateHTTPConfig(update func(*HTTPConfig)) (bool, error) {
+	api.node.lock.Lock()
+	defer api.node.lock.Unlock()
+
+	config, err := api.node.httpConfig()
+	if err != nil {
+		return false, err
+	}
+	update(config)
+	if err := api.node.reconfigureHTTP(config); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// splitList splits a comma separated list, dropping the empty elements.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // StartWS starts the websocket RPC API server.
 func (api *PrivateAdminAPI) StartWS(host *string, port *int, allowedOrigins *string, apis *string) (bool, error) {
 	api.node.lock.Lock()
@@ -209,7 +264,7 @@ func (api *PrivateAdminAPI) StartWS(host *string, port *int, allowedOrigins *str
 	return true, nil
 }
 
-// StopRPC terminates an already running websocket RPC API endpoint.
+// StopWS terminates an already running websocket RPC API endpoint.
 func (api *PrivateAdminAPI) StopWS() (bool, error) {
 	api.node.lock.Lock()
 	defer api.node.lock.Unlock()
@@ -221,6 +276,36 @@ func (api *PrivateAdminAPI) StopWS() (bool, error) {
 	return true, nil
 }
 
+// AuditLog returns the most recent privileged RPC invocations recorded in the
+// audit log, newest first. If count is omitted, all retained entries are returned.
+func (api *PrivateAdminAPI) AuditLog(count *int) ([]*AuditEntry, error) {
+	api.node.lock.RLock()
+	audit := api.node.auditLog
+	api.node.lock.RUnlock()
+
+	if audit == nil {
+		return nil, fmt.Errorf("audit log not enabled")
+	}
+	limit := 0
+	if count != nil {
+		limit = *count
+	}
+	return audit.Recent(limit), nil
+}
+
+// RpcStats returns the call counts, error rates and execution times of the RPC
+// methods served by all endpoints of the node, grouped by namespace, along with
+// the number of calls issued by each client since the last reset.
+func (api *PrivateAdminAPI) RpcStats() *rpc.UsageReport {
+	return api.node.rpcStats.Report()
+}
+
+// ResetRpcStats clears the RPC usage stats, returning the ones accumulated until
+// the reset.
+func (api *PrivateAdminAPI) ResetRpcStats() *rpc.UsageReport {
+	return api.node.rpcStats.Reset()
+}
+
 // PublicAdminAPI is the collection of administrative API methods exposed over
 // both secure and unsecure RPC channels.
 type PublicAdminAPI struct {
@@ -245,12 +330,12 @@ func (api *PublicAdminAPI) Peers() ([]*p2p.PeerInfo, error) {
 
 // NodeInfo retrieves all the information we know about the host node at the
 // protocol granularity.
-func (api *PublicAdminAPI) NodeInfo() (*p2p.NodeInfo, error) {
+func (api *PublicAdminAPI) NodeInfo() (*NodeInfo, error) {
 	server := api.node.Server()
 	if server == nil {
 		return nil, ErrNodeStopped
 	}
-	return server.NodeInfo(), nil
+	return &NodeInfo{NodeInfo: server.NodeInfo(), Build: api.node.BuildInfo()}, nil
 }
 
 // Datadir retrieves the current data directory the node is using.
@@ -373,9 +458,13 @@ func NewPublicWeb3API(stack *Node) *PublicWeb3API {
 	return &PublicWeb3API{stack}
 }
 
-// ClientVersion returns the node name
+// ClientVersion returns the node name, consisting of the client name, version and
+// commit, platform and Go version.
 func (s *PublicWeb3API) ClientVersion() string {
-	return s.stack.Server().Name
+	if server := s.stack.Server(); server != nil {
+		return server.Name
+	}
+	return s.stack.config.NodeName()
 }
 
 // Sha3 applies the ethereum sha3 implementation on the input.
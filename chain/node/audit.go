@@ -0,0 +1,170 @@
+package node
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+// auditRecentLimit is the number of most recent audit entries kept in memory for
+// the admin_auditLog query.
+const auditRecentLimit = 1024
+
+// auditRedacted replaces sensitive parameters in the audit log.
+const auditRedacted = "<redacted>"
+
+// auditedMethods lists the privileged RPC methods recorded in the audit log, along
+// with the positions of the parameters that must never be written out.
+var auditedMethods = map[string][]int{
+	"personal_unlockAccount":     {1},
+	"eth_setSystemBaseContracts": nil,
+	"eth_addValidator":           nil,
+	"debug_setHead":              nil,
+}
+
+// auditedPrefixes lists method name prefixes of which all methods are audited.
+var auditedPrefixes = []string{"eth_stock"}
+
+// AuditEntry is a single privileged RPC invocation recorded in the audit log.
+type AuditEntry struct {
+	Time   time.Time     `json:"time"`
+	Remote string        `json:"remote"`
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+	Result interface{}   `json:"result,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// auditLog is an append-only file recording the invocations of privileged RPC
+// methods. It implements rpc.Auditor and is attached to every RPC endpoint.
+type auditLog struct {
+	path   string
+	file   *os.File
+	recent []*AuditEntry // most recent entries, oldest first
+	lock   sync.Mutex
+}
+
+// openAuditLog opens (or creates) the audit file at path for appending, loading
+// its most recent entries for later queries.
+func openAuditLog(path string) (*auditLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	l := &auditLog{path: path, file: file}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		entry := new(AuditEntry)
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			continue
+		}
+		l.remember(entry)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Warn("Failed to load audit log", "path", path, "err", err)
+	}
+	return l, nil
+}
+
+// audited reports whether a method is privileged and returns the positions of its
+// redacted parameters.
+func audited(method string) ([]int, bool) {
+	if redacted, ok := auditedMethods[method]; ok {
+		return redacted, true
+	}
+	for _, prefix := range auditedPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return nil, true
+		}
+	}
+	return nil, false
+}
+
+// Audit implements rpc.Auditor, appending privileged method calls to the log.
+func (l *auditLog) Audit(ctx context.Context, method string, args []interface{}, result interface{}, err error) {
+	redacted, ok := audited(method)
+	if !ok {
+		return
+	}
+	entry := &AuditEntry{
+		Time:   time.Now().UTC(),
+		Remote: "local",
+		Method: method,
+		Params: make([]interface{}, len(args)),
+		Result: result,
+	}
+	if remote, ok := rpc.RemoteAddrFromContext(ctx); ok {
+		entry.Remote = remote
+	}
+	copy(entry.Params, args)
+	for _, i := range redacted {
+		if i < len(entry.Params) {
+			entry.Params[i] = auditRedacted
+		}
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	blob, jsonErr := json.Marshal(entry)
+	if jsonErr != nil {
+		log.Error("Failed to encode audit entry", "method", method, "err", jsonErr)
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.file == nil {
+		return
+	}
+	if _, err := l.file.Write(append(blob, '\n')); err != nil {
+		log.Error("Failed to write audit entry", "path", l.path, "method", method, "err", err)
+	} else if err := l.file.Sync(); err != nil {
+		log.Error("Failed to sync audit log", "path", l.path, "err", err)
+	}
+	l.remember(entry)
+}
+
+// remember adds an entry to the in-memory window of recent entries.
+func (l *auditLog) remember(entry *AuditEntry) {
+	l.recent = append(l.recent, entry)
+	if len(l.recent) > auditRecentLimit {
+		l.recent = append([]*AuditEntry{}, l.recent[len(l.recent)-auditRecentLimit:]...)
+	}
+}
+
+// Recent returns up to count of the most recent entries, newest first.
+func (l *auditLog) Recent(count int) []*AuditEntry {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if count <= 0 || count > len(l.recent) {
+		count = len(l.recent)
+	}
+	entries := make([]*AuditEntry, 0, count)
+	for i := len(l.recent) - 1; i >= len(l.recent)-count; i-- {
+		entries = append(entries, l.recent[i])
+	}
+	return entries
+}
+
+// Close closes the underlying audit file.
+func (l *auditLog) Close() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
@@ -0,0 +1,93 @@
+package node
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+// AuditedService mimics a couple of privileged and unprivileged RPC methods.
+type AuditedService struct{}
+
+func (s *AuditedService) UnlockAccount(account string, password string) (bool, error) {
+	if password != "secret" {
+		return false, errors.New("could not decrypt key with given passphrase")
+	}
+	return true, nil
+}
+
+func (s *AuditedService) ListAccounts() []string { return []string{"0x01"} }
+
+// Tests that privileged calls are recorded with the password redacted and that the
+// recorded entries survive reopening the log.
+func TestAuditLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit-test")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+
+	audit, err := openAuditLog(path)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	server := rpc.NewServer()
+	server.SetAuditor(audit)
+	if err := server.RegisterName("personal", new(AuditedService)); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	client := rpc.DialInProc(server)
+
+	var unlocked bool
+	if err := client.Call(&unlocked, "personal_unlockAccount", "0x01", "secret"); err != nil || !unlocked {
+		t.Fatalf("unlock failed: %v", err)
+	}
+	if err := client.Call(&unlocked, "personal_unlockAccount", "0x01", "wrong"); err == nil {
+		t.Fatalf("unlock with wrong password succeeded")
+	}
+	var accounts []string
+	if err := client.Call(&accounts, "personal_listAccounts"); err != nil {
+		t.Fatalf("list accounts failed: %v", err)
+	}
+	client.Close()
+	audit.Close()
+
+	blob, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	if strings.Contains(string(blob), "secret") || strings.Contains(string(blob), "wrong") {
+		t.Fatalf("audit log contains password: %s", blob)
+	}
+	if strings.Contains(string(blob), "personal_listAccounts") {
+		t.Fatalf("audit log contains unprivileged call: %s", blob)
+	}
+
+	audit, err = openAuditLog(path)
+	if err != nil {
+		t.Fatalf("failed to reopen audit log: %v", err)
+	}
+	defer audit.Close()
+
+	entries := audit.Recent(0)
+	if len(entries) != 2 {
+		t.Fatalf("entry count mismatch: have %d, want 2", len(entries))
+	}
+	if entries[0].Error == "" || entries[1].Error != "" || entries[1].Result != true {
+		t.Errorf("entry outcome mismatch: have %+v, %+v", entries[0], entries[1])
+	}
+	for _, entry := range entries {
+		if entry.Method != "personal_unlockAccount" || entry.Remote != "local" || entry.Params[1] != auditRedacted {
+			t.Errorf("entry mismatch: have %+v", entry)
+		}
+	}
+	if recent := audit.Recent(1); len(recent) != 1 || recent[0] != entries[0] {
+		t.Errorf("recent entries mismatch: have %v", recent)
+	}
+}
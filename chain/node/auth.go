@@ -0,0 +1,118 @@
+package node
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/log"
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+const authTokenSkew = 60 * time.Second // Maximum distance of a token's issue time from the local clock
+
+var (
+	errMissingToken    = errors.New("missing authorization token")
+	errNamespaceDenied = errors.New("token not authorized for namespace")
+	errMissingIssuedAt = errors.New("missing token issue time")
+	errStaleToken      = errors.New("token issue time too far from the local clock")
+	errExpiredToken    = errors.New("token is expired")
+)
+
+// authClaims are the claims of the JSON web tokens accepted by the RPC endpoints.
+// If Modules is empty, the token grants access to all protected namespaces.
+type authClaims struct {
+	jwt.StandardClaims
+	Modules []string `json:"modules,omitempty"`
+}
+
+// Valid implements jwt.Claims. Tokens must carry an issue time close to the local
+// clock, so a leaked token can only be replayed for a short while.
+func (c *authClaims) Valid() error {
+	if c.IssuedAt == 0 {
+		return errMissingIssuedAt
+	}
+	now := time.Now()
+	if issued := time.Unix(c.IssuedAt, 0); issued.Before(now.Add(-authTokenSkew)) || issued.After(now.Add(authTokenSkew)) {
+		return errStaleToken
+	}
+	if c.ExpiresAt != 0 && now.Unix() > c.ExpiresAt {
+		return errExpiredToken
+	}
+	return nil
+}
+
+// rpcAuth is an rpc.Authorizer granting access to the protected namespaces only to
+// callers presenting a token signed (HS256) with the node's authentication secret
+// and issued (iat) within authTokenSkew of the local clock.
+type rpcAuth struct {
+	secret    []byte
+	protected map[string]bool
+}
+
+// newRPCAuth creates an authorizer protecting the given namespaces.
+func newRPCAuth(secret []byte, modules []string) *rpcAuth {
+	auth := &rpcAuth{secret: secret, protected: make(map[string]bool)}
+	for _, module := range modules {
+		auth.protected[module] = true
+	}
+	return auth
+}
+
+// Authorize implements rpc.Authorizer.
+func (a *rpcAuth) Authorize(token string, namespace string) error {
+	if !a.protected[namespace] {
+		return nil
+	}
+	if token == "" {
+		return errMissingToken
+	}
+	claims := new(authClaims)
+	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return a.secret, nil
+	})
+	if err != nil {
+		return fmt.Errorf("invalid authorization token: %v", err)
+	}
+	if len(claims.Modules) == 0 {
+		return nil
+	}
+	for _, module := range claims.Modules {
+		if module == namespace {
+			return nil
+		}
+	}
+	return errNamespaceDenied
+}
+
+// loadAuthSecret reads the hex encoded authentication secret from path, generating
+// and persisting a new random one if the file does not exist yet.
+func loadAuthSecret(path string) ([]byte, error) {
+	if blob, err := ioutil.ReadFile(path); err == nil {
+		secret := common.FromHex(strings.TrimSpace(string(blob)))
+		if len(secret) < 32 {
+			return nil, fmt.Errorf("authentication secret in %s must be at least 32 bytes", path)
+		}
+		return secret, nil
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(path, []byte(hex.EncodeToString(secret)), 0600); err != nil {
+		return nil, err
+	}
+	log.Info("Generated RPC authentication secret", "path", path)
+	return secret, nil
+}
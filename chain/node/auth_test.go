@@ -0,0 +1,102 @@
+package node
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/rpc"
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+func signAuthToken(t *testing.T, secret []byte, claims *authClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return token
+}
+
+// Tests that protected namespaces are only reachable with a valid token.
+func TestRPCAuthorization(t *testing.T) {
+	secret := bytes.Repeat([]byte{0x01}, 32)
+	auth := newRPCAuth(secret, []string{"personal", "admin"})
+
+	server := rpc.NewServer()
+	server.SetAuthorizer(auth)
+	if err := server.RegisterName("personal", new(AuditedService)); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	if err := server.RegisterName("eth", new(AuditedService)); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	issued := func(offset time.Duration, modules ...string) *authClaims {
+		return &authClaims{StandardClaims: jwt.StandardClaims{IssuedAt: time.Now().Add(offset).Unix()}, Modules: modules}
+	}
+	expired := issued(0)
+	expired.ExpiresAt = time.Now().Add(-time.Second).Unix()
+	tests := []struct {
+		token  string
+		method string
+		ok     bool
+	}{
+		{"", "eth_listAccounts", true},
+		{"", "personal_listAccounts", false},
+		{"garbage", "personal_listAccounts", false},
+		{signAuthToken(t, secret, issued(0)), "personal_listAccounts", true},
+		{signAuthToken(t, bytes.Repeat([]byte{0x02}, 32), issued(0)), "personal_listAccounts", false},
+		{signAuthToken(t, secret, issued(0, "personal")), "personal_listAccounts", true},
+		{signAuthToken(t, secret, issued(0, "admin")), "personal_listAccounts", false},
+		{signAuthToken(t, secret, expired), "personal_listAccounts", false},
+		{signAuthToken(t, secret, new(authClaims)), "personal_listAccounts", false},
+		{signAuthToken(t, secret, issued(30*time.Second)), "personal_listAccounts", true},
+		{signAuthToken(t, secret, issued(-30*time.Second)), "personal_listAccounts", true},
+		{signAuthToken(t, secret, issued(2*time.Minute)), "personal_listAccounts", false},
+		{signAuthToken(t, secret, issued(-2*time.Minute)), "personal_listAccounts", false},
+	}
+	for i, tt := range tests {
+		client, err := rpc.DialHTTPWithToken(httpsrv.URL, tt.token)
+		if err != nil {
+			t.Fatalf("test %d: failed to dial: %v", i, err)
+		}
+		var accounts []string
+		err = client.Call(&accounts, tt.method)
+		if tt.ok && err != nil {
+			t.Errorf("test %d: call failed: %v", i, err)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("test %d: unauthorized call succeeded", i)
+		}
+		client.Close()
+	}
+}
+
+// Tests that a missing secret is generated once and reused afterwards.
+func TestLoadAuthSecret(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auth-test")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "authsecret")
+
+	secret, err := loadAuthSecret(path)
+	if err != nil || len(secret) != 32 {
+		t.Fatalf("failed to generate secret: %x, %v", secret, err)
+	}
+	loaded, err := loadAuthSecret(path)
+	if err != nil || !bytes.Equal(loaded, secret) {
+		t.Fatalf("secret mismatch: have %x, want %x (%v)", loaded, secret, err)
+	}
+	ioutil.WriteFile(path, []byte("0x1234"), 0600)
+	if _, err := loadAuthSecret(path); err == nil {
+		t.Fatalf("short secret accepted")
+	}
+}
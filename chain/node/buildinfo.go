@@ -0,0 +1,50 @@
+package node
+
+import (
+	"runtime"
+	"sort"
+
+	"github.com/Tinachain/Tina/chain/p2p"
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+// BuildInfo describes the running client build, allowing infrastructure to track
+// the versions deployed across the validator set.
+type BuildInfo struct {
+	Client    string   `json:"client"`    // Client name advertised over the network
+	Version   string   `json:"version"`   // Semantic version of the release
+	GitCommit string   `json:"gitCommit"` // Git commit the binary was built from (set via linker flags)
+	GoVersion string   `json:"goVersion"` // Go compiler version used to build the binary
+	Platform  string   `json:"platform"`  // Operating system and architecture
+	Modules   []string `json:"modules"`   // API modules offered by the node
+}
+
+// NodeInfo is the information about the running node reported by admin_nodeInfo.
+type NodeInfo struct {
+	*p2p.NodeInfo
+	Build *BuildInfo `json:"build"`
+}
+
+// BuildInfo returns the build information of the running client.
+func (n *Node) BuildInfo() *BuildInfo {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	info := &BuildInfo{
+		Client:    n.config.name(),
+		Version:   params.Version,
+		GitCommit: n.config.GitCommit,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "-" + runtime.GOARCH,
+		Modules:   []string{},
+	}
+	seen := make(map[string]bool)
+	for _, api := range n.rpcAPIs {
+		if !seen[api.Namespace] {
+			seen[api.Namespace] = true
+			info.Modules = append(info.Modules, api.Namespace)
+		}
+	}
+	sort.Strings(info.Modules)
+	return info
+}
@@ -0,0 +1,44 @@
+package node
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/params"
+)
+
+// Tests that the build information is reported through admin_nodeInfo.
+func TestBuildInfo(t *testing.T) {
+	config := testNodeConfig()
+	config.GitCommit = "0123456789abcdef"
+	stack, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create protocol stack: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start protocol stack: %v", err)
+	}
+	defer stack.Stop()
+
+	info, err := NewPublicAdminAPI(stack).NodeInfo()
+	if err != nil {
+		t.Fatalf("failed to retrieve node info: %v", err)
+	}
+	build := info.Build
+	if build.Version != params.Version || build.GitCommit != config.GitCommit || build.GoVersion == "" {
+		t.Errorf("build info mismatch: have %+v", build)
+	}
+	if want := []string{"admin", "debug", "web3"}; !reflect.DeepEqual(build.Modules, want) {
+		t.Errorf("modules mismatch: have %v, want %v", build.Modules, want)
+	}
+	blob, err := json.Marshal(info)
+	if err != nil {
+		t.Fatalf("failed to encode node info: %v", err)
+	}
+	var fields map[string]interface{}
+	json.Unmarshal(blob, &fields)
+	if fields["enode"] == nil || fields["build"] == nil {
+		t.Errorf("node info fields missing: %s", blob)
+	}
+}
@@ -18,12 +18,14 @@ package node
 
 import (
 	"crypto/ecdsa"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/Tinachain/Tina/chain/accounts"
 	"github.com/Tinachain/Tina/chain/accounts/keystore"
@@ -33,6 +35,7 @@ import (
 	"github.com/Tinachain/Tina/chain/log"
 	"github.com/Tinachain/Tina/chain/p2p"
 	"github.com/Tinachain/Tina/chain/p2p/discover"
+	"github.com/Tinachain/Tina/chain/rpc"
 )
 
 const (
@@ -41,6 +44,7 @@ const (
 	datadirStaticNodes     = "static-nodes.json"  // Path within the datadir to the static node list
 	datadirTrustedNodes    = "trusted-nodes.json" // Path within the datadir to the trusted node list
 	datadirNodeDatabase    = "nodes"              // Path within the datadir to store the node infos
+	datadirHTTPConfig      = "http-config.json"   // Path within the datadir to the runtime HTTP RPC settings
 )
 
 // Config represents a small collection of configuration values to fine tune the
@@ -59,6 +63,10 @@ type Config struct {
 	// in the devp2p node identifier.
 	Version string `toml:"-"`
 
+	// GitCommit is the commit hash the program was built from, reported alongside
+	// the version in the build information of the node.
+	GitCommit string `toml:"-"`
+
 	// DataDir is the file system folder the node should use for any data storage
 	// requirements. The configured data directory will not be directly shared with
 	// registered services, instead those can use utility methods to create/access
@@ -105,11 +113,24 @@ type Config struct {
 	// useless for custom HTTP clients.
 	HTTPCors []string `toml:",omitempty"`
 
+	// HTTPVirtualHosts is the list of virtual hostnames which are allowed on incoming
+	// requests. This is by default {'localhost'}. Using this prevents attacks like
+	// DNS rebinding, which bypasses SOP by simply masquerading as being within the
+	// same origin. These attacks do not utilize CORS, since they are not cross-domain.
+	// By explicitly checking the Host-header, the server will not allow requests
+	// made against the server with a malicious host domain. Requests using an IP
+	// address directly are not affected.
+	HTTPVirtualHosts []string `toml:",omitempty"`
+
 	// HTTPModules is a list of API modules to expose via the HTTP RPC interface.
 	// If the module list is empty, all RPC API endpoints designated public will be
 	// exposed.
 	HTTPModules []string `toml:",omitempty"`
 
+	// HTTPTimeouts are the read, write and idle timeouts of the HTTP RPC server.
+	// Zero values disable the corresponding timeout.
+	HTTPTimeouts rpc.HTTPTimeouts
+
 	// WSHost is the host interface on which to start the websocket RPC server. If
 	// this field is empty, no websocket API endpoint will be started.
 	WSHost string `toml:",omitempty"`
@@ -129,12 +150,55 @@ type Config struct {
 	// exposed.
 	WSModules []string `toml:",omitempty"`
 
+	// WSHandshakeTimeout is the time a websocket client is given to complete the
+	// upgrade request. Established connections are not affected. Zero disables it.
+	WSHandshakeTimeout time.Duration `toml:",omitempty"`
+
+	// WSMaxSubscriptions is the maximum number of subscriptions a single websocket
+	// connection may hold. Zero means unlimited.
+	WSMaxSubscriptions int `toml:",omitempty"`
+
+	// WSMaxBufferedNotifications is the maximum number of notifications queued for a
+	// single websocket connection. Clients falling further behind are disconnected.
+	// Zero writes notifications synchronously.
+	WSMaxBufferedNotifications int `toml:",omitempty"`
+
 	// WSExposeAll exposes all API modules via the WebSocket RPC interface rather
 	// than just the public ones.
 	//
 	// *WARNING* Only set this if the node is running in a trusted network, exposing
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
+
+	// IPCOnlyPrivileged restricts the privileged API modules (admin, debug and
+	// personal), which can touch keys or rewind the chain, to the IPC transport. They
+	// are never offered over HTTP or websocket, regardless of the module whitelists.
+	IPCOnlyPrivileged bool `toml:",omitempty"`
+
+	// AuthSecret is the file holding the hex encoded secret used to verify the JSON
+	// web tokens (HS256) presented by HTTP and websocket RPC clients. A random secret
+	// is generated if the file does not exist. Relative paths are resolved in the
+	// instance directory, an empty value disables authentication.
+	AuthSecret string `toml:",omitempty"`
+
+	// AuthModules is the list of API modules which may only be called over HTTP and
+	// websocket by clients presenting a valid token, if authentication is enabled.
+	// IPC and in-process connections are always trusted.
+	AuthModules []string `toml:",omitempty"`
+
+	// AuditLog is the file recording every invocation of the privileged RPC methods
+	// (account unlocking, system contract, stock, chain head and validator changes).
+	// Relative paths are resolved in the instance directory, an empty value disables
+	// auditing.
+	AuditLog string `toml:",omitempty"`
+
+	// MetricsHost is the host interface on which to expose the collected metrics in
+	// the Prometheus text format. If this field is empty, no metrics endpoint will
+	// be started.
+	MetricsHost string `toml:",omitempty"`
+
+	// MetricsPort is the TCP port number on which to start the metrics HTTP server.
+	MetricsPort int `toml:",omitempty"`
 }
 
 // IPCEndpoint resolves an IPC endpoint based on a configured value, taking into
@@ -212,6 +276,24 @@ func DefaultWSEndpoint() string {
 	return config.WSEndpoint()
 }
 
+// AuditLogPath resolves the path of the audit log, returning an empty string if
+// auditing is disabled or the node is ephemeral and a relative path was given.
+func (c *Config) AuditLogPath() string {
+	if c.AuditLog == "" {
+		return ""
+	}
+	return c.resolvePath(c.AuditLog)
+}
+
+// MetricsEndpoint resolves the metrics HTTP endpoint based on the configured host
+// interface and port parameters.
+func (c *Config) MetricsEndpoint() string {
+	if c.MetricsHost == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", c.MetricsHost, c.MetricsPort)
+}
+
 // NodeName returns the devp2p node identifier.
 func (c *Config) NodeName() string {
 	name := c.name()
@@ -328,6 +410,62 @@ func (c *Config) TrustedNodes() []*discover.Node {
 	return c.parsePersistentNodes(c.resolvePath(datadirTrustedNodes))
 }
 
+// ipcOnlyModules are the API modules kept off the HTTP and websocket transports if
+// IPCOnlyPrivileged is set.
+var ipcOnlyModules = []string{"admin", "debug", "personal"}
+
+// ipcOnly reports whether the module may only be offered over IPC.
+func (c *Config) ipcOnly(module string) bool {
+	if !c.IPCOnlyPrivileged {
+		return false
+	}
+	for _, m := range ipcOnlyModules {
+		if m == module {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPConfig is the part of the HTTP RPC settings that can be changed at runtime.
+type HTTPConfig struct {
+	Cors         []string `json:"cors"`
+	VirtualHosts []string `json:"vhosts"`
+	Modules      []string `json:"modules"`
+}
+
+// loadHTTPConfig overrides the HTTP RPC settings with the ones persisted in the
+// instance directory by a previous runtime change, if any.
+func (c *Config) loadHTTPConfig() {
+	path := c.resolvePath(datadirHTTPConfig)
+	if path == "" {
+		return
+	}
+	if _, err := os.Stat(path); err != nil {
+		return
+	}
+	var config HTTPConfig
+	if err := common.LoadJSON(path, &config); err != nil {
+		log.Error(fmt.Sprintf("Can't load HTTP config file %s: %v", path, err))
+		return
+	}
+	c.HTTPCors, c.HTTPVirtualHosts, c.HTTPModules = config.Cors, config.VirtualHosts, config.Modules
+}
+
+// storeHTTPConfig persists the runtime HTTP RPC settings in the instance directory
+// so that they survive a restart of the node.
+func (c *Config) storeHTTPConfig() error {
+	path := c.resolvePath(datadirHTTPConfig)
+	if path == "" {
+		return nil
+	}
+	blob, err := json.MarshalIndent(&HTTPConfig{c.HTTPCors, c.HTTPVirtualHosts, c.HTTPModules}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, blob, 0600)
+}
+
 // parsePersistentNodes parses a list of discovery node URLs loaded from a .json
 // file from within the data directory.
 func (c *Config) parsePersistentNodes(path string) []*discover.Node {
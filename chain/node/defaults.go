@@ -21,9 +21,11 @@ import (
 	"os/user"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	"github.com/Tinachain/Tina/chain/p2p"
 	"github.com/Tinachain/Tina/chain/p2p/nat"
+	"github.com/Tinachain/Tina/chain/rpc"
 )
 
 const (
@@ -31,15 +33,31 @@ const (
 	DefaultHTTPPort = 8545        // Default TCP port for the HTTP RPC server
 	DefaultWSHost   = "localhost" // Default host interface for the websocket RPC server
 	DefaultWSPort   = 8546        // Default TCP port for the websocket RPC server
+
+	DefaultWSMaxSubscriptions         = 128  // Default subscription limit of a websocket connection
+	DefaultWSMaxBufferedNotifications = 4096 // Default notification queue limit of a websocket connection
+
+	DefaultWSHandshakeTimeout = 30 * time.Second // Default time allowed to complete a websocket handshake
+
+	DefaultMetricsHost = "localhost" // Default host interface for the metrics server
+	DefaultMetricsPort = 6061        // Default TCP port for the metrics server
 )
 
 // DefaultConfig contains reasonable default settings.
 var DefaultConfig = Config{
-	DataDir:     DefaultDataDir(),
-	HTTPPort:    DefaultHTTPPort,
-	HTTPModules: []string{"net", "web3"},
-	WSPort:      DefaultWSPort,
-	WSModules:   []string{"net", "web3"},
+	DataDir:                    DefaultDataDir(),
+	HTTPPort:                   DefaultHTTPPort,
+	HTTPModules:                []string{"net", "web3"},
+	HTTPVirtualHosts:           []string{"localhost"},
+	HTTPTimeouts:               rpc.DefaultHTTPTimeouts,
+	WSPort:                     DefaultWSPort,
+	WSModules:                  []string{"net", "web3"},
+	WSMaxSubscriptions:         DefaultWSMaxSubscriptions,
+	WSMaxBufferedNotifications: DefaultWSMaxBufferedNotifications,
+	WSHandshakeTimeout:         DefaultWSHandshakeTimeout,
+	AuditLog:                   "audit.log",
+	AuthModules:                []string{"admin", "debug", "miner", "personal"},
+	MetricsPort:                DefaultMetricsPort,
 	P2P: p2p.Config{
 		ListenAddr:      ":30303",
 		DiscoveryV5Addr: ":30304",
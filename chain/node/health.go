@@ -0,0 +1,82 @@
+package node
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// HealthChecker is implemented by services that are able to report whether they
+// are alive and whether they are ready to serve requests. The node aggregates the
+// reports of all such services on its /health and /ready HTTP endpoints, which
+// are meant to be polled by load balancers and container orchestrators.
+type HealthChecker interface {
+	// HealthStatus returns a JSON marshallable report of the service state.
+	HealthStatus() interface{}
+
+	// Healthy returns an error if the service stopped functioning altogether.
+	Healthy() error
+
+	// Ready returns an error if the service is running but should not receive
+	// any traffic yet (e.g. while the chain is still syncing).
+	Ready() error
+}
+
+// healthResponse is the JSON body returned by the health and readiness probes.
+type healthResponse struct {
+	Status   string                 `json:"status"`
+	Error    string                 `json:"error,omitempty"`
+	Services map[string]interface{} `json:"services,omitempty"`
+}
+
+// healthHandler wraps an HTTP handler, answering the /health and /ready probes
+// itself and passing every other request through to next.
+func (n *Node) healthHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			n.serveHealth(w, false)
+		case "/ready":
+			n.serveHealth(w, true)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// serveHealth writes the aggregated service report, failing with 503 if the node
+// is stopped or any service is unhealthy (or not ready, if requested).
+func (n *Node) serveHealth(w http.ResponseWriter, ready bool) {
+	n.lock.RLock()
+	running := n.server != nil
+	checkers := make(map[string]HealthChecker)
+	for kind, service := range n.services {
+		if checker, ok := service.(HealthChecker); ok {
+			checkers[kind.String()] = checker
+		}
+	}
+	n.lock.RUnlock()
+
+	resp := &healthResponse{Status: "ok", Services: make(map[string]interface{})}
+	if !running {
+		resp.Error = ErrNodeStopped.Error()
+	}
+	for name, checker := range checkers {
+		resp.Services[name] = checker.HealthStatus()
+		if resp.Error != "" {
+			continue
+		}
+		if err := checker.Healthy(); err != nil {
+			resp.Error = err.Error()
+		} else if ready {
+			if err := checker.Ready(); err != nil {
+				resp.Error = err.Error()
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if resp.Error != "" {
+		resp.Status = "unavailable"
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,63 @@
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// HealthService is a service reporting configurable health and readiness.
+type HealthService struct {
+	NoopService
+	healthy error
+	ready   error
+}
+
+func (s *HealthService) HealthStatus() interface{} { return "status" }
+func (s *HealthService) Healthy() error            { return s.healthy }
+func (s *HealthService) Ready() error              { return s.ready }
+
+// Tests that the health and readiness probes aggregate the service reports.
+func TestNodeHealthProbes(t *testing.T) {
+	stack, err := New(testNodeConfig())
+	if err != nil {
+		t.Fatalf("failed to create protocol stack: %v", err)
+	}
+	service := &HealthService{ready: errors.New("syncing")}
+	if err := stack.Register(func(*ServiceContext) (Service, error) { return service, nil }); err != nil {
+		t.Fatalf("failed to register health service: %v", err)
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) })
+	handler := stack.healthHandler(next)
+
+	probe := func(path string) (int, *healthResponse) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		resp := new(healthResponse)
+		json.Unmarshal(rec.Body.Bytes(), resp)
+		return rec.Code, resp
+	}
+	if code, resp := probe("/health"); code != http.StatusServiceUnavailable || resp.Error != ErrNodeStopped.Error() {
+		t.Fatalf("stopped node health mismatch: have %d %v", code, resp)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start protocol stack: %v", err)
+	}
+	defer stack.Stop()
+
+	if code, resp := probe("/health"); code != http.StatusOK || resp.Status != "ok" || len(resp.Services) != 1 {
+		t.Fatalf("health mismatch: have %d %v", code, resp)
+	}
+	if code, resp := probe("/ready"); code != http.StatusServiceUnavailable || resp.Error != "syncing" {
+		t.Fatalf("readiness mismatch: have %d %v", code, resp)
+	}
+	service.ready = nil
+	if code, _ := probe("/ready"); code != http.StatusOK {
+		t.Fatalf("readiness mismatch: have %d, want %d", code, http.StatusOK)
+	}
+	if code, _ := probe("/"); code != http.StatusTeapot {
+		t.Fatalf("passthrough mismatch: have %d, want %d", code, http.StatusTeapot)
+	}
+}
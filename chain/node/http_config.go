@@ -0,0 +1,69 @@
+package node
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/Tinachain/Tina/chain/log"
+)
+
+var errHTTPNotRunning = errors.New("HTTP RPC not running")
+
+// httpSwitch is the handler of the HTTP RPC listener, forwarding requests to the
+// currently configured RPC handler. It allows the allowed origins, virtual hosts
+// and modules to be changed without closing the listener.
+type httpSwitch struct {
+	handler http.Handler
+	lock    sync.RWMutex
+}
+
+// ServeHTTP implements http.Handler.
+func (s *httpSwitch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.RLock()
+	handler := s.handler
+	s.lock.RUnlock()
+
+	handler.ServeHTTP(w, r)
+}
+
+// swap replaces the handler serving the subsequent requests.
+func (s *httpSwitch) swap(handler http.Handler) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.handler = handler
+}
+
+// httpConfig returns the settings of the running HTTP RPC endpoint.
+func (n *Node) httpConfig() (*HTTPConfig, error) {
+	if n.httpHandler == nil {
+		return nil, errHTTPNotRunning
+	}
+	return &HTTPConfig{Cors: n.httpCors, VirtualHosts: n.httpVirtualHosts, Modules: n.httpWhitelist}, nil
+}
+
+// reconfigureHTTP changes the allowed origins, virtual hosts and modules of the
+// running HTTP RPC endpoint and persists them for subsequent restarts. The caller
+// must hold the node lock.
+func (n *Node) reconfigureHTTP(config *HTTPConfig) error {
+	if n.httpHandler == nil {
+		return errHTTPNotRunning
+	}
+	handler, err := n.newHTTPHandler(n.rpcAPIs, config.Modules)
+	if err != nil {
+		return err
+	}
+	n.httpSwitch.swap(n.newHTTPEndpointHandler(handler, config.Modules, config.Cors, config.VirtualHosts))
+	n.httpHandler.Stop()
+
+	n.httpHandler = handler
+	n.httpCors, n.httpVirtualHosts, n.httpWhitelist = config.Cors, config.VirtualHosts, config.Modules
+	n.config.HTTPCors, n.config.HTTPVirtualHosts, n.config.HTTPModules = config.Cors, config.VirtualHosts, config.Modules
+	if err := n.config.storeHTTPConfig(); err != nil {
+		log.Error("Failed to persist HTTP config", "err", err)
+		return err
+	}
+	log.Info("HTTP endpoint reconfigured", "cors", config.Cors, "vhosts", config.VirtualHosts, "modules", config.Modules)
+	return nil
+}
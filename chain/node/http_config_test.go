@@ -0,0 +1,142 @@
+package node
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+// Tests that the HTTP endpoint can be reconfigured at runtime and that the changes
+// are picked up again after a restart.
+func TestHTTPReconfiguration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "http-config-test")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := testNodeConfig()
+	config.DataDir = dir
+	config.HTTPHost = "127.0.0.1"
+	config.HTTPModules = []string{"eth"}
+	newStack := func() *Node {
+		stack, err := New(config)
+		if err != nil {
+			t.Fatalf("failed to create protocol stack: %v", err)
+		}
+		service := &InstrumentedService{apis: []rpc.API{
+			{Namespace: "eth", Version: "1.0", Service: new(AuditedService), Public: true},
+			{Namespace: "personal", Version: "1.0", Service: new(AuditedService)},
+		}}
+		if err := stack.Register(func(*ServiceContext) (Service, error) { return service, nil }); err != nil {
+			t.Fatalf("failed to register service: %v", err)
+		}
+		if err := stack.Start(); err != nil {
+			t.Fatalf("failed to start protocol stack: %v", err)
+		}
+		return stack
+	}
+	call := func(stack *Node, method string) error {
+		client, err := rpc.DialHTTP("http://" + stack.httpListener.Addr().String())
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		defer client.Close()
+
+		var accounts []string
+		return client.Call(&accounts, method)
+	}
+
+	stack := newStack()
+	if err := call(stack, "personal_listAccounts"); err == nil {
+		t.Fatalf("call to disabled module succeeded")
+	}
+	api := NewPrivateAdminAPI(stack)
+	if _, err := api.SetHTTPModules("eth, personal"); err != nil {
+		t.Fatalf("failed to set modules: %v", err)
+	}
+	if err := call(stack, "personal_listAccounts"); err != nil {
+		t.Fatalf("call to enabled module failed: %v", err)
+	}
+	if _, err := api.SetHTTPVirtualHosts("example.com"); err != nil {
+		t.Fatalf("failed to set virtual hosts: %v", err)
+	}
+	req, _ := http.NewRequest("POST", "http://"+stack.httpListener.Addr().String(), strings.NewReader("{}"))
+	req.Host = "localhost"
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("vhost status mismatch: have %d, want %d", resp.StatusCode, http.StatusForbidden)
+	}
+	stack.Stop()
+
+	//重启后应加载运行时修改的配置
+	stack = newStack()
+	defer stack.Stop()
+
+	have, err := NewPrivateAdminAPI(stack).HTTPConfig()
+	if err != nil {
+		t.Fatalf("failed to get HTTP config: %v", err)
+	}
+	want := &HTTPConfig{VirtualHosts: []string{"example.com"}, Modules: []string{"eth", "personal"}}
+	if !reflect.DeepEqual(have, want) {
+		t.Fatalf("HTTP config mismatch: have %+v, want %+v", have, want)
+	}
+}
+
+// Tests that the privileged modules are never offered over HTTP if they are
+// restricted to IPC, even if whitelisted.
+func TestIPCOnlyPrivileged(t *testing.T) {
+	config := testNodeConfig()
+	config.HTTPHost = "127.0.0.1"
+	config.HTTPModules = []string{"eth", "personal"}
+	config.IPCOnlyPrivileged = true
+
+	stack, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create protocol stack: %v", err)
+	}
+	service := &InstrumentedService{apis: []rpc.API{
+		{Namespace: "eth", Version: "1.0", Service: new(AuditedService), Public: true},
+		{Namespace: "personal", Version: "1.0", Service: new(AuditedService)},
+	}}
+	if err := stack.Register(func(*ServiceContext) (Service, error) { return service, nil }); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start protocol stack: %v", err)
+	}
+	defer stack.Stop()
+
+	client, err := rpc.DialHTTP("http://" + stack.httpListener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	var accounts []string
+	if err := client.Call(&accounts, "eth_listAccounts"); err != nil {
+		t.Fatalf("public call failed: %v", err)
+	}
+	if err := client.Call(&accounts, "personal_listAccounts"); err == nil {
+		t.Fatalf("privileged call succeeded over HTTP")
+	}
+	if err := stack.httpHandler.RegisterName("personal", new(AuditedService)); err == nil {
+		t.Fatalf("privileged module registered on HTTP server")
+	}
+	//进程内连接不受限制
+	inproc, _ := stack.Attach()
+	defer inproc.Close()
+	if err := inproc.Call(&accounts, "personal_listAccounts"); err != nil {
+		t.Fatalf("in-process privileged call failed: %v", err)
+	}
+}
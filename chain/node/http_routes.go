@@ -0,0 +1,78 @@
+package node
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+// HTTPRoute is a raw (non JSON-RPC) HTTP resource served by a service on the HTTP
+// RPC endpoint. Requests are only routed to it if its namespace is explicitly
+// enabled on the endpoint, and they are subject to the same IPC-only and token
+// restrictions as the RPC methods of that namespace.
+type HTTPRoute struct {
+	Namespace string       // RPC namespace guarding the route (e.g. "debug")
+	Pattern   string       // http.ServeMux pattern of the route (e.g. "/debug/block/")
+	Handler   http.Handler // Handler serving the matching requests
+}
+
+// HTTPRouter is implemented by services exposing raw HTTP resources, such as
+// binary downloads that would be wasteful to hex encode into JSON-RPC responses.
+type HTTPRouter interface {
+	HTTPRoutes() []HTTPRoute
+}
+
+// serviceRoutes gathers the HTTP routes of all the services, in a stable order.
+func serviceRoutes(services map[reflect.Type]Service) []HTTPRoute {
+	var routes []HTTPRoute
+	for _, service := range services {
+		if router, ok := service.(HTTPRouter); ok {
+			routes = append(routes, router.HTTPRoutes()...)
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Pattern < routes[j].Pattern })
+	return routes
+}
+
+// newHTTPEndpointHandler wraps the HTTP RPC server into the handler of the endpoint,
+// mounting the service routes whose namespace is enabled through modules.
+func (n *Node) newHTTPEndpointHandler(srv *rpc.Server, modules []string, cors []string, vhosts []string) http.Handler {
+	handler := rpc.NewHTTPHandler(cors, vhosts, srv)
+
+	whitelist := make(map[string]bool)
+	for _, module := range modules {
+		whitelist[module] = true
+	}
+	var mux *http.ServeMux
+	for _, route := range n.httpRoutes {
+		if !whitelist[route.Namespace] || n.config.ipcOnly(route.Namespace) {
+			continue
+		}
+		if mux == nil {
+			mux = http.NewServeMux()
+			mux.Handle("/", handler)
+		}
+		mux.Handle(route.Pattern, rpc.NewVirtualHostHandler(vhosts, n.authorizeRoute(route)))
+	}
+	if mux == nil {
+		return handler
+	}
+	return mux
+}
+
+// authorizeRoute rejects the requests to a route lacking a token for its namespace,
+// if authentication is enabled.
+func (n *Node) authorizeRoute(route HTTPRoute) http.Handler {
+	auth := n.rpcAuth
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth != nil {
+			if err := auth.Authorize(rpc.BearerToken(r.Header.Get("Authorization")), route.Namespace); err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+		route.Handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,67 @@
+package node
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+)
+
+// routedService is a service exposing a raw HTTP resource in the debug namespace.
+type routedService struct{ NoopService }
+
+func (s *routedService) HTTPRoutes() []HTTPRoute {
+	return []HTTPRoute{{
+		Namespace: "debug",
+		Pattern:   "/debug/ping",
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("pong"))
+		}),
+	}}
+}
+
+// Tests that service routes are only mounted on the HTTP endpoint if their
+// namespace is enabled, and that they are dropped again when it is disabled.
+func TestHTTPServiceRoutes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "http-routes-test")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := testNodeConfig()
+	config.DataDir = dir
+	config.HTTPHost = "127.0.0.1"
+	config.HTTPModules = []string{"eth", "debug"}
+
+	stack, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create protocol stack: %v", err)
+	}
+	if err := stack.Register(func(*ServiceContext) (Service, error) { return new(routedService), nil }); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start protocol stack: %v", err)
+	}
+	defer stack.Stop()
+
+	get := func() (int, string) {
+		resp, err := http.Get("http://" + stack.httpListener.Addr().String() + "/debug/ping")
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+	if status, body := get(); status != http.StatusOK || body != "pong" {
+		t.Fatalf("enabled route mismatch: have %d %q, want %d %q", status, body, http.StatusOK, "pong")
+	}
+	if _, err := NewPrivateAdminAPI(stack).SetHTTPModules("eth"); err != nil {
+		t.Fatalf("failed to set modules: %v", err)
+	}
+	if _, body := get(); body == "pong" {
+		t.Fatalf("disabled route still served")
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -31,9 +32,11 @@ import (
 	"github.com/Tinachain/Tina/chain/event"
 	"github.com/Tinachain/Tina/chain/internal/debug"
 	"github.com/Tinachain/Tina/chain/log"
+	"github.com/Tinachain/Tina/chain/metrics"
 	"github.com/Tinachain/Tina/chain/p2p"
 	"github.com/Tinachain/Tina/chain/rpc"
 	"github.com/prometheus/prometheus/util/flock"
+	gometrics "github.com/rcrowley/go-metrics"
 )
 
 // Node is a container on which services can be registered.
@@ -58,15 +61,27 @@ type Node struct {
 	ipcListener net.Listener // IPC RPC listener socket to serve API requests
 	ipcHandler  *rpc.Server  // IPC RPC request handler to process the API requests
 
-	httpEndpoint  string       // HTTP endpoint (interface + port) to listen at (empty = HTTP disabled)
-	httpWhitelist []string     // HTTP RPC modules to allow through this endpoint
-	httpListener  net.Listener // HTTP RPC listener socket to server API requests
-	httpHandler   *rpc.Server  // HTTP RPC request handler to process the API requests
+	httpEndpoint     string       // HTTP endpoint (interface + port) to listen at (empty = HTTP disabled)
+	httpWhitelist    []string     // HTTP RPC modules to allow through this endpoint
+	httpCors         []string     // HTTP RPC cross-origin domains allowed through this endpoint
+	httpVirtualHosts []string     // HTTP RPC virtual hosts allowed through this endpoint
+	httpListener     net.Listener // HTTP RPC listener socket to server API requests
+	httpHandler      *rpc.Server  // HTTP RPC request handler to process the API requests
+	httpSwitch       *httpSwitch  // HTTP handler of the listener, swapped on runtime reconfiguration
+	httpRoutes       []HTTPRoute  // Raw HTTP resources of the services, mounted next to the RPC handler
 
 	wsEndpoint string       // Websocket endpoint (interface + port) to listen at (empty = websocket disabled)
 	wsListener net.Listener // Websocket RPC listener socket to server API requests
 	wsHandler  *rpc.Server  // Websocket RPC request handler to process the API requests
 
+	auditLog *auditLog // Append-only log of the privileged RPC calls (nil = auditing disabled)
+	rpcAuth  *rpcAuth  // Token authorizer of the HTTP and websocket endpoints (nil = authentication disabled)
+
+	rpcStats *rpc.UsageStats // Per method usage accumulated over all RPC endpoints
+
+	metricsEndpoint string       // Metrics endpoint (interface + port) to listen at (empty = metrics disabled)
+	metricsListener net.Listener // Metrics HTTP listener socket serving the Prometheus exposition
+
 	stop chan struct{} // Channel to wait for termination notifications
 	lock sync.RWMutex
 }
@@ -112,6 +127,7 @@ func New(conf *Config) (*Node, error) {
 		httpEndpoint:      conf.HTTPEndpoint(),
 		wsEndpoint:        conf.WSEndpoint(),
 		eventmux:          new(event.TypeMux),
+		rpcStats:          rpc.NewUsageStats(),
 	}, nil
 }
 
@@ -141,6 +157,8 @@ func (n *Node) Start() error {
 	if err := n.openDataDir(); err != nil {
 		return err
 	}
+	//加载运行时修改过的HTTP接口配置
+	n.config.loadHTTPConfig()
 
 	//初始化p2p服务器，创建节点密钥和发现数据库。
 	n.serverConfig = n.config.P2P
@@ -246,6 +264,20 @@ func (n *Node) Start() error {
 	}
 	log.Info("(n *Node) Start RPC")
 
+	//启动Prometheus监控数据的HTTP服务
+	if err := n.startMetrics(n.config.MetricsEndpoint()); err != nil {
+		n.stopWS()
+		n.stopHTTP()
+		n.stopIPC()
+		n.stopInProc()
+		n.stopAudit()
+		for _, service := range services {
+			service.Stop()
+		}
+		running.Stop()
+		return err
+	}
+
 	//完成启动的初始化工作
 	n.services = services
 	n.server = running
@@ -283,21 +315,34 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 	for _, service := range services {
 		apis = append(apis, service.APIs()...)
 	}
+	n.httpRoutes = serviceRoutes(services)
+
+	//加载HTTP以及Websocket接口的认证密钥
+	if err := n.startAuth(); err != nil {
+		return err
+	}
+	//打开特权接口调用的审计日志
+	if err := n.startAudit(); err != nil {
+		return err
+	}
 	// Start the various API endpoints, terminating all in case of errors
 	if err := n.startInProc(apis); err != nil {
+		n.stopAudit()
 		return err
 	}
 
 	//log.Info("startIPC", "apis", apis)
 	if err := n.startIPC(apis); err != nil {
 		n.stopInProc()
+		n.stopAudit()
 		return err
 	}
 
 	//log.Info("startHTTP", "httpEndpoint", n.httpEndpoint, "apis", apis, "HTTPModules", n.config.HTTPModules, "HTTPCors", n.config.HTTPCors)
-	if err := n.startHTTP(n.httpEndpoint, apis, n.config.HTTPModules, n.config.HTTPCors); err != nil {
+	if err := n.startHTTP(n.httpEndpoint, apis, n.config.HTTPModules, n.config.HTTPCors, n.config.HTTPVirtualHosts); err != nil {
 		n.stopIPC()
 		n.stopInProc()
+		n.stopAudit()
 		return err
 	}
 
@@ -306,6 +351,7 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 		n.stopHTTP()
 		n.stopIPC()
 		n.stopInProc()
+		n.stopAudit()
 		return err
 	}
 	// All API endpoints started successfully
@@ -313,10 +359,79 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 	return nil
 }
 
+// startAuth loads the secret verifying the tokens of the HTTP and websocket RPC
+// clients, if authentication is enabled.
+func (n *Node) startAuth() error {
+	n.rpcAuth = nil
+	if n.config.AuthSecret == "" {
+		return nil
+	}
+	path := n.config.resolvePath(n.config.AuthSecret)
+	if path == "" {
+		return fmt.Errorf("authentication secret %q requires a data directory", n.config.AuthSecret)
+	}
+	secret, err := loadAuthSecret(path)
+	if err != nil {
+		return err
+	}
+	n.rpcAuth = newRPCAuth(secret, n.config.AuthModules)
+	log.Info("RPC authentication enabled", "modules", strings.Join(n.config.AuthModules, ","))
+	return nil
+}
+
+// startAudit opens the audit log recording the privileged RPC calls, if enabled.
+func (n *Node) startAudit() error {
+	path := n.config.AuditLogPath()
+	if path == "" {
+		return nil
+	}
+	audit, err := openAuditLog(path)
+	if err != nil {
+		return err
+	}
+	n.auditLog = audit
+	log.Info("Audit log opened", "path", path)
+	return nil
+}
+
+// stopAudit closes the audit log.
+func (n *Node) stopAudit() {
+	if n.auditLog != nil {
+		if err := n.auditLog.Close(); err != nil {
+			log.Error("Failed to close audit log", "err", err)
+		}
+		n.auditLog = nil
+	}
+}
+
+// newRPCServer creates an RPC server recording privileged calls in the audit log
+// and accounting all calls in the node wide usage stats.
+func (n *Node) newRPCServer() *rpc.Server {
+	handler := rpc.NewServer()
+	handler.SetUsageStats(n.rpcStats)
+	if n.auditLog != nil {
+		handler.SetAuditor(n.auditLog)
+	}
+	return handler
+}
+
+// newRemoteRPCServer creates an RPC server for the network reachable (HTTP and
+// websocket) endpoints, applying the authentication and IPC-only restrictions.
+func (n *Node) newRemoteRPCServer() *rpc.Server {
+	handler := n.newRPCServer()
+	if n.rpcAuth != nil {
+		handler.SetAuthorizer(n.rpcAuth)
+	}
+	if n.config.IPCOnlyPrivileged {
+		handler.DenyNamespaces(ipcOnlyModules)
+	}
+	return handler
+}
+
 // startInProc initializes an in-process RPC endpoint.
 func (n *Node) startInProc(apis []rpc.API) error {
 	// Register all the APIs exposed by the services
-	handler := rpc.NewServer()
+	handler := n.newRPCServer()
 	for _, api := range apis {
 		if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
 			return err
@@ -342,7 +457,7 @@ func (n *Node) startIPC(apis []rpc.API) error {
 		return nil
 	}
 	// Register all the APIs exposed by the services
-	handler := rpc.NewServer()
+	handler := n.newRPCServer()
 	for _, api := range apis {
 		if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
 			return err
@@ -399,45 +514,59 @@ func (n *Node) stopIPC() {
 }
 
 // startHTTP initializes and starts the HTTP RPC endpoint.
-func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors []string) error {
+func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors []string, vhosts []string) error {
 	// Short circuit if the HTTP endpoint isn't being exposed
 	if endpoint == "" {
 		return nil
 	}
-	// Generate the whitelist based on the allowed modules
-	whitelist := make(map[string]bool)
-	for _, module := range modules {
-		whitelist[module] = true
-	}
 	// Register all the APIs exposed by the services
-	handler := rpc.NewServer()
-	for _, api := range apis {
-		if whitelist[api.Namespace] || (len(whitelist) == 0 && api.Public) {
-			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
-				return err
-			}
-			log.Debug(fmt.Sprintf("HTTP registered %T under '%s'", api.Service, api.Namespace))
-		}
+	handler, err := n.newHTTPHandler(apis, modules)
+	if err != nil {
+		return err
 	}
 	// All APIs registered, start the HTTP listener
-	var (
-		listener net.Listener
-		err      error
-	)
-	if listener, err = net.Listen("tcp", endpoint); err != nil {
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
 		return err
 	}
-	go rpc.NewHTTPServer(cors, handler).Serve(listener)
+	n.httpSwitch = &httpSwitch{handler: n.newHTTPEndpointHandler(handler, modules, cors, vhosts)}
+	go rpc.NewHTTPServerWithTimeouts(n.healthHandler(n.httpSwitch), n.config.HTTPTimeouts).Serve(listener)
 	log.Info(fmt.Sprintf("HTTP endpoint opened: http://%s", endpoint))
 
 	// All listeners booted successfully
 	n.httpEndpoint = endpoint
+	n.httpWhitelist = modules
+	n.httpCors = cors
+	n.httpVirtualHosts = vhosts
 	n.httpListener = listener
 	n.httpHandler = handler
 
 	return nil
 }
 
+// newHTTPHandler creates the RPC server of the HTTP endpoint, registering the APIs
+// allowed through the modules whitelist.
+func (n *Node) newHTTPHandler(apis []rpc.API, modules []string) (*rpc.Server, error) {
+	// Generate the whitelist based on the allowed modules
+	whitelist := make(map[string]bool)
+	for _, module := range modules {
+		whitelist[module] = true
+	}
+	handler := n.newRemoteRPCServer()
+	for _, api := range apis {
+		if n.config.ipcOnly(api.Namespace) {
+			continue
+		}
+		if whitelist[api.Namespace] || (len(whitelist) == 0 && api.Public) {
+			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
+				return nil, err
+			}
+			log.Debug(fmt.Sprintf("HTTP registered %T under '%s'", api.Service, api.Namespace))
+		}
+	}
+	return handler, nil
+}
+
 // stopHTTP terminates the HTTP RPC endpoint.
 func (n *Node) stopHTTP() {
 	if n.httpListener != nil {
@@ -449,6 +578,7 @@ func (n *Node) stopHTTP() {
 	if n.httpHandler != nil {
 		n.httpHandler.Stop()
 		n.httpHandler = nil
+		n.httpSwitch = nil
 	}
 }
 
@@ -464,8 +594,12 @@ func (n *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrig
 		whitelist[module] = true
 	}
 	// Register all the APIs exposed by the services
-	handler := rpc.NewServer()
+	handler := n.newRemoteRPCServer()
+	handler.SetSubscriptionLimits(n.config.WSMaxSubscriptions, n.config.WSMaxBufferedNotifications)
 	for _, api := range apis {
+		if n.config.ipcOnly(api.Namespace) {
+			continue
+		}
 		if exposeAll || whitelist[api.Namespace] || (len(whitelist) == 0 && api.Public) {
 			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
 				return err
@@ -481,7 +615,7 @@ func (n *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrig
 	if listener, err = net.Listen("tcp", endpoint); err != nil {
 		return err
 	}
-	go rpc.NewWSServer(wsOrigins, handler).Serve(listener)
+	go rpc.NewWSServerWithTimeout(wsOrigins, n.config.WSHandshakeTimeout, handler).Serve(listener)
 	log.Info(fmt.Sprintf("WebSocket endpoint opened: ws://%s", listener.Addr()))
 
 	// All listeners booted successfully
@@ -506,6 +640,40 @@ func (n *Node) stopWS() {
 	}
 }
 
+// startMetrics starts the HTTP server exposing the collected metrics in the
+// Prometheus text format under /metrics, along with the /health and /ready probes.
+func (n *Node) startMetrics(endpoint string) error {
+	// Short circuit if the metrics endpoint isn't being exposed
+	if endpoint == "" {
+		return nil
+	}
+	if !metrics.Enabled {
+		log.Warn("Metrics endpoint opened without metrics collection enabled", "endpoint", endpoint)
+	}
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.PrometheusHandler(gometrics.DefaultRegistry))
+	go http.Serve(listener, n.healthHandler(mux))
+	log.Info(fmt.Sprintf("Metrics endpoint opened: http://%s/metrics", endpoint))
+
+	n.metricsEndpoint = endpoint
+	n.metricsListener = listener
+	return nil
+}
+
+// stopMetrics terminates the metrics HTTP endpoint.
+func (n *Node) stopMetrics() {
+	if n.metricsListener != nil {
+		n.metricsListener.Close()
+		n.metricsListener = nil
+
+		log.Info(fmt.Sprintf("Metrics endpoint closed: http://%s/metrics", n.metricsEndpoint))
+	}
+}
+
 // Stop terminates a running node along with all it's services. In the node was
 // not started, an error is returned.
 func (n *Node) Stop() error {
@@ -518,9 +686,11 @@ func (n *Node) Stop() error {
 	}
 
 	// Terminate the API, services and the p2p server.
+	n.stopMetrics()
 	n.stopWS()
 	n.stopHTTP()
 	n.stopIPC()
+	n.stopAudit()
 	n.rpcAPIs = nil
 	failure := &StopError{
 		Services: make(map[reflect.Type]error),
@@ -29,6 +29,7 @@ var (
 	ingressTrafficMeter = metrics.NewMeter("p2p/InboundTraffic")
 	egressConnectMeter  = metrics.NewMeter("p2p/OutboundConnects")
 	egressTrafficMeter  = metrics.NewMeter("p2p/OutboundTraffic")
+	peersGauge          = metrics.NewGauge("p2p/peers")
 )
 
 // meteredConn is a wrapper around a network TCP connection that meters both the
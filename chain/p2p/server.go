@@ -200,6 +200,8 @@ type conn struct {
 	id    discover.NodeID // valid after the encryption handshake
 	caps  []Cap           // valid after the protocol handshake
 	name  string          // valid after the protocol handshake
+
+	snappy bool // whether messages are snappy compressed, valid after the protocol handshake
 }
 
 type transport interface {
@@ -569,6 +571,7 @@ running:
 				name := truncateName(c.name)
 				log.Debug("Adding p2p peer", "id", c.id, "name", name, "addr", c.fd.RemoteAddr(), "peers", len(peers)+1)
 				peers[c.id] = p
+				peersGauge.Update(int64(len(peers)))
 				go srv.runPeer(p)
 			}
 			// The dialer logic relies on the assumption that
@@ -584,6 +587,7 @@ running:
 			d := common.PrettyDuration(mclock.Now() - pd.created)
 			pd.log.Debug("Removing p2p peer", "duration", d, "peers", len(peers)-1, "req", pd.requested, "err", pd.err)
 			delete(peers, pd.ID())
+			peersGauge.Update(int64(len(peers)))
 		}
 	}
 
@@ -742,6 +746,10 @@ func (srv *Server) SetupConn(fd net.Conn, flags connFlag, dialDest *discover.Nod
 		return
 	}
 	c.caps, c.name = phs.Caps, phs.Name
+	c.snappy = phs.Version >= snappyProtocolVersion
+	if !c.snappy {
+		clog.Debug("Peer does not support compressed messages", "version", phs.Version)
+	}
 	if err := srv.checkpoint(c, srv.addpeer); err != nil {
 		clog.Trace("Rejected peer", "err", err)
 		c.close(err)
@@ -494,3 +494,50 @@ func randomID() (id discover.NodeID) {
 	}
 	return id
 }
+
+type versionTransport struct {
+	*testTransport
+	version uint64
+}
+
+func (c *versionTransport) doProtoHandshake(our *protoHandshake) (*protoHandshake, error) {
+	return &protoHandshake{Version: c.version, ID: c.id, Name: "test"}, nil
+}
+
+// Tests that peers report whether snappy compression was negotiated in the
+// protocol handshake.
+func TestServerPeerCompression(t *testing.T) {
+	for _, version := range []uint64{baseProtocolVersion - 1, baseProtocolVersion} {
+		connected := make(chan *Peer, 1)
+		remid := randomID()
+		srv := &Server{
+			Config: Config{
+				Name:       "test",
+				MaxPeers:   10,
+				ListenAddr: "127.0.0.1:0",
+				PrivateKey: newkey(),
+			},
+			newPeerHook: func(p *Peer) { connected <- p },
+			newTransport: func(fd net.Conn) transport {
+				return &versionTransport{testTransport: newTestTransport(remid, fd).(*testTransport), version: version}
+			},
+		}
+		if err := srv.Start(); err != nil {
+			t.Fatalf("could not start server: %v", err)
+		}
+		conn, err := net.DialTimeout("tcp", srv.ListenAddr, 5*time.Second)
+		if err != nil {
+			t.Fatalf("could not dial: %v", err)
+		}
+		select {
+		case peer := <-connected:
+			if have, want := peer.Info().Network.Compressed, version >= snappyProtocolVersion; have != want {
+				t.Errorf("version %d: compression mismatch: have %v, want %v", version, have, want)
+			}
+		case <-time.After(1 * time.Second):
+			t.Errorf("version %d: server did not accept within one second", version)
+		}
+		conn.Close()
+		srv.Stop()
+	}
+}
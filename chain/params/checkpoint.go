@@ -0,0 +1,55 @@
+package params
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Tinachain/Tina/chain/common"
+)
+
+// TrustedCheckpoint represents a set of post-processed trie roots (CHT and BloomTrie) associated with
+// the appropriate section index and head hash. It is used to start light syncing from this checkpoint
+// and avoid downloading the entire header chain while still being able to securely access old headers/logs.
+//
+// No checkpoint is shipped for the Tina networks, light clients sync all headers from
+// genesis unless a checkpoint is configured (--lightcheckpoint).
+type TrustedCheckpoint struct {
+	Name          string      `toml:",omitempty"`
+	SectionIndex  uint64      //LES/2 CHT分段的索引
+	SectionHead   common.Hash //分段最后一个区块的哈希
+	CHTRoot       common.Hash //CHT的树根
+	BloomTrieRoot common.Hash //BloomTrie的树根
+}
+
+var errInvalidCheckpoint = errors.New("invalid checkpoint, want <index>:<sectionHead>:<chtRoot>:<bloomTrieRoot>")
+
+// ParseTrustedCheckpoint parses a checkpoint given in the
+// <index>:<sectionHead>:<chtRoot>:<bloomTrieRoot> format.
+func ParseTrustedCheckpoint(s string) (*TrustedCheckpoint, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 4 {
+		return nil, errInvalidCheckpoint
+	}
+	index, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, errInvalidCheckpoint
+	}
+	hashes := make([]common.Hash, 3)
+	for i, part := range parts[1:] {
+		raw, err := hex.DecodeString(strings.TrimPrefix(part, "0x"))
+		if err != nil || len(raw) != common.HashLength {
+			return nil, errInvalidCheckpoint
+		}
+		hashes[i] = common.BytesToHash(raw)
+	}
+	return &TrustedCheckpoint{
+		Name:          fmt.Sprintf("custom #%d", index),
+		SectionIndex:  index,
+		SectionHead:   hashes[0],
+		CHTRoot:       hashes[1],
+		BloomTrieRoot: hashes[2],
+	}, nil
+}
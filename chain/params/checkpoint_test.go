@@ -0,0 +1,38 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/common"
+)
+
+func TestParseTrustedCheckpoint(t *testing.T) {
+	cp := &TrustedCheckpoint{
+		SectionIndex:  129,
+		SectionHead:   common.HexToHash("64100587c8ec9a76870056d07cb0f58622552d16de6253a59cac4b580c899501"),
+		CHTRoot:       common.HexToHash("bb4fb4076cbe6923c8a8ce8f158452bbe19564959313466989fda095a60884ca"),
+		BloomTrieRoot: common.HexToHash("0db524b2c4a2a9520a42fd842b02d2e8fb58ff37c75cf57bd0eb82daeace6716"),
+	}
+	spec := "129:" + cp.SectionHead.Hex() + ":" + cp.CHTRoot.Hex() + ":" + cp.BloomTrieRoot.Hex()
+
+	parsed, err := ParseTrustedCheckpoint(spec)
+	if err != nil {
+		t.Fatalf("failed to parse checkpoint: %v", err)
+	}
+	if parsed.SectionIndex != cp.SectionIndex || parsed.SectionHead != cp.SectionHead || parsed.CHTRoot != cp.CHTRoot || parsed.BloomTrieRoot != cp.BloomTrieRoot {
+		t.Errorf("checkpoint mismatch: have %+v, want %+v", parsed, cp)
+	}
+
+	invalid := []string{
+		"",
+		"129",
+		"x:" + cp.SectionHead.Hex() + ":" + cp.CHTRoot.Hex() + ":" + cp.BloomTrieRoot.Hex(),
+		"129:0x1234:" + cp.CHTRoot.Hex() + ":" + cp.BloomTrieRoot.Hex(),
+		"129:" + cp.SectionHead.Hex() + ":" + cp.CHTRoot.Hex(),
+	}
+	for _, spec := range invalid {
+		if _, err := ParseTrustedCheckpoint(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
@@ -36,7 +36,30 @@ var (
 		big.NewInt(0),
 		big.NewInt(0),
 		big.NewInt(0),
-		common.Address{}}
+		common.Address{},
+		nil,
+		nil,
+		nil,
+		0,
+		false,
+		nil,
+		nil,
+		0,
+		0,
+		0,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil}
 
 	AllEthashProtocolChanges = &ChainConfig{
 		big.NewInt(1337),
@@ -48,7 +71,30 @@ var (
 		big.NewInt(0),
 		big.NewInt(0),
 		big.NewInt(0),
-		common.Address{}}
+		common.Address{},
+		nil,
+		nil,
+		nil,
+		0,
+		false,
+		nil,
+		nil,
+		0,
+		0,
+		0,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil}
 
 	AllCliqueProtocolChanges = &ChainConfig{
 		big.NewInt(1337),
@@ -60,10 +106,33 @@ var (
 		big.NewInt(0),
 		big.NewInt(0),
 		big.NewInt(0),
-		common.Address{}}
+		common.Address{},
+		nil,
+		nil,
+		nil,
+		0,
+		false,
+		nil,
+		nil,
+		0,
+		0,
+		0,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil}
 )
 
-//ChainConfig是确定区块链设置的核心配置,ChainConfig基于每个块存储在数据库中。
+// ChainConfig是确定区块链设置的核心配置,ChainConfig基于每个块存储在数据库中。
 //意即由其创世块标识的任何网络都可以拥有自己的网络一组配置选项。
 type ChainConfig struct {
 	ChainId        *big.Int       `json:"chainId"`                  //Chain id identifies the current chain and is used for replay protection
@@ -76,6 +145,33 @@ type ChainConfig struct {
 	EIP158Block    *big.Int       `json:"eip158Block,omitempty"`    //EIP158 HF block
 	ByzantiumBlock *big.Int       `json:"byzantiumBlock,omitempty"` //Byzantium switch block (nil = no fork, 0 = already on byzantium)
 	Coinbase       common.Address `json:"coinbase,omitempty"`       //Tina链新增当前挖矿的账号
+	Rewards        *RewardConfig  `json:"rewards,omitempty"`        //出块奖励计划（nil则使用默认奖励计划）
+	WasmBlock      *big.Int       `json:"wasmBlock,omitempty"`      //开启实验性WASM合约的区块（nil则不开启）
+	GasSchedules   []*GasSchedule `json:"gasSchedules,omitempty"`   //虚拟机Gas价格表的升级计划（按区块递增）
+
+	MaxCodeSize     uint64 `json:"maxCodeSize,omitempty"`     //合约代码的最大字节数（0则使用params.MaxCodeSize）
+	DeployWhitelist bool   `json:"deployWhitelist,omitempty"` //是否只允许白名单中的账号部署合约
+
+	VoteWeight *VoteWeightConfig `json:"voteWeight,omitempty"` //验证者选举投票权重的计算方式（nil则由投票合约计票）
+	Governance *GovernanceConfig `json:"governance,omitempty"` //链上治理提案的分叉以及计票规则（nil则不开启）
+
+	MaxWordSize       uint64 `json:"maxWordSize,omitempty"`       //Word扩展交易携带数据的最大字节数（0则使用params.MaxWordSize）
+	MaxDataSize       uint64 `json:"maxDataSize,omitempty"`       //Data扩展交易携带数据的最大字节数（0则使用params.MaxDataSize）
+	MaxBlockExtraSize uint64 `json:"maxBlockExtraSize,omitempty"` //每个区块中扩展交易携带数据累计的最大字节数（0则使用params.MaxBlockExtraSize）
+
+	RewardScheduleBlock  *big.Int `json:"rewardScheduleBlock,omitempty"`  //开始接受调整出块奖励计划交易的区块（nil则不接受）
+	BokerPrecompileBlock *big.Int `json:"bokerPrecompileBlock,omitempty"` //开启读取Dpos以及Tina链上下文的预编译合约的区块（nil则不开启）
+	UserContractBlock    *big.Int `json:"userContractBlock,omitempty"`    //用户基础合约的设置以及取消交易开始生效的区块（nil则保持原有处理）
+	SystemContractBlock  *big.Int `json:"systemContractBlock,omitempty"`  //系统基础合约变更需要授权以及延迟确认的区块（nil则保持原有处理）
+	DeployControlBlock   *big.Int `json:"deployControlBlock,omitempty"`   //开始使用maxCodeSize以及部署白名单的区块（nil则保持原有限制）
+	ExtraLimitBlock      *big.Int `json:"extraLimitBlock,omitempty"`      //区块校验开始检查扩展交易数据累计上限的区块（nil则不检查）
+	SponsorBlock         *big.Int `json:"sponsorBlock,omitempty"`         //次要类型为Sponsored的普通交易开始由代付账号支付Gas的区块（nil则按普通交易处理）
+	ContractMetaBlock    *big.Int `json:"contractMetaBlock,omitempty"`    //次要类型为ContractMeta的扩展交易开始登记合约元数据的区块（nil则按调用合约处理）
+	SigningKeyBlock      *big.Int `json:"signingKeyBlock,omitempty"`      //开始接受验证者设置出块签名账号交易的区块（nil则不接受）
+	BridgeBlock          *big.Int `json:"bridgeBlock,omitempty"`          //开始接受跨链锁定以及释放交易的区块（nil则不接受）
+	MultiSendBlock       *big.Int `json:"multiSendBlock,omitempty"`       //开始接受批量转账交易的区块（nil则不接受）
+	ValidatorInfoBlock   *big.Int `json:"validatorInfoBlock,omitempty"`   //开始接受登记验证者运营信息交易的区块（nil则不接受）
+	GasPoolFundBlock     *big.Int `json:"gasPoolFundBlock,omitempty"`     //开始接受向股权Gas池注资交易的区块（nil则不接受）
 }
 
 // CliqueConfig is the consensus engine configs for proof-of-authority based sealing.
@@ -89,7 +185,7 @@ func (c *CliqueConfig) String() string {
 	return "clique"
 }
 
-//DPOS共识机制中的配置信息.
+// DPOS共识机制中的配置信息.
 type DposConfig struct {
 	Validators []common.Address `json:"validators"` //初始化时的验证者帐号信息
 }
@@ -139,6 +235,108 @@ func (c *ChainConfig) IsByzantium(num *big.Int) bool {
 	return isForked(c.ByzantiumBlock, num)
 }
 
+//合约代码的最大字节数（分叉之前使用params.MaxCodeSize）
+func (c *ChainConfig) CodeSizeLimit(num *big.Int) int {
+	if c.MaxCodeSize == 0 || !c.IsDeployControl(num) {
+		return MaxCodeSize
+	}
+	return int(c.MaxCodeSize)
+}
+
+//是否已经开始使用配置的合约代码大小限制以及部署白名单
+func (c *ChainConfig) IsDeployControl(num *big.Int) bool {
+	return isForked(c.DeployControlBlock, num)
+}
+
+//是否只允许白名单中的账号部署合约
+func (c *ChainConfig) IsDeployWhitelist(num *big.Int) bool {
+	return c.DeployWhitelist && c.IsDeployControl(num)
+}
+
+// Word扩展交易携带数据的最大字节数
+func (c *ChainConfig) WordSizeLimit() uint64 {
+	if c.MaxWordSize == 0 {
+		return MaxWordSize
+	}
+	return c.MaxWordSize
+}
+
+// Data扩展交易携带数据的最大字节数
+func (c *ChainConfig) DataSizeLimit() uint64 {
+	if c.MaxDataSize == 0 {
+		return MaxDataSize
+	}
+	return c.MaxDataSize
+}
+
+//每个区块中扩展交易携带数据累计的最大字节数
+func (c *ChainConfig) BlockExtraLimit() uint64 {
+	if c.MaxBlockExtraSize == 0 {
+		return MaxBlockExtraSize
+	}
+	return c.MaxBlockExtraSize
+}
+
+//区块校验是否检查扩展交易携带数据累计的上限
+func (c *ChainConfig) IsExtraLimit(num *big.Int) bool {
+	return isForked(c.ExtraLimitBlock, num)
+}
+
+//是否已经开启WASM合约
+func (c *ChainConfig) IsWasm(num *big.Int) bool {
+	return isForked(c.WasmBlock, num)
+}
+
+//是否已经开启Tina链的预编译合约
+func (c *ChainConfig) IsBokerPrecompile(num *big.Int) bool {
+	return isForked(c.BokerPrecompileBlock, num)
+}
+
+//用户基础合约的设置以及取消交易是否已经生效
+func (c *ChainConfig) IsUserContract(num *big.Int) bool {
+	return isForked(c.UserContractBlock, num)
+}
+
+//系统基础合约的变更是否需要授权以及延迟确认
+func (c *ChainConfig) IsSystemContract(num *big.Int) bool {
+	return isForked(c.SystemContractBlock, num)
+}
+
+//次要类型为Sponsored的普通交易是否由代付账号支付Gas
+func (c *ChainConfig) IsSponsor(num *big.Int) bool {
+	return isForked(c.SponsorBlock, num)
+}
+
+//次要类型为ContractMeta的扩展交易是否登记合约元数据(而不是调用合约)
+func (c *ChainConfig) IsContractMeta(num *big.Int) bool {
+	return isForked(c.ContractMetaBlock, num)
+}
+
+//是否已经接受验证者设置出块签名账号的交易
+func (c *ChainConfig) IsSigningKey(num *big.Int) bool {
+	return isForked(c.SigningKeyBlock, num)
+}
+
+//是否已经接受跨链锁定以及释放交易
+func (c *ChainConfig) IsBridge(num *big.Int) bool {
+	return isForked(c.BridgeBlock, num)
+}
+
+//是否已经接受批量转账交易
+func (c *ChainConfig) IsMultiSend(num *big.Int) bool {
+	return isForked(c.MultiSendBlock, num)
+}
+
+//是否已经接受登记验证者运营信息的交易
+func (c *ChainConfig) IsValidatorInfo(num *big.Int) bool {
+	return isForked(c.ValidatorInfoBlock, num)
+}
+
+//是否已经接受向股权Gas池注资的交易
+func (c *ChainConfig) IsGasPoolFund(num *big.Int) bool {
+	return isForked(c.GasPoolFundBlock, num)
+}
+
 // GasTable returns the gas table corresponding to the current phase (homestead or homestead reprice).
 //
 // The returned GasTable's fields shouldn't, under any circumstances, be changed.
@@ -146,6 +344,9 @@ func (c *ChainConfig) GasTable(num *big.Int) GasTable {
 	if num == nil {
 		return GasTableHomestead
 	}
+	if table, ok := c.scheduledGasTable(num); ok {
+		return table
+	}
 	switch {
 	case c.IsEIP158(num):
 		return GasTableEIP158
@@ -199,6 +400,63 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.ByzantiumBlock, newcfg.ByzantiumBlock, head) {
 		return newCompatError("Byzantium fork block", c.ByzantiumBlock, newcfg.ByzantiumBlock)
 	}
+	if isForkIncompatible(c.WasmBlock, newcfg.WasmBlock, head) {
+		return newCompatError("WASM fork block", c.WasmBlock, newcfg.WasmBlock)
+	}
+	if isForkIncompatible(c.BokerPrecompileBlock, newcfg.BokerPrecompileBlock, head) {
+		return newCompatError("Boker precompile fork block", c.BokerPrecompileBlock, newcfg.BokerPrecompileBlock)
+	}
+	if isForkIncompatible(c.UserContractBlock, newcfg.UserContractBlock, head) {
+		return newCompatError("user contract fork block", c.UserContractBlock, newcfg.UserContractBlock)
+	}
+	if isForkIncompatible(c.SystemContractBlock, newcfg.SystemContractBlock, head) {
+		return newCompatError("system contract fork block", c.SystemContractBlock, newcfg.SystemContractBlock)
+	}
+	if isForkIncompatible(c.DeployControlBlock, newcfg.DeployControlBlock, head) {
+		return newCompatError("deploy control fork block", c.DeployControlBlock, newcfg.DeployControlBlock)
+	}
+	if c.IsDeployControl(head) && (c.MaxCodeSize != newcfg.MaxCodeSize || c.DeployWhitelist != newcfg.DeployWhitelist) {
+		return newCompatError("deploy control", c.DeployControlBlock, newcfg.DeployControlBlock)
+	}
+	if isForkIncompatible(c.ExtraLimitBlock, newcfg.ExtraLimitBlock, head) {
+		return newCompatError("extra limit fork block", c.ExtraLimitBlock, newcfg.ExtraLimitBlock)
+	}
+	if c.IsExtraLimit(head) && (c.MaxWordSize != newcfg.MaxWordSize || c.MaxDataSize != newcfg.MaxDataSize || c.MaxBlockExtraSize != newcfg.MaxBlockExtraSize) {
+		return newCompatError("extra size limits", c.ExtraLimitBlock, newcfg.ExtraLimitBlock)
+	}
+	if isForkIncompatible(c.SponsorBlock, newcfg.SponsorBlock, head) {
+		return newCompatError("sponsor fork block", c.SponsorBlock, newcfg.SponsorBlock)
+	}
+	if isForkIncompatible(c.ContractMetaBlock, newcfg.ContractMetaBlock, head) {
+		return newCompatError("contract meta fork block", c.ContractMetaBlock, newcfg.ContractMetaBlock)
+	}
+	if isForkIncompatible(c.SigningKeyBlock, newcfg.SigningKeyBlock, head) {
+		return newCompatError("signing key fork block", c.SigningKeyBlock, newcfg.SigningKeyBlock)
+	}
+	if isForkIncompatible(c.BridgeBlock, newcfg.BridgeBlock, head) {
+		return newCompatError("bridge fork block", c.BridgeBlock, newcfg.BridgeBlock)
+	}
+	if isForkIncompatible(c.MultiSendBlock, newcfg.MultiSendBlock, head) {
+		return newCompatError("multi-send fork block", c.MultiSendBlock, newcfg.MultiSendBlock)
+	}
+	if isForkIncompatible(c.ValidatorInfoBlock, newcfg.ValidatorInfoBlock, head) {
+		return newCompatError("validator info fork block", c.ValidatorInfoBlock, newcfg.ValidatorInfoBlock)
+	}
+	if isForkIncompatible(c.GasPoolFundBlock, newcfg.GasPoolFundBlock, head) {
+		return newCompatError("gas pool fund fork block", c.GasPoolFundBlock, newcfg.GasPoolFundBlock)
+	}
+	if err := c.checkGasSchedules(newcfg, head); err != nil {
+		return err
+	}
+	if err := c.checkVoteWeight(newcfg, head); err != nil {
+		return err
+	}
+	if err := c.checkGovernance(newcfg, head); err != nil {
+		return err
+	}
+	if isForkIncompatible(c.RewardScheduleBlock, newcfg.RewardScheduleBlock, head) {
+		return newCompatError("reward schedule fork block", c.RewardScheduleBlock, newcfg.RewardScheduleBlock)
+	}
 	return nil
 }
 
@@ -265,7 +523,7 @@ func (err *ConfigCompatError) Error() string {
 type Rules struct {
 	ChainId                                   *big.Int
 	IsHomestead, IsEIP150, IsEIP155, IsEIP158 bool
-	IsByzantium                               bool
+	IsByzantium, IsWasm, IsBokerPrecompile    bool
 }
 
 func (c *ChainConfig) Rules(num *big.Int) Rules {
@@ -273,5 +531,5 @@ func (c *ChainConfig) Rules(num *big.Int) Rules {
 	if chainId == nil {
 		chainId = new(big.Int)
 	}
-	return Rules{ChainId: new(big.Int).Set(chainId), IsHomestead: c.IsHomestead(num), IsEIP150: c.IsEIP150(num), IsEIP155: c.IsEIP155(num), IsEIP158: c.IsEIP158(num), IsByzantium: c.IsByzantium(num)}
+	return Rules{ChainId: new(big.Int).Set(chainId), IsHomestead: c.IsHomestead(num), IsEIP150: c.IsEIP150(num), IsEIP155: c.IsEIP155(num), IsEIP158: c.IsEIP158(num), IsByzantium: c.IsByzantium(num), IsWasm: c.IsWasm(num), IsBokerPrecompile: c.IsBokerPrecompile(num)}
 }
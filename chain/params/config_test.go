@@ -70,6 +70,144 @@ func TestCheckCompatible(t *testing.T) {
 				RewindTo:     9,
 			},
 		},
+		{
+			stored: &ChainConfig{BokerPrecompileBlock: big.NewInt(10)},
+			new:    &ChainConfig{},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "Boker precompile fork block",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    nil,
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{UserContractBlock: big.NewInt(10)},
+			new:    &ChainConfig{UserContractBlock: big.NewInt(20)},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "user contract fork block",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(20),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{},
+			new:    &ChainConfig{SystemContractBlock: big.NewInt(10)},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "system contract fork block",
+				StoredConfig: nil,
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored:  &ChainConfig{DeployControlBlock: big.NewInt(10), MaxCodeSize: 1024},
+			new:     &ChainConfig{DeployControlBlock: big.NewInt(10), MaxCodeSize: 2048},
+			head:    9,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{DeployControlBlock: big.NewInt(10), DeployWhitelist: true},
+			new:    &ChainConfig{DeployControlBlock: big.NewInt(10)},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "deploy control",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{ExtraLimitBlock: big.NewInt(10)},
+			new:    &ChainConfig{ExtraLimitBlock: big.NewInt(10), MaxBlockExtraSize: 1024},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "extra size limits",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{},
+			new:    &ChainConfig{SponsorBlock: big.NewInt(10)},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "sponsor fork block",
+				StoredConfig: nil,
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{ContractMetaBlock: big.NewInt(10)},
+			new:    &ChainConfig{},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "contract meta fork block",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    nil,
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{},
+			new:    &ChainConfig{SigningKeyBlock: big.NewInt(10)},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "signing key fork block",
+				StoredConfig: nil,
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{BridgeBlock: big.NewInt(10)},
+			new:    &ChainConfig{},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "bridge fork block",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    nil,
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{},
+			new:    &ChainConfig{MultiSendBlock: big.NewInt(10)},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "multi-send fork block",
+				StoredConfig: nil,
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{ValidatorInfoBlock: big.NewInt(10)},
+			new:    &ChainConfig{},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "validator info fork block",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    nil,
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{},
+			new:    &ChainConfig{GasPoolFundBlock: big.NewInt(10)},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "gas pool fund fork block",
+				StoredConfig: nil,
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,91 @@
+package params
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// GasTableEIP1884 contains the gas re-prices of EIP-1884 (SLOAD and BALANCE).
+var GasTableEIP1884 = GasTable{
+	ExtcodeSize:     700,
+	ExtcodeCopy:     700,
+	Balance:         700,
+	SLoad:           800,
+	Calls:           700,
+	Suicide:         5000,
+	ExpByte:         50,
+	CreateBySuicide: 25000,
+}
+
+// GasTables lists the gas tables a chain config may schedule by name. Tables
+// must never be changed once a chain uses them, new prices get a new name.
+var GasTables = map[string]GasTable{
+	"homestead": GasTableHomestead,
+	"eip150":    GasTableEIP150,
+	"eip158":    GasTableEIP158,
+	"eip1884":   GasTableEIP1884,
+}
+
+// GasSchedule switches the EVM gas table from the given block onwards. Blocks
+// before the first schedule keep the prices implied by the EIP fork blocks, so
+// historical blocks are replayed with the prices they were mined with.
+type GasSchedule struct {
+	Block *big.Int `json:"block"` //开始使用价格表的区块
+	Table string   `json:"table"` //价格表名称（见GasTables）
+}
+
+// CheckGasSchedules verifies that every scheduled gas table is known and that
+// the schedules are ordered by strictly increasing block numbers.
+func (c *ChainConfig) CheckGasSchedules() error {
+	var last *big.Int
+	for i, schedule := range c.GasSchedules {
+		if schedule == nil || schedule.Block == nil {
+			return fmt.Errorf("gas schedule %d: missing block", i)
+		}
+		if _, ok := GasTables[schedule.Table]; !ok {
+			return fmt.Errorf("gas schedule %d: unknown gas table %q", i, schedule.Table)
+		}
+		if last != nil && schedule.Block.Cmp(last) <= 0 {
+			return fmt.Errorf("gas schedule %d: block %v not after %v", i, schedule.Block, last)
+		}
+		last = schedule.Block
+	}
+	return nil
+}
+
+// scheduledGasTable returns the gas table scheduled for the given block, if any.
+func (c *ChainConfig) scheduledGasTable(num *big.Int) (GasTable, bool) {
+	for i := len(c.GasSchedules) - 1; i >= 0; i-- {
+		schedule := c.GasSchedules[i]
+		if schedule == nil || !isForked(schedule.Block, num) {
+			continue
+		}
+		if table, ok := GasTables[schedule.Table]; ok {
+			return table, true
+		}
+	}
+	return GasTable{}, false
+}
+
+// checkGasSchedules returns an error if the gas table in effect at any block up
+// to head differs between the two configurations.
+func (c *ChainConfig) checkGasSchedules(newcfg *ChainConfig, head *big.Int) *ConfigCompatError {
+	var blocks []*big.Int
+	for _, schedules := range [][]*GasSchedule{c.GasSchedules, newcfg.GasSchedules} {
+		for _, schedule := range schedules {
+			if schedule != nil && isForked(schedule.Block, head) {
+				blocks = append(blocks, schedule.Block)
+			}
+		}
+	}
+	var conflict *big.Int
+	for _, block := range blocks {
+		if c.GasTable(block) != newcfg.GasTable(block) && (conflict == nil || block.Cmp(conflict) < 0) {
+			conflict = block
+		}
+	}
+	if conflict != nil {
+		return newCompatError("gas schedule", conflict, conflict)
+	}
+	return nil
+}
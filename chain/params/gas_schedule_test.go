@@ -0,0 +1,66 @@
+package params
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestScheduledGasTable(t *testing.T) {
+	config := *TestChainConfig
+	config.GasSchedules = []*GasSchedule{
+		{Block: big.NewInt(10), Table: "eip1884"},
+		{Block: big.NewInt(20), Table: "eip158"},
+	}
+	if err := config.CheckGasSchedules(); err != nil {
+		t.Fatalf("valid schedules rejected: %v", err)
+	}
+	tests := []struct {
+		number int64
+		want   GasTable
+	}{
+		{0, GasTableEIP158},
+		{9, GasTableEIP158},
+		{10, GasTableEIP1884},
+		{19, GasTableEIP1884},
+		{20, GasTableEIP158},
+	}
+	for i, tt := range tests {
+		if have := config.GasTable(big.NewInt(tt.number)); have != tt.want {
+			t.Errorf("test %d: gas table mismatch: have %+v, want %+v", i, have, tt.want)
+		}
+	}
+}
+
+func TestCheckGasSchedules(t *testing.T) {
+	tests := [][]*GasSchedule{
+		{{Block: big.NewInt(1), Table: "unknown"}},
+		{{Block: nil, Table: "eip1884"}},
+		{{Block: big.NewInt(2), Table: "eip1884"}, {Block: big.NewInt(2), Table: "eip158"}},
+	}
+	for i, schedules := range tests {
+		config := &ChainConfig{GasSchedules: schedules}
+		if err := config.CheckGasSchedules(); err == nil {
+			t.Errorf("test %d: invalid schedules accepted", i)
+		}
+	}
+}
+
+func TestGasScheduleCompatible(t *testing.T) {
+	stored := *TestChainConfig
+	stored.GasSchedules = []*GasSchedule{{Block: big.NewInt(10), Table: "eip1884"}}
+
+	//尚未到达的升级可以修改
+	moved := stored
+	moved.GasSchedules = []*GasSchedule{{Block: big.NewInt(15), Table: "eip1884"}}
+	if err := stored.CheckCompatible(&moved, 5); err != nil {
+		t.Errorf("future schedule change rejected: %v", err)
+	}
+	//已经生效的升级不能修改
+	err := stored.CheckCompatible(&moved, 12)
+	if err == nil {
+		t.Fatal("past schedule change accepted")
+	}
+	if err.RewindTo != 9 {
+		t.Errorf("rewind mismatch: have %d, want 9", err.RewindTo)
+	}
+}
@@ -0,0 +1,65 @@
+package params
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// GovernanceConfig enables on-chain governance proposals from the fork block
+// onwards. Before the fork governance transactions are rejected and the header
+// keeps the boker context encoding without the governance root.
+type GovernanceConfig struct {
+	Block         *big.Int `json:"block"`         //开始接受治理提案的区块
+	QuorumPercent uint64   `json:"quorumPercent"` //计票时参与投票的权重至少占提案快照总权重的比例（百分比）
+}
+
+// IsGovernance returns whether num is past the governance fork block.
+func (c *ChainConfig) IsGovernance(num *big.Int) bool {
+	return c.Governance != nil && isForked(c.Governance.Block, num)
+}
+
+// CheckGovernance verifies that the governance configuration is complete.
+func (c *ChainConfig) CheckGovernance() error {
+	g := c.Governance
+	if g == nil {
+		return nil
+	}
+	if g.Block == nil {
+		return fmt.Errorf("governance: missing block")
+	}
+	if g.QuorumPercent == 0 || g.QuorumPercent > 100 {
+		return fmt.Errorf("governance: quorum %d%% outside 1-100%%", g.QuorumPercent)
+	}
+	return nil
+}
+
+// Quorum returns whether the weight cast on a proposal reaches the quorum of
+// the total weight snapshotted when the proposal was created.
+func (g *GovernanceConfig) Quorum(cast, total *big.Int) bool {
+	if total.Sign() <= 0 {
+		return false
+	}
+	//cast*100 >= total*QuorumPercent
+	have := new(big.Int).Mul(cast, big.NewInt(100))
+	want := new(big.Int).Mul(total, new(big.Int).SetUint64(g.QuorumPercent))
+	return have.Cmp(want) >= 0
+}
+
+// checkGovernance returns an error if the governance rules in effect at head
+// differ between the two configurations.
+func (c *ChainConfig) checkGovernance(newcfg *ChainConfig, head *big.Int) *ConfigCompatError {
+	var stored, updated *big.Int
+	if c.Governance != nil {
+		stored = c.Governance.Block
+	}
+	if newcfg.Governance != nil {
+		updated = newcfg.Governance.Block
+	}
+	if isForkIncompatible(stored, updated, head) {
+		return newCompatError("governance fork block", stored, updated)
+	}
+	if c.IsGovernance(head) && c.Governance.QuorumPercent != newcfg.Governance.QuorumPercent {
+		return newCompatError("governance quorum", stored, updated)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package params
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestGovernanceQuorum(t *testing.T) {
+	config := &GovernanceConfig{Block: big.NewInt(0), QuorumPercent: 30}
+	tests := []struct {
+		cast, total int64
+		want        bool
+	}{
+		{30, 100, true},
+		{29, 100, false},
+		{100, 100, true},
+		{0, 0, false},
+	}
+	for i, tt := range tests {
+		if have := config.Quorum(big.NewInt(tt.cast), big.NewInt(tt.total)); have != tt.want {
+			t.Errorf("test %d: quorum mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
+
+func TestCheckGovernance(t *testing.T) {
+	tests := []*GovernanceConfig{
+		{QuorumPercent: 30},
+		{Block: big.NewInt(1)},
+		{Block: big.NewInt(1), QuorumPercent: 101},
+	}
+	for i, governance := range tests {
+		config := *TestChainConfig
+		config.Governance = governance
+		if err := config.CheckGovernance(); err == nil {
+			t.Errorf("test %d: invalid config accepted", i)
+		}
+	}
+}
+
+func TestGovernanceCompatible(t *testing.T) {
+	stored, updated := *TestChainConfig, *TestChainConfig
+	stored.Governance = &GovernanceConfig{Block: big.NewInt(10), QuorumPercent: 30}
+	updated.Governance = &GovernanceConfig{Block: big.NewInt(10), QuorumPercent: 50}
+
+	//分叉之前可以修改配置
+	if err := stored.CheckCompatible(&updated, 9); err != nil {
+		t.Fatalf("config change before the fork rejected: %v", err)
+	}
+	if err := stored.CheckCompatible(&updated, 10); err == nil || err.RewindTo != 9 {
+		t.Fatalf("quorum change after the fork: have %v, want rewind to 9", err)
+	}
+	updated.Governance = nil
+	if err := stored.CheckCompatible(&updated, 10); err == nil || err.RewindTo != 9 {
+		t.Fatalf("fork removal after the fork: have %v, want rewind to 9", err)
+	}
+}
@@ -49,6 +49,19 @@ const (
 	Bn256ScalarMulGas       uint64 = 40000  // Gas needed for an elliptic curve scalar multiplication
 	Bn256PairingBaseGas     uint64 = 100000 // Base price for an elliptic curve pairing check
 	Bn256PairingPerPointGas uint64 = 80000  // Per-point price for an elliptic curve pairing check
+	BokerValidatorsGas      uint64 = 2000   //Tina链新增读取当前验证者列表的预编译合约价格
+	BokerIsValidatorGas     uint64 = 400    //Tina链新增判断是否为验证者的预编译合约价格
+	BokerVotesGas           uint64 = 400    //Tina链新增读取验证者票数的预编译合约价格
+	BokerStockGas           uint64 = 400    //Tina链新增读取账号股权的预编译合约价格
+
+	MultiSendRecipientGas uint64 = 9000 //Tina链批量转账中每个接收账号的转账价格
+)
+
+//扩展交易携带数据的默认上限(链配置中未设置时使用)
+const (
+	MaxWordSize       uint64 = 1 * 1024 * 1024 //Word扩展交易携带数据的最大字节数
+	MaxDataSize       uint64 = 1 * 1024 * 1024 //Data扩展交易携带数据的最大字节数
+	MaxBlockExtraSize uint64 = 5 * 1024 * 1024 //每个区块中扩展交易携带数据累计的最大字节数
 )
 
 var (
@@ -0,0 +1,91 @@
+package params
+
+import (
+	"math/big"
+	"time"
+)
+
+// RewardConfig is the block reward schedule of the dpos chain. Amounts are
+// expressed in Tina units; each period the total block reward is split between
+// the block producer and the stock gas pool by GasPoolPercent.
+type RewardConfig struct {
+	GenesisYear    int        `json:"genesisYear"`    //奖励周期的起始年份
+	PeriodYears    int        `json:"periodYears"`    //每个奖励周期的年数
+	Rewards        []*big.Int `json:"rewards"`        //每个奖励周期的区块总奖励
+	GasPoolPercent uint64     `json:"gasPoolPercent"` //放入股权Gas池的比例（百分比）
+}
+
+// DefaultRewardConfig reproduces the reward schedule the chain launched with.
+var DefaultRewardConfig = &RewardConfig{
+	GenesisYear: 2019,
+	PeriodYears: 4,
+	Rewards: []*big.Int{
+		big.NewInt(28186474),
+		big.NewInt(14093237),
+		big.NewInt(7046619),
+		big.NewInt(3523309),
+		big.NewInt(1761655),
+		big.NewInt(880827),
+	},
+	GasPoolPercent: 20,
+}
+
+// RewardSchedule returns the configured reward schedule, falling back to the
+// default one if the chain config does not carry any.
+func (c *ChainConfig) RewardSchedule() *RewardConfig {
+	if c == nil || c.Rewards == nil {
+		return DefaultRewardConfig
+	}
+	return c.Rewards
+}
+
+// IsRewardSchedule returns whether num is past the fork accepting reward
+// schedule overrides.
+func (c *ChainConfig) IsRewardSchedule(num *big.Int) bool {
+	return isForked(c.RewardScheduleBlock, num)
+}
+
+// MaxReward returns the highest total block reward of the schedule, the upper
+// bound of any reward override.
+func (r *RewardConfig) MaxReward() *big.Int {
+	max := new(big.Int)
+	for _, reward := range r.Rewards {
+		if reward.Cmp(max) > 0 {
+			max.Set(reward)
+		}
+	}
+	return max
+}
+
+// RewardAt returns the total block reward of the period containing the given
+// block time. Blocks past the last period keep receiving the last reward.
+func (r *RewardConfig) RewardAt(blockTime int64) *big.Int {
+	if blockTime == 0 || len(r.Rewards) == 0 {
+		return new(big.Int)
+	}
+	period := 0
+	if r.PeriodYears > 0 {
+		period = (time.Unix(blockTime, 0).Year() - r.GenesisYear) / r.PeriodYears
+	}
+	if period < 0 {
+		period = 0
+	}
+	if period >= len(r.Rewards) {
+		period = len(r.Rewards) - 1
+	}
+	return new(big.Int).Set(r.Rewards[period])
+}
+
+// SplitReward divides a total block reward into the producer and the gas pool
+// shares. The producer share is rounded to the nearest unit and the gas pool
+// receives the remainder, so the two always add up to the total.
+func SplitReward(total *big.Int, gasPoolPercent uint64) (producer, gasPool *big.Int) {
+	if gasPoolPercent > 100 {
+		gasPoolPercent = 100
+	}
+	producer = new(big.Int).Mul(total, new(big.Int).SetUint64(100-gasPoolPercent))
+	producer.Add(producer, big.NewInt(50))
+	producer.Div(producer, big.NewInt(100))
+	gasPool = new(big.Int).Sub(total, producer)
+	return producer, gasPool
+}
@@ -0,0 +1,42 @@
+package params
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestDefaultRewardSplit(t *testing.T) {
+	// Producer and gas pool shares of the schedule the chain launched with.
+	miner := []int64{22549179, 11274590, 5637295, 2818647, 1409324, 704662}
+	stock := []int64{5637295, 2818647, 1409324, 704662, 352331, 176165}
+
+	for i, total := range DefaultRewardConfig.Rewards {
+		producer, gasPool := SplitReward(total, DefaultRewardConfig.GasPoolPercent)
+		if producer.Int64() != miner[i] || gasPool.Int64() != stock[i] {
+			t.Errorf("period %d: split mismatch: have %v/%v, want %d/%d", i, producer, gasPool, miner[i], stock[i])
+		}
+	}
+}
+
+func TestRewardAt(t *testing.T) {
+	config := DefaultRewardConfig
+	at := func(year int) int64 {
+		return time.Date(year, time.June, 1, 0, 0, 0, 0, time.Local).Unix()
+	}
+	tests := []struct {
+		time int64
+		want *big.Int
+	}{
+		{0, new(big.Int)},
+		{at(2019), config.Rewards[0]},
+		{at(2022), config.Rewards[0]},
+		{at(2023), config.Rewards[1]},
+		{at(2100), config.Rewards[len(config.Rewards)-1]},
+	}
+	for i, tt := range tests {
+		if have := config.RewardAt(tt.time); have.Cmp(tt.want) != 0 {
+			t.Errorf("test %d: reward mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package params
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// VoteWeightMode selects which balance a validator vote is weighted with.
+type VoteWeightMode string
+
+const (
+	VoteWeightSnapshot VoteWeightMode = "snapshot" //按投票时转入投票合约的数量
+	VoteWeightLive     VoteWeightMode = "live"     //按投票账号在选举时的余额
+)
+
+// VoteWeightConfig controls how the votes of the validator election are weighted
+// from the fork block onwards. Before the fork the election keeps using the tickets
+// tallied by the vote contract. Afterwards every vote is recorded in the dpos
+// context and the VoteEpoch transaction elects the validators from the recorded
+// votes, so votes cast before the fork have to be cast again to count.
+type VoteWeightConfig struct {
+	Block        *big.Int       `json:"block"`                  //开始按本配置计算投票权重的区块
+	Mode         VoteWeightMode `json:"mode"`                   //投票权重使用的余额（snapshot或者live）
+	DecayPercent uint64         `json:"decayPercent,omitempty"` //投票后每经过一个周期权重衰减的比例（百分比）
+	RevoteEpochs uint64         `json:"revoteEpochs,omitempty"` //投票在多少个周期后失效，需要重新投票（0则不失效）
+}
+
+// IsVoteWeight returns whether num is past the vote weight fork block.
+func (c *ChainConfig) IsVoteWeight(num *big.Int) bool {
+	return c.VoteWeight != nil && isForked(c.VoteWeight.Block, num)
+}
+
+// CheckVoteWeight verifies that the vote weight configuration is complete.
+func (c *ChainConfig) CheckVoteWeight() error {
+	v := c.VoteWeight
+	if v == nil {
+		return nil
+	}
+	if v.Block == nil {
+		return fmt.Errorf("vote weight: missing block")
+	}
+	if v.Mode != VoteWeightSnapshot && v.Mode != VoteWeightLive {
+		return fmt.Errorf("vote weight: unknown mode %q", v.Mode)
+	}
+	if v.DecayPercent > 100 {
+		return fmt.Errorf("vote weight: decay %d%% above 100%%", v.DecayPercent)
+	}
+	return nil
+}
+
+// Weight returns the weight of a vote of amount cast age epochs ago, where
+// balance is the live balance of the voter at election time.
+func (v *VoteWeightConfig) Weight(amount, balance *big.Int, age uint64) *big.Int {
+	if v.RevoteEpochs > 0 && age >= v.RevoteEpochs {
+		return new(big.Int)
+	}
+	weight := new(big.Int).Set(amount)
+	if v.Mode == VoteWeightLive {
+		weight.Set(balance)
+	}
+	if v.DecayPercent >= 100 && age > 0 {
+		return new(big.Int)
+	}
+	//权重衰减到0以后无需继续计算
+	keep, hundred := new(big.Int).SetUint64(100-v.DecayPercent), big.NewInt(100)
+	for i := uint64(0); i < age && v.DecayPercent > 0 && weight.Sign() > 0; i++ {
+		weight.Mul(weight, keep)
+		weight.Div(weight, hundred)
+	}
+	return weight
+}
+
+// checkVoteWeight returns an error if the vote weight rules in effect at head
+// differ between the two configurations.
+func (c *ChainConfig) checkVoteWeight(newcfg *ChainConfig, head *big.Int) *ConfigCompatError {
+	var stored, updated *big.Int
+	if c.VoteWeight != nil {
+		stored = c.VoteWeight.Block
+	}
+	if newcfg.VoteWeight != nil {
+		updated = newcfg.VoteWeight.Block
+	}
+	if isForkIncompatible(stored, updated, head) {
+		return newCompatError("vote weight fork block", stored, updated)
+	}
+	if c.IsVoteWeight(head) {
+		have, want := *c.VoteWeight, *newcfg.VoteWeight
+		have.Block, want.Block = nil, nil
+		if have != want {
+			return newCompatError("vote weight rules", stored, updated)
+		}
+	}
+	return nil
+}
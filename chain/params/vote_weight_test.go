@@ -0,0 +1,64 @@
+package params
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestVoteWeight(t *testing.T) {
+	amount, balance := big.NewInt(1000), big.NewInt(300)
+	tests := []struct {
+		config VoteWeightConfig
+		age    uint64
+		want   int64
+	}{
+		{VoteWeightConfig{Mode: VoteWeightSnapshot}, 0, 1000},
+		{VoteWeightConfig{Mode: VoteWeightSnapshot}, 50, 1000},
+		{VoteWeightConfig{Mode: VoteWeightLive}, 3, 300},
+		{VoteWeightConfig{Mode: VoteWeightSnapshot, DecayPercent: 10}, 0, 1000},
+		{VoteWeightConfig{Mode: VoteWeightSnapshot, DecayPercent: 10}, 2, 810},
+		{VoteWeightConfig{Mode: VoteWeightLive, DecayPercent: 50}, 1, 150},
+		{VoteWeightConfig{Mode: VoteWeightSnapshot, DecayPercent: 100}, 1, 0},
+		{VoteWeightConfig{Mode: VoteWeightSnapshot, RevoteEpochs: 4}, 3, 1000},
+		{VoteWeightConfig{Mode: VoteWeightSnapshot, RevoteEpochs: 4}, 4, 0},
+	}
+	for i, tt := range tests {
+		if have := tt.config.Weight(amount, balance, tt.age); have.Int64() != tt.want {
+			t.Errorf("test %d: weight mismatch: have %v, want %d", i, have, tt.want)
+		}
+	}
+}
+
+func TestCheckVoteWeight(t *testing.T) {
+	tests := []*VoteWeightConfig{
+		{Mode: VoteWeightSnapshot},
+		{Block: big.NewInt(1), Mode: "stake"},
+		{Block: big.NewInt(1), Mode: VoteWeightLive, DecayPercent: 101},
+	}
+	for i, weight := range tests {
+		config := *TestChainConfig
+		config.VoteWeight = weight
+		if err := config.CheckVoteWeight(); err == nil {
+			t.Errorf("test %d: invalid config accepted", i)
+		}
+	}
+}
+
+func TestVoteWeightCompatible(t *testing.T) {
+	stored, updated := *TestChainConfig, *TestChainConfig
+	stored.VoteWeight = &VoteWeightConfig{Block: big.NewInt(10), Mode: VoteWeightSnapshot}
+	updated.VoteWeight = &VoteWeightConfig{Block: big.NewInt(10), Mode: VoteWeightLive}
+
+	//分叉之前可以修改配置
+	if err := stored.CheckCompatible(&updated, 9); err != nil {
+		t.Fatalf("config change before the fork rejected: %v", err)
+	}
+	err := stored.CheckCompatible(&updated, 10)
+	if err == nil || err.RewindTo != 9 {
+		t.Fatalf("rules change after the fork: have %v, want rewind to 9", err)
+	}
+	updated.VoteWeight = nil
+	if err := stored.CheckCompatible(&updated, 10); err == nil || err.RewindTo != 9 {
+		t.Fatalf("fork removal after the fork: have %v, want rewind to 9", err)
+	}
+}
@@ -0,0 +1,40 @@
+package rpc
+
+import "context"
+
+// Auditor is notified about the outcome of every method call handled by a server
+// it is attached to, allowing privileged operations to be recorded.
+type Auditor interface {
+	// Audit is invoked after the method has returned. The ctx carries the address
+	// of the caller if it is known (see RemoteAddrFromContext).
+	Audit(ctx context.Context, method string, args []interface{}, result interface{}, err error)
+}
+
+// remoteAddrKey is used to store the caller address within the connection context.
+type remoteAddrKey struct{}
+
+// RemoteAddrFromContext returns the network address of the caller that issued the
+// request, if the transport it arrived over exposes one.
+func RemoteAddrFromContext(ctx context.Context) (string, bool) {
+	addr, ok := ctx.Value(remoteAddrKey{}).(string)
+	return addr, ok
+}
+
+// SetAuditor attaches an auditor to the server. It must be called before the
+// server starts serving requests.
+func (s *Server) SetAuditor(auditor Auditor) {
+	s.auditor = auditor
+}
+
+// audit hands a finished method call over to the auditor, if one is attached.
+func (s *Server) audit(ctx context.Context, req *serverRequest, result interface{}, err error) {
+	if s.auditor == nil {
+		return
+	}
+	args := make([]interface{}, len(req.args))
+	for i, arg := range req.args {
+		args[i] = arg.Interface()
+	}
+	method := req.svcname + serviceMethodSeparator + formatName(req.callb.method.Name)
+	s.auditor.Audit(ctx, method, args, result, err)
+}
@@ -0,0 +1,49 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+)
+
+// Authorizer decides whether the caller holding the given bearer token (empty if
+// none was presented) may invoke methods of a namespace.
+type Authorizer interface {
+	Authorize(token string, namespace string) error
+}
+
+// authTokenKey is used to store the caller's bearer token within the connection context.
+type authTokenKey struct{}
+
+// unauthorizedError is returned when a request is rejected by the authorizer.
+type unauthorizedError struct{ message string }
+
+func (e *unauthorizedError) ErrorCode() int { return -32001 }
+
+func (e *unauthorizedError) Error() string { return e.message }
+
+// SetAuthorizer attaches an authorizer to the server, checking every request before
+// it is executed. It must be called before the server starts serving requests.
+func (s *Server) SetAuthorizer(authorizer Authorizer) {
+	s.authorizer = authorizer
+}
+
+// authorize checks the request against the attached authorizer, if any.
+func (s *Server) authorize(ctx context.Context, req *serverRequest) Error {
+	if s.authorizer == nil {
+		return nil
+	}
+	token, _ := ctx.Value(authTokenKey{}).(string)
+	if err := s.authorizer.Authorize(token, req.svcname); err != nil {
+		return &unauthorizedError{err.Error()}
+	}
+	return nil
+}
+
+// BearerToken extracts the token of a "Bearer" authorization header value.
+func BearerToken(header string) string {
+	const prefix = "bearer "
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(header[len(prefix):])
+}
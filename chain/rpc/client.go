@@ -402,13 +402,24 @@ func (c *Client) newMessage(method string, paramsIn ...interface{}) (*jsonrpcMes
 	return &jsonrpcMessage{Version: "2.0", ID: c.nextID(), Method: method, Params: params}, nil
 }
 
+// redactParams hides the parameters of personal_* requests, which carry account
+// passwords, so they never reach the logs.
+func redactParams(msg interface{}) interface{} {
+	if m, ok := msg.(*jsonrpcMessage); ok && strings.HasPrefix(m.Method, "personal"+serviceMethodSeparator) {
+		redacted := *m
+		redacted.Params = json.RawMessage(`"<redacted>"`)
+		return &redacted
+	}
+	return msg
+}
+
 // send registers op with the dispatch loop, then sends msg on the connection.
 // if sending fails, op is deregistered.
 func (c *Client) send(ctx context.Context, op *requestOp, msg interface{}) error {
 	select {
 	case c.requestOp <- op:
 		log.Trace("", "msg", log.Lazy{Fn: func() string {
-			return fmt.Sprint("sending ", msg)
+			return fmt.Sprint("sending ", redactParams(msg))
 		}})
 		err := c.write(ctx, msg)
 		c.sendDone <- err
@@ -49,6 +49,36 @@ func TestClientRequest(t *testing.T) {
 	}
 }
 
+type testDataError struct{}
+
+func (e *testDataError) Error() string          { return "reverted" }
+func (e *testDataError) ErrorCode() int         { return 3 }
+func (e *testDataError) ErrorData() interface{} { return "0x1234" }
+
+type ErrorService struct{}
+
+func (s *ErrorService) Fail() error {
+	return &testDataError{}
+}
+
+func TestClientErrorData(t *testing.T) {
+	server := newTestServer("service", new(ErrorService))
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	err := client.Call(nil, "service_fail")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if code := err.(Error).ErrorCode(); code != 3 {
+		t.Errorf("error code mismatch: have %d, want 3", code)
+	}
+	if data := err.(DataError).ErrorData(); data != "0x1234" {
+		t.Errorf("error data mismatch: have %v, want 0x1234", data)
+	}
+}
+
 func TestClientBatchRequest(t *testing.T) {
 	server := newTestServer("service", new(Service))
 	defer server.Stop()
@@ -27,6 +27,7 @@ import (
 	"mime"
 	"net"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -39,6 +40,31 @@ const (
 	maxHTTPRequestContentLength = 1024 * 128
 )
 
+// HTTPTimeouts represents the configuration params for the HTTP RPC server.
+type HTTPTimeouts struct {
+	// ReadTimeout is the maximum duration for reading the entire
+	// request, including the body.
+	ReadTimeout time.Duration
+
+	// WriteTimeout is the maximum duration before timing out
+	// writes of the response. Zero leaves long running calls
+	// (e.g. tracing) unbounded at the transport level.
+	WriteTimeout time.Duration
+
+	// IdleTimeout is the maximum amount of time to wait for the
+	// next request when keep-alives are enabled. If IdleTimeout
+	// is zero, the value of ReadTimeout is used.
+	IdleTimeout time.Duration
+}
+
+// DefaultHTTPTimeouts represents the default timeout values used if further
+// configuration is not provided.
+var DefaultHTTPTimeouts = HTTPTimeouts{
+	ReadTimeout:  30 * time.Second,
+	WriteTimeout: 0,
+	IdleTimeout:  120 * time.Second,
+}
+
 var nullAddr, _ = net.ResolveTCPAddr("tcp", "127.0.0.1:0")
 
 type httpConn struct {
@@ -68,6 +94,29 @@ func (hc *httpConn) Close() error {
 
 // DialHTTP creates a new RPC clients that connection to an RPC server over HTTP.
 func DialHTTP(endpoint string) (*Client, error) {
+	return DialHTTPWithToken(endpoint, "")
+}
+
+// DialHTTPWithToken creates a new RPC client that connects to an RPC server over
+// HTTP, presenting the given bearer token on every request to gain access to the
+// namespaces protected by the server.
+func DialHTTPWithToken(endpoint string, token string) (*Client, error) {
+	req, err := http.NewRequest("POST", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", contentType)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return dialHTTP(req, new(http.Client))
+}
+
+// DialHTTPWithClient creates a new RPC client that connects to an RPC server over
+// HTTP using the provided HTTP Client, e.g. one configured for mutual TLS.
+func DialHTTPWithClient(endpoint string, client *http.Client) (*Client, error) {
 	req, err := http.NewRequest("POST", endpoint, nil)
 	if err != nil {
 		return nil, err
@@ -75,9 +124,13 @@ func DialHTTP(endpoint string) (*Client, error) {
 	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("Accept", contentType)
 
+	return dialHTTP(req, client)
+}
+
+func dialHTTP(req *http.Request, client *http.Client) (*Client, error) {
 	initctx := context.Background()
 	return newClient(initctx, func(context.Context) (net.Conn, error) {
-		return &httpConn{client: new(http.Client), req: req, closed: make(chan struct{})}, nil
+		return &httpConn{client: client, req: req, closed: make(chan struct{})}, nil
 	})
 }
 
@@ -146,8 +199,31 @@ func (t *httpReadWriteNopCloser) Close() error {
 // NewHTTPServer creates a new HTTP RPC server around an API provider.
 //
 // Deprecated: Server implements http.Handler
-func NewHTTPServer(cors []string, srv *Server) *http.Server {
-	return &http.Server{Handler: newCorsHandler(srv, cors)}
+func NewHTTPServer(cors []string, vhosts []string, srv *Server) *http.Server {
+	return &http.Server{Handler: NewHTTPHandler(cors, vhosts, srv)}
+}
+
+// NewHTTPServerWithTimeouts creates an HTTP server serving the given handler,
+// applying the read, write and idle timeouts.
+func NewHTTPServerWithTimeouts(handler http.Handler, timeouts HTTPTimeouts) *http.Server {
+	return &http.Server{
+		Handler:      handler,
+		ReadTimeout:  timeouts.ReadTimeout,
+		WriteTimeout: timeouts.WriteTimeout,
+		IdleTimeout:  timeouts.IdleTimeout,
+	}
+}
+
+// NewVirtualHostHandler wraps an HTTP handler, only letting through requests
+// addressed to one of the given virtual hosts.
+func NewVirtualHostHandler(vhosts []string, next http.Handler) http.Handler {
+	return newVHostHandler(vhosts, next)
+}
+
+// NewHTTPHandler wraps an RPC server into an HTTP handler enforcing the allowed
+// cross-origin domains and virtual hosts.
+func NewHTTPHandler(cors []string, vhosts []string, srv *Server) http.Handler {
+	return newVHostHandler(vhosts, newCorsHandler(srv, cors))
 }
 
 // ServeHTTP serves JSON-RPC requests over HTTP.
@@ -167,7 +243,9 @@ func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	defer codec.Close()
 
 	w.Header().Set("content-type", contentType)
-	srv.ServeSingleRequest(codec, OptionMethodInvocation)
+	ctx := context.WithValue(context.Background(), remoteAddrKey{}, r.RemoteAddr)
+	ctx = context.WithValue(ctx, authTokenKey{}, BearerToken(r.Header.Get("Authorization")))
+	srv.serveRequest(ctx, codec, true, OptionMethodInvocation)
 }
 
 // validateRequest returns a non-zero response code and error message if the
@@ -188,6 +266,54 @@ func validateRequest(r *http.Request) (int, error) {
 	return 0, nil
 }
 
+// virtualHostHandler is a handler which validates the Host-header of incoming
+// requests, preventing DNS rebinding attacks against the RPC endpoint.
+type virtualHostHandler struct {
+	vhosts map[string]struct{}
+	next   http.Handler
+}
+
+// newVHostHandler creates a handler only letting through requests addressed to one
+// of the given virtual hosts. A "*" entry (or an empty list) accepts every host.
+func newVHostHandler(vhosts []string, next http.Handler) http.Handler {
+	allowed := make(map[string]struct{})
+	for _, vhost := range vhosts {
+		if vhost == "*" {
+			return next
+		}
+		allowed[strings.ToLower(vhost)] = struct{}{}
+	}
+	if len(allowed) == 0 {
+		return next
+	}
+	return &virtualHostHandler{allowed, next}
+}
+
+// ServeHTTP serves JSON-RPC requests over HTTP, implements http.Handler
+func (h *virtualHostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// if r.Host is not set, we can continue serving since a browser would set the Host header
+	if r.Host == "" {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		// Either invalid (too many colons) or no port specified
+		host = r.Host
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		// It's an IP address, we can serve that
+		h.next.ServeHTTP(w, r)
+		return
+	}
+	// Not an ip address, but a hostname. Need to validate
+	if _, exist := h.vhosts[strings.ToLower(host)]; exist {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+	http.Error(w, "invalid host specified", http.StatusForbidden)
+}
+
 func newCorsHandler(srv *Server, allowedOrigins []string) http.Handler {
 	// disable CORS support if user has not specified a custom CORS configuration
 	if len(allowedOrigins) == 0 {
@@ -36,7 +36,11 @@ func ipcListen(endpoint string) (net.Listener, error) {
 	if err != nil {
 		return nil, err
 	}
-	os.Chmod(endpoint, 0600)
+	// Restrict the socket to the node's user, it exposes the privileged APIs
+	if err := os.Chmod(endpoint, 0600); err != nil {
+		l.Close()
+		return nil, err
+	}
 	return l, nil
 }
 
@@ -0,0 +1,30 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/Tinachain/Tina/chain/metrics"
+)
+
+var (
+	rpcRequestMeter = metrics.NewMeter("rpc/requests")
+	rpcSuccessMeter = metrics.NewMeter("rpc/success")
+	rpcFailureMeter = metrics.NewMeter("rpc/failure")
+
+	rpcSubscriptionCounter = metrics.NewCounter("rpc/subscriptions/active")
+	rpcRejectedSubMeter    = metrics.NewMeter("rpc/subscriptions/rejected")
+	rpcNotificationMeter   = metrics.NewMeter("rpc/notifications/sent")
+	rpcSlowConsumerMeter   = metrics.NewMeter("rpc/notifications/slowconsumers")
+)
+
+// updateServingMetrics records the outcome and the latency of a method call.
+func updateServingMetrics(req *serverRequest, start time.Time, failed bool) {
+	rpcRequestMeter.Mark(1)
+	if failed {
+		rpcFailureMeter.Mark(1)
+	} else {
+		rpcSuccessMeter.Mark(1)
+	}
+	method := req.svcname + serviceMethodSeparator + formatName(req.callb.method.Name)
+	metrics.NewTimer("rpc/duration/" + method).UpdateSince(start)
+}
@@ -24,6 +24,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/Tinachain/Tina/chain/log"
 	"gopkg.in/fatih/set.v0"
@@ -88,6 +89,9 @@ func (s *Server) RegisterName(name string, rcvr interface{}) error {
 	if name == "" {
 		return fmt.Errorf("no service name for type %s", svc.typ.String())
 	}
+	if s.denied[name] {
+		return fmt.Errorf("service %s is not available on this transport", name)
+	}
 	if !isExported(reflect.Indirect(rcvrVal).Type().Name()) {
 		return fmt.Errorf("%s is not exported", reflect.Indirect(rcvrVal).Type().Name())
 	}
@@ -130,7 +134,7 @@ func (s *Server) RegisterName(name string, rcvr interface{}) error {
 // If singleShot is true it will process a single request, otherwise it will handle
 // requests until the codec returns an error when reading a request (in most cases
 // an EOF). It executes requests in parallel when singleShot is false.
-func (s *Server) serveRequest(codec ServerCodec, singleShot bool, options CodecOption) error {
+func (s *Server) serveRequest(ctx context.Context, codec ServerCodec, singleShot bool, options CodecOption) error {
 	var pend sync.WaitGroup
 
 	defer func() {
@@ -145,14 +149,16 @@ func (s *Server) serveRequest(codec ServerCodec, singleShot bool, options CodecO
 		s.codecsMu.Unlock()
 	}()
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	// if the codec supports notification include a notifier that callbacks can use
 	// to send notification to clients. It is thight to the codec/connection. If the
 	// connection is closed the notifier will stop and cancels all active subscriptions.
 	if options&OptionSubscriptions == OptionSubscriptions {
-		ctx = context.WithValue(ctx, notifierKey{}, newNotifier(codec))
+		notifier := newNotifier(codec, s.maxBuffered)
+		defer notifier.close()
+		ctx = context.WithValue(ctx, notifierKey{}, notifier)
 	}
 	s.codecsMu.Lock()
 	if atomic.LoadInt32(&s.run) != 1 { // server stopped
@@ -215,19 +221,40 @@ func (s *Server) serveRequest(codec ServerCodec, singleShot bool, options CodecO
 	return nil
 }
 
+// DenyNamespaces prevents services from ever being registered under the given
+// namespaces, e.g. to keep privileged APIs off remotely reachable transports. It
+// must be called before any service is registered.
+func (s *Server) DenyNamespaces(namespaces []string) {
+	if s.denied == nil {
+		s.denied = make(map[string]bool)
+	}
+	for _, namespace := range namespaces {
+		s.denied[namespace] = true
+	}
+}
+
+// SetSubscriptionLimits caps the number of subscriptions a single connection may
+// hold and the number of notifications queued for it. Connections not reading
+// their notifications fast enough are closed. Zero values disable the limits.
+// It must be called before the server starts serving requests.
+func (s *Server) SetSubscriptionLimits(maxSubscriptions int, maxBuffered int) {
+	s.maxSubscriptions = maxSubscriptions
+	s.maxBuffered = maxBuffered
+}
+
 // ServeCodec reads incoming requests from codec, calls the appropriate callback and writes the
 // response back using the given codec. It will block until the codec is closed or the server is
 // stopped. In either case the codec is closed.
 func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 	defer codec.Close()
-	s.serveRequest(codec, false, options)
+	s.serveRequest(context.Background(), codec, false, options)
 }
 
 // ServeSingleRequest reads and processes a single RPC request from the given codec. It will not
 // close the codec unless a non-recoverable error has occurred. Note, this method will return after
 // a single request has been processed!
 func (s *Server) ServeSingleRequest(codec ServerCodec, options CodecOption) {
-	s.serveRequest(codec, true, options)
+	s.serveRequest(context.Background(), codec, true, options)
 }
 
 // Stop will stop reading new requests, wait for stopPendingRequestTimeout to allow pending requests to finish,
@@ -246,6 +273,10 @@ func (s *Server) Stop() {
 
 // createSubscription will call the subscription callback and returns the subscription id or error.
 func (s *Server) createSubscription(ctx context.Context, c ServerCodec, req *serverRequest) (ID, error) {
+	if notifier, ok := NotifierFromContext(ctx); ok && s.maxSubscriptions > 0 && notifier.subscriptions() >= s.maxSubscriptions {
+		rpcRejectedSubMeter.Mark(1)
+		return "", ErrTooManySubscriptions
+	}
 	// subscription have as first argument the context following optional arguments
 	args := []reflect.Value{req.callb.rcvr, reflect.ValueOf(ctx)}
 	args = append(args, req.args...)
@@ -263,6 +294,9 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 	if req.err != nil {
 		return codec.CreateErrorResponse(&req.id, req.err), nil
 	}
+	if err := s.authorize(ctx, req); err != nil {
+		return codec.CreateErrorResponse(&req.id, err), nil
+	}
 
 	if req.isUnsubscribe { // cancel subscription, first param must be the subscription id
 		if len(req.args) >= 1 && req.args[0].Kind() == reflect.String {
@@ -313,21 +347,41 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 	}
 
 	// execute RPC method and return result
+	start := time.Now()
 	reply := req.callb.method.Func.Call(arguments)
 	if len(reply) == 0 {
+		s.served(ctx, req, start, false)
+		s.audit(ctx, req, nil, nil)
 		return codec.CreateResponse(req.id, nil), nil
 	}
 
 	if req.callb.errPos >= 0 { // test if method returned an error
 		if !reply[req.callb.errPos].IsNil() {
+			s.served(ctx, req, start, true)
 			e := reply[req.callb.errPos].Interface().(error)
-			res := codec.CreateErrorResponse(&req.id, &callbackError{e.Error()})
-			return res, nil
+			s.audit(ctx, req, nil, e)
+			return createCallbackErrorResponse(codec, &req.id, e), nil
 		}
 	}
+	s.served(ctx, req, start, false)
+	s.audit(ctx, req, reply[0].Interface(), nil)
 	return codec.CreateResponse(req.id, reply[0].Interface()), nil
 }
 
+// createCallbackErrorResponse creates the error response for an error returned by a
+// callback. Errors implementing Error keep their own code, errors implementing
+// DataError have their data attached to the response.
+func createCallbackErrorResponse(codec ServerCodec, id interface{}, err error) interface{} {
+	rpcErr, ok := err.(Error)
+	if !ok {
+		rpcErr = &callbackError{err.Error()}
+	}
+	if dataErr, ok := err.(DataError); ok {
+		return codec.CreateErrorResponseWithInfo(id, rpcErr, dataErr.ErrorData())
+	}
+	return codec.CreateErrorResponse(id, rpcErr)
+}
+
 // exec executes the given request and writes the result back using the codec.
 func (s *Server) exec(ctx context.Context, codec ServerCodec, req *serverRequest) {
 	var response interface{}
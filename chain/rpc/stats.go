@@ -0,0 +1,184 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxUsageClients is the number of distinct clients tracked by the usage stats,
+// calls of further clients are accounted to usageOtherClients.
+const maxUsageClients = 1024
+
+const (
+	usageLocalClient  = "local" // clients of transports without a network address (IPC, in-process)
+	usageOtherClients = "other" // clients beyond the tracking limit
+)
+
+// UsageStats accumulates the call counts, failures and execution times of every
+// method served by the servers it is attached to, along with the number of calls
+// issued by each client. A single instance may be shared by multiple servers.
+type UsageStats struct {
+	methods map[string]*methodUsage
+	clients map[string]uint64
+	since   time.Time
+	lock    sync.Mutex
+}
+
+type methodUsage struct {
+	calls    uint64
+	failures uint64
+	total    time.Duration
+	max      time.Duration
+}
+
+// MethodUsage is the accumulated usage of a single method.
+type MethodUsage struct {
+	Calls     uint64  `json:"calls"`
+	Failures  uint64  `json:"failures"`
+	ErrorRate float64 `json:"errorRate"` // failures / calls
+	TotalTime float64 `json:"totalTime"` // execution time of all calls in milliseconds
+	AvgTime   float64 `json:"avgTime"`   // average execution time in milliseconds
+	MaxTime   float64 `json:"maxTime"`   // slowest call in milliseconds
+}
+
+// NamespaceUsage is the accumulated usage of the methods within a namespace.
+type NamespaceUsage struct {
+	MethodUsage
+	Methods map[string]*MethodUsage `json:"methods"`
+}
+
+// UsageReport is a snapshot of the usage stats, grouped by namespace.
+type UsageReport struct {
+	Since      time.Time                  `json:"since"`
+	Namespaces map[string]*NamespaceUsage `json:"namespaces"`
+	Clients    map[string]uint64          `json:"clients"` // calls per client host
+}
+
+// NewUsageStats creates an empty usage accumulator.
+func NewUsageStats() *UsageStats {
+	return &UsageStats{
+		methods: make(map[string]*methodUsage),
+		clients: make(map[string]uint64),
+		since:   time.Now(),
+	}
+}
+
+// SetUsageStats attaches a usage accumulator to the server. It must be called
+// before the server starts serving requests.
+func (s *Server) SetUsageStats(stats *UsageStats) {
+	s.stats = stats
+}
+
+// served records the outcome and the latency of a method call in the metrics
+// and the attached usage stats.
+func (s *Server) served(ctx context.Context, req *serverRequest, start time.Time, failed bool) {
+	updateServingMetrics(req, start, failed)
+	if s.stats != nil {
+		s.stats.record(ctx, req.svcname+serviceMethodSeparator+formatName(req.callb.method.Name), time.Since(start), failed)
+	}
+}
+
+// record accounts a single finished method call.
+func (u *UsageStats) record(ctx context.Context, method string, elapsed time.Duration, failed bool) {
+	client := usageLocalClient
+	if addr, ok := RemoteAddrFromContext(ctx); ok {
+		client = addr
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			client = host
+		}
+	}
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	usage := u.methods[method]
+	if usage == nil {
+		usage = new(methodUsage)
+		u.methods[method] = usage
+	}
+	usage.calls++
+	if failed {
+		usage.failures++
+	}
+	usage.total += elapsed
+	if elapsed > usage.max {
+		usage.max = elapsed
+	}
+	if _, ok := u.clients[client]; !ok && len(u.clients) >= maxUsageClients {
+		client = usageOtherClients
+	}
+	u.clients[client]++
+}
+
+// Report returns a snapshot of the stats accumulated since the last reset.
+func (u *UsageStats) Report() *UsageReport {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	return u.report()
+}
+
+// Reset drops all accumulated stats and returns the snapshot taken right before.
+func (u *UsageStats) Reset() *UsageReport {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	report := u.report()
+	u.methods = make(map[string]*methodUsage)
+	u.clients = make(map[string]uint64)
+	u.since = time.Now()
+	return report
+}
+
+func (u *UsageStats) report() *UsageReport {
+	report := &UsageReport{
+		Since:      u.since,
+		Namespaces: make(map[string]*NamespaceUsage),
+		Clients:    make(map[string]uint64, len(u.clients)),
+	}
+	for method, usage := range u.methods {
+		namespace := method
+		if idx := strings.Index(method, serviceMethodSeparator); idx >= 0 {
+			namespace = method[:idx]
+		}
+		ns := report.Namespaces[namespace]
+		if ns == nil {
+			ns = &NamespaceUsage{Methods: make(map[string]*MethodUsage)}
+			report.Namespaces[namespace] = ns
+		}
+		ns.Methods[method] = usage.export()
+
+		ns.Calls += usage.calls
+		ns.Failures += usage.failures
+		ns.TotalTime += ns.Methods[method].TotalTime
+		if max := ns.Methods[method].MaxTime; max > ns.MaxTime {
+			ns.MaxTime = max
+		}
+	}
+	for _, ns := range report.Namespaces {
+		if ns.Calls > 0 {
+			ns.ErrorRate = float64(ns.Failures) / float64(ns.Calls)
+			ns.AvgTime = ns.TotalTime / float64(ns.Calls)
+		}
+	}
+	for client, calls := range u.clients {
+		report.Clients[client] = calls
+	}
+	return report
+}
+
+func (m *methodUsage) export() *MethodUsage {
+	usage := &MethodUsage{
+		Calls:     m.calls,
+		Failures:  m.failures,
+		TotalTime: float64(m.total) / float64(time.Millisecond),
+		MaxTime:   float64(m.max) / float64(time.Millisecond),
+	}
+	if m.calls > 0 {
+		usage.ErrorRate = float64(m.failures) / float64(m.calls)
+		usage.AvgTime = usage.TotalTime / float64(m.calls)
+	}
+	return usage
+}
@@ -0,0 +1,48 @@
+package rpc
+
+import "testing"
+
+func TestUsageStats(t *testing.T) {
+	stats := NewUsageStats()
+
+	server := newTestServer("service", new(Service))
+	server.SetUsageStats(stats)
+	if err := server.RegisterName("fail", new(ErrorService)); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	for i := 0; i < 3; i++ {
+		if err := client.Call(nil, "service_echo", "hello", 10, &Args{"world"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client.Call(nil, "fail_fail")
+	client.Call(nil, "fail_fail")
+	client.Call(nil, "service_echo") // invalid parameters are not accounted
+
+	report := stats.Report()
+	if ns := report.Namespaces["service"]; ns == nil || ns.Calls != 3 || ns.Failures != 0 {
+		t.Fatalf("service namespace mismatch: %+v", ns)
+	}
+	if m := report.Namespaces["service"].Methods["service_echo"]; m == nil || m.Calls != 3 || m.AvgTime > m.MaxTime {
+		t.Fatalf("service_echo usage mismatch: %+v", m)
+	}
+	ns := report.Namespaces["fail"]
+	if ns == nil || ns.Calls != 2 || ns.Failures != 2 || ns.ErrorRate != 1 {
+		t.Fatalf("fail namespace mismatch: %+v", ns)
+	}
+	if calls := report.Clients[usageLocalClient]; calls != 5 {
+		t.Fatalf("local client calls mismatch: have %d, want 5", calls)
+	}
+	// Resetting returns the accumulated stats and starts over
+	reset := stats.Reset()
+	if len(reset.Namespaces) != 2 {
+		t.Fatalf("reset report namespace count mismatch: have %d, want 2", len(reset.Namespaces))
+	}
+	if report := stats.Report(); len(report.Namespaces) != 0 || len(report.Clients) != 0 || !report.Since.After(reset.Since) {
+		t.Fatalf("stats not cleared: %+v", report)
+	}
+}
@@ -27,6 +27,11 @@ var (
 	ErrNotificationsUnsupported = errors.New("notifications not supported")
 	// ErrNotificationNotFound is returned when the notification for the given id is not found
 	ErrSubscriptionNotFound = errors.New("subscription not found")
+	// ErrTooManySubscriptions is returned when a connection exceeds its subscription limit
+	ErrTooManySubscriptions = errors.New("too many subscriptions on connection")
+	// ErrSlowConsumer is returned when a client doesn't keep up reading its notifications
+	// and the connection is closed
+	ErrSlowConsumer = errors.New("notification buffer full, slow consumer disconnected")
 )
 
 // ID defines a pseudo random number that is used to identify RPC subscriptions.
@@ -55,16 +60,59 @@ type Notifier struct {
 	subMu    sync.RWMutex // guards active and inactive maps
 	active   map[ID]*Subscription
 	inactive map[ID]*Subscription
+	buffer   chan interface{} // pending notifications, nil if written synchronously
 }
 
 // newNotifier creates a new notifier that can be used to send subscription
-// notifications to the client.
-func newNotifier(codec ServerCodec) *Notifier {
-	return &Notifier{
+// notifications to the client. If buffered is positive, notifications are queued
+// and written in the background, disconnecting clients that fall more than the
+// given number of notifications behind.
+func newNotifier(codec ServerCodec, buffered int) *Notifier {
+	n := &Notifier{
 		codec:    codec,
 		active:   make(map[ID]*Subscription),
 		inactive: make(map[ID]*Subscription),
 	}
+	if buffered > 0 {
+		n.buffer = make(chan interface{}, buffered)
+		go n.sendLoop()
+	}
+	return n
+}
+
+// sendLoop writes the queued notifications to the client until the connection is
+// closed.
+func (n *Notifier) sendLoop() {
+	for {
+		select {
+		case notification := <-n.buffer:
+			if err := n.codec.Write(notification); err != nil {
+				n.codec.Close()
+				return
+			}
+			rpcNotificationMeter.Mark(1)
+		case <-n.codec.Closed():
+			return
+		}
+	}
+}
+
+// subscriptions returns the number of active and pending subscriptions.
+func (n *Notifier) subscriptions() int {
+	n.subMu.RLock()
+	defer n.subMu.RUnlock()
+
+	return len(n.active) + len(n.inactive)
+}
+
+// close releases the subscriptions still registered when the connection is closed.
+func (n *Notifier) close() {
+	n.subMu.Lock()
+	defer n.subMu.Unlock()
+
+	rpcSubscriptionCounter.Dec(int64(len(n.active)))
+	n.active = make(map[ID]*Subscription)
+	n.inactive = make(map[ID]*Subscription)
 }
 
 // NotifierFromContext returns the Notifier value stored in ctx, if any.
@@ -94,10 +142,21 @@ func (n *Notifier) Notify(id ID, data interface{}) error {
 	sub, active := n.active[id]
 	if active {
 		notification := n.codec.CreateNotification(string(id), sub.namespace, data)
+		if n.buffer != nil {
+			select {
+			case n.buffer <- notification:
+				return nil
+			default:
+				rpcSlowConsumerMeter.Mark(1)
+				n.codec.Close()
+				return ErrSlowConsumer
+			}
+		}
 		if err := n.codec.Write(notification); err != nil {
 			n.codec.Close()
 			return err
 		}
+		rpcNotificationMeter.Mark(1)
 	}
 	return nil
 }
@@ -115,6 +174,7 @@ func (n *Notifier) unsubscribe(id ID) error {
 	if s, found := n.active[id]; found {
 		close(s.err)
 		delete(n.active, id)
+		rpcSubscriptionCounter.Dec(1)
 		return nil
 	}
 	return ErrSubscriptionNotFound
@@ -131,5 +191,6 @@ func (n *Notifier) activate(id ID, namespace string) {
 		sub.namespace = namespace
 		n.active[id] = sub
 		delete(n.inactive, id)
+		rpcSubscriptionCounter.Inc(1)
 	}
 }
@@ -328,3 +328,63 @@ func TestSubscriptionMultipleNamespaces(t *testing.T) {
 		}
 	}
 }
+
+// Tests that a connection can't hold more subscriptions than allowed.
+func TestSubscriptionLimit(t *testing.T) {
+	server := NewServer()
+	server.SetSubscriptionLimits(1, 0)
+	if err := server.RegisterName("eth", new(NotificationTestService)); err != nil {
+		t.Fatalf("unable to register test service %v", err)
+	}
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	go server.ServeCodec(NewJSONCodec(serverConn), OptionMethodInvocation|OptionSubscriptions)
+
+	out := json.NewEncoder(clientConn)
+	in := json.NewDecoder(clientConn)
+	for i := 1; i <= 2; i++ {
+		request := map[string]interface{}{
+			"id":      i,
+			"method":  "eth_subscribe",
+			"version": "2.0",
+			"params":  []interface{}{"someSubscription", 0, 0},
+		}
+		if err := out.Encode(request); err != nil {
+			t.Fatal(err)
+		}
+		var response jsonrpcMessage
+		if err := in.Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if i == 1 && response.Error != nil {
+			t.Fatalf("subscription failed: %v", response.Error)
+		}
+		if i == 2 && (response.Error == nil || response.Error.Message != ErrTooManySubscriptions.Error()) {
+			t.Fatalf("subscription limit error mismatch: have %v, want %v", response.Error, ErrTooManySubscriptions)
+		}
+	}
+}
+
+// Tests that a client not reading its notifications is disconnected once its
+// notification buffer is full.
+func TestSlowConsumerDisconnect(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+
+	notifier := newNotifier(NewJSONCodec(serverConn), 2)
+	sub := notifier.CreateSubscription()
+	notifier.activate(sub.ID, "eth")
+
+	var err error
+	for i := 0; i < 10 && err == nil; i++ {
+		err = notifier.Notify(sub.ID, i)
+	}
+	if err != ErrSlowConsumer {
+		t.Fatalf("notify error mismatch: have %v, want %v", err, ErrSlowConsumer)
+	}
+	select {
+	case <-notifier.Closed():
+	case <-time.After(time.Second):
+		t.Fatal("slow consumer connection not closed")
+	}
+}
@@ -17,12 +17,15 @@
 package rpc
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"reflect"
 	"strings"
 	"sync"
 
+	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/common/hexutil"
 	"gopkg.in/fatih/set.v0"
 )
@@ -74,6 +77,14 @@ type Server struct {
 	run      int32
 	codecsMu sync.Mutex
 	codecs   *set.Set
+
+	auditor    Auditor         // optional recorder of method calls
+	authorizer Authorizer      // optional access control of namespaces
+	denied     map[string]bool // namespaces which may not be registered
+	stats      *UsageStats     // optional accumulator of the served calls
+
+	maxSubscriptions int // maximum number of subscriptions per connection (0 = unlimited)
+	maxBuffered      int // maximum number of queued notifications per connection (0 = write synchronously)
 }
 
 // rpcRequest represents a raw incoming RPC request
@@ -92,6 +103,13 @@ type Error interface {
 	ErrorCode() int // returns the code
 }
 
+// DataError is implemented by callback errors that carry additional data which
+// is returned to the client in the "data" member of the JSON-RPC error object.
+type DataError interface {
+	Error() string          // returns the message
+	ErrorData() interface{} // returns the error data
+}
+
 // ServerCodec implements reading, parsing and writing RPC messages for the server side of
 // a RPC session. Implementations must be go-routine safe since the codec can be called in
 // multiple go-routines concurrently.
@@ -163,3 +181,91 @@ func (bn *BlockNumber) UnmarshalJSON(data []byte) error {
 func (bn BlockNumber) Int64() int64 {
 	return (int64)(bn)
 }
+
+// BlockNumberOrHash selects a block either by number (including the "latest",
+// "earliest" and "pending" tags) or by hash. When RequireCanonical is set, a
+// block selected by hash must be part of the canonical chain.
+type BlockNumberOrHash struct {
+	BlockNumber      *BlockNumber `json:"blockNumber,omitempty"`
+	BlockHash        *common.Hash `json:"blockHash,omitempty"`
+	RequireCanonical bool         `json:"requireCanonical,omitempty"`
+}
+
+// UnmarshalJSON parses the given JSON fragment into a BlockNumberOrHash. It supports:
+// - an object {"blockNumber": ...} or {"blockHash": ..., "requireCanonical": ...}
+// - "latest", "earliest" or "pending" as string arguments
+// - the block number or a 32 byte block hash as hex string
+func (bnh *BlockNumberOrHash) UnmarshalJSON(data []byte) error {
+	type erased BlockNumberOrHash
+	e := erased{}
+	if err := json.Unmarshal(data, &e); err == nil {
+		if e.BlockNumber != nil && e.BlockHash != nil {
+			return errors.New("cannot specify both BlockHash and BlockNumber, choose one or the other")
+		}
+		if e.BlockNumber == nil && e.BlockHash == nil {
+			return errors.New("either BlockHash or BlockNumber must be specified")
+		}
+		*bnh = BlockNumberOrHash(e)
+		return nil
+	}
+	var input string
+	if err := json.Unmarshal(data, &input); err != nil {
+		return err
+	}
+	if len(input) == 66 {
+		var hash common.Hash
+		if err := hash.UnmarshalText([]byte(input)); err != nil {
+			return err
+		}
+		*bnh = BlockNumberOrHashWithHash(hash, false)
+		return nil
+	}
+	var number BlockNumber
+	if err := number.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*bnh = BlockNumberOrHashWithNumber(number)
+	return nil
+}
+
+// Number returns the selected block number, if the block was selected by number.
+func (bnh *BlockNumberOrHash) Number() (BlockNumber, bool) {
+	if bnh.BlockNumber != nil {
+		return *bnh.BlockNumber, true
+	}
+	return BlockNumber(0), false
+}
+
+// Hash returns the selected block hash, if the block was selected by hash.
+func (bnh *BlockNumberOrHash) Hash() (common.Hash, bool) {
+	if bnh.BlockHash != nil {
+		return *bnh.BlockHash, true
+	}
+	return common.Hash{}, false
+}
+
+func (bnh BlockNumberOrHash) String() string {
+	if bnh.BlockNumber != nil {
+		return fmt.Sprintf("%d", *bnh.BlockNumber)
+	}
+	if bnh.BlockHash != nil {
+		return bnh.BlockHash.Hex()
+	}
+	return "nil"
+}
+
+func BlockNumberOrHashWithNumber(blockNr BlockNumber) BlockNumberOrHash {
+	return BlockNumberOrHash{
+		BlockNumber:      &blockNr,
+		BlockHash:        nil,
+		RequireCanonical: false,
+	}
+}
+
+func BlockNumberOrHashWithHash(hash common.Hash, canonical bool) BlockNumberOrHash {
+	return BlockNumberOrHash{
+		BlockNumber:      nil,
+		BlockHash:        &hash,
+		RequireCanonical: canonical,
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/Tinachain/Tina/chain/common"
 	"github.com/Tinachain/Tina/chain/common/math"
 )
 
@@ -64,3 +65,48 @@ func TestBlockNumberJSONUnmarshal(t *testing.T) {
 		}
 	}
 }
+
+func TestBlockNumberOrHashJSONUnmarshal(t *testing.T) {
+	hash := common.HexToHash("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+	tests := []struct {
+		input    string
+		mustFail bool
+		expected BlockNumberOrHash
+	}{
+		0:  {`"0x"`, true, BlockNumberOrHash{}},
+		1:  {`"0x0"`, false, BlockNumberOrHashWithNumber(0)},
+		2:  {`"0x12"`, false, BlockNumberOrHashWithNumber(18)},
+		3:  {`"0x8000000000000000"`, true, BlockNumberOrHash{}},
+		4:  {`"pending"`, false, BlockNumberOrHashWithNumber(PendingBlockNumber)},
+		5:  {`"latest"`, false, BlockNumberOrHashWithNumber(LatestBlockNumber)},
+		6:  {`"earliest"`, false, BlockNumberOrHashWithNumber(EarliestBlockNumber)},
+		7:  {`someString`, true, BlockNumberOrHash{}},
+		8:  {`"` + hash.Hex() + `"`, false, BlockNumberOrHashWithHash(hash, false)},
+		9:  {`"0x1234"`, false, BlockNumberOrHashWithNumber(0x1234)},
+		10: {`{"blockNumber":"0x12"}`, false, BlockNumberOrHashWithNumber(18)},
+		11: {`{"blockNumber":"latest"}`, false, BlockNumberOrHashWithNumber(LatestBlockNumber)},
+		12: {`{"blockHash":"` + hash.Hex() + `"}`, false, BlockNumberOrHashWithHash(hash, false)},
+		13: {`{"blockHash":"` + hash.Hex() + `","requireCanonical":true}`, false, BlockNumberOrHashWithHash(hash, true)},
+		14: {`{"blockNumber":"0x1","blockHash":"` + hash.Hex() + `"}`, true, BlockNumberOrHash{}},
+		15: {`{}`, true, BlockNumberOrHash{}},
+	}
+
+	for i, test := range tests {
+		var bnh BlockNumberOrHash
+		err := json.Unmarshal([]byte(test.input), &bnh)
+		if test.mustFail && err == nil {
+			t.Errorf("Test %d should fail", i)
+			continue
+		}
+		if !test.mustFail && err != nil {
+			t.Errorf("Test %d should pass but got err: %v", i, err)
+			continue
+		}
+		if test.mustFail {
+			continue
+		}
+		if bnh.String() != test.expected.String() || bnh.RequireCanonical != test.expected.RequireCanonical {
+			t.Errorf("Test %d got unexpected value, want %v, got %v", i, test.expected, bnh)
+		}
+	}
+}
@@ -40,7 +40,12 @@ func (srv *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 	return websocket.Server{
 		Handshake: wsHandshakeValidator(allowedOrigins),
 		Handler: func(conn *websocket.Conn) {
-			srv.ServeCodec(NewJSONCodec(conn), OptionMethodInvocation|OptionSubscriptions)
+			codec := NewJSONCodec(conn)
+			defer codec.Close()
+
+			ctx := context.WithValue(context.Background(), remoteAddrKey{}, conn.Request().RemoteAddr)
+			ctx = context.WithValue(ctx, authTokenKey{}, BearerToken(conn.Request().Header.Get("Authorization")))
+			srv.serveRequest(ctx, codec, false, OptionMethodInvocation|OptionSubscriptions)
 		},
 	}
 }
@@ -52,6 +57,15 @@ func NewWSServer(allowedOrigins []string, srv *Server) *http.Server {
 	return &http.Server{Handler: srv.WebsocketHandler(allowedOrigins)}
 }
 
+// NewWSServerWithTimeout is like NewWSServer, but closes connections that do not
+// complete the websocket handshake within the given timeout. Established
+// connections are long lived and have no deadline.
+func NewWSServerWithTimeout(allowedOrigins []string, handshakeTimeout time.Duration, srv *Server) *http.Server {
+	server := NewWSServer(allowedOrigins, srv)
+	server.ReadHeaderTimeout = handshakeTimeout
+	return server
+}
+
 // wsHandshakeValidator returns a handler that verifies the origin during the
 // websocket upgrade process. When a '*' is specified as an allowed origins all
 // connections are accepted.
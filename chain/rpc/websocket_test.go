@@ -0,0 +1,69 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// Tests that the handshake timeout drops clients that never complete the
+// websocket upgrade, but does not limit the lifetime of established connections.
+func TestWebsocketHandshakeTimeout(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+
+	srv := newTestServer("service", new(Service))
+	defer srv.Stop()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("can't listen:", err)
+	}
+	defer listener.Close()
+	go NewWSServerWithTimeout([]string{"*"}, timeout, srv).Serve(listener)
+
+	// A client sending nothing is disconnected
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal("can't dial:", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Fatal("stalled handshake not dropped")
+	} else if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		t.Fatal("stalled handshake not dropped within 5s")
+	}
+
+	// An established connection outlives the timeout
+	client, err := DialWebsocket(context.Background(), "ws://"+listener.Addr().String(), "http://localhost")
+	if err != nil {
+		t.Fatal("can't dial websocket:", err)
+	}
+	defer client.Close()
+
+	time.Sleep(3 * timeout)
+	var result Result
+	if err := client.Call(&result, "service_echo", "hello", 10, &Args{"world"}); err != nil {
+		t.Fatal("call on established connection failed:", err)
+	}
+	if result.String != "hello" || result.Int != 10 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
@@ -0,0 +1,652 @@
+//tinaclient包提供Tina链扩展RPC接口(股权、文字/数据、Dpos查询、Boker系统交易以及链上治理)的类型化客户端,
+//标准的以太坊接口以及已有的扩展接口通过内嵌的ethclient.Client提供。
+package tinaclient
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/Tinachain/Tina/chain"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/ethclient"
+	"github.com/Tinachain/Tina/chain/rlp"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+//Tina链RPC接口的类型化客户端
+type Client struct {
+	*ethclient.Client
+	c *rpc.Client
+}
+
+//连接到指定URL的节点
+func Dial(rawurl string) (*Client, error) {
+	c, err := rpc.Dial(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(c), nil
+}
+
+//使用已有的RPC连接创建客户端
+func NewClient(c *rpc.Client) *Client {
+	return &Client{ethclient.NewClient(c), c}
+}
+
+//关闭底层的RPC连接
+func (tc *Client) Close() {
+	tc.c.Close()
+}
+
+func toBlockNumArg(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+	return hexutil.EncodeBig(number)
+}
+
+//交易
+
+//得到包含Tina扩展字段的交易, 交易不存在时返回nil
+func (tc *Client) TinaTransactionByHash(ctx context.Context, hash common.Hash) (*Transaction, error) {
+	var tx *Transaction
+	err := tc.c.CallContext(ctx, &tx, "boker_getTransactionByHash", hash)
+	return tx, err
+}
+
+//得到区块范围内Name字段等于name的交易(需要节点开启--nameindex), 节点开启eth兼容输出时不包含Tina扩展字段
+func (tc *Client) TransactionsByName(ctx context.Context, name string, from, to *big.Int) ([]*Transaction, error) {
+	var txs []*Transaction
+	err := tc.c.CallContext(ctx, &txs, "eth_getTransactionsByName", name, toBlockNumArg(from), toBlockNumArg(to))
+	return txs, err
+}
+
+//由节点解码原始交易(不提交), 用于校验客户端对扩展交易格式的编码
+func (tc *Client) DecodeRawTransaction(ctx context.Context, raw []byte) (*DecodedTransaction, error) {
+	var tx *DecodedTransaction
+	err := tc.c.CallContext(ctx, &tx, "eth_decodeRawTransaction", hexutil.Bytes(raw))
+	return tx, err
+}
+
+//由节点构造未签名的Boker交易, 签名后通过SendTransaction提交
+func (tc *Client) BuildSystemTx(ctx context.Context, args BuildTxArgs) (*UnsignedTransaction, error) {
+	var tx *UnsignedTransaction
+	err := tc.c.CallContext(ctx, &tx, "boker_buildSystemTx", args)
+	return tx, err
+}
+
+//在节点的当前区块之上试执行Boker交易(不签名也不提交), 交易会被拒绝时返回带有错误码的错误
+func (tc *Client) ValidateTransaction(ctx context.Context, args BuildTxArgs) (*TxValidation, error) {
+	var result *TxValidation
+	err := tc.c.CallContext(ctx, &result, "boker_validateTransaction", args)
+	return result, err
+}
+
+//使用节点上已解锁的from账号发送批量转账交易
+func (tc *Client) MultiSend(ctx context.Context, from common.Address, recipients []MultiSendRecipient) (common.Hash, error) {
+	var txHash common.Hash
+	err := tc.c.CallContext(ctx, &txHash, "boker_multiSend", from, recipients)
+	return txHash, err
+}
+
+//由节点构造未签名的批量转账交易, nonce为nil时使用交易池中的下一个Nonce
+func (tc *Client) BuildMultiSend(ctx context.Context, from common.Address, recipients []MultiSendRecipient, nonce *uint64) (*UnsignedTransaction, error) {
+	var tx *UnsignedTransaction
+	err := tc.c.CallContext(ctx, &tx, "boker_buildMultiSend", from, recipients, (*hexutil.Uint64)(nonce))
+	return tx, err
+}
+
+//使用节点上已解锁的payer账号为args中from账号的交易代付Gas, 返回填充了Nonce、Gas价格以及代付签名的参数
+func (tc *Client) SponsorTransaction(ctx context.Context, payer common.Address, args SponsoredTxArgs) (*SponsoredTxArgs, error) {
+	var sponsored *SponsoredTxArgs
+	err := tc.c.CallContext(ctx, &sponsored, "eth_sponsorTransaction", payer, args)
+	return sponsored, err
+}
+
+//使用节点上已解锁的from账号签名并发送代付交易
+func (tc *Client) SendSponsoredTransaction(ctx context.Context, args SponsoredTxArgs) (common.Hash, error) {
+	var txHash common.Hash
+	err := tc.c.CallContext(ctx, &txHash, "eth_sendTransaction", args)
+	return txHash, err
+}
+
+//提交已签名的定时交易, 交易在区块高度达到block之前保存在节点的交易池中, 既不广播也不打包
+func (tc *Client) ScheduleTransaction(ctx context.Context, tx *types.Transaction, block uint64) (common.Hash, error) {
+	data, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var txHash common.Hash
+	err = tc.c.CallContext(ctx, &txHash, "eth_scheduleRawTransaction", hexutil.Bytes(data), hexutil.Uint64(block))
+	return txHash, err
+}
+
+//立即向节点的所有相邻节点重新广播交易池中的交易, 交易不在交易池中时返回false
+func (tc *Client) RebroadcastTransaction(ctx context.Context, hash common.Hash) (bool, error) {
+	var ok bool
+	err := tc.c.CallContext(ctx, &ok, "eth_rebroadcastTransaction", hash)
+	return ok, err
+}
+
+//得到节点交易池中还没有生效的定时交易
+func (tc *Client) ScheduledTransactions(ctx context.Context) ([]*ScheduledTransaction, error) {
+	var scheduled []*ScheduledTransaction
+	err := tc.c.CallContext(ctx, &scheduled, "txpool_scheduled")
+	return scheduled, err
+}
+
+//得到节点交易池中的交易及其状态, 交易不在交易池中时返回nil
+func (tc *Client) PoolTransaction(ctx context.Context, hash common.Hash) (*PoolTransaction, error) {
+	var tx *PoolTransaction
+	err := tc.c.CallContext(ctx, &tx, "txpool_getTransaction", hash)
+	return tx, err
+}
+
+//得到节点交易池中账号Nonce在[from, to]范围内的交易, 按Nonce排序
+func (tc *Client) PoolTransactionsByNonce(ctx context.Context, account common.Address, from, to uint64) ([]*PoolTransaction, error) {
+	var txs []*PoolTransaction
+	err := tc.c.CallContext(ctx, &txs, "txpool_contentForNonceRange", account, hexutil.Uint64(from), hexutil.Uint64(to))
+	return txs, err
+}
+
+//由节点导出未签名的Boker交易的离线交易包, 在离线机器上签名后通过ImportOfflineSignature得到最终的交易
+func (tc *Client) ExportOfflineTx(ctx context.Context, args BuildTxArgs) (*OfflineTx, error) {
+	var tx *OfflineTx
+	err := tc.c.CallContext(ctx, &tx, "boker_exportOfflineTx", args)
+	return tx, err
+}
+
+//解码离线交易包, 用于签名前核对交易内容
+func (tc *Client) DecodeOfflineTx(ctx context.Context, payload string) (*OfflineTx, error) {
+	var tx *OfflineTx
+	err := tc.c.CallContext(ctx, &tx, "boker_decodeOfflineTx", payload)
+	return tx, err
+}
+
+//将离线签名[R || S || V]写入离线交易包中的交易, 返回的Raw解码后通过SendTransaction提交
+func (tc *Client) ImportOfflineSignature(ctx context.Context, payload string, signature []byte) (*SignedOfflineTx, error) {
+	var tx *SignedOfflineTx
+	err := tc.c.CallContext(ctx, &tx, "boker_importOfflineSignature", payload, hexutil.Bytes(signature))
+	return tx, err
+}
+
+//得到包含Tina扩展字段和执行错误信息的交易收据, 交易不存在时返回nil
+func (tc *Client) TinaTransactionReceipt(ctx context.Context, hash common.Hash) (*Receipt, error) {
+	var receipt *Receipt
+	err := tc.c.CallContext(ctx, &receipt, "boker_getTransactionReceipt", hash)
+	return receipt, err
+}
+
+//得到包含Tina扩展字段的区块, number为nil时返回最新区块, 区块不存在时返回nil
+func (tc *Client) TinaBlockByNumber(ctx context.Context, number *big.Int, fullTx bool) (*Block, error) {
+	var block *Block
+	err := tc.c.CallContext(ctx, &block, "boker_getBlockByNumber", toBlockNumArg(number), fullTx)
+	return block, err
+}
+
+//得到包含Tina扩展字段的区块, 区块不存在时返回nil
+func (tc *Client) TinaBlockByHash(ctx context.Context, hash common.Hash, fullTx bool) (*Block, error) {
+	var block *Block
+	err := tc.c.CallContext(ctx, &block, "boker_getBlockByHash", hash, fullTx)
+	return block, err
+}
+
+//得到区块哈希对应的区块高度, 区块不存在时返回nil
+func (tc *Client) BlockNumberByHash(ctx context.Context, hash common.Hash) (*uint64, error) {
+	var number *hexutil.Uint64
+	if err := tc.c.CallContext(ctx, &number, "eth_getBlockNumberByHash", hash); err != nil || number == nil {
+		return nil, err
+	}
+	n := uint64(*number)
+	return &n, nil
+}
+
+//按区块哈希查询状态的参数, requireCanonical为true时区块不在主链上(已被分叉替换)返回错误
+func toBlockHashArg(hash common.Hash, requireCanonical bool) rpc.BlockNumberOrHash {
+	return rpc.BlockNumberOrHashWithHash(hash, requireCanonical)
+}
+
+//得到账号在指定哈希的区块状态中的余额
+func (tc *Client) BalanceAtHash(ctx context.Context, account common.Address, blockHash common.Hash, requireCanonical bool) (*big.Int, error) {
+	var balance hexutil.Big
+	err := tc.c.CallContext(ctx, &balance, "eth_getBalance", account, toBlockHashArg(blockHash, requireCanonical))
+	return (*big.Int)(&balance), err
+}
+
+//得到账号在指定哈希的区块状态中的Nonce
+func (tc *Client) NonceAtHash(ctx context.Context, account common.Address, blockHash common.Hash, requireCanonical bool) (uint64, error) {
+	var nonce hexutil.Uint64
+	err := tc.c.CallContext(ctx, &nonce, "eth_getTransactionCount", account, toBlockHashArg(blockHash, requireCanonical))
+	return uint64(nonce), err
+}
+
+//得到合约在指定哈希的区块状态中的代码
+func (tc *Client) CodeAtHash(ctx context.Context, account common.Address, blockHash common.Hash, requireCanonical bool) ([]byte, error) {
+	var code hexutil.Bytes
+	err := tc.c.CallContext(ctx, &code, "eth_getCode", account, toBlockHashArg(blockHash, requireCanonical))
+	return code, err
+}
+
+//得到合约存储位置在指定哈希的区块状态中的值
+func (tc *Client) StorageAtHash(ctx context.Context, account common.Address, key common.Hash, blockHash common.Hash, requireCanonical bool) ([]byte, error) {
+	var value hexutil.Bytes
+	err := tc.c.CallContext(ctx, &value, "eth_getStorageAt", account, key.Hex(), toBlockHashArg(blockHash, requireCanonical))
+	return value, err
+}
+
+//只得到包含Tina扩展字段的区块头, 区块不存在时返回nil
+func (tc *Client) TinaHeaderByHash(ctx context.Context, hash common.Hash) (*Header, error) {
+	var header *Header
+	err := tc.c.CallContext(ctx, &header, "eth_getHeaderByHash", hash)
+	return header, err
+}
+
+//得到[from, to]范围内的精简区块头(最多1000个), to为nil时到最新区块为止
+func (tc *Client) HeadersByRange(ctx context.Context, from, to *big.Int) ([]*CompactHeader, error) {
+	var headers []*CompactHeader
+	err := tc.c.CallContext(ctx, &headers, "eth_getHeadersByRange", toBlockNumArg(from), toBlockNumArg(to))
+	return headers, err
+}
+
+//订阅新链头的精简区块头, 需要使用websocket或IPC连接
+func (tc *Client) SubscribeCompactHeads(ctx context.Context, ch chan<- *CompactHeader) (ethereum.Subscription, error) {
+	return tc.c.EthSubscribe(ctx, ch, "compactHeads")
+}
+
+//Dpos查询
+
+//得到最近一个区块的出块者
+func (tc *Client) LastProducer(ctx context.Context) (common.Address, error) {
+	var producer common.Address
+	err := tc.c.CallContext(ctx, &producer, "eth_getLastProducer")
+	return producer, err
+}
+
+//得到下一个区块的出块者
+func (tc *Client) NextProducer(ctx context.Context) (common.Address, error) {
+	var producer common.Address
+	err := tc.c.CallContext(ctx, &producer, "eth_getNextProducer")
+	return producer, err
+}
+
+//得到指定区块所在周期的验证者, number为nil时使用最新区块
+func (tc *Client) Validators(ctx context.Context, number *big.Int) ([]common.Address, error) {
+	var blob hexutil.Bytes
+	if err := tc.c.CallContext(ctx, &blob, "eth_getBlockValidator", toBlockNumArg(number)); err != nil {
+		return nil, err
+	}
+	var list struct {
+		Address []common.Address `json:"address"`
+	}
+	if err := json.Unmarshal(blob, &list); err != nil {
+		return nil, err
+	}
+	return list.Address, nil
+}
+
+//Boker系统交易(使用节点的挖矿账号提交, 返回交易哈希)
+
+//确认待生效的系统基础合约变更
+func (tc *Client) ConfirmSystemBaseContracts(ctx context.Context, address common.Address) (common.Hash, error) {
+	var txHash common.Hash
+	err := tc.c.CallContext(ctx, &txHash, "eth_confirmSystemBaseContracts", address)
+	return txHash, err
+}
+
+//将账号加入部署合约的白名单
+func (tc *Client) ApproveDeployer(ctx context.Context, address common.Address) (common.Hash, error) {
+	var txHash common.Hash
+	err := tc.c.CallContext(ctx, &txHash, "eth_approveDeployer", address)
+	return txHash, err
+}
+
+//将账号移出部署合约的白名单
+func (tc *Client) RevokeDeployer(ctx context.Context, address common.Address) (common.Hash, error) {
+	var txHash common.Hash
+	err := tc.c.CallContext(ctx, &txHash, "eth_revokeDeployer", address)
+	return txHash, err
+}
+
+//设置挖矿账号(验证者)的出块签名账号
+func (tc *Client) SetSigningKey(ctx context.Context, key common.Address) (common.Hash, error) {
+	var txHash common.Hash
+	err := tc.c.CallContext(ctx, &txHash, "eth_setSigningKey", key)
+	return txHash, err
+}
+
+//使用挖矿账号(验证者)登记或更新验证者的运营信息, 信息全部为空时删除登记
+func (tc *Client) SetValidatorInfo(ctx context.Context, info ValidatorInfo) (common.Hash, error) {
+	var txHash common.Hash
+	err := tc.c.CallContext(ctx, &txHash, "eth_setValidatorInfo", info.Name, info.Website, info.Contact)
+	return txHash, err
+}
+
+//设置新的出块奖励计划
+func (tc *Client) SetRewardSchedule(ctx context.Context, reward *big.Int, gasPoolPercent uint64) (common.Hash, error) {
+	var txHash common.Hash
+	err := tc.c.CallContext(ctx, &txHash, "eth_setRewardSchedule", reward, gasPoolPercent)
+	return txHash, err
+}
+
+//登记合约的名称、Abi哈希以及源码链接
+func (tc *Client) RegisterContractMeta(ctx context.Context, args ContractMetaArgs) (common.Hash, error) {
+	var txHash common.Hash
+	err := tc.c.CallContext(ctx, &txHash, "boker_registerContractMeta", args)
+	return txHash, err
+}
+
+//Boker上下文查询(number为nil时使用最新区块)
+
+//得到指定区块的出块奖励、Gas费用以及股权分红, 没有记录时返回nil
+func (tc *Client) BlockRewards(ctx context.Context, number *big.Int) (*BlockRewards, error) {
+	var rewards *BlockRewards
+	err := tc.c.CallContext(ctx, &rewards, "boker_getBlockRewards", toBlockNumArg(number))
+	return rewards, err
+}
+
+//按时间顺序得到账号在[from, to]区块范围内的交易、内部交易、股权交易、出块奖励以及股权分红, 用于对账
+//offset和limit用于分页, limit为0时使用节点的默认值
+func (tc *Client) AccountActivity(ctx context.Context, address common.Address, from, to *big.Int, offset, limit uint64) (*AccountActivityPage, error) {
+	var page *AccountActivityPage
+	args := ActivityPage{Offset: hexutil.Uint64(offset), Limit: hexutil.Uint64(limit)}
+	err := tc.c.CallContext(ctx, &page, "boker_getAccountActivity", address, toBlockNumArg(from), toBlockNumArg(to), args)
+	return page, err
+}
+
+//得到指定区块中已注册的基础合约及其变更历史
+func (tc *Client) BaseContracts(ctx context.Context, number *big.Int) (*BaseContracts, error) {
+	var contracts *BaseContracts
+	err := tc.c.CallContext(ctx, &contracts, "boker_getBaseContracts", toBlockNumArg(number))
+	return contracts, err
+}
+
+//得到指定区块中部署合约白名单内的账号
+func (tc *Client) Deployers(ctx context.Context, number *big.Int) ([]common.Address, error) {
+	var deployers []common.Address
+	err := tc.c.CallContext(ctx, &deployers, "boker_getDeployers", toBlockNumArg(number))
+	return deployers, err
+}
+
+//得到指定区块中验证者的出块签名账号
+func (tc *Client) SigningKey(ctx context.Context, validator common.Address, number *big.Int) (common.Address, error) {
+	var key common.Address
+	err := tc.c.CallContext(ctx, &key, "boker_getSigningKey", validator, toBlockNumArg(number))
+	return key, err
+}
+
+//得到指定区块中的验证者以及每个验证者的得票数和本周期内产生的区块数量
+func (tc *Client) ValidatorsDetailed(ctx context.Context, number *big.Int) ([]*ValidatorDetail, error) {
+	var details []*ValidatorDetail
+	err := tc.c.CallContext(ctx, &details, "dpos_getValidatorsDetailed", toBlockNumArg(number))
+	return details, err
+}
+
+//得到指定区块中验证者登记的运营信息, 没有登记时返回nil
+func (tc *Client) ValidatorInfo(ctx context.Context, validator common.Address, number *big.Int) (*ValidatorInfo, error) {
+	var info *ValidatorInfo
+	err := tc.c.CallContext(ctx, &info, "dpos_getValidatorInfo", validator, toBlockNumArg(number))
+	return info, err
+}
+
+//得到指定区块中待生效的系统基础合约变更, 没有则返回nil
+func (tc *Client) PendingSystemContract(ctx context.Context, number *big.Int) (*PendingContract, error) {
+	var pending *PendingContract
+	err := tc.c.CallContext(ctx, &pending, "boker_getPendingSystemContract", toBlockNumArg(number))
+	return pending, err
+}
+
+//得到合约登记的元数据, 没有登记时返回nil
+func (tc *Client) ContractMeta(ctx context.Context, address common.Address) (*ContractMeta, error) {
+	var meta *ContractMeta
+	err := tc.c.CallContext(ctx, &meta, "boker_getContractMeta", address)
+	return meta, err
+}
+
+//在服务端按Abi编码参数并调用合约方法, 返回解码后的结果
+func (tc *Client) CallContractMethod(ctx context.Context, call ContractCall, number *big.Int) (*ContractResult, error) {
+	if call.Args == nil {
+		call.Args = []interface{}{}
+	}
+	var result *ContractResult
+	err := tc.c.CallContext(ctx, &result, "boker_callContract", call, toBlockNumArg(number))
+	return result, err
+}
+
+//链上治理
+
+//发起治理提案
+func (tc *Client) Propose(ctx context.Context, args ProposalArgs) (common.Hash, error) {
+	var txHash common.Hash
+	err := tc.c.CallContext(ctx, &txHash, "gov_propose", args)
+	return txHash, err
+}
+
+//对治理提案投票
+func (tc *Client) Vote(ctx context.Context, id uint64, approve bool) (common.Hash, error) {
+	var txHash common.Hash
+	err := tc.c.CallContext(ctx, &txHash, "gov_vote", hexutil.Uint64(id), approve)
+	return txHash, err
+}
+
+//得到指定区块中的提案
+func (tc *Client) Proposal(ctx context.Context, id uint64, number *big.Int) (*Proposal, error) {
+	var proposal *Proposal
+	err := tc.c.CallContext(ctx, &proposal, "gov_getProposal", hexutil.Uint64(id), toBlockNumArg(number))
+	return proposal, err
+}
+
+//得到指定区块中的所有提案
+func (tc *Client) Proposals(ctx context.Context, number *big.Int) ([]*Proposal, error) {
+	var proposals []*Proposal
+	err := tc.c.CallContext(ctx, &proposals, "gov_getProposals", toBlockNumArg(number))
+	return proposals, err
+}
+
+//关注账号
+
+//将账号加入节点的关注列表, 新加入时返回true
+func (tc *Client) WatchAddress(ctx context.Context, address common.Address) (bool, error) {
+	var added bool
+	err := tc.c.CallContext(ctx, &added, "eth_watchAddress", address)
+	return added, err
+}
+
+//将账号移出节点的关注列表
+func (tc *Client) UnwatchAddress(ctx context.Context, address common.Address) (bool, error) {
+	var removed bool
+	err := tc.c.CallContext(ctx, &removed, "eth_unwatchAddress", address)
+	return removed, err
+}
+
+//得到节点关注列表中的账号
+func (tc *Client) WatchedAddresses(ctx context.Context) ([]common.Address, error) {
+	var addresses []common.Address
+	err := tc.c.CallContext(ctx, &addresses, "eth_watchedAddresses")
+	return addresses, err
+}
+
+//订阅关注账号的交易、余额以及股权变化, 需要使用websocket或IPC连接
+func (tc *Client) SubscribeAddressEvents(ctx context.Context, ch chan<- *AddressEvent) (ethereum.Subscription, error) {
+	return tc.c.EthSubscribe(ctx, ch, "addressEvents")
+}
+
+//得到账号以及存储位置在指定区块状态中的Merkle证明
+func (tc *Client) Proof(ctx context.Context, account common.Address, keys []common.Hash, number *big.Int) (*AccountProof, error) {
+	storageKeys := make([]string, len(keys))
+	for i, key := range keys {
+		storageKeys[i] = key.Hex()
+	}
+	var proof *AccountProof
+	err := tc.c.CallContext(ctx, &proof, "eth_getProof", account, storageKeys, toBlockNumArg(number))
+	return proof, err
+}
+
+//跨链桥
+
+//锁定from账号中的amount跨链转出到外部链上的recipient账号
+func (tc *Client) BridgeLock(ctx context.Context, from, recipient common.Address, amount *big.Int) (common.Hash, error) {
+	var txHash common.Hash
+	err := tc.c.CallContext(ctx, &txHash, "bridge_lock", from, recipient, (*hexutil.Big)(amount))
+	return txHash, err
+}
+
+//使用节点的挖矿账号确认外部链上的销毁事件
+func (tc *Client) BridgeRelease(ctx context.Context, args BridgeReleaseArgs) (common.Hash, error) {
+	var txHash common.Hash
+	err := tc.c.CallContext(ctx, &txHash, "bridge_release", args)
+	return txHash, err
+}
+
+//得到指定区块中的锁定次数
+func (tc *Client) BridgeLockCount(ctx context.Context, number *big.Int) (uint64, error) {
+	var count hexutil.Uint64
+	err := tc.c.CallContext(ctx, &count, "bridge_getLockCount", toBlockNumArg(number))
+	return uint64(count), err
+}
+
+//得到指定区块中的锁定记录
+func (tc *Client) BridgeLockRecord(ctx context.Context, nonce uint64, number *big.Int) (*BridgeLockRecord, error) {
+	var record *BridgeLockRecord
+	err := tc.c.CallContext(ctx, &record, "bridge_getLock", hexutil.Uint64(nonce), toBlockNumArg(number))
+	return record, err
+}
+
+//得到外部链销毁事件在指定区块中的确认以及释放状态
+func (tc *Client) BridgeReleaseStatus(ctx context.Context, args BridgeReleaseArgs, number *big.Int) (*BridgeReleaseStatus, error) {
+	var status *BridgeReleaseStatus
+	err := tc.c.CallContext(ctx, &status, "bridge_getRelease", args, toBlockNumArg(number))
+	return status, err
+}
+
+//股权Gas池
+
+//使用from账号向股权Gas池注入资金
+func (tc *Client) FundGasPool(ctx context.Context, from common.Address, amount *big.Int) (common.Hash, error) {
+	var txHash common.Hash
+	err := tc.c.CallContext(ctx, &txHash, "boker_fundGasPool", from, (*hexutil.Big)(amount))
+	return txHash, err
+}
+
+//得到账号在指定区块中累计注入股权Gas池的数量
+func (tc *Client) GasPoolContribution(ctx context.Context, account common.Address, number *big.Int) (*big.Int, error) {
+	var amount hexutil.Big
+	err := tc.c.CallContext(ctx, &amount, "boker_getGasPoolContribution", account, toBlockNumArg(number))
+	return (*big.Int)(&amount), err
+}
+
+//得到指定区块中股权Gas池的数量以及所有账号累计注入的数量
+func (tc *Client) GasPoolFunding(ctx context.Context, number *big.Int) (*GasPoolFunding, error) {
+	var funding *GasPoolFunding
+	err := tc.c.CallContext(ctx, &funding, "boker_getGasPoolFunding", toBlockNumArg(number))
+	return funding, err
+}
+
+//委托奖励
+
+//使用from账号登记出块节点的佣金比例(出块节点保留的百分比)
+func (tc *Client) SetCommission(ctx context.Context, from common.Address, percent uint64) (common.Hash, error) {
+	var txHash common.Hash
+	err := tc.c.CallContext(ctx, &txHash, "delegation_setCommission", from, hexutil.Uint64(percent))
+	return txHash, err
+}
+
+//使用from账号领取全部已分配的委托奖励
+func (tc *Client) ClaimRewards(ctx context.Context, from common.Address) (common.Hash, error) {
+	var txHash common.Hash
+	err := tc.c.CallContext(ctx, &txHash, "delegation_claimRewards", from)
+	return txHash, err
+}
+
+//得到出块节点在指定区块中的佣金比例
+func (tc *Client) Commission(ctx context.Context, producer common.Address, number *big.Int) (*Commission, error) {
+	var commission *Commission
+	err := tc.c.CallContext(ctx, &commission, "delegation_getCommission", producer, toBlockNumArg(number))
+	return commission, err
+}
+
+//得到账号在指定区块中已分配以及已领取的委托奖励
+func (tc *Client) DelegationRewards(ctx context.Context, account common.Address, number *big.Int) (*DelegationRewards, error) {
+	var rewards *DelegationRewards
+	err := tc.c.CallContext(ctx, &rewards, "delegation_getRewards", account, toBlockNumArg(number))
+	return rewards, err
+}
+
+//充值地址
+
+//由扩展公钥派生子公钥序号为[start, start+count)的只读充值地址并开始跟踪, 需要连接节点的私有API
+func (tc *Client) TrackDeposits(ctx context.Context, name, xpub string, start, count uint32) (*DepositSet, error) {
+	var set *DepositSet
+	err := tc.c.CallContext(ctx, &set, "deposit_track", name, xpub, hexutil.Uint64(start), hexutil.Uint64(count))
+	return set, err
+}
+
+//停止跟踪充值地址集合
+func (tc *Client) UntrackDeposits(ctx context.Context, name string) (bool, error) {
+	var removed bool
+	err := tc.c.CallContext(ctx, &removed, "deposit_untrack", name)
+	return removed, err
+}
+
+//得到节点跟踪的充值地址集合
+func (tc *Client) DepositSets(ctx context.Context) ([]*DepositSet, error) {
+	var sets []*DepositSet
+	err := tc.c.CallContext(ctx, &sets, "deposit_list")
+	return sets, err
+}
+
+//得到充值地址集合中的地址
+func (tc *Client) DepositAddresses(ctx context.Context, name string) ([]*DepositAddress, error) {
+	var addresses []*DepositAddress
+	err := tc.c.CallContext(ctx, &addresses, "deposit_getAddresses", name)
+	return addresses, err
+}
+
+//得到充值地址集合在指定区块的余额
+func (tc *Client) DepositBalances(ctx context.Context, name string, number *big.Int) (*DepositBalances, error) {
+	var balances *DepositBalances
+	err := tc.c.CallContext(ctx, &balances, "deposit_getBalances", name, toBlockNumArg(number))
+	return balances, err
+}
+
+//得到充值地址集合在区块范围内收到的转账
+func (tc *Client) DepositIncoming(ctx context.Context, name string, from, to *big.Int) (*DepositIncoming, error) {
+	var incoming *DepositIncoming
+	err := tc.c.CallContext(ctx, &incoming, "deposit_getIncoming", name, toBlockNumArg(from), toBlockNumArg(to))
+	return incoming, err
+}
+
+//得到address持有的token代币余额(需要节点开启--tokenindex, 开启索引之前的转账不计入)
+func (tc *Client) TokenBalance(ctx context.Context, address, token common.Address) (*TokenBalance, error) {
+	var balance *TokenBalance
+	err := tc.c.CallContext(ctx, &balance, "token_getBalance", address, token)
+	return balance, err
+}
+
+//得到address在区块范围内转入或转出的代币转账
+func (tc *Client) TokenTransfers(ctx context.Context, address common.Address, from, to *big.Int) ([]*TokenTransfer, error) {
+	var transfers []*TokenTransfer
+	err := tc.c.CallContext(ctx, &transfers, "token_getTransfers", address, toBlockNumArg(from), toBlockNumArg(to))
+	return transfers, err
+}
+
+//得到同步时下载器对各节点的吞吐量估计, 节点按区块体吞吐量从高到低排列
+func (tc *Client) DownloaderStats(ctx context.Context) (*DownloaderStats, error) {
+	var stats *DownloaderStats
+	err := tc.c.CallContext(ctx, &stats, "downloader_stats")
+	return stats, err
+}
+
+//得到同步进度的详细信息(阶段、区块以及状态的导入速度和预计剩余时间)
+func (tc *Client) SyncProgressDetailed(ctx context.Context) (*SyncProgress, error) {
+	var progress *SyncProgress
+	err := tc.c.CallContext(ctx, &progress, "eth_syncProgressDetailed")
+	return progress, err
+}
+
+//订阅同步进度, 同步期间定期推送, 同步结束时再推送一次
+func (tc *Client) SubscribeSyncProgress(ctx context.Context, ch chan<- *SyncProgress) (ethereum.Subscription, error) {
+	return tc.c.EthSubscribe(ctx, ch, "syncProgress")
+}
@@ -0,0 +1,457 @@
+package tinaclient
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core/types"
+	"github.com/Tinachain/Tina/chain/crypto"
+	"github.com/Tinachain/Tina/chain/internal/ethapi"
+	"github.com/Tinachain/Tina/chain/rlp"
+	"github.com/Tinachain/Tina/chain/rpc"
+)
+
+var (
+	testAccount = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	testTxHash  = common.HexToHash("0x2000000000000000000000000000000000000000000000000000000000000002")
+	testChainId = big.NewInt(7)
+	testHeader  = &types.Header{
+		Number:     big.NewInt(16),
+		Validator:  testAccount,
+		Difficulty: big.NewInt(1),
+		GasLimit:   big.NewInt(4700000),
+		GasUsed:    big.NewInt(21000),
+		Time:       big.NewInt(1500000000),
+		BokerProto: &types.BokerBackendProto{StocksHash: common.HexToHash("0x03")},
+	}
+)
+
+//模拟boker命名空间的服务端
+type BokerService struct{}
+
+func (s *BokerService) GetBlockByNumber(number rpc.BlockNumber, fullTx bool) map[string]interface{} {
+	block := map[string]interface{}{
+		"number":    (*hexutil.Big)(hexutil.MustDecodeBig("0x10")),
+		"validator": testAccount,
+	}
+	if fullTx {
+		block["transactions"] = []interface{}{map[string]interface{}{
+			"hash":  testTxHash,
+			"major": protocol.Extra,
+			"minor": protocol.Data,
+			"name":  "data",
+		}}
+	} else {
+		block["transactions"] = []common.Hash{testTxHash}
+	}
+	return block
+}
+
+func (s *BokerService) GetTransactionReceipt(hash common.Hash) map[string]interface{} {
+	return map[string]interface{}{
+		"transactionHash": hash,
+		"major":           protocol.SystemBase,
+		"errorCode":       hexutil.Uint(2),
+		"revertReason":    "denied",
+		"logs":            []interface{}{},
+	}
+}
+
+func (s *BokerService) GetDeployers(number rpc.BlockNumber) []common.Address {
+	return []common.Address{testAccount}
+}
+
+func (s *BokerService) BuildSystemTx(args BuildTxArgs) (*UnsignedTransaction, error) {
+	tx, err := ethapi.NewBokerTransaction(args.Major, args.Minor, uint64(*args.Nonce), args.To, nil, args.Name, args.Extra, args.Encryption)
+	if err != nil {
+		return nil, err
+	}
+	raw, _ := rlp.EncodeToBytes(tx)
+	signer := types.NewEIP155Signer(testChainId)
+	return &UnsignedTransaction{Raw: raw, SigningHash: signer.Hash(tx), ChainId: (*hexutil.Big)(testChainId)}, nil
+}
+
+//模拟eth命名空间中的Dpos查询
+type EthService struct{}
+
+func (s *EthService) GetBlockValidator(number rpc.BlockNumber) (hexutil.Bytes, error) {
+	return json.Marshal(map[string]interface{}{"address": []common.Address{testAccount}})
+}
+
+func (s *EthService) GetBlockNumberByHash(hash common.Hash) *hexutil.Uint64 {
+	if hash != testHeader.Hash() {
+		return nil
+	}
+	number := hexutil.Uint64(testHeader.Number.Uint64())
+	return &number
+}
+
+//使用节点的区块头编码输出
+func (s *EthService) GetHeaderByHash(hash common.Hash) map[string]interface{} {
+	if hash != testHeader.Hash() {
+		return nil
+	}
+	return ethapi.RPCMarshalHeader(testHeader, false)
+}
+
+func (s *EthService) GetHeadersByRange(from, to rpc.BlockNumber) []*ethapi.RPCCompactHeader {
+	return []*ethapi.RPCCompactHeader{ethapi.RPCMarshalCompactHeader(testHeader)}
+}
+
+//按区块哈希查询时只认识testHeader, requireCanonical时返回不在主链上的错误
+func (s *EthService) GetBalance(address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	hash, ok := blockNrOrHash.Hash()
+	if !ok || hash != testHeader.Hash() {
+		return nil, nil
+	}
+	if blockNrOrHash.RequireCanonical {
+		return nil, protocol.ErrNonCanonicalBlock
+	}
+	return (*hexutil.Big)(big.NewInt(42)), nil
+}
+
+func (s *EthService) WatchAddress(address common.Address) bool {
+	return address == testAccount
+}
+
+//订阅后推送关注账号的交易事件
+func (s *EthService) AddressEvents(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		hash, from := testTxHash, testAccount
+		event := map[string]interface{}{
+			"type":            "transaction",
+			"address":         testAccount,
+			"blockNumber":     hexutil.Uint64(16),
+			"transactionHash": &hash,
+			"direction":       "out",
+			"from":            &from,
+			"value":           (*hexutil.Big)(big.NewInt(5)),
+		}
+		//订阅在返回结果之后才生效, 因此定时推送直到取消订阅
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				notifier.Notify(sub.ID, event)
+			case <-sub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+func newTestClient(t *testing.T) *Client {
+	server := rpc.NewServer()
+	if err := server.RegisterName("boker", new(BokerService)); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	if err := server.RegisterName("eth", new(EthService)); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	return NewClient(rpc.DialInProc(server))
+}
+
+//测试包含Tina扩展字段的区块能够按交易或哈希列表解码
+func TestTinaBlock(t *testing.T) {
+	client := newTestClient(t)
+	defer client.Close()
+
+	block, err := client.TinaBlockByNumber(context.Background(), nil, true)
+	if err != nil {
+		t.Fatalf("failed to get block: %v", err)
+	}
+	if block.Validator == nil || *block.Validator != testAccount {
+		t.Errorf("validator mismatch: have %v, want %x", block.Validator, testAccount)
+	}
+	if len(block.Transactions) != 1 || block.Transactions[0].Minor != protocol.Data || block.Transactions[0].Name != "data" {
+		t.Fatalf("transactions mismatch: have %+v", block.Transactions)
+	}
+	if len(block.TxHashes) != 1 || block.TxHashes[0] != testTxHash {
+		t.Errorf("transaction hashes mismatch: have %x", block.TxHashes)
+	}
+
+	block, err = client.TinaBlockByNumber(context.Background(), nil, false)
+	if err != nil {
+		t.Fatalf("failed to get block: %v", err)
+	}
+	if len(block.Transactions) != 0 || len(block.TxHashes) != 1 || block.TxHashes[0] != testTxHash {
+		t.Errorf("transaction hashes mismatch: have %x (%d transactions)", block.TxHashes, len(block.Transactions))
+	}
+}
+
+//测试收据、白名单以及验证者的解码
+func TestTinaQueries(t *testing.T) {
+	client := newTestClient(t)
+	defer client.Close()
+	ctx := context.Background()
+
+	receipt, err := client.TinaTransactionReceipt(ctx, testTxHash)
+	if err != nil {
+		t.Fatalf("failed to get receipt: %v", err)
+	}
+	if receipt.TransactionHash != testTxHash || receipt.Major != protocol.SystemBase {
+		t.Errorf("receipt mismatch: have %+v", receipt)
+	}
+	if receipt.ErrorCode == nil || *receipt.ErrorCode != 2 || receipt.RevertReason != "denied" {
+		t.Errorf("receipt error mismatch: have %v %q", receipt.ErrorCode, receipt.RevertReason)
+	}
+
+	deployers, err := client.Deployers(ctx, nil)
+	if err != nil || len(deployers) != 1 || deployers[0] != testAccount {
+		t.Errorf("deployers mismatch: have %x, %v", deployers, err)
+	}
+	validators, err := client.Validators(ctx, nil)
+	if err != nil || len(validators) != 1 || validators[0] != testAccount {
+		t.Errorf("validators mismatch: have %x, %v", validators, err)
+	}
+}
+
+//测试节点解码原始交易并恢复发送者
+func TestDecodeRawTransaction(t *testing.T) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", ethapi.NewPublicTransactionPoolAPI(nil, nil)); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	client := NewClient(rpc.DialInProc(server))
+	defer client.Close()
+
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	tx := types.NewExtraTransaction(protocol.Extra, protocol.Data, 1, testAccount, big.NewInt(0), big.NewInt(21000), big.NewInt(1), []byte("name"), []byte("payload"), 0)
+
+	tests := []struct {
+		signer    types.Signer
+		signed    bool
+		protected bool
+	}{
+		{nil, false, false},
+		{types.HomesteadSigner{}, true, false},
+		{types.NewEIP155Signer(big.NewInt(7)), true, true},
+	}
+	for i, tt := range tests {
+		signed := tx
+		if tt.signer != nil {
+			var err error
+			if signed, err = types.SignTx(tx, tt.signer, key); err != nil {
+				t.Fatalf("test %d: failed to sign transaction: %v", i, err)
+			}
+		}
+		raw, _ := rlp.EncodeToBytes(signed)
+		decoded, err := client.DecodeRawTransaction(context.Background(), raw)
+		if err != nil {
+			t.Fatalf("test %d: failed to decode transaction: %v", i, err)
+		}
+		if decoded.Signed != tt.signed || decoded.Protected != tt.protected {
+			t.Errorf("test %d: signature mismatch: have signed %v protected %v, want %v %v", i, decoded.Signed, decoded.Protected, tt.signed, tt.protected)
+		}
+		want := common.Address{}
+		if tt.signed {
+			want = sender
+		}
+		if decoded.From != want {
+			t.Errorf("test %d: sender mismatch: have %x, want %x", i, decoded.From, want)
+		}
+		if decoded.Error != "" {
+			t.Errorf("test %d: unexpected error: %s", i, decoded.Error)
+		}
+		if decoded.Hash != signed.Hash() || decoded.Minor != protocol.Data || string(decoded.Extra) != "payload" || decoded.Name != "name" {
+			t.Errorf("test %d: transaction mismatch: have %+v", i, decoded.Transaction)
+		}
+	}
+	if _, err := client.DecodeRawTransaction(context.Background(), []byte{0x01, 0x02}); err == nil {
+		t.Errorf("invalid encoding decoded")
+	}
+}
+
+//测试按signingHash离线签名的交易与节点使用EIP155签名的交易一致
+func TestBuildSystemTx(t *testing.T) {
+	client := newTestClient(t)
+	defer client.Close()
+
+	nonce := hexutil.Uint64(3)
+	args := BuildTxArgs{Major: protocol.SystemBase, Minor: protocol.SetValidator, Nonce: &nonce, To: testAccount, Extra: []byte("payload")}
+	unsigned, err := client.BuildSystemTx(context.Background(), args)
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(unsigned.Raw, tx); err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+	if tx.Major() != protocol.SystemBase || tx.Nonce() != 3 || string(tx.Data()) != "payload" {
+		t.Fatalf("transaction mismatch: have %v", tx)
+	}
+
+	key, _ := crypto.GenerateKey()
+	sig, err := crypto.Sign(unsigned.SigningHash[:], key)
+	if err != nil {
+		t.Fatalf("failed to sign hash: %v", err)
+	}
+	signer := types.NewEIP155Signer((*big.Int)(unsigned.ChainId))
+	signed, err := tx.WithSignature(signer, sig)
+	if err != nil {
+		t.Fatalf("failed to apply signature: %v", err)
+	}
+	want, _ := types.SignTx(tx, signer, key)
+	if signed.Hash() != want.Hash() {
+		t.Errorf("signed transaction mismatch: have %x, want %x", signed.Hash(), want.Hash())
+	}
+	if from, err := types.Sender(signer, signed); err != nil || from != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("sender mismatch: have %x, %v", from, err)
+	}
+}
+
+//测试离线交易包在离线机器上签名后能够得到与直接签名一致的交易, 并拒绝其他账号的签名
+func TestOfflineTx(t *testing.T) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("boker", ethapi.NewPublicBokerAPI(nil)); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	client := NewClient(rpc.DialInProc(server))
+	defer client.Close()
+
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	tx := types.NewStockTransaction(protocol.Stock, protocol.StockSet, 4, testAccount, big.NewInt(10), big.NewInt(90000), big.NewInt(50), []byte("name"), []byte("payload"), 0)
+	payload, err := ethapi.EncodeOfflineTx(&ethapi.OfflineTx{ChainId: testChainId, From: from, Tx: tx})
+	if err != nil {
+		t.Fatalf("failed to encode package: %v", err)
+	}
+
+	decoded, err := client.DecodeOfflineTx(context.Background(), payload)
+	if err != nil {
+		t.Fatalf("failed to decode package: %v", err)
+	}
+	signer := types.NewEIP155Signer(testChainId)
+	if decoded.SigningHash != signer.Hash(tx) || decoded.ChainId.ToInt().Cmp(testChainId) != 0 || decoded.Tx.From != from || decoded.Tx.Minor != protocol.StockSet {
+		t.Fatalf("package mismatch: have %+v", decoded)
+	}
+
+	sig, _ := crypto.Sign(decoded.SigningHash[:], key)
+	sig[64] += 27
+	signed, err := client.ImportOfflineSignature(context.Background(), payload, sig)
+	if err != nil {
+		t.Fatalf("failed to import signature: %v", err)
+	}
+	want, _ := types.SignTx(tx, signer, key)
+	if signed.Hash != want.Hash() || signed.From != from {
+		t.Errorf("signed transaction mismatch: have %x, want %x", signed.Hash, want.Hash())
+	}
+
+	other, _ := crypto.GenerateKey()
+	sig, _ = crypto.Sign(decoded.SigningHash[:], other)
+	if _, err := client.ImportOfflineSignature(context.Background(), payload, sig); err == nil {
+		t.Errorf("signature of wrong account imported")
+	}
+	if _, err := client.DecodeOfflineTx(context.Background(), payload[:len(payload)-2]+"AA"); err == nil {
+		t.Errorf("corrupted package decoded")
+	}
+}
+
+//测试关注账号并接收推送的事件
+func TestAddressEvents(t *testing.T) {
+	client := newTestClient(t)
+	defer client.Close()
+
+	if added, err := client.WatchAddress(context.Background(), testAccount); err != nil || !added {
+		t.Fatalf("failed to watch address: %v", err)
+	}
+	events := make(chan *AddressEvent)
+	sub, err := client.SubscribeAddressEvents(context.Background(), events)
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	select {
+	case event := <-events:
+		if event.Type != "transaction" || event.Address != testAccount || uint64(event.BlockNumber) != 16 {
+			t.Errorf("event mismatch: have %+v", event)
+		}
+		if event.TransactionHash == nil || *event.TransactionHash != testTxHash || event.Direction != "out" {
+			t.Errorf("transaction mismatch: have %+v", event)
+		}
+		if event.Value == nil || event.Value.ToInt().Int64() != 5 {
+			t.Errorf("value mismatch: have %v", event.Value)
+		}
+	case err := <-sub.Err():
+		t.Fatalf("subscription failed: %v", err)
+	case <-time.After(time.Second):
+		t.Fatalf("no event received")
+	}
+}
+
+//测试按哈希查询区块高度以及只查询区块头
+func TestHeaderByHash(t *testing.T) {
+	client := newTestClient(t)
+	defer client.Close()
+
+	number, err := client.BlockNumberByHash(context.Background(), testHeader.Hash())
+	if err != nil || number == nil || *number != 16 {
+		t.Fatalf("number mismatch: have %v, %v", number, err)
+	}
+	if number, err := client.BlockNumberByHash(context.Background(), testTxHash); err != nil || number != nil {
+		t.Errorf("unknown block returned %v, %v", number, err)
+	}
+
+	header, err := client.TinaHeaderByHash(context.Background(), testHeader.Hash())
+	if err != nil || header == nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+	if header.Hash != testHeader.Hash() || header.Validator != testAccount || header.BokerBackend.StocksHash != testHeader.BokerProto.StocksHash {
+		t.Errorf("header mismatch: have %+v", header)
+	}
+	if header, err := client.TinaHeaderByHash(context.Background(), testTxHash); err != nil || header != nil {
+		t.Errorf("unknown header returned %v, %v", header, err)
+	}
+}
+
+//测试批量获取精简区块头
+func TestHeadersByRange(t *testing.T) {
+	client := newTestClient(t)
+	defer client.Close()
+
+	headers, err := client.HeadersByRange(context.Background(), big.NewInt(16), nil)
+	if err != nil {
+		t.Fatalf("failed to get headers: %v", err)
+	}
+	if len(headers) != 1 {
+		t.Fatalf("header count mismatch: have %d, want 1", len(headers))
+	}
+	header := headers[0]
+	if uint64(header.Number) != 16 || header.Hash != testHeader.Hash() || header.Validator != testAccount {
+		t.Errorf("header mismatch: have %+v", header)
+	}
+	if header.BokerBackend.StocksHash != testHeader.BokerProto.StocksHash || header.DposContext != (types.DposContextProto{}) {
+		t.Errorf("context roots mismatch: have %+v, %+v", header.BokerBackend, header.DposContext)
+	}
+}
+
+//测试按区块哈希查询状态的参数编码
+func TestBalanceAtHash(t *testing.T) {
+	client := newTestClient(t)
+	defer client.Close()
+
+	balance, err := client.BalanceAtHash(context.Background(), testAccount, testHeader.Hash(), false)
+	if err != nil || balance.Cmp(big.NewInt(42)) != 0 {
+		t.Fatalf("balance mismatch: have %v, %v", balance, err)
+	}
+	if _, err := client.BalanceAtHash(context.Background(), testAccount, testHeader.Hash(), true); err == nil || err.Error() != protocol.ErrNonCanonicalBlock.Error() {
+		t.Errorf("non-canonical error mismatch: have %v", err)
+	}
+}
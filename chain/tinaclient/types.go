@@ -0,0 +1,589 @@
+package tinaclient
+
+import (
+	"encoding/json"
+
+	"github.com/Tinachain/Tina/chain/boker/protocol"
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/common/hexutil"
+	"github.com/Tinachain/Tina/chain/core/types"
+)
+
+//包含Tina扩展字段的交易(boker_getTransactionByHash)
+type Transaction struct {
+	Major            protocol.TxMajor `json:"major"`
+	MajorNotes       string           `json:"majorNotes"`
+	Minor            protocol.TxMinor `json:"minor"`
+	MinorNotes       string           `json:"minorNotes"`
+	BlockHash        common.Hash      `json:"blockHash"`
+	BlockNumber      *hexutil.Big     `json:"blockNumber"` //交易还在交易池中时为空
+	Time             string           `json:"timestamp"`
+	From             common.Address   `json:"from"`
+	Gas              *hexutil.Big     `json:"gas"`
+	GasPrice         *hexutil.Big     `json:"gasPrice"`
+	Hash             common.Hash      `json:"hash"`
+	Input            hexutil.Bytes    `json:"input"`
+	Name             string           `json:"name"`
+	Encryption       uint8            `json:"encryption"`
+	Extra            hexutil.Bytes    `json:"extra"`
+	Ip               string           `json:"ip"`
+	Nonce            hexutil.Uint64   `json:"nonce"`
+	To               *common.Address  `json:"to"`
+	TransactionIndex hexutil.Uint     `json:"transactionIndex"`
+	Value            *hexutil.Big     `json:"value"`
+	V                *hexutil.Big     `json:"v"`
+	R                *hexutil.Big     `json:"r"`
+	S                *hexutil.Big     `json:"s"`
+}
+
+//解码后的原始交易(eth_decodeRawTransaction)
+type DecodedTransaction struct {
+	Transaction
+	Signed    bool         `json:"signed"`            //是否已签名(未签名时from为空)
+	Protected bool         `json:"protected"`         //是否使用EIP155签名
+	ChainId   *hexutil.Big `json:"chainId,omitempty"` //EIP155签名中的链ID
+	Error     string       `json:"error,omitempty"`   //签名无法恢复或交易类型校验失败的原因
+}
+
+//构造离线签名的Boker交易的参数(boker_buildSystemTx)
+type BuildTxArgs struct {
+	From       common.Address   `json:"from"`
+	Major      protocol.TxMajor `json:"major"`
+	Minor      protocol.TxMinor `json:"minor"`
+	Nonce      *hexutil.Uint64  `json:"nonce,omitempty"` //为空时使用交易池中的下一个Nonce
+	To         common.Address   `json:"to"`
+	Value      *hexutil.Big     `json:"value,omitempty"`
+	Name       hexutil.Bytes    `json:"name"`
+	Extra      hexutil.Bytes    `json:"extra"`
+	Encryption uint8            `json:"encryption"`
+}
+
+//未签名的Boker交易
+type UnsignedTransaction struct {
+	Tx          *Transaction  `json:"tx"`
+	Raw         hexutil.Bytes `json:"raw"`         //未签名交易的RLP编码
+	SigningHash common.Hash   `json:"signingHash"` //需要签名的哈希
+	ChainId     *hexutil.Big  `json:"chainId"`     //为空时使用V为27/28的签名
+}
+
+//Boker交易试执行的结果, Reverted为交易可以被打包但合约执行失败
+type TxValidation struct {
+	Valid    bool         `json:"valid"`
+	Reverted bool         `json:"reverted"`
+	GasUsed  *hexutil.Big `json:"gasUsed"`
+}
+
+//批量转账中的一笔转账
+type MultiSendRecipient struct {
+	To    common.Address `json:"to"`
+	Value *hexutil.Big   `json:"value"`
+}
+
+//由代付账号支付Gas的交易参数(eth_sponsorTransaction), 可以直接通过eth_sendTransaction提交
+type SponsoredTxArgs struct {
+	From        common.Address  `json:"from"`
+	To          *common.Address `json:"to"`
+	Gas         *hexutil.Big    `json:"gas"`
+	GasPrice    *hexutil.Big    `json:"gasPrice"`
+	Value       *hexutil.Big    `json:"value"`
+	Data        hexutil.Bytes   `json:"data"`
+	Nonce       *hexutil.Uint64 `json:"nonce"`
+	Sponsorship hexutil.Bytes   `json:"sponsorship"` //代付账号的签名
+}
+
+//还没有生效的定时交易
+type ScheduledTransaction struct {
+	Block hexutil.Uint64 `json:"block"`
+	Tx    *Transaction   `json:"tx"`
+}
+
+//交易池中的交易及其状态(txpool_getTransaction)
+type PoolTransaction struct {
+	Transaction
+	Status     string         `json:"status"`     //pending或queued
+	AddedAt    hexutil.Uint64 `json:"addedAt"`    //加入交易池的时间(Unix秒)
+	TimeInPool hexutil.Uint64 `json:"timeInPool"` //在交易池中停留的秒数
+}
+
+//离线交易包(boker_exportOfflineTx), Payload可以保存为文件或者生成二维码
+type OfflineTx struct {
+	Payload     string       `json:"payload"`
+	Tx          *Transaction `json:"tx"`
+	SigningHash common.Hash  `json:"signingHash"`
+	ChainId     *hexutil.Big `json:"chainId"`
+}
+
+//写入离线签名后的交易
+type SignedOfflineTx struct {
+	Raw  hexutil.Bytes  `json:"raw"`
+	Hash common.Hash    `json:"hash"`
+	From common.Address `json:"from"`
+}
+
+//包含Tina扩展字段和执行错误信息的交易收据(boker_getTransactionReceipt)
+type Receipt struct {
+	BlockHash         common.Hash      `json:"blockHash"`
+	BlockNumber       hexutil.Uint64   `json:"blockNumber"`
+	TransactionHash   common.Hash      `json:"transactionHash"`
+	TransactionIndex  hexutil.Uint64   `json:"transactionIndex"`
+	Major             protocol.TxMajor `json:"major"`
+	Minor             protocol.TxMinor `json:"minor"`
+	From              common.Address   `json:"from"`
+	To                *common.Address  `json:"to"`
+	Extra             hexutil.Bytes    `json:"extra"`
+	Ip                string           `json:"ip"`
+	GasUsed           *hexutil.Big     `json:"gasUsed"`
+	CumulativeGasUsed *hexutil.Big     `json:"cumulativeGasUsed"`
+	ContractAddress   *common.Address  `json:"contractAddress"`
+	Logs              []*types.Log     `json:"logs"`
+	LogsBloom         types.Bloom      `json:"logsBloom"`
+	Root              hexutil.Bytes    `json:"root,omitempty"`         //拜占庭分叉之前的状态根
+	Status            *hexutil.Uint    `json:"status,omitempty"`       //拜占庭分叉之后的执行状态
+	ErrorCode         *hexutil.Uint    `json:"errorCode,omitempty"`    //执行失败时的错误码
+	RevertReason      string           `json:"revertReason,omitempty"` //合约回滚的原因
+}
+
+//包含Tina扩展字段(验证者、Dpos和Boker上下文)的区块(boker_getBlockByNumber/boker_getBlockByHash)
+type Block struct {
+	Number           *hexutil.Big            `json:"number"`
+	Hash             *common.Hash            `json:"hash"` //待打包区块为空
+	ParentHash       common.Hash             `json:"parentHash"`
+	LogsBloom        types.Bloom             `json:"logsBloom"`
+	StateRoot        common.Hash             `json:"stateRoot"`
+	Validator        *common.Address         `json:"validator"` //待打包区块为空
+	Coinbase         common.Address          `json:"coinbase"`
+	Difficulty       *hexutil.Big            `json:"difficulty"`
+	TotalDifficulty  *hexutil.Big            `json:"totalDifficulty"`
+	GasLimit         *hexutil.Big            `json:"gasLimit"`
+	GasUsed          *hexutil.Big            `json:"gasUsed"`
+	Timestamp        *hexutil.Big            `json:"timestamp"`
+	TransactionsRoot common.Hash             `json:"transactionsRoot"`
+	ReceiptsRoot     common.Hash             `json:"receiptsRoot"`
+	DposContext      types.DposContextProto  `json:"dposContext"`
+	BokerBackend     types.BokerBackendProto `json:"bokerBackend"`
+	ExtraData        hexutil.Bytes           `json:"extraData"`
+	Size             hexutil.Uint64          `json:"size"`
+	Transactions     []*Transaction          `json:"-"` //请求完整交易时的交易列表
+	TxHashes         []common.Hash           `json:"-"` //只请求交易哈希时的哈希列表
+}
+
+//包含Tina扩展字段的区块头(eth_getHeaderByHash, 节点未开启--ethcompat时)
+type Header struct {
+	Number           *hexutil.Big            `json:"number"`
+	Hash             common.Hash             `json:"hash"`
+	ParentHash       common.Hash             `json:"parentHash"`
+	LogsBloom        types.Bloom             `json:"logsBloom"`
+	StateRoot        common.Hash             `json:"stateRoot"`
+	Validator        common.Address          `json:"validator"`
+	Coinbase         common.Address          `json:"coinbase"`
+	Difficulty       *hexutil.Big            `json:"difficulty"`
+	TotalDifficulty  *hexutil.Big            `json:"totalDifficulty"`
+	GasLimit         *hexutil.Big            `json:"gasLimit"`
+	GasUsed          *hexutil.Big            `json:"gasUsed"`
+	Timestamp        *hexutil.Big            `json:"timestamp"`
+	TransactionsRoot common.Hash             `json:"transactionsRoot"`
+	ReceiptsRoot     common.Hash             `json:"receiptsRoot"`
+	DposContext      types.DposContextProto  `json:"dposContext"`
+	BokerBackend     types.BokerBackendProto `json:"bokerBackend"`
+	ExtraData        hexutil.Bytes           `json:"extraData"`
+}
+
+//精简的区块头(eth_getHeadersByRange以及compactHeads订阅), extraData的最后65字节为出块签名
+type CompactHeader struct {
+	Number           hexutil.Uint64          `json:"number"`
+	Hash             common.Hash             `json:"hash"`
+	ParentHash       common.Hash             `json:"parentHash"`
+	StateRoot        common.Hash             `json:"stateRoot"`
+	TransactionsRoot common.Hash             `json:"transactionsRoot"`
+	ReceiptsRoot     common.Hash             `json:"receiptsRoot"`
+	Timestamp        hexutil.Uint64          `json:"timestamp"`
+	Validator        common.Address          `json:"validator"`
+	Coinbase         common.Address          `json:"coinbase"`
+	DposContext      types.DposContextProto  `json:"dposContext"`
+	BokerBackend     types.BokerBackendProto `json:"bokerBackend"`
+	ExtraData        hexutil.Bytes           `json:"extraData"`
+}
+
+//区块的交易列表根据请求的类型可能是交易或哈希
+func (b *Block) UnmarshalJSON(input []byte) error {
+	type block Block
+	var dec struct {
+		block
+		Transactions []json.RawMessage `json:"transactions"`
+	}
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	*b = Block(dec.block)
+	for _, raw := range dec.Transactions {
+		var hash common.Hash
+		if err := json.Unmarshal(raw, &hash); err == nil {
+			b.TxHashes = append(b.TxHashes, hash)
+			continue
+		}
+		tx := new(Transaction)
+		if err := json.Unmarshal(raw, tx); err != nil {
+			return err
+		}
+		b.Transactions = append(b.Transactions, tx)
+		b.TxHashes = append(b.TxHashes, tx.Hash)
+	}
+	return nil
+}
+
+//股权分红
+type StockPayout struct {
+	Account common.Address `json:"account"`
+	Amount  *hexutil.Big   `json:"amount"`
+}
+
+//区块奖励及Gas分配
+type BlockRewards struct {
+	BlockHash           common.Hash    `json:"blockHash"`
+	BlockNumber         *hexutil.Big   `json:"blockNumber"`
+	Producer            common.Address `json:"producer"`
+	ProducerReward      *hexutil.Big   `json:"producerReward"`
+	GasFees             *hexutil.Big   `json:"gasFees"`
+	GasPoolContribution *hexutil.Big   `json:"gasPoolContribution"`
+	StockPayouts        []StockPayout  `json:"stockPayouts"`
+}
+
+//账号活动的分页参数
+type ActivityPage struct {
+	Offset hexutil.Uint64 `json:"offset"`
+	Limit  hexutil.Uint64 `json:"limit"`
+}
+
+//账号的一条活动记录, Kind为transaction、internal、stock、reward或stockPayout
+type AccountActivity struct {
+	Kind             string           `json:"kind"`
+	BlockNumber      hexutil.Uint64   `json:"blockNumber"`
+	BlockHash        common.Hash      `json:"blockHash"`
+	Timestamp        *hexutil.Big     `json:"timestamp"`
+	TransactionHash  *common.Hash     `json:"transactionHash"`
+	TransactionIndex *hexutil.Uint    `json:"transactionIndex"`
+	Major            protocol.TxMajor `json:"major"`
+	Minor            protocol.TxMinor `json:"minor"`
+	From             common.Address   `json:"from"`
+	To               *common.Address  `json:"to"`
+	Value            *hexutil.Big     `json:"value"`
+	Direction        string           `json:"direction"` //in、out或self
+	Failed           bool             `json:"failed,omitempty"`
+}
+
+//一页账号活动
+type AccountActivityPage struct {
+	Address    common.Address     `json:"address"`
+	FromBlock  hexutil.Uint64     `json:"fromBlock"`
+	ToBlock    hexutil.Uint64     `json:"toBlock"`
+	Total      hexutil.Uint64     `json:"total"`
+	Activities []*AccountActivity `json:"activities"`
+}
+
+//基础合约的变更记录
+type BaseContractRecord struct {
+	Address     common.Address              `json:"address"`
+	Type        protocol.BaseContractType   `json:"type"`   //0:系统基础合约 1:用户基础合约
+	Action      protocol.BaseContractAction `json:"action"` //0:设置 1:取消 2:治理提案升级
+	From        common.Address              `json:"from"`
+	TxHash      *common.Hash                `json:"transactionHash"`
+	BlockNumber hexutil.Uint64              `json:"blockNumber"`
+}
+
+//已注册的基础合约
+type BaseContracts struct {
+	BlockHash      common.Hash          `json:"blockHash"`
+	BlockNumber    *hexutil.Big         `json:"blockNumber"`
+	SystemContract *common.Address      `json:"systemContract"`
+	UserContracts  []common.Address     `json:"userContracts"`
+	History        []BaseContractRecord `json:"history"`
+}
+
+//待生效的系统基础合约变更
+type PendingContract struct {
+	Address        common.Address   `json:"address"`
+	Proposer       common.Address   `json:"proposer"`
+	ProposedBlock  hexutil.Uint64   `json:"proposedBlock"`
+	EffectiveBlock hexutil.Uint64   `json:"effectiveBlock"`
+	Approvals      []common.Address `json:"approvals"`
+	Confirmable    bool             `json:"confirmable"` //是否已到达可以确认生效的区块
+}
+
+//登记合约元数据的参数
+type ContractMetaArgs struct {
+	Address common.Address `json:"address"`
+	Name    string         `json:"name"`
+	Abi     string         `json:"abi"`               //合约Abi（为空时只登记abiHash）
+	AbiHash *common.Hash   `json:"abiHash,omitempty"` //Abi哈希（传入Abi时可以省略）
+	Source  string         `json:"source"`            //源码链接
+}
+
+//合约元数据
+type ContractMeta struct {
+	Address     common.Address `json:"address"`
+	Name        string         `json:"name"`
+	AbiHash     common.Hash    `json:"abiHash"`
+	Abi         string         `json:"abi"`
+	Source      string         `json:"source"`
+	Registrant  common.Address `json:"registrant"`
+	TxHash      common.Hash    `json:"transactionHash"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+}
+
+//调用合约方法的参数
+type ContractCall struct {
+	From     common.Address `json:"from"`
+	To       common.Address `json:"to"`
+	Abi      string         `json:"abi,omitempty"` //合约Abi（为空时使用节点或链上登记的Abi）
+	Method   string         `json:"method"`        //方法名称
+	Args     []interface{}  `json:"args"`          //方法参数（按Abi中的类型解析）
+	Gas      *hexutil.Big   `json:"gas,omitempty"`
+	GasPrice *hexutil.Big   `json:"gasPrice,omitempty"`
+	Value    *hexutil.Big   `json:"value,omitempty"`
+}
+
+//调用合约方法的结果
+type ContractResult struct {
+	Data    hexutil.Bytes          `json:"data"`    //原始返回数据
+	Outputs map[string]interface{} `json:"outputs"` //按Abi解码后的返回值（没有名称的返回值使用序号）
+}
+
+//发起治理提案的参数
+type ProposalArgs struct {
+	Kind           protocol.ProposalKind `json:"kind"`             //0:调整出块奖励 1:升级系统基础合约
+	Weight         protocol.VoteWeight   `json:"weight"`           //0:按股权投票 1:按余额投票
+	Description    string                `json:"description"`      //提案说明
+	Target         *common.Address       `json:"target,omitempty"` //升级后的系统基础合约地址
+	Reward         *hexutil.Big          `json:"reward,omitempty"` //新的区块总奖励（单位为TinaUnit）
+	GasPoolPercent hexutil.Uint64        `json:"gasPoolPercent"`   //新的股权Gas池比例
+}
+
+//治理提案
+type Proposal struct {
+	Id             hexutil.Uint64         `json:"id"`
+	Kind           protocol.ProposalKind  `json:"kind"`
+	Weight         protocol.VoteWeight    `json:"weight"`
+	Proposer       common.Address         `json:"proposer"`
+	Description    string                 `json:"description"`
+	Target         common.Address         `json:"target"`
+	Reward         *hexutil.Big           `json:"reward"`
+	GasPoolPercent hexutil.Uint64         `json:"gasPoolPercent"`
+	CreatedEpoch   hexutil.Uint64         `json:"createdEpoch"`
+	EndEpoch       hexutil.Uint64         `json:"endEpoch"`
+	Yes            *hexutil.Big           `json:"yes"`
+	No             *hexutil.Big           `json:"no"`
+	State          protocol.ProposalState `json:"state"`
+}
+
+//关注账号的事件, Type为transaction、balance或stock
+type AddressEvent struct {
+	Type            string          `json:"type"`
+	Address         common.Address  `json:"address"`
+	BlockNumber     hexutil.Uint64  `json:"blockNumber"`
+	BlockHash       common.Hash     `json:"blockHash"`
+	TransactionHash *common.Hash    `json:"transactionHash"`
+	Direction       string          `json:"direction"`
+	From            *common.Address `json:"from"`
+	To              *common.Address `json:"to"`
+	Value           *hexutil.Big    `json:"value"`
+	Balance         *hexutil.Big    `json:"balance"`
+	PreviousBalance *hexutil.Big    `json:"previousBalance"`
+	Stock           *hexutil.Uint64 `json:"stock"`
+	PreviousStock   *hexutil.Uint64 `json:"previousStock"`
+}
+
+//账号及其存储的Merkle证明
+type AccountProof struct {
+	Address      common.Address  `json:"address"`
+	AccountProof []hexutil.Bytes `json:"accountProof"`
+	Balance      *hexutil.Big    `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	StorageProof []StorageProof  `json:"storageProof"`
+}
+
+//存储位置的Merkle证明
+type StorageProof struct {
+	Key   common.Hash     `json:"key"`
+	Value *hexutil.Big    `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+//外部链上的一次销毁事件
+type BridgeReleaseArgs struct {
+	RemoteTx  common.Hash    `json:"remoteTx"`
+	LogIndex  hexutil.Uint64 `json:"logIndex"`
+	Recipient common.Address `json:"recipient"`
+	Amount    *hexutil.Big   `json:"amount"`
+}
+
+//跨链锁定记录, Slot为承诺哈希的存储位置(用于Proof)
+type BridgeLockRecord struct {
+	Nonce     hexutil.Uint64 `json:"nonce"`
+	Sender    common.Address `json:"sender"`
+	Recipient common.Address `json:"recipient"`
+	Amount    *hexutil.Big   `json:"amount"`
+	Hash      common.Hash    `json:"hash"`
+	Slot      common.Hash    `json:"slot"`
+}
+
+//外部链销毁事件的确认以及释放状态
+type BridgeReleaseStatus struct {
+	Id        common.Hash    `json:"id"`
+	Approvals hexutil.Uint64 `json:"approvals"`
+	Required  hexutil.Uint64 `json:"required"`
+	Released  bool           `json:"released"`
+}
+
+//出块节点的佣金比例, Pending为本周期内留给投票者、尚未分配的奖励
+type Commission struct {
+	Producer   common.Address `json:"producer"`
+	Registered bool           `json:"registered"`
+	Percent    hexutil.Uint64 `json:"percent"`
+	Pending    *hexutil.Big   `json:"pending"`
+}
+
+//账号累计注入股权Gas池的数量
+type GasPoolContribution struct {
+	Account common.Address `json:"account"`
+	Amount  *hexutil.Big   `json:"amount"`
+}
+
+//股权Gas池中等待分配的数量以及每个账号累计注入的数量
+type GasPoolFunding struct {
+	GasPool      *hexutil.Big           `json:"gasPool"`
+	Total        *hexutil.Big           `json:"total"`
+	Contributors []*GasPoolContribution `json:"contributors"`
+}
+
+//账号已分配(尚未领取)以及累计已领取的委托奖励
+type DelegationRewards struct {
+	Account common.Address `json:"account"`
+	Accrued *hexutil.Big   `json:"accrued"`
+	Claimed *hexutil.Big   `json:"claimed"`
+}
+
+//周期验证者的得票数以及本周期内已经产生的区块数量
+type ValidatorDetail struct {
+	Address         common.Address `json:"address"`
+	Votes           *hexutil.Big   `json:"votes"`
+	ProducedInEpoch hexutil.Uint64 `json:"producedInEpoch"`
+}
+
+//验证者登记的运营信息(名称、网站以及联系方式)
+type ValidatorInfo struct {
+	Validator common.Address `json:"validator"`
+	Name      string         `json:"name"`
+	Website   string         `json:"website"`
+	Contact   string         `json:"contact"`
+}
+
+//由扩展公钥派生的充值地址集合
+type DepositSet struct {
+	Name      string            `json:"name"`
+	Xpub      string            `json:"xpub"`
+	Start     hexutil.Uint64    `json:"start"`
+	Count     hexutil.Uint64    `json:"count"`
+	Addresses []*DepositAddress `json:"addresses"`
+}
+
+//充值地址, 查询余额时Balance不为空
+type DepositAddress struct {
+	Index   hexutil.Uint64 `json:"index"`
+	Address common.Address `json:"address"`
+	Balance *hexutil.Big   `json:"balance"`
+}
+
+//充值地址集合的余额
+type DepositBalances struct {
+	Name        string            `json:"name"`
+	BlockNumber hexutil.Uint64    `json:"blockNumber"`
+	BlockHash   common.Hash       `json:"blockHash"`
+	Total       *hexutil.Big      `json:"total"`
+	Addresses   []*DepositAddress `json:"addresses"`
+}
+
+//充值地址收到的一笔转账
+type DepositTransfer struct {
+	Kind            string         `json:"kind"`
+	BlockNumber     hexutil.Uint64 `json:"blockNumber"`
+	BlockHash       common.Hash    `json:"blockHash"`
+	TransactionHash common.Hash    `json:"transactionHash"`
+	Index           hexutil.Uint64 `json:"index"`
+	From            common.Address `json:"from"`
+	To              common.Address `json:"to"`
+	Value           *hexutil.Big   `json:"value"`
+}
+
+//充值地址集合在区块范围内的入账
+type DepositIncoming struct {
+	Name      string             `json:"name"`
+	FromBlock hexutil.Uint64     `json:"fromBlock"`
+	ToBlock   hexutil.Uint64     `json:"toBlock"`
+	Total     *hexutil.Big       `json:"total"`
+	Transfers []*DepositTransfer `json:"transfers"`
+}
+
+//代币转账(token_getTransfers), ERC-20使用Value, ERC-721使用TokenId
+type TokenTransfer struct {
+	Standard        string         `json:"standard"`
+	Token           common.Address `json:"token"`
+	From            common.Address `json:"from"`
+	To              common.Address `json:"to"`
+	Value           *hexutil.Big   `json:"value,omitempty"`
+	TokenId         *hexutil.Big   `json:"tokenId,omitempty"`
+	TransactionHash common.Hash    `json:"transactionHash"`
+	BlockHash       common.Hash    `json:"blockHash"`
+	BlockNumber     hexutil.Uint64 `json:"blockNumber"`
+	LogIndex        hexutil.Uint64 `json:"logIndex"`
+}
+
+//由索引的转账累计的代币余额(token_getBalance)
+type TokenBalance struct {
+	Token       common.Address `json:"token"`
+	Address     common.Address `json:"address"`
+	Standard    string         `json:"standard,omitempty"`
+	Balance     *hexutil.Big   `json:"balance"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+}
+
+//下载器对单个节点的吞吐量估计(downloader_stats), 吞吐量为每秒的数量, 时间单位为毫秒
+type DownloaderPeerStats struct {
+	ID                string  `json:"id"`
+	Version           int     `json:"version"`
+	HeaderThroughput  float64 `json:"headerThroughput"`
+	BlockThroughput   float64 `json:"blockThroughput"`
+	ReceiptThroughput float64 `json:"receiptThroughput"`
+	StateThroughput   float64 `json:"stateThroughput"`
+	RTT               int64   `json:"rtt"`
+	HeadersDelivered  uint64  `json:"headersDelivered"`
+	BodiesDelivered   uint64  `json:"bodiesDelivered"`
+	ReceiptsDelivered uint64  `json:"receiptsDelivered"`
+	StatesDelivered   uint64  `json:"statesDelivered"`
+	BlockCapacity     int     `json:"blockCapacity"`
+	ReceiptCapacity   int     `json:"receiptCapacity"`
+}
+
+//下载器的请求往返时间目标以及各节点的吞吐量估计(downloader_stats)
+type DownloaderStats struct {
+	TargetRTT  int64                  `json:"targetRTT"`
+	RequestTTL int64                  `json:"requestTTL"`
+	Confidence float64                `json:"confidence"`
+	Peers      []*DownloaderPeerStats `json:"peers"`
+}
+
+//同步进度的详细信息, ETA为预计剩余的秒数(无法估计时为nil)
+type SyncProgress struct {
+	Syncing         bool           `json:"syncing"`
+	Phase           string         `json:"phase"`
+	Mode            string         `json:"mode"`
+	StartingBlock   hexutil.Uint64 `json:"startingBlock"`
+	CurrentBlock    hexutil.Uint64 `json:"currentBlock"`
+	HighestBlock    hexutil.Uint64 `json:"highestBlock"`
+	PulledStates    hexutil.Uint64 `json:"pulledStates"`
+	KnownStates     hexutil.Uint64 `json:"knownStates"`
+	BlocksPerSecond float64        `json:"blocksPerSecond"`
+	StatesPerSecond float64        `json:"statesPerSecond"`
+	ETA             *uint64        `json:"eta"`
+}
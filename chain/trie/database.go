@@ -0,0 +1,489 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/ethdb"
+	"github.com/Tinachain/Tina/chain/log"
+	"github.com/rcrowley/go-metrics"
+)
+
+var (
+	memcacheFlushCounter = metrics.NewRegisteredCounter("trie/memcache/flush/nodes", nil)
+	memcacheGCCounter    = metrics.NewRegisteredCounter("trie/memcache/gc/nodes", nil)
+)
+
+// LeafResolver is called for every leaf value of a node inserted into a
+// NodeDatabase and returns the hashes of other tries the leaf points to. Those
+// are reference counted as children of the node containing the leaf, which is
+// how account leaves keep their storage tries alive.
+type LeafResolver func(leaf []byte) []common.Hash
+
+// NodeDatabase is an intermediate write layer between the trie data structures
+// and the disk database. Committed nodes are kept in memory together with the
+// number of live parents referencing them, so the nodes of state roots that are
+// dereferenced before reaching the disk are dropped without ever being written.
+// Nodes are flushed to disk in batches, children before their parents, so the
+// disk never holds a node whose subtrie is incomplete.
+//
+// A NodeDatabase implements Database and may be used as the backing store of
+// tries: reads are served from memory first and fall through to disk.
+type NodeDatabase struct {
+	diskdb ethdb.Database // Persistent storage for flushed nodes
+	leaves LeafResolver   // Resolver for references hidden in leaf values
+
+	nodes  map[common.Hash]*cachedNode // Dirty nodes, plus the meta-root at the zero hash
+	oldest common.Hash                 // Oldest tracked node, flush-list head
+	newest common.Hash                 // Newest tracked node, flush-list tail
+	blobs  map[string][]byte           // Unreferenced entries (preimages, contract code)
+
+	nodesSize common.StorageSize // Storage size of the dirty nodes
+	blobsSize common.StorageSize // Storage size of the unreferenced entries
+
+	gcnodes    uint64             // Nodes garbage collected since the last flush log
+	gcsize     common.StorageSize // Data storage garbage collected since the last flush log
+	flushnodes uint64             // Nodes flushed since startup
+	flushsize  common.StorageSize // Data storage flushed since startup
+
+	flushLock sync.Mutex     // Serializes flushes, held for the duration of a write
+	flushing  int32          // Set while a background flush is in flight (atomic)
+	flushWg   sync.WaitGroup // Tracks the background flush for Wait
+
+	lock sync.RWMutex
+}
+
+// cachedNode is a dirty trie node with its reference counts.
+type cachedNode struct {
+	blob     []byte              // Encoded node, as written to disk
+	parents  int                 // Number of live nodes (or roots) referencing this one
+	children map[common.Hash]int // Dirty or flushed nodes referenced by this one
+
+	flushPrev common.Hash // Previous node in the flush-list
+	flushNext common.Hash // Next node in the flush-list
+}
+
+// flushEntry is a node scheduled for writing, tagged with the cache entry it
+// was taken from so only that exact entry is evicted once it reached disk.
+type flushEntry struct {
+	hash common.Hash
+	node *cachedNode
+}
+
+// NewNodeDatabase creates a node database writing through to diskdb. The leaf
+// resolver may be nil if the stored tries don't link to each other.
+func NewNodeDatabase(diskdb ethdb.Database, leaves LeafResolver) *NodeDatabase {
+	return &NodeDatabase{
+		diskdb: diskdb,
+		leaves: leaves,
+		nodes: map[common.Hash]*cachedNode{
+			{}: {children: make(map[common.Hash]int)},
+		},
+		blobs: make(map[string][]byte),
+	}
+}
+
+// DiskDB returns the persistent storage backing the node database.
+func (db *NodeDatabase) DiskDB() ethdb.Database {
+	return db.diskdb
+}
+
+// insert tracks a freshly committed node. The node is the collapsed form whose
+// children are hash nodes, embedded nodes or values. Dirty children gain a
+// parent; children already on disk are recorded so dereferencing stays cheap.
+func (db *NodeDatabase) insert(hash common.Hash, blob []byte, n node) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if _, ok := db.nodes[hash]; ok {
+		return
+	}
+	entry := &cachedNode{
+		blob:      common.CopyBytes(blob),
+		children:  make(map[common.Hash]int),
+		flushPrev: db.newest,
+	}
+	db.gatherChildren(n, entry.children)
+	for child, count := range entry.children {
+		if c := db.nodes[child]; c != nil {
+			c.parents += count
+		}
+	}
+	// Append the node to the end of the flush-list
+	if db.oldest == (common.Hash{}) {
+		db.oldest, db.newest = hash, hash
+	} else {
+		db.nodes[db.newest].flushNext, db.newest = hash, hash
+	}
+	db.nodes[hash] = entry
+	db.nodesSize += common.StorageSize(common.HashLength + len(entry.blob))
+}
+
+// gatherChildren collects the hashes a collapsed node references, descending
+// into embedded nodes and resolving leaf values into external references.
+func (db *NodeDatabase) gatherChildren(n node, children map[common.Hash]int) {
+	switch n := n.(type) {
+	case *shortNode:
+		db.gatherChildren(n.Val, children)
+	case *fullNode:
+		for i := 0; i < 16; i++ {
+			db.gatherChildren(n.Children[i], children)
+		}
+	case hashNode:
+		children[common.BytesToHash(n)]++
+	case valueNode:
+		if db.leaves != nil && len(n) > 0 {
+			for _, child := range db.leaves(n) {
+				children[child]++
+			}
+		}
+	}
+}
+
+// Reference adds a new reference from a parent node to a child node. The zero
+// hash as parent pins child as a root until it is dereferenced again.
+func (db *NodeDatabase) Reference(child common.Hash, parent common.Hash) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if child == (common.Hash{}) {
+		return
+	}
+	node, ok := db.nodes[child]
+	if !ok {
+		return
+	}
+	owner, ok := db.nodes[parent]
+	if !ok {
+		return
+	}
+	// Regular nodes reference a child only once, roots may be pinned repeatedly
+	if _, ok := owner.children[child]; ok && parent != (common.Hash{}) {
+		return
+	}
+	node.parents++
+	owner.children[child]++
+}
+
+// Dereference removes a root pin, dropping every dirty node that is no longer
+// referenced by any other live node. Dropped nodes never reach the disk.
+func (db *NodeDatabase) Dereference(root common.Hash) {
+	if root == (common.Hash{}) {
+		return
+	}
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	nodes, size := len(db.nodes), db.nodesSize
+	db.dereference(root, common.Hash{})
+
+	db.gcnodes += uint64(nodes - len(db.nodes))
+	db.gcsize += size - db.nodesSize
+	memcacheGCCounter.Inc(int64(nodes - len(db.nodes)))
+}
+
+func (db *NodeDatabase) dereference(child common.Hash, parent common.Hash) {
+	if owner := db.nodes[parent]; owner != nil && owner.children[child] > 0 {
+		owner.children[child]--
+		if owner.children[child] == 0 {
+			delete(owner.children, child)
+		}
+	}
+	node, ok := db.nodes[child]
+	if !ok {
+		return
+	}
+	if node.parents > 0 {
+		node.parents--
+	}
+	if node.parents > 0 {
+		return
+	}
+	db.unlink(child, node)
+	for grandchild, count := range node.children {
+		for i := 0; i < count; i++ {
+			db.dereference(grandchild, child)
+		}
+	}
+}
+
+// unlink removes a node from the flush-list and the dirty set.
+func (db *NodeDatabase) unlink(hash common.Hash, node *cachedNode) {
+	switch hash {
+	case db.oldest:
+		db.oldest = node.flushNext
+		if db.oldest != (common.Hash{}) {
+			db.nodes[db.oldest].flushPrev = common.Hash{}
+		}
+	default:
+		db.nodes[node.flushPrev].flushNext = node.flushNext
+	}
+	switch hash {
+	case db.newest:
+		db.newest = node.flushPrev
+		if db.newest != (common.Hash{}) {
+			db.nodes[db.newest].flushNext = common.Hash{}
+		}
+	default:
+		db.nodes[node.flushNext].flushPrev = node.flushPrev
+	}
+	delete(db.nodes, hash)
+	db.nodesSize -= common.StorageSize(common.HashLength + len(node.blob))
+}
+
+// Node retrieves an encoded trie node or contract code by hash, from memory if
+// still dirty or from disk otherwise.
+func (db *NodeDatabase) Node(hash common.Hash) ([]byte, error) {
+	return db.Get(hash[:])
+}
+
+// Get implements DatabaseReader, serving dirty entries before the disk.
+func (db *NodeDatabase) Get(key []byte) ([]byte, error) {
+	if blob := db.dirty(key); blob != nil {
+		return blob, nil
+	}
+	return db.diskdb.Get(key)
+}
+
+// Has implements DatabaseReader.
+func (db *NodeDatabase) Has(key []byte) (bool, error) {
+	if blob := db.dirty(key); blob != nil {
+		return true, nil
+	}
+	return db.diskdb.Has(key)
+}
+
+// dirty returns the in-memory value of key, or nil if it is only on disk.
+func (db *NodeDatabase) dirty(key []byte) []byte {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if hash := common.BytesToHash(key); len(key) == common.HashLength && hash != (common.Hash{}) {
+		if node := db.nodes[hash]; node != nil {
+			return node.blob
+		}
+	}
+	return db.blobs[string(key)]
+}
+
+// Put implements DatabaseWriter. Entries written this way (preimages and
+// contract code) aren't reference counted and go to disk with the next flush.
+func (db *NodeDatabase) Put(key []byte, value []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if _, ok := db.blobs[string(key)]; ok {
+		return nil
+	}
+	db.blobs[string(key)] = common.CopyBytes(value)
+	db.blobsSize += common.StorageSize(len(key) + len(value))
+	return nil
+}
+
+// Size returns the memory held by dirty nodes and unreferenced entries.
+func (db *NodeDatabase) Size() common.StorageSize {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	return db.nodesSize + db.blobsSize
+}
+
+// Cap flushes the oldest dirty nodes to disk until the memory held drops below
+// limit. The flush-list order guarantees children are written before parents.
+func (db *NodeDatabase) Cap(limit common.StorageSize) error {
+	db.flushLock.Lock()
+	defer db.flushLock.Unlock()
+
+	return db.write(db.collectOldest(limit))
+}
+
+// CapAsync is like Cap, but writes in the background. The nodes to flush are
+// chosen before returning; they stay readable from memory until they are on
+// disk. It returns false without doing anything if a flush is already running.
+func (db *NodeDatabase) CapAsync(limit common.StorageSize) bool {
+	if !atomic.CompareAndSwapInt32(&db.flushing, 0, 1) {
+		return false
+	}
+	db.flushLock.Lock()
+	entries := db.collectOldest(limit)
+
+	db.flushWg.Add(1)
+	go func() {
+		defer db.flushWg.Done()
+		defer atomic.StoreInt32(&db.flushing, 0)
+		defer db.flushLock.Unlock()
+
+		if err := db.write(entries); err != nil {
+			log.Error("Failed to flush trie nodes", "err", err)
+		}
+	}()
+	return true
+}
+
+// Commit synchronously flushes root and all its dirty descendants to disk.
+func (db *NodeDatabase) Commit(root common.Hash) error {
+	db.flushLock.Lock()
+	defer db.flushLock.Unlock()
+
+	return db.write(db.collectRoot(root))
+}
+
+// CommitAsync is like Commit, but writes in the background. It returns false
+// without doing anything if a flush is already running.
+func (db *NodeDatabase) CommitAsync(root common.Hash) bool {
+	if !atomic.CompareAndSwapInt32(&db.flushing, 0, 1) {
+		return false
+	}
+	db.flushLock.Lock()
+	entries := db.collectRoot(root)
+
+	db.flushWg.Add(1)
+	go func() {
+		defer db.flushWg.Done()
+		defer atomic.StoreInt32(&db.flushing, 0)
+		defer db.flushLock.Unlock()
+
+		if err := db.write(entries); err != nil {
+			log.Error("Failed to flush trie nodes", "err", err)
+		}
+	}()
+	return true
+}
+
+// Wait blocks until the background flush, if any, finished.
+func (db *NodeDatabase) Wait() {
+	db.flushWg.Wait()
+}
+
+// collectOldest picks nodes from the head of the flush-list until the memory
+// left behind is below limit.
+func (db *NodeDatabase) collectOldest(limit common.StorageSize) []flushEntry {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	var (
+		entries []flushEntry
+		size    = db.nodesSize + db.blobsSize
+	)
+	for hash := db.oldest; hash != (common.Hash{}) && size > limit; {
+		node := db.nodes[hash]
+		entries = append(entries, flushEntry{hash, node})
+		size -= common.StorageSize(common.HashLength + len(node.blob))
+		hash = node.flushNext
+	}
+	return entries
+}
+
+// collectRoot gathers the dirty nodes of the trie rooted at root, children
+// before their parents.
+func (db *NodeDatabase) collectRoot(root common.Hash) []flushEntry {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	var (
+		entries []flushEntry
+		seen    = make(map[common.Hash]struct{})
+	)
+	var collect func(hash common.Hash)
+	collect = func(hash common.Hash) {
+		node, ok := db.nodes[hash]
+		if !ok {
+			return
+		}
+		if _, ok := seen[hash]; ok {
+			return
+		}
+		seen[hash] = struct{}{}
+		for child := range node.children {
+			collect(child)
+		}
+		entries = append(entries, flushEntry{hash, node})
+	}
+	if root != (common.Hash{}) {
+		collect(root)
+	}
+	return entries
+}
+
+// write flushes the unreferenced entries and the given nodes in batches, then
+// evicts them from memory. Entries are evicted only if the cache still holds
+// the exact instance that was written.
+func (db *NodeDatabase) write(entries []flushEntry) error {
+	start := time.Now()
+
+	db.lock.RLock()
+	blobs := make(map[string][]byte, len(db.blobs))
+	for key, blob := range db.blobs {
+		blobs[key] = blob
+	}
+	db.lock.RUnlock()
+
+	batch := db.diskdb.NewBatch()
+	for key, blob := range blobs {
+		if err := batch.Put([]byte(key), blob); err != nil {
+			return err
+		}
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch = db.diskdb.NewBatch()
+		}
+	}
+	for _, entry := range entries {
+		if err := batch.Put(entry.hash[:], entry.node.blob); err != nil {
+			return err
+		}
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch = db.diskdb.NewBatch()
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	// Everything is on disk, drop the written instances from memory
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	for key, blob := range blobs {
+		delete(db.blobs, key)
+		db.blobsSize -= common.StorageSize(len(key) + len(blob))
+	}
+	var size common.StorageSize
+	for _, entry := range entries {
+		if node := db.nodes[entry.hash]; node == entry.node {
+			db.unlink(entry.hash, node)
+			size += common.StorageSize(common.HashLength + len(node.blob))
+		}
+	}
+	db.flushnodes += uint64(len(entries))
+	db.flushsize += size
+	memcacheFlushCounter.Inc(int64(len(entries)))
+
+	log.Debug("Flushed trie nodes to disk", "nodes", len(entries), "size", size, "blobs", len(blobs), "elapsed", common.PrettyDuration(time.Since(start)),
+		"gcnodes", db.gcnodes, "gcsize", db.gcsize, "livenodes", len(db.nodes)-1, "livesize", db.nodesSize)
+	db.gcnodes, db.gcsize = 0, 0
+
+	return nil
+}
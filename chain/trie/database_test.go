@@ -0,0 +1,187 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/Tinachain/Tina/chain/common"
+	"github.com/Tinachain/Tina/chain/ethdb"
+)
+
+// commitNodeTrie fills a trie backed by the node database and commits it.
+func commitNodeTrie(t *testing.T, triedb *NodeDatabase, root common.Hash, vals map[string]string) common.Hash {
+	tr, err := New(root, triedb)
+	if err != nil {
+		t.Fatalf("failed to open trie %x: %v", root, err)
+	}
+	for key, val := range vals {
+		tr.Update([]byte(key), []byte(val))
+	}
+	hash, err := tr.CommitTo(triedb)
+	if err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+	return hash
+}
+
+// checkNodeTrie iterates the trie at root and ensures it holds exactly vals.
+func checkNodeTrie(root common.Hash, db Database, vals map[string]string) error {
+	tr, err := New(root, db)
+	if err != nil {
+		return err
+	}
+	found := 0
+	it := NewIterator(tr.NodeIterator(nil))
+	for it.Next() {
+		if want, ok := vals[string(it.Key)]; !ok || !bytes.Equal(it.Value, []byte(want)) {
+			return fmt.Errorf("unexpected entry %q: %q", it.Key, it.Value)
+		}
+		found++
+	}
+	if it.Err != nil {
+		return it.Err
+	}
+	if found != len(vals) {
+		return fmt.Errorf("entry count mismatch: have %d, want %d", found, len(vals))
+	}
+	return nil
+}
+
+func makeNodeTrieValues(prefix string, n int) map[string]string {
+	vals := make(map[string]string)
+	for i := 0; i < n; i++ {
+		vals[fmt.Sprintf("%s-key-%04d", prefix, i)] = fmt.Sprintf("%s-value-%04d-%s", prefix, i, bytes.Repeat([]byte{'x'}, 32))
+	}
+	return vals
+}
+
+// Tests that committed nodes are served from memory before they are flushed and
+// that capping the cache writes complete tries to disk.
+func TestNodeDatabaseCap(t *testing.T) {
+	diskdb, _ := ethdb.NewMemDatabase()
+	triedb := NewNodeDatabase(diskdb, nil)
+
+	vals := makeNodeTrieValues("a", 500)
+	root := commitNodeTrie(t, triedb, common.Hash{}, vals)
+
+	if len(diskdb.Keys()) != 0 {
+		t.Fatalf("nodes written on commit: %d", len(diskdb.Keys()))
+	}
+	if err := checkNodeTrie(root, triedb, vals); err != nil {
+		t.Fatalf("dirty trie unreadable: %v", err)
+	}
+	// Flush half of the nodes, the disk must only hold complete subtries
+	if err := triedb.Cap(triedb.Size() / 2); err != nil {
+		t.Fatalf("failed to cap cache: %v", err)
+	}
+	if size := triedb.Size(); size == 0 || len(diskdb.Keys()) == 0 {
+		t.Fatalf("partial flush mismatch: cache %v, disk %d entries", size, len(diskdb.Keys()))
+	}
+	for _, key := range diskdb.Keys() {
+		blob, _ := diskdb.Get(key)
+		n := mustDecodeNode(key, blob, 0)
+		children := make(map[common.Hash]int)
+		triedb.gatherChildren(n, children)
+		for child := range children {
+			if ok, _ := diskdb.Has(child[:]); !ok {
+				t.Fatalf("flushed node %x references unflushed child %x", key, child)
+			}
+		}
+	}
+	if err := checkNodeTrie(root, triedb, vals); err != nil {
+		t.Fatalf("partially flushed trie unreadable: %v", err)
+	}
+	// Flush in the background and wait for completion
+	if !triedb.CapAsync(0) {
+		t.Fatalf("background flush refused")
+	}
+	triedb.Wait()
+	if size := triedb.Size(); size != 0 {
+		t.Fatalf("cache not empty after full flush: %v", size)
+	}
+	if err := checkNodeTrie(root, diskdb, vals); err != nil {
+		t.Fatalf("flushed trie incomplete on disk: %v", err)
+	}
+}
+
+// Tests that dereferencing a root drops the nodes only it referenced, while the
+// nodes shared with a live root survive and can be committed.
+func TestNodeDatabaseDereference(t *testing.T) {
+	diskdb, _ := ethdb.NewMemDatabase()
+	triedb := NewNodeDatabase(diskdb, nil)
+
+	vals := makeNodeTrieValues("a", 200)
+	root1 := commitNodeTrie(t, triedb, common.Hash{}, vals)
+	triedb.Reference(root1, common.Hash{})
+
+	update := makeNodeTrieValues("b", 10)
+	root2 := commitNodeTrie(t, triedb, root1, update)
+	triedb.Reference(root2, common.Hash{})
+	for key, val := range update {
+		vals[key] = val
+	}
+	size := triedb.Size()
+	triedb.Dereference(root1)
+
+	if triedb.Size() >= size {
+		t.Fatalf("nothing collected: have %v, had %v", triedb.Size(), size)
+	}
+	if _, err := New(root1, triedb); err == nil {
+		t.Fatalf("dereferenced root still available")
+	}
+	if err := checkNodeTrie(root2, triedb, vals); err != nil {
+		t.Fatalf("live trie damaged by collection: %v", err)
+	}
+	if err := triedb.Commit(root2); err != nil {
+		t.Fatalf("failed to commit root: %v", err)
+	}
+	if size := triedb.Size(); size != 0 {
+		t.Fatalf("cache not empty after commit: %v", size)
+	}
+	if err := checkNodeTrie(root2, diskdb, vals); err != nil {
+		t.Fatalf("committed trie incomplete on disk: %v", err)
+	}
+}
+
+// Tests that tries referenced from leaf values stay alive as long as the node
+// holding the leaf does.
+func TestNodeDatabaseLeafReferences(t *testing.T) {
+	diskdb, _ := ethdb.NewMemDatabase()
+	triedb := NewNodeDatabase(diskdb, func(leaf []byte) []common.Hash {
+		if len(leaf) != common.HashLength {
+			return nil
+		}
+		return []common.Hash{common.BytesToHash(leaf)}
+	})
+	inner := makeNodeTrieValues("inner", 100)
+	innerRoot := commitNodeTrie(t, triedb, common.Hash{}, inner)
+
+	outer := map[string]string{"link": string(innerRoot[:])}
+	outerRoot := commitNodeTrie(t, triedb, common.Hash{}, outer)
+	triedb.Reference(outerRoot, common.Hash{})
+
+	if err := checkNodeTrie(innerRoot, triedb, inner); err != nil {
+		t.Fatalf("linked trie unreadable: %v", err)
+	}
+	triedb.Dereference(outerRoot)
+	if size := triedb.Size(); size != 0 {
+		t.Fatalf("linked trie not collected: %v left", size)
+	}
+}
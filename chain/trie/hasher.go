@@ -167,6 +167,11 @@ func (h *hasher) store(n node, db DatabaseWriter, force bool) (node, error) {
 		hash = hashNode(h.sha.Sum(nil))
 	}
 	if db != nil {
+		// Node databases track the node's children to reference count them
+		if ndb, ok := db.(*NodeDatabase); ok {
+			ndb.insert(common.BytesToHash(hash), h.tmp.Bytes(), n)
+			return hash, nil
+		}
 		return hash, db.Put(hash, h.tmp.Bytes())
 	}
 	return hash, nil
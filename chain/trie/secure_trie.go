@@ -167,6 +167,12 @@ func (t *SecureTrie) NodeIterator(start []byte) NodeIterator {
 	return t.trie.NodeIterator(start)
 }
 
+// Prove constructs a merkle proof for a hashed key, i.e. a key as returned by
+// the node iterator of the secure trie. See Trie.Prove for the proof format.
+func (t *SecureTrie) Prove(hashedKey []byte, fromLevel uint, proofDb DatabaseWriter) error {
+	return t.trie.Prove(hashedKey, fromLevel, proofDb)
+}
+
 // CommitTo writes all nodes and the secure hash pre-images to the given database.
 // Nodes are stored with their sha3 hash as the key.
 //